@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+)
+
+// printUsage describes the subcommands
+func printUsage(out io.Writer) {
+	fmt.Fprintf(out, `Usage: zerops-mcp <command> [flags]
+
+Commands:
+  serve                       Run the MCP server (default; flags without a command are serve flags)
+  tools list [--json]         Print the registered tools and their input schemas
+  call <tool> --args '{...}'  Call one tool with ZEROPS_API_KEY and print its result as JSON
+  doctor                      Check the API key, connectivity and local setup
+  docs [--out dir] [--check]  Write the tool reference (tools.json, TOOLS.md) generated from the registry
+  help                        Show this help
+
+Run "zerops-mcp <command> -h" for the flags of a command.
+`)
+}
+
+// runTools prints the registered tools; only the list subcommand exists
+func runTools(args []string) int {
+	if len(args) == 0 || args[0] != "list" {
+		fmt.Fprintf(os.Stderr, "Usage: zerops-mcp tools list [--json]\n")
+		return 2
+	}
+	flags := flag.NewFlagSet("tools list", flag.ExitOnError)
+	asJSON := flags.Bool("json", false, "Print the full tool definitions (description, input schema, annotations) as JSON")
+	flags.Parse(args[1:])
+
+	initialize()
+	tools := shared.GlobalRegistry.List()
+
+	if *asJSON {
+		definitions := make([]map[string]interface{}, 0, len(tools))
+		for _, tool := range tools {
+			definition := map[string]interface{}{
+				"name":        tool.Name,
+				"description": tool.Description,
+				"inputSchema": tool.InputSchema,
+			}
+			if tool.Annotations != nil {
+				definition["annotations"] = tool.Annotations.Map()
+			}
+			definitions = append(definitions, definition)
+		}
+		return printJSON(map[string]interface{}{"tools": definitions})
+	}
+
+	for _, tool := range tools {
+		fmt.Printf("%s\n  %s\n", tool.Name, strings.ReplaceAll(tool.DescriptionFor(true), "\n", "\n  "))
+		if params := toolParameters(tool.InputSchema); params != "" {
+			fmt.Printf("  Arguments: %s\n", params)
+		}
+		fmt.Println()
+	}
+	return 0
+}
+
+// toolParameters summarizes the properties of an input schema as "name (type, required), ..."
+func toolParameters(schema map[string]interface{}) string {
+	properties, _ := schema["properties"].(map[string]interface{})
+	required := map[string]bool{}
+	if list, ok := schema["required"].([]string); ok {
+		for _, name := range list {
+			required[name] = true
+		}
+	}
+
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if required[names[i]] != required[names[j]] {
+			return required[names[i]]
+		}
+		return names[i] < names[j]
+	})
+
+	params := make([]string, 0, len(names))
+	for _, name := range names {
+		kind := "any"
+		if property, ok := properties[name].(map[string]interface{}); ok {
+			if value, ok := property["type"].(string); ok {
+				kind = value
+			}
+		}
+		if required[name] {
+			kind += ", required"
+		}
+		params = append(params, fmt.Sprintf("%s (%s)", name, kind))
+	}
+	return strings.Join(params, ", ")
+}
+
+// runCall calls one tool through the registry, as an MCP client would, and prints the result
+// as JSON. It returns 1 when the tool fails, with the error code and message on stderr.
+func runCall(args []string) int {
+	flags := flag.NewFlagSet("call", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: zerops-mcp call <tool> [--args '{...}'] [--timeout 10m]\n")
+		flags.PrintDefaults()
+	}
+	argsJSON := flags.String("args", "{}", "Tool arguments as a JSON object; - reads them from stdin")
+	timeout := flags.Duration("timeout", 10*time.Minute, "Cancel the call after this long")
+
+	// The tool name may come before or after the flags
+	flags.Parse(args)
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+	name := flags.Arg(0)
+	flags.Parse(flags.Args()[1:])
+	if flags.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "Unexpected arguments %v; pass tool arguments with --args\n", flags.Args())
+		return 2
+	}
+
+	raw := []byte(*argsJSON)
+	if *argsJSON == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to read arguments from stdin: %v\n", err)
+			return 2
+		}
+		raw = data
+	}
+	var toolArgs map[string]interface{}
+	if err := json.Unmarshal(raw, &toolArgs); err != nil {
+		fmt.Fprintf(os.Stderr, "--args must be a JSON object: %v\n", err)
+		return 2
+	}
+	if toolArgs == nil {
+		toolArgs = map[string]interface{}{}
+	}
+
+	initialize()
+	if _, ok := shared.GlobalRegistry.Get(name); !ok {
+		fmt.Fprintf(os.Stderr, "Unknown tool %q; list them with: zerops-mcp tools list\n", name)
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, *timeout)
+	defer cancel()
+
+	apiKey := os.Getenv("ZEROPS_API_KEY")
+	if apiKey == "" {
+		fmt.Fprintf(os.Stderr, "ZEROPS_API_KEY environment variable is required for call\n")
+		return 2
+	}
+	ctx = context.WithValue(ctx, "zeropsClient", createZeropsClient(apiKey))
+	ctx = shared.WithClientFactory(ctx, createZeropsClient)
+	ctx = shared.WithProgress(ctx, func(ctx context.Context, progress, total float64, message string) error {
+		fmt.Fprintf(os.Stderr, "[progress] %.0f/%.0f %s\n", progress, total, message)
+		return nil
+	})
+	ctx = context.WithValue(ctx, "clientName", "cli")
+
+	result, err := shared.GlobalRegistry.CallTool(ctx, name, toolArgs)
+	if shared.GlobalExporter != nil {
+		shared.GlobalExporter.Close(5 * time.Second)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error [%s]: %v\n", shared.ErrorCode(err), err)
+		return 1
+	}
+	return printJSON(result)
+}
+
+// printJSON writes value to stdout as indented JSON
+func printJSON(value interface{}) int {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to encode result: %v\n", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+)
+
+// The tool reference in docs/tools is generated from the registry; regenerate it after
+// changing a tool with "go generate ./cmd/mcp-server".
+//
+//go:generate go run . docs --out ../../docs/tools
+
+const (
+	docsJSONFile     = "tools.json"
+	docsMarkdownFile = "TOOLS.md"
+)
+
+// runDocs writes the tool reference as JSON and markdown. With --check it writes nothing and
+// returns 1 when the files on disk differ from the registry, so CI can catch stale docs.
+func runDocs(args []string) int {
+	flags := flag.NewFlagSet("docs", flag.ExitOnError)
+	outDir := flags.String("out", filepath.Join("docs", "tools"), "Directory to write "+docsJSONFile+" and "+docsMarkdownFile+" to")
+	check := flags.Bool("check", false, "Only report whether the files in --out are up to date")
+	flags.Parse(args)
+
+	initialize()
+	tools := shared.GlobalRegistry.List()
+
+	jsonDoc, err := toolsJSONDoc(tools)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to render %s: %v\n", docsJSONFile, err)
+		return 1
+	}
+	files := map[string][]byte{
+		docsJSONFile:     jsonDoc,
+		docsMarkdownFile: toolsMarkdownDoc(tools),
+	}
+	names := []string{docsJSONFile, docsMarkdownFile}
+
+	if *check {
+		stale := 0
+		for _, name := range names {
+			current, err := os.ReadFile(filepath.Join(*outDir, name))
+			if err != nil || !bytes.Equal(current, files[name]) {
+				fmt.Fprintf(os.Stderr, "%s is out of date; run go generate ./cmd/mcp-server\n", filepath.Join(*outDir, name))
+				stale++
+			}
+		}
+		if stale > 0 {
+			return 1
+		}
+		return 0
+	}
+
+	if err := os.MkdirAll(*outDir, 0o755); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create %s: %v\n", *outDir, err)
+		return 1
+	}
+	for _, name := range names {
+		path := filepath.Join(*outDir, name)
+		if err := os.WriteFile(path, files[name], 0o644); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to write %s: %v\n", path, err)
+			return 1
+		}
+		fmt.Fprintf(os.Stderr, "Wrote %s\n", path)
+	}
+	return 0
+}
+
+// toolsJSONDoc renders the machine-readable reference: the tools/list entries plus the
+// output schema and an example call of each tool
+func toolsJSONDoc(tools []*shared.ToolDefinition) ([]byte, error) {
+	definitions := make([]map[string]interface{}, 0, len(tools))
+	for _, tool := range tools {
+		definition := map[string]interface{}{
+			"name":        tool.Name,
+			"description": tool.Description,
+			"inputSchema": tool.InputSchema,
+			"example":     exampleArguments(tool.InputSchema),
+		}
+		if tool.Annotations != nil {
+			definition["annotations"] = tool.Annotations.Map()
+		}
+		if schema := tool.OutputSchema(); schema != nil {
+			definition["outputSchema"] = schema
+		}
+		definitions = append(definitions, definition)
+	}
+	return marshalDoc(map[string]interface{}{"server": serverName, "tools": definitions}, "  ")
+}
+
+// marshalDoc encodes JSON for the docs, without escaping the <placeholders> of examples
+func marshalDoc(value interface{}, indent string) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", indent)
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// toolsMarkdownDoc renders the human-readable reference with one section per tool
+func toolsMarkdownDoc(tools []*shared.ToolDefinition) []byte {
+	var b strings.Builder
+	b.WriteString("# Tool Reference\n\n")
+	b.WriteString("<!-- Generated by \"zerops-mcp docs\" (go generate ./cmd/mcp-server). Do not edit. -->\n\n")
+	fmt.Fprintf(&b, "%d tools. The same data is in [%s](%s) for programs.\n\n", len(tools), docsJSONFile, docsJSONFile)
+	for _, tool := range tools {
+		fmt.Fprintf(&b, "- [%s](#%s)\n", tool.Name, tool.Name)
+	}
+
+	for _, tool := range tools {
+		fmt.Fprintf(&b, "\n## %s\n\n", tool.Name)
+		if tool.Annotations != nil {
+			fmt.Fprintf(&b, "_%s_\n\n", annotationSummary(tool.Annotations))
+		}
+		b.WriteString(tool.Description)
+		b.WriteString("\n")
+
+		if rows := schemaRows(tool.InputSchema, true); len(rows) > 0 {
+			b.WriteString("\n### Arguments\n\n| Name | Type | Required | Description |\n| --- | --- | --- | --- |\n")
+			for _, row := range rows {
+				fmt.Fprintf(&b, "| `%s` | %s | %s | %s |\n", row.name, row.kind, yesNo(row.required), markdownCell(row.description))
+			}
+		}
+		if rows := schemaRows(tool.OutputSchema(), false); len(rows) > 0 {
+			b.WriteString("\n### Result\n\n| Field | Type | Always present |\n| --- | --- | --- |\n")
+			for _, row := range rows {
+				fmt.Fprintf(&b, "| `%s` | %s | %s |\n", row.name, row.kind, yesNo(row.required))
+			}
+		}
+
+		example, _ := marshalDoc(exampleArguments(tool.InputSchema), "")
+		fmt.Fprintf(&b, "\n### Example\n\n```bash\nzerops-mcp call %s --args '%s'\n```\n", tool.Name, bytes.TrimSpace(example))
+	}
+	return []byte(b.String())
+}
+
+// schemaRow is one property of an input or output schema in the markdown tables
+type schemaRow struct {
+	name        string
+	kind        string
+	required    bool
+	description string
+}
+
+// schemaRows lists the top-level properties of an object schema. Input arguments keep the
+// order of toolParameters (required first); result fields are sorted by name.
+func schemaRows(schema map[string]interface{}, requiredFirst bool) []schemaRow {
+	properties, _ := schema["properties"].(map[string]interface{})
+	required := map[string]bool{}
+	switch list := schema["required"].(type) {
+	case []string:
+		for _, name := range list {
+			required[name] = true
+		}
+	case []interface{}:
+		for _, name := range list {
+			required[fmt.Sprint(name)] = true
+		}
+	}
+
+	rows := make([]schemaRow, 0, len(properties))
+	for name, value := range properties {
+		property, _ := value.(map[string]interface{})
+		description, _ := property["description"].(string)
+		rows = append(rows, schemaRow{name: name, kind: schemaType(property), required: required[name], description: description})
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if requiredFirst && rows[i].required != rows[j].required {
+			return rows[i].required
+		}
+		return rows[i].name < rows[j].name
+	})
+	return rows
+}
+
+// schemaType is a short type name such as string, integer[] or object
+func schemaType(property map[string]interface{}) string {
+	kind, _ := property["type"].(string)
+	switch kind {
+	case "":
+		return "any"
+	case "array":
+		items, _ := property["items"].(map[string]interface{})
+		return schemaType(items) + "[]"
+	}
+	if values, ok := property["enum"].([]string); ok {
+		return kind + " (" + strings.Join(values, ", ") + ")"
+	}
+	return kind
+}
+
+// exampleArguments builds the arguments of an example call from the required properties:
+// the default or first enum value when the schema has one, a placeholder otherwise
+func exampleArguments(schema map[string]interface{}) map[string]interface{} {
+	properties, _ := schema["properties"].(map[string]interface{})
+	example := map[string]interface{}{}
+	for _, row := range schemaRows(schema, true) {
+		if !row.required {
+			break
+		}
+		property, _ := properties[row.name].(map[string]interface{})
+		example[row.name] = exampleValue(row.name, property)
+	}
+	return example
+}
+
+func exampleValue(name string, property map[string]interface{}) interface{} {
+	if value, ok := property["default"]; ok {
+		return value
+	}
+	if values, ok := property["enum"].([]string); ok && len(values) > 0 {
+		return values[0]
+	}
+	switch property["type"] {
+	case "integer", "number":
+		if minimum, ok := property["minimum"]; ok {
+			return minimum
+		}
+		return 1
+	case "boolean":
+		return true
+	case "array":
+		items, _ := property["items"].(map[string]interface{})
+		return []interface{}{exampleValue(strings.TrimSuffix(name, "s"), items)}
+	case "object":
+		return map[string]interface{}{}
+	}
+	return "<" + name + ">"
+}
+
+// annotationSummary describes the behavior hints in words
+func annotationSummary(annotations *shared.ToolAnnotations) string {
+	switch {
+	case annotations.ReadOnly:
+		return "Read-only"
+	case annotations.Destructive && annotations.Idempotent:
+		return "Mutating, destructive, idempotent"
+	case annotations.Destructive:
+		return "Mutating, destructive"
+	case annotations.Idempotent:
+		return "Mutating, idempotent"
+	}
+	return "Mutating"
+}
+
+func yesNo(value bool) string {
+	if value {
+		return "yes"
+	}
+	return "no"
+}
+
+// markdownCell keeps a description on one table row
+func markdownCell(text string) string {
+	return strings.NewReplacer("|", "\\|", "\n", " ").Replace(text)
+}
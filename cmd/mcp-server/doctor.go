@@ -0,0 +1,215 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/tools"
+)
+
+const (
+	// doctorTimeout bounds each network check
+	doctorTimeout = 10 * time.Second
+	// knowledgeCheckURL is a guide load_platform_guide fetches
+	knowledgeCheckURL = "https://raw.githubusercontent.com/zeropsio/zagent-knowledge/main/fresh_project.md"
+	// maxClockSkew is the skew at which the check fails; a quarter of it warns
+	maxClockSkew = 5 * time.Minute
+)
+
+// doctorStatus is the outcome of one preflight check
+type doctorStatus string
+
+const (
+	doctorPass doctorStatus = "PASS"
+	doctorWarn doctorStatus = "WARN"
+	doctorFail doctorStatus = "FAIL"
+)
+
+// doctorCheck is one line of the checklist
+type doctorCheck struct {
+	name   string
+	status doctorStatus
+	detail string
+}
+
+// runDoctor checks the setup the server depends on, prints a checklist and returns the exit
+// code: 1 when any check failed. Warnings only affect some tools and don't fail the run.
+func runDoctor(out io.Writer) int {
+	ctx := context.Background()
+	var checks []doctorCheck
+	add := func(name string, status doctorStatus, format string, args ...interface{}) {
+		checks = append(checks, doctorCheck{name: name, status: status, detail: fmt.Sprintf(format, args...)})
+	}
+
+	// Unauthenticated requests get 4xx answers, which still prove the API is reachable
+	serverTime, latency, status, endpointErr := checkEndpoint(ctx, apiEndpoint)
+	if endpointErr == nil && status >= http.StatusInternalServerError {
+		endpointErr = fmt.Errorf("HTTP %d", status)
+	}
+	if endpointErr != nil {
+		add("API endpoint", doctorFail, "%s unreachable: %v", apiEndpoint, endpointErr)
+	} else {
+		add("API endpoint", doctorPass, "%s answered in %s", apiEndpoint, latency.Round(time.Millisecond))
+		skew := time.Since(serverTime).Round(time.Second)
+		if skew < 0 {
+			skew = -skew
+		}
+		switch {
+		case serverTime.IsZero():
+			add("Clock skew", doctorWarn, "the API response had no Date header to compare with")
+		case skew >= maxClockSkew:
+			add("Clock skew", doctorFail, "local clock is %s off the API clock; sync it (NTP), OAuth tokens and schedules depend on it", skew)
+		case skew >= maxClockSkew/4:
+			add("Clock skew", doctorWarn, "local clock is %s off the API clock", skew)
+		default:
+			add("Clock skew", doctorPass, "within %s of the API clock", max(skew, time.Second))
+		}
+	}
+
+	apiKey := os.Getenv("ZEROPS_API_KEY")
+	switch {
+	case apiKey == "":
+		add("API key", doctorFail, "ZEROPS_API_KEY is not set; create one at https://app.zerops.io/settings/token-management")
+	case endpointErr != nil:
+		add("API key", doctorFail, "not checked, the API endpoint is unreachable")
+	default:
+		keyCtx, cancel := context.WithTimeout(ctx, doctorTimeout)
+		identity, err := shared.ResolveIdentity(keyCtx, createZeropsClient(apiKey), apiKey)
+		cancel()
+		if err != nil {
+			add("API key", doctorFail, "rejected by the API: %v", err)
+		} else {
+			add("API key", doctorPass, "%s, organizations: %s", identity.Email, identity.OrgNames())
+		}
+	}
+
+	if zcliPath, err := exec.LookPath("zcli"); err != nil {
+		add("zcli", doctorWarn, "not on PATH; deploy_push needs it (https://docs.zerops.io/references/cli)")
+	} else {
+		versionCtx, cancel := context.WithTimeout(ctx, doctorTimeout)
+		version, err := exec.CommandContext(versionCtx, zcliPath, "version").CombinedOutput()
+		cancel()
+		if err != nil {
+			add("zcli", doctorWarn, "%s found but 'zcli version' failed: %v", zcliPath, err)
+		} else {
+			add("zcli", doctorPass, "%s (%s)", strings.TrimSpace(firstLine(string(version))), zcliPath)
+		}
+	}
+
+	if _, latency, status, err := checkEndpoint(ctx, knowledgeCheckURL); err != nil || status != http.StatusOK {
+		if err == nil {
+			err = fmt.Errorf("HTTP %d", status)
+		}
+		add("Knowledge API", doctorWarn, "unreachable, load_platform_guide falls back to built-in guides: %v", err)
+	} else {
+		add("Knowledge API", doctorPass, "answered in %s", latency.Round(time.Millisecond))
+	}
+
+	if dir, err := checkStateDir(); err != nil {
+		add("State directory", doctorFail, "%v; set ZEROPS_MCP_STATE_DIR to a writable directory", err)
+	} else {
+		add("State directory", doctorPass, "%s is writable", dir)
+	}
+
+	if source, err := checkMasterKey(); err != nil {
+		add("Master key", doctorWarn, "%v; scheduled actions and the session store can't be persisted", err)
+	} else {
+		add("Master key", doctorPass, "from %s, encryption round trip works", source)
+	}
+
+	failed := 0
+	for _, check := range checks {
+		fmt.Fprintf(out, "%-4s  %-16s %s\n", check.status, check.name, check.detail)
+		if check.status == doctorFail {
+			failed++
+		}
+	}
+	if failed > 0 {
+		fmt.Fprintf(out, "\n%d of %d checks failed\n", failed, len(checks))
+		return 1
+	}
+	fmt.Fprintf(out, "\nAll checks passed\n")
+	return 0
+}
+
+// checkEndpoint requests url and returns the server time from the Date header (zero without
+// one), the latency and the HTTP status
+func checkEndpoint(ctx context.Context, url string) (time.Time, time.Duration, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, doctorTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return time.Time{}, 0, 0, err
+	}
+	start := time.Now()
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return time.Time{}, 0, 0, err
+	}
+	latency := time.Since(start)
+	resp.Body.Close()
+	serverTime, _ := http.ParseTime(resp.Header.Get("Date"))
+	// The Date header has second precision and is set before the response is sent
+	if !serverTime.IsZero() {
+		serverTime = serverTime.Add(latency / 2)
+	}
+	return serverTime, latency, resp.StatusCode, nil
+}
+
+// checkStateDir writes and removes a file in the directory where state is persisted
+func checkStateDir() (string, error) {
+	schedulePath := tools.DefaultSchedulePath()
+	if schedulePath == "" {
+		return "", fmt.Errorf("no user config directory")
+	}
+	dir := filepath.Dir(schedulePath)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return dir, err
+	}
+	file, err := os.CreateTemp(dir, ".doctor-*")
+	if err != nil {
+		return dir, err
+	}
+	name := file.Name()
+	_, err = file.WriteString("ok")
+	file.Close()
+	os.Remove(name)
+	return dir, err
+}
+
+// checkMasterKey seals and opens a value with the master key
+func checkMasterKey() (string, error) {
+	source, err := shared.MasterKeySource()
+	if err != nil {
+		return "", err
+	}
+	probe := []byte("zerops-mcp doctor")
+	sealed, err := shared.SealSecret(probe)
+	if err != nil {
+		return source, err
+	}
+	opened, _, err := shared.OpenSecret(sealed)
+	if err != nil {
+		return source, err
+	}
+	if !bytes.Equal(opened, probe) {
+		return source, fmt.Errorf("decrypted value differs")
+	}
+	return source, nil
+}
+
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
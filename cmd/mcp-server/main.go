@@ -10,18 +10,24 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers"
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/fxck/zerops-mcp-go-sdk/internal/inspector"
+	"github.com/fxck/zerops-mcp-go-sdk/internal/mock"
+	"github.com/fxck/zerops-mcp-go-sdk/internal/replay"
+	"github.com/fxck/zerops-mcp-go-sdk/internal/transport"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
-	"github.com/zerops-mcp-basic/internal/handlers"
-	"github.com/zerops-mcp-basic/internal/transport"
 	"github.com/zeropsio/zerops-go/sdk"
 	"github.com/zeropsio/zerops-go/sdkBase"
 )
 
 const (
 	serverName    = "zerops-mcp"
-	serverVersion = "1.0.0"
+	serverVersion = shared.ServerVersion
 	apiEndpoint   = "https://api.app-prg1.zerops.io"
 )
 
@@ -29,16 +35,80 @@ const (
 var globalClientInfo *mcp.Implementation
 
 func main() {
-	// Parse command-line flags
+	// Without a subcommand the flags are serve flags, as before subcommands existed
+	command, args := "serve", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = args[0], args[1:]
+	}
+
+	switch command {
+	case "serve":
+		runServe(args)
+	case "tools":
+		os.Exit(runTools(args))
+	case "call":
+		os.Exit(runCall(args))
+	case "doctor":
+		os.Exit(runDoctor(os.Stdout))
+	case "docs":
+		os.Exit(runDocs(args))
+	case "help":
+		printUsage(os.Stdout)
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n\n", command)
+		printUsage(os.Stderr)
+		os.Exit(2)
+	}
+}
+
+// runServe starts the MCP server, or one of its alternative modes (inspector, replay, sealing)
+func runServe(args []string) {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
 	var (
-		transportMode = flag.String("transport", getEnvOrDefault("MCP_TRANSPORT", "stdio"), "Transport mode: stdio or http")
-		httpHost      = flag.String("host", getEnvOrDefault("MCP_HTTP_HOST", "0.0.0.0"), "HTTP server host (http mode only)")
-		httpPort      = flag.String("port", getEnvOrDefault("MCP_HTTP_PORT", "8080"), "HTTP server port (http mode only)")
+		transportMode = flags.String("transport", getEnvOrDefault("MCP_TRANSPORT", "stdio"), "Transport mode: stdio or http")
+		httpHost      = flags.String("host", getEnvOrDefault("MCP_HTTP_HOST", "0.0.0.0"), "HTTP server host (http mode only)")
+		httpPort      = flags.String("port", getEnvOrDefault("MCP_HTTP_PORT", "8080"), "HTTP server port (http mode only)")
+		grpcPort      = flags.String("grpc-port", os.Getenv("MCP_GRPC_PORT"), "Also serve the gRPC ToolService on this port (http mode only)")
+		sessionStore  = flags.String("session-store", os.Getenv("MCP_SESSION_STORE"), "Persist session state to this encrypted file across restarts (http mode only)")
+		metricsPort   = flags.String("metrics-port", os.Getenv("MCP_METRICS_PORT"), "Serve authentication counters at /metrics on this port of 127.0.0.1 (http mode only)")
+		sealFile      = flags.String("seal-file", "", "Encrypt a credentials file (e.g. the OAuth key vault) in place with the master key and exit")
+		inspect       = flags.Bool("inspect", false, "Serve a local web UI for calling tools with ZEROPS_API_KEY instead of an MCP transport")
+		inspectAddr   = flags.String("inspect-addr", getEnvOrDefault("MCP_INSPECT_ADDR", "127.0.0.1:8790"), "Listen address of the inspector UI (inspect mode only)")
+		recordFile    = flags.String("record", "", "Record tool calls and API responses of the session to a file")
+		replayFile    = flags.String("replay", "", "Replay a recorded session against its recorded API responses and exit")
 	)
-	flag.Parse()
+	flags.Parse(args)
+
+	if *sealFile != "" {
+		if err := sealCredentialsFile(*sealFile); err != nil {
+			log.Fatalf("Failed to seal %s: %v", *sealFile, err)
+		}
+		fmt.Fprintf(os.Stderr, "Sealed %s\n", *sealFile)
+		return
+	}
 
 	// Initialize global tool registry first
-	handlers.InitializeRegistry()
+	initialize()
+
+	if *replayFile != "" {
+		runReplay(*replayFile)
+		return
+	}
+
+	// Recording wraps the default transport, so it must start before any client exists
+	if *recordFile != "" {
+		recorder, err := replay.StartRecording(*recordFile)
+		if err != nil {
+			log.Fatalf("Failed to start recording: %v", err)
+		}
+		defer recorder.Close()
+		fmt.Fprintf(os.Stderr, "Recording tool calls to %s\n", *recordFile)
+	}
+
+	if *inspect {
+		runInspector(*inspectAddr)
+		return
+	}
 
 	// Create MCP server with initialized handler
 	server := mcp.NewServer(
@@ -47,12 +117,13 @@ func main() {
 			Version: serverVersion,
 		},
 		&mcp.ServerOptions{
+			Instructions: shared.ServerInstructions(),
 			InitializedHandler: func(ctx context.Context, session *mcp.ServerSession, params *mcp.InitializedParams) {
 				if globalClientInfo != nil {
-					fmt.Fprintf(os.Stderr, "✓ Client connected: %s v%s (session: %s)\n", 
+					fmt.Fprintf(os.Stderr, "Client connected: %s v%s (session: %s)\n", 
 						globalClientInfo.Name, globalClientInfo.Version, session.ID())
 				} else {
-					fmt.Fprintf(os.Stderr, "✓ Client initialized session: %s\n", session.ID())
+					fmt.Fprintf(os.Stderr, "Client initialized session: %s\n", session.ID())
 				}
 			},
 		},
@@ -92,13 +163,18 @@ func main() {
 		}
 		client = createZeropsClient(apiKey)
 
-		// Register tools with MCP server for stdio
+		// Register tools with MCP server for stdio; rotate_api_key swaps the session client
+		handlers.SetClientFactory(createZeropsClient)
 		if err := handlers.RegisterForMCPWithClientInfo(server, client, &globalClientInfo); err != nil {
 			log.Fatalf("Failed to register handlers: %v", err)
 		}
 	} else if *transportMode == "http" {
-		// HTTP mode: API key will come from client requests
-		log.Println("HTTP mode: API keys will be provided by clients via Authorization header")
+		// HTTP mode: API key will come from client requests, ZEROPS_API_KEY is an optional fallback
+		if os.Getenv("ZEROPS_API_KEY") != "" {
+			log.Println("HTTP mode: using ZEROPS_API_KEY for requests without an Authorization header")
+		} else {
+			log.Println("HTTP mode: API keys will be provided by clients via Authorization header")
+		}
 		// No need to register with MCP server - HTTP will use shared registry directly
 	} else {
 		log.Fatalf("Invalid transport mode: %s (must be 'stdio' or 'http')", *transportMode)
@@ -117,19 +193,88 @@ func main() {
 		cancel()
 	}()
 
+	// Run scheduled actions; only stdio mode has a stable API key to persist them for
+	handlers.StartScheduler(ctx, client, *transportMode == "stdio")
+
 	// Start server based on transport mode
 	switch *transportMode {
 	case "stdio":
 		startStdioServer(ctx, server)
 	case "http":
-		startHTTPServer(ctx, server, *httpHost, *httpPort)
+		startHTTPServer(ctx, server, *httpHost, *httpPort, *grpcPort, *sessionStore, *metricsPort)
+	}
+
+	// Ship the audit events still queued for the exporter
+	if shared.GlobalExporter != nil {
+		shared.GlobalExporter.Close(5 * time.Second)
 	}
 }
 
+// initialize registers the tools and enables mock mode when MCP_MOCK is set
+func initialize() {
+	handlers.InitializeRegistry()
+
+	// Mock mode answers every API request with canned data; no API key needed
+	if os.Getenv("MCP_MOCK") == "true" {
+		mock.Enable()
+		if os.Getenv("ZEROPS_API_KEY") == "" {
+			os.Setenv("ZEROPS_API_KEY", mock.APIKey)
+		}
+		fmt.Fprintf(os.Stderr, "Mock mode: tools return canned data, nothing reaches the Zerops API\n")
+	}
+}
+
+// runInspector serves the inspector UI until interrupted
+func runInspector(addr string) {
+	apiKey := os.Getenv("ZEROPS_API_KEY")
+	if apiKey == "" {
+		log.Fatal("ZEROPS_API_KEY environment variable is required for inspect mode")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Fprintf(os.Stderr, "Starting %s v%s inspector...\n", serverName, serverVersion)
+	err := inspector.Start(ctx, inspector.Config{
+		Addr:          addr,
+		Client:        createZeropsClient(apiKey),
+		ClientFactory: createZeropsClient,
+	})
+	if err != nil {
+		log.Fatalf("Inspector error: %v", err)
+	}
+	if shared.GlobalExporter != nil {
+		shared.GlobalExporter.Close(5 * time.Second)
+	}
+}
+
+// runReplay replays a recorded session and exits non-zero when any result differs
+func runReplay(path string) {
+	ok, err := replay.Run(context.Background(), path, createZeropsClient, os.Stdout)
+	if err != nil {
+		log.Fatalf("Replay failed: %v", err)
+	}
+	if !ok {
+		os.Exit(1)
+	}
+}
+
+// sealCredentialsFile encrypts a plaintext file in place; already sealed files are left untouched
+func sealCredentialsFile(path string) error {
+	data, sealed, err := shared.ReadSealedFile(path)
+	if err != nil {
+		return err
+	}
+	if sealed {
+		return nil
+	}
+	return shared.WriteSealedFile(path, data)
+}
+
 func startStdioServer(ctx context.Context, server *mcp.Server) {
 	fmt.Fprintf(os.Stderr, "Starting %s v%s in stdio mode...\n", serverName, serverVersion)
 
-	stdioTransport := mcp.NewStdioTransport()
+	stdioTransport := handlers.SubscriptionTransport(mcp.NewStdioTransport())
 	if err := server.Run(ctx, stdioTransport); err != nil {
 		if err != context.Canceled {
 			log.Fatalf("Stdio server error: %v", err)
@@ -137,14 +282,37 @@ func startStdioServer(ctx context.Context, server *mcp.Server) {
 	}
 }
 
-func startHTTPServer(ctx context.Context, server *mcp.Server, host, port string) {
+func startHTTPServer(ctx context.Context, server *mcp.Server, host, port, grpcPort, sessionStore, metricsPort string) {
 	fmt.Fprintf(os.Stderr, "Starting %s v%s in HTTP mode on %s:%s...\n", serverName, serverVersion, host, port)
-	fmt.Fprintf(os.Stderr, "Authentication: Bearer token with ZEROPS_API_KEY\n")
+
+	oauth, err := transport.LoadOAuthConfigFromEnv()
+	if err != nil {
+		log.Fatalf("Invalid OAuth configuration: %v", err)
+	}
+	if oauth != nil {
+		fmt.Fprintf(os.Stderr, "Authentication: OAuth access tokens issued by %s\n", oauth.Issuer)
+	} else {
+		fmt.Fprintf(os.Stderr, "Authentication: Bearer token with ZEROPS_API_KEY\n")
+	}
 
 	config := transport.HTTPServerConfig{
 		Host:   host,
 		Port:   port,
 		Server: server,
+		APIKey: os.Getenv("ZEROPS_API_KEY"),
+		OAuth:  oauth,
+	}
+	if grpcPort != "" {
+		config.GRPCPort = grpcPort
+		fmt.Fprintf(os.Stderr, "gRPC ToolService on %s:%s\n", host, grpcPort)
+	}
+	if sessionStore != "" {
+		config.SessionStore = sessionStore
+		fmt.Fprintf(os.Stderr, "Session store: %s\n", sessionStore)
+	}
+	if metricsPort != "" {
+		config.MetricsPort = metricsPort
+		fmt.Fprintf(os.Stderr, "Metrics on 127.0.0.1:%s/metrics\n", metricsPort)
 	}
 
 	// Use the HTTP handler with global registry
@@ -167,7 +335,7 @@ func createZeropsClient(apiKey string) *sdk.Handler {
 		Endpoint: apiEndpoint,
 	}
 
-	baseSDK := sdk.New(config, http.DefaultClient)
+	baseSDK := sdk.New(config, shared.BudgetedHTTPClient(""))
 	authorizedSDK := sdk.AuthorizeSdk(baseSDK, apiKey)
 
 	return &authorizedSDK
@@ -3,7 +3,7 @@
 # Deploy script for Zerops MCP HTTP server
 
 echo "Building for Linux x86_64..."
-GOOS=linux GOARCH=amd64 go build -o zerops-mcp-linux ./cmd/mcp-server/main.go
+GOOS=linux GOARCH=amd64 go build -o zerops-mcp-linux ./cmd/mcp-server
 
 echo "Deploying to server..."
 scp zerops-mcp-linux mcp.zerops:/var/www/zerops-mcp
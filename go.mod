@@ -1,6 +1,6 @@
-module github.com/zerops-mcp-basic
+module github.com/fxck/zerops-mcp-go-sdk
 
-go 1.23.0
+go 1.24.0
 
 toolchain go1.24.5
 
@@ -3,12 +3,14 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sync/atomic"
 
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/tools"
 	"github.com/modelcontextprotocol/go-sdk/jsonschema"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
-	"github.com/zerops-mcp-basic/internal/handlers/shared"
-	"github.com/zerops-mcp-basic/internal/handlers/tools"
 	"github.com/zeropsio/zerops-go/sdk"
 )
 
@@ -16,11 +18,74 @@ import (
 // This should be called at startup before any transport is initialized
 func InitializeRegistry() {
 	// Register simplified MCP tool handlers
-	tools.RegisterDiscovery()        // discovery tool
-	tools.RegisterServiceTools()     // get_service_types, import_services, enable_preview_subdomain, scale_service, get_service_logs
-	tools.RegisterEnvironment()      // set_project_env, set_service_env
-	tools.RegisterProcesses()        // get_running_processes
-	tools.RegisterKnowledgeBase()    // knowledge_base
+	tools.RegisterDiscovery()      // discovery tool
+	tools.RegisterServiceTools()   // get_service_types, import_services, enable_preview_subdomain, scale_service, get_service_logs
+	tools.RegisterEnvironment()    // get/set/delete_project_env, get/set/delete_service_env
+	tools.RegisterLifecycle()      // start_project, stop_project, start_service, stop_service
+	tools.RegisterProcesses()      // get_running_processes
+	tools.RegisterKnowledgeBase()  // knowledge_base
+	tools.RegisterStateHistory()   // state_history
+	tools.RegisterServiceClone()   // service_clone
+	tools.RegisterScheduler()      // schedule_action, list_scheduled_actions, cancel_scheduled_action
+	tools.RegisterWatch()          // watch_service, unwatch_service
+	tools.RegisterDeployImpact()   // deploy_impact
+	tools.RegisterAccessStats()    // get_access_stats
+	tools.RegisterRecipeFit()      // check_recipe_fit
+	tools.RegisterHostname()       // suggest_hostname
+	tools.RegisterWait()           // wait_for_service
+	tools.RegisterDiscoverAll()    // discover_all
+	tools.RegisterProjectDiff()    // project_diff
+	tools.RegisterProjectApply()   // project_apply
+	tools.RegisterCredentials()    // credentials_doctor
+	tools.RegisterGuidelines()     // company_guidelines (only with ORG_GUIDELINES_PATH)
+	tools.RegisterBudget()         // budget_status
+	tools.RegisterRoots()          // list_effective_roots
+	tools.RegisterDeployConfig()   // get_deployment_config
+	tools.RegisterOutputFormat()   // set_output_format
+	tools.RegisterPreferences()    // set_preferences
+	tools.RegisterHealthCheck()    // generate_healthcheck
+	tools.RegisterEnvReferences()  // validate_env_references
+	tools.RegisterTroubleshoot()   // troubleshoot_service
+	tools.RegisterRuntimeInfo()    // get_runtime_info
+	tools.RegisterEnvBulk()        // set_env_bulk
+	tools.RegisterKeyRotation()    // rotate_api_key
+	tools.RegisterWhoami()         // whoami
+	tools.RegisterLogExport()      // export_service_logs
+	tools.RegisterProcessWait()    // wait_for_process
+	tools.RegisterInfraPlan()      // plan_infrastructure
+	tools.RegisterAppVersions()    // list_app_versions
+	tools.RegisterEnvironments()   // list_environments, create_environment
+	tools.RegisterRollback()       // rollback_deployment
+	tools.RegisterDeployPush()     // deploy_push
+	tools.RegisterZeropsYml()      // generate_zerops_yml
+	tools.RegisterDeployValidate() // deploy_validate
+	tools.RegisterEnvRestart()     // apply_env_and_restart
+	tools.RegisterHttpRouting()    // list_http_routing, set_http_routing, delete_http_routing, apply_http_routing
+	tools.RegisterImportLint()     // lint_import_yaml
+	tools.RegisterBackups()        // backup_list, backup_create, backup_download
+	tools.RegisterSharedStorage()  // list_shared_storages, create_shared_storage, connect_shared_storage, disconnect_shared_storage
+	tools.RegisterVpn()            // vpn_connect
+	tools.RegisterProjectCost()    // project_cost
+	tools.RegisterUsageReport()    // export_usage_report
+	tools.RegisterProjectExport()  // project_export
+	tools.RegisterRename()         // project_rename, service_rename
+	tools.RegisterProjectTags()    // list_project_tags, add_project_tags, remove_project_tags
+	tools.RegisterContainers()     // list_containers
+	tools.RegisterCurrentProject() // current_project
+	tools.RegisterServiceMetrics() // get_service_metrics
+	tools.RegisterRegions()        // list_regions
+	tools.RegisterResources()      // zerops://project/{id}, zerops://service/{id}, their /env and app version deploy configs
+	tools.RegisterPrompts()        // fresh_project, add_service, debug_deploy prompts
+}
+
+// StartScheduler starts executing scheduled actions in the background.
+// In stdio mode pass the server client so persisted actions can run after a restart.
+func StartScheduler(ctx context.Context, client *sdk.Handler, persist bool) {
+	persistPath := ""
+	if persist {
+		persistPath = tools.DefaultSchedulePath()
+	}
+	tools.StartScheduler(ctx, client, persistPath)
 }
 
 // RegisterForMCP registers all tools with the MCP server for stdio transport
@@ -29,11 +94,36 @@ func RegisterForMCP(server *mcp.Server, client *sdk.Handler) error {
 	return RegisterForMCPWithClientInfo(server, client, nil)
 }
 
+// clientFactory builds stdio session clients for rotate_api_key; nil disables rotation
+var clientFactory shared.ClientFactory
+
+// SetClientFactory lets rotate_api_key switch the stdio session to a new API key.
+// Call it before RegisterForMCPWithClientInfo.
+func SetClientFactory(factory shared.ClientFactory) {
+	clientFactory = factory
+}
+
+// stdioSession is the MCP session of stdio calls
+const stdioSession = "stdio"
+
+// stdioClient returns the client of the stdio session, which rotate_api_key may replace
+var stdioClient = func() *sdk.Handler { return nil }
+
 // RegisterForMCPWithClientInfo registers all tools with client info support
 func RegisterForMCPWithClientInfo(server *mcp.Server, client *sdk.Handler, clientInfo **mcp.Implementation) error {
 	// Get all tools from the shared registry
 	toolDefs := shared.GlobalRegistry.List()
 
+	// The session client is replaced when the API key is rotated
+	var current atomic.Pointer[sdk.Handler]
+	current.Store(client)
+	stdioClient = current.Load
+	factory := clientFactory
+	keyStore := func(ctx context.Context, apiKey string, client *sdk.Handler) error {
+		current.Store(client)
+		return nil
+	}
+
 	// Register each tool with the MCP server
 	for _, toolDef := range toolDefs {
 		// Create a closure to capture the tool definition
@@ -84,6 +174,14 @@ func RegisterForMCPWithClientInfo(server *mcp.Server, client *sdk.Handler, clien
 			Description: td.Description,
 			InputSchema: inputSchema,
 		}
+		if td.Annotations != nil {
+			destructive := td.Annotations.Destructive
+			mcpTool.Annotations = &mcp.ToolAnnotations{
+				ReadOnlyHint:    td.Annotations.ReadOnly,
+				DestructiveHint: &destructive,
+				IdempotentHint:  td.Annotations.Idempotent,
+			}
+		}
 
 		// Create handler that bridges to shared handler
 		handler := mcp.ToolHandler(func(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParamsFor[map[string]any]) (*mcp.CallToolResultFor[any], error) {
@@ -91,10 +189,54 @@ func RegisterForMCPWithClientInfo(server *mcp.Server, client *sdk.Handler, clien
 			args := params.Arguments
 
 			// Add client to context if available
+			client := current.Load()
 			if client != nil {
 				ctx = context.WithValue(ctx, "zeropsClient", client)
 			}
+			if factory != nil {
+				ctx = shared.WithClientFactory(ctx, factory)
+				ctx = shared.WithKeyStore(ctx, keyStore)
+			}
+			// A stdio server has one client for its lifetime, which is the session
+			ctx = shared.WithSession(ctx, stdioSession)
 			
+			// Allow tools to push notifications and log messages to this session;
+			// the session drops messages below the level set by the client
+			notifier := func(ctx context.Context, level, logger string, data interface{}) error {
+				return session.Log(ctx, &mcp.LoggingMessageParams{
+					Level:  mcp.LoggingLevel(level),
+					Logger: logger,
+					Data:   data,
+				})
+			}
+			ctx = shared.WithNotifier(ctx, notifier)
+			ctx = shared.WithLogger(ctx, notifier)
+
+			// Scope local filesystem operations to the client's roots
+			ctx = shared.WithRoots(ctx, func(ctx context.Context) ([]shared.Root, error) {
+				result, err := session.ListRoots(ctx, nil)
+				if err != nil {
+					return nil, err
+				}
+				roots := make([]shared.Root, 0, len(result.Roots))
+				for _, root := range result.Roots {
+					roots = append(roots, shared.RootFromURI(root.Name, root.URI))
+				}
+				return roots, nil
+			})
+
+			// Report progress when the client sent a progress token
+			if token := params.GetProgressToken(); token != nil {
+				ctx = shared.WithProgress(ctx, func(ctx context.Context, progress, total float64, message string) error {
+					return session.NotifyProgress(ctx, &mcp.ProgressNotificationParams{
+						ProgressToken: token,
+						Progress:      progress,
+						Total:         total,
+						Message:       message,
+					})
+				})
+			}
+
 			// Add client info to context if available
 			if clientInfo != nil && *clientInfo != nil {
 				ctx = context.WithValue(ctx, "clientName", (*clientInfo).Name)
@@ -102,15 +244,10 @@ func RegisterForMCPWithClientInfo(server *mcp.Server, client *sdk.Handler, clien
 			}
 
 			// Call the shared handler
-			result, err := td.Handler(ctx, client, args)
+			result, err := shared.GlobalRegistry.CallTool(ctx, td.Name, args)
 			if err != nil {
 				// Return error as MCP result
-				return &mcp.CallToolResultFor[any]{
-					Content: []mcp.Content{
-						&mcp.TextContent{Text: fmt.Sprintf("Error: %v", err)},
-					},
-					IsError: true,
-				}, nil
+				result = shared.ErrorResult(ctx, err)
 			}
 
 			// Convert result to MCP format
@@ -127,8 +264,9 @@ func RegisterForMCPWithClientInfo(server *mcp.Server, client *sdk.Handler, clien
 						}
 					}
 					return &mcp.CallToolResultFor[any]{
-						Content: content,
-						IsError: mcpResult["isError"] == true,
+						Content:           content,
+						StructuredContent: mcpResult["structuredContent"],
+						IsError:           mcpResult["isError"] == true,
 					}, nil
 				}
 			}
@@ -155,5 +293,89 @@ func RegisterForMCPWithClientInfo(server *mcp.Server, client *sdk.Handler, clien
 		mcp.AddTool(server, mcpTool, handler)
 	}
 
+	// Resources are read with the session client, like tools
+	for _, template := range shared.GlobalResources.Templates() {
+		server.AddResourceTemplate(&mcp.ResourceTemplate{
+			URITemplate: template.URITemplate,
+			Name:        template.Name,
+			Description: template.Description,
+			MIMEType:    shared.ResourceMIMEType,
+		}, func(ctx context.Context, session *mcp.ServerSession, params *mcp.ReadResourceParams) (*mcp.ReadResourceResult, error) {
+			if client := current.Load(); client != nil {
+				ctx = context.WithValue(ctx, "zeropsClient", client)
+			}
+			ctx = shared.WithSession(ctx, stdioSession)
+			text, err := shared.GlobalResources.ReadResource(ctx, params.URI)
+			if errors.Is(err, shared.ErrNotFound) {
+				return nil, mcp.ResourceNotFoundError(params.URI)
+			}
+			if err != nil {
+				return nil, err
+			}
+			return &mcp.ReadResourceResult{
+				Contents: []*mcp.ResourceContents{{URI: params.URI, MIMEType: shared.ResourceMIMEType, Text: text}},
+			}, nil
+		})
+	}
+
+	for _, prompt := range shared.GlobalPrompts.List() {
+		arguments := make([]*mcp.PromptArgument, 0, len(prompt.Arguments))
+		for _, arg := range prompt.Arguments {
+			arguments = append(arguments, &mcp.PromptArgument{Name: arg.Name, Description: arg.Description, Required: arg.Required})
+		}
+		server.AddPrompt(&mcp.Prompt{
+			Name:        prompt.Name,
+			Description: prompt.Description,
+			Arguments:   arguments,
+		}, func(ctx context.Context, session *mcp.ServerSession, params *mcp.GetPromptParams) (*mcp.GetPromptResult, error) {
+			definition, text, err := shared.GlobalPrompts.GetPrompt(params.Name, params.Arguments)
+			if err != nil {
+				return nil, err
+			}
+			return &mcp.GetPromptResult{
+				Description: definition.Description,
+				Messages:    []*mcp.PromptMessage{{Role: "user", Content: &mcp.TextContent{Text: text}}},
+			}, nil
+		})
+	}
+
+	// Tools are added before the client is known, so short descriptions are swapped in
+	// when the client lists them
+	server.AddReceivingMiddleware(toolDescriptionMiddleware(clientInfo))
+
 	return nil
 }
+
+// toolDescriptionMiddleware replaces the tool descriptions of tools/list results with the
+// short variants when the client should get them (see shared.ShortDescriptions)
+func toolDescriptionMiddleware(clientInfo **mcp.Implementation) mcp.Middleware[*mcp.ServerSession] {
+	return func(next mcp.MethodHandler[*mcp.ServerSession]) mcp.MethodHandler[*mcp.ServerSession] {
+		return func(ctx context.Context, session *mcp.ServerSession, method string, params mcp.Params) (mcp.Result, error) {
+			result, err := next(ctx, session, method, params)
+			list, ok := result.(*mcp.ListToolsResult)
+			if err != nil || method != "tools/list" || !ok {
+				return result, err
+			}
+			clientName := ""
+			if clientInfo != nil && *clientInfo != nil {
+				clientName = (*clientInfo).Name
+			}
+			if !shared.ShortDescriptions(clientName) {
+				return result, nil
+			}
+
+			// The listed tools are the server's own, so they are copied before changing them
+			short := *list
+			short.Tools = make([]*mcp.Tool, 0, len(list.Tools))
+			for _, tool := range list.Tools {
+				if td, ok := shared.GlobalRegistry.Get(tool.Name); ok {
+					copied := *tool
+					copied.Description = td.DescriptionFor(true)
+					tool = &copied
+				}
+				short.Tools = append(short.Tools, tool)
+			}
+			return &short, nil
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package shared
+
+import (
+	"context"
+
+	"github.com/zeropsio/zerops-go/sdk"
+)
+
+// ClientFactory creates a Zerops API client for an API key, configured like the server's own clients
+type ClientFactory func(apiKey string) *sdk.Handler
+
+// clientFactoryKey is the context key under which transports store their ClientFactory
+const clientFactoryKey = "mcpClientFactory"
+
+// WithClientFactory returns a context carrying the transport's client factory
+func WithClientFactory(ctx context.Context, factory ClientFactory) context.Context {
+	return context.WithValue(ctx, clientFactoryKey, factory)
+}
+
+// ClientFactoryFromContext returns the client factory, nil when the transport has none
+func ClientFactoryFromContext(ctx context.Context) ClientFactory {
+	factory, _ := ctx.Value(clientFactoryKey).(ClientFactory)
+	return factory
+}
+
+// KeyStore makes apiKey the key of the current session wherever the transport keeps it;
+// client is already authorized with apiKey
+type KeyStore func(ctx context.Context, apiKey string, client *sdk.Handler) error
+
+// keyStoreKey is the context key under which transports store the session KeyStore
+const keyStoreKey = "mcpKeyStore"
+
+// WithKeyStore returns a context carrying the session key store
+func WithKeyStore(ctx context.Context, store KeyStore) context.Context {
+	return context.WithValue(ctx, keyStoreKey, store)
+}
+
+// KeyStoreFromContext returns the session key store, nil when the client sends its key with
+// every request and only the client can change it
+func KeyStoreFromContext(ctx context.Context) KeyStore {
+	store, _ := ctx.Value(keyStoreKey).(KeyStore)
+	return store
+}
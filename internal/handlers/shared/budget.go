@@ -0,0 +1,196 @@
+package shared
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// defaultAPIBudget is the default number of Zerops API calls allowed per owner and window
+const defaultAPIBudget = 1000
+
+// defaultPollingBudget is the default number of polling calls of wait and watch tools allowed
+// per owner and window, on top of the API budget
+const defaultPollingBudget = 1000
+
+// budgetWindow is the length of a budget window
+const budgetWindow = time.Hour
+
+// budgetUsage counts the calls of one owner
+type budgetUsage struct {
+	windowStart time.Time
+	count       int
+	rejected    int
+	total       int
+	// background counts calls of pollers, which have their own cap
+	background int
+}
+
+// APIBudget caps outbound Zerops API calls per owner (API key) and hour, so runaway
+// agent loops cannot exhaust the user's rate limits
+type APIBudget struct {
+	limit        int
+	pollingLimit int
+
+	mu    sync.Mutex
+	usage map[string]*budgetUsage
+}
+
+// GlobalBudget is configured by ZEROPS_MCP_API_BUDGET and ZEROPS_MCP_POLLING_BUDGET
+// (calls per hour, 0 disables the cap)
+var GlobalBudget = newAPIBudgetFromEnv()
+
+func newAPIBudgetFromEnv() *APIBudget {
+	limit := defaultAPIBudget
+	if value, err := strconv.Atoi(os.Getenv("ZEROPS_MCP_API_BUDGET")); err == nil && value >= 0 {
+		limit = value
+	}
+	pollingLimit := defaultPollingBudget
+	if value, err := strconv.Atoi(os.Getenv("ZEROPS_MCP_POLLING_BUDGET")); err == nil && value >= 0 {
+		pollingLimit = value
+	}
+	return &APIBudget{limit: limit, pollingLimit: pollingLimit, usage: make(map[string]*budgetUsage)}
+}
+
+// OwnerID identifies an API key without exposing it
+func OwnerID(apiKey string) string {
+	if apiKey == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:8])
+}
+
+// usageLocked returns the usage of owner, starting a new window when the current one ended
+func (b *APIBudget) usageLocked(owner string, now time.Time) *budgetUsage {
+	usage, ok := b.usage[owner]
+	if !ok {
+		usage = &budgetUsage{windowStart: now}
+		b.usage[owner] = usage
+	}
+	if now.Sub(usage.windowStart) >= budgetWindow {
+		usage.windowStart = now
+		usage.count = 0
+		usage.rejected = 0
+		usage.background = 0
+	}
+	return usage
+}
+
+// Take records one API call for owner, failing once the window's budget is spent
+func (b *APIBudget) Take(owner string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	usage := b.usageLocked(owner, now)
+	if b.limit > 0 && usage.count >= b.limit {
+		usage.rejected++
+		resetIn := usage.windowStart.Add(budgetWindow).Sub(now).Round(time.Second)
+		return NewToolError(ErrBudgetExceeded,
+			"Zerops API call budget of %d calls per hour is spent; it resets in %s. Stop polling and check budget_status before retrying.",
+			b.limit, resetIn)
+	}
+	usage.count++
+	usage.total++
+	return nil
+}
+
+// TakeBackground records one API call of a background poller for owner, failing once the
+// window's polling budget is spent
+func (b *APIBudget) TakeBackground(owner string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	usage := b.usageLocked(owner, now)
+	if b.pollingLimit > 0 && usage.background >= b.pollingLimit {
+		usage.rejected++
+		resetIn := usage.windowStart.Add(budgetWindow).Sub(now).Round(time.Second)
+		return NewToolError(ErrBudgetExceeded,
+			"Zerops API polling budget of %d calls per hour is spent; it resets in %s. Stop waiting in a loop and check budget_status before retrying.",
+			b.pollingLimit, resetIn)
+	}
+	usage.background++
+	usage.total++
+	return nil
+}
+
+// backgroundPollingKey is the context key marking the polling of wait and watch tools
+const backgroundPollingKey = "backgroundPolling"
+
+// WithBackgroundPolling marks ctx as the polling of watch_service or a wait tool. Its API
+// calls are charged to the separate polling budget, so a long wait can't make unrelated
+// tools fail with BUDGET_EXCEEDED, while waiting in a loop is still capped.
+func WithBackgroundPolling(ctx context.Context) context.Context {
+	return context.WithValue(ctx, backgroundPollingKey, true)
+}
+
+// BudgetStatus is the budget state of an API key owner, as returned by budget_status.
+// Remaining is a number, or "unlimited" without a limit.
+type BudgetStatus struct {
+	LimitPerHour        int         `json:"limit_per_hour"`
+	Used                int         `json:"used"`
+	Rejected            int         `json:"rejected"`
+	Background          int         `json:"background_calls"`
+	PollingLimitPerHour int         `json:"polling_limit_per_hour"`
+	TotalCalls          int         `json:"total_calls"`
+	WindowStart         string      `json:"window_start"`
+	WindowReset         string      `json:"window_reset"`
+	Remaining           interface{} `json:"remaining"`
+}
+
+// Status returns the budget state of owner
+func (b *APIBudget) Status(owner string) BudgetStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	usage := b.usageLocked(owner, time.Now())
+	status := BudgetStatus{
+		LimitPerHour:        b.limit,
+		Used:                usage.count,
+		Rejected:            usage.rejected,
+		Background:          usage.background,
+		PollingLimitPerHour: b.pollingLimit,
+		TotalCalls:          usage.total,
+		WindowStart:         usage.windowStart.UTC().Format(time.RFC3339),
+		WindowReset:         usage.windowStart.Add(budgetWindow).UTC().Format(time.RFC3339),
+		Remaining:           "unlimited",
+	}
+	if b.limit > 0 {
+		status.Remaining = b.limit - usage.count
+	}
+	return status
+}
+
+// budgetTransport charges every outbound request to the owner's budget or polling budget
+type budgetTransport struct {
+	base  http.RoundTripper
+	owner string
+}
+
+func (t *budgetTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	take := GlobalBudget.Take
+	if background, _ := req.Context().Value(backgroundPollingKey).(bool); background {
+		take = GlobalBudget.TakeBackground
+	}
+	if err := take(t.owner); err != nil {
+		return nil, err
+	}
+	return t.base.RoundTrip(req)
+}
+
+// BudgetedHTTPClient returns an HTTP client whose requests count against the budget of owner
+// (OwnerID of the API key in HTTP mode, empty for the single stdio user). Requests are
+// retried during platform maintenance; retries are not charged. Writes of mutating tool
+// calls carry X-Invoked-By.
+func BudgetedHTTPClient(owner string) *http.Client {
+	return &http.Client{
+		Transport: &budgetTransport{base: &invokedByTransport{base: &maintenanceTransport{base: http.DefaultTransport}}, owner: owner},
+	}
+}
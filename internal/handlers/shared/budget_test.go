@@ -0,0 +1,91 @@
+package shared
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPIBudgetTake(t *testing.T) {
+	tests := []struct {
+		name           string
+		limit          int
+		pollingLimit   int
+		calls          int
+		polls          int
+		wantRejected   int
+		wantBackground int
+	}{
+		{name: "within both budgets", limit: 3, pollingLimit: 3, calls: 3, polls: 3, wantBackground: 3},
+		{name: "calls over budget", limit: 2, pollingLimit: 3, calls: 4, polls: 1, wantRejected: 2, wantBackground: 1},
+		{name: "polls over polling budget", limit: 3, pollingLimit: 2, calls: 1, polls: 5, wantRejected: 3, wantBackground: 2},
+		{name: "polling doesn't spend the api budget", limit: 1, pollingLimit: 5, calls: 1, polls: 5, wantBackground: 5},
+		{name: "zero disables the caps", calls: 10, polls: 10, wantBackground: 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			budget := &APIBudget{limit: tt.limit, pollingLimit: tt.pollingLimit, usage: make(map[string]*budgetUsage)}
+			rejected := 0
+			for i := 0; i < tt.calls; i++ {
+				if err := budget.Take("owner"); err != nil {
+					if !errors.Is(err, ErrBudgetExceeded) {
+						t.Fatalf("unexpected error: %v", err)
+					}
+					rejected++
+				}
+			}
+			for i := 0; i < tt.polls; i++ {
+				if err := budget.TakeBackground("owner"); err != nil {
+					if !errors.Is(err, ErrBudgetExceeded) {
+						t.Fatalf("unexpected error: %v", err)
+					}
+					rejected++
+				}
+			}
+			if rejected != tt.wantRejected {
+				t.Fatalf("rejected = %d, want %d", rejected, tt.wantRejected)
+			}
+			status := budget.Status("owner")
+			if status.Rejected != tt.wantRejected || status.Background != tt.wantBackground {
+				t.Fatalf("status = %+v, want %d rejected and %d background calls", status, tt.wantRejected, tt.wantBackground)
+			}
+		})
+	}
+}
+
+func TestBudgetTransportPolling(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	previous := GlobalBudget
+	GlobalBudget = &APIBudget{limit: 1, pollingLimit: 2, usage: make(map[string]*budgetUsage)}
+	defer func() { GlobalBudget = previous }()
+
+	client := &http.Client{Transport: &budgetTransport{base: http.DefaultTransport, owner: "owner"}}
+	get := func(ctx context.Context) error {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		resp, err := client.Do(req)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+
+	polling := WithBackgroundPolling(context.Background())
+	for i := 0; i < 2; i++ {
+		if err := get(polling); err != nil {
+			t.Fatalf("poll %d: unexpected error: %v", i+1, err)
+		}
+	}
+	if err := get(polling); !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("poll over the polling budget: err = %v, want budget exceeded", err)
+	}
+	if err := get(context.Background()); err != nil {
+		t.Fatalf("call after spent polling budget: unexpected error: %v", err)
+	}
+	if err := get(context.Background()); !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("call over the budget: err = %v, want budget exceeded", err)
+	}
+}
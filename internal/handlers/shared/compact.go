@@ -0,0 +1,160 @@
+package shared
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// compactGuidanceKeys are result fields with generic advice that compact output drops
+var compactGuidanceKeys = map[string]bool{
+	"note":         true,
+	"instructions": true,
+}
+
+// CompactResult renders a tool result as terse key=value lines: nested fields are joined
+// with dots, lists of objects get one line per item, and emoji, banner lines and generic
+// guidance fields are removed. Text results keep their text without emoji and banners.
+func CompactResult(result interface{}) interface{} {
+	if mcpResult, ok := result.(map[string]interface{}); ok {
+		if content, ok := mcpResult["content"].([]interface{}); ok {
+			compacted := make([]interface{}, 0, len(content))
+			for _, item := range content {
+				if textItem, ok := item.(map[string]interface{}); ok && textItem["type"] == "text" {
+					text, _ := textItem["text"].(string)
+					item = map[string]interface{}{"type": "text", "text": compactText(text)}
+				}
+				compacted = append(compacted, item)
+			}
+			out := map[string]interface{}{"content": compacted}
+			if isError, ok := mcpResult["isError"]; ok {
+				out["isError"] = isError
+			}
+			return out
+		}
+	}
+
+	// Round-trip through JSON so structs are rendered by their json tags
+	data, err := json.Marshal(result)
+	if err != nil {
+		return result
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return result
+	}
+	var lines []string
+	compactLines(&lines, "", value)
+	return TextResponse(strings.Join(lines, "\n"))
+}
+
+// compactLines appends the lines of value under prefix
+func compactLines(lines *[]string, prefix string, value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		var pairs []string
+		for _, key := range compactKeys(v) {
+			if scalar, ok := compactScalar(v[key]); ok {
+				pairs = append(pairs, key+"="+scalar)
+			}
+		}
+		if len(pairs) > 0 {
+			line := strings.Join(pairs, " ")
+			if prefix != "" {
+				line = prefix + " " + line
+			}
+			*lines = append(*lines, line)
+		}
+		for _, key := range compactKeys(v) {
+			if _, ok := compactScalar(v[key]); !ok {
+				compactLines(lines, joinCompactKey(prefix, key), v[key])
+			}
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			*lines = append(*lines, prefix+"=")
+			return
+		}
+		for i, item := range v {
+			compactLines(lines, fmt.Sprintf("%s[%d]", prefix, i), item)
+		}
+	default:
+		if text, ok := v.(string); ok && prefix == "" {
+			*lines = append(*lines, compactText(text))
+			return
+		}
+		scalar, _ := compactScalar(v)
+		*lines = append(*lines, prefix+"="+scalar)
+	}
+}
+
+// compactKeys returns the keys of a map in order, without guidance fields and empty values
+func compactKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key, value := range m {
+		if compactGuidanceKeys[key] || value == nil || value == "" {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// compactScalar renders a value that fits on a line: scalars and lists of scalars
+func compactScalar(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case nil:
+		return "", true
+	case string:
+		return compactQuote(compactText(v)), true
+	case bool:
+		return strconv.FormatBool(v), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			switch item.(type) {
+			case map[string]interface{}, []interface{}:
+				return "", false
+			}
+			scalar, _ := compactScalar(item)
+			items = append(items, scalar)
+		}
+		return strings.Join(items, ","), len(items) > 0
+	}
+	return "", false
+}
+
+// compactQuote quotes values that would break the key=value layout
+func compactQuote(value string) string {
+	if value == "" || strings.ContainsAny(value, " =,\"\n\t") {
+		return strconv.Quote(value)
+	}
+	return value
+}
+
+func joinCompactKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// compactText removes emoji and banner lines such as "=====" from text. Indentation is
+// kept, so YAML and code in text survive.
+func compactText(text string) string {
+	lines := strings.Split(stripEmoji(text), "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if len(trimmed) >= 5 && strings.Trim(trimmed, "=-*#~_ ") == "" {
+			continue
+		}
+		kept = append(kept, strings.TrimRight(line, " "))
+	}
+	return strings.TrimSpace(strings.Join(kept, "\n"))
+}
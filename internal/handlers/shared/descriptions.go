@@ -0,0 +1,40 @@
+package shared
+
+import (
+	"os"
+	"strings"
+)
+
+// shortDescriptionClients are clients whose tool list counts against a tight context or
+// tool budget; they get the short tool descriptions unless MCP_TOOL_DESC says otherwise
+var shortDescriptionClients = []string{"cursor", "windsurf"}
+
+// ShortDescriptions reports whether tools/list should send short tool descriptions to the
+// named client. MCP_TOOL_DESC=short or long overrides the choice for every client.
+func ShortDescriptions(clientName string) bool {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv("MCP_TOOL_DESC"))) {
+	case "short":
+		return true
+	case "long":
+		return false
+	}
+	name := strings.ToLower(clientName)
+	for _, client := range shortDescriptionClients {
+		if strings.Contains(name, client) {
+			return true
+		}
+	}
+	return false
+}
+
+// DescriptionFor returns the short or the long description of the tool. The short one is
+// the first paragraph of the long one, usually the sentence saying what the tool does.
+func (t *ToolDefinition) DescriptionFor(short bool) string {
+	if !short {
+		return t.Description
+	}
+	if paragraph, _, found := strings.Cut(t.Description, "\n\n"); found {
+		return strings.TrimSpace(paragraph)
+	}
+	return t.Description
+}
@@ -0,0 +1,158 @@
+package shared
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/zeropsio/zerops-go/apiError"
+)
+
+// Error kinds returned by tool handlers. Use errors.Is to check the kind of
+// an error returned from a handler.
+var (
+	ErrNotFound        = errors.New("not found")
+	ErrInvalidArgument = errors.New("invalid argument")
+	ErrAPIUnavailable  = errors.New("api unavailable")
+	ErrForbidden       = errors.New("forbidden")
+	ErrConflict        = errors.New("conflict")
+	ErrBudgetExceeded  = errors.New("budget exceeded")
+)
+
+// Machine-readable error codes exposed to MCP clients
+const (
+	CodeNotFound        = "NOT_FOUND"
+	CodeInvalidArgument = "INVALID_ARGUMENT"
+	CodeAPIUnavailable  = "API_UNAVAILABLE"
+	CodeForbidden       = "FORBIDDEN"
+	CodeConflict        = "CONFLICT"
+	CodeBudgetExceeded  = "BUDGET_EXCEEDED"
+	CodeInternal        = "INTERNAL"
+)
+
+// ToolError is an error returned by a tool handler with a known kind
+type ToolError struct {
+	Kind    error
+	Message string
+	Err     error
+}
+
+func (e *ToolError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+// Is reports whether target is the kind of this error
+func (e *ToolError) Is(target error) bool {
+	return e.Kind == target
+}
+
+func (e *ToolError) Unwrap() error {
+	return e.Err
+}
+
+// NewToolError creates an error of the given kind with a formatted message
+func NewToolError(kind error, format string, args ...interface{}) error {
+	return &ToolError{
+		Kind:    kind,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// InvalidArgument creates an ErrInvalidArgument error
+func InvalidArgument(format string, args ...interface{}) error {
+	return NewToolError(ErrInvalidArgument, format, args...)
+}
+
+// NotFound creates an ErrNotFound error
+func NotFound(format string, args ...interface{}) error {
+	return NewToolError(ErrNotFound, format, args...)
+}
+
+// Conflict creates an ErrConflict error
+func Conflict(format string, args ...interface{}) error {
+	return NewToolError(ErrConflict, format, args...)
+}
+
+// ErrNoClient is returned when a tool needs the Zerops API but no API key was provided
+var ErrNoClient = NewToolError(ErrForbidden, "No API key provided")
+
+// WrapAPIError classifies an error returned by the Zerops SDK and prefixes it with message
+func WrapAPIError(err error, message string) error {
+	if err == nil {
+		return nil
+	}
+
+	var toolErr *ToolError
+	if errors.As(err, &toolErr) {
+		return &ToolError{Kind: toolErr.Kind, Message: message, Err: err}
+	}
+
+	return &ToolError{Kind: classifyAPIError(err), Message: message, Err: err}
+}
+
+// classifyAPIError maps Zerops API responses to an error kind
+func classifyAPIError(err error) error {
+	var apiErr apiError.Error
+	if !errors.As(err, &apiErr) {
+		// Transport level failure (DNS, timeout, connection reset, ...)
+		return ErrAPIUnavailable
+	}
+
+	switch status := apiErr.GetHttpStatusCode(); {
+	case status == http.StatusNotFound:
+		return ErrNotFound
+	case status == http.StatusUnauthorized, status == http.StatusForbidden:
+		return ErrForbidden
+	case status == http.StatusConflict:
+		return ErrConflict
+	case status == http.StatusBadRequest, status == http.StatusUnprocessableEntity:
+		return ErrInvalidArgument
+	case status >= http.StatusInternalServerError, status == http.StatusTooManyRequests:
+		return ErrAPIUnavailable
+	default:
+		return ErrInvalidArgument
+	}
+}
+
+// ErrorCode returns the machine-readable code for an error
+func ErrorCode(err error) string {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return CodeNotFound
+	case errors.Is(err, ErrInvalidArgument):
+		return CodeInvalidArgument
+	case errors.Is(err, ErrAPIUnavailable):
+		return CodeAPIUnavailable
+	case errors.Is(err, ErrForbidden):
+		return CodeForbidden
+	case errors.Is(err, ErrConflict):
+		return CodeConflict
+	case errors.Is(err, ErrBudgetExceeded):
+		return CodeBudgetExceeded
+	default:
+		return CodeInternal
+	}
+}
+
+// APIErrorDetails returns the structured Zerops API error in err's chain: its code,
+// message, HTTP status and meta (e.g. the offending parameter and allowed limits).
+// It returns nil when err did not come from an API response.
+func APIErrorDetails(err error) map[string]interface{} {
+	var apiErr apiError.Error
+	if !errors.As(err, &apiErr) {
+		return nil
+	}
+
+	details := map[string]interface{}{
+		"code":        apiErr.GetErrorCode(),
+		"message":     apiErr.GetMessage(),
+		"http_status": apiErr.GetHttpStatusCode(),
+	}
+	if meta := apiErr.GetMeta(); meta != nil {
+		details["meta"] = meta
+	}
+	return details
+}
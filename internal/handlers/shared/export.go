@@ -0,0 +1,213 @@
+package shared
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// exportQueueSize is how many events wait for shipping; newer events are dropped when it is full
+	exportQueueSize = 1000
+	// exportBatchSize is the most events sent in one bulk request
+	exportBatchSize = 100
+	// exportFlushInterval is how often queued events are shipped
+	exportFlushInterval = 5 * time.Second
+	// defaultExportIndex is used when ZEROPS_MCP_EXPORT_INDEX is not set
+	defaultExportIndex = "zerops-mcp"
+)
+
+// EventExporter ships events to an Elasticsearch or OpenSearch index through the _bulk API.
+// Events are queued and sent in the background so tool calls never wait for the cluster.
+type EventExporter struct {
+	url      string
+	index    string
+	apiKey   string
+	username string
+	password string
+	client   *http.Client
+
+	queue    chan map[string]interface{}
+	stopping chan struct{}
+	done     chan struct{}
+	stop     sync.Once
+	dropped  atomic.Int64
+	failed   atomic.Int64
+	sent     atomic.Int64
+}
+
+// GlobalExporter is configured by ZEROPS_MCP_EXPORT_URL; nil when exporting is disabled
+var GlobalExporter = newEventExporterFromEnv()
+
+// newEventExporterFromEnv reads ZEROPS_MCP_EXPORT_URL, ZEROPS_MCP_EXPORT_INDEX and either
+// ZEROPS_MCP_EXPORT_API_KEY or ZEROPS_MCP_EXPORT_USERNAME / ZEROPS_MCP_EXPORT_PASSWORD
+func newEventExporterFromEnv() *EventExporter {
+	url := strings.TrimRight(os.Getenv("ZEROPS_MCP_EXPORT_URL"), "/")
+	if url == "" {
+		return nil
+	}
+	index := os.Getenv("ZEROPS_MCP_EXPORT_INDEX")
+	if index == "" {
+		index = defaultExportIndex
+	}
+
+	exporter := &EventExporter{
+		url:      url,
+		index:    index,
+		apiKey:   os.Getenv("ZEROPS_MCP_EXPORT_API_KEY"),
+		username: os.Getenv("ZEROPS_MCP_EXPORT_USERNAME"),
+		password: os.Getenv("ZEROPS_MCP_EXPORT_PASSWORD"),
+		// Bind the transport now so --record, which wraps http.DefaultTransport, skips export traffic
+		client:   &http.Client{Timeout: 10 * time.Second, Transport: http.DefaultTransport},
+		queue:    make(chan map[string]interface{}, exportQueueSize),
+		stopping: make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go exporter.run()
+	return exporter
+}
+
+// ExportEvent queues an event of the given kind; a no-op when exporting is disabled
+func ExportEvent(kind string, fields map[string]interface{}) {
+	if GlobalExporter == nil {
+		return
+	}
+	event := make(map[string]interface{}, len(fields)+2)
+	for key, value := range fields {
+		event[key] = value
+	}
+	event["@timestamp"] = time.Now().UTC().Format(time.RFC3339Nano)
+	event["event_kind"] = kind
+
+	select {
+	case GlobalExporter.queue <- event:
+	default:
+		GlobalExporter.dropped.Add(1)
+	}
+}
+
+// Close ships the queued events, waiting at most timeout. Events queued afterwards are not sent.
+func (e *EventExporter) Close(timeout time.Duration) {
+	e.stop.Do(func() { close(e.stopping) })
+	select {
+	case <-e.done:
+	case <-time.After(timeout):
+	}
+}
+
+// Stats reports how many events were sent, failed to send or were dropped because the queue was full
+func (e *EventExporter) Stats() map[string]int64 {
+	return map[string]int64{
+		"sent":    e.sent.Load(),
+		"failed":  e.failed.Load(),
+		"dropped": e.dropped.Load(),
+	}
+}
+
+func (e *EventExporter) run() {
+	defer close(e.done)
+	ticker := time.NewTicker(exportFlushInterval)
+	defer ticker.Stop()
+
+	var batch []map[string]interface{}
+	for {
+		select {
+		case event := <-e.queue:
+			batch = append(batch, event)
+			if len(batch) >= exportBatchSize {
+				e.ship(batch)
+				batch = nil
+			}
+		case <-ticker.C:
+			e.ship(batch)
+			batch = nil
+		case <-e.stopping:
+			// Drain what is queued, then stop
+			for {
+				select {
+				case event := <-e.queue:
+					batch = append(batch, event)
+					if len(batch) >= exportBatchSize {
+						e.ship(batch)
+						batch = nil
+					}
+				default:
+					e.ship(batch)
+					return
+				}
+			}
+		}
+	}
+}
+
+// ship sends a batch with one bulk request; failures are counted and reported on stderr
+func (e *EventExporter) ship(batch []map[string]interface{}) {
+	if len(batch) == 0 {
+		return
+	}
+
+	var payload bytes.Buffer
+	action, _ := json.Marshal(map[string]interface{}{"index": map[string]string{"_index": e.index}})
+	for _, event := range batch {
+		data, err := json.Marshal(event)
+		if err != nil {
+			e.failed.Add(1)
+			continue
+		}
+		payload.Write(action)
+		payload.WriteByte('\n')
+		payload.Write(data)
+		payload.WriteByte('\n')
+	}
+
+	if err := e.post(payload.Bytes()); err != nil {
+		e.failed.Add(int64(len(batch)))
+		fmt.Fprintf(os.Stderr, "Event export failed: %v\n", err)
+		return
+	}
+	e.sent.Add(int64(len(batch)))
+}
+
+func (e *EventExporter) post(payload []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), e.client.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url+"/_bulk", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	switch {
+	case e.apiKey != "":
+		req.Header.Set("Authorization", "ApiKey "+e.apiKey)
+	case e.username != "":
+		req.SetBasicAuth(e.username, e.password)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	// The bulk API answers 200 even when single documents were rejected
+	var result struct {
+		Errors bool `json:"errors"`
+	}
+	if json.Unmarshal(body, &result) == nil && result.Errors {
+		return fmt.Errorf("the cluster rejected some documents")
+	}
+	return nil
+}
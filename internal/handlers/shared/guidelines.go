@@ -0,0 +1,37 @@
+package shared
+
+import (
+	"os"
+	"strings"
+)
+
+// guidelinesEnv points to an operator-provided markdown file with organization conventions
+const guidelinesEnv = "ORG_GUIDELINES_PATH"
+
+// GuidelinesPath returns the configured guidelines file, empty when none is configured
+func GuidelinesPath() string {
+	return os.Getenv(guidelinesEnv)
+}
+
+// OrgGuidelines reads the guidelines file; it is read on every call so edits apply without a restart
+func OrgGuidelines() (string, error) {
+	path := GuidelinesPath()
+	if path == "" {
+		return "", nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// ServerInstructions returns the instructions sent to clients on initialize.
+// They consist of the organization guidelines, if configured.
+func ServerInstructions() string {
+	guidelines, err := OrgGuidelines()
+	if err != nil || guidelines == "" {
+		return ""
+	}
+	return "# Organization guidelines\n\nFollow these conventions when managing Zerops infrastructure for this organization. Call company_guidelines to re-read them.\n\n" + guidelines
+}
@@ -0,0 +1,117 @@
+package shared
+
+import (
+	"context"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zeropsio/zerops-go/dto/output"
+	"github.com/zeropsio/zerops-go/sdk"
+)
+
+// defaultIdentityTTL is how long a resolved identity is reused; ZEROPS_MCP_IDENTITY_TTL overrides it
+const defaultIdentityTTL = 10 * time.Minute
+
+// Identity is the user and organizations behind an API key
+type Identity struct {
+	UserID        string        `json:"user_id"`
+	Email         string        `json:"email"`
+	FullName      string        `json:"full_name"`
+	Organizations []IdentityOrg `json:"organizations"`
+	ResolvedAt    time.Time     `json:"resolved_at"`
+}
+
+// IdentityOrg is an organization the key can access and its role there
+type IdentityOrg struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Role   string `json:"role"`
+	Status string `json:"status"`
+}
+
+// OrgNames returns the organization names joined by commas
+func (i *Identity) OrgNames() string {
+	names := make([]string, 0, len(i.Organizations))
+	for _, org := range i.Organizations {
+		names = append(names, org.Name)
+	}
+	return strings.Join(names, ",")
+}
+
+// identityCache keeps resolved identities per API key owner (see OwnerID)
+var identityCache = struct {
+	mu      sync.Mutex
+	entries map[string]*Identity
+}{entries: make(map[string]*Identity)}
+
+// identityTTL reads ZEROPS_MCP_IDENTITY_TTL (a Go duration such as "5m")
+func identityTTL() time.Duration {
+	if value := os.Getenv("ZEROPS_MCP_IDENTITY_TTL"); value != "" {
+		if ttl, err := time.ParseDuration(value); err == nil && ttl > 0 {
+			return ttl
+		}
+	}
+	return defaultIdentityTTL
+}
+
+// StoreIdentity caches the identity from a GetUserInfo response for apiKey.
+// The stdio session key is stored under the empty key.
+func StoreIdentity(apiKey string, info output.UserAuthorize) *Identity {
+	identity := &Identity{
+		UserID:     string(info.Id),
+		Email:      info.Email.Native(),
+		FullName:   info.FullName.Native(),
+		ResolvedAt: time.Now(),
+	}
+	for _, clientUser := range info.ClientUserList {
+		identity.Organizations = append(identity.Organizations, IdentityOrg{
+			ID:     string(clientUser.ClientId),
+			Name:   clientUser.Client.AccountName.Native(),
+			Role:   string(clientUser.RoleCode),
+			Status: string(clientUser.Status),
+		})
+	}
+
+	identityCache.mu.Lock()
+	identityCache.entries[OwnerID(apiKey)] = identity
+	identityCache.mu.Unlock()
+	return identity
+}
+
+// CachedIdentity returns the identity of apiKey without calling the API, nil when unknown or expired
+func CachedIdentity(apiKey string) *Identity {
+	identityCache.mu.Lock()
+	defer identityCache.mu.Unlock()
+
+	identity, ok := identityCache.entries[OwnerID(apiKey)]
+	if !ok || time.Since(identity.ResolvedAt) > identityTTL() {
+		return nil
+	}
+	return identity
+}
+
+// ResolveIdentity returns the cached identity of apiKey, reading it with client on a miss
+func ResolveIdentity(ctx context.Context, client *sdk.Handler, apiKey string) (*Identity, error) {
+	if identity := CachedIdentity(apiKey); identity != nil {
+		return identity, nil
+	}
+
+	resp, err := client.GetUserInfo(ctx)
+	if err != nil {
+		return nil, WrapAPIError(err, "Failed to get user info")
+	}
+	info, err := resp.Output()
+	if err != nil {
+		return nil, WrapAPIError(err, "Failed to get user info")
+	}
+	return StoreIdentity(apiKey, info), nil
+}
+
+// ForgetIdentity drops the cached identity of apiKey, e.g. after the session key changed
+func ForgetIdentity(apiKey string) {
+	identityCache.mu.Lock()
+	delete(identityCache.entries, OwnerID(apiKey))
+	identityCache.mu.Unlock()
+}
@@ -0,0 +1,61 @@
+package shared
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// ServerVersion is reported in serverInfo and in the X-Invoked-By header
+const ServerVersion = "1.0.0"
+
+// invokedByHeader marks API requests of mutating tool calls, so changes made through the
+// server can be told apart from GUI actions in the Zerops audit trail
+const invokedByHeader = "X-Invoked-By"
+
+// invokedByKey is the context key of the mutating tool being called
+const invokedByKey = "invokedByTool"
+
+// invokedByEnabled is false when ZEROPS_MCP_INVOKED_BY_HEADER=false, for API proxies that
+// reject unknown headers
+var invokedByEnabled = os.Getenv("ZEROPS_MCP_INVOKED_BY_HEADER") != "false"
+
+// withInvokedBy marks ctx as the context of a call to the mutating tool name
+func withInvokedBy(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, invokedByKey, name)
+}
+
+// invokedByValue returns the header value for a request made in ctx; empty outside
+// mutating tool calls
+func invokedByValue(ctx context.Context) string {
+	tool, _ := ctx.Value(invokedByKey).(string)
+	if tool == "" {
+		return ""
+	}
+	value := "zerops-mcp/" + ServerVersion
+	if clientName, _ := ctx.Value("clientName").(string); clientName != "" {
+		value += "; client=" + clientName
+	}
+	return fmt.Sprintf("%s; tool=%s", value, tool)
+}
+
+// invokedByTransport adds X-Invoked-By to the writes of mutating tool calls. Reads are left
+// alone; they don't show up in the audit trail.
+type invokedByTransport struct {
+	base http.RoundTripper
+}
+
+func (t *invokedByTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodGet || !invokedByEnabled {
+		return t.base.RoundTrip(req)
+	}
+	value := invokedByValue(req.Context())
+	if value == "" {
+		return t.base.RoundTrip(req)
+	}
+	// A RoundTripper must not modify the caller's request
+	req = req.Clone(req.Context())
+	req.Header.Set(invokedByHeader, value)
+	return t.base.RoundTrip(req)
+}
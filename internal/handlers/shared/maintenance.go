@@ -0,0 +1,165 @@
+package shared
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Zerops answers 503 with a maintenance error while the platform is being updated, usually with
+// a Retry-After header. Such requests were not processed, so they are retried after the announced
+// delay instead of failing the tool call. Other 503s, Retry-After or not, are not retried.
+const (
+	// maintenanceRetries is how many times a request is retried during maintenance
+	maintenanceRetries = 3
+	// defaultMaintenanceDelay is the delay when the response has no usable Retry-After
+	defaultMaintenanceDelay = 5 * time.Second
+	// maxMaintenanceDelay caps a single delay, so a long announced window fails fast instead
+	maxMaintenanceDelay = 30 * time.Second
+	// maintenanceBodyLimit is how much of a 503 body is read to recognize maintenance
+	maintenanceBodyLimit = 64 << 10
+)
+
+// maintenanceNotes collects the retries of one tool call
+type maintenanceNotes struct {
+	mu      sync.Mutex
+	retries int
+	waited  time.Duration
+}
+
+// maintenanceKey is the context key of the call's maintenanceNotes
+const maintenanceKey = "maintenanceNotes"
+
+func withMaintenanceNotes(ctx context.Context) (context.Context, *maintenanceNotes) {
+	notes := &maintenanceNotes{}
+	return context.WithValue(ctx, maintenanceKey, notes), notes
+}
+
+func (n *maintenanceNotes) add(delay time.Duration) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.retries++
+	n.waited += delay
+}
+
+// note describes the retries for the tool result; empty when there were none
+func (n *maintenanceNotes) note() string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.retries == 0 {
+		return ""
+	}
+	return fmt.Sprintf("Zerops platform maintenance in progress, retried after %s (%d retries)", n.waited.Round(time.Second), n.retries)
+}
+
+// annotate adds the maintenance note to a successful result, or turns the error of a call
+// that gave up during maintenance into one that says so
+func (n *maintenanceNotes) annotate(result interface{}, err error) (interface{}, error) {
+	note := n.note()
+	if note == "" {
+		return result, err
+	}
+	if err != nil {
+		return nil, &ToolError{
+			Kind:    ErrAPIUnavailable,
+			Message: note + "; the API is still unavailable, try again in a few minutes",
+			Err:     err,
+		}
+	}
+	if object, ok := result.(map[string]interface{}); ok {
+		object["maintenance_note"] = note
+	}
+	return result, nil
+}
+
+// maintenanceTransport retries requests that Zerops rejected because of maintenance
+type maintenanceTransport struct {
+	base http.RoundTripper
+}
+
+func (t *maintenanceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if err != nil || resp.StatusCode != http.StatusServiceUnavailable || attempt == maintenanceRetries {
+			return resp, err
+		}
+
+		delay, maintenance := maintenanceDelay(resp)
+		if !maintenance || delay > maxMaintenanceDelay || (req.Body != nil && req.GetBody == nil) {
+			return resp, nil
+		}
+		resp.Body.Close()
+
+		ctx := req.Context()
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return nil, fmt.Errorf("zerops platform maintenance in progress, retry after %s exceeds the call deadline", delay)
+		}
+		if notes, ok := ctx.Value(maintenanceKey).(*maintenanceNotes); ok {
+			notes.add(delay)
+		}
+		Log(ctx, "warning", "zerops-api", fmt.Sprintf("Zerops platform maintenance in progress, retrying %s %s in %s", req.Method, req.URL.Path, delay))
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(ctx)
+			req.Body = body
+		}
+	}
+}
+
+// maintenanceDelay reports whether a 503 response is a maintenance response and how long
+// to wait before retrying. The body is restored so an unretried response reads as received.
+func maintenanceDelay(resp *http.Response) (time.Duration, bool) {
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, maintenanceBodyLimit))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(data), resp.Body), resp.Body}
+
+	if !isMaintenanceBody(data) {
+		return 0, false
+	}
+	if value := resp.Header.Get("Retry-After"); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+			return max(time.Duration(seconds)*time.Second, time.Second), true
+		}
+		if at, err := http.ParseTime(value); err == nil {
+			return max(time.Until(at).Round(time.Second), time.Second), true
+		}
+	}
+	return defaultMaintenanceDelay, true
+}
+
+// isMaintenanceBody reports whether a 503 body is the API's maintenance error: an error whose
+// code or message names maintenance, or a plain page saying so when the body isn't an API error
+func isMaintenanceBody(data []byte) bool {
+	var body struct {
+		Error *struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(data, &body); err == nil && body.Error != nil {
+		return strings.Contains(strings.ToLower(body.Error.Code), "maintenance") ||
+			strings.Contains(strings.ToLower(body.Error.Message), "maintenance")
+	}
+	return strings.Contains(strings.ToLower(string(data)), "maintenance")
+}
@@ -0,0 +1,132 @@
+package shared
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIsMaintenanceBody(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want bool
+	}{
+		{name: "maintenance error code", body: `{"error":{"code":"platformMaintenance","message":"Try later"}}`, want: true},
+		{name: "maintenance in message", body: `{"error":{"code":"serviceUnavailable","message":"Scheduled maintenance"}}`, want: true},
+		{name: "other api error", body: `{"error":{"code":"serviceUnavailable","message":"Backend overloaded"}}`},
+		{name: "api error mentioning maintenance elsewhere", body: `{"error":{"code":"serviceUnavailable","message":"Overloaded"},"hint":"maintenance"}`},
+		{name: "plain maintenance page", body: "<h1>Down for maintenance</h1>", want: true},
+		{name: "plain proxy error", body: "<h1>503 Service Unavailable</h1>"},
+		{name: "empty body"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isMaintenanceBody([]byte(tt.body)); got != tt.want {
+				t.Fatalf("isMaintenanceBody(%q) = %v, want %v", tt.body, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaintenanceDelay(t *testing.T) {
+	const maintenance = `{"error":{"code":"platformMaintenance","message":"Try later"}}`
+	tests := []struct {
+		name            string
+		body            string
+		retryAfter      string
+		want            time.Duration
+		wantMaintenance bool
+	}{
+		{name: "seconds", body: maintenance, retryAfter: "10", want: 10 * time.Second, wantMaintenance: true},
+		{name: "zero seconds waits a second", body: maintenance, retryAfter: "0", want: time.Second, wantMaintenance: true},
+		{name: "no header", body: maintenance, want: defaultMaintenanceDelay, wantMaintenance: true},
+		{name: "unusable header", body: maintenance, retryAfter: "soon", want: defaultMaintenanceDelay, wantMaintenance: true},
+		{name: "retry after without maintenance", body: "overloaded", retryAfter: "10"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{
+				StatusCode: http.StatusServiceUnavailable,
+				Header:     http.Header{},
+				Body:       io.NopCloser(strings.NewReader(tt.body)),
+			}
+			if tt.retryAfter != "" {
+				resp.Header.Set("Retry-After", tt.retryAfter)
+			}
+			delay, maintenance := maintenanceDelay(resp)
+			if maintenance != tt.wantMaintenance || delay != tt.want {
+				t.Fatalf("delay = %s, %v, want %s, %v", delay, maintenance, tt.want, tt.wantMaintenance)
+			}
+			// The body must still read as received
+			if data, _ := io.ReadAll(resp.Body); string(data) != tt.body {
+				t.Fatalf("body = %q after the check, want %q", data, tt.body)
+			}
+		})
+	}
+}
+
+func TestMaintenanceTransport(t *testing.T) {
+	const maintenance = `{"error":{"code":"platformMaintenance","message":"Try later"}}`
+	tests := []struct {
+		name         string
+		retryAfter   string
+		body         string
+		failures     int
+		wantStatus   int
+		wantRequests int
+		wantRetries  int
+	}{
+		{name: "retried after maintenance", retryAfter: "1", body: maintenance, failures: 1, wantStatus: http.StatusOK, wantRequests: 2, wantRetries: 1},
+		{name: "other 503 is not retried", retryAfter: "1", body: `{"error":{"code":"serviceUnavailable","message":"Overloaded"}}`, failures: 1, wantStatus: http.StatusServiceUnavailable, wantRequests: 1},
+		{name: "long window is not waited for", retryAfter: "120", body: maintenance, failures: 1, wantStatus: http.StatusServiceUnavailable, wantRequests: 1},
+		{name: "success is passed through", wantStatus: http.StatusOK, wantRequests: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if body, _ := io.ReadAll(r.Body); string(body) != `{"name":"app"}` {
+					t.Errorf("request %d body = %q", requests, body)
+				}
+				if requests <= tt.failures {
+					w.Header().Set("Retry-After", tt.retryAfter)
+					w.WriteHeader(http.StatusServiceUnavailable)
+					io.WriteString(w, tt.body)
+					return
+				}
+				io.WriteString(w, `{}`)
+			}))
+			defer server.Close()
+
+			ctx, notes := withMaintenanceNotes(context.Background())
+			req, _ := http.NewRequestWithContext(ctx, http.MethodPost, server.URL, strings.NewReader(`{"name":"app"}`))
+			client := &http.Client{Transport: &maintenanceTransport{base: http.DefaultTransport}}
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusServiceUnavailable {
+				if body, _ := io.ReadAll(resp.Body); string(body) != tt.body {
+					t.Fatalf("body = %q, want the 503 body %q", body, tt.body)
+				}
+			}
+			if requests != tt.wantRequests {
+				t.Fatalf("requests = %d, want %d", requests, tt.wantRequests)
+			}
+			if notes.retries != tt.wantRetries {
+				t.Fatalf("retries = %d, want %d", notes.retries, tt.wantRetries)
+			}
+		})
+	}
+}
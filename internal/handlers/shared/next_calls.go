@@ -0,0 +1,63 @@
+package shared
+
+// NextCall is a follow-up tool call suggested by a tool result. Its arguments are prefilled
+// from the result, so clients can offer it as a one-click follow-up and agents can chain it
+// without parsing the message.
+type NextCall struct {
+	Tool      string                 `json:"tool"`
+	Arguments map[string]interface{} `json:"arguments"`
+	Purpose   string                 `json:"purpose"`
+}
+
+// nextCallsKey is the result field holding the suggested calls
+const nextCallsKey = "suggested_next_calls"
+
+// Suggestions is embedded in tool results that suggest follow-up calls
+type Suggestions struct {
+	NextCalls []NextCall `json:"suggested_next_calls,omitempty"`
+}
+
+// SuggestNext appends calls to the suggested_next_calls of the result, in the order given
+func (s *Suggestions) SuggestNext(calls ...NextCall) {
+	for _, call := range calls {
+		if call.Arguments == nil {
+			call.Arguments = map[string]interface{}{}
+		}
+		s.NextCalls = append(s.NextCalls, call)
+	}
+}
+
+// WaitForProcess suggests waiting for a process a tool started
+func WaitForProcess(processID, purpose string) NextCall {
+	return NextCall{
+		Tool:      "wait_for_process",
+		Arguments: map[string]interface{}{"process_id": processID},
+		Purpose:   purpose,
+	}
+}
+
+// checkNextCalls drops suggestions of tools that are not registered, so a result never
+// points at a tool the client can't call. result is the structured result of a call.
+func (r *ToolRegistry) checkNextCalls(result interface{}) {
+	object, ok := result.(map[string]interface{})
+	if !ok {
+		return
+	}
+	calls, ok := object[nextCallsKey].([]interface{})
+	if !ok {
+		return
+	}
+	kept := make([]interface{}, 0, len(calls))
+	for _, call := range calls {
+		suggestion, _ := call.(map[string]interface{})
+		tool, _ := suggestion["tool"].(string)
+		if _, registered := r.Get(tool); registered {
+			kept = append(kept, call)
+		}
+	}
+	if len(kept) == 0 {
+		delete(object, nextCallsKey)
+		return
+	}
+	object[nextCallsKey] = kept
+}
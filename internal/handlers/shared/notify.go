@@ -0,0 +1,65 @@
+package shared
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// Notifier sends a server-initiated notification to the connected MCP client
+type Notifier func(ctx context.Context, level, logger string, data interface{}) error
+
+// notifierKey is the context key under which transports store the session Notifier
+const notifierKey = "mcpNotifier"
+
+// WithNotifier returns a context carrying the session notifier
+func WithNotifier(ctx context.Context, notifier Notifier) context.Context {
+	return context.WithValue(ctx, notifierKey, notifier)
+}
+
+// NotifierFromContext returns the session notifier, nil when the transport has no push channel
+func NotifierFromContext(ctx context.Context) Notifier {
+	notifier, _ := ctx.Value(notifierKey).(Notifier)
+	return notifier
+}
+
+// ProgressReporter sends a progress notification for the tool call being executed
+type ProgressReporter func(ctx context.Context, progress, total float64, message string) error
+
+// progressKey is the context key under which transports store the call's ProgressReporter
+const progressKey = "mcpProgress"
+
+// WithProgress returns a context carrying the progress reporter of the current call
+func WithProgress(ctx context.Context, reporter ProgressReporter) context.Context {
+	return context.WithValue(ctx, progressKey, reporter)
+}
+
+// ReportProgress reports progress of the current call; a no-op when the client did not ask for progress
+func ReportProgress(ctx context.Context, progress, total float64, message string) {
+	if reporter, ok := ctx.Value(progressKey).(ProgressReporter); ok {
+		_ = reporter(ctx, progress, total, message)
+	}
+}
+
+// loggerKey is the context key under which transports store the logger of the current call
+const loggerKey = "mcpLogger"
+
+// WithLogger returns a context carrying a logger that delivers messages to the client of the current call
+func WithLogger(ctx context.Context, logger Notifier) context.Context {
+	return context.WithValue(ctx, loggerKey, logger)
+}
+
+// Log sends an MCP log message (notifications/message) to the client of the current call.
+// The client's negotiated level filters messages; without a channel to the client,
+// warnings and worse go to stderr.
+func Log(ctx context.Context, level, logger string, data interface{}) {
+	if send, ok := ctx.Value(loggerKey).(Notifier); ok {
+		if err := send(ctx, level, logger, data); err == nil {
+			return
+		}
+	}
+	switch level {
+	case "warning", "error", "critical", "alert", "emergency":
+		fmt.Fprintf(os.Stderr, "[%s] %s: %v\n", level, logger, data)
+	}
+}
@@ -0,0 +1,39 @@
+package shared
+
+import (
+	"context"
+	"os"
+)
+
+// OutputOptions are a session's choices for rendering tool results
+type OutputOptions struct {
+	// Compact renders results as key=value lines, see CompactResult
+	Compact bool `json:"compact"`
+	// ASCII replaces or escapes every non-ASCII character, see SanitizeASCII
+	ASCII bool `json:"ascii"`
+}
+
+// defaultOutputOptions is read from ZEROPS_MCP_COMPACT and ZEROPS_MCP_ASCII
+func defaultOutputOptions() OutputOptions {
+	return OutputOptions{
+		Compact: os.Getenv("ZEROPS_MCP_COMPACT") == "true",
+		ASCII:   os.Getenv("ZEROPS_MCP_ASCII") == "true",
+	}
+}
+
+// SessionOutput returns the output options of the caller's session, see Preferences
+func SessionOutput(ctx context.Context) OutputOptions {
+	return SessionPreferences(ctx).OutputOptions
+}
+
+// renderResult applies the session's output options to a successful tool result
+func renderResult(ctx context.Context, result interface{}) interface{} {
+	options := SessionOutput(ctx)
+	if options.Compact {
+		result = CompactResult(result)
+	}
+	if options.ASCII {
+		result = SanitizeASCII(result)
+	}
+	return result
+}
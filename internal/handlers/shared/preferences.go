@@ -0,0 +1,124 @@
+package shared
+
+import (
+	"context"
+	"os"
+	"sync"
+)
+
+// Preferences are a session's defaults chosen with set_preferences, or with set_output_format
+// for the output settings. Both tools update this one store.
+type Preferences struct {
+	// Dates is "iso" (RFC3339) or "locale" (the layout of Language)
+	Dates string `json:"dates"`
+	// Sizes is "decimal" (GB) or "binary" (GiB)
+	Sizes string `json:"sizes"`
+	OutputOptions
+	// Timezone is the IANA timezone of returned timestamps when a call doesn't pass one
+	Timezone string `json:"timezone,omitempty"`
+	// Language is a BCP 47 tag; it picks the layout of locale dates
+	Language string `json:"language,omitempty"`
+	// ConfirmDestructive makes destructive tools fail unless called with confirm: true
+	ConfirmDestructive bool `json:"confirm_destructive"`
+}
+
+// preferenceSessions keeps the preferences chosen per session, see preferenceKey
+var preferenceSessions = struct {
+	mu        sync.Mutex
+	bySession map[string]Preferences
+}{bySession: make(map[string]Preferences)}
+
+// preferenceKey picks whose preferences apply to the call. confirm_destructive is a safety
+// setting, so one client's choice must not reach other clients sharing the API key: the MCP
+// session is used, and the key owner only for calls without a session.
+func preferenceKey(ctx context.Context) string {
+	if session := SessionID(ctx); session != "" {
+		return "session:" + session
+	}
+	apiKey, _ := ctx.Value("apiKey").(string)
+	return "owner:" + OwnerID(apiKey)
+}
+
+// defaultPreferences is read from ZEROPS_MCP_DATE_FORMAT, ZEROPS_MCP_SIZE_UNITS,
+// ZEROPS_MCP_COMPACT, ZEROPS_MCP_ASCII, ZEROPS_MCP_TIMEZONE, ZEROPS_MCP_LANGUAGE and
+// ZEROPS_MCP_CONFIRM_DESTRUCTIVE
+func defaultPreferences() Preferences {
+	preferences := Preferences{
+		Dates:              "iso",
+		Sizes:              "decimal",
+		OutputOptions:      defaultOutputOptions(),
+		Timezone:           os.Getenv("ZEROPS_MCP_TIMEZONE"),
+		Language:           os.Getenv("ZEROPS_MCP_LANGUAGE"),
+		ConfirmDestructive: os.Getenv("ZEROPS_MCP_CONFIRM_DESTRUCTIVE") == "true",
+	}
+	if os.Getenv("ZEROPS_MCP_DATE_FORMAT") == "locale" {
+		preferences.Dates = "locale"
+	}
+	if os.Getenv("ZEROPS_MCP_SIZE_UNITS") == "binary" {
+		preferences.Sizes = "binary"
+	}
+	return preferences
+}
+
+// SessionPreferences returns the preferences of the caller's session
+func SessionPreferences(ctx context.Context) Preferences {
+	preferenceSessions.mu.Lock()
+	defer preferenceSessions.mu.Unlock()
+	if preferences, ok := preferenceSessions.bySession[preferenceKey(ctx)]; ok {
+		return preferences
+	}
+	return defaultPreferences()
+}
+
+// UpdateSessionPreferences applies update to the preferences of the caller's session and stores
+// them. The session is locked meanwhile, so concurrent updates don't get lost.
+func UpdateSessionPreferences(ctx context.Context, update func(*Preferences)) Preferences {
+	key := preferenceKey(ctx)
+	preferenceSessions.mu.Lock()
+	preferences, ok := preferenceSessions.bySession[key]
+	if !ok {
+		preferences = defaultPreferences()
+	}
+	update(&preferences)
+	preferenceSessions.bySession[key] = preferences
+	preferenceSessions.mu.Unlock()
+	SessionStateChanged()
+	return preferences
+}
+
+// CheckConfirmation rejects a destructive call without confirm: true when the session asked
+// to confirm destructive changes. Tools that always require confirm check it themselves.
+// Calls made by the scheduler are skipped: they were confirmed when the action was scheduled.
+func CheckConfirmation(ctx context.Context, tool *ToolDefinition, args map[string]interface{}) error {
+	if skip, _ := ctx.Value("skipConfirmation").(bool); skip {
+		return nil
+	}
+	if tool.Annotations == nil || !tool.Annotations.Destructive || !SessionPreferences(ctx).ConfirmDestructive {
+		return nil
+	}
+	if confirm, _ := args["confirm"].(bool); confirm {
+		return nil
+	}
+	return InvalidArgument("%s is destructive and this session confirms destructive changes (set_preferences confirm_destructive); review the call and repeat it with confirm: true", tool.Name)
+}
+
+// withConfirmProperty adds the optional confirm argument to the input schema of a destructive
+// tool, so clients that validate arguments accept it
+func withConfirmProperty(tool *ToolDefinition) {
+	if tool.Annotations == nil || !tool.Annotations.Destructive || tool.InputSchema == nil {
+		return
+	}
+	properties, _ := tool.InputSchema["properties"].(map[string]interface{})
+	if _, ok := properties["confirm"]; ok {
+		return
+	}
+	extended := make(map[string]interface{}, len(properties)+1)
+	for name, property := range properties {
+		extended[name] = property
+	}
+	extended["confirm"] = map[string]interface{}{
+		"type":        "boolean",
+		"description": "OPTIONAL: Set to true to confirm the change when the session set confirm_destructive with set_preferences",
+	}
+	tool.InputSchema["properties"] = extended
+}
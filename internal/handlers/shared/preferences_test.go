@@ -0,0 +1,36 @@
+package shared
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSessionPreferencesScope(t *testing.T) {
+	owner := context.WithValue(context.Background(), "apiKey", "preferences-test-key")
+	first := WithSession(owner, "first")
+	second := WithSession(owner, "second")
+
+	UpdateSessionPreferences(first, func(preferences *Preferences) {
+		preferences.ConfirmDestructive = true
+	})
+	UpdateSessionPreferences(second, func(preferences *Preferences) {
+		preferences.ConfirmDestructive = false
+	})
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want bool
+	}{
+		{name: "session that turned confirmation on", ctx: first, want: true},
+		{name: "other session of the same key", ctx: second, want: false},
+		{name: "call without a session", ctx: owner, want: defaultPreferences().ConfirmDestructive},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SessionPreferences(tt.ctx).ConfirmDestructive; got != tt.want {
+				t.Fatalf("confirm_destructive = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
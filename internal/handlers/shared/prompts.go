@@ -0,0 +1,68 @@
+package shared
+
+import (
+	"sync"
+)
+
+// PromptFunc builds the text of a prompt from its arguments; required arguments are
+// checked before it runs
+type PromptFunc func(args map[string]string) (string, error)
+
+// PromptArgument is one parameter of a prompt
+type PromptArgument struct {
+	Name        string
+	Description string
+	Required    bool
+}
+
+// PromptDefinition describes a guided workflow offered as an MCP prompt
+type PromptDefinition struct {
+	Name        string
+	Description string
+	Arguments   []PromptArgument
+	Handler     PromptFunc
+}
+
+// PromptRegistry manages prompt registrations
+type PromptRegistry struct {
+	mu      sync.RWMutex
+	prompts []*PromptDefinition
+}
+
+// GlobalPrompts is the shared prompt registry
+var GlobalPrompts = &PromptRegistry{}
+
+// Register adds a prompt; prompts keep their registration order
+func (r *PromptRegistry) Register(prompt *PromptDefinition) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.prompts = append(r.prompts, prompt)
+}
+
+// List returns the registered prompts
+func (r *PromptRegistry) List() []*PromptDefinition {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]*PromptDefinition(nil), r.prompts...)
+}
+
+// GetPrompt builds the prompt name with args. Unknown prompts fail with ErrNotFound,
+// missing required arguments with ErrInvalidArgument.
+func (r *PromptRegistry) GetPrompt(name string, args map[string]string) (*PromptDefinition, string, error) {
+	for _, prompt := range r.List() {
+		if prompt.Name != name {
+			continue
+		}
+		for _, arg := range prompt.Arguments {
+			if arg.Required && args[arg.Name] == "" {
+				return nil, "", InvalidArgument("Prompt '%s' requires the argument '%s'", name, arg.Name)
+			}
+		}
+		text, err := prompt.Handler(args)
+		if err != nil {
+			return nil, "", err
+		}
+		return prompt, text, nil
+	}
+	return nil, "", NotFound("Unknown prompt '%s'", name)
+}
@@ -2,8 +2,13 @@ package shared
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/zeropsio/zerops-go/sdk"
 )
@@ -11,27 +16,65 @@ import (
 // ToolFunc is a function that handles a tool call
 type ToolFunc func(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error)
 
-// ToolDefinition describes a tool
+// ToolDefinition describes a tool. Output is the zero value of the struct the handler
+// returns, so the shape of the structured result can be documented.
 type ToolDefinition struct {
 	Name        string
 	Description string
 	InputSchema map[string]interface{}
+	Annotations *ToolAnnotations
+	Output      interface{}
 	Handler     ToolFunc
 }
 
+// ToolAnnotations are the MCP behavior hints of a tool. Clients may use them
+// to require confirmation before destructive calls.
+type ToolAnnotations struct {
+	ReadOnly    bool
+	Destructive bool
+	Idempotent  bool
+}
+
+// ReadOnly annotates a tool that does not modify any infrastructure
+func ReadOnly() *ToolAnnotations {
+	return &ToolAnnotations{ReadOnly: true, Idempotent: true}
+}
+
+// Mutating annotates a tool that modifies infrastructure. Destructive tools may
+// overwrite, remove or interrupt something; non-destructive tools only add.
+func Mutating(destructive, idempotent bool) *ToolAnnotations {
+	return &ToolAnnotations{Destructive: destructive, Idempotent: idempotent}
+}
+
+// Map returns the annotations as the MCP "annotations" object
+func (a *ToolAnnotations) Map() map[string]interface{} {
+	return map[string]interface{}{
+		"readOnlyHint":    a.ReadOnly,
+		"destructiveHint": a.Destructive,
+		"idempotentHint":  a.Idempotent,
+	}
+}
+
 // ToolRegistry manages tool registrations
 type ToolRegistry struct {
 	mu    sync.RWMutex
 	tools map[string]*ToolDefinition
 }
 
+// ErrToolNotFound is returned by CallTool for unknown tool names
+var ErrToolNotFound = errors.New("tool not found")
+
 // GlobalRegistry is the shared tool registry
-var GlobalRegistry = &ToolRegistry{
-	tools: make(map[string]*ToolDefinition),
+var GlobalRegistry = NewToolRegistry()
+
+// NewToolRegistry creates an empty tool registry
+func NewToolRegistry() *ToolRegistry {
+	return &ToolRegistry{tools: make(map[string]*ToolDefinition)}
 }
 
 // Register adds a tool to the registry
 func (r *ToolRegistry) Register(tool *ToolDefinition) {
+	withConfirmProperty(tool)
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	r.tools[tool.Name] = tool
@@ -45,7 +88,8 @@ func (r *ToolRegistry) Get(name string) (*ToolDefinition, bool) {
 	return tool, ok
 }
 
-// List returns all registered tools
+// List returns all registered tools sorted by name. The order is stable across
+// runs and transports, so clients can cache tools/list and compare it verbatim.
 func (r *ToolRegistry) List() []*ToolDefinition {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -54,20 +98,109 @@ func (r *ToolRegistry) List() []*ToolDefinition {
 	for _, tool := range r.tools {
 		tools = append(tools, tool)
 	}
+	sort.Slice(tools, func(i, j int) bool {
+		return tools[i].Name < tools[j].Name
+	})
 	return tools
 }
 
+// CallRecorder captures tool calls for later replay. ToolCalled runs before the handler, so a
+// call that crashes the server is still captured; the returned function receives the outcome.
+type CallRecorder interface {
+	ToolCalled(ctx context.Context, name string, args map[string]interface{}) func(result interface{}, err error)
+}
+
+// GlobalRecorder is set by the --record flag; nil when recording is disabled
+var GlobalRecorder CallRecorder
+
 // CallTool executes a tool by name
 func (r *ToolRegistry) CallTool(ctx context.Context, name string, args map[string]interface{}) (interface{}, error) {
 	tool, ok := r.Get(name)
 	if !ok {
-		return nil, fmt.Errorf("tool not found: %s", name)
+		return nil, fmt.Errorf("%w: %s", ErrToolNotFound, name)
 	}
 
 	// Get client from context (may be nil for some tools)
 	client, _ := ctx.Value("zeropsClient").(*sdk.Handler)
 
-	return tool.Handler(ctx, client, args)
+	var recorded func(result interface{}, err error)
+	if GlobalRecorder != nil {
+		recorded = GlobalRecorder.ToolCalled(ctx, name, args)
+	}
+
+	if tool.Annotations == nil || !tool.Annotations.ReadOnly {
+		ctx = withInvokedBy(ctx, name)
+	}
+	ctx, maintenance := withMaintenanceNotes(ctx)
+	start := time.Now()
+	var result interface{}
+	err := CheckConfirmation(ctx, tool, args)
+	if err == nil {
+		result, err = tool.Handler(ctx, client, args)
+	}
+	if err == nil {
+		result = structuredResult(result)
+	}
+	result, err = maintenance.annotate(result, err)
+	if recorded != nil {
+		recorded(result, err)
+	}
+	if GlobalExporter != nil {
+		ExportEvent("tool_call", auditEvent(ctx, name, args, time.Since(start), err))
+	}
+	if err == nil {
+		r.checkNextCalls(result)
+		result = renderResult(ctx, result)
+	}
+	return result, err
+}
+
+// auditEvent describes a finished tool call for the event exporter. Only IDs, hostnames,
+// numbers and booleans of the arguments are kept; YAML and env values never leave the server.
+func auditEvent(ctx context.Context, name string, args map[string]interface{}, duration time.Duration, err error) map[string]interface{} {
+	arguments := make(map[string]interface{})
+	for key, value := range args {
+		switch value.(type) {
+		case float64, bool:
+			arguments[key] = value
+		case string:
+			if strings.HasSuffix(key, "_id") || key == "hostname" || key == "action" {
+				arguments[key] = value
+			}
+		}
+	}
+
+	event := map[string]interface{}{
+		"tool":        name,
+		"arguments":   arguments,
+		"duration_ms": duration.Milliseconds(),
+		"status":      "ok",
+		"transport":   "stdio",
+	}
+	if httpMode, _ := ctx.Value("httpMode").(bool); httpMode {
+		event["transport"] = "http"
+	}
+	if grpcMode, _ := ctx.Value("grpcMode").(bool); grpcMode {
+		event["transport"] = "grpc"
+	}
+	if err != nil {
+		event["status"] = "error"
+		event["error_code"] = ErrorCode(err)
+		event["error"] = err.Error()
+	}
+
+	apiKey, _ := ctx.Value("apiKey").(string)
+	if owner := OwnerID(apiKey); owner != "" {
+		event["key_fingerprint"] = owner
+	}
+	if identity := CachedIdentity(apiKey); identity != nil {
+		event["user"] = identity.Email
+		event["organizations"] = identity.OrgNames()
+	}
+	if clientName, ok := ctx.Value("clientName").(string); ok {
+		event["client"] = clientName
+	}
+	return event
 }
 
 // Helper function to create standard text response
@@ -82,15 +215,43 @@ func TextResponse(text string) interface{} {
 	}
 }
 
-// Helper function to create error response
-func ErrorResponse(message string) interface{} {
-	return map[string]interface{}{
+// ErrorResult converts a handler error into an MCP error result.
+// The error kind is exposed as a machine-readable error_code in structuredContent.
+// Sessions with compact output get the text as error= and message= fields.
+func ErrorResult(ctx context.Context, err error) interface{} {
+	options := SessionOutput(ctx)
+	code := ErrorCode(err)
+	text := fmt.Sprintf("Error [%s]: %v", code, err)
+	if options.Compact {
+		text = "error=" + code + " message=" + compactQuote(err.Error())
+	}
+	structured := map[string]interface{}{
+		"error_code": code,
+		"message":    err.Error(),
+	}
+
+	// Pass the API's own code and meta through so agents can fix the exact parameter
+	if details := APIErrorDetails(err); details != nil {
+		structured["api_error"] = details
+		if meta, ok := details["meta"]; ok {
+			if data, jsonErr := json.Marshal(meta); jsonErr == nil {
+				text += "\nAPI error meta: " + string(data)
+			}
+		}
+	}
+
+	result := map[string]interface{}{
 		"content": []interface{}{
 			map[string]interface{}{
 				"type": "text",
-				"text": fmt.Sprintf("❌ Error: %s", message),
+				"text": text,
 			},
 		},
-		"isError": true,
+		"structuredContent": structured,
+		"isError":           true,
+	}
+	if options.ASCII {
+		return SanitizeASCII(result)
 	}
+	return result
 }
@@ -0,0 +1,103 @@
+package shared
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+
+	"github.com/zeropsio/zerops-go/sdk"
+)
+
+// ResourceMIMEType is the MIME type of every resource; contents are the JSON a tool would return
+const ResourceMIMEType = "application/json"
+
+// ResourceFunc reads a resource. params holds the values of the template's {placeholders}.
+type ResourceFunc func(ctx context.Context, client *sdk.Handler, params map[string]string) (interface{}, error)
+
+// ResourceTemplate describes read-only resources addressed by a URI template such as
+// zerops://service/{id}/env. Placeholders match a single path segment.
+type ResourceTemplate struct {
+	URITemplate string
+	Name        string
+	Description string
+	Handler     ResourceFunc
+	// Watch, when set, lets clients subscribe to the resources. It returns the state whose
+	// changes are reported as notifications/resources/updated.
+	Watch ResourceFunc
+}
+
+// ResourceRegistry manages resource templates
+type ResourceRegistry struct {
+	mu        sync.RWMutex
+	templates []*ResourceTemplate
+}
+
+// GlobalResources is the shared resource registry
+var GlobalResources = &ResourceRegistry{}
+
+// Register adds a resource template; templates keep their registration order
+func (r *ResourceRegistry) Register(template *ResourceTemplate) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.templates = append(r.templates, template)
+}
+
+// Templates returns the registered resource templates
+func (r *ResourceRegistry) Templates() []*ResourceTemplate {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]*ResourceTemplate(nil), r.templates...)
+}
+
+// Match finds the template of uri and the values of its placeholders
+func (r *ResourceRegistry) Match(uri string) (*ResourceTemplate, map[string]string, bool) {
+	for _, template := range r.Templates() {
+		if params, ok := matchURITemplate(template.URITemplate, uri); ok {
+			return template, params, true
+		}
+	}
+	return nil, nil, false
+}
+
+// ReadResource reads uri with the client in ctx and returns its contents as JSON.
+// Unknown URIs fail with ErrNotFound.
+func (r *ResourceRegistry) ReadResource(ctx context.Context, uri string) (string, error) {
+	template, params, ok := r.Match(uri)
+	if !ok {
+		return "", NotFound("Unknown resource '%s'", uri)
+	}
+	client, _ := ctx.Value("zeropsClient").(*sdk.Handler)
+	result, err := template.Handler(ctx, client, params)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// matchURITemplate matches uri against a template segment by segment
+func matchURITemplate(template, uri string) (map[string]string, bool) {
+	templateParts := strings.Split(template, "/")
+	uriParts := strings.Split(uri, "/")
+	if len(templateParts) != len(uriParts) {
+		return nil, false
+	}
+	params := map[string]string{}
+	for i, part := range templateParts {
+		if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") {
+			if uriParts[i] == "" {
+				return nil, false
+			}
+			params[strings.Trim(part, "{}")] = uriParts[i]
+			continue
+		}
+		if part != uriParts[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
@@ -0,0 +1,75 @@
+package shared
+
+import (
+	"context"
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+// Root is a filesystem root the MCP client allows the server to operate in
+type Root struct {
+	Name string `json:"name,omitempty"`
+	URI  string `json:"uri"`
+	Path string `json:"path,omitempty"`
+}
+
+// RootsLister asks the client for its current roots
+type RootsLister func(ctx context.Context) ([]Root, error)
+
+// rootsKey is the context key under which the stdio transport stores the RootsLister
+const rootsKey = "mcpRoots"
+
+// WithRoots returns a context carrying the client's roots lister
+func WithRoots(ctx context.Context, lister RootsLister) context.Context {
+	return context.WithValue(ctx, rootsKey, lister)
+}
+
+// EffectiveRoots returns the client's roots. enforced is false when the transport
+// or client does not support roots, in which case filesystem paths are not restricted.
+func EffectiveRoots(ctx context.Context) (roots []Root, enforced bool, err error) {
+	lister, ok := ctx.Value(rootsKey).(RootsLister)
+	if !ok {
+		return nil, false, nil
+	}
+	roots, err = lister(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	return roots, true, nil
+}
+
+// RootFromURI converts a file:// root URI to a Root with its local path
+func RootFromURI(name, uri string) Root {
+	root := Root{Name: name, URI: uri}
+	if parsed, err := url.Parse(uri); err == nil && parsed.Scheme == "file" {
+		root.Path = filepath.Clean(filepath.FromSlash(parsed.Path))
+	}
+	return root
+}
+
+// CheckPathInRoots fails with ErrForbidden when the client declared roots and path is outside all of them
+func CheckPathInRoots(ctx context.Context, path string) error {
+	roots, enforced, _ := EffectiveRoots(ctx)
+	if !enforced {
+		return nil
+	}
+
+	absolute, err := filepath.Abs(path)
+	if err != nil {
+		return InvalidArgument("Invalid path '%s'", path)
+	}
+	allowed := make([]string, 0, len(roots))
+	for _, root := range roots {
+		if root.Path == "" {
+			continue
+		}
+		allowed = append(allowed, root.Path)
+		rel, err := filepath.Rel(root.Path, absolute)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return nil
+		}
+	}
+	return NewToolError(ErrForbidden, "Path '%s' is outside the roots allowed by the client (%s). Add it as a root in your MCP client or use list_effective_roots to check.",
+		path, strings.Join(allowed, ", "))
+}
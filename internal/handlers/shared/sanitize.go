@@ -0,0 +1,90 @@
+package shared
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// asciiReplacements are ASCII stand-ins for the symbols tools and the Zerops API commonly use
+var asciiReplacements = map[rune]string{
+	'→': "->", '←': "<-", '⇒': "=>", '↔': "<->",
+	'—': "-", '–': "-", '‑': "-", '−': "-",
+	'‘': "'", '’': "'", '‚': ",", '“': "\"", '”': "\"", '„': "\"",
+	'…': "...", '•': "*", '·': "*", '×': "x", '≥': ">=", '≤': "<=", '≠': "!=",
+	'✓': "OK", '✔': "OK", '✗': "X", '✘': "X",
+	'\u00a0': " ", '\u2009': " ", '\u202f': " ",
+}
+
+// stripEmoji removes emoji and the space following them
+func stripEmoji(text string) string {
+	var b strings.Builder
+	dropSpace := false
+	for _, r := range text {
+		switch {
+		case r == '\u200d' || r == '\ufe0f' || (unicode.Is(unicode.So, r) && asciiReplacements[r] == ""):
+			dropSpace = true
+			continue
+		case dropSpace && r == ' ':
+			dropSpace = false
+			continue
+		}
+		dropSpace = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// asciiText makes text ASCII-only: emoji are removed, common symbols replaced and any other
+// character, such as accented letters in names, written as a \uXXXX escape
+func asciiText(text string) string {
+	var b strings.Builder
+	for _, r := range stripEmoji(text) {
+		switch {
+		case r <= unicode.MaxASCII:
+			b.WriteRune(r)
+		case asciiReplacements[r] != "":
+			b.WriteString(asciiReplacements[r])
+		case r <= 0xFFFF:
+			fmt.Fprintf(&b, "\\u%04x", r)
+		default:
+			fmt.Fprintf(&b, "\\U%08x", r)
+		}
+	}
+	return b.String()
+}
+
+// SanitizeASCII returns result with every string made ASCII-only, for terminals and
+// clients that mangle UTF-8
+func SanitizeASCII(result interface{}) interface{} {
+	// Round-trip through JSON so structs are rendered by their json tags
+	data, err := json.Marshal(result)
+	if err != nil {
+		return result
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return result
+	}
+	return sanitizeValue(value)
+}
+
+func sanitizeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return asciiText(v)
+	case map[string]interface{}:
+		sanitized := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			sanitized[asciiText(key)] = sanitizeValue(item)
+		}
+		return sanitized
+	case []interface{}:
+		for i := range v {
+			v[i] = sanitizeValue(v[i])
+		}
+		return v
+	}
+	return value
+}
@@ -0,0 +1,270 @@
+package shared
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// sealedPrefix marks files written by SealSecret
+const sealedPrefix = "zerops-mcp-sealed:v1:"
+
+// masterKeyEnv holds the master key, 32 random bytes encoded as base64; it takes precedence
+// over the OS keychain. Passphrases are rejected: without a slow KDF a hashed passphrase is
+// only as strong as the passphrase.
+const masterKeyEnv = "ZEROPS_MCP_MASTER_KEY"
+
+// masterKeySize is the AES-256 key size
+const masterKeySize = 32
+
+// keychainService and keychainAccount identify the master key in the OS keychain
+const (
+	keychainService = "zerops-mcp"
+	keychainAccount = "master-key"
+)
+
+// ErrNoMasterKey is returned when neither the env variable nor the OS keychain provides a key
+var ErrNoMasterKey = errors.New("no master key: set " + masterKeyEnv + " or install an OS keychain (macOS Keychain, secret-tool on Linux)")
+
+var masterKey struct {
+	mu     sync.Mutex
+	key    []byte
+	source string
+}
+
+// MasterKeySource returns where the master key comes from ("env" or "keychain"),
+// creating a keychain key on first use
+func MasterKeySource() (string, error) {
+	_, source, err := loadMasterKey()
+	return source, err
+}
+
+func loadMasterKey() ([]byte, string, error) {
+	masterKey.mu.Lock()
+	defer masterKey.mu.Unlock()
+
+	if masterKey.key != nil {
+		return masterKey.key, masterKey.source, nil
+	}
+
+	if material := os.Getenv(masterKeyEnv); material != "" {
+		key, err := parseMasterKey(material)
+		if err != nil {
+			return nil, "", err
+		}
+		masterKey.key = key
+		masterKey.source = "env"
+		return masterKey.key, masterKey.source, nil
+	}
+
+	material, err := keychainMasterKey()
+	if err != nil {
+		return nil, "", err
+	}
+	// The keychain holds random bytes generated by keychainMasterKey, so hashing them is enough
+	sum := sha256.Sum256([]byte(material))
+	masterKey.key = sum[:]
+	masterKey.source = "keychain"
+	return masterKey.key, masterKey.source, nil
+}
+
+// parseMasterKey decodes the master key from the environment
+func parseMasterKey(material string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(material))
+	if err != nil || len(key) != masterKeySize {
+		return nil, fmt.Errorf("%s must be %d random bytes encoded as base64, e.g. the output of 'openssl rand -base64 32'", masterKeyEnv, masterKeySize)
+	}
+	return key, nil
+}
+
+// keychainMasterKey reads the master key from the OS keychain, generating and storing one when missing
+func keychainMasterKey() (string, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		if out, err := exec.Command("security", "find-generic-password", "-s", keychainService, "-a", keychainAccount, "-w").Output(); err == nil {
+			return strings.TrimSpace(string(out)), nil
+		}
+	case "linux":
+		if _, err := exec.LookPath("secret-tool"); err != nil {
+			return "", ErrNoMasterKey
+		}
+		if out, err := exec.Command("secret-tool", "lookup", "service", keychainService, "account", keychainAccount).Output(); err == nil && len(bytes.TrimSpace(out)) > 0 {
+			return strings.TrimSpace(string(out)), nil
+		}
+	default:
+		return "", ErrNoMasterKey
+	}
+
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	material := hex.EncodeToString(random)
+
+	// The key goes over stdin, never argv, where other local users could read it with ps
+	var cmd *exec.Cmd
+	if runtime.GOOS == "darwin" {
+		cmd = exec.Command("security", "-i")
+		cmd.Stdin = strings.NewReader(fmt.Sprintf("add-generic-password -s %s -a %s -w %s\n", keychainService, keychainAccount, material))
+	} else {
+		cmd = exec.Command("secret-tool", "store", "--label=Zerops MCP master key", "service", keychainService, "account", keychainAccount)
+		cmd.Stdin = strings.NewReader(material)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("%w (storing a new key failed: %s)", ErrNoMasterKey, strings.TrimSpace(string(out)))
+	}
+	return material, nil
+}
+
+// SealSecret encrypts data with the master key using AES-256-GCM
+func SealSecret(plaintext []byte) ([]byte, error) {
+	key, _, err := loadMasterKey()
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	sealed := gcm.Seal(nonce, nonce, plaintext, nil)
+	return []byte(sealedPrefix + base64.StdEncoding.EncodeToString(sealed) + "\n"), nil
+}
+
+// OpenSecret decrypts data written by SealSecret. Plaintext data from older
+// versions is returned unchanged with sealed=false so callers can re-seal it.
+func OpenSecret(data []byte) (plaintext []byte, sealed bool, err error) {
+	if !IsSealed(data) {
+		return data, false, nil
+	}
+
+	key, _, err := loadMasterKey()
+	if err != nil {
+		return nil, true, err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, true, err
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data[len(sealedPrefix):])))
+	if err != nil || len(raw) < gcm.NonceSize() {
+		return nil, true, errors.New("sealed data is corrupted")
+	}
+	plaintext, err = gcm.Open(nil, raw[:gcm.NonceSize()], raw[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, true, errors.New("failed to decrypt: wrong master key or corrupted data")
+	}
+	return plaintext, true, nil
+}
+
+// IsSealed reports whether data was written by SealSecret
+func IsSealed(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(sealedPrefix))
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// WriteSealedFile encrypts data and writes it with owner-only permissions. The file is
+// replaced atomically, so a crash or a concurrent write never leaves it truncated.
+func WriteSealedFile(path string, data []byte) error {
+	sealed, err := SealSecret(data)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(sealed); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// ReadSealedFile reads and decrypts a file written by WriteSealedFile
+func ReadSealedFile(path string) (data []byte, sealed bool, err error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false, err
+	}
+	return OpenSecret(raw)
+}
+
+// credentialStores lists files holding credentials, checked by credentials_doctor
+var credentialStores = struct {
+	mu    sync.Mutex
+	paths map[string]string
+}{paths: make(map[string]string)}
+
+// RegisterCredentialStore records a file that holds credentials or credential-bearing state
+func RegisterCredentialStore(name, path string) {
+	if path == "" {
+		return
+	}
+	credentialStores.mu.Lock()
+	defer credentialStores.mu.Unlock()
+	credentialStores.paths[name] = path
+}
+
+// CredentialStore is a registered credential file
+type CredentialStore struct {
+	Name string
+	Path string
+}
+
+// CredentialStores returns the registered credential files sorted by name
+func CredentialStores() []CredentialStore {
+	credentialStores.mu.Lock()
+	defer credentialStores.mu.Unlock()
+
+	stores := make([]CredentialStore, 0, len(credentialStores.paths))
+	for name, path := range credentialStores.paths {
+		stores = append(stores, CredentialStore{Name: name, Path: path})
+	}
+	sort.Slice(stores, func(i, j int) bool {
+		return stores[i].Name < stores[j].Name
+	})
+	return stores
+}
@@ -0,0 +1,156 @@
+package shared
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Master keys for the tests, 32 bytes each
+const (
+	testKeyOne = "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="
+	testKeyTwo = "ZmVkY2JhOTg3NjU0MzIxMGZlZGNiYTk4NzY1NDMyMTA="
+)
+
+// useMasterKey sets the master key material for one test and drops the cached key around it
+func useMasterKey(t *testing.T, material string) {
+	t.Helper()
+	t.Setenv(masterKeyEnv, material)
+	resetMasterKey()
+	t.Cleanup(resetMasterKey)
+}
+
+func resetMasterKey() {
+	masterKey.mu.Lock()
+	masterKey.key = nil
+	masterKey.source = ""
+	masterKey.mu.Unlock()
+}
+
+func TestSealOpenRoundTrip(t *testing.T) {
+	tests := []struct {
+		name      string
+		sealKey   string
+		openKey   string
+		plaintext []byte
+		wantErr   bool
+	}{
+		{name: "same key", sealKey: testKeyOne, openKey: testKeyOne, plaintext: []byte(`{"user-1":"zrp_secret"}`)},
+		{name: "empty plaintext", sealKey: testKeyOne, openKey: testKeyOne, plaintext: []byte{}},
+		{name: "wrong key", sealKey: testKeyOne, openKey: testKeyTwo, plaintext: []byte(`{"user-1":"zrp_secret"}`), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useMasterKey(t, tt.sealKey)
+			sealed, err := SealSecret(tt.plaintext)
+			if err != nil {
+				t.Fatalf("SealSecret: %v", err)
+			}
+			if !IsSealed(sealed) {
+				t.Fatalf("sealed data lacks the sealed prefix: %q", sealed)
+			}
+			if len(tt.plaintext) > 0 && bytes.Contains(sealed, tt.plaintext) {
+				t.Fatalf("sealed data contains the plaintext")
+			}
+
+			useMasterKey(t, tt.openKey)
+			opened, wasSealed, err := OpenSecret(sealed)
+			if !wasSealed {
+				t.Fatalf("OpenSecret reported unsealed data")
+			}
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("OpenSecret = %q, want an error", opened)
+				}
+				return
+			}
+			if err != nil || !bytes.Equal(opened, tt.plaintext) {
+				t.Fatalf("OpenSecret = %q, %v, want %q", opened, err, tt.plaintext)
+			}
+		})
+	}
+}
+
+func TestMasterKeyFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		material string
+		wantErr  bool
+	}{
+		{name: "32 bytes of base64", material: testKeyOne},
+		{name: "surrounding whitespace", material: " " + testKeyOne + "\n"},
+		{name: "passphrase", material: "correct horse battery staple", wantErr: true},
+		{name: "16 bytes of base64", material: "MDEyMzQ1Njc4OWFiY2RlZg==", wantErr: true},
+		{name: "hex", material: "3031323334353637383961626364656630313233343536373839616263646566", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useMasterKey(t, tt.material)
+			source, err := MasterKeySource()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("MasterKeySource = %q, want an error", source)
+				}
+				return
+			}
+			if err != nil || source != "env" {
+				t.Fatalf("MasterKeySource = %q, %v, want env", source, err)
+			}
+		})
+	}
+}
+
+func TestOpenSecretUnsealedAndCorrupted(t *testing.T) {
+	useMasterKey(t, testKeyOne)
+	tests := []struct {
+		name       string
+		data       []byte
+		wantSealed bool
+		wantErr    bool
+	}{
+		{name: "plaintext from older versions", data: []byte(`{"user-1":"zrp_secret"}`)},
+		{name: "bad base64", data: []byte(sealedPrefix + "not base64!\n"), wantSealed: true, wantErr: true},
+		{name: "too short", data: []byte(sealedPrefix + "AAAA\n"), wantSealed: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opened, sealed, err := OpenSecret(tt.data)
+			if sealed != tt.wantSealed || (err != nil) != tt.wantErr {
+				t.Fatalf("OpenSecret = %q, sealed %v, err %v", opened, sealed, err)
+			}
+			if !tt.wantErr && !bytes.Equal(opened, tt.data) {
+				t.Fatalf("OpenSecret = %q, want the data unchanged", opened)
+			}
+		})
+	}
+}
+
+func TestWriteSealedFile(t *testing.T) {
+	useMasterKey(t, testKeyOne)
+	path := filepath.Join(t.TempDir(), "vault", "keys.json")
+	for _, content := range []string{`{"user-1":"zrp_a"}`, `{"user-1":"zrp_b"}`} {
+		if err := WriteSealedFile(path, []byte(content)); err != nil {
+			t.Fatalf("WriteSealedFile: %v", err)
+		}
+		data, sealed, err := ReadSealedFile(path)
+		if err != nil || !sealed || string(data) != content {
+			t.Fatalf("ReadSealedFile = %q, sealed %v, err %v, want %q", data, sealed, err, content)
+		}
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Fatalf("mode = %v, want 0600", info.Mode().Perm())
+	}
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("directory holds %d files, want only the sealed file", len(entries))
+	}
+}
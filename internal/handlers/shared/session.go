@@ -0,0 +1,17 @@
+package shared
+
+import "context"
+
+// sessionKey is the context key under which transports store the MCP session of the call
+const sessionKey = "mcpSession"
+
+// WithSession returns a context carrying the ID of the caller's MCP session
+func WithSession(ctx context.Context, session string) context.Context {
+	return context.WithValue(ctx, sessionKey, session)
+}
+
+// SessionID returns the caller's MCP session, empty when the call has none (the CLI, embedding)
+func SessionID(ctx context.Context) string {
+	session, _ := ctx.Value(sessionKey).(string)
+	return session
+}
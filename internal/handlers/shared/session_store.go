@@ -0,0 +1,216 @@
+package shared
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// In HTTP mode per-session state (output preferences, read stamps, state history, log levels)
+// lives in memory and is lost when a hosted server restarts. A session store persists it to
+// an encrypted file: each piece of state registers how to save and restore itself, and the
+// store writes all of them shortly after any changes and once more on shutdown.
+
+// sessionStoreDelay batches the changes of a burst of tool calls into one write
+const sessionStoreDelay = 2 * time.Second
+
+// SessionState is in-memory session state that a session store persists. Save returns a
+// JSON-encodable snapshot; Load restores one written by Save.
+type SessionState struct {
+	Save func() interface{}
+	Load func(data json.RawMessage) error
+}
+
+var sessionStates = struct {
+	mu     sync.Mutex
+	states map[string]SessionState
+}{states: make(map[string]SessionState)}
+
+// RegisterSessionState adds state to the session store under name. Register before
+// OpenSessionStore, which restores registered state from the file.
+func RegisterSessionState(name string, state SessionState) {
+	sessionStates.mu.Lock()
+	defer sessionStates.mu.Unlock()
+	sessionStates.states[name] = state
+}
+
+// MapSessionState persists a map of session state guarded by mu, e.g. preferences per owner
+func MapSessionState[V any](mu *sync.Mutex, values map[string]V) SessionState {
+	return SessionState{
+		Save: func() interface{} {
+			mu.Lock()
+			defer mu.Unlock()
+			snapshot := make(map[string]V, len(values))
+			for key, value := range values {
+				snapshot[key] = value
+			}
+			return snapshot
+		},
+		Load: func(data json.RawMessage) error {
+			var restored map[string]V
+			if err := json.Unmarshal(data, &restored); err != nil {
+				return err
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			for key, value := range restored {
+				values[key] = value
+			}
+			return nil
+		},
+	}
+}
+
+// sessionStoreFile is the content of the session store file
+type sessionStoreFile struct {
+	Saved  time.Time                  `json:"saved"`
+	States map[string]json.RawMessage `json:"states"`
+}
+
+// sessionStore writes registered state to path
+type sessionStore struct {
+	path    string
+	changed chan struct{}
+	// writeMu serializes writes of the background loop and FlushSessionStore
+	writeMu sync.Mutex
+}
+
+// activeSessionStore is set by OpenSessionStore; nil keeps session state in memory only
+var activeSessionStore struct {
+	mu    sync.Mutex
+	store *sessionStore
+}
+
+// OpenSessionStore restores registered session state from path and persists it there until
+// ctx is cancelled; FlushSessionStore writes the last changes before exiting. The file is
+// encrypted with the master key, which must be available.
+func OpenSessionStore(ctx context.Context, path string) error {
+	if _, err := MasterKeySource(); err != nil {
+		return fmt.Errorf("session store needs the master key to encrypt %s: %w", path, err)
+	}
+
+	RegisterSessionState("preferences", MapSessionState(&preferenceSessions.mu, preferenceSessions.bySession))
+
+	store := &sessionStore{path: path, changed: make(chan struct{}, 1)}
+	restored, err := store.load()
+	if err != nil {
+		return fmt.Errorf("failed to load session store %s: %w", path, err)
+	}
+	if restored > 0 {
+		fmt.Fprintf(os.Stderr, "Restored %d kinds of session state from %s\n", restored, path)
+	}
+	RegisterCredentialStore("sessions", path)
+
+	activeSessionStore.mu.Lock()
+	activeSessionStore.store = store
+	activeSessionStore.mu.Unlock()
+
+	go store.run(ctx)
+	return nil
+}
+
+// SessionStateChanged schedules a write of the session store; a no-op without one
+func SessionStateChanged() {
+	activeSessionStore.mu.Lock()
+	store := activeSessionStore.store
+	activeSessionStore.mu.Unlock()
+	if store == nil {
+		return
+	}
+	select {
+	case store.changed <- struct{}{}:
+	default:
+	}
+}
+
+// FlushSessionStore writes the session store now; a no-op without one
+func FlushSessionStore() {
+	activeSessionStore.mu.Lock()
+	store := activeSessionStore.store
+	activeSessionStore.mu.Unlock()
+	if store != nil {
+		store.save()
+	}
+}
+
+func (s *sessionStore) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.changed:
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(sessionStoreDelay):
+		}
+		s.save()
+	}
+}
+
+// load restores registered state and returns how many states the file had
+func (s *sessionStore) load() (int, error) {
+	data, _, err := ReadSealedFile(s.path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	var file sessionStoreFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return 0, err
+	}
+
+	sessionStates.mu.Lock()
+	defer sessionStates.mu.Unlock()
+	restored := 0
+	for name, raw := range file.States {
+		state, ok := sessionStates.states[name]
+		if !ok {
+			continue
+		}
+		// One unreadable state, e.g. after a format change, must not lose the others
+		if err := state.Load(raw); err != nil {
+			fmt.Fprintf(os.Stderr, "Skipping session state %s: %v\n", name, err)
+			continue
+		}
+		restored++
+	}
+	return restored, nil
+}
+
+func (s *sessionStore) save() {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	sessionStates.mu.Lock()
+	names := make([]string, 0, len(sessionStates.states))
+	for name := range sessionStates.states {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	file := sessionStoreFile{Saved: time.Now().UTC(), States: make(map[string]json.RawMessage, len(names))}
+	for _, name := range names {
+		raw, err := json.Marshal(sessionStates.states[name].Save())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to save session state %s: %v\n", name, err)
+			continue
+		}
+		file.States[name] = raw
+	}
+	sessionStates.mu.Unlock()
+
+	data, err := json.Marshal(file)
+	if err != nil {
+		return
+	}
+	if err := WriteSealedFile(s.path, data); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to persist sessions: %v\n", err)
+	}
+}
@@ -0,0 +1,163 @@
+package shared
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/zeropsio/zerops-go/sdk"
+)
+
+// Subscription limits. Polls are jittered around subscriptionPollInterval, so
+// subscriptions made together don't hit the API in lockstep.
+const (
+	subscriptionPollInterval = 30 * time.Second
+	maxSessionSubscriptions  = 20
+)
+
+// ResourceNotifier sends notifications/resources/updated for uri to the subscribed session
+type ResourceNotifier func(uri string) error
+
+// resourceSubscription is the poller of one subscribed resource
+type resourceSubscription struct {
+	cancel context.CancelFunc
+}
+
+// ResourceSubscriptions polls subscribed resources and notifies their sessions of changes
+type ResourceSubscriptions struct {
+	mu        sync.Mutex
+	resources *ResourceRegistry
+	interval  time.Duration
+	sessions  map[string]map[string]*resourceSubscription
+}
+
+// GlobalSubscriptions holds the resource subscriptions of every session
+var GlobalSubscriptions = NewResourceSubscriptions(GlobalResources, subscriptionPollInterval)
+
+// NewResourceSubscriptions creates subscriptions to the resources of registry, polled every interval on average
+func NewResourceSubscriptions(registry *ResourceRegistry, interval time.Duration) *ResourceSubscriptions {
+	return &ResourceSubscriptions{
+		resources: registry,
+		interval:  interval,
+		sessions:  make(map[string]map[string]*resourceSubscription),
+	}
+}
+
+// Subscribe starts polling uri with the client in ctx and calls notify whenever its watched
+// state changes. The poller outlives ctx; it stops on Unsubscribe, when the resource is gone
+// or when notify fails. Subscribing again to the same uri restarts its poller.
+func (s *ResourceSubscriptions) Subscribe(ctx context.Context, session, uri string, notify ResourceNotifier) error {
+	template, params, ok := s.resources.Match(uri)
+	if !ok {
+		return NotFound("Unknown resource '%s'", uri)
+	}
+	if template.Watch == nil {
+		return InvalidArgument("Resource '%s' can't be subscribed to", uri)
+	}
+	client, _ := ctx.Value("zeropsClient").(*sdk.Handler)
+	state, err := watchResource(ctx, template, client, params)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	subscriptions := s.sessions[session]
+	if subscriptions == nil {
+		subscriptions = make(map[string]*resourceSubscription)
+		s.sessions[session] = subscriptions
+	}
+	if existing, ok := subscriptions[uri]; ok {
+		existing.cancel()
+	} else if len(subscriptions) >= maxSessionSubscriptions {
+		return InvalidArgument("Too many resource subscriptions (max %d). Unsubscribe from one first.", maxSessionSubscriptions)
+	}
+
+	pollCtx, cancel := context.WithCancel(WithBackgroundPolling(context.WithoutCancel(ctx)))
+	subscription := &resourceSubscription{cancel: cancel}
+	subscriptions[uri] = subscription
+	go s.poll(pollCtx, subscription, session, uri, template, client, params, state, notify)
+	return nil
+}
+
+// Unsubscribe stops polling uri for session
+func (s *ResourceSubscriptions) Unsubscribe(session, uri string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if subscription, ok := s.sessions[session][uri]; ok {
+		subscription.cancel()
+		s.removeLocked(session, uri)
+	}
+}
+
+// UnsubscribeAll stops every poller of session, e.g. when its connection closes
+func (s *ResourceSubscriptions) UnsubscribeAll(session string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, subscription := range s.sessions[session] {
+		subscription.cancel()
+	}
+	delete(s.sessions, session)
+}
+
+func (s *ResourceSubscriptions) removeLocked(session, uri string) {
+	delete(s.sessions[session], uri)
+	if len(s.sessions[session]) == 0 {
+		delete(s.sessions, session)
+	}
+}
+
+// poll compares the watched state of uri after every jittered interval until ctx is canceled
+func (s *ResourceSubscriptions) poll(ctx context.Context, subscription *resourceSubscription, session, uri string, template *ResourceTemplate, client *sdk.Handler, params map[string]string, last string, notify ResourceNotifier) {
+	defer func() {
+		s.mu.Lock()
+		// A restarted subscription has replaced this one; leave it in place
+		if s.sessions[session][uri] == subscription {
+			s.removeLocked(session, uri)
+		}
+		s.mu.Unlock()
+	}()
+
+	for {
+		timer := time.NewTimer(s.interval/2 + rand.N(s.interval))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		state, err := watchResource(ctx, template, client, params)
+		if ctx.Err() != nil {
+			return
+		}
+		if errors.Is(err, ErrNotFound) {
+			// The resource was deleted; reading it tells the client so
+			notify(uri)
+			return
+		}
+		if err != nil || state == last {
+			continue
+		}
+		last = state
+		if notify(uri) != nil {
+			return
+		}
+	}
+}
+
+// watchResource returns the watched state of a resource as JSON, so states compare as strings
+func watchResource(ctx context.Context, template *ResourceTemplate, client *sdk.Handler, params map[string]string) (string, error) {
+	state, err := template.Watch(ctx, client, params)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,109 @@
+package shared
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/zeropsio/zerops-go/sdk"
+)
+
+func TestResourceSubscriptions(t *testing.T) {
+	var status atomic.Value
+	status.Store("ACTIVE")
+	registry := &ResourceRegistry{}
+	registry.Register(&ResourceTemplate{
+		URITemplate: "zerops://service/{id}",
+		Watch: func(ctx context.Context, client *sdk.Handler, params map[string]string) (interface{}, error) {
+			if params["id"] == "missing" {
+				return nil, NotFound("Service not found")
+			}
+			return status.Load(), nil
+		},
+	})
+	registry.Register(&ResourceTemplate{URITemplate: "zerops://service/{id}/env"})
+	subscriptions := NewResourceSubscriptions(registry, 10*time.Millisecond)
+
+	updates := make(chan string, 10)
+	notify := func(uri string) error {
+		updates <- uri
+		return nil
+	}
+
+	tests := []struct {
+		name    string
+		uri     string
+		wantErr error
+	}{
+		{name: "unknown resource", uri: "zerops://volume/1", wantErr: ErrNotFound},
+		{name: "missing service", uri: "zerops://service/missing", wantErr: ErrNotFound},
+		{name: "resource without watch", uri: "zerops://service/1/env", wantErr: ErrInvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := subscriptions.Subscribe(context.Background(), "session", tt.uri, notify)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := subscriptions.Subscribe(context.Background(), "session", "zerops://service/1", notify); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	select {
+	case uri := <-updates:
+		t.Fatalf("update for %s before any change", uri)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	status.Store("STOPPED")
+	select {
+	case uri := <-updates:
+		if uri != "zerops://service/1" {
+			t.Fatalf("update for %s, want zerops://service/1", uri)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no update after the state changed")
+	}
+
+	subscriptions.Unsubscribe("session", "zerops://service/1")
+	status.Store("ACTIVE")
+	select {
+	case uri := <-updates:
+		t.Fatalf("update for %s after unsubscribing", uri)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestResourceSubscriptionsLimit(t *testing.T) {
+	registry := &ResourceRegistry{}
+	registry.Register(&ResourceTemplate{
+		URITemplate: "zerops://service/{id}",
+		Watch: func(ctx context.Context, client *sdk.Handler, params map[string]string) (interface{}, error) {
+			return "ACTIVE", nil
+		},
+	})
+	subscriptions := NewResourceSubscriptions(registry, time.Hour)
+	defer subscriptions.UnsubscribeAll("session")
+	notify := func(uri string) error { return nil }
+
+	for i := 0; i < maxSessionSubscriptions; i++ {
+		uri := "zerops://service/" + string(rune('a'+i))
+		if err := subscriptions.Subscribe(context.Background(), "session", uri, notify); err != nil {
+			t.Fatalf("subscription %d: unexpected error: %v", i+1, err)
+		}
+	}
+	if err := subscriptions.Subscribe(context.Background(), "session", "zerops://service/a", notify); err != nil {
+		t.Fatalf("subscribing again: unexpected error: %v", err)
+	}
+	if err := subscriptions.Subscribe(context.Background(), "session", "zerops://service/over", notify); !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("subscription over the limit: err = %v, want invalid argument", err)
+	}
+	if err := subscriptions.Subscribe(context.Background(), "other", "zerops://service/a", notify); err != nil {
+		t.Fatalf("other session: unexpected error: %v", err)
+	}
+	subscriptions.UnsubscribeAll("other")
+}
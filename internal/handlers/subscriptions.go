@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// SubscriptionTransport adds resource subscriptions to a stdio transport. The go-sdk server
+// answers resources/subscribe with "method not found", so the connection answers
+// resources/subscribe and resources/unsubscribe itself, writes
+// notifications/resources/updated and advertises the subscribe capability on initialize.
+func SubscriptionTransport(transport mcp.Transport) mcp.Transport {
+	return &subscriptionTransport{transport: transport}
+}
+
+type subscriptionTransport struct {
+	transport mcp.Transport
+}
+
+func (t *subscriptionTransport) Connect(ctx context.Context) (mcp.Connection, error) {
+	conn, err := t.transport.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &subscriptionConn{Connection: conn, ctx: ctx}, nil
+}
+
+// subscriptionConn serializes writes, since notifications are written by the pollers
+// of the subscriptions while the server writes its responses
+type subscriptionConn struct {
+	mcp.Connection
+	ctx context.Context
+
+	mu           sync.Mutex
+	initializeID jsonrpc.ID
+}
+
+func (c *subscriptionConn) Read(ctx context.Context) (jsonrpc.Message, error) {
+	for {
+		msg, err := c.Connection.Read(ctx)
+		if err != nil {
+			return nil, err
+		}
+		req, ok := msg.(*jsonrpc.Request)
+		if !ok || !req.IsCall() {
+			return msg, nil
+		}
+		switch req.Method {
+		case "initialize":
+			c.mu.Lock()
+			c.initializeID = req.ID
+			c.mu.Unlock()
+		case "resources/subscribe", "resources/unsubscribe":
+			// Subscribing reads the resource, so it doesn't hold up the requests behind it
+			go c.handleSubscription(req)
+			continue
+		}
+		return msg, nil
+	}
+}
+
+func (c *subscriptionConn) Write(ctx context.Context, msg jsonrpc.Message) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if resp, ok := msg.(*jsonrpc.Response); ok && c.initializeID.IsValid() && resp.ID == c.initializeID {
+		c.initializeID = jsonrpc.ID{}
+		if resp.Error == nil {
+			resp.Result = withSubscribeCapability(resp.Result)
+		}
+	}
+	return c.Connection.Write(ctx, msg)
+}
+
+func (c *subscriptionConn) Close() error {
+	shared.GlobalSubscriptions.UnsubscribeAll(stdioSession)
+	return c.Connection.Close()
+}
+
+// handleSubscription answers resources/subscribe or resources/unsubscribe
+func (c *subscriptionConn) handleSubscription(req *jsonrpc.Request) {
+	var params struct {
+		URI string `json:"uri"`
+	}
+	err := json.Unmarshal(req.Params, &params)
+	if err == nil && params.URI == "" {
+		err = shared.InvalidArgument("uri is required")
+	}
+	if err == nil {
+		if req.Method == "resources/subscribe" {
+			ctx := shared.WithSession(c.ctx, stdioSession)
+			if client := stdioClient(); client != nil {
+				ctx = context.WithValue(ctx, "zeropsClient", client)
+			}
+			err = shared.GlobalSubscriptions.Subscribe(ctx, stdioSession, params.URI, c.notifyUpdated)
+		} else {
+			shared.GlobalSubscriptions.Unsubscribe(stdioSession, params.URI)
+		}
+	}
+
+	resp := &jsonrpc.Response{ID: req.ID, Result: json.RawMessage("{}")}
+	if errors.Is(err, shared.ErrNotFound) {
+		resp = &jsonrpc.Response{ID: req.ID, Error: mcp.ResourceNotFoundError(params.URI)}
+	} else if err != nil {
+		resp = &jsonrpc.Response{ID: req.ID, Error: err}
+	}
+	c.Write(c.ctx, resp)
+}
+
+// notifyUpdated tells the client that the subscribed resource at uri changed
+func (c *subscriptionConn) notifyUpdated(uri string) error {
+	params, err := json.Marshal(map[string]string{"uri": uri})
+	if err != nil {
+		return err
+	}
+	return c.Write(c.ctx, &jsonrpc.Request{Method: "notifications/resources/updated", Params: params})
+}
+
+// withSubscribeCapability sets capabilities.resources.subscribe in an initialize result
+func withSubscribeCapability(result json.RawMessage) json.RawMessage {
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(result, &decoded); err != nil {
+		return result
+	}
+	capabilities, _ := decoded["capabilities"].(map[string]interface{})
+	resources, ok := capabilities["resources"].(map[string]interface{})
+	if !ok {
+		return result
+	}
+	resources["subscribe"] = true
+	data, err := json.Marshal(decoded)
+	if err != nil {
+		return result
+	}
+	return data
+}
@@ -0,0 +1,221 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/dto/input/path"
+	"github.com/zeropsio/zerops-go/sdk"
+	"github.com/zeropsio/zerops-go/types/uuid"
+)
+
+// accessLogLimit is the number of webserver log lines analysed per call
+const accessLogLimit = 1000
+
+// accessLinePattern extracts method, path and status from common/combined access log lines:
+// 1.2.3.4 - - [02/Jan/2025:15:04:05 +0000] "GET /path?q=1 HTTP/1.1" 200 612 "-" "curl/8.0"
+var accessLinePattern = regexp.MustCompile(`"([A-Z]+) ([^ "]+)[^"]*" (\d{3}) `)
+
+// accessEntry is a parsed webserver access log line
+type accessEntry struct {
+	Method string
+	Path   string
+	Status string
+}
+
+// parseAccessLine parses a webserver log line, ok is false for non-access lines (e.g. error log)
+func parseAccessLine(line string) (accessEntry, bool) {
+	match := accessLinePattern.FindStringSubmatch(line)
+	if match == nil {
+		return accessEntry{}, false
+	}
+	requestPath := match[2]
+	if i := strings.IndexByte(requestPath, '?'); i >= 0 {
+		requestPath = requestPath[:i]
+	}
+	return accessEntry{Method: match[1], Path: requestPath, Status: match[3]}, true
+}
+
+// RegisterAccessStats registers the get_access_stats tool
+func RegisterAccessStats() {
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "get_access_stats",
+		Description: toolDescription("get_access_stats"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"service_id": map[string]interface{}{
+					"type":        "string",
+					"description": "REQUIRED: Service ID from discovery tool",
+					"pattern":     "^[A-Za-z0-9_-]+$",
+				},
+				"since_minutes": map[string]interface{}{
+					"type":        "integer",
+					"description": "OPTIONAL: Only count requests from the last N minutes (1-1440, default: 60)",
+					"minimum":     1,
+					"maximum":     1440,
+					"default":     60,
+				},
+				"top": map[string]interface{}{
+					"type":        "integer",
+					"description": "OPTIONAL: Number of top paths to return (1-50, default: 10)",
+					"minimum":     1,
+					"maximum":     50,
+					"default":     10,
+				},
+			},
+			"required":             []string{"service_id"},
+			"additionalProperties": false,
+		},
+		Annotations: shared.ReadOnly(),
+		Output:      accessStatsResult{},
+		Handler:     handleGetAccessStats,
+	})
+}
+
+// accessStatsResult is the result of get_access_stats
+type accessStatsResult struct {
+	ServiceID      string         `json:"service_id"`
+	ServiceName    string         `json:"service_name"`
+	SinceMinutes   int            `json:"since_minutes"`
+	Requests       int            `json:"requests"`
+	StatusCodes    map[string]int `json:"status_codes,omitempty"`
+	StatusClasses  map[string]int `json:"status_classes,omitempty"`
+	Methods        map[string]int `json:"methods,omitempty"`
+	TopPaths       []pathCount    `json:"top_paths,omitempty"`
+	NonAccessLines int            `json:"non_access_lines,omitempty"`
+	Note           string         `json:"note,omitempty"`
+	Message        string         `json:"message,omitempty"`
+}
+
+// pathCount is the number of requests to one path
+type pathCount struct {
+	Path     string `json:"path"`
+	Requests int    `json:"requests"`
+}
+
+func handleGetAccessStats(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+
+	serviceID, ok := args["service_id"].(string)
+	if !ok || serviceID == "" {
+		return nil, shared.InvalidArgument("Service ID is required")
+	}
+
+	since := time.Hour
+	if m, ok := args["since_minutes"].(float64); ok && m > 0 {
+		since = time.Duration(m) * time.Minute
+	}
+
+	top := 10
+	if t, ok := args["top"].(float64); ok && t > 0 && t <= 50 {
+		top = int(t)
+	}
+
+	serviceResp, err := client.GetServiceStack(ctx, path.ServiceStackId{Id: uuid.ServiceStackId(serviceID)})
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to get service")
+	}
+
+	service, err := serviceResp.Output()
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to get service")
+	}
+
+	logs, err := fetchLogs(ctx, client, service.ProjectId, logQuery{
+		ServiceID: serviceID,
+		Limit:     accessLogLimit,
+		Facility:  getFacilityCode("WEBSERVER"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	from := time.Now().Add(-since)
+	statusCodes := map[string]int{}
+	statusClasses := map[string]int{}
+	methods := map[string]int{}
+	paths := map[string]int{}
+	total, skipped := 0, 0
+	var oldest time.Time
+
+	for _, entry := range logs {
+		if ts, err := time.Parse(time.RFC3339Nano, entry.Timestamp); err == nil {
+			if ts.Before(from) {
+				continue
+			}
+			if oldest.IsZero() || ts.Before(oldest) {
+				oldest = ts
+			}
+		}
+
+		line := entry.Content
+		if line == "" {
+			line = entry.Message
+		}
+		access, ok := parseAccessLine(line)
+		if !ok {
+			skipped++
+			continue
+		}
+
+		total++
+		statusCodes[access.Status]++
+		statusClasses[access.Status[:1]+"xx"]++
+		methods[access.Method]++
+		paths[access.Path]++
+	}
+
+	result := accessStatsResult{
+		ServiceID:    serviceID,
+		ServiceName:  service.Name.Native(),
+		SinceMinutes: int(since.Minutes()),
+		Requests:     total,
+	}
+
+	if total == 0 {
+		result.Message = "No HTTP access log lines found in the time range. The service may not receive traffic or may not write webserver logs."
+		return result, nil
+	}
+
+	result.StatusCodes = statusCodes
+	result.StatusClasses = statusClasses
+	result.Methods = methods
+	result.TopPaths = topPaths(paths, top)
+	result.NonAccessLines = skipped
+	if len(logs) >= accessLogLimit && oldest.After(from) {
+		result.Note = fmt.Sprintf("Log limit reached; stats cover requests since %s only.", oldest.UTC().Format(time.RFC3339))
+	}
+
+	return result, nil
+}
+
+// topPaths returns the n most requested paths, ties ordered by path
+func topPaths(counts map[string]int, n int) []pathCount {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	if len(keys) > n {
+		keys = keys[:n]
+	}
+
+	result := make([]pathCount, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, pathCount{Path: key, Requests: counts[key]})
+	}
+	return result
+}
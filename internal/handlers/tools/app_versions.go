@@ -0,0 +1,201 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/dto/input/body"
+	"github.com/zeropsio/zerops-go/dto/input/path"
+	"github.com/zeropsio/zerops-go/dto/output"
+	"github.com/zeropsio/zerops-go/sdk"
+	"github.com/zeropsio/zerops-go/types"
+	"github.com/zeropsio/zerops-go/types/uuid"
+)
+
+// App version listing limits
+const (
+	defaultAppVersionLimit = 20
+	maxAppVersionLimit     = 100
+)
+
+// RegisterAppVersions registers the list_app_versions tool
+func RegisterAppVersions() {
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "list_app_versions",
+		Description: toolDescription("list_app_versions"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"service_id": map[string]interface{}{
+					"type":        "string",
+					"description": "REQUIRED: Service ID from discovery tool",
+					"pattern":     "^[A-Za-z0-9_-]+$",
+				},
+				"limit": map[string]interface{}{
+					"type":        "integer",
+					"description": "OPTIONAL: Number of versions to return (default: 20, max: 100)",
+					"minimum":     1,
+					"maximum":     maxAppVersionLimit,
+				},
+				"status": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Only versions with this status, e.g. ACTIVE, BACKUP or BUILD_FAILED",
+				},
+				"timezone": timezoneProperty(),
+			},
+			"required":             []string{"service_id"},
+			"additionalProperties": false,
+		},
+		Annotations: shared.ReadOnly(),
+		Output:      appVersionsResult{},
+		Handler:     handleListAppVersions,
+	})
+}
+
+// appVersionsResult is the result of list_app_versions
+type appVersionsResult struct {
+	ServiceID          string                `json:"service_id"`
+	ServiceName        string                `json:"service_name"`
+	Versions           []appVersionListEntry `json:"versions"`
+	Count              int                   `json:"count"`
+	ActiveAppVersionID string                `json:"active_app_version_id,omitempty"`
+	Message            string                `json:"message,omitempty"`
+}
+
+// appVersionListEntry is one app version in list_app_versions
+type appVersionListEntry struct {
+	AppVersionID string     `json:"app_version_id"`
+	Sequence     int        `json:"sequence"`
+	Status       string     `json:"status"`
+	Active       bool       `json:"active"`
+	Source       string     `json:"source"`
+	Created      string     `json:"created"`
+	Repository   string     `json:"repository,omitempty"`
+	Commit       string     `json:"commit,omitempty"`
+	Pusher       string     `json:"pusher,omitempty"`
+	Branch       string     `json:"branch,omitempty"`
+	Tag          string     `json:"tag,omitempty"`
+	Build        *buildInfo `json:"build,omitempty"`
+}
+
+func handleListAppVersions(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+
+	serviceID, ok := args["service_id"].(string)
+	if !ok || serviceID == "" {
+		return nil, shared.InvalidArgument("Service ID is required")
+	}
+	limit := defaultAppVersionLimit
+	if value, ok := args["limit"].(float64); ok {
+		limit = int(value)
+	}
+	if limit < 1 || limit > maxAppVersionLimit {
+		return nil, shared.InvalidArgument("limit must be between 1 and %d", maxAppVersionLimit)
+	}
+	status, _ := args["status"].(string)
+
+	display, err := resolveDisplayFormat(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	serviceResp, err := client.GetServiceStack(ctx, path.ServiceStackId{Id: uuid.ServiceStackId(serviceID)})
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to get service")
+	}
+	service, err := serviceResp.Output()
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to get service")
+	}
+	activeID := ""
+	if service.ActiveAppVersion != nil {
+		activeID = string(service.ActiveAppVersion.Id)
+	}
+
+	filter := body.EsFilter{
+		Search: []body.EsSearchItem{
+			{
+				Name:     "serviceStackId",
+				Operator: "eq",
+				Value:    types.String(serviceID),
+			},
+		},
+		Sort: []body.EsSortItem{
+			{Name: "sequence", Ascending: types.NewBoolNull(false)},
+		},
+		Limit: types.NewIntNull(limit),
+	}
+	if status != "" {
+		filter.Search = append(filter.Search, body.EsSearchItem{
+			Name:     "status",
+			Operator: "eq",
+			Value:    types.String(status),
+		})
+	}
+
+	resp, err := client.PostAppVersionSearch(ctx, filter)
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to search app versions")
+	}
+	versions, err := resp.Output()
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to parse app versions")
+	}
+
+	items := make([]appVersionListEntry, 0, len(versions.Items))
+	for i := range versions.Items {
+		items = append(items, appVersionEntry(&versions.Items[i], activeID, display))
+	}
+
+	result := appVersionsResult{
+		ServiceID:   serviceID,
+		ServiceName: service.Name.Native(),
+		Versions:    items,
+		Count:       len(items),
+	}
+	if activeID != "" {
+		result.ActiveAppVersionID = activeID
+	} else {
+		result.Message = "The service has no active app version; nothing was deployed yet or every deploy failed."
+	}
+	return result, nil
+}
+
+// appVersionEntry describes one app version in list_app_versions
+func appVersionEntry(version *output.EsAppVersion, activeID string, display *displayFormat) appVersionListEntry {
+	entry := appVersionListEntry{
+		AppVersionID: string(version.Id),
+		Sequence:     version.Sequence.Native(),
+		Status:       string(version.Status),
+		Active:       string(version.Id) == activeID,
+		Source:       string(version.Source),
+		Created:      formatTimestamp(version.Created.Native(), display),
+	}
+
+	if github := version.GithubIntegration; github != nil {
+		entry.Repository = github.RepositoryFullName.Native()
+		entry.Commit = github.Commit.Native()
+		entry.Pusher = github.Pusher.Native()
+		if branch, ok := github.BranchName.Get(); ok {
+			entry.Branch = branch.Native()
+		}
+		if tag, ok := github.TagName.Get(); ok {
+			entry.Tag = tag.Native()
+		}
+	}
+	if git := version.PublicGitSource; git != nil {
+		entry.Repository = git.GitUrl.Native()
+		entry.Branch = git.BranchName.Native()
+	}
+
+	if version.Build != nil {
+		build := buildSummary(version, display)
+		build.AppVersionID = ""
+		build.Sequence = 0
+		build.Status = ""
+		entry.Build = build
+	}
+	return entry
+}
@@ -0,0 +1,408 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/dto/input/body"
+	"github.com/zeropsio/zerops-go/dto/input/path"
+	"github.com/zeropsio/zerops-go/dto/output"
+	"github.com/zeropsio/zerops-go/sdk"
+	"github.com/zeropsio/zerops-go/types"
+	"github.com/zeropsio/zerops-go/types/uuid"
+)
+
+// backupServiceTypes are the service types the backup tools work with
+var backupServiceTypes = map[string]bool{"postgresql": true, "mariadb": true, "mongodb": true}
+
+// backupPollInterval is how often backup_create checks for the new backup
+const backupPollInterval = 5 * time.Second
+
+// RegisterBackups registers the backup_list, backup_create and backup_download tools
+func RegisterBackups() {
+	serviceIDProperty := map[string]interface{}{
+		"type":        "string",
+		"description": "REQUIRED: ID of a postgresql, mariadb or mongodb service",
+		"pattern":     "^[A-Za-z0-9_-]+$",
+	}
+
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "backup_list",
+		Description: toolDescription("backup_list"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"service_id": serviceIDProperty,
+				"timezone": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: IANA timezone for timestamps (default: the session timezone, ZEROPS_MCP_TIMEZONE or UTC)",
+				},
+			},
+			"required":             []string{"service_id"},
+			"additionalProperties": false,
+		},
+		Annotations: shared.ReadOnly(),
+		Output:      backupListResult{},
+		Handler:     handleBackupList,
+	})
+
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "backup_create",
+		Description: toolDescription("backup_create"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"service_id": serviceIDProperty,
+				"tags": map[string]interface{}{
+					"type":        "array",
+					"description": "OPTIONAL: Tags of the backup, e.g. [\"before-migration\"]",
+					"items":       map[string]interface{}{"type": "string"},
+				},
+				"wait": map[string]interface{}{
+					"type":        "boolean",
+					"description": "OPTIONAL: Wait until the backup is listed (default: true)",
+				},
+				"wait_seconds": map[string]interface{}{
+					"type":        "integer",
+					"description": "OPTIONAL: How long to wait (default: 300, max: 1800)",
+					"minimum":     10,
+					"maximum":     1800,
+				},
+				"timezone": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: IANA timezone for timestamps (default: the session timezone, ZEROPS_MCP_TIMEZONE or UTC)",
+				},
+			},
+			"required":             []string{"service_id"},
+			"additionalProperties": false,
+		},
+		Annotations: shared.Mutating(false, false),
+		Output:      backupCreateResult{},
+		Handler:     handleBackupCreate,
+	})
+
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "backup_download",
+		Description: toolDescription("backup_download"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"service_id": serviceIDProperty,
+				"backup": map[string]interface{}{
+					"type":        "string",
+					"description": "REQUIRED: Backup name from backup_list",
+				},
+			},
+			"required":             []string{"service_id", "backup"},
+			"additionalProperties": false,
+		},
+		Annotations: shared.ReadOnly(),
+		Output:      backupDownloadResult{},
+		Handler:     handleBackupDownload,
+	})
+}
+
+// backupListResult is the result of backup_list
+type backupListResult struct {
+	ServiceID       string                        `json:"service_id"`
+	ServiceName     string                        `json:"service_name"`
+	Backups         []backupEntry                 `json:"backups"`
+	Count           int                           `json:"count"`
+	BackupPeriod    string                        `json:"backup_period"`
+	RetentionPolicy *output.BackupRetentionPolicy `json:"retention_policy"`
+	Message         string                        `json:"message,omitempty"`
+}
+
+// backupCreateResult is the result of backup_create
+type backupCreateResult struct {
+	ServiceID      string       `json:"service_id"`
+	ServiceName    string       `json:"service_name"`
+	Tags           []string     `json:"tags"`
+	Status         string       `json:"status"`
+	Backup         *backupEntry `json:"backup,omitempty"`
+	ElapsedSeconds int          `json:"elapsed_seconds,omitempty"`
+	Message        string       `json:"message,omitempty"`
+}
+
+// backupDownloadResult is the result of backup_download
+type backupDownloadResult struct {
+	ServiceID   string      `json:"service_id"`
+	ServiceName string      `json:"service_name"`
+	Backup      backupEntry `json:"backup"`
+	URL         string      `json:"url"`
+	Message     string      `json:"message"`
+}
+
+// backupEntry is one backup file of a service
+type backupEntry struct {
+	Name      string                 `json:"name"`
+	Timestamp string                 `json:"timestamp"`
+	SizeBytes int64                  `json:"size_bytes"`
+	Size      string                 `json:"size"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+}
+
+func handleBackupList(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+	service, err := backupService(ctx, client, args)
+	if err != nil {
+		return nil, err
+	}
+	display, err := resolveDisplayFormat(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := serviceBackups(ctx, client, service.Id)
+	if err != nil {
+		return nil, err
+	}
+	backups := make([]backupEntry, 0, len(list.Files))
+	for _, file := range sortedBackupFiles(list.Files) {
+		backups = append(backups, describeBackup(file, display))
+	}
+
+	result := backupListResult{
+		ServiceID:       string(service.Id),
+		ServiceName:     service.Name.Native(),
+		Backups:         backups,
+		Count:           len(backups),
+		BackupPeriod:    list.BackupPeriod.Native(),
+		RetentionPolicy: list.RetentionPolicy,
+	}
+	if result.RetentionPolicy == nil {
+		result.RetentionPolicy = &list.DefaultRetentionPolicy
+	}
+	if len(backups) == 0 {
+		result.Message = "No backups yet. Create one with backup_create."
+	}
+	return result, nil
+}
+
+func handleBackupCreate(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+	tags, err := stringListArg(args, "tags")
+	if err != nil {
+		return nil, err
+	}
+	wait := true
+	if value, ok := args["wait"].(bool); ok {
+		wait = value
+	}
+	timeout := 300 * time.Second
+	if value, ok := args["wait_seconds"].(float64); ok {
+		if value < 10 || value > 1800 {
+			return nil, shared.InvalidArgument("wait_seconds must be between 10 and 1800")
+		}
+		timeout = time.Duration(value) * time.Second
+	}
+	service, err := backupService(ctx, client, args)
+	if err != nil {
+		return nil, err
+	}
+	display, err := resolveDisplayFormat(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	// Remember the existing backups to recognize the new one
+	before := map[string]bool{}
+	if wait {
+		list, err := serviceBackups(ctx, client, service.Id)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range list.Files {
+			before[file.Name.Native()] = true
+		}
+	}
+
+	request := body.PostServiceStackBackup{}
+	if len(tags) > 0 {
+		request.Tags = types.NewStringArrayNull(tags)
+	}
+	resp, err := client.PostServiceStackBackup(ctx, path.ServiceStackId{Id: service.Id}, request)
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to create backup")
+	}
+	started, err := resp.Output()
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to create backup")
+	}
+	if !started.Success.Native() {
+		return nil, fmt.Errorf("backup of %s was not started", service.Name.Native())
+	}
+
+	result := backupCreateResult{
+		ServiceID:   string(service.Id),
+		ServiceName: service.Name.Native(),
+		Tags:        tags,
+		Status:      "started",
+	}
+	if !wait {
+		result.Message = "Backup started. Check backup_list for it in a few minutes."
+		return result, nil
+	}
+
+	begin := time.Now()
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	for {
+		select {
+		case <-waitCtx.Done():
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			result.Status = "timeout"
+			result.Message = fmt.Sprintf("The backup was started but not listed after %d seconds. Check backup_list later.", int(timeout.Seconds()))
+			return result, nil
+		case <-time.After(backupPollInterval):
+		}
+		shared.ReportProgress(ctx, time.Since(begin).Seconds(), timeout.Seconds(), "Waiting for the backup")
+
+		list, err := serviceBackups(waitCtx, client, service.Id)
+		if err != nil {
+			if waitCtx.Err() != nil {
+				continue
+			}
+			return nil, err
+		}
+		for _, file := range sortedBackupFiles(list.Files) {
+			if !before[file.Name.Native()] {
+				backup := describeBackup(file, display)
+				result.Status = "completed"
+				result.Backup = &backup
+				result.ElapsedSeconds = int(time.Since(begin).Seconds())
+				result.Message = "Backup created. Use backup_download for a download URL."
+				return result, nil
+			}
+		}
+	}
+}
+
+func handleBackupDownload(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+	name, _ := args["backup"].(string)
+	if name == "" {
+		return nil, shared.InvalidArgument("backup is required; take the name from backup_list")
+	}
+	service, err := backupService(ctx, client, args)
+	if err != nil {
+		return nil, err
+	}
+	display, err := resolveDisplayFormat(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := serviceBackups(ctx, client, service.Id)
+	if err != nil {
+		return nil, err
+	}
+	var backup *output.ServiceStackBackupFile
+	names := map[string]bool{}
+	for i, file := range list.Files {
+		names[file.Name.Native()] = true
+		if file.Name.Native() == name {
+			backup = &list.Files[i]
+		}
+	}
+	if backup == nil {
+		if suggestion := closestKey(name, names); suggestion != "" {
+			return nil, shared.NotFound("Backup '%s' not found on %s. Did you mean '%s'?", name, service.Name.Native(), suggestion)
+		}
+		return nil, shared.NotFound("Backup '%s' not found on %s. List the backups with backup_list.", name, service.Name.Native())
+	}
+
+	resp, err := client.PostServiceStackBackupDownloadUrl(ctx, path.ServiceStackBackup{Id: service.Id, Date: types.NewString(name)})
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to create backup download URL")
+	}
+	download, err := resp.Output()
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to create backup download URL")
+	}
+
+	return backupDownloadResult{
+		ServiceID:   string(service.Id),
+		ServiceName: service.Name.Native(),
+		Backup:      describeBackup(*backup, display),
+		URL:         download.Url.Native(),
+		Message:     "The URL is temporary and works without an API key; download the backup now and don't share the URL.",
+	}, nil
+}
+
+// backupService loads the service_id argument and checks it is a database with backups
+func backupService(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (output.ServiceStack, error) {
+	serviceID, _ := args["service_id"].(string)
+	if serviceID == "" {
+		return output.ServiceStack{}, shared.InvalidArgument("Service ID is required")
+	}
+	resp, err := client.GetServiceStack(ctx, path.ServiceStackId{Id: uuid.ServiceStackId(serviceID)})
+	if err != nil {
+		return output.ServiceStack{}, shared.WrapAPIError(err, "Failed to get service")
+	}
+	service, err := resp.Output()
+	if err != nil {
+		return output.ServiceStack{}, shared.WrapAPIError(err, "Failed to get service")
+	}
+	if base := serviceTypeBase(string(service.ServiceStackTypeVersionId)); !backupServiceTypes[base] {
+		return output.ServiceStack{}, shared.InvalidArgument("%s is a %s service; backups are supported for postgresql, mariadb and mongodb", service.Name.Native(), base)
+	}
+	return service, nil
+}
+
+// serviceBackups reads the backup list of a service
+func serviceBackups(ctx context.Context, client *sdk.Handler, serviceID uuid.ServiceStackId) (output.ServiceStackBackupFileList, error) {
+	resp, err := client.GetServiceStackBackup(ctx, path.ServiceStackId{Id: serviceID})
+	if err != nil {
+		return output.ServiceStackBackupFileList{}, shared.WrapAPIError(err, "Failed to list backups")
+	}
+	list, err := resp.Output()
+	if err != nil {
+		return output.ServiceStackBackupFileList{}, shared.WrapAPIError(err, "Failed to list backups")
+	}
+	return list, nil
+}
+
+// sortedBackupFiles orders backups newest first
+func sortedBackupFiles(files []output.ServiceStackBackupFile) []output.ServiceStackBackupFile {
+	sorted := append([]output.ServiceStackBackupFile(nil), files...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return backupTimestamp(sorted[i]) > backupTimestamp(sorted[j])
+	})
+	return sorted
+}
+
+// backupTimestamp is when a backup was taken, from its metadata or else its name,
+// which starts with the backup date
+func backupTimestamp(file output.ServiceStackBackupFile) string {
+	metadata := file.Metadata.Native()
+	for _, key := range []string{"createdAt", "created", "date", "timestamp"} {
+		if value, ok := metadata[key].(string); ok && value != "" {
+			return value
+		}
+	}
+	return strings.SplitN(file.Name.Native(), ".", 2)[0]
+}
+
+// describeBackup converts a backup file to the tool output shape
+func describeBackup(file output.ServiceStackBackupFile, display *displayFormat) backupEntry {
+	return backupEntry{
+		Name:      file.Name.Native(),
+		Timestamp: normalizeTimestamp(backupTimestamp(file), display),
+		SizeBytes: file.Size.Native(),
+		Size:      formatSize(float64(file.Size.Native()), display),
+		Metadata:  file.Metadata.Native(),
+	}
+}
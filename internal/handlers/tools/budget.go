@@ -0,0 +1,28 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/sdk"
+)
+
+// RegisterBudget registers the budget_status tool
+func RegisterBudget() {
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "budget_status",
+		Description: toolDescription("budget_status"),
+		InputSchema: map[string]interface{}{
+			"type":                 "object",
+			"properties":           map[string]interface{}{},
+			"additionalProperties": false,
+		},
+		Annotations: shared.ReadOnly(),
+		Output:      shared.BudgetStatus{},
+		Handler:     handleBudgetStatus,
+	})
+}
+
+func handleBudgetStatus(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	return shared.GlobalBudget.Status(actionOwner(ctx)), nil
+}
@@ -0,0 +1,100 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/dto/input/body"
+	"github.com/zeropsio/zerops-go/dto/output"
+	"github.com/zeropsio/zerops-go/sdk"
+	"github.com/zeropsio/zerops-go/types"
+)
+
+// buildSearchLimit is how many recent app versions are searched for a build
+const buildSearchLimit = 20
+
+// findBuildAppVersion returns the app version whose build logs to read: appVersionID when given,
+// otherwise the newest app version of the service that ran a build container
+func findBuildAppVersion(ctx context.Context, client *sdk.Handler, serviceID, appVersionID string) (*output.EsAppVersion, error) {
+	filter := body.EsFilter{
+		Search: []body.EsSearchItem{
+			{
+				Name:     "serviceStackId",
+				Operator: "eq",
+				Value:    types.String(serviceID),
+			},
+		},
+		Sort: []body.EsSortItem{
+			{Name: "sequence", Ascending: types.NewBoolNull(false)},
+		},
+		Limit: types.NewIntNull(buildSearchLimit),
+	}
+	if appVersionID != "" {
+		filter.Search = append(filter.Search, body.EsSearchItem{
+			Name:     "id",
+			Operator: "eq",
+			Value:    types.String(appVersionID),
+		})
+	}
+
+	resp, err := client.PostAppVersionSearch(ctx, filter)
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to search app versions")
+	}
+	versions, err := resp.Output()
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to parse app versions")
+	}
+
+	for i := range versions.Items {
+		version := &versions.Items[i]
+		if version.Build == nil {
+			continue
+		}
+		if _, ok := version.Build.ServiceStackId.Get(); ok {
+			return version, nil
+		}
+	}
+	if appVersionID != "" {
+		return nil, shared.NotFound("App version '%s' of service '%s' has no build container", appVersionID, serviceID)
+	}
+	return nil, shared.NotFound("Service '%s' has no builds; deploys without a build pipeline (zcli push --no-build) have no build logs", serviceID)
+}
+
+// buildInfo describes the build pipeline of an app version. list_app_versions leaves out
+// the app version fields, which its entries already carry.
+type buildInfo struct {
+	AppVersionID   string `json:"app_version_id,omitempty"`
+	Sequence       int    `json:"sequence,omitempty"`
+	Status         string `json:"status,omitempty"`
+	BuildServiceID string `json:"build_service_id,omitempty"`
+	PipelineStart  string `json:"pipeline_start,omitempty"`
+	PipelineFinish string `json:"pipeline_finish,omitempty"`
+	PipelineFailed string `json:"pipeline_failed,omitempty"`
+}
+
+// buildSummary describes the build pipeline of an app version
+func buildSummary(version *output.EsAppVersion, display *displayFormat) *buildInfo {
+	build := version.Build
+	summary := &buildInfo{
+		AppVersionID: string(version.Id),
+		Sequence:     version.Sequence.Native(),
+		Status:       string(version.Status),
+	}
+	if id, ok := build.ServiceStackId.Get(); ok {
+		summary.BuildServiceID = string(id)
+	}
+	for _, stamp := range []struct {
+		field *string
+		value types.DateTimeNull
+	}{
+		{&summary.PipelineStart, build.PipelineStart},
+		{&summary.PipelineFinish, build.PipelineFinish},
+		{&summary.PipelineFailed, build.PipelineFailed},
+	} {
+		if value, ok := stamp.value.Get(); ok {
+			*stamp.field = formatTimestamp(value.Native(), display)
+		}
+	}
+	return summary
+}
@@ -0,0 +1,200 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/dto/input/body"
+	"github.com/zeropsio/zerops-go/dto/input/path"
+	"github.com/zeropsio/zerops-go/dto/output"
+	"github.com/zeropsio/zerops-go/sdk"
+	"github.com/zeropsio/zerops-go/types"
+	"github.com/zeropsio/zerops-go/types/enum"
+)
+
+// containerUsageWindow is how far back current container usage is looked up; statistics
+// are hourly, so the latest bucket is at most this old
+const containerUsageWindow = 2 * time.Hour
+
+// RegisterContainers registers the list_containers tool
+func RegisterContainers() {
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "list_containers",
+		Description: toolDescription("list_containers"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"service_id": map[string]interface{}{
+					"type":        "string",
+					"description": "REQUIRED: Service ID from discovery tool",
+					"pattern":     "^[A-Za-z0-9_-]+$",
+				},
+				"timezone": timezoneProperty(),
+			},
+			"required":             []string{"service_id"},
+			"additionalProperties": false,
+		},
+		Annotations: shared.ReadOnly(),
+		Output:      containersResult{},
+		Handler:     handleListContainers,
+	})
+}
+
+// containersResult is the result of list_containers
+type containersResult struct {
+	ServiceID  string           `json:"service_id"`
+	Hostname   string           `json:"hostname"`
+	Containers []containerEntry `json:"containers"`
+	Count      int              `json:"count"`
+	ByStatus   map[string]int   `json:"by_status"`
+	Message    string           `json:"message,omitempty"`
+	Warnings   []string         `json:"warnings,omitempty"`
+}
+
+// containerEntry is one container of a service
+type containerEntry struct {
+	ID         string          `json:"id"`
+	Number     int             `json:"number"`
+	Status     string          `json:"status"`
+	Created    string          `json:"created"`
+	LastUpdate string          `json:"last_update"`
+	Limits     containerLimit  `json:"limits"`
+	Hostname   string          `json:"hostname,omitempty"`
+	Name       string          `json:"name,omitempty"`
+	Usage      *containerUsage `json:"usage,omitempty"`
+}
+
+// containerLimit is the hardware a container is given
+type containerLimit struct {
+	CPUCores int    `json:"cpu_cores"`
+	RAM      string `json:"ram"`
+	Disk     string `json:"disk"`
+}
+
+// containerUsage is what a container used in the latest statistics window
+type containerUsage struct {
+	CPUCores float64 `json:"cpu_cores"`
+	RAM      string  `json:"ram"`
+	Disk     string  `json:"disk"`
+	From     string  `json:"from"`
+	Till     string  `json:"till"`
+}
+
+func handleListContainers(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+
+	serviceID, _ := args["service_id"].(string)
+	if serviceID == "" {
+		return nil, shared.InvalidArgument("Service ID is required")
+	}
+	display, err := resolveDisplayFormat(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	service, err := getServiceStack(ctx, client, serviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Searches are scoped by organization, so the project provides the clientId
+	projectResp, err := client.GetProject(ctx, path.ProjectId{Id: service.ProjectId})
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to get project details")
+	}
+	projectOutput, err := projectResp.Output()
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to get project details")
+	}
+
+	containerResp, err := client.PostContainerSearch(ctx, body.EsFilter{
+		Search: []body.EsSearchItem{
+			{Name: "serviceStackId", Operator: "eq", Value: types.String(serviceID)},
+			{Name: "clientId", Operator: "eq", Value: projectOutput.ClientId.TypedString()},
+		},
+	})
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to search containers")
+	}
+	containerOutput, err := containerResp.Output()
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to search containers")
+	}
+	containers := containerOutput.Items
+	sort.Slice(containers, func(i, j int) bool {
+		return containers[i].Number.Native() < containers[j].Number.Native()
+	})
+
+	// Usage is best-effort; containers are still listed with their limits
+	var warnings []string
+	till := time.Now().UTC()
+	usage := map[string]output.EsStatsHistory{}
+	history, err := statsHistory(ctx, client, "serviceStackId", serviceID, enum.EsStatsHistoryGroupByEnumContainerId, till.Add(-containerUsageWindow), till, time.UTC, "hour")
+	if err != nil {
+		warnings = append(warnings, "Current usage unavailable: "+err.Error())
+	}
+	for _, item := range history {
+		id, ok := item.ContainerId.Get()
+		if !ok {
+			continue
+		}
+		if latest, seen := usage[id.Native()]; !seen || item.From.Native().After(latest.From.Native()) {
+			usage[id.Native()] = item
+		}
+	}
+
+	statuses := map[string]int{}
+	list := make([]containerEntry, 0, len(containers))
+	for _, container := range containers {
+		status := string(container.Status)
+		statuses[status]++
+		resources := container.CurrentHardwareResource
+		entry := containerEntry{
+			ID:         string(container.Id),
+			Number:     container.Number.Native(),
+			Status:     status,
+			Created:    formatTimestamp(container.Created.Native(), display),
+			LastUpdate: formatTimestamp(container.LastUpdate.Native(), display),
+			Limits: containerLimit{
+				CPUCores: resources.CpuCoreCount.Native(),
+				RAM:      formatSize(float64(resources.MemoryMBytes.Native())*1e6, display),
+				Disk:     formatSize(float64(resources.DiskGBytes.Native())*1e9, display),
+			},
+		}
+		if hostname, ok := container.Hostname.Get(); ok {
+			entry.Hostname = hostname.Native()
+		}
+		if name, ok := container.Name.Get(); ok {
+			entry.Name = name.Native()
+		}
+		if item, ok := usage[string(container.Id)]; ok {
+			entry.Usage = &containerUsage{
+				CPUCores: math.Round(item.CpuUsed.Native()*100) / 100,
+				RAM:      formatSize(item.RamUsed.Native(), display),
+				Disk:     formatSize(item.DiskUsed.Native(), display),
+				From:     formatTimestamp(item.From.Native(), display),
+				Till:     formatTimestamp(item.Till.Native(), display),
+			}
+		}
+		list = append(list, entry)
+	}
+
+	result := containersResult{
+		ServiceID:  serviceID,
+		Hostname:   service.Name.Native(),
+		Containers: list,
+		Count:      len(list),
+		ByStatus:   statuses,
+		Warnings:   warnings,
+	}
+	if active := statuses[string(enum.ContainerStatusEnumActive)]; active < len(list) {
+		result.Message = fmt.Sprintf("%d of %d containers are not ACTIVE; log lines from get_service_logs carry the hostname of the container that wrote them.", len(list)-active, len(list))
+	}
+	return result, nil
+}
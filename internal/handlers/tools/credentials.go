@@ -0,0 +1,140 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/sdk"
+)
+
+// RegisterCredentials registers the credentials_doctor tool
+func RegisterCredentials() {
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "credentials_doctor",
+		Description: toolDescription("credentials_doctor"),
+		InputSchema: map[string]interface{}{
+			"type":                 "object",
+			"properties":           map[string]interface{}{},
+			"additionalProperties": false,
+		},
+		Annotations: shared.ReadOnly(),
+		Output:      credentialsResult{},
+		Handler:     handleCredentialsDoctor,
+	})
+}
+
+// credentialsResult is the result of credentials_doctor
+type credentialsResult struct {
+	Status    string             `json:"status"`
+	MasterKey masterKeyReport    `json:"master_key"`
+	Stores    []credentialReport `json:"stores"`
+	Issues    []string           `json:"issues"`
+}
+
+// masterKeyReport tells whether credentials can be encrypted
+type masterKeyReport struct {
+	Available bool   `json:"available"`
+	Source    string `json:"source,omitempty"`
+	RoundTrip *bool  `json:"round_trip,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// credentialReport describes one persisted credentials file; the fields after exists are
+// only set when it exists
+type credentialReport struct {
+	Name        string `json:"name"`
+	Path        string `json:"path,omitempty"`
+	Exists      bool   `json:"exists"`
+	Permissions string `json:"permissions,omitempty"`
+	Encrypted   *bool  `json:"encrypted,omitempty"`
+	Readable    *bool  `json:"readable,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+func handleCredentialsDoctor(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	httpMode, _ := ctx.Value("httpMode").(bool)
+	var issues []string
+
+	var key masterKeyReport
+	if source, err := shared.MasterKeySource(); err != nil {
+		key.Error = err.Error()
+		issues = append(issues, "No master key is available, so credentials cannot be persisted")
+	} else {
+		key.Available = true
+		key.Source = source
+
+		probe := []byte("zerops-mcp credentials probe")
+		sealed, err := shared.SealSecret(probe)
+		if err == nil {
+			var opened []byte
+			opened, _, err = shared.OpenSecret(sealed)
+			if err == nil && !bytes.Equal(opened, probe) {
+				err = fmt.Errorf("decrypted data does not match")
+			}
+		}
+		roundTrip := err == nil
+		key.RoundTrip = &roundTrip
+		if err != nil {
+			issues = append(issues, "Encryption round trip failed: "+err.Error())
+		}
+	}
+
+	stores := make([]credentialReport, 0)
+	for _, store := range shared.CredentialStores() {
+		report, storeIssues := checkCredentialStore(store)
+		if !httpMode {
+			report.Path = store.Path
+		}
+		stores = append(stores, report)
+		issues = append(issues, storeIssues...)
+	}
+
+	status := "healthy"
+	if len(issues) > 0 {
+		status = "unhealthy"
+	}
+
+	return credentialsResult{
+		Status:    status,
+		MasterKey: key,
+		Stores:    stores,
+		Issues:    issues,
+	}, nil
+}
+
+// checkCredentialStore inspects one persisted file without returning its content
+func checkCredentialStore(store shared.CredentialStore) (credentialReport, []string) {
+	report := credentialReport{Name: store.Name}
+
+	info, err := os.Stat(store.Path)
+	if os.IsNotExist(err) {
+		return report, nil
+	}
+	if err != nil {
+		report.Error = err.Error()
+		return report, []string{fmt.Sprintf("%s: %v", store.Name, err)}
+	}
+	report.Exists = true
+
+	var issues []string
+	mode := info.Mode().Perm()
+	report.Permissions = fmt.Sprintf("%#o", mode)
+	if mode&0o077 != 0 {
+		issues = append(issues, fmt.Sprintf("%s is readable by other users (%#o); chmod 600 it", store.Name, mode))
+	}
+
+	_, sealed, err := shared.ReadSealedFile(store.Path)
+	readable := err == nil
+	report.Encrypted = &sealed
+	report.Readable = &readable
+	if !sealed {
+		issues = append(issues, store.Name+" is stored in plaintext; run zerops-mcp --seal-file on it")
+	} else if err != nil {
+		report.Error = err.Error()
+		issues = append(issues, fmt.Sprintf("%s cannot be decrypted: %v", store.Name, err))
+	}
+	return report, issues
+}
@@ -0,0 +1,234 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/dto/input/body"
+	"github.com/zeropsio/zerops-go/sdk"
+	"github.com/zeropsio/zerops-go/types"
+)
+
+// Tools that act on a project and get no project_id resolve it, in order, from:
+//  1. the project pinned for the session with current_project
+//  2. the $projectId env variable, set in Zerops containers (stdio only)
+//  3. the only project the API key can access
+const (
+	projectSourceArgument = "argument"
+	projectSourcePinned   = "pinned"
+	projectSourceEnv      = "env"
+	projectSourceOnly     = "only_project"
+)
+
+// onlyProjectTTL is how long the project count of an API key is reused for auto-detection
+const onlyProjectTTL = 5 * time.Minute
+
+// pinnedProjects keeps the project pinned with current_project per API key owner
+var pinnedProjects = struct {
+	mu      sync.Mutex
+	byOwner map[string]string
+}{byOwner: make(map[string]string)}
+
+// onlyProjects caches the projects an API key can access, per owner, for auto-detection
+var onlyProjects = struct {
+	mu      sync.Mutex
+	byOwner map[string]accessibleProjects
+}{byOwner: make(map[string]accessibleProjects)}
+
+// accessibleProjects are the project IDs an API key could access when checked
+type accessibleProjects struct {
+	ids     []string
+	checked time.Time
+}
+
+// resolvedProject is the project a call acts on and where its ID came from
+type resolvedProject struct {
+	ID     string
+	Source string
+}
+
+// resolveProject applies the project precedence. Auto-detection lists the key's projects,
+// so it only runs when nothing else provides a project.
+func resolveProject(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (resolvedProject, error) {
+	if projectID, ok := args["project_id"].(string); ok && projectID != "" {
+		return resolvedProject{ID: projectID, Source: projectSourceArgument}, nil
+	}
+	if projectID := pinnedProject(ctx); projectID != "" {
+		return resolvedProject{ID: projectID, Source: projectSourcePinned}, nil
+	}
+	if projectID := envProjectID(ctx); projectID != "" {
+		return resolvedProject{ID: projectID, Source: projectSourceEnv}, nil
+	}
+
+	if client != nil {
+		ids, err := accessibleProjectIDs(ctx, client)
+		if err != nil {
+			return resolvedProject{}, shared.InvalidArgument("Project ID is required and could not be detected (%v). Provide project_id or pin a project with current_project.", err)
+		}
+		if len(ids) == 1 {
+			return resolvedProject{ID: ids[0], Source: projectSourceOnly}, nil
+		}
+		if len(ids) > 1 {
+			return resolvedProject{}, shared.InvalidArgument("Project ID is required: the API key can access %d projects. Provide project_id, or pin one with current_project; discover_all lists them.", len(ids))
+		}
+	}
+	return resolvedProject{}, shared.InvalidArgument("Project ID is required. Provide project_id, pin a project with current_project or set $projectId.")
+}
+
+// envProjectID returns $projectId. A server hosted on Zerops gets its own project there,
+// so remote HTTP callers never fall back to it.
+func envProjectID(ctx context.Context) string {
+	if httpMode, _ := ctx.Value("httpMode").(bool); httpMode {
+		return ""
+	}
+	return os.Getenv("projectId")
+}
+
+func pinnedProject(ctx context.Context) string {
+	pinnedProjects.mu.Lock()
+	defer pinnedProjects.mu.Unlock()
+	return pinnedProjects.byOwner[actionOwner(ctx)]
+}
+
+func setPinnedProject(ctx context.Context, projectID string) {
+	pinnedProjects.mu.Lock()
+	if projectID == "" {
+		delete(pinnedProjects.byOwner, actionOwner(ctx))
+	} else {
+		pinnedProjects.byOwner[actionOwner(ctx)] = projectID
+	}
+	pinnedProjects.mu.Unlock()
+	shared.SessionStateChanged()
+}
+
+// accessibleProjectIDs lists the projects of every organization of the session's API key
+func accessibleProjectIDs(ctx context.Context, client *sdk.Handler) ([]string, error) {
+	owner := actionOwner(ctx)
+	onlyProjects.mu.Lock()
+	cached, ok := onlyProjects.byOwner[owner]
+	onlyProjects.mu.Unlock()
+	if ok && time.Since(cached.checked) < onlyProjectTTL {
+		return cached.ids, nil
+	}
+
+	identity, err := shared.ResolveIdentity(ctx, client, sessionAPIKey(ctx))
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, org := range identity.Organizations {
+		resp, err := client.PostProjectSearch(ctx, body.EsFilter{
+			Search: []body.EsSearchItem{
+				{Name: "clientId", Operator: "eq", Value: types.String(org.ID)},
+			},
+		})
+		if err != nil {
+			return nil, shared.WrapAPIError(err, "Failed to search projects")
+		}
+		projects, err := resp.Output()
+		if err != nil {
+			return nil, shared.WrapAPIError(err, "Failed to search projects")
+		}
+		for _, project := range projects.Items {
+			ids = append(ids, string(project.Id))
+		}
+	}
+
+	onlyProjects.mu.Lock()
+	onlyProjects.byOwner[owner] = accessibleProjects{ids: ids, checked: time.Now()}
+	onlyProjects.mu.Unlock()
+	return ids, nil
+}
+
+// RegisterCurrentProject registers the current_project tool
+func RegisterCurrentProject() {
+	shared.RegisterSessionState("pinned_projects", shared.MapSessionState(&pinnedProjects.mu, pinnedProjects.byOwner))
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "current_project",
+		Description: toolDescription("current_project"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"pin": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Project ID to use for this session when tools get no project_id; takes precedence over $projectId",
+					"pattern":     "^[A-Za-z0-9_-]+$",
+				},
+				"unpin": map[string]interface{}{
+					"type":        "boolean",
+					"description": "OPTIONAL: Forget the pinned project (default: false)",
+					"default":     false,
+				},
+			},
+			"additionalProperties": false,
+		},
+		Annotations: shared.Mutating(false, true),
+		Output:      currentProjectResult{},
+		Handler:     handleCurrentProject,
+	})
+}
+
+// currentProjectResult is the result of current_project. ProjectID is null when no
+// project could be resolved.
+type currentProjectResult struct {
+	Precedence  []string `json:"precedence"`
+	Pinned      string   `json:"pinned"`
+	Env         string   `json:"env"`
+	ProjectID   *string  `json:"project_id"`
+	Source      string   `json:"source,omitempty"`
+	ProjectName string   `json:"project_name,omitempty"`
+	Error       string   `json:"error,omitempty"`
+	Message     string   `json:"message,omitempty"`
+}
+
+func handleCurrentProject(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+
+	pin, _ := args["pin"].(string)
+	unpin, _ := args["unpin"].(bool)
+	if pin != "" && unpin {
+		return nil, shared.InvalidArgument("Use either pin or unpin, not both")
+	}
+
+	var message string
+	switch {
+	case pin != "":
+		// Pinning checks access, so later calls don't fail on a mistyped ID
+		project, err := searchProject(ctx, client, pin)
+		if err != nil {
+			return nil, err
+		}
+		setPinnedProject(ctx, pin)
+		message = fmt.Sprintf("Pinned project '%s'; tools use it when called without project_id.", project.Name.Native())
+	case unpin:
+		setPinnedProject(ctx, "")
+		message = "Unpinned the project."
+	}
+
+	result := currentProjectResult{
+		Precedence: []string{"project_id argument", "pinned project (current_project pin)", "$projectId env variable", "the only project the API key can access"},
+		Pinned:     pinnedProject(ctx),
+		Env:        envProjectID(ctx),
+		Message:    message,
+	}
+	resolved, err := resolveProject(ctx, client, map[string]interface{}{})
+	if err != nil {
+		result.Error = err.Error()
+	} else {
+		result.ProjectID = &resolved.ID
+		result.Source = resolved.Source
+		project, err := searchProject(ctx, client, resolved.ID)
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			result.ProjectName = project.Name.Native()
+		}
+	}
+	return result, nil
+}
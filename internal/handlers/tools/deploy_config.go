@@ -0,0 +1,198 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/dto/input/body"
+	"github.com/zeropsio/zerops-go/dto/input/path"
+	"github.com/zeropsio/zerops-go/dto/output"
+	"github.com/zeropsio/zerops-go/sdk"
+	"github.com/zeropsio/zerops-go/types"
+	"github.com/zeropsio/zerops-go/types/uuid"
+)
+
+// deployConfigSearchLimit is how many recent processes of a service are searched for app versions
+const deployConfigSearchLimit = 100
+
+// RegisterDeployConfig registers the get_deployment_config tool
+func RegisterDeployConfig() {
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "get_deployment_config",
+		Description: toolDescription("get_deployment_config"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"app_version_id": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: App version ID (e.g. active_version.id from discovery)",
+					"pattern":     "^[A-Za-z0-9_-]+$",
+				},
+				"service_id": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Service ID; returns the version live at 'at'",
+					"pattern":     "^[A-Za-z0-9_-]+$",
+				},
+				"at": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: RFC3339 point in time for service_id (default: now)",
+				},
+				"timezone": timezoneProperty(),
+			},
+			"additionalProperties": false,
+		},
+		Annotations: shared.ReadOnly(),
+		Output:      deploymentConfig{},
+		Handler:     handleGetDeploymentConfig,
+	})
+}
+
+func handleGetDeploymentConfig(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+
+	display, err := resolveDisplayFormat(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	appVersionID, _ := args["app_version_id"].(string)
+	serviceID, _ := args["service_id"].(string)
+	if appVersionID == "" && serviceID == "" {
+		return nil, shared.InvalidArgument("Either app_version_id or service_id is required")
+	}
+
+	if appVersionID != "" {
+		// The config is only exposed on the processes that built and deployed the version
+		if serviceID == "" {
+			versionResp, err := client.GetAppVersion(ctx, path.AppVersionId{Id: uuid.AppVersionId(appVersionID)})
+			if err != nil {
+				return nil, shared.WrapAPIError(err, "Failed to get app version")
+			}
+			version, err := versionResp.Output()
+			if err != nil {
+				return nil, shared.WrapAPIError(err, "Failed to parse app version")
+			}
+			serviceID = string(version.ServiceStackId)
+		}
+
+		versions, err := deployedAppVersions(ctx, client, serviceID)
+		if err != nil {
+			return nil, err
+		}
+		for _, version := range versions {
+			if string(version.Id) == appVersionID {
+				return deploymentConfigResult(version, display), nil
+			}
+		}
+		return nil, shared.NotFound("No deploy process found for app version '%s' in the last %d processes of the service", appVersionID, deployConfigSearchLimit)
+	}
+
+	at := time.Now()
+	if value, _ := args["at"].(string); value != "" {
+		at, err = time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, shared.InvalidArgument("at must be an RFC3339 timestamp, got '%s'", value)
+		}
+	}
+
+	versions, err := deployedAppVersions(ctx, client, serviceID)
+	if err != nil {
+		return nil, err
+	}
+	// Versions are newest first; the live one is the latest activated before 'at'
+	for _, version := range versions {
+		if activated, ok := version.ActivationDate.Get(); ok && !activated.Native().After(at) {
+			result := deploymentConfigResult(version, display)
+			result.At = formatTimestamp(at, display)
+			return result, nil
+		}
+	}
+	return nil, shared.NotFound("No deployment of service '%s' was live at %s", serviceID, at.UTC().Format(time.RFC3339))
+}
+
+// deployedAppVersions returns the app versions attached to the service's recent processes,
+// newest first and without duplicates
+func deployedAppVersions(ctx context.Context, client *sdk.Handler, serviceID string) ([]output.AppVersionJsonObject, error) {
+	filter := body.EsFilter{
+		Search: []body.EsSearchItem{
+			{
+				Name:     "serviceStackId",
+				Operator: "eq",
+				Value:    types.String(serviceID),
+			},
+		},
+		Sort: []body.EsSortItem{
+			{Name: "created", Ascending: types.NewBoolNull(false)},
+		},
+		Limit: types.NewIntNull(deployConfigSearchLimit),
+	}
+
+	processResp, err := client.PostProcessSearch(ctx, filter)
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to search processes")
+	}
+	processOutput, err := processResp.Output()
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to parse processes")
+	}
+
+	seen := make(map[string]bool)
+	var versions []output.AppVersionJsonObject
+	for _, process := range processOutput.Items {
+		if process.AppVersion == nil || seen[string(process.AppVersion.Id)] {
+			continue
+		}
+		seen[string(process.AppVersion.Id)] = true
+		versions = append(versions, *process.AppVersion)
+	}
+	return versions, nil
+}
+
+// deploymentConfig is the result of get_deployment_config
+type deploymentConfig struct {
+	AppVersionID string `json:"app_version_id"`
+	Source       string `json:"source"`
+	Sequence     int    `json:"sequence"`
+	ServiceID    string `json:"service_id,omitempty"`
+	Status       string `json:"status,omitempty"`
+	Created      string `json:"created,omitempty"`
+	Activated    string `json:"activated,omitempty"`
+	Name         string `json:"name,omitempty"`
+	At           string `json:"at,omitempty"`
+	ZeropsYml    string `json:"zerops_yml,omitempty"`
+	Message      string `json:"message,omitempty"`
+}
+
+func deploymentConfigResult(version output.AppVersionJsonObject, display *displayFormat) deploymentConfig {
+	result := deploymentConfig{
+		AppVersionID: string(version.Id),
+		Source:       string(version.Source),
+		Sequence:     version.Sequence.Native(),
+	}
+	if serviceID, ok := version.ServiceStackId.Get(); ok {
+		result.ServiceID = string(serviceID)
+	}
+	if version.Status != nil {
+		result.Status = string(*version.Status)
+	}
+	if created, ok := version.Created.Get(); ok {
+		result.Created = formatTimestamp(created.Native(), display)
+	}
+	if activated, ok := version.ActivationDate.Get(); ok {
+		result.Activated = formatTimestamp(activated.Native(), display)
+	}
+	if name, ok := version.Name.Get(); ok {
+		result.Name = name.Native()
+	}
+
+	if config, ok := version.ConfigContent.Get(); ok && config.Native() != "" {
+		result.ZeropsYml = config.Native()
+	} else {
+		result.Message = fmt.Sprintf("No zerops.yml is stored for app version %s.", version.Id)
+	}
+	return result
+}
@@ -0,0 +1,209 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/dto/input/path"
+	"github.com/zeropsio/zerops-go/sdk"
+	"github.com/zeropsio/zerops-go/types/uuid"
+)
+
+// Deploy impact thresholds
+const (
+	defaultImpactWindow = 30 * time.Minute
+	maxImpactWindow     = 4 * time.Hour
+	impactLogLimit      = 1000
+	// regressionRatio is how much higher the error rate after a deploy must be to flag it
+	regressionRatio = 2.0
+	// minRegressionErrors avoids flagging a handful of errors as a regression
+	minRegressionErrors = 5
+)
+
+// RegisterDeployImpact registers the deploy_impact tool
+func RegisterDeployImpact() {
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "deploy_impact",
+		Description: toolDescription("deploy_impact"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"service_id": map[string]interface{}{
+					"type":        "string",
+					"description": "REQUIRED: Service ID from discovery tool",
+					"pattern":     "^[A-Za-z0-9_-]+$",
+				},
+				"window_minutes": map[string]interface{}{
+					"type":        "integer",
+					"description": "OPTIONAL: Minutes to compare before and after the deploy (5-240, default: 30)",
+					"minimum":     5,
+					"maximum":     240,
+					"default":     30,
+				},
+			},
+			"required":             []string{"service_id"},
+			"additionalProperties": false,
+		},
+		Annotations: shared.ReadOnly(),
+		Output:      deployImpactResult{},
+		Handler:     handleDeployImpact,
+	})
+}
+
+// deployImpactResult is the result of deploy_impact
+type deployImpactResult struct {
+	ServiceID       string       `json:"service_id"`
+	ServiceName     string       `json:"service_name"`
+	AppVersionID    string       `json:"app_version_id"`
+	DeployedAt      string       `json:"deployed_at"`
+	WindowMinutes   int          `json:"window_minutes"`
+	Verdict         string       `json:"verdict"`
+	Before          *errorWindow `json:"before,omitempty"`
+	After           *errorWindow `json:"after,omitempty"`
+	Note            string       `json:"note,omitempty"`
+	UnparsedEntries int          `json:"unparsed_entries,omitempty"`
+	Message         string       `json:"message"`
+}
+
+// errorWindow counts the error log lines on one side of a deploy
+type errorWindow struct {
+	Errors       int     `json:"errors"`
+	Minutes      int     `json:"minutes"`
+	ErrorsPerMin float64 `json:"errors_per_min"`
+}
+
+func handleDeployImpact(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+
+	serviceID, ok := args["service_id"].(string)
+	if !ok || serviceID == "" {
+		return nil, shared.InvalidArgument("Service ID is required")
+	}
+
+	window := defaultImpactWindow
+	if w, ok := args["window_minutes"].(float64); ok && w > 0 {
+		window = time.Duration(w) * time.Minute
+		if window > maxImpactWindow {
+			window = maxImpactWindow
+		}
+	}
+
+	serviceResp, err := client.GetServiceStack(ctx, path.ServiceStackId{Id: uuid.ServiceStackId(serviceID)})
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to get service")
+	}
+
+	service, err := serviceResp.Output()
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to get service")
+	}
+
+	if service.ActiveAppVersion == nil {
+		return nil, shared.NotFound("Service '%s' has no active deployment", service.Name.Native())
+	}
+
+	deployedAt := service.ActiveAppVersion.LastUpdate.Native()
+	if activation, ok := service.ActiveAppVersion.ActivationDate.Get(); ok {
+		deployedAt = activation.Native()
+	}
+
+	now := time.Now()
+	afterWindow := window
+	if elapsed := now.Sub(deployedAt); elapsed < afterWindow {
+		afterWindow = elapsed
+	}
+
+	result := deployImpactResult{
+		ServiceID:     serviceID,
+		ServiceName:   service.Name.Native(),
+		AppVersionID:  string(service.ActiveAppVersion.Id),
+		DeployedAt:    deployedAt.UTC().Format(time.RFC3339),
+		WindowMinutes: int(window.Minutes()),
+	}
+
+	if afterWindow < time.Minute {
+		result.Verdict = "insufficient_data"
+		result.Message = "Deployment is less than a minute old. Try again in a few minutes."
+		return result, nil
+	}
+
+	logs, err := fetchLogs(ctx, client, service.ProjectId, logQuery{
+		ServiceID:   serviceID,
+		Limit:       impactLogLimit,
+		Facility:    getFacilityCode("APPLICATION"),
+		MinSeverity: "error",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	beforeStart := deployedAt.Add(-window)
+	afterEnd := deployedAt.Add(afterWindow)
+	before, after, unparsed := 0, 0, 0
+	var oldest time.Time
+	for _, entry := range logs {
+		ts, err := time.Parse(time.RFC3339Nano, entry.Timestamp)
+		if err != nil {
+			unparsed++
+			continue
+		}
+		if oldest.IsZero() || ts.Before(oldest) {
+			oldest = ts
+		}
+		switch {
+		case !ts.Before(beforeStart) && ts.Before(deployedAt):
+			before++
+		case !ts.Before(deployedAt) && !ts.After(afterEnd):
+			after++
+		}
+	}
+
+	beforeRate := float64(before) / window.Minutes()
+	afterRate := float64(after) / afterWindow.Minutes()
+
+	verdict := "no_significant_change"
+	switch {
+	case after >= minRegressionErrors && afterRate >= beforeRate*regressionRatio:
+		verdict = "likely_regression"
+	case before >= minRegressionErrors && afterRate <= beforeRate/regressionRatio:
+		verdict = "improved"
+	}
+
+	result.Before = &errorWindow{
+		Errors:       before,
+		Minutes:      int(window.Minutes()),
+		ErrorsPerMin: roundRate(beforeRate),
+	}
+	result.After = &errorWindow{
+		Errors:       after,
+		Minutes:      int(afterWindow.Minutes()),
+		ErrorsPerMin: roundRate(afterRate),
+	}
+	result.Verdict = verdict
+
+	// With a full page of logs the oldest entries may have been cut off
+	if len(logs) >= impactLogLimit && oldest.After(beforeStart) {
+		result.Note = fmt.Sprintf("Log limit reached; only errors since %s were counted, so the before window is incomplete.", oldest.UTC().Format(time.RFC3339))
+	}
+	result.UnparsedEntries = unparsed
+
+	switch verdict {
+	case "likely_regression":
+		result.Message = "Error rate increased significantly after the deploy. Inspect get_service_logs with minimum_severity=error."
+	case "improved":
+		result.Message = "Error rate dropped after the deploy."
+	default:
+		result.Message = "No significant change in error rate after the deploy."
+	}
+
+	return result, nil
+}
+
+// roundRate rounds a per-minute rate to two decimals
+func roundRate(rate float64) float64 {
+	return float64(int(rate*100+0.5)) / 100
+}
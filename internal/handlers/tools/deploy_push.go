@@ -0,0 +1,462 @@
+package tools
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/dto/input/body"
+	"github.com/zeropsio/zerops-go/dto/input/path"
+	"github.com/zeropsio/zerops-go/sdk"
+	"github.com/zeropsio/zerops-go/types"
+	"github.com/zeropsio/zerops-go/types/uuid"
+)
+
+// zcliPushTimeout bounds a deploy_push that falls back to zcli
+const zcliPushTimeout = 15 * time.Minute
+
+// RegisterDeployPush registers the deploy_push tool
+func RegisterDeployPush() {
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "deploy_push",
+		Description: toolDescription("deploy_push"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"service_id": map[string]interface{}{
+					"type":        "string",
+					"description": "REQUIRED: Service ID from discovery tool",
+					"pattern":     "^[A-Za-z0-9_-]+$",
+				},
+				"working_dir": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Source directory to deploy (default: current directory)",
+				},
+				"setup": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: zerops.yml setup to build (default: the service hostname)",
+				},
+				"zerops_yml_path": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Path to zerops.yml (default: zerops.yml or zerops.yaml in working_dir)",
+				},
+				"version_name": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Name of the new app version, e.g. a git tag",
+				},
+				"use_zcli": map[string]interface{}{
+					"type":        "boolean",
+					"description": "OPTIONAL: Deploy with 'zcli push' instead of the API (default: false)",
+				},
+				"expected_last_update": expectedLastUpdateProperty(),
+			},
+			"required":             []string{"service_id"},
+			"additionalProperties": false,
+		},
+		Annotations: shared.Mutating(true, false),
+		Output:      deployPushResult{},
+		Handler:     handleDeployPush,
+	})
+}
+
+// deployPushResult is the result of deploy_push. API uploads start a build and report the
+// archive; zcli pushes finish before returning and report the zcli output.
+type deployPushResult struct {
+	Status       string `json:"status"`
+	Method       string `json:"method"`
+	ServiceID    string `json:"service_id"`
+	ServiceName  string `json:"service_name"`
+	ProcessID    string `json:"process_id,omitempty"`
+	AppVersionID string `json:"app_version_id,omitempty"`
+	Setup        string `json:"setup"`
+	Files        int    `json:"files,omitempty"`
+	ArchiveBytes int64  `json:"archive_bytes,omitempty"`
+	ArchiveSize  string `json:"archive_size,omitempty"`
+	Output       string `json:"output,omitempty"`
+	Message      string `json:"message"`
+	shared.Suggestions
+}
+
+func handleDeployPush(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+
+	serviceID, ok := args["service_id"].(string)
+	if !ok || serviceID == "" {
+		return nil, shared.InvalidArgument("Service ID is required")
+	}
+	if httpMode, _ := ctx.Value("httpMode").(bool); httpMode {
+		return nil, shared.InvalidArgument("deploy_push is not available in HTTP mode: the server's filesystem is not yours. Deploy with zcli or a git integration.")
+	}
+
+	workingDir, _ := args["working_dir"].(string)
+	if workingDir == "" {
+		workingDir = "."
+	}
+	workingDir, err := filepath.Abs(workingDir)
+	if err != nil {
+		return nil, shared.InvalidArgument("Invalid working_dir '%s'", workingDir)
+	}
+	if err := shared.CheckPathInRoots(ctx, workingDir); err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(workingDir); err != nil || !info.IsDir() {
+		return nil, shared.InvalidArgument("working_dir '%s' is not a directory", workingDir)
+	}
+
+	yamlPath, _ := args["zerops_yml_path"].(string)
+	zeropsYaml, yamlPath, err := readDeployZeropsYml(ctx, workingDir, yamlPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkServiceUnchanged(ctx, client, args, serviceID); err != nil {
+		return nil, err
+	}
+
+	serviceResp, err := client.GetServiceStack(ctx, path.ServiceStackId{Id: uuid.ServiceStackId(serviceID)})
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to get service")
+	}
+	service, err := serviceResp.Output()
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to get service")
+	}
+
+	setup, _ := args["setup"].(string)
+	if setup == "" {
+		setup = service.Name.Native()
+	}
+	if err := checkDeploySetup(zeropsYaml, yamlPath, setup); err != nil {
+		return nil, err
+	}
+	versionName, _ := args["version_name"].(string)
+	display, err := resolveDisplayFormat(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	recordSnapshot(ctx, client, string(service.ProjectId), "deploy_push", serviceID)
+
+	if useZcli, _ := args["use_zcli"].(bool); useZcli {
+		result, err := deployWithZcli(ctx, serviceID, service.Name.Native(), workingDir, yamlPath, setup, versionName)
+		if err == nil {
+			shared.ExportEvent("deploy_config", deployConfigEvent(string(service.ProjectId), serviceID, service.Name.Native(), "zcli", setup, "", zeropsYaml))
+		}
+		return result, err
+	}
+
+	shared.ReportProgress(ctx, 0, 3, "Packing "+workingDir)
+	archive, files, err := archiveSourceDir(workingDir)
+	if err != nil {
+		return nil, shared.NewToolError(shared.ErrInvalidArgument, "Failed to pack '%s': %v", workingDir, err)
+	}
+	defer os.Remove(archive.Name())
+	defer archive.Close()
+	info, err := archive.Stat()
+	if err != nil {
+		return nil, shared.NewToolError(shared.ErrInvalidArgument, "Failed to pack '%s': %v", workingDir, err)
+	}
+
+	versionBody := body.PostAppVersion{ServiceStackId: uuid.ServiceStackId(serviceID)}
+	if versionName != "" {
+		versionBody.Name = types.NewStringNull(versionName)
+	}
+	versionResp, err := client.PostAppVersion(ctx, versionBody)
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to create app version")
+	}
+	version, err := versionResp.Output()
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to create app version")
+	}
+
+	shared.ReportProgress(ctx, 1, 3, fmt.Sprintf("Uploading %d files (%s)", files, formatSize(float64(info.Size()), display)))
+	uploadResp, err := client.PutAppVersionUpload(ctx, path.AppVersionId{Id: version.Id}, archive)
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to upload source package")
+	}
+	if _, err := uploadResp.Output(); err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to upload source package")
+	}
+
+	shared.ReportProgress(ctx, 2, 3, "Starting build pipeline")
+	buildResp, err := client.PutAppVersionBuildAndDeploy(ctx, path.AppVersionId{Id: version.Id}, body.PutAppVersionBuildAndDeploy{
+		ZeropsYaml:      types.NewMediumText(base64.StdEncoding.EncodeToString(zeropsYaml)),
+		ZeropsYamlSetup: types.NewStringNull(setup),
+	})
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to start build")
+	}
+	process, err := buildResp.Output()
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to start build")
+	}
+	forgetServiceStamp(ctx, serviceID)
+	shared.ReportProgress(ctx, 3, 3, "Build started")
+	shared.ExportEvent("deploy_config", deployConfigEvent(string(service.ProjectId), serviceID, service.Name.Native(), "api", setup, string(version.Id), zeropsYaml))
+
+	result := deployPushResult{
+		Status:       "deploy_started",
+		Method:       "api",
+		ServiceID:    serviceID,
+		ServiceName:  service.Name.Native(),
+		ProcessID:    string(process.Id),
+		AppVersionID: string(version.Id),
+		Setup:        setup,
+		Files:        files,
+		ArchiveBytes: info.Size(),
+		ArchiveSize:  formatSize(float64(info.Size()), display),
+		Message:      fmt.Sprintf("Uploaded %d files to %s and started the build. Use wait_for_process to monitor progress.", files, service.Name.Native()),
+	}
+	result.SuggestNext(
+		shared.WaitForProcess(string(process.Id), "Wait until the build and deploy finish"),
+		shared.NextCall{
+			Tool:      "get_service_logs",
+			Arguments: map[string]interface{}{"service_id": serviceID, "show_build_logs": true},
+			Purpose:   "Read the build log if the deploy fails",
+		},
+	)
+	return result, nil
+}
+
+// deployConfigEvent records which zerops.yml a deploy used for the event exporter. Only its
+// hash and size are kept, the content may hold env values; get_deployment_config and the
+// zerops://app-version/{id}/deploy-config resource return it by app version ID.
+func deployConfigEvent(projectID, serviceID, serviceName, method, setup, appVersionID string, zeropsYaml []byte) map[string]interface{} {
+	sum := sha256.Sum256(zeropsYaml)
+	event := map[string]interface{}{
+		"project_id":        projectID,
+		"service_id":        serviceID,
+		"service_name":      serviceName,
+		"method":            method,
+		"setup":             setup,
+		"zerops_yml_sha256": hex.EncodeToString(sum[:]),
+		"zerops_yml_bytes":  len(zeropsYaml),
+	}
+	if appVersionID != "" {
+		event["app_version_id"] = appVersionID
+	}
+	return event
+}
+
+// readDeployZeropsYml reads the zerops.yml to deploy with, from yamlPath or the working directory
+func readDeployZeropsYml(ctx context.Context, workingDir, yamlPath string) ([]byte, string, error) {
+	candidates := []string{filepath.Join(workingDir, "zerops.yml"), filepath.Join(workingDir, "zerops.yaml")}
+	if yamlPath != "" {
+		absolute, err := filepath.Abs(yamlPath)
+		if err != nil {
+			return nil, "", shared.InvalidArgument("Invalid zerops_yml_path '%s'", yamlPath)
+		}
+		if err := shared.CheckPathInRoots(ctx, absolute); err != nil {
+			return nil, "", err
+		}
+		candidates = []string{absolute}
+	}
+	for _, candidate := range candidates {
+		data, err := os.ReadFile(candidate)
+		if err == nil {
+			return data, candidate, nil
+		}
+		if !os.IsNotExist(err) {
+			return nil, "", shared.NewToolError(shared.ErrInvalidArgument, "Failed to read '%s': %v", candidate, err)
+		}
+	}
+	return nil, "", shared.NotFound("No zerops.yml found at %s. Create one (get_knowledge has examples) or pass zerops_yml_path.", strings.Join(candidates, " or "))
+}
+
+// checkDeploySetup fails when the zerops.yml does not pass deploy_validate or has no setup with
+// the given name, so a broken file is rejected before the upload
+func checkDeploySetup(zeropsYaml []byte, yamlPath, setup string) error {
+	setups, issues := validateZeropsYml(zeropsYaml)
+	var errors []string
+	for _, issue := range issues {
+		if issue.Severity == "error" {
+			errors = append(errors, issue.String())
+		}
+	}
+	if len(errors) > 0 {
+		if len(errors) > 5 {
+			errors = append(errors[:5], fmt.Sprintf("and %d more (see deploy_validate)", len(errors)-5))
+		}
+		return shared.InvalidArgument("%s is invalid:\n%s", yamlPath, strings.Join(errors, "\n"))
+	}
+	if containsString(setups, setup) {
+		return nil
+	}
+	return shared.InvalidArgument("%s has no setup '%s' (available: %s); pass setup", yamlPath, setup, strings.Join(setups, ", "))
+}
+
+// archiveSourceDir packs dir into a temporary tar.gz the way zcli does and returns it rewound,
+// with the number of files packed. The caller removes the file.
+func archiveSourceDir(dir string) (*os.File, int, error) {
+	ignore, err := readDeployIgnore(dir)
+	if err != nil {
+		return nil, 0, err
+	}
+	archive, err := os.CreateTemp("", "zerops-deploy-*.tar.gz")
+	if err != nil {
+		return nil, 0, err
+	}
+	fail := func(err error) (*os.File, int, error) {
+		archive.Close()
+		os.Remove(archive.Name())
+		return nil, 0, err
+	}
+
+	gz := gzip.NewWriter(archive)
+	tw := tar.NewWriter(gz)
+	files := 0
+	err = filepath.WalkDir(dir, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, filePath)
+		if err != nil || rel == "." {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if entry.Name() == ".git" || deployIgnored(ignore, rel) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		link := ""
+		if info.Mode()&fs.ModeSymlink != 0 {
+			if link, err = os.Readlink(filePath); err != nil {
+				return err
+			}
+		}
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		header.Name = rel
+		if entry.IsDir() {
+			header.Name += "/"
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		file, err := os.Open(filePath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		if _, err := io.Copy(tw, file); err != nil {
+			return err
+		}
+		files++
+		return nil
+	})
+	if err != nil {
+		return fail(err)
+	}
+	if err := tw.Close(); err != nil {
+		return fail(err)
+	}
+	if err := gz.Close(); err != nil {
+		return fail(err)
+	}
+	if _, err := archive.Seek(0, io.SeekStart); err != nil {
+		return fail(err)
+	}
+	return archive, files, nil
+}
+
+// readDeployIgnore reads the patterns of .deployignore in dir, if there is one
+func readDeployIgnore(dir string) ([]string, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ".deployignore"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var patterns []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, strings.TrimSuffix(line, "/"))
+	}
+	return patterns, nil
+}
+
+// deployIgnored matches rel against .deployignore patterns: a pattern with a slash matches the
+// path from the source root, one without matches any path element
+func deployIgnored(patterns []string, rel string) bool {
+	for _, pattern := range patterns {
+		if strings.Contains(pattern, "/") {
+			if ok, _ := filepath.Match(strings.TrimPrefix(pattern, "/"), rel); ok {
+				return true
+			}
+			continue
+		}
+		if ok, _ := filepath.Match(pattern, filepath.Base(rel)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// deployWithZcli runs 'zcli push' for users who prefer the CLI's own packing and upload
+func deployWithZcli(ctx context.Context, serviceID, serviceName, workingDir, yamlPath, setup, versionName string) (interface{}, error) {
+	zcli, err := exec.LookPath("zcli")
+	if err != nil {
+		return nil, shared.NotFound("zcli is not installed or not on PATH; deploy without use_zcli to upload through the API")
+	}
+
+	cmdArgs := []string{"push", "--serviceId", serviceID, "--setup", setup, "--workingDir", workingDir, "--zeropsYamlPath", yamlPath}
+	if versionName != "" {
+		cmdArgs = append(cmdArgs, "--versionName", versionName)
+	}
+	ctx, cancel := context.WithTimeout(ctx, zcliPushTimeout)
+	defer cancel()
+	shared.ReportProgress(ctx, 0, 1, "Running zcli push")
+	out, err := exec.CommandContext(ctx, zcli, cmdArgs...).CombinedOutput()
+	output := strings.TrimSpace(string(out))
+	if len(output) > 4000 {
+		output = "..." + output[len(output)-4000:]
+	}
+	if err != nil {
+		return nil, shared.NewToolError(shared.ErrAPIUnavailable, "zcli push failed: %v\n%s", err, output)
+	}
+	forgetServiceStamp(ctx, serviceID)
+
+	return deployPushResult{
+		Status:      "deployed",
+		Method:      "zcli",
+		ServiceID:   serviceID,
+		ServiceName: serviceName,
+		Setup:       setup,
+		Output:      output,
+		Message:     fmt.Sprintf("zcli push to %s finished. Check get_service_logs for the first run.", serviceName),
+	}, nil
+}
@@ -0,0 +1,553 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/sdk"
+	"gopkg.in/yaml.v3"
+)
+
+// yamlKind is the shape a zerops.yml value must have
+type yamlKind int
+
+const (
+	yamlObject yamlKind = iota
+	yamlList
+	yamlString
+	yamlInt
+	yamlBool
+	// yamlScalarValue is any string, number or boolean, e.g. an env variable value
+	yamlScalarValue
+	// yamlStringOrList is a string or a list of strings, e.g. deployFiles
+	yamlStringOrList
+	// yamlScalarMap is an object with free keys and scalar values, e.g. envVariables
+	yamlScalarMap
+	// yamlAnything is not checked further
+	yamlAnything
+)
+
+var yamlKindNames = map[yamlKind]string{
+	yamlObject:       "an object",
+	yamlList:         "a list",
+	yamlString:       "a string",
+	yamlInt:          "an integer",
+	yamlBool:         "true or false",
+	yamlScalarValue:  "a string, number or boolean",
+	yamlStringOrList: "a string or a list of strings",
+	yamlScalarMap:    "an object of key: value pairs",
+}
+
+// yamlField describes the allowed shape of one zerops.yml value
+type yamlField struct {
+	kind     yamlKind
+	fields   map[string]*yamlField
+	required []string
+	items    *yamlField
+	enum     []string
+	// intRange bounds yamlInt values when set
+	intRange [2]int
+}
+
+func yamlObjectOf(required []string, fields map[string]*yamlField) *yamlField {
+	return &yamlField{kind: yamlObject, fields: fields, required: required}
+}
+
+func yamlListOf(items *yamlField) *yamlField { return &yamlField{kind: yamlList, items: items} }
+
+func yamlValue(kind yamlKind) *yamlField { return &yamlField{kind: kind} }
+
+func yamlEnum(values ...string) *yamlField { return &yamlField{kind: yamlString, enum: values} }
+
+func yamlIntRange(low, high int) *yamlField {
+	return &yamlField{kind: yamlInt, intRange: [2]int{low, high}}
+}
+
+// zeropsProbeSchema is a readinessCheck or healthCheck block
+func zeropsProbeSchema() *yamlField {
+	return yamlObjectOf(nil, map[string]*yamlField{
+		"httpGet": yamlObjectOf([]string{"port", "path"}, map[string]*yamlField{
+			"port":   yamlIntRange(10, 65435),
+			"path":   yamlValue(yamlString),
+			"host":   yamlValue(yamlString),
+			"scheme": yamlEnum("http", "https"),
+		}),
+		"exec": yamlObjectOf([]string{"command"}, map[string]*yamlField{
+			"command": yamlValue(yamlString),
+		}),
+		"failureTimeout":    yamlValue(yamlInt),
+		"disconnectTimeout": yamlValue(yamlInt),
+		"recoveryTimeout":   yamlValue(yamlInt),
+		"execPeriod":        yamlValue(yamlInt),
+		"retryPeriod":       yamlValue(yamlInt),
+	})
+}
+
+// zeropsYmlSchema is the structure of zerops.yml as documented for the build and run pipeline
+var zeropsYmlSchema = yamlObjectOf([]string{"zerops"}, map[string]*yamlField{
+	"zerops": yamlListOf(yamlObjectOf([]string{"setup"}, map[string]*yamlField{
+		"setup":   yamlValue(yamlString),
+		"extends": yamlValue(yamlString),
+		"build": yamlObjectOf(nil, map[string]*yamlField{
+			"base":            yamlValue(yamlStringOrList),
+			"os":              yamlEnum("alpine", "ubuntu"),
+			"prepareCommands": yamlListOf(yamlValue(yamlString)),
+			"buildCommands":   yamlListOf(yamlValue(yamlString)),
+			"deployFiles":     yamlValue(yamlStringOrList),
+			"cache":           yamlValue(yamlAnything),
+			"addToRunPrepare": yamlValue(yamlStringOrList),
+			"envVariables":    yamlValue(yamlScalarMap),
+		}),
+		"deploy": yamlObjectOf(nil, map[string]*yamlField{
+			"readinessCheck":    zeropsProbeSchema(),
+			"temporaryShutdown": yamlValue(yamlBool),
+		}),
+		"run": yamlObjectOf(nil, map[string]*yamlField{
+			"base":            yamlValue(yamlString),
+			"os":              yamlEnum("alpine", "ubuntu"),
+			"prepareCommands": yamlListOf(yamlValue(yamlString)),
+			"initCommands":    yamlListOf(yamlValue(yamlString)),
+			"start":           yamlValue(yamlString),
+			"startCommands": yamlListOf(yamlObjectOf([]string{"command"}, map[string]*yamlField{
+				"command":      yamlValue(yamlString),
+				"name":         yamlValue(yamlString),
+				"workingDir":   yamlValue(yamlString),
+				"initCommands": yamlListOf(yamlValue(yamlString)),
+			})),
+			"ports": yamlListOf(yamlObjectOf([]string{"port"}, map[string]*yamlField{
+				"port":        yamlIntRange(10, 65435),
+				"protocol":    yamlEnum("TCP", "UDP"),
+				"httpSupport": yamlValue(yamlBool),
+			})),
+			"envVariables": yamlValue(yamlScalarMap),
+			"envReplace": yamlObjectOf(nil, map[string]*yamlField{
+				"target":    yamlValue(yamlStringOrList),
+				"delimiter": yamlValue(yamlStringOrList),
+			}),
+			"healthCheck":    zeropsProbeSchema(),
+			"documentRoot":   yamlValue(yamlString),
+			"siteConfigPath": yamlValue(yamlString),
+			"workingDir":     yamlValue(yamlString),
+			"crontab": yamlListOf(yamlObjectOf([]string{"command", "timing"}, map[string]*yamlField{
+				"command":       yamlValue(yamlString),
+				"timing":        yamlValue(yamlString),
+				"workingDir":    yamlValue(yamlString),
+				"allContainers": yamlValue(yamlBool),
+			})),
+			"routing": yamlValue(yamlAnything),
+		}),
+	})),
+})
+
+// yamlIssue is one problem found in a zerops.yml or import YAML
+type yamlIssue struct {
+	Severity string `json:"severity"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Path     string `json:"path"`
+	Message  string `json:"message"`
+}
+
+func (i yamlIssue) String() string {
+	return fmt.Sprintf("line %d: %s: %s", i.Line, i.Path, i.Message)
+}
+
+// checkYamlDocument parses content and checks it against schema. root is nil when the
+// content is not valid YAML.
+func checkYamlDocument(content []byte, schema *yamlField) (*yaml.Node, []yamlIssue) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		line := 0
+		message := err.Error()
+		// yaml.v3 reports "yaml: line N: ..."
+		if rest, ok := strings.CutPrefix(message, "yaml: line "); ok {
+			if number, after, ok := strings.Cut(rest, ": "); ok {
+				line, _ = strconv.Atoi(number)
+				message = after
+			}
+		}
+		return nil, []yamlIssue{{Severity: "error", Line: line, Path: displayYamlPath(""), Message: "invalid YAML: " + message}}
+	}
+	if len(document.Content) == 0 {
+		return nil, []yamlIssue{{Severity: "error", Line: 1, Path: displayYamlPath(""), Message: "the file is empty"}}
+	}
+
+	var issues []yamlIssue
+	root := document.Content[0]
+	checkYamlNode(root, schema, "", &issues)
+	return root, issues
+}
+
+// validateZeropsYml checks content against zeropsYmlSchema and the cross-field rules zcli
+// enforces, without calling zcli. It returns the setup names and the issues, errors first.
+func validateZeropsYml(content []byte) ([]string, []yamlIssue) {
+	root, issues := checkYamlDocument(content, zeropsYmlSchema)
+	if root == nil {
+		return nil, issues
+	}
+
+	var setups []string
+	seen := map[string]int{}
+	if zerops := mappingValue(root, "zerops"); zerops != nil && zerops.Kind == yaml.SequenceNode {
+		if len(zerops.Content) == 0 {
+			issues = append(issues, yamlIssue{Severity: "error", Line: zerops.Line, Column: zerops.Column, Path: "zerops", Message: "no setups defined"})
+		}
+		for i, setupNode := range zerops.Content {
+			setupPath := fmt.Sprintf("zerops[%d]", i)
+			name := mappingValue(setupNode, "setup")
+			if name == nil || name.Kind != yaml.ScalarNode {
+				continue
+			}
+			setups = append(setups, name.Value)
+			if first, ok := seen[name.Value]; ok {
+				issues = append(issues, yamlIssue{Severity: "error", Line: name.Line, Column: name.Column, Path: setupPath + ".setup",
+					Message: fmt.Sprintf("duplicate setup '%s', first defined on line %d", name.Value, first)})
+			} else {
+				seen[name.Value] = name.Line
+			}
+			issues = append(issues, checkZeropsSetupRules(setupNode, setupPath)...)
+		}
+		// extends must name another setup of the file
+		for i, setupNode := range zerops.Content {
+			if extends := mappingValue(setupNode, "extends"); extends != nil && extends.Kind == yaml.ScalarNode {
+				if _, ok := seen[extends.Value]; !ok {
+					issues = append(issues, yamlIssue{Severity: "error", Line: extends.Line, Column: extends.Column,
+						Path: fmt.Sprintf("zerops[%d].extends", i), Message: fmt.Sprintf("setup '%s' does not exist", extends.Value)})
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Severity != issues[j].Severity {
+			return issues[i].Severity == "error"
+		}
+		return issues[i].Line < issues[j].Line
+	})
+	return setups, issues
+}
+
+// checkYamlNode reports where node does not match spec, including unknown keys
+func checkYamlNode(node *yaml.Node, spec *yamlField, path string, issues *[]yamlIssue) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	fail := func(message string) {
+		*issues = append(*issues, yamlIssue{Severity: "error", Line: node.Line, Column: node.Column, Path: displayYamlPath(path), Message: message})
+	}
+	isScalar := node.Kind == yaml.ScalarNode && node.Tag != "!!null"
+
+	switch spec.kind {
+	case yamlAnything:
+	case yamlObject:
+		if node.Kind != yaml.MappingNode {
+			fail("must be " + yamlKindNames[spec.kind])
+			return
+		}
+		known := map[string]bool{}
+		for key := range spec.fields {
+			known[key] = true
+		}
+		present := map[string]bool{}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			childPath := joinYamlPath(path, key.Value)
+			if present[key.Value] {
+				*issues = append(*issues, yamlIssue{Severity: "error", Line: key.Line, Column: key.Column, Path: displayYamlPath(childPath), Message: "duplicate key"})
+				continue
+			}
+			present[key.Value] = true
+			child, ok := spec.fields[key.Value]
+			if !ok {
+				message := fmt.Sprintf("unknown key '%s'", key.Value)
+				if suggestion := closestKey(key.Value, known); suggestion != "" {
+					message += fmt.Sprintf("; did you mean '%s'?", suggestion)
+				}
+				*issues = append(*issues, yamlIssue{Severity: "error", Line: key.Line, Column: key.Column, Path: displayYamlPath(childPath), Message: message})
+				continue
+			}
+			checkYamlNode(value, child, childPath, issues)
+		}
+		for _, key := range spec.required {
+			if !present[key] {
+				fail(fmt.Sprintf("missing required key '%s'", key))
+			}
+		}
+	case yamlList:
+		if node.Kind != yaml.SequenceNode {
+			fail("must be " + yamlKindNames[spec.kind])
+			return
+		}
+		for i, item := range node.Content {
+			checkYamlNode(item, spec.items, fmt.Sprintf("%s[%d]", path, i), issues)
+		}
+	case yamlString:
+		if !isScalar {
+			fail("must be " + yamlKindNames[spec.kind])
+			return
+		}
+		if len(spec.enum) > 0 && !containsString(spec.enum, node.Value) {
+			fail(fmt.Sprintf("must be one of %s, got '%s'", strings.Join(spec.enum, ", "), node.Value))
+		}
+	case yamlInt:
+		value, err := strconv.Atoi(node.Value)
+		if !isScalar || node.Tag != "!!int" || err != nil {
+			fail("must be " + yamlKindNames[spec.kind])
+			return
+		}
+		if spec.intRange != [2]int{} && (value < spec.intRange[0] || value > spec.intRange[1]) {
+			fail(fmt.Sprintf("must be between %d and %d, got %d", spec.intRange[0], spec.intRange[1], value))
+		}
+	case yamlBool:
+		if !isScalar || node.Tag != "!!bool" {
+			fail("must be " + yamlKindNames[spec.kind])
+		}
+	case yamlScalarValue:
+		if !isScalar {
+			fail("must be " + yamlKindNames[spec.kind])
+		}
+	case yamlStringOrList:
+		if isScalar {
+			return
+		}
+		if node.Kind != yaml.SequenceNode {
+			fail("must be " + yamlKindNames[spec.kind])
+			return
+		}
+		for i, item := range node.Content {
+			checkYamlNode(item, yamlValue(yamlString), fmt.Sprintf("%s[%d]", path, i), issues)
+		}
+	case yamlScalarMap:
+		if node.Kind != yaml.MappingNode {
+			fail("must be " + yamlKindNames[spec.kind])
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			checkYamlNode(node.Content[i+1], yamlValue(yamlScalarValue), joinYamlPath(path, node.Content[i].Value), issues)
+		}
+	}
+}
+
+// checkZeropsSetupRules reports problems the schema cannot express: required build keys unless
+// the setup extends another, a runtime without a way to start, probes on ports that are not
+// exposed and start together with startCommands
+func checkZeropsSetupRules(setup *yaml.Node, path string) []yamlIssue {
+	var issues []yamlIssue
+	warn := func(node *yaml.Node, suffix, message string) {
+		issues = append(issues, yamlIssue{Severity: "warning", Line: node.Line, Column: node.Column, Path: path + suffix, Message: message})
+	}
+
+	// build.base and build.deployFiles may come from the setup named in extends
+	build := mappingValue(setup, "build")
+	if mappingValue(setup, "extends") == nil {
+		if build == nil {
+			issues = append(issues, yamlIssue{Severity: "error", Line: setup.Line, Column: setup.Column, Path: path, Message: "missing required key 'build'"})
+		} else {
+			for _, key := range []string{"base", "deployFiles"} {
+				if mappingValue(build, key) == nil {
+					issues = append(issues, yamlIssue{Severity: "error", Line: build.Line, Column: build.Column, Path: path + ".build", Message: fmt.Sprintf("missing required key '%s'", key)})
+				}
+			}
+		}
+	}
+
+	run := mappingValue(setup, "run")
+	base := ""
+	if build != nil {
+		if node := mappingValue(build, "base"); node != nil && node.Kind == yaml.ScalarNode {
+			base = node.Value
+		}
+	}
+	if run != nil {
+		if node := mappingValue(run, "base"); node != nil && node.Kind == yaml.ScalarNode {
+			base = node.Value
+		}
+	}
+	servesItself := strings.HasPrefix(base, "php") || strings.HasPrefix(base, "static") || strings.HasPrefix(base, "nginx")
+
+	var start, startCommands *yaml.Node
+	exposed := map[int]bool{}
+	if run != nil {
+		start = mappingValue(run, "start")
+		startCommands = mappingValue(run, "startCommands")
+		if ports := mappingValue(run, "ports"); ports != nil && ports.Kind == yaml.SequenceNode {
+			for _, port := range ports.Content {
+				if node := mappingValue(port, "port"); node != nil {
+					if value, err := strconv.Atoi(node.Value); err == nil {
+						exposed[value] = true
+					}
+				}
+			}
+		}
+	}
+	if start != nil && startCommands != nil {
+		issues = append(issues, yamlIssue{Severity: "error", Line: startCommands.Line, Column: startCommands.Column, Path: path + ".run.startCommands", Message: "use either start or startCommands, not both"})
+	}
+	if start == nil && startCommands == nil && !servesItself && base != "" && mappingValue(setup, "extends") == nil {
+		warn(setup, "", fmt.Sprintf("%s has no run.start; the container has nothing to run", base))
+	}
+
+	probes := map[string]*yaml.Node{}
+	if deploy := mappingValue(setup, "deploy"); deploy != nil {
+		probes[".deploy.readinessCheck"] = mappingValue(deploy, "readinessCheck")
+	}
+	if run != nil {
+		probes[".run.healthCheck"] = mappingValue(run, "healthCheck")
+	}
+	for suffix, probe := range probes {
+		if probe == nil || servesItself {
+			continue
+		}
+		httpGet := mappingValue(probe, "httpGet")
+		if httpGet == nil {
+			continue
+		}
+		if node := mappingValue(httpGet, "port"); node != nil {
+			if value, err := strconv.Atoi(node.Value); err == nil && !exposed[value] {
+				warn(node, suffix+".httpGet.port", fmt.Sprintf("port %d is not listed in run.ports", value))
+			}
+		}
+	}
+	return issues
+}
+
+// mappingValue returns the value of key in a mapping node, or nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func joinYamlPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func displayYamlPath(path string) string {
+	if path == "" {
+		return "(root)"
+	}
+	return path
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// RegisterDeployValidate registers the deploy_validate tool
+func RegisterDeployValidate() {
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "deploy_validate",
+		Description: toolDescription("deploy_validate"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"zerops_yml": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: zerops.yml content; required in HTTP mode",
+				},
+				"working_dir": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Directory with zerops.yml or zerops.yaml, used when zerops_yml is not given (default: current directory)",
+				},
+				"zerops_yml_path": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Path to the zerops.yml to validate instead of working_dir",
+				},
+				"setup": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Setup that must exist, e.g. the hostname deploy_push will build",
+				},
+			},
+			"additionalProperties": false,
+		},
+		Annotations: shared.ReadOnly(),
+		Output:      deployValidateResult{},
+		Handler:     handleDeployValidate,
+	})
+}
+
+// deployValidateResult is the result of deploy_validate
+type deployValidateResult struct {
+	Source   string      `json:"source"`
+	Valid    bool        `json:"valid"`
+	Setups   []string    `json:"setups"`
+	Errors   []yamlIssue `json:"errors"`
+	Warnings []yamlIssue `json:"warnings"`
+	Message  string      `json:"message,omitempty"`
+}
+
+func handleDeployValidate(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	content, _ := args["zerops_yml"].(string)
+	source := "zerops_yml"
+	if content == "" {
+		if httpMode, _ := ctx.Value("httpMode").(bool); httpMode {
+			return nil, shared.InvalidArgument("zerops_yml is required in HTTP mode; the server's filesystem is not yours")
+		}
+		workingDir, _ := args["working_dir"].(string)
+		if workingDir == "" {
+			workingDir = "."
+		}
+		workingDir, err := filepath.Abs(workingDir)
+		if err != nil {
+			return nil, shared.InvalidArgument("Invalid working_dir '%s'", workingDir)
+		}
+		if err := shared.CheckPathInRoots(ctx, workingDir); err != nil {
+			return nil, err
+		}
+		yamlPath, _ := args["zerops_yml_path"].(string)
+		data, found, err := readDeployZeropsYml(ctx, workingDir, yamlPath)
+		if err != nil {
+			return nil, err
+		}
+		content, source = string(data), found
+	}
+
+	setups, issues := validateZeropsYml([]byte(content))
+	if setup, _ := args["setup"].(string); setup != "" && len(setups) > 0 && !containsString(setups, setup) {
+		issues = append([]yamlIssue{{Severity: "error", Line: 1, Path: "zerops",
+			Message: fmt.Sprintf("no setup '%s' (available: %s)", setup, strings.Join(setups, ", "))}}, issues...)
+	}
+
+	errors := []yamlIssue{}
+	warnings := []yamlIssue{}
+	for _, issue := range issues {
+		if issue.Severity == "error" {
+			errors = append(errors, issue)
+		} else {
+			warnings = append(warnings, issue)
+		}
+	}
+	if setups == nil {
+		setups = []string{}
+	}
+	result := deployValidateResult{
+		Source:   source,
+		Valid:    len(errors) == 0,
+		Setups:   setups,
+		Errors:   errors,
+		Warnings: warnings,
+	}
+	if len(errors) == 0 && len(warnings) == 0 {
+		result.Message = "No problems found."
+	}
+	return result, nil
+}
@@ -0,0 +1,23 @@
+package tools
+
+import (
+	"embed"
+	"strings"
+)
+
+// Tool descriptions live in descriptions/<tool>.md, so the Go files stay readable and the
+// text can be edited without touching code. The short variant sent to clients that save
+// tokens is the first paragraph of the file.
+//
+//go:embed descriptions/*.md
+var descriptionFiles embed.FS
+
+// toolDescription returns the description of a tool. A missing file is a programming
+// error and panics when the tool is registered.
+func toolDescription(name string) string {
+	data, err := descriptionFiles.ReadFile("descriptions/" + name + ".md")
+	if err != nil {
+		panic("tools: no description for " + name + ": " + err.Error())
+	}
+	return strings.TrimRight(string(data), "\n")
+}
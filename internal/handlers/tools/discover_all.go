@@ -0,0 +1,255 @@
+package tools
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/dto/input/body"
+	"github.com/zeropsio/zerops-go/dto/output"
+	"github.com/zeropsio/zerops-go/sdk"
+	"github.com/zeropsio/zerops-go/types/uuid"
+)
+
+// discoverConcurrency bounds how many organizations are searched at once
+const discoverConcurrency = 4
+
+// RegisterDiscoverAll registers the discover_all tool
+func RegisterDiscoverAll() {
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "discover_all",
+		Description: toolDescription("discover_all"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"tags": map[string]interface{}{
+					"type":        "array",
+					"description": "OPTIONAL: Only list projects that have all of these tags, e.g. [\"prod\"]",
+					"items":       map[string]interface{}{"type": "string"},
+				},
+			},
+			"additionalProperties": false,
+		},
+		Annotations: shared.ReadOnly(),
+		Output:      discoverAllResult{},
+		Handler:     handleDiscoverAll,
+	})
+}
+
+// discoverAllResult is the result of discover_all
+type discoverAllResult struct {
+	Organizations []organizationSummary `json:"organizations"`
+	ProjectCount  int                   `json:"project_count"`
+	ServiceCount  int                   `json:"service_count"`
+	Errors        []string              `json:"errors,omitempty"`
+	Warnings      []string              `json:"warnings,omitempty"`
+}
+
+// organizationSummary is one organization of the account with its projects
+type organizationSummary struct {
+	ClientID string           `json:"client_id"`
+	Name     string           `json:"name"`
+	Role     string           `json:"role"`
+	Projects []projectSummary `json:"projects"`
+}
+
+// projectSummary is one project in discover_all
+type projectSummary struct {
+	ID               string           `json:"id"`
+	Name             string           `json:"name"`
+	Status           string           `json:"status"`
+	Tags             []string         `json:"tags"`
+	ServiceCount     int              `json:"service_count"`
+	ServicesByStatus map[string]int   `json:"services_by_status"`
+	Services         []serviceSummary `json:"services"`
+	URLs             []string         `json:"urls"`
+}
+
+// serviceSummary is one service of a project in discover_all
+type serviceSummary struct {
+	ID       string `json:"id"`
+	Hostname string `json:"hostname"`
+	Type     string `json:"type"`
+	Status   string `json:"status"`
+}
+
+func handleDiscoverAll(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+	tagFilter, err := stringListArg(args, "tags")
+	if err != nil {
+		return nil, err
+	}
+
+	userResp, err := client.GetUserInfo(ctx)
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to get user info")
+	}
+
+	userOutput, err := userResp.Output()
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to get user info")
+	}
+	shared.StoreIdentity(sessionAPIKey(ctx), userOutput)
+
+	// Organizations are searched concurrently; results keep the account's organization order
+	type clientResult struct {
+		projects []projectSummary
+		warnings []string
+		err      error
+	}
+	results := make([]clientResult, len(userOutput.ClientUserList))
+	sem := make(chan struct{}, discoverConcurrency)
+	var wg sync.WaitGroup
+	for i, clientUser := range userOutput.ClientUserList {
+		wg.Add(1)
+		go func(i int, clientID uuid.ClientId) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			projects, warnings, err := discoverClient(ctx, client, clientID, tagFilter)
+			results[i] = clientResult{projects: projects, warnings: warnings, err: err}
+		}(i, clientUser.ClientId)
+	}
+	wg.Wait()
+
+	var organizations []organizationSummary
+	var errors, warnings []string
+	projectCount, serviceCount := 0, 0
+
+	for i, clientUser := range userOutput.ClientUserList {
+		projects, err := results[i].projects, results[i].err
+		if err != nil {
+			errors = append(errors, clientUser.Client.AccountName.Native()+": "+err.Error())
+			shared.Log(ctx, "warning", "discover_all", "Skipping organization "+clientUser.Client.AccountName.Native()+": "+err.Error())
+			continue
+		}
+		for _, warning := range results[i].warnings {
+			warnings = append(warnings, clientUser.Client.AccountName.Native()+": "+warning)
+		}
+
+		for _, project := range projects {
+			serviceCount += project.ServiceCount
+		}
+		projectCount += len(projects)
+
+		organizations = append(organizations, organizationSummary{
+			ClientID: string(clientUser.ClientId),
+			Name:     clientUser.Client.AccountName.Native(),
+			Role:     string(clientUser.RoleCode),
+			Projects: projects,
+		})
+	}
+
+	return discoverAllResult{
+		Organizations: organizations,
+		ProjectCount:  projectCount,
+		ServiceCount:  serviceCount,
+		Errors:        errors,
+		Warnings:      warnings,
+	}, nil
+}
+
+// discoverClient summarizes the projects of one organization that have all tags, using a single
+// search per resource type. Warnings describe best-effort lookups that failed.
+func discoverClient(ctx context.Context, client *sdk.Handler, clientID uuid.ClientId, tags []string) ([]projectSummary, []string, error) {
+	clientFilter := body.EsFilter{
+		Search: []body.EsSearchItem{
+			{Name: "clientId", Operator: "eq", Value: clientID.TypedString()},
+		},
+	}
+
+	projectResp, err := client.PostProjectSearch(ctx, clientFilter)
+	if err != nil {
+		return nil, nil, shared.WrapAPIError(err, "Failed to search projects")
+	}
+	projectOutput, err := projectResp.Output()
+	if err != nil {
+		return nil, nil, shared.WrapAPIError(err, "Failed to search projects")
+	}
+
+	serviceResp, err := client.PostServiceStackSearch(ctx, clientFilter)
+	if err != nil {
+		return nil, nil, shared.WrapAPIError(err, "Failed to search services")
+	}
+	serviceOutput, err := serviceResp.Output()
+	if err != nil {
+		return nil, nil, shared.WrapAPIError(err, "Failed to search services")
+	}
+
+	// Routing is best-effort; projects are still listed without URLs
+	var warnings []string
+	urls := map[uuid.ProjectId][]string{}
+	routingResp, err := client.PostPublicHttpRoutingSearch(ctx, clientFilter)
+	if err == nil {
+		var routingOutput output.EsPublicHttpRoutingResponse
+		if routingOutput, err = routingResp.Output(); err == nil {
+			for _, routing := range routingOutput.Items {
+				for _, domain := range routing.Domains {
+					urls[routing.ProjectId] = append(urls[routing.ProjectId], "https://"+domain.DomainName.Native())
+				}
+			}
+		}
+	}
+	if err != nil {
+		warnings = append(warnings, "public URLs unavailable: "+err.Error())
+	}
+
+	services := map[uuid.ProjectId][]serviceSummary{}
+	statuses := map[uuid.ProjectId]map[string]int{}
+	for _, service := range serviceOutput.Items {
+		if service.IsSystem.Native() {
+			continue
+		}
+		status := string(service.Status)
+		services[service.ProjectId] = append(services[service.ProjectId], serviceSummary{
+			ID:       string(service.Id),
+			Hostname: service.Name.Native(),
+			Type:     string(service.ServiceStackTypeVersionId),
+			Status:   status,
+		})
+		if statuses[service.ProjectId] == nil {
+			statuses[service.ProjectId] = map[string]int{}
+		}
+		statuses[service.ProjectId][status]++
+	}
+
+	projects := make([]projectSummary, 0, len(projectOutput.Items))
+	for _, project := range projectOutput.Items {
+		if !hasTags(project.TagList.Native(), tags) {
+			continue
+		}
+		projectURLs := urls[project.Id]
+		sort.Strings(projectURLs)
+
+		projects = append(projects, projectSummary{
+			ID:               string(project.Id),
+			Name:             project.Name.Native(),
+			Status:           string(project.Status),
+			Tags:             projectTags(project.TagList),
+			ServiceCount:     len(services[project.Id]),
+			ServicesByStatus: statuses[project.Id],
+			Services:         services[project.Id],
+			URLs:             projectURLs,
+		})
+	}
+
+	sort.Slice(projects, func(i, j int) bool {
+		return projects[i].Name < projects[j].Name
+	})
+	return projects, warnings, nil
+}
+
+// hasTags reports whether projectTags contains every tag in wanted
+func hasTags(projectTags, wanted []string) bool {
+	for _, tag := range wanted {
+		if !slices.Contains(projectTags, strings.TrimSpace(tag)) {
+			return false
+		}
+	}
+	return true
+}
@@ -4,9 +4,10 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/zerops-mcp-basic/internal/handlers/shared"
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
 	"github.com/zeropsio/zerops-go/dto/input/body"
 	"github.com/zeropsio/zerops-go/dto/input/path"
+	"github.com/zeropsio/zerops-go/dto/output"
 	"github.com/zeropsio/zerops-go/sdk"
 	"github.com/zeropsio/zerops-go/types"
 	"github.com/zeropsio/zerops-go/types/uuid"
@@ -14,30 +15,17 @@ import (
 
 // RegisterDiscovery registers the discovery tool
 func RegisterDiscovery() {
+	// discovery records the read stamps checked by optimistic locking
+	shared.RegisterSessionState("read_stamps", shared.MapSessionState(&readStamps.mu, readStamps.stamps))
 	shared.GlobalRegistry.Register(&shared.ToolDefinition{
 		Name:        "discovery",
-		Description: `ESSENTIAL FIRST STEP: Discovers all services in a project with their IDs, hostnames, service types, deployment status, and environment variable availability.
-
-CRITICAL: Requires a project ID. To get the project ID, the agent can run 'echo $projectId' in the container environment.
-
-Returns condensed data about:
-- All services with their unique IDs (required for other tools)
-- Service hostnames, types, and current status
-- Active app version details (for runtime services with deployments)
-- Available environment variables at project and service level
-- Current project configuration
-
-Optional filters:
-- service_id: Get details for a specific service by ID
-- service_name: Get details for a specific service by hostname
-
-Always use this tool first to understand the project structure before performing other operations.`,
+		Description: toolDescription("discovery"),
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
 				"project_id": map[string]interface{}{
 					"type":        "string",
-					"description": "Zerops project ID. Get it by running 'echo $projectId' in the container.",
+					"description": "OPTIONAL: Zerops project ID (default: the current project, see current_project)",
 				},
 				"service_id": map[string]interface{}{
 					"type":        "string",
@@ -47,44 +35,89 @@ Always use this tool first to understand the project structure before performing
 					"type":        "string",
 					"description": "Optional: Service hostname/name to get details for a single service only",
 				},
+				"timezone": timezoneProperty(),
 			},
-			"required":             []string{"project_id"},
 			"additionalProperties": false,
 		},
-		Handler: handleDiscovery,
+		Annotations: shared.ReadOnly(),
+		Output:      discoveryResult{},
+		Handler:     handleDiscovery,
 	})
 }
 
+// discoveryResult is the result of discovery
+type discoveryResult struct {
+	Project  discoveryProject   `json:"project"`
+	Services []discoveryService `json:"services"`
+	Count    int                `json:"count"`
+	Message  string             `json:"message,omitempty"`
+	Warnings []string           `json:"warnings,omitempty"`
+}
+
+// discoveryProject is the project discovery was called for
+type discoveryProject struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	Tags    []string `json:"tags"`
+	EnvKeys []string `json:"env_keys"`
+}
+
+// discoveryService is one service of the project. LastUpdate is remembered for optimistic
+// locking.
+type discoveryService struct {
+	ID            string         `json:"id"`
+	Hostname      string         `json:"hostname"`
+	Type          string         `json:"type"`
+	Status        string         `json:"status"`
+	EnvKeys       []string       `json:"env_keys"`
+	ProcessCount  int            `json:"process_count"`
+	LastUpdate    string         `json:"last_update"`
+	ActiveVersion *activeVersion `json:"active_version,omitempty"`
+}
+
+// activeVersion is the app version a runtime service runs
+type activeVersion struct {
+	ID      string `json:"id"`
+	Status  string `json:"status"`
+	Created string `json:"created"`
+	Updated string `json:"updated"`
+}
+
 func handleDiscovery(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
 	if client == nil {
-		return shared.ErrorResponse("No API key provided"), nil
+		return nil, shared.ErrNoClient
 	}
 
-	// Debug: Log all received parameters
-	fmt.Printf("DEBUG: Discovery received args: %+v\n", args)
-
 	// Get project ID parameter
 	projectID, ok := args["project_id"].(string)
 	if !ok || projectID == "" {
 		// Check if it was passed as "projectId" instead
 		if altProjectID, altOk := args["projectId"].(string); altOk && altProjectID != "" {
 			projectID = altProjectID
-			fmt.Printf("DEBUG: Found projectId parameter (camelCase): %s\n", projectID)
 		} else {
-			return shared.ErrorResponse("Project ID is required. Run 'echo $projectId' in the container to get it."), nil
+			resolved, err := resolveProjectID(ctx, client, args)
+			if err != nil {
+				return nil, err
+			}
+			projectID = resolved
 		}
 	}
 
+	display, err := resolveDisplayFormat(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get project details first (we need clientId for searches)
 	projectPath := path.ProjectId{Id: uuid.ProjectId(projectID)}
 	projectResp, err := client.GetProject(ctx, projectPath)
 	if err != nil {
-		return shared.ErrorResponse(fmt.Sprintf("Failed to get project: %v", err)), nil
+		return nil, shared.WrapAPIError(err, "Failed to get project")
 	}
 
 	projectOutput, err := projectResp.Output()
 	if err != nil {
-		return shared.ErrorResponse(fmt.Sprintf("Failed to parse project: %v", err)), nil
+		return nil, shared.WrapAPIError(err, "Failed to parse project")
 	}
 
 	// Search for the project to get envList
@@ -105,16 +138,16 @@ func handleDiscovery(ctx context.Context, client *sdk.Handler, args map[string]i
 
 	projectSearchResp, err := client.PostProjectSearch(ctx, projectFilter)
 	if err != nil {
-		return shared.ErrorResponse(fmt.Sprintf("Failed to search project: %v", err)), nil
+		return nil, shared.WrapAPIError(err, "Failed to search project")
 	}
 
 	projectSearchOutput, err := projectSearchResp.Output()
 	if err != nil {
-		return shared.ErrorResponse(fmt.Sprintf("Failed to parse project search: %v", err)), nil
+		return nil, shared.WrapAPIError(err, "Failed to parse project search")
 	}
 
 	if len(projectSearchOutput.Items) == 0 {
-		return shared.ErrorResponse("Project not found"), nil
+		return nil, shared.NotFound("Project not found")
 	}
 
 	project := projectSearchOutput.Items[0]
@@ -166,12 +199,12 @@ func handleDiscovery(ctx context.Context, client *sdk.Handler, args map[string]i
 
 	serviceResp, err := client.PostServiceStackSearch(ctx, serviceFilter)
 	if err != nil {
-		return shared.ErrorResponse(fmt.Sprintf("Failed to search services: %v", err)), nil
+		return nil, shared.WrapAPIError(err, "Failed to search services")
 	}
 
 	serviceOutput, err := serviceResp.Output()
 	if err != nil {
-		return shared.ErrorResponse(fmt.Sprintf("Failed to parse services: %v", err)), nil
+		return nil, shared.WrapAPIError(err, "Failed to parse services")
 	}
 
 	if len(serviceOutput.Items) == 0 {
@@ -182,32 +215,39 @@ func handleDiscovery(ctx context.Context, client *sdk.Handler, args map[string]i
 			message = fmt.Sprintf("No service found with name '%s'", serviceNameFilter)
 		}
 		
-		return map[string]interface{}{
-			"services": []interface{}{},
-			"project": map[string]interface{}{
-				"id":       projectID,
-				"name":     project.Name.Native(),
-				"env_keys": projectEnvKeys,
+		return discoveryResult{
+			Project: discoveryProject{
+				ID:      projectID,
+				Name:    project.Name.Native(),
+				Tags:    projectTags(project.TagList),
+				EnvKeys: projectEnvKeys,
 			},
-			"message": message,
+			Services: []discoveryService{},
+			Message:  message,
 		}, nil
 	}
 
-	// Build service information for this project
-	var services []map[string]interface{}
+	// Build service information for this project; failed auxiliary lookups are reported
+	// as warnings so missing data is not mistaken for absent resources
+	var services []discoveryService
+	var warnings []string
 	for _, service := range serviceOutput.Items {
 		// Get service environment variables
 		var serviceEnvKeys []string
 		servicePath := path.ServiceStackId{Id: service.Id}
 		serviceEnvResp, err := client.GetServiceStackEnv(ctx, servicePath)
 		if err == nil {
-			if envOutput, err := serviceEnvResp.Output(); err == nil {
+			var envOutput output.ServiceStackEnvList
+			if envOutput, err = serviceEnvResp.Output(); err == nil {
 				// Extract env variable keys
 				for _, envItem := range envOutput.Items {
 					serviceEnvKeys = append(serviceEnvKeys, envItem.Key.Native())
 				}
 			}
 		}
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s: env keys unavailable: %v", service.Name.Native(), err))
+		}
 
 		// Count running processes
 		processCount := 0
@@ -227,39 +267,47 @@ func handleDiscovery(ctx context.Context, client *sdk.Handler, args map[string]i
 		}
 		processResp, err := client.PostProcessSearch(ctx, processFilter)
 		if err == nil {
-			if processOutput, err := processResp.Output(); err == nil {
+			var processOutput output.EsProcessResponse
+			if processOutput, err = processResp.Output(); err == nil {
 				processCount = len(processOutput.Items)
 			}
 		}
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s: process count unavailable: %v", service.Name.Native(), err))
+		}
 
-		serviceInfo := map[string]interface{}{
-			"id":            string(service.Id),
-			"hostname":      service.Name.Native(),
-			"type":          string(service.ServiceStackTypeVersionId),
-			"status":        string(service.Status),
-			"env_keys":      serviceEnvKeys,
-			"process_count": processCount,
+		serviceInfo := discoveryService{
+			ID:           string(service.Id),
+			Hostname:     service.Name.Native(),
+			Type:         string(service.ServiceStackTypeVersionId),
+			Status:       string(service.Status),
+			EnvKeys:      serviceEnvKeys,
+			ProcessCount: processCount,
+			LastUpdate:   formatServiceStamp(service.LastUpdate.Native(), display),
 		}
+		rememberServiceStamp(ctx, string(service.Id), service.LastUpdate.Native())
 		
 		// Add active app version info if available (for runtime services)
 		if service.ActiveAppVersion != nil {
-			serviceInfo["active_version"] = map[string]interface{}{
-				"id":         string(service.ActiveAppVersion.Id),
-				"status":     string(service.ActiveAppVersion.Status),
-				"created":    service.ActiveAppVersion.Created.Native(),
-				"updated":    service.ActiveAppVersion.LastUpdate.Native(),
+			serviceInfo.ActiveVersion = &activeVersion{
+				ID:      string(service.ActiveAppVersion.Id),
+				Status:  string(service.ActiveAppVersion.Status),
+				Created: formatTimestamp(service.ActiveAppVersion.Created.Native(), display),
+				Updated: formatTimestamp(service.ActiveAppVersion.LastUpdate.Native(), display),
 			}
 		}
 		services = append(services, serviceInfo)
 	}
 
-	return map[string]interface{}{
-		"project": map[string]interface{}{
-			"id":       projectID,
-			"name":     project.Name.Native(),
-			"env_keys": projectEnvKeys,
+	return discoveryResult{
+		Project: discoveryProject{
+			ID:      projectID,
+			Name:    project.Name.Native(),
+			Tags:    projectTags(project.TagList),
+			EnvKeys: projectEnvKeys,
 		},
-		"services": services,
-		"count":    len(services),
+		Services: services,
+		Count:    len(services),
+		Warnings: warnings,
 	}, nil
 }
\ No newline at end of file
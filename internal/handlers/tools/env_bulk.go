@@ -0,0 +1,333 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/dto/input/body"
+	"github.com/zeropsio/zerops-go/dto/input/path"
+	"github.com/zeropsio/zerops-go/dto/output"
+	"github.com/zeropsio/zerops-go/sdk"
+	"github.com/zeropsio/zerops-go/types"
+	"github.com/zeropsio/zerops-go/types/enum"
+	"github.com/zeropsio/zerops-go/types/uuid"
+)
+
+// envKeyPattern is what a .env line may use as a variable name
+var envKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// maxBulkEnvVariables caps one set_env_bulk call
+const maxBulkEnvVariables = 200
+
+// dotenvEntry is one parsed KEY=VALUE line; err is set when the line is malformed
+type dotenvEntry struct {
+	line  int
+	key   string
+	value string
+	err   string
+}
+
+// RegisterEnvBulk registers the set_env_bulk tool
+func RegisterEnvBulk() {
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "set_env_bulk",
+		Description: toolDescription("set_env_bulk"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"env": map[string]interface{}{
+					"type":        "string",
+					"description": "REQUIRED: .env content, one KEY=VALUE per line",
+				},
+				"project_id": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Set project-level variables of this project",
+					"pattern":     "^[A-Za-z0-9_-]+$",
+				},
+				"service_id": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Set service-level variables of this service",
+					"pattern":     "^[A-Za-z0-9_-]+$",
+				},
+				"overwrite": map[string]interface{}{
+					"type":        "boolean",
+					"description": "OPTIONAL: Update keys that already exist (default: true)",
+				},
+				"expected_last_update": expectedLastUpdateProperty(),
+			},
+			"required":             []string{"env"},
+			"additionalProperties": false,
+		},
+		Annotations: shared.Mutating(true, true),
+		Output:      envBulkResult{},
+		Handler:     handleSetEnvBulk,
+	})
+}
+
+// envBulkResult is the result of set_env_bulk
+type envBulkResult struct {
+	ProjectID string         `json:"project_id,omitempty"`
+	ServiceID string         `json:"service_id,omitempty"`
+	Results   []envBulkEntry `json:"results"`
+	Summary   map[string]int `json:"summary"`
+	Message   string         `json:"message"`
+	shared.Suggestions
+}
+
+// envBulkEntry is the outcome for one line of the .env content
+type envBulkEntry struct {
+	Key       string                 `json:"key"`
+	Line      int                    `json:"line"`
+	Status    string                 `json:"status"`
+	ProcessID string                 `json:"process_id,omitempty"`
+	Error     string                 `json:"error,omitempty"`
+	APIError  map[string]interface{} `json:"api_error,omitempty"`
+}
+
+func handleSetEnvBulk(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+
+	content, _ := args["env"].(string)
+	projectID, _ := args["project_id"].(string)
+	serviceID, _ := args["service_id"].(string)
+	if (projectID == "") == (serviceID == "") {
+		return nil, shared.InvalidArgument("Provide exactly one of project_id or service_id")
+	}
+	overwrite := true
+	if value, ok := args["overwrite"].(bool); ok {
+		overwrite = value
+	}
+
+	entries := parseDotenv(content)
+	if len(entries) == 0 {
+		return nil, shared.InvalidArgument("env contains no KEY=VALUE lines")
+	}
+	if len(entries) > maxBulkEnvVariables {
+		return nil, shared.InvalidArgument("env has %d variables; at most %d are allowed per call", len(entries), maxBulkEnvVariables)
+	}
+
+	var results []envBulkEntry
+	var err error
+	if projectID != "" {
+		results, err = applyProjectEnvBulk(ctx, client, projectID, entries, overwrite)
+	} else {
+		if err := checkServiceUnchanged(ctx, client, args, serviceID); err != nil {
+			return nil, err
+		}
+		results, err = applyServiceEnvBulk(ctx, client, serviceID, entries, overwrite)
+		forgetServiceStamp(ctx, serviceID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int{}
+	for _, result := range results {
+		counts[result.Status]++
+	}
+	response := envBulkResult{
+		ProjectID: projectID,
+		Results:   results,
+		Summary:   counts,
+	}
+	if projectID == "" {
+		response.ServiceID = serviceID
+	}
+	if counts["failed"] > 0 {
+		response.Message = fmt.Sprintf("%d of %d variables failed; fix them and call again with only those lines.", counts["failed"], len(results))
+	} else {
+		response.Message = "All variables processed. Use 'get_process_status' to monitor the returned processes."
+	}
+	for _, result := range results {
+		if result.ProcessID != "" {
+			response.SuggestNext(shared.WaitForProcess(result.ProcessID, fmt.Sprintf("Wait until %s is set", result.Key)))
+		}
+	}
+	if serviceID != "" && counts["failed"] < len(results) {
+		response.SuggestNext(shared.NextCall{
+			Tool:      "restart_service",
+			Arguments: map[string]interface{}{"service_id": serviceID},
+			Purpose:   "Running containers read the new values after a restart",
+		})
+	}
+	return response, nil
+}
+
+// parseDotenv parses .env content; duplicate keys keep the last value
+func parseDotenv(content string) []dotenvEntry {
+	var entries []dotenvEntry
+	index := map[string]int{}
+	for i, raw := range strings.Split(content, "\n") {
+		line := strings.TrimSpace(raw)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		entry := dotenvEntry{line: i + 1}
+		key, value, found := strings.Cut(line, "=")
+		entry.key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		switch {
+		case !found:
+			entry.err = "missing '='"
+		case !envKeyPattern.MatchString(entry.key):
+			entry.err = fmt.Sprintf("invalid key '%s'", entry.key)
+		case len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"':
+			unquoted, err := strconv.Unquote(value)
+			if err != nil {
+				entry.err = "invalid double-quoted value"
+			}
+			entry.value = unquoted
+		case len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'':
+			entry.value = value[1 : len(value)-1]
+		default:
+			// Unquoted values end at an inline comment
+			if j := strings.Index(value, " #"); j >= 0 {
+				value = strings.TrimSpace(value[:j])
+			}
+			entry.value = value
+		}
+
+		if j, ok := index[entry.key]; ok && entry.err == "" {
+			entries[j] = entry
+			continue
+		}
+		if entry.err == "" {
+			index[entry.key] = len(entries)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func applyProjectEnvBulk(ctx context.Context, client *sdk.Handler, projectID string, entries []dotenvEntry, overwrite bool) ([]envBulkEntry, error) {
+	project, err := searchProject(ctx, client, projectID)
+	if err != nil {
+		return nil, err
+	}
+	live := map[string]output.ProjectEnv{}
+	for _, env := range project.EnvList {
+		live[env.Key.Native()] = env
+	}
+
+	recordSnapshot(ctx, client, projectID, "set_env_bulk", fmt.Sprintf("%d project variables", len(entries)))
+
+	results := make([]envBulkEntry, 0, len(entries))
+	for _, entry := range entries {
+		result := bulkEntryResult(entry)
+		if entry.err != "" {
+			results = append(results, result)
+			continue
+		}
+
+		current, exists := live[entry.key]
+		var process output.Process
+		var err error
+		switch {
+		case !exists:
+			resp, callErr := client.PostProjectEnv(ctx, body.ProjectEnvPost{
+				ProjectId: uuid.ProjectId(projectID),
+				Key:       types.NewString(entry.key),
+				Content:   types.NewText(entry.value),
+			})
+			if callErr == nil {
+				process, callErr = resp.Output()
+			}
+			err = callErr
+			result.Status = "created"
+		case current.Content.Native() == entry.value:
+			result.Status = "unchanged"
+		case !overwrite:
+			result.Status = "skipped"
+		case current.Type == enum.EnvTypeEnumSystem || !current.Editable.Native():
+			result.Status = "failed"
+			result.Error = "system variable cannot be changed"
+		default:
+			resp, callErr := client.PutProjectEnv(ctx, path.ProjectEnvId{Id: current.Id}, body.ProjectEnvPut{
+				Key:       types.NewString(entry.key),
+				Content:   types.NewText(entry.value),
+				Sensitive: current.Sensitive,
+			})
+			if callErr == nil {
+				process, callErr = resp.Output()
+			}
+			err = callErr
+			result.Status = "updated"
+		}
+		results = append(results, finishBulkResult(result, process, err))
+	}
+	return results, nil
+}
+
+func applyServiceEnvBulk(ctx context.Context, client *sdk.Handler, serviceID string, entries []dotenvEntry, overwrite bool) ([]envBulkEntry, error) {
+	live, err := serviceEnvByKey(ctx, client, uuid.ServiceStackId(serviceID))
+	if err != nil {
+		return nil, err
+	}
+
+	if projectID, err := serviceProjectID(ctx, client, serviceID); err == nil {
+		recordSnapshot(ctx, client, projectID, "set_env_bulk", fmt.Sprintf("%s: %d service variables", serviceID, len(entries)))
+	}
+
+	results := make([]envBulkEntry, 0, len(entries))
+	for _, entry := range entries {
+		result := bulkEntryResult(entry)
+		if entry.err != "" {
+			results = append(results, result)
+			continue
+		}
+
+		current, exists := live[entry.key]
+		var process output.Process
+		var err error
+		switch {
+		case exists && current.Content.Native() == entry.value:
+			result.Status = "unchanged"
+		case exists && !overwrite:
+			result.Status = "skipped"
+		default:
+			// Generated variables are refused by the upsert and reported as failed
+			var created bool
+			process, created, err = upsertServiceEnv(ctx, client, uuid.ServiceStackId(serviceID), live, entry.key, entry.value)
+			result.Status = "updated"
+			if created {
+				result.Status = "created"
+			}
+		}
+		results = append(results, finishBulkResult(result, process, err))
+	}
+	return results, nil
+}
+
+func bulkEntryResult(entry dotenvEntry) envBulkEntry {
+	result := envBulkEntry{
+		Key:  entry.key,
+		Line: entry.line,
+	}
+	if entry.err != "" {
+		result.Status = "failed"
+		result.Error = entry.err
+	}
+	return result
+}
+
+// finishBulkResult records the process of a created or updated key, or its API error
+func finishBulkResult(result envBulkEntry, process output.Process, err error) envBulkEntry {
+	if err != nil {
+		result.Status = "failed"
+		result.Error = shared.WrapAPIError(err, "Failed to set variable").Error()
+		result.APIError = shared.APIErrorDetails(err)
+		return result
+	}
+	if process.Id != "" {
+		result.ProcessID = string(process.Id)
+	}
+	return result
+}
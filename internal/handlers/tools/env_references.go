@@ -0,0 +1,288 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/sdk"
+	"github.com/zeropsio/zerops-go/types/uuid"
+	"gopkg.in/yaml.v3"
+)
+
+// envReferencePattern matches ${name} references in zerops.yml env values
+var envReferencePattern = regexp.MustCompile(`\$\{([A-Za-z0-9_]+)\}`)
+
+// hostnamePrefixPattern matches what can be a hostname in ${hostname_key}
+var hostnamePrefixPattern = regexp.MustCompile(`^[a-z0-9]+$`)
+
+// zeropsEnvSetup is the part of a zerops.yml setup that defines env variables
+type zeropsEnvSetup struct {
+	Setup string `yaml:"setup"`
+	Build struct {
+		EnvVariables map[string]string `yaml:"envVariables"`
+	} `yaml:"build"`
+	Run struct {
+		EnvVariables map[string]string `yaml:"envVariables"`
+	} `yaml:"run"`
+}
+
+// RegisterEnvReferences registers the validate_env_references tool
+func RegisterEnvReferences() {
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "validate_env_references",
+		Description: toolDescription("validate_env_references"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"zerops_yml": map[string]interface{}{
+					"type":        "string",
+					"description": "REQUIRED: zerops.yml content",
+				},
+				"project_id": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Project ID. Defaults to $projectId.",
+					"pattern":     "^[A-Za-z0-9_-]+$",
+				},
+			},
+			"required":             []string{"zerops_yml"},
+			"additionalProperties": false,
+		},
+		Annotations: shared.ReadOnly(),
+		Output:      envReferencesResult{},
+		Handler:     handleValidateEnvReferences,
+	})
+}
+
+// envReferencesResult is the result of validate_env_references
+type envReferencesResult struct {
+	ProjectID  string         `json:"project_id"`
+	References []envReference `json:"references"`
+	Count      int            `json:"count"`
+	Problems   int            `json:"problems"`
+	Valid      bool           `json:"valid"`
+	Message    string         `json:"message,omitempty"`
+}
+
+// envReference is one ${...} reference of a setup and whether it resolves. Status is ok,
+// unverified, unknown_service or unknown_variable.
+type envReference struct {
+	Setup      string `json:"setup"`
+	Variable   string `json:"variable"`
+	Reference  string `json:"reference"`
+	Status     string `json:"status"`
+	Message    string `json:"message,omitempty"`
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+func handleValidateEnvReferences(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+
+	content, _ := args["zerops_yml"].(string)
+	if strings.TrimSpace(content) == "" {
+		return nil, shared.InvalidArgument("zerops_yml is required")
+	}
+
+	var config struct {
+		Zerops []zeropsEnvSetup `yaml:"zerops"`
+	}
+	if err := yaml.Unmarshal([]byte(content), &config); err != nil {
+		return nil, shared.InvalidArgument("Invalid zerops.yml: %v", err)
+	}
+	if len(config.Zerops) == 0 {
+		return nil, shared.InvalidArgument("zerops.yml has no setups under 'zerops'")
+	}
+
+	projectID, err := resolveProjectID(ctx, client, args)
+	if err != nil {
+		return nil, err
+	}
+	references, problems, err := checkEnvReferences(ctx, client, projectID, config.Zerops)
+	if err != nil {
+		return nil, err
+	}
+
+	result := envReferencesResult{
+		ProjectID:  projectID,
+		References: references,
+		Count:      len(references),
+		Problems:   problems,
+		Valid:      problems == 0,
+	}
+	if len(references) == 0 {
+		result.Message = "No ${...} references found."
+	}
+	return result, nil
+}
+
+// checkEnvReferences resolves the ${...} references of the setups against the project;
+// problems counts references that are known to be broken
+func checkEnvReferences(ctx context.Context, client *sdk.Handler, projectID string, setups []zeropsEnvSetup) ([]envReference, int, error) {
+	project, err := searchProject(ctx, client, projectID)
+	if err != nil {
+		return nil, 0, err
+	}
+	services, err := searchProjectServices(ctx, client, project)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	projectKeys := map[string]bool{}
+	for _, envItem := range project.EnvList {
+		projectKeys[envItem.Key.Native()] = true
+	}
+
+	// Service env keys are loaded lazily; nil means they could not be read
+	hostnames := map[string]bool{}
+	serviceIDs := map[string]uuid.ServiceStackId{}
+	for _, service := range services {
+		hostnames[service.Name.Native()] = true
+		serviceIDs[service.Name.Native()] = service.Id
+	}
+	envCache := map[string]map[string]bool{}
+	keysOf := func(hostname string) map[string]bool {
+		if keys, ok := envCache[hostname]; ok {
+			return keys
+		}
+		var keys map[string]bool
+		if serviceID, ok := serviceIDs[hostname]; ok {
+			if list := serviceEnvKeys(ctx, client, serviceID); list != nil {
+				keys = map[string]bool{}
+				for _, key := range list {
+					keys[key] = true
+				}
+			}
+		}
+		envCache[hostname] = keys
+		return keys
+	}
+
+	var references []envReference
+	problems := 0
+	for _, setup := range setups {
+		local := map[string]bool{}
+		for key := range setup.Build.EnvVariables {
+			local[key] = true
+		}
+		for key := range setup.Run.EnvVariables {
+			local[key] = true
+		}
+
+		for _, phase := range []struct {
+			name string
+			vars map[string]string
+		}{{"build", setup.Build.EnvVariables}, {"run", setup.Run.EnvVariables}} {
+			for _, key := range sortedMapKeys(phase.vars) {
+				for _, match := range envReferencePattern.FindAllStringSubmatch(phase.vars[key], -1) {
+					ref := checkEnvReference(match[1], setup.Setup, local, projectKeys, hostnames, keysOf)
+					ref.Setup = setup.Setup
+					ref.Variable = phase.name + ".envVariables." + key
+					if ref.Status != "ok" && ref.Status != "unverified" {
+						problems++
+					}
+					references = append(references, ref)
+				}
+			}
+		}
+	}
+	return references, problems, nil
+}
+
+// checkEnvReference resolves one reference name; ${hostname_key} refers to another
+// service (hostnames cannot contain '_'), anything else to local, project or own service variables
+func checkEnvReference(name, setup string, local, projectKeys, hostnames map[string]bool, keysOf func(string) map[string]bool) envReference {
+	ref := envReference{Reference: "${" + name + "}"}
+
+	if hostname, key, ok := strings.Cut(name, "_"); ok && hostnames[hostname] {
+		keys := keysOf(hostname)
+		switch {
+		case keys == nil:
+			ref.Status = "unverified"
+			ref.Message = fmt.Sprintf("Env variables of '%s' could not be read", hostname)
+		case keys[key]:
+			ref.Status = "ok"
+		default:
+			ref.Status = "unknown_variable"
+			ref.Message = fmt.Sprintf("Service '%s' has no variable '%s'", hostname, key)
+			if suggestion := closestKey(key, keys); suggestion != "" {
+				ref.Suggestion = "${" + hostname + "_" + suggestion + "}"
+			}
+		}
+		return ref
+	}
+
+	if local[name] || projectKeys[name] {
+		ref.Status = "ok"
+		return ref
+	}
+	if own := keysOf(setup); own != nil && own[name] {
+		ref.Status = "ok"
+		return ref
+	}
+
+	if hostname, _, ok := strings.Cut(name, "_"); ok && hostnamePrefixPattern.MatchString(hostname) {
+		ref.Status = "unknown_service"
+		ref.Message = fmt.Sprintf("No service '%s' in the project and no variable '%s'", hostname, name)
+		if suggestion := closestKey(hostname, hostnames); suggestion != "" {
+			ref.Suggestion = "${" + suggestion + strings.TrimPrefix(name, hostname) + "}"
+		}
+		return ref
+	}
+
+	ref.Status = "unknown_variable"
+	ref.Message = fmt.Sprintf("'%s' is not defined in the setup, on the project or on service '%s'", name, setup)
+	candidates := map[string]bool{}
+	for key := range local {
+		candidates[key] = true
+	}
+	for key := range projectKeys {
+		candidates[key] = true
+	}
+	if suggestion := closestKey(name, candidates); suggestion != "" {
+		ref.Suggestion = "${" + suggestion + "}"
+	}
+	return ref
+}
+
+// closestKey returns the candidate within edit distance 2 of name, preferring the closest
+func closestKey(name string, candidates map[string]bool) string {
+	keys := make([]string, 0, len(candidates))
+	for key := range candidates {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	best, bestDistance := "", 3
+	for _, key := range keys {
+		if distance := editDistance(strings.ToLower(name), strings.ToLower(key)); distance < bestDistance {
+			best, bestDistance = key, distance
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
@@ -0,0 +1,372 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/dto/input/path"
+	"github.com/zeropsio/zerops-go/dto/output"
+	"github.com/zeropsio/zerops-go/sdk"
+	"github.com/zeropsio/zerops-go/types/enum"
+	"github.com/zeropsio/zerops-go/types/uuid"
+	"gopkg.in/yaml.v3"
+)
+
+// envRestartWaitTimeout bounds the wait for each restart when restarts are sequenced
+const envRestartWaitTimeout = 5 * time.Minute
+
+// envDependent is a service that reads changed variables of another service
+type envDependent struct {
+	service    output.EsServiceStack
+	references []string
+	sources    []string
+}
+
+// RegisterEnvRestart registers the apply_env_and_restart tool
+func RegisterEnvRestart() {
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "apply_env_and_restart",
+		Description: toolDescription("apply_env_and_restart"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"service_id": map[string]interface{}{
+					"type":        "string",
+					"description": "REQUIRED: Service whose variables are set",
+					"pattern":     "^[A-Za-z0-9_-]+$",
+				},
+				"variables": map[string]interface{}{
+					"type":        "object",
+					"description": "REQUIRED: Variables to set, e.g. {\"API_KEY\": \"...\"}",
+					"additionalProperties": map[string]interface{}{
+						"type": "string",
+					},
+					"minProperties": 1,
+				},
+				"restart_dependents": map[string]interface{}{
+					"type":        "boolean",
+					"description": "OPTIONAL: Also restart services that reference the changed keys (default: true)",
+				},
+				"wait": map[string]interface{}{
+					"type":        "boolean",
+					"description": "OPTIONAL: Wait for each restart to finish before the next one (default: true)",
+				},
+				"expected_last_update": expectedLastUpdateProperty(),
+			},
+			"required":             []string{"service_id", "variables"},
+			"additionalProperties": false,
+		},
+		Annotations: shared.Mutating(true, false),
+		Output:      applyEnvAndRestartResult{},
+		Handler:     handleApplyEnvAndRestart,
+	})
+}
+
+// applyEnvAndRestartResult is the result of apply_env_and_restart
+type applyEnvAndRestartResult struct {
+	ServiceID   string            `json:"service_id"`
+	ServiceName string            `json:"service_name"`
+	Variables   []envBulkEntry    `json:"variables"`
+	ChangedKeys []string          `json:"changed_keys"`
+	Dependents  []envDependentRef `json:"dependents,omitempty"`
+	Restarts    []envRestart      `json:"restarts"`
+	Message     string            `json:"message"`
+	shared.Suggestions
+}
+
+// envDependentRef is a service that references one of the changed keys
+type envDependentRef struct {
+	ServiceID   string   `json:"service_id"`
+	ServiceName string   `json:"service_name"`
+	References  []string `json:"references"`
+	FoundIn     []string `json:"found_in"`
+}
+
+// envRestart is the restart of the changed service or of one of its dependents
+type envRestart struct {
+	ServiceID   string `json:"service_id"`
+	ServiceName string `json:"service_name"`
+	Status      string `json:"status"`
+	Reason      string `json:"reason,omitempty"`
+	ProcessID   string `json:"process_id,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+func handleApplyEnvAndRestart(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+
+	serviceID, ok := args["service_id"].(string)
+	if !ok || serviceID == "" {
+		return nil, shared.InvalidArgument("Service ID is required")
+	}
+	variables, _ := args["variables"].(map[string]interface{})
+	if len(variables) == 0 {
+		return nil, shared.InvalidArgument("variables must set at least one key")
+	}
+	if len(variables) > maxBulkEnvVariables {
+		return nil, shared.InvalidArgument("variables has %d keys; at most %d are allowed per call", len(variables), maxBulkEnvVariables)
+	}
+	keys := make([]string, 0, len(variables))
+	for key := range variables {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	entries := make([]dotenvEntry, 0, len(keys))
+	for _, key := range keys {
+		value, ok := variables[key].(string)
+		if !ok {
+			return nil, shared.InvalidArgument("variable %s must be a string", key)
+		}
+		if !envKeyPattern.MatchString(key) {
+			return nil, shared.InvalidArgument("Invalid variable name '%s'", key)
+		}
+		entries = append(entries, dotenvEntry{key: key, value: value})
+	}
+	restartDependents := true
+	if value, ok := args["restart_dependents"].(bool); ok {
+		restartDependents = value
+	}
+	wait := true
+	if value, ok := args["wait"].(bool); ok {
+		wait = value
+	}
+	display, err := resolveDisplayFormat(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkServiceUnchanged(ctx, client, args, serviceID); err != nil {
+		return nil, err
+	}
+	serviceResp, err := client.GetServiceStack(ctx, path.ServiceStackId{Id: uuid.ServiceStackId(serviceID)})
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to get service")
+	}
+	service, err := serviceResp.Output()
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to get service")
+	}
+	hostname := service.Name.Native()
+
+	results, err := applyServiceEnvBulk(ctx, client, serviceID, entries, true)
+	forgetServiceStamp(ctx, serviceID)
+	if err != nil {
+		return nil, err
+	}
+	var changed []string
+	failed := 0
+	for _, result := range results {
+		switch result.Status {
+		case "created", "updated":
+			changed = append(changed, result.Key)
+		case "failed":
+			failed++
+		}
+	}
+
+	response := applyEnvAndRestartResult{
+		ServiceID:   serviceID,
+		ServiceName: hostname,
+		Variables:   results,
+		ChangedKeys: changed,
+	}
+	if len(changed) == 0 {
+		response.Restarts = []envRestart{}
+		response.Message = "No variable changed; nothing was restarted."
+		if failed > 0 {
+			response.Message = fmt.Sprintf("%d variable(s) failed and none changed; nothing was restarted.", failed)
+		}
+		return response, nil
+	}
+
+	var dependents []envDependent
+	if restartDependents {
+		project, err := searchProject(ctx, client, string(service.ProjectId))
+		if err != nil {
+			return nil, err
+		}
+		dependents, err = findEnvDependents(ctx, client, project, serviceID, hostname, changed)
+		if err != nil {
+			return nil, err
+		}
+	}
+	response.Dependents = make([]envDependentRef, 0, len(dependents))
+	for _, dependent := range dependents {
+		response.Dependents = append(response.Dependents, envDependentRef{
+			ServiceID:   string(dependent.service.Id),
+			ServiceName: dependent.service.Name.Native(),
+			References:  dependent.references,
+			FoundIn:     dependent.sources,
+		})
+	}
+
+	type restartTarget struct {
+		id, name string
+		status   enum.ServiceStackStatusEnum
+	}
+	targets := []restartTarget{{id: serviceID, name: hostname, status: service.Status}}
+	for _, dependent := range dependents {
+		targets = append(targets, restartTarget{id: string(dependent.service.Id), name: dependent.service.Name.Native(), status: dependent.service.Status})
+	}
+
+	restarts := make([]envRestart, len(targets))
+	halted := false
+	restarted := 0
+	for i, target := range targets {
+		entry := &restarts[i]
+		entry.ServiceID = target.id
+		entry.ServiceName = target.name
+		switch {
+		case halted:
+			entry.Status = "skipped"
+			entry.Reason = "an earlier restart failed"
+			continue
+		case target.status != enum.ServiceStackStatusEnumActive:
+			entry.Status = "skipped"
+			entry.Reason = fmt.Sprintf("service is %s; it reads the new values when started", target.status)
+			continue
+		}
+
+		shared.ReportProgress(ctx, float64(i), float64(len(targets)), fmt.Sprintf("Restarting %s", target.name))
+		process, err := restartService(ctx, client, target.id)
+		if err != nil {
+			entry.Status = "failed"
+			entry.Error = err.Error()
+			halted = true
+			continue
+		}
+		entry.ProcessID = string(process.Id)
+		entry.Status = "started"
+		restarted++
+		if !wait {
+			continue
+		}
+
+		waited, err := waitForProcess(ctx, client, string(process.Id), envRestartWaitTimeout, display)
+		if err != nil {
+			entry.Status = "failed"
+			entry.Error = err.Error()
+			halted = true
+			continue
+		}
+		entry.Status = waited.Status
+		if waited.Status != "completed" {
+			halted = true
+			shared.Log(ctx, "warning", "apply_env_and_restart", fmt.Sprintf("Restart of %s ended with %s; remaining restarts skipped", target.name, waited.Status))
+		}
+	}
+	shared.ReportProgress(ctx, float64(len(targets)), float64(len(targets)), "Restarts done")
+	response.Restarts = restarts
+
+	switch {
+	case halted:
+		response.Message = "A restart did not complete; the remaining services were not restarted. Check get_service_logs of the failed service, then restart the rest with restart_service."
+	case !wait:
+		response.Message = fmt.Sprintf("%d restart(s) started. Use wait_for_process on the process_id values to follow them.", restarted)
+		for _, entry := range restarts {
+			if entry.ProcessID != "" {
+				response.SuggestNext(shared.WaitForProcess(entry.ProcessID, fmt.Sprintf("Wait until %s is restarted", entry.ServiceName)))
+			}
+		}
+	default:
+		response.Message = fmt.Sprintf("%d changed variable(s) applied; %d service(s) restarted.", len(changed), restarted)
+	}
+	if failed > 0 {
+		response.Message = fmt.Sprintf("%d variable(s) failed to set. %s", failed, response.Message)
+	}
+	return response, nil
+}
+
+// findEnvDependents returns the services of the project that reference any of the keys of
+// hostname, ordered by hostname. References are read from each service's env variables and
+// from run.envVariables of its latest deployed zerops.yml.
+func findEnvDependents(ctx context.Context, client *sdk.Handler, project output.EsProject, serviceID, hostname string, keys []string) ([]envDependent, error) {
+	wanted := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		wanted[hostname+"_"+key] = true
+	}
+	referencedIn := func(content string) []string {
+		var found []string
+		for _, match := range envReferencePattern.FindAllStringSubmatch(content, -1) {
+			if wanted[match[1]] {
+				found = append(found, "${"+match[1]+"}")
+			}
+		}
+		return found
+	}
+
+	services, err := searchProjectServices(ctx, client, project)
+	if err != nil {
+		return nil, err
+	}
+	var dependents []envDependent
+	for _, service := range services {
+		if string(service.Id) == serviceID || service.IsSystem.Native() {
+			continue
+		}
+		references := map[string]bool{}
+		var sources []string
+
+		if envResp, err := client.GetServiceStackEnv(ctx, path.ServiceStackId{Id: service.Id}); err == nil {
+			if envOutput, err := envResp.Output(); err == nil {
+				found := false
+				for _, env := range envOutput.Items {
+					for _, reference := range referencedIn(env.Content.Native()) {
+						references[reference] = true
+						found = true
+					}
+				}
+				if found {
+					sources = append(sources, "service env")
+				}
+			}
+		}
+
+		if versions, err := deployedAppVersions(ctx, client, string(service.Id)); err == nil {
+			for _, version := range versions {
+				config, ok := version.ConfigContent.Get()
+				if !ok || config.Native() == "" {
+					continue
+				}
+				// Only the newest deployed config matters
+				var zeropsYaml struct {
+					Zerops []zeropsEnvSetup `yaml:"zerops"`
+				}
+				if yaml.Unmarshal([]byte(config.Native()), &zeropsYaml) == nil {
+					found := false
+					for _, setup := range zeropsYaml.Zerops {
+						for _, value := range setup.Run.EnvVariables {
+							for _, reference := range referencedIn(value) {
+								references[reference] = true
+								found = true
+							}
+						}
+					}
+					if found {
+						sources = append(sources, "zerops.yml")
+					}
+				}
+				break
+			}
+		}
+
+		if len(references) > 0 {
+			list := make([]string, 0, len(references))
+			for reference := range references {
+				list = append(list, reference)
+			}
+			sort.Strings(list)
+			dependents = append(dependents, envDependent{service: service, references: list, sources: sources})
+		}
+	}
+	sort.Slice(dependents, func(i, j int) bool {
+		return strings.Compare(dependents[i].service.Name.Native(), dependents[j].service.Name.Native()) < 0
+	})
+	return dependents, nil
+}
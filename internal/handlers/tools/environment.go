@@ -3,42 +3,89 @@ package tools
 import (
 	"context"
 	"fmt"
-	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 
-	"github.com/zerops-mcp-basic/internal/handlers/shared"
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
 	"github.com/zeropsio/zerops-go/dto/input/body"
+	"github.com/zeropsio/zerops-go/dto/input/path"
+	"github.com/zeropsio/zerops-go/dto/output"
 	"github.com/zeropsio/zerops-go/sdk"
 	"github.com/zeropsio/zerops-go/types"
+	"github.com/zeropsio/zerops-go/types/enum"
 	"github.com/zeropsio/zerops-go/types/uuid"
 )
 
+// secretKeyPattern matches variable names that hold credentials even when not flagged sensitive
+var secretKeyPattern = regexp.MustCompile(`(?i)(password|secret|token|key|credential|private)`)
+
+// maskedValue replaces secret values when masking is on
+const maskedValue = "********"
+
+// envReadProperties is the input schema shared by the env read tools
+func envReadProperties() map[string]interface{} {
+	return map[string]interface{}{
+		"mask_secrets": map[string]interface{}{
+			"type":        "boolean",
+			"description": "OPTIONAL: Replace values of sensitive variables and names like *PASSWORD*, *SECRET*, *TOKEN*, *KEY* (default: true)",
+		},
+		"output": map[string]interface{}{
+			"type":        "string",
+			"description": "OPTIONAL: 'json' (default) or 'text' (KEY=value lines, .env style)",
+			"enum":        []string{"json", "text"},
+		},
+	}
+}
+
 // RegisterEnvironment registers environment variable tools
 func RegisterEnvironment() {
+	// Read project environment variables
+	projectProperties := envReadProperties()
+	projectProperties["project_id"] = map[string]interface{}{
+		"type":        "string",
+		"description": "OPTIONAL: Project ID. If not provided, will check $projectId environment variable.",
+		"pattern":     "^[A-Za-z0-9_-]+$",
+	}
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "get_project_env",
+		Description: toolDescription("get_project_env"),
+		InputSchema: map[string]interface{}{
+			"type":                 "object",
+			"properties":           projectProperties,
+			"additionalProperties": false,
+		},
+		Annotations: shared.ReadOnly(),
+		Output:      envVariablesResult{},
+		Handler:     handleGetProjectEnv,
+	})
+
+	// Read service environment variables
+	serviceProperties := envReadProperties()
+	serviceProperties["service_id"] = map[string]interface{}{
+		"type":        "string",
+		"description": "REQUIRED: Service ID from discovery tool",
+		"pattern":     "^[A-Za-z0-9_-]+$",
+	}
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "get_service_env",
+		Description: toolDescription("get_service_env"),
+		InputSchema: map[string]interface{}{
+			"type":                 "object",
+			"properties":           serviceProperties,
+			"required":             []string{"service_id"},
+			"additionalProperties": false,
+		},
+		Annotations: shared.ReadOnly(),
+		Output:      envVariablesResult{},
+		Handler:     handleGetServiceEnv,
+	})
+
 	// Set project environment variable
 	shared.GlobalRegistry.Register(&shared.ToolDefinition{
 		Name:        "set_project_env",
-		Description: `Sets environment variables at the project level, making them available to all services.
-
-PROJECT ENVIRONMENT VARIABLES:
-- Available to ALL services in the project
-- Good for shared configuration (database URLs, API keys, etc.)
-- Override service-level variables with same name
-
-SECURITY:
-- Never use for sensitive data in logs
-- Consider using Zerops secrets for sensitive values
-- Environment variables are visible to all project services
-
-WHEN TO USE:
-- Shared database connection strings
-- API endpoints used by multiple services
-- Global application configuration
-- Feature flags
-
-NAMING CONVENTIONS:
-- Use UPPERCASE for environment variables
-- Use underscores for word separation
-- Prefix with app/service name for clarity: "MYAPP_DATABASE_URL"`,
+		Description: toolDescription("set_project_env"),
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
@@ -63,34 +110,15 @@ NAMING CONVENTIONS:
 			"required":             []string{"key", "value"},
 			"additionalProperties": false,
 		},
-		Handler: handleSetProjectEnv,
+		Annotations: shared.Mutating(true, true),
+		Output:      envChangeResult{},
+		Handler:     handleSetProjectEnv,
 	})
 
 	// Set service environment variable
 	shared.GlobalRegistry.Register(&shared.ToolDefinition{
 		Name:        "set_service_env",
-		Description: `Sets environment variables for a specific service only.
-
-SERVICE ENVIRONMENT VARIABLES:
-- Available only to the specified service
-- Override project-level variables with same name
-- Good for service-specific configuration
-
-USE CASES:
-- Service-specific ports or configurations
-- Service-specific API keys or tokens
-- Runtime-specific settings
-- Service-specific feature flags
-
-PRIORITY ORDER (highest to lowest):
-1. Service-level environment variables
-2. Project-level environment variables  
-3. Default application values
-
-WHEN TO USE:
-- Service needs different config than others
-- Service-specific secrets or keys
-- Runtime-specific environment settings`,
+		Description: toolDescription("set_service_env"),
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
@@ -111,39 +139,110 @@ WHEN TO USE:
 					"description": "REQUIRED: Environment variable value",
 					"maxLength":   10000,
 				},
+				"expected_last_update": expectedLastUpdateProperty(),
 			},
 			"required":             []string{"service_id", "key", "value"},
 			"additionalProperties": false,
 		},
-		Handler: handleSetServiceEnv,
+		Annotations: shared.Mutating(true, true),
+		Output:      envChangeResult{},
+		Handler:     handleSetServiceEnv,
+	})
+
+	// Delete project environment variable
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "delete_project_env",
+		Description: toolDescription("delete_project_env"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"project_id": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Project ID. If not provided, will check $projectId environment variable.",
+					"pattern":     "^[A-Za-z0-9_-]+$",
+				},
+				"key": map[string]interface{}{
+					"type":        "string",
+					"description": "REQUIRED: Environment variable name",
+					"minLength":   1,
+				},
+				"confirm": map[string]interface{}{
+					"type":        "boolean",
+					"description": "REQUIRED: Must be true to delete the variable",
+				},
+			},
+			"required":             []string{"key", "confirm"},
+			"additionalProperties": false,
+		},
+		Annotations: shared.Mutating(true, false),
+		Output:      envChangeResult{},
+		Handler:     handleDeleteProjectEnv,
+	})
+
+	// Delete service environment variable
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "delete_service_env",
+		Description: toolDescription("delete_service_env"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"service_id": map[string]interface{}{
+					"type":        "string",
+					"description": "REQUIRED: Service ID from discovery tool",
+					"pattern":     "^[A-Za-z0-9_-]+$",
+				},
+				"key": map[string]interface{}{
+					"type":        "string",
+					"description": "REQUIRED: Environment variable name",
+					"minLength":   1,
+				},
+				"confirm": map[string]interface{}{
+					"type":        "boolean",
+					"description": "REQUIRED: Must be true to delete the variable",
+				},
+				"expected_last_update": expectedLastUpdateProperty(),
+			},
+			"required":             []string{"service_id", "key", "confirm"},
+			"additionalProperties": false,
+		},
+		Annotations: shared.Mutating(true, false),
+		Output:      envChangeResult{},
+		Handler:     handleDeleteServiceEnv,
 	})
 }
 
+// envChangeResult is the result of the tools that set or delete a single variable
+type envChangeResult struct {
+	ProcessID string `json:"process_id"`
+	Status    string `json:"status"`
+	ServiceID string `json:"service_id,omitempty"`
+	Key       string `json:"key"`
+	Message   string `json:"message"`
+	shared.Suggestions
+}
+
 func handleSetProjectEnv(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
 	if client == nil {
-		return shared.ErrorResponse("No API key provided"), nil
+		return nil, shared.ErrNoClient
 	}
 
-	projectID, ok := args["project_id"].(string)
-	if !ok || projectID == "" {
-		// Check environment variable
-		if envProjectID := os.Getenv("projectId"); envProjectID != "" {
-			projectID = envProjectID
-		} else {
-			return shared.ErrorResponse("Project ID is required. Provide project_id parameter or set $projectId environment variable."), nil
-		}
+	projectID, err := resolveProjectID(ctx, client, args)
+	if err != nil {
+		return nil, err
 	}
 
 	key, ok := args["key"].(string)
 	if !ok || key == "" {
-		return shared.ErrorResponse("Environment variable key is required"), nil
+		return nil, shared.InvalidArgument("Environment variable key is required")
 	}
 
 	value, ok := args["value"].(string)
 	if !ok {
-		return shared.ErrorResponse("Environment variable value is required"), nil
+		return nil, shared.InvalidArgument("Environment variable value is required")
 	}
 
+	recordSnapshot(ctx, client, projectID, "set_project_env", key)
+
 	// Create project env body
 	envBody := body.ProjectEnvPost{
 		ProjectId: uuid.ProjectId(projectID),
@@ -154,52 +253,396 @@ func handleSetProjectEnv(ctx context.Context, client *sdk.Handler, args map[stri
 	// Add environment variable
 	resp, err := client.PostProjectEnv(ctx, envBody)
 	if err != nil {
-		return shared.ErrorResponse(fmt.Sprintf("Failed to set project environment variable: %v", err)), nil
+		return nil, shared.WrapAPIError(err, "Failed to set project environment variable")
 	}
 
 	output, err := resp.Output()
 	if err != nil {
-		return shared.ErrorResponse(fmt.Sprintf("Failed to parse response: %v", err)), nil
+		return nil, shared.WrapAPIError(err, "Failed to parse response")
 	}
 
-	return map[string]interface{}{
-		"process_id": string(output.Id),
-		"status":     "env_var_set",
-		"key":        key,
-		"message":    fmt.Sprintf("Project environment variable '%s' has been set", key),
+	return envChangeResult{
+		ProcessID: string(output.Id),
+		Status:    "env_var_set",
+		Key:       key,
+		Message:   fmt.Sprintf("Project environment variable '%s' has been set", key),
 	}, nil
 }
 
 func handleSetServiceEnv(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
 	if client == nil {
-		return shared.ErrorResponse("No API key provided"), nil
+		return nil, shared.ErrNoClient
 	}
 
 	serviceID, ok := args["service_id"].(string)
 	if !ok || serviceID == "" {
-		return shared.ErrorResponse("Service ID is required"), nil
+		return nil, shared.InvalidArgument("Service ID is required")
 	}
 
 	key, ok := args["key"].(string)
 	if !ok || key == "" {
-		return shared.ErrorResponse("Environment variable key is required"), nil
+		return nil, shared.InvalidArgument("Environment variable key is required")
 	}
 
 	value, ok := args["value"].(string)
 	if !ok {
-		return shared.ErrorResponse("Environment variable value is required"), nil
+		return nil, shared.InvalidArgument("Environment variable value is required")
 	}
 
-	// Note: Service environment variables in Zerops are called UserData
-	// The SDK may not have a direct method for this
-	// This is a simplified response
-	_ = value // Mark as used
-	
-	return map[string]interface{}{
-		"status":        "env_var_configured",
-		"service_id":    serviceID,
-		"key":           key,
-		"message":       fmt.Sprintf("Service environment variable '%s' has been configured", key),
-		"note":          "Service environment variables are managed as UserData in Zerops",
-	}, nil
-}
\ No newline at end of file
+	if err := checkServiceUnchanged(ctx, client, args, serviceID); err != nil {
+		return nil, err
+	}
+
+	if projectID, err := serviceProjectID(ctx, client, serviceID); err == nil {
+		recordSnapshot(ctx, client, projectID, "set_service_env", serviceID+"/"+key)
+	}
+
+	live, err := serviceEnvByKey(ctx, client, uuid.ServiceStackId(serviceID))
+	if err != nil {
+		return nil, err
+	}
+	process, created, err := upsertServiceEnv(ctx, client, uuid.ServiceStackId(serviceID), live, key, value)
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to set service environment variable")
+	}
+	status := "env_var_updated"
+	if created {
+		status = "env_var_created"
+	}
+	forgetServiceStamp(ctx, serviceID)
+
+	result := envChangeResult{
+		ProcessID: string(process.Id),
+		Status:    status,
+		ServiceID: serviceID,
+		Key:       key,
+		Message:   fmt.Sprintf("Service environment variable '%s' is being set. Use 'get_process_status' to monitor progress.", key),
+	}
+	result.SuggestNext(
+		shared.WaitForProcess(string(process.Id), "Wait until the variable is set"),
+		shared.NextCall{
+			Tool:      "restart_service",
+			Arguments: map[string]interface{}{"service_id": serviceID},
+			Purpose:   "Running containers pick up the variable after a restart",
+		},
+	)
+	return result, nil
+}
+
+// serviceEnvByKey reads the environment variables of a service, keyed by name
+func serviceEnvByKey(ctx context.Context, client *sdk.Handler, serviceID uuid.ServiceStackId) (map[string]output.ServiceStackEnv, error) {
+	envResp, err := client.GetServiceStackEnv(ctx, path.ServiceStackId{Id: serviceID})
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to get service environment variables")
+	}
+	envOutput, err := envResp.Output()
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to parse service environment variables")
+	}
+	live := make(map[string]output.ServiceStackEnv, len(envOutput.Items))
+	for _, env := range envOutput.Items {
+		live[env.Key.Native()] = env
+	}
+	return live, nil
+}
+
+// upsertServiceEnv sets a service environment variable. Service variables are user data in
+// Zerops: a key present in live is updated in place, otherwise it is created (created is true).
+// Variables generated by Zerops can't be changed.
+func upsertServiceEnv(ctx context.Context, client *sdk.Handler, serviceID uuid.ServiceStackId, live map[string]output.ServiceStackEnv, key, value string) (process output.Process, created bool, err error) {
+	current, exists := live[key]
+	if !exists {
+		resp, err := client.PostUserData(ctx, body.UserDataPost{
+			ServiceStackId: serviceID,
+			Key:            types.NewString(key),
+			Content:        types.NewText(value),
+		})
+		if err != nil {
+			return output.Process{}, true, err
+		}
+		process, err = resp.Output()
+		return process, true, err
+	}
+
+	if current.Type == enum.UserDataTypeEnumReadOnly || current.Type == enum.UserDataTypeEnumInternal {
+		return output.Process{}, false, shared.InvalidArgument("Variable '%s' is generated by Zerops (%s) and cannot be changed", key, current.Type)
+	}
+	resp, err := client.PutUserData(ctx, path.UserDataId{Id: current.Id}, body.UserDataPut{
+		Key:     types.NewString(key),
+		Content: types.NewText(value),
+	})
+	if err != nil {
+		return output.Process{}, false, err
+	}
+	process, err = resp.Output()
+	return process, false, err
+}
+
+// envEntry is one variable returned by the env read tools
+type envEntry struct {
+	Key       string `json:"key"`
+	Value     string `json:"value"`
+	Sensitive bool   `json:"sensitive"`
+	Type      string `json:"type,omitempty"`
+}
+
+// envVariablesResult is the result of get_project_env and get_service_env. Variables holds
+// the entries for the json output, Env the .env text for the text output.
+type envVariablesResult struct {
+	ProjectID   string     `json:"project_id,omitempty"`
+	ProjectName string     `json:"project_name,omitempty"`
+	ServiceID   string     `json:"service_id,omitempty"`
+	Count       int        `json:"count"`
+	Masked      int        `json:"masked"`
+	Variables   []envEntry `json:"variables,omitempty"`
+	Env         string     `json:"env,omitempty"`
+}
+
+func handleGetProjectEnv(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+
+	projectID, err := resolveProjectID(ctx, client, args)
+	if err != nil {
+		return nil, err
+	}
+	project, err := searchProject(ctx, client, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]envEntry, 0, len(project.EnvList))
+	for _, env := range project.EnvList {
+		entries = append(entries, envEntry{
+			Key:       env.Key.Native(),
+			Value:     env.Content.Native(),
+			Sensitive: env.Sensitive.Native(),
+		})
+	}
+
+	result, err := envReadResult(entries, args)
+	if err != nil {
+		return nil, err
+	}
+	result.ProjectID = projectID
+	result.ProjectName = project.Name.Native()
+	return result, nil
+}
+
+func handleGetServiceEnv(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+
+	serviceID, ok := args["service_id"].(string)
+	if !ok || serviceID == "" {
+		return nil, shared.InvalidArgument("Service ID is required")
+	}
+
+	envResp, err := client.GetServiceStackEnv(ctx, path.ServiceStackId{Id: uuid.ServiceStackId(serviceID)})
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to get service environment variables")
+	}
+	envOutput, err := envResp.Output()
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to parse service environment variables")
+	}
+
+	entries := make([]envEntry, 0, len(envOutput.Items))
+	for _, env := range envOutput.Items {
+		entries = append(entries, envEntry{
+			Key:       env.Key.Native(),
+			Value:     env.Content.Native(),
+			Sensitive: env.Sensitive.Native(),
+			Type:      string(env.Type),
+		})
+	}
+
+	result, err := envReadResult(entries, args)
+	if err != nil {
+		return nil, err
+	}
+	result.ServiceID = serviceID
+	return result, nil
+}
+
+// envReadResult sorts and masks the entries and renders them as JSON or .env text
+func envReadResult(entries []envEntry, args map[string]interface{}) (*envVariablesResult, error) {
+	mask := true
+	if value, ok := args["mask_secrets"].(bool); ok {
+		mask = value
+	}
+	format, _ := args["output"].(string)
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "text" {
+		return nil, shared.InvalidArgument("output must be 'json' or 'text'")
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Key < entries[j].Key
+	})
+	masked := 0
+	for i := range entries {
+		if mask && (entries[i].Sensitive || secretKeyPattern.MatchString(entries[i].Key)) {
+			entries[i].Value = maskedValue
+			masked++
+		}
+	}
+
+	result := &envVariablesResult{
+		Count:  len(entries),
+		Masked: masked,
+	}
+	if format == "text" {
+		var lines []string
+		for _, entry := range entries {
+			value := entry.Value
+			if strings.ContainsAny(value, " \t\n\"'#$") {
+				value = strconv.Quote(value)
+			}
+			lines = append(lines, entry.Key+"="+value)
+		}
+		result.Env = strings.Join(lines, "\n")
+	} else {
+		result.Variables = entries
+	}
+	return result, nil
+}
+
+func handleDeleteProjectEnv(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+
+	projectID, err := resolveProjectID(ctx, client, args)
+	if err != nil {
+		return nil, err
+	}
+
+	key, ok := args["key"].(string)
+	if !ok || key == "" {
+		return nil, shared.InvalidArgument("Environment variable key is required")
+	}
+	if confirm, _ := args["confirm"].(bool); !confirm {
+		return nil, shared.InvalidArgument("Deleting project environment variable '%s' requires confirm: true", key)
+	}
+
+	project, err := searchProject(ctx, client, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	var env *output.ProjectEnv
+	for i := range project.EnvList {
+		if project.EnvList[i].Key.Native() == key {
+			env = &project.EnvList[i]
+			break
+		}
+	}
+	if env == nil {
+		return nil, shared.NotFound("Project has no environment variable '%s'", key)
+	}
+	if env.Type == enum.EnvTypeEnumSystem || !env.Editable.Native() {
+		return nil, shared.InvalidArgument("'%s' is a system variable and cannot be deleted", key)
+	}
+
+	recordSnapshot(ctx, client, projectID, "delete_project_env", key)
+
+	resp, err := client.DeleteProjectEnv(ctx, path.ProjectEnvId{Id: env.Id})
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to delete project environment variable")
+	}
+	process, err := resp.Output()
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to parse response")
+	}
+
+	result := envChangeResult{
+		ProcessID: string(process.Id),
+		Status:    "env_var_deleted",
+		Key:       key,
+		Message:   fmt.Sprintf("Project environment variable '%s' is being deleted. Use 'get_process_status' to monitor progress.", key),
+	}
+	result.SuggestNext(shared.WaitForProcess(string(process.Id), "Wait until the variable is deleted"))
+	return result, nil
+}
+
+func handleDeleteServiceEnv(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+
+	serviceID, ok := args["service_id"].(string)
+	if !ok || serviceID == "" {
+		return nil, shared.InvalidArgument("Service ID is required")
+	}
+
+	key, ok := args["key"].(string)
+	if !ok || key == "" {
+		return nil, shared.InvalidArgument("Environment variable key is required")
+	}
+	if confirm, _ := args["confirm"].(bool); !confirm {
+		return nil, shared.InvalidArgument("Deleting service environment variable '%s' requires confirm: true", key)
+	}
+
+	if err := checkServiceUnchanged(ctx, client, args, serviceID); err != nil {
+		return nil, err
+	}
+
+	envResp, err := client.GetServiceStackEnv(ctx, path.ServiceStackId{Id: uuid.ServiceStackId(serviceID)})
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to get service environment variables")
+	}
+	envOutput, err := envResp.Output()
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to parse service environment variables")
+	}
+
+	var env *output.ServiceStackEnv
+	for i := range envOutput.Items {
+		if envOutput.Items[i].Key.Native() == key {
+			env = &envOutput.Items[i]
+			break
+		}
+	}
+	if env == nil {
+		return nil, shared.NotFound("Service has no environment variable '%s'", key)
+	}
+	if env.Type == enum.UserDataTypeEnumReadOnly || env.Type == enum.UserDataTypeEnumInternal {
+		return nil, shared.InvalidArgument("'%s' is generated by Zerops (%s) and cannot be deleted", key, env.Type)
+	}
+
+	if projectID, err := serviceProjectID(ctx, client, serviceID); err == nil {
+		recordSnapshot(ctx, client, projectID, "delete_service_env", serviceID+"/"+key)
+	}
+
+	resp, err := client.DeleteUserData(ctx, path.UserDataId{Id: env.Id})
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to delete service environment variable")
+	}
+	process, err := resp.Output()
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to parse response")
+	}
+	forgetServiceStamp(ctx, serviceID)
+
+	result := envChangeResult{
+		ProcessID: string(process.Id),
+		Status:    "env_var_deleted",
+		ServiceID: serviceID,
+		Key:       key,
+		Message:   fmt.Sprintf("Service environment variable '%s' is being deleted. Use 'get_process_status' to monitor progress.", key),
+	}
+	result.SuggestNext(
+		shared.WaitForProcess(string(process.Id), "Wait until the variable is deleted"),
+		shared.NextCall{
+			Tool:      "restart_service",
+			Arguments: map[string]interface{}{"service_id": serviceID},
+			Purpose:   "Running containers keep the variable until restarted",
+		},
+	)
+	return result, nil
+}
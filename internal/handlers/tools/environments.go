@@ -0,0 +1,469 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/dto/input/body"
+	"github.com/zeropsio/zerops-go/dto/input/path"
+	"github.com/zeropsio/zerops-go/dto/output"
+	"github.com/zeropsio/zerops-go/sdk"
+	"github.com/zeropsio/zerops-go/types"
+	"gopkg.in/yaml.v3"
+)
+
+// environmentHostnameSuffixes map hostname suffixes, including long forms, to the environment they mark
+var environmentHostnameSuffixes = []struct {
+	suffix      string
+	environment string
+}{
+	{"production", "prod"},
+	{"staging", "stage"},
+	{"stage", "stage"},
+	{"prod", "prod"},
+	{"dev", "dev"},
+}
+
+// environmentNames are the environments services are planned and created for, with their hostname suffix
+var environmentNames = map[string]string{"dev": "dev", "stage": "stage", "prod": "prod"}
+
+// detectEnvironment splits a hostname into its base name and environment; env is empty for
+// hostnames without an environment suffix (shared services such as databases)
+func detectEnvironment(hostname string) (base, env string) {
+	for _, candidate := range environmentHostnameSuffixes {
+		if strings.HasSuffix(hostname, candidate.suffix) && len(hostname) > len(candidate.suffix) {
+			return strings.TrimSuffix(hostname, candidate.suffix), candidate.environment
+		}
+	}
+	return hostname, ""
+}
+
+// RegisterEnvironments registers the list_environments and create_environment tools
+func RegisterEnvironments() {
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "list_environments",
+		Description: toolDescription("list_environments"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"project_id": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Project ID (default: $projectId)",
+					"pattern":     "^[A-Za-z0-9_-]+$",
+				},
+			},
+			"additionalProperties": false,
+		},
+		Annotations: shared.ReadOnly(),
+		Output:      listEnvironmentsResult{},
+		Handler:     handleListEnvironments,
+	})
+
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "create_environment",
+		Description: toolDescription("create_environment"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"project_id": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Project ID (default: $projectId)",
+					"pattern":     "^[A-Za-z0-9_-]+$",
+				},
+				"source_environment": map[string]interface{}{
+					"type":        "string",
+					"description": "REQUIRED: Environment to copy",
+					"enum":        []string{"dev", "stage", "prod"},
+				},
+				"environment": map[string]interface{}{
+					"type":        "string",
+					"description": "REQUIRED: Environment to create",
+					"enum":        []string{"dev", "stage", "prod"},
+				},
+				"services": map[string]interface{}{
+					"type":        "array",
+					"description": "OPTIONAL: Base names or hostnames to clone (default: every service of the source environment)",
+					"items":       map[string]interface{}{"type": "string"},
+				},
+				"copy_secrets": map[string]interface{}{
+					"type":        "boolean",
+					"description": "OPTIONAL: Copy literal secret env values too (default: false)",
+					"default":     false,
+				},
+			},
+			"required":             []string{"source_environment", "environment"},
+			"additionalProperties": false,
+		},
+		Annotations: shared.Mutating(false, false),
+		Output:      createEnvironmentResult{},
+		Handler:     handleCreateEnvironment,
+	})
+}
+
+// listEnvironmentsResult is the result of list_environments. Environments groups the
+// services by environment suffix; Shared holds services without one.
+type listEnvironmentsResult struct {
+	ProjectID    string                          `json:"project_id"`
+	ProjectName  string                          `json:"project_name"`
+	Environments map[string][]environmentService `json:"environments"`
+	Shared       []environmentService            `json:"shared"`
+	Bases        []environmentBase               `json:"bases"`
+}
+
+// environmentService is a service as grouped by list_environments
+type environmentService struct {
+	Hostname  string `json:"hostname"`
+	ServiceID string `json:"service_id"`
+	Type      string `json:"type"`
+	Status    string `json:"status"`
+	Base      string `json:"base,omitempty"`
+}
+
+// environmentBase lists the environments a base hostname exists in and those it is missing from
+type environmentBase struct {
+	Base         string   `json:"base"`
+	Environments []string `json:"environments"`
+	Missing      []string `json:"missing"`
+}
+
+func handleListEnvironments(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+
+	projectID, err := resolveProjectID(ctx, client, args)
+	if err != nil {
+		return nil, err
+	}
+	project, err := searchProject(ctx, client, projectID)
+	if err != nil {
+		return nil, err
+	}
+	services, err := searchProjectServices(ctx, client, project)
+	if err != nil {
+		return nil, err
+	}
+
+	environments := map[string][]environmentService{}
+	sharedServices := []environmentService{}
+	bases := map[string][]string{}
+	for _, service := range services {
+		hostname := service.Name.Native()
+		base, env := detectEnvironment(hostname)
+		entry := environmentService{
+			Hostname:  hostname,
+			ServiceID: string(service.Id),
+			Type:      service.ServiceStackTypeInfo.ServiceStackTypeVersionName.Native(),
+			Status:    string(service.Status),
+		}
+		if env == "" {
+			sharedServices = append(sharedServices, entry)
+			continue
+		}
+		entry.Base = base
+		environments[env] = append(environments[env], entry)
+		bases[base] = append(bases[base], env)
+	}
+
+	envNames := make([]string, 0, len(environments))
+	for env := range environments {
+		envNames = append(envNames, env)
+	}
+	sort.Strings(envNames)
+
+	baseSummary := make([]environmentBase, 0, len(bases))
+	baseNames := make([]string, 0, len(bases))
+	for base := range bases {
+		baseNames = append(baseNames, base)
+	}
+	sort.Strings(baseNames)
+	for _, base := range baseNames {
+		present := map[string]bool{}
+		for _, env := range bases[base] {
+			present[env] = true
+		}
+		var missing []string
+		for _, env := range envNames {
+			if !present[env] {
+				missing = append(missing, env)
+			}
+		}
+		sort.Strings(bases[base])
+		baseSummary = append(baseSummary, environmentBase{
+			Base:         base,
+			Environments: bases[base],
+			Missing:      missing,
+		})
+	}
+
+	return listEnvironmentsResult{
+		ProjectID:    projectID,
+		ProjectName:  project.Name.Native(),
+		Environments: environments,
+		Shared:       sharedServices,
+		Bases:        baseSummary,
+	}, nil
+}
+
+// createEnvironmentResult is the result of create_environment
+type createEnvironmentResult struct {
+	Status            string               `json:"status"`
+	ProjectID         string               `json:"project_id,omitempty"`
+	SourceEnvironment string               `json:"source_environment,omitempty"`
+	Environment       string               `json:"environment,omitempty"`
+	Services          []importedService    `json:"services,omitempty"`
+	SecretsCopied     bool                 `json:"secrets_copied"`
+	YAML              string               `json:"yaml,omitempty"`
+	Skipped           []skippedEnvironment `json:"skipped,omitempty"`
+	EnvToSet          []string             `json:"env_to_set,omitempty"`
+	Note              string               `json:"note,omitempty"`
+	Message           string               `json:"message"`
+	shared.Suggestions
+}
+
+// importedService is a service created by an import, with the process creating it
+type importedService struct {
+	Hostname  string              `json:"hostname"`
+	ServiceID string              `json:"service_id"`
+	ProcessID string              `json:"process_id,omitempty"`
+	Error     *output.ErrorObject `json:"error,omitempty"`
+}
+
+// skippedEnvironment is a source service that got no counterpart
+type skippedEnvironment struct {
+	Hostname string `json:"hostname"`
+	Reason   string `json:"reason"`
+}
+
+func handleCreateEnvironment(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+
+	sourceEnv, _ := args["source_environment"].(string)
+	targetEnv, _ := args["environment"].(string)
+	if _, ok := environmentNames[sourceEnv]; !ok {
+		return nil, shared.InvalidArgument("source_environment must be dev, stage or prod")
+	}
+	targetSuffix, ok := environmentNames[targetEnv]
+	if !ok {
+		return nil, shared.InvalidArgument("environment must be dev, stage or prod")
+	}
+	if sourceEnv == targetEnv {
+		return nil, shared.InvalidArgument("environment must differ from source_environment")
+	}
+	copySecrets, _ := args["copy_secrets"].(bool)
+
+	selected := map[string]bool{}
+	if list, ok := args["services"].([]interface{}); ok {
+		for _, item := range list {
+			if name, ok := item.(string); ok && name != "" {
+				selected[name] = true
+			}
+		}
+	}
+
+	projectID, err := resolveProjectID(ctx, client, args)
+	if err != nil {
+		return nil, err
+	}
+	project, err := searchProject(ctx, client, projectID)
+	if err != nil {
+		return nil, err
+	}
+	services, err := searchProjectServices(ctx, client, project)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := map[string]bool{}
+	for _, service := range services {
+		existing[service.Name.Native()] = true
+	}
+
+	// renames maps every source hostname to its counterpart so references can be rewritten,
+	// including services that already exist in the target environment
+	renames := map[string]string{}
+	var sources []output.EsServiceStack
+	var skipped []skippedEnvironment
+	for _, service := range services {
+		hostname := service.Name.Native()
+		base, env := detectEnvironment(hostname)
+		if env != sourceEnv {
+			continue
+		}
+		target := base + targetSuffix
+		renames[hostname] = target
+		if len(selected) > 0 && !selected[base] && !selected[hostname] {
+			continue
+		}
+		switch {
+		case !hostnamePattern.MatchString(target):
+			skipped = append(skipped, skippedEnvironment{Hostname: hostname, Reason: fmt.Sprintf("'%s' is not a valid hostname", target)})
+		case existing[target]:
+			skipped = append(skipped, skippedEnvironment{Hostname: hostname, Reason: fmt.Sprintf("'%s' already exists", target)})
+		default:
+			sources = append(sources, service)
+		}
+	}
+	if len(sources) == 0 {
+		result := createEnvironmentResult{
+			Status:  "nothing_to_create",
+			Skipped: skipped,
+		}
+		if len(renames) == 0 {
+			result.Message = fmt.Sprintf("Project has no %s services. Hostnames must end with the environment suffix, e.g. api%s.", sourceEnv, sourceEnv)
+		} else {
+			result.Message = fmt.Sprintf("Every selected %s service already has a %s counterpart.", sourceEnv, targetEnv)
+		}
+		return result, nil
+	}
+
+	definitions := make([]interface{}, 0, len(sources))
+	var dropped []string
+	for _, service := range sources {
+		exportResp, err := client.GetServiceStackExport(ctx, path.ServiceStackId{Id: service.Id})
+		if err != nil {
+			return nil, shared.WrapAPIError(err, "Failed to export service "+service.Name.Native())
+		}
+		exportOutput, err := exportResp.Output()
+		if err != nil {
+			return nil, shared.WrapAPIError(err, "Failed to export service "+service.Name.Native())
+		}
+
+		hostname := service.Name.Native()
+		definition, err := cloneServiceDefinition(exportOutput.Yaml.Native(), hostname, renames[hostname], true)
+		if err != nil {
+			return nil, err
+		}
+		dropped = append(dropped, linkEnvironmentEnv(definition, renames, copySecrets)...)
+		if targetEnv == "dev" {
+			definition["startWithoutCode"] = true
+		} else {
+			delete(definition, "startWithoutCode")
+		}
+		definitions = append(definitions, definition)
+	}
+
+	importYaml, err := yaml.Marshal(map[string]interface{}{"services": definitions})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build environment YAML: %w", err)
+	}
+
+	recordSnapshot(ctx, client, projectID, "create_environment", targetEnv)
+
+	importResp, err := client.PostServiceStackImport(ctx, body.ServiceStackImport{
+		ProjectId: project.Id,
+		Yaml:      types.NewText(string(importYaml)),
+	})
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Environment import failed")
+	}
+	importOutput, err := importResp.Output()
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Environment import failed")
+	}
+
+	created := make([]importedService, 0, len(importOutput.ServiceStacks))
+	for _, stack := range importOutput.ServiceStacks {
+		entry := importedService{
+			Hostname:  stack.Name.Native(),
+			ServiceID: string(stack.Id),
+			Error:     stack.Error,
+		}
+		if len(stack.Processes) > 0 {
+			entry.ProcessID = string(stack.Processes[0].Id)
+		}
+		created = append(created, entry)
+	}
+
+	result := createEnvironmentResult{
+		Status:            "environment_started",
+		ProjectID:         projectID,
+		SourceEnvironment: sourceEnv,
+		Environment:       targetEnv,
+		Services:          created,
+		SecretsCopied:     copySecrets,
+		YAML:              string(importYaml),
+		Skipped:           skipped,
+		Message:           fmt.Sprintf("Creating %d %s services. Use wait_for_process on each process_id, then deploy with the %s setup.", len(created), targetEnv, targetEnv),
+	}
+	if len(dropped) > 0 {
+		result.EnvToSet = dropped
+		result.Note = "Literal secret values were not copied; set them on the new services with set_service_env or set_env_bulk."
+	}
+	for _, entry := range created {
+		if entry.ProcessID != "" {
+			result.SuggestNext(shared.WaitForProcess(entry.ProcessID, fmt.Sprintf("Wait until %s is created", entry.Hostname)))
+		}
+	}
+	return result, nil
+}
+
+// linkEnvironmentEnv rewrites references to source environment hostnames in the env
+// variables of a cloned definition. Without copySecrets, literal values are removed and
+// their keys returned as "<hostname>.<key>".
+func linkEnvironmentEnv(definition map[string]interface{}, renames map[string]string, copySecrets bool) []string {
+	hostname, _ := definition["hostname"].(string)
+	var dropped []string
+
+	if secrets, ok := definition["envSecrets"].(map[string]interface{}); ok {
+		for key, value := range secrets {
+			text, ok := value.(string)
+			if !ok {
+				continue
+			}
+			linked := renameHostnames(text, renames)
+			if !copySecrets && linked == text && !strings.Contains(text, "${") {
+				delete(secrets, key)
+				dropped = append(dropped, hostname+"."+key)
+				continue
+			}
+			secrets[key] = linked
+		}
+		if len(secrets) == 0 {
+			delete(definition, "envSecrets")
+		}
+	}
+
+	if dotEnv, ok := definition["dotEnvSecrets"].(string); ok {
+		if copySecrets {
+			definition["dotEnvSecrets"] = renameHostnames(dotEnv, renames)
+		} else {
+			delete(definition, "dotEnvSecrets")
+			dropped = append(dropped, hostname+".dotEnvSecrets")
+		}
+	}
+	sort.Strings(dropped)
+	return dropped
+}
+
+// renameHostnames replaces whole hostnames in text; a hostname is whole when it is not part
+// of a longer run of lowercase letters and digits, so ${apidev_port} and http://apidev:3000 match
+func renameHostnames(text string, renames map[string]string) string {
+	isHostnameChar := func(c byte) bool { return c >= 'a' && c <= 'z' || c >= '0' && c <= '9' }
+
+	var out strings.Builder
+	for i := 0; i < len(text); {
+		replaced := false
+		if i == 0 || !isHostnameChar(text[i-1]) {
+			for from, to := range renames {
+				end := i + len(from)
+				if strings.HasPrefix(text[i:], from) && (end == len(text) || !isHostnameChar(text[end])) {
+					out.WriteString(to)
+					i = end
+					replaced = true
+					break
+				}
+			}
+		}
+		if !replaced {
+			out.WriteByte(text[i])
+			i++
+		}
+	}
+	return out.String()
+}
@@ -0,0 +1,51 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/sdk"
+)
+
+// RegisterGuidelines registers the company_guidelines tool when ORG_GUIDELINES_PATH is set
+func RegisterGuidelines() {
+	if shared.GuidelinesPath() == "" {
+		return
+	}
+	if _, err := shared.OrgGuidelines(); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read organization guidelines: %v\n", err)
+	}
+
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "company_guidelines",
+		Description: toolDescription("company_guidelines"),
+		InputSchema: map[string]interface{}{
+			"type":                 "object",
+			"properties":           map[string]interface{}{},
+			"additionalProperties": false,
+		},
+		Annotations: shared.ReadOnly(),
+		Output:      guidelinesResult{},
+		Handler:     handleCompanyGuidelines,
+	})
+}
+
+// guidelinesResult is the result of company_guidelines
+type guidelinesResult struct {
+	Guidelines string `json:"guidelines"`
+	Message    string `json:"message,omitempty"`
+}
+
+func handleCompanyGuidelines(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	guidelines, err := shared.OrgGuidelines()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read organization guidelines: %w", err)
+	}
+	if guidelines == "" {
+		return guidelinesResult{Message: "The guidelines file is empty."}, nil
+	}
+
+	return guidelinesResult{Guidelines: guidelines}, nil
+}
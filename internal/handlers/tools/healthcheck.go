@@ -0,0 +1,226 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/sdk"
+	"gopkg.in/yaml.v3"
+)
+
+// runtimeDefaultPorts are the ports the runtimes' recipes listen on
+var runtimeDefaultPorts = map[string]int{
+	"nodejs": 3000, "bun": 3000, "deno": 8000, "python": 8000,
+	"go": 8080, "java": 8080, "rust": 8080, "dotnet": 5000,
+	"php": 80, "elixir": 4000, "gleam": 3000, "ruby": 3000,
+}
+
+// zeropsYml is the part of zerops.yml needed to check health and readiness checks
+type zeropsYml struct {
+	Zerops []zeropsSetup `yaml:"zerops"`
+}
+
+type zeropsSetup struct {
+	Setup  string `yaml:"setup"`
+	Deploy struct {
+		ReadinessCheck *zeropsProbe `yaml:"readinessCheck"`
+	} `yaml:"deploy"`
+	Run struct {
+		Ports []struct {
+			Port int `yaml:"port"`
+		} `yaml:"ports"`
+		HealthCheck *zeropsProbe `yaml:"healthCheck"`
+	} `yaml:"run"`
+}
+
+// zeropsProbe is a healthCheck or readinessCheck block
+type zeropsProbe struct {
+	HTTPGet *struct {
+		Port int    `yaml:"port"`
+		Path string `yaml:"path"`
+	} `yaml:"httpGet"`
+	Exec *struct {
+		Command string `yaml:"command"`
+	} `yaml:"exec"`
+}
+
+// RegisterHealthCheck registers the generate_healthcheck tool
+func RegisterHealthCheck() {
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "generate_healthcheck",
+		Description: toolDescription("generate_healthcheck"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"runtime": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Runtime (e.g. 'nodejs', 'python@3.12'); picks the default port",
+				},
+				"port": map[string]interface{}{
+					"type":        "integer",
+					"description": "OPTIONAL: Port the app listens on (default: runtime default, else 8080)",
+					"minimum":     1,
+					"maximum":     65535,
+				},
+				"path": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: HTTP path of the health endpoint (default: '/')",
+				},
+				"zerops_yml": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Existing zerops.yml content to verify",
+				},
+			},
+			"additionalProperties": false,
+		},
+		Annotations: shared.ReadOnly(),
+		Output:      healthCheckResult{},
+		Handler:     handleGenerateHealthCheck,
+	})
+}
+
+// healthCheckResult is the result of generate_healthcheck; Setups is set when a zerops.yml
+// was given to verify
+type healthCheckResult struct {
+	Port   int                `json:"port"`
+	Path   string             `json:"path"`
+	YAML   string             `json:"yaml"`
+	Note   string             `json:"note"`
+	Setups []healthCheckSetup `json:"setups,omitempty"`
+}
+
+// healthCheckSetup tells whether a setup has consistent health and readiness checks
+type healthCheckSetup struct {
+	Setup             string   `json:"setup"`
+	HasHealthCheck    bool     `json:"has_health_check"`
+	HasReadinessCheck bool     `json:"has_readiness_check"`
+	Valid             bool     `json:"valid"`
+	Issues            []string `json:"issues"`
+}
+
+func handleGenerateHealthCheck(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	runtime, _ := args["runtime"].(string)
+	runtime = strings.ToLower(strings.SplitN(runtime, "@", 2)[0])
+
+	port := 8080
+	if defaultPort, ok := runtimeDefaultPorts[runtime]; ok {
+		port = defaultPort
+	}
+	if value, ok := args["port"].(float64); ok {
+		port = int(value)
+	}
+	if port < 1 || port > 65535 {
+		return nil, shared.InvalidArgument("port must be between 1 and 65535")
+	}
+
+	checkPath, _ := args["path"].(string)
+	if checkPath == "" {
+		checkPath = "/"
+	}
+	if !strings.HasPrefix(checkPath, "/") {
+		return nil, shared.InvalidArgument("path must start with '/', got '%s'", checkPath)
+	}
+
+	result := healthCheckResult{
+		Port: port,
+		Path: checkPath,
+		YAML: healthCheckSnippet(port, checkPath),
+		Note: "Merge deploy.readinessCheck and run.healthCheck into the setup in zerops.yml.",
+	}
+
+	if content, _ := args["zerops_yml"].(string); content != "" {
+		setups, err := verifyHealthChecks(content)
+		if err != nil {
+			return nil, err
+		}
+		result.Setups = setups
+	}
+	return result, nil
+}
+
+// healthCheckSnippet renders the checks as they appear inside a zerops.yml setup
+func healthCheckSnippet(port int, checkPath string) string {
+	return fmt.Sprintf(`deploy:
+  readinessCheck:
+    httpGet:
+      port: %[1]d
+      path: %[2]s
+    failureTimeout: 60
+    retryPeriod: 10
+run:
+  healthCheck:
+    httpGet:
+      port: %[1]d
+      path: %[2]s
+    failureTimeout: 60
+    disconnectTimeout: 30
+    recoveryTimeout: 30
+    execPeriod: 10
+`, port, checkPath)
+}
+
+// verifyHealthChecks reports, per setup, whether the checks exist and are consistent with run.ports
+func verifyHealthChecks(content string) ([]healthCheckSetup, error) {
+	var config zeropsYml
+	if err := yaml.Unmarshal([]byte(content), &config); err != nil {
+		return nil, shared.InvalidArgument("Invalid zerops.yml: %v", err)
+	}
+	if len(config.Zerops) == 0 {
+		return nil, shared.InvalidArgument("zerops.yml has no setups under 'zerops'")
+	}
+
+	setups := make([]healthCheckSetup, 0, len(config.Zerops))
+	for _, setup := range config.Zerops {
+		ports := map[int]bool{}
+		for _, port := range setup.Run.Ports {
+			ports[port.Port] = true
+		}
+
+		issues := []string{}
+		if setup.Run.HealthCheck == nil {
+			issues = append(issues, "run.healthCheck is missing; crashed or hung containers are not replaced")
+		} else {
+			issues = append(issues, probeIssues("run.healthCheck", setup.Run.HealthCheck, ports)...)
+		}
+		if setup.Deploy.ReadinessCheck == nil {
+			issues = append(issues, "deploy.readinessCheck is missing; traffic switches to new containers before they are ready")
+		} else {
+			issues = append(issues, probeIssues("deploy.readinessCheck", setup.Deploy.ReadinessCheck, ports)...)
+		}
+
+		setups = append(setups, healthCheckSetup{
+			Setup:             setup.Setup,
+			HasHealthCheck:    setup.Run.HealthCheck != nil,
+			HasReadinessCheck: setup.Deploy.ReadinessCheck != nil,
+			Valid:             len(issues) == 0,
+			Issues:            issues,
+		})
+	}
+	return setups, nil
+}
+
+func probeIssues(name string, probe *zeropsProbe, ports map[int]bool) []string {
+	var issues []string
+	switch {
+	case probe.HTTPGet == nil && probe.Exec == nil:
+		issues = append(issues, name+" needs httpGet or exec")
+	case probe.HTTPGet != nil && probe.Exec != nil:
+		issues = append(issues, name+" sets both httpGet and exec; use one")
+	case probe.HTTPGet != nil:
+		if probe.HTTPGet.Port == 0 {
+			issues = append(issues, name+".httpGet.port is missing")
+		} else if len(ports) > 0 && !ports[probe.HTTPGet.Port] {
+			issues = append(issues, fmt.Sprintf("%s.httpGet.port %d is not listed in run.ports", name, probe.HTTPGet.Port))
+		}
+		if !strings.HasPrefix(probe.HTTPGet.Path, "/") {
+			issues = append(issues, name+".httpGet.path must start with '/'")
+		}
+	case probe.Exec != nil:
+		if strings.TrimSpace(probe.Exec.Command) == "" {
+			issues = append(issues, name+".exec.command is empty")
+		}
+	}
+	return issues
+}
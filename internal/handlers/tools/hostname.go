@@ -0,0 +1,141 @@
+package tools
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/sdk"
+)
+
+// maxHostnameLength is the longest hostname Zerops accepts
+const maxHostnameLength = 25
+
+// RegisterHostname registers the suggest_hostname tool
+func RegisterHostname() {
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "suggest_hostname",
+		Description: toolDescription("suggest_hostname"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": "REQUIRED: Desired service name, e.g. 'My API-Server'",
+				},
+				"project_id": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Project ID to check for existing hostnames. Defaults to $projectId.",
+					"pattern":     "^[A-Za-z0-9_-]+$",
+				},
+			},
+			"required":             []string{"name"},
+			"additionalProperties": false,
+		},
+		Annotations: shared.ReadOnly(),
+		Output:      suggestHostnameResult{},
+		Handler:     handleSuggestHostname,
+	})
+}
+
+// suggestHostnameResult is the result of suggest_hostname; Changes explains each change
+// made to the requested name
+type suggestHostnameResult struct {
+	Hostname  string   `json:"hostname"`
+	Requested string   `json:"requested"`
+	ProjectID string   `json:"project_id"`
+	Valid     bool     `json:"valid"`
+	Changed   bool     `json:"changed"`
+	Changes   []string `json:"changes"`
+}
+
+func handleSuggestHostname(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+
+	name, ok := args["name"].(string)
+	if !ok || strings.TrimSpace(name) == "" {
+		return nil, shared.InvalidArgument("Name is required")
+	}
+
+	projectID, err := resolveProjectID(ctx, client, args)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := searchProject(ctx, client, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	services, err := searchProjectServices(ctx, client, project)
+	if err != nil {
+		return nil, err
+	}
+
+	taken := make(map[string]bool, len(services))
+	for _, service := range services {
+		taken[service.Name.Native()] = true
+	}
+
+	base := sanitizeHostname(name)
+	hostname := uniqueHostname(base, taken)
+
+	var changes []string
+	if base != name {
+		changes = append(changes, "normalized to lowercase alphanumeric, max 25 characters, starting with a letter")
+	}
+	if hostname != base {
+		changes = append(changes, "'"+base+"' is already used in the project, added a numeric suffix")
+	}
+
+	return suggestHostnameResult{
+		Hostname:  hostname,
+		Requested: name,
+		ProjectID: projectID,
+		Valid:     hostnamePattern.MatchString(hostname),
+		Changed:   hostname != name,
+		Changes:   changes,
+	}, nil
+}
+
+// sanitizeHostname reduces a name to the characters and length Zerops accepts
+func sanitizeHostname(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+
+	hostname := b.String()
+	if hostname == "" {
+		hostname = "app"
+	}
+	if hostname[0] >= '0' && hostname[0] <= '9' {
+		hostname = "s" + hostname
+	}
+	if len(hostname) > maxHostnameLength {
+		hostname = hostname[:maxHostnameLength]
+	}
+	return hostname
+}
+
+// uniqueHostname appends the lowest numeric suffix that avoids taken hostnames
+func uniqueHostname(base string, taken map[string]bool) string {
+	if !taken[base] {
+		return base
+	}
+	for i := 2; ; i++ {
+		suffix := strconv.Itoa(i)
+		prefix := base
+		if len(prefix)+len(suffix) > maxHostnameLength {
+			prefix = prefix[:maxHostnameLength-len(suffix)]
+		}
+		if candidate := prefix + suffix; !taken[candidate] {
+			return candidate
+		}
+	}
+}
@@ -0,0 +1,709 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/dto/input/body"
+	"github.com/zeropsio/zerops-go/dto/input/path"
+	"github.com/zeropsio/zerops-go/dto/output"
+	"github.com/zeropsio/zerops-go/sdk"
+	"github.com/zeropsio/zerops-go/types"
+	"github.com/zeropsio/zerops-go/types/uuid"
+)
+
+var routingDomainPattern = regexp.MustCompile(`^(\*\.)?([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z]{2,63}$`)
+
+// redirectCodes are the HTTP status codes a routing location can redirect with
+var redirectCodes = map[int]bool{301: true, 302: true, 307: true, 308: true}
+
+// RegisterHttpRouting registers the public HTTP routing tools
+func RegisterHttpRouting() {
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "list_http_routing",
+		Description: toolDescription("list_http_routing"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"project_id": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Project ID. If not provided, will check $projectId environment variable.",
+					"pattern":     "^[A-Za-z0-9_-]+$",
+				},
+			},
+			"additionalProperties": false,
+		},
+		Annotations: shared.ReadOnly(),
+		Output:      listHttpRoutingResult{},
+		Handler:     handleListHttpRouting,
+	})
+
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "set_http_routing",
+		Description: toolDescription("set_http_routing"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"project_id": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Project ID. If not provided, will check $projectId environment variable.",
+					"pattern":     "^[A-Za-z0-9_-]+$",
+				},
+				"routing_id": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Routing to replace; omit to create a new routing",
+					"pattern":     "^[A-Za-z0-9_-]+$",
+				},
+				"domains": map[string]interface{}{
+					"type":        "array",
+					"description": "REQUIRED: Domains of the routing, e.g. [\"example.com\", \"www.example.com\"]",
+					"items":       map[string]interface{}{"type": "string"},
+					"minItems":    1,
+				},
+				"locations": map[string]interface{}{
+					"type":        "array",
+					"description": "REQUIRED: Locations; each routes a path prefix to service_id and port, or redirects it",
+					"minItems":    1,
+					"items": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"path": map[string]interface{}{
+								"type":        "string",
+								"description": "Path prefix starting with /, e.g. / or /api",
+							},
+							"service_id": map[string]interface{}{
+								"type":        "string",
+								"description": "Service the path routes to",
+							},
+							"port": map[string]interface{}{
+								"type":        "integer",
+								"description": "Port of the service, e.g. 3000",
+								"minimum":     1,
+								"maximum":     65535,
+							},
+							"redirect_to": map[string]interface{}{
+								"type":        "string",
+								"description": "Redirect target URL instead of routing to a service",
+							},
+							"redirect_code": map[string]interface{}{
+								"type":        "integer",
+								"description": "Redirect status code (default: 301)",
+								"enum":        []int{301, 302, 307, 308},
+							},
+							"preserve_path": map[string]interface{}{
+								"type":        "boolean",
+								"description": "Append the request path to redirect_to (default: false)",
+							},
+							"preserve_query": map[string]interface{}{
+								"type":        "boolean",
+								"description": "Append the query string to redirect_to (default: false)",
+							},
+						},
+						"required":             []string{"path"},
+						"additionalProperties": false,
+					},
+				},
+				"ssl": map[string]interface{}{
+					"type":        "boolean",
+					"description": "OPTIONAL: Issue certificates and serve HTTPS (default: true)",
+				},
+				"cdn": map[string]interface{}{
+					"type":        "boolean",
+					"description": "OPTIONAL: Serve the domains through the Zerops CDN (default: false)",
+				},
+				"apply": map[string]interface{}{
+					"type":        "boolean",
+					"description": "OPTIONAL: Sync the project's routing changes afterwards (default: true)",
+				},
+			},
+			"required":             []string{"domains", "locations"},
+			"additionalProperties": false,
+		},
+		Annotations: shared.Mutating(true, false),
+		Output:      httpRoutingResult{},
+		Handler:     handleSetHttpRouting,
+	})
+
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "delete_http_routing",
+		Description: toolDescription("delete_http_routing"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"routing_id": map[string]interface{}{
+					"type":        "string",
+					"description": "REQUIRED: Routing ID from list_http_routing",
+					"pattern":     "^[A-Za-z0-9_-]+$",
+				},
+				"confirm": map[string]interface{}{
+					"type":        "boolean",
+					"description": "REQUIRED: Must be true to delete the routing",
+				},
+				"apply": map[string]interface{}{
+					"type":        "boolean",
+					"description": "OPTIONAL: Sync the project's routing changes afterwards (default: true)",
+				},
+			},
+			"required":             []string{"routing_id", "confirm"},
+			"additionalProperties": false,
+		},
+		Annotations: shared.Mutating(true, false),
+		Output:      httpRoutingResult{},
+		Handler:     handleDeleteHttpRouting,
+	})
+
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "apply_http_routing",
+		Description: toolDescription("apply_http_routing"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"project_id": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Project ID. If not provided, will check $projectId environment variable.",
+					"pattern":     "^[A-Za-z0-9_-]+$",
+				},
+				"revert": map[string]interface{}{
+					"type":        "boolean",
+					"description": "OPTIONAL: Discard the pending changes instead of applying them (default: false)",
+				},
+			},
+			"additionalProperties": false,
+		},
+		Annotations: shared.Mutating(true, true),
+		Output:      httpRoutingResult{},
+		Handler:     handleApplyHttpRouting,
+	})
+}
+
+// listHttpRoutingResult is the result of list_http_routing
+type listHttpRoutingResult struct {
+	ProjectID string        `json:"project_id"`
+	Routings  []httpRouting `json:"routings"`
+	Count     int           `json:"count"`
+	Message   string        `json:"message,omitempty"`
+}
+
+// httpRoutingResult is the result of set_http_routing, delete_http_routing and
+// apply_http_routing. Applied is missing when routing changes were reverted.
+type httpRoutingResult struct {
+	Status    string       `json:"status,omitempty"`
+	ProjectID string       `json:"project_id,omitempty"`
+	RoutingID string       `json:"routing_id,omitempty"`
+	Domains   []string     `json:"domains,omitempty"`
+	Routing   *httpRouting `json:"routing,omitempty"`
+	Applied   *bool        `json:"applied,omitempty"`
+	ProcessID string       `json:"process_id,omitempty"`
+	Message   string       `json:"message"`
+	shared.Suggestions
+}
+
+// httpRouting is a public HTTP routing of a project
+type httpRouting struct {
+	RoutingID  string                `json:"routing_id"`
+	Domains    []httpRoutingDomain   `json:"domains"`
+	SslEnabled bool                  `json:"ssl_enabled"`
+	CdnEnabled bool                  `json:"cdn_enabled"`
+	Synced     bool                  `json:"synced"`
+	Editable   bool                  `json:"editable"`
+	Locations  []httpRoutingLocation `json:"locations"`
+}
+
+// httpRoutingDomain is a domain of a routing with its DNS, SSL and CDN state
+type httpRoutingDomain struct {
+	Domain    string `json:"domain"`
+	DnsStatus string `json:"dns_status"`
+	SslStatus string `json:"ssl_status"`
+	CdnStatus string `json:"cdn_status"`
+}
+
+// httpRoutingLocation is a path of a routing and where it leads
+type httpRoutingLocation struct {
+	Path          string               `json:"path"`
+	ServiceID     string               `json:"service_id,omitempty"`
+	ServiceName   string               `json:"service_name,omitempty"`
+	Port          int                  `json:"port,omitempty"`
+	Redirect      *httpRoutingRedirect `json:"redirect,omitempty"`
+	StaticContent *httpRoutingContent  `json:"static_content,omitempty"`
+	Features      []string             `json:"features,omitempty"`
+}
+
+// httpRoutingRedirect is the redirect a location answers with
+type httpRoutingRedirect struct {
+	To            string `json:"to"`
+	Code          int    `json:"code"`
+	PreservePath  bool   `json:"preserve_path"`
+	PreserveQuery bool   `json:"preserve_query"`
+}
+
+// httpRoutingContent is the static content a location answers with
+type httpRoutingContent struct {
+	Code        int    `json:"code"`
+	ContentType string `json:"content_type"`
+}
+
+func handleListHttpRouting(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+
+	projectID, err := resolveProjectID(ctx, client, args)
+	if err != nil {
+		return nil, err
+	}
+	project, err := searchProject(ctx, client, projectID)
+	if err != nil {
+		return nil, err
+	}
+	routings, err := searchProjectHttpRouting(ctx, client, project)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]httpRouting, 0, len(routings))
+	pending := false
+	for _, routing := range routings {
+		list = append(list, describeHttpRouting(routing))
+		if !routing.IsSynced.Native() {
+			pending = true
+		}
+	}
+	result := listHttpRoutingResult{
+		ProjectID: projectID,
+		Routings:  list,
+		Count:     len(list),
+	}
+	if pending {
+		result.Message = "Some routings have changes that are not applied yet. Use apply_http_routing to sync or discard them."
+	}
+	return result, nil
+}
+
+func handleSetHttpRouting(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+
+	projectID, err := resolveProjectID(ctx, client, args)
+	if err != nil {
+		return nil, err
+	}
+	domains, err := stringListArg(args, "domains")
+	if err != nil {
+		return nil, err
+	}
+	if len(domains) == 0 {
+		return nil, shared.InvalidArgument("domains must list at least one domain")
+	}
+	for i, domain := range domains {
+		domain = strings.ToLower(strings.TrimSpace(domain))
+		if !routingDomainPattern.MatchString(domain) {
+			return nil, shared.InvalidArgument("Invalid domain '%s'; use a name like example.com or *.example.com without scheme or path", domains[i])
+		}
+		domains[i] = domain
+	}
+	ssl := true
+	if value, ok := args["ssl"].(bool); ok {
+		ssl = value
+	}
+	apply := true
+	if value, ok := args["apply"].(bool); ok {
+		apply = value
+	}
+
+	project, err := searchProject(ctx, client, projectID)
+	if err != nil {
+		return nil, err
+	}
+	services, err := searchProjectServices(ctx, client, project)
+	if err != nil {
+		return nil, err
+	}
+	locations, err := routingLocationsArg(args, services)
+	if err != nil {
+		return nil, err
+	}
+
+	cdn := types.BoolNull{}
+	if value, ok := args["cdn"].(bool); ok {
+		cdn = types.NewBoolNull(value)
+	}
+
+	var routing output.PublicHttpRouting
+	status := "created"
+	if routingID, _ := args["routing_id"].(string); routingID != "" {
+		existing, err := getHttpRouting(ctx, client, routingID)
+		if err != nil {
+			return nil, err
+		}
+		if string(existing.ProjectId) != projectID {
+			return nil, shared.InvalidArgument("Routing %s belongs to project %s, not %s", routingID, existing.ProjectId, projectID)
+		}
+		if !existing.IsEditable.Native() {
+			return nil, shared.InvalidArgument("Routing %s is managed by Zerops and cannot be edited", routingID)
+		}
+		if err := keepRoutingLocationSettings(existing, locations); err != nil {
+			return nil, err
+		}
+		resp, err := client.PutPublicHttpRouting(ctx, path.PublicHttpRoutingId{Id: uuid.PublicHttpRoutingId(routingID)}, body.PublicHttpRoutingPut{
+			SslEnabled: types.NewBool(ssl),
+			CdnEnabled: cdn,
+			Domains:    types.NewStringArray(domains),
+			Locations:  locations,
+		})
+		if err != nil {
+			return nil, shared.WrapAPIError(err, "Failed to update HTTP routing")
+		}
+		routing, err = resp.Output()
+		if err != nil {
+			return nil, shared.WrapAPIError(err, "Failed to update HTTP routing")
+		}
+		status = "updated"
+	} else {
+		resp, err := client.PostPublicHttpRouting(ctx, body.PublicHttpRoutingPost{
+			ProjectId:  uuid.ProjectId(projectID),
+			SslEnabled: types.NewBool(ssl),
+			CdnEnabled: cdn,
+			Domains:    types.NewStringArray(domains),
+			Locations:  body.PublicHttpRoutingPostLocations(locations),
+		})
+		if err != nil {
+			return nil, shared.WrapAPIError(err, "Failed to create HTTP routing")
+		}
+		routing, err = resp.Output()
+		if err != nil {
+			return nil, shared.WrapAPIError(err, "Failed to create HTTP routing")
+		}
+	}
+
+	described := describeHttpRouting(output.EsPublicHttpRouting{
+		Id:         routing.Id,
+		ProjectId:  routing.ProjectId,
+		SslEnabled: routing.SslEnabled,
+		Domains:    output.EsPublicHttpRoutingDomains(routing.Domains),
+		Locations:  output.EsPublicHttpRoutingLocations(routing.Locations),
+		IsSynced:   routing.IsSynced,
+		IsEditable: routing.IsEditable,
+		CdnEnabled: routing.CdnEnabled,
+	})
+	return withRoutingSync(ctx, client, projectID, apply, &httpRoutingResult{Status: status, Routing: &described})
+}
+
+func handleDeleteHttpRouting(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+
+	routingID, ok := args["routing_id"].(string)
+	if !ok || routingID == "" {
+		return nil, shared.InvalidArgument("Routing ID is required")
+	}
+	if confirm, _ := args["confirm"].(bool); !confirm {
+		return nil, shared.InvalidArgument("Deleting HTTP routing '%s' requires confirm: true", routingID)
+	}
+	apply := true
+	if value, ok := args["apply"].(bool); ok {
+		apply = value
+	}
+
+	existing, err := getHttpRouting(ctx, client, routingID)
+	if err != nil {
+		return nil, err
+	}
+	if !existing.IsEditable.Native() {
+		return nil, shared.InvalidArgument("Routing %s is managed by Zerops and cannot be deleted", routingID)
+	}
+	resp, err := client.DeletePublicHttpRouting(ctx, path.PublicHttpRoutingId{Id: uuid.PublicHttpRoutingId(routingID)})
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to delete HTTP routing")
+	}
+	if _, err := resp.Output(); err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to delete HTTP routing")
+	}
+
+	domains := make([]string, 0, len(existing.Domains))
+	for _, domain := range existing.Domains {
+		domains = append(domains, domain.DomainName.Native())
+	}
+	result := &httpRoutingResult{
+		Status:    "deleted",
+		RoutingID: routingID,
+		Domains:   domains,
+	}
+	return withRoutingSync(ctx, client, string(existing.ProjectId), apply, result)
+}
+
+func handleApplyHttpRouting(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+
+	projectID, err := resolveProjectID(ctx, client, args)
+	if err != nil {
+		return nil, err
+	}
+	if revert, _ := args["revert"].(bool); revert {
+		resp, err := client.PutProjectRevertChangesPublicHttpRouting(ctx, path.ProjectId{Id: uuid.ProjectId(projectID)})
+		if err != nil {
+			return nil, shared.WrapAPIError(err, "Failed to revert HTTP routing changes")
+		}
+		if _, err := resp.Output(); err != nil {
+			return nil, shared.WrapAPIError(err, "Failed to revert HTTP routing changes")
+		}
+		return httpRoutingResult{
+			ProjectID: projectID,
+			Status:    "reverted",
+			Message:   "Pending HTTP routing changes were discarded.",
+		}, nil
+	}
+	return withRoutingSync(ctx, client, projectID, true, &httpRoutingResult{ProjectID: projectID})
+}
+
+// withRoutingSync syncs the project's routing changes when apply is set and adds the
+// outcome to result
+func withRoutingSync(ctx context.Context, client *sdk.Handler, projectID string, apply bool, result *httpRoutingResult) (interface{}, error) {
+	result.Applied = &apply
+	if !apply {
+		result.Message = "Saved but not applied. Use apply_http_routing to sync the changes or revert them."
+		return result, nil
+	}
+	resp, err := client.PutProjectSyncPublicHttpRouting(ctx, path.ProjectId{Id: uuid.ProjectId(projectID)})
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to apply HTTP routing changes")
+	}
+	process, err := resp.Output()
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to apply HTTP routing changes")
+	}
+	result.ProcessID = string(process.Id)
+	result.Message = "Routing changes are being applied. Use wait_for_process with this process_id to follow the sync."
+	result.SuggestNext(shared.WaitForProcess(string(process.Id), "Wait until the routing is synced"))
+	return result, nil
+}
+
+// searchProjectHttpRouting returns the HTTP routings of a project ordered by their first domain
+func searchProjectHttpRouting(ctx context.Context, client *sdk.Handler, project output.EsProject) ([]output.EsPublicHttpRouting, error) {
+	resp, err := client.PostPublicHttpRoutingSearch(ctx, body.EsFilter{
+		Search: []body.EsSearchItem{
+			{Name: "projectId", Operator: "eq", Value: types.String(string(project.Id))},
+			{Name: "clientId", Operator: "eq", Value: project.ClientId.TypedString()},
+		},
+	})
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to search HTTP routing")
+	}
+	routingOutput, err := resp.Output()
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to search HTTP routing")
+	}
+
+	routings := routingOutput.Items
+	firstDomain := func(routing output.EsPublicHttpRouting) string {
+		if len(routing.Domains) == 0 {
+			return ""
+		}
+		return routing.Domains[0].DomainName.Native()
+	}
+	sort.SliceStable(routings, func(i, j int) bool {
+		return firstDomain(routings[i]) < firstDomain(routings[j])
+	})
+	return routings, nil
+}
+
+// getHttpRouting loads one routing
+func getHttpRouting(ctx context.Context, client *sdk.Handler, routingID string) (output.PublicHttpRouting, error) {
+	resp, err := client.GetPublicHttpRouting(ctx, path.PublicHttpRoutingId{Id: uuid.PublicHttpRoutingId(routingID)})
+	if err != nil {
+		return output.PublicHttpRouting{}, shared.WrapAPIError(err, "Failed to get HTTP routing")
+	}
+	routing, err := resp.Output()
+	if err != nil {
+		return output.PublicHttpRouting{}, shared.WrapAPIError(err, "Failed to get HTTP routing")
+	}
+	return routing, nil
+}
+
+// describeHttpRouting converts a routing to the tool output shape
+func describeHttpRouting(routing output.EsPublicHttpRouting) httpRouting {
+	domains := make([]httpRoutingDomain, 0, len(routing.Domains))
+	for _, domain := range routing.Domains {
+		domains = append(domains, httpRoutingDomain{
+			Domain:    domain.DomainName.Native(),
+			DnsStatus: string(domain.DnsCheckStatus),
+			SslStatus: string(domain.SslStatus),
+			CdnStatus: string(domain.CdnStatus),
+		})
+	}
+
+	locations := make([]httpRoutingLocation, 0, len(routing.Locations))
+	for _, location := range routing.Locations {
+		entry := httpRoutingLocation{
+			Path: location.Path.Native(),
+		}
+		if location.ServiceStackId != "" {
+			entry.ServiceID = string(location.ServiceStackId)
+			entry.ServiceName = location.ServiceStackInfo.ServiceStackName.Native()
+			entry.Port = location.Port.Native()
+		}
+		if config := location.Config; config != nil {
+			if redirect := config.Redirect; redirect != nil && redirect.Enabled.Native() {
+				entry.Redirect = &httpRoutingRedirect{
+					To:            redirect.To.Native(),
+					Code:          redirect.Code.Native(),
+					PreservePath:  redirect.PreservePath.Native(),
+					PreserveQuery: redirect.PreserveQuery.Native(),
+				}
+			}
+			if content := config.Content; content != nil && content.Enabled.Native() {
+				entry.StaticContent = &httpRoutingContent{
+					Code:        content.Code.Native(),
+					ContentType: content.ContentType.Native(),
+				}
+			}
+			var features []string
+			if config.AccessPolicy != nil && config.AccessPolicy.Enabled.Native() {
+				features = append(features, "access_policy")
+			}
+			if config.BasicAuth != nil && config.BasicAuth.Enabled.Native() {
+				features = append(features, "basic_auth")
+			}
+			if config.RateLimiting != nil && config.RateLimiting.Enabled.Native() {
+				features = append(features, "rate_limiting")
+			}
+			entry.Features = features
+		}
+		locations = append(locations, entry)
+	}
+
+	return httpRouting{
+		RoutingID:  string(routing.Id),
+		Domains:    domains,
+		SslEnabled: routing.SslEnabled.Native(),
+		CdnEnabled: routing.CdnEnabled.Native(),
+		Synced:     routing.IsSynced.Native(),
+		Editable:   routing.IsEditable.Native(),
+		Locations:  locations,
+	}
+}
+
+// routingLocationsArg reads the locations argument of set_http_routing and checks that
+// every target service belongs to the project
+func routingLocationsArg(args map[string]interface{}, services []output.EsServiceStack) (body.PublicHttpRoutingPutLocations, error) {
+	list, _ := args["locations"].([]interface{})
+	if len(list) == 0 {
+		return nil, shared.InvalidArgument("locations must list at least one location")
+	}
+	serviceNames := make(map[string]string, len(services))
+	for _, service := range services {
+		serviceNames[string(service.Id)] = service.Name.Native()
+	}
+
+	locations := make(body.PublicHttpRoutingPutLocations, 0, len(list))
+	seen := map[string]bool{}
+	for i, item := range list {
+		spec, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, shared.InvalidArgument("locations[%d] must be an object", i)
+		}
+		locationPath, _ := spec["path"].(string)
+		if !strings.HasPrefix(locationPath, "/") {
+			return nil, shared.InvalidArgument("locations[%d].path must start with /, got '%s'", i, locationPath)
+		}
+		if seen[locationPath] {
+			return nil, shared.InvalidArgument("locations[%d].path '%s' is listed twice", i, locationPath)
+		}
+		seen[locationPath] = true
+
+		location := body.PublicHttpRoutingLocation{Path: types.NewString(locationPath)}
+		redirectTo, _ := spec["redirect_to"].(string)
+		serviceID, _ := spec["service_id"].(string)
+		switch {
+		case redirectTo != "" && serviceID != "":
+			return nil, shared.InvalidArgument("locations[%d] sets both service_id and redirect_to; use one", i)
+		case redirectTo != "":
+			if !strings.HasPrefix(redirectTo, "https://") && !strings.HasPrefix(redirectTo, "http://") && !strings.HasPrefix(redirectTo, "/") {
+				return nil, shared.InvalidArgument("locations[%d].redirect_to must be an http(s) URL or a path, got '%s'", i, redirectTo)
+			}
+			code := 301
+			if value, ok := spec["redirect_code"].(float64); ok {
+				code = int(value)
+			}
+			if !redirectCodes[code] {
+				return nil, shared.InvalidArgument("locations[%d].redirect_code must be 301, 302, 307 or 308, got %d", i, code)
+			}
+			preservePath, _ := spec["preserve_path"].(bool)
+			preserveQuery, _ := spec["preserve_query"].(bool)
+			location.Config = &body.PublicHttpRoutingLocationConfig{
+				Redirect: &body.PublicHttpRoutingLocationRedirect{
+					Enabled:       types.NewBool(true),
+					To:            types.NewEmptyString(redirectTo),
+					Code:          types.NewInt(code),
+					PreservePath:  types.NewBool(preservePath),
+					PreserveQuery: types.NewBool(preserveQuery),
+				},
+			}
+		case serviceID != "":
+			if _, ok := serviceNames[serviceID]; !ok {
+				return nil, shared.NotFound("Service %s is not in this project", serviceID)
+			}
+			port, ok := spec["port"].(float64)
+			if !ok || port < 1 || port > 65535 || port != float64(int(port)) {
+				return nil, shared.InvalidArgument("locations[%d].port must be the service port between 1 and 65535", i)
+			}
+			location.ServiceStackId = uuid.ServiceStackId(serviceID)
+			location.Port = types.NewInt(int(port))
+		default:
+			return nil, shared.InvalidArgument("locations[%d] needs service_id and port, or redirect_to", i)
+		}
+		if _, ok := spec["redirect_code"]; ok && redirectTo == "" {
+			return nil, shared.InvalidArgument("locations[%d].redirect_code needs redirect_to", i)
+		}
+		locations = append(locations, location)
+	}
+	return locations, nil
+}
+
+// keepRoutingLocationSettings copies the access policy, basic auth and rate limiting of the
+// existing locations to the new locations with the same path, since set_http_routing does
+// not expose them and replacing a routing would otherwise drop them
+func keepRoutingLocationSettings(existing output.PublicHttpRouting, locations body.PublicHttpRoutingPutLocations) error {
+	byPath := make(map[string]output.PublicHttpRoutingLocation, len(existing.Locations))
+	for _, location := range existing.Locations {
+		byPath[location.Path.Native()] = location
+	}
+	for i := range locations {
+		previous, ok := byPath[locations[i].Path.Native()]
+		if !ok || previous.Config == nil {
+			continue
+		}
+		stored, err := routingLocationInput(previous)
+		if err != nil {
+			return err
+		}
+		if locations[i].Config == nil {
+			locations[i].Config = &body.PublicHttpRoutingLocationConfig{}
+		}
+		locations[i].Config.AccessPolicy = stored.Config.AccessPolicy
+		locations[i].Config.BasicAuth = stored.Config.BasicAuth
+		locations[i].Config.RateLimiting = stored.Config.RateLimiting
+	}
+	return nil
+}
+
+// routingLocationInput converts a stored location back to its input form
+func routingLocationInput(location output.PublicHttpRoutingLocation) (body.PublicHttpRoutingLocation, error) {
+	data, err := json.Marshal(location)
+	if err != nil {
+		return body.PublicHttpRoutingLocation{}, err
+	}
+	var input body.PublicHttpRoutingLocation
+	if err := json.Unmarshal(data, &input); err != nil {
+		return body.PublicHttpRoutingLocation{}, fmt.Errorf("failed to read routing location %s: %w", location.Path.Native(), err)
+	}
+	return input, nil
+}
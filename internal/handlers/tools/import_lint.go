@@ -0,0 +1,298 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/sdk"
+	"gopkg.in/yaml.v3"
+)
+
+// lintMaxMinRamGB is the largest verticalAutoscaling.minRam the lint accepts without a warning
+const lintMaxMinRamGB = 4.0
+
+// lintService is one service of an import YAML as the lint rules see it
+type lintService struct {
+	node     *yaml.Node
+	path     string
+	hostname string
+	// base is the type without version, e.g. postgresql
+	base string
+	// production is true when the project is tagged as a production project
+	production bool
+}
+
+// runtime reports whether the service runs user code
+func (s lintService) runtime() bool {
+	return s.base != "" && !managedServiceTypes[s.base]
+}
+
+// importLintRule is a best-practice check of one service. Unlike the preflight checks,
+// lint findings don't make the import fail; strict mode turns them into blockers.
+type importLintRule struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+	// check returns the offending node with a message and fix, or a nil node
+	check func(s lintService) (node *yaml.Node, key, message, fix string)
+}
+
+var importLintRules = []importLintRule{
+	{
+		ID:          "dev-start-without-code",
+		Description: "Dev runtime services should set startWithoutCode: true so their container runs before the first deploy",
+		check: func(s lintService) (*yaml.Node, string, string, string) {
+			if !s.runtime() || !lintDevService(s) || mappingValue(s.node, "buildFromGit") != nil {
+				return nil, "", "", ""
+			}
+			if value := mappingValue(s.node, "startWithoutCode"); value != nil && value.Value == "true" {
+				return nil, "", "", ""
+			}
+			return s.node, ".startWithoutCode", "dev service has no startWithoutCode: true; it stays empty until the first deploy and cannot be used over SSH",
+				"add startWithoutCode: true"
+		},
+	},
+	{
+		ID:          "prod-database-ha",
+		Description: "Databases in projects tagged prod or production should use mode: HA",
+		check: func(s lintService) (*yaml.Node, string, string, string) {
+			if !s.production || !managedServiceTypes[s.base] || s.base == "object-storage" {
+				return nil, "", "", ""
+			}
+			mode := mappingValue(s.node, "mode")
+			if mode != nil && mode.Value == "HA" {
+				return nil, "", "", ""
+			}
+			node := s.node
+			if mode != nil {
+				node = mode
+			}
+			return node, ".mode", s.base + " runs without HA in a production project; the mode cannot be changed after import",
+				"use mode: HA"
+		},
+	},
+	{
+		ID:          "single-container-subdomain",
+		Description: "Public services with maxContainers: 1 go down on every deploy and container failure",
+		check: func(s lintService) (*yaml.Node, string, string, string) {
+			if !s.runtime() {
+				return nil, "", "", ""
+			}
+			subdomain := mappingValue(s.node, "enableSubdomainAccess")
+			maxContainers, ok := yamlIntValue(mappingValue(s.node, "maxContainers"))
+			if subdomain == nil || subdomain.Value != "true" || !ok || maxContainers != 1 {
+				return nil, "", "", ""
+			}
+			return mappingValue(s.node, "maxContainers"), ".maxContainers", "public service is limited to one container; every deploy and container failure makes it unavailable",
+				"set maxContainers: 2 or more"
+		},
+	},
+	{
+		ID:          "oversized-min-ram",
+		Description: fmt.Sprintf("verticalAutoscaling.minRam above %g GB is reserved and billed even when the service is idle", lintMaxMinRamGB),
+		check: func(s lintService) (*yaml.Node, string, string, string) {
+			minRam := mappingValue(mappingValue(s.node, "verticalAutoscaling"), "minRam")
+			gigabytes, ok := lintGigabytes(minRam)
+			if !ok || gigabytes <= lintMaxMinRamGB {
+				return nil, "", "", ""
+			}
+			return minRam, ".verticalAutoscaling.minRam", fmt.Sprintf("minRam of %g GB is reserved even when the service is idle", gigabytes),
+				fmt.Sprintf("lower minRam to %g or less and let maxRam cover peaks", lintMaxMinRamGB)
+		},
+	},
+}
+
+// RegisterImportLint registers the lint_import_yaml tool
+func RegisterImportLint() {
+	ruleIDs := make([]string, 0, len(importLintRules))
+	for _, rule := range importLintRules {
+		ruleIDs = append(ruleIDs, rule.ID)
+	}
+
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "lint_import_yaml",
+		Description: toolDescription("lint_import_yaml"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"yaml": map[string]interface{}{
+					"type":        "string",
+					"description": "REQUIRED: Import YAML to lint",
+				},
+				"project_id": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Target project whose tags count for production detection",
+					"pattern":     "^[A-Za-z0-9_-]+$",
+				},
+				"disable_rules": map[string]interface{}{
+					"type":        "array",
+					"description": "OPTIONAL: Rule IDs to skip",
+					"items": map[string]interface{}{
+						"type": "string",
+						"enum": ruleIDs,
+					},
+				},
+			},
+			"required":             []string{"yaml"},
+			"additionalProperties": false,
+		},
+		Annotations: shared.ReadOnly(),
+		Output:      lintImportResult{},
+		Handler:     handleLintImportYaml,
+	})
+}
+
+// lintImportResult is the result of lint_import_yaml; Rules are the rules that ran
+type lintImportResult struct {
+	Findings []importProblem  `json:"findings"`
+	Count    int              `json:"count"`
+	Rules    []importLintRule `json:"rules"`
+	Message  string           `json:"message"`
+}
+
+func handleLintImportYaml(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	yamlContent, ok := args["yaml"].(string)
+	if !ok || strings.TrimSpace(yamlContent) == "" {
+		return nil, shared.InvalidArgument("YAML content is required")
+	}
+	disabled := map[string]bool{}
+	names, err := stringListArg(args, "disable_rules")
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		known := false
+		for _, rule := range importLintRules {
+			known = known || rule.ID == name
+		}
+		if !known {
+			return nil, shared.InvalidArgument("Unknown lint rule '%s'", name)
+		}
+		disabled[name] = true
+	}
+
+	var projectTags []string
+	if projectID, _ := args["project_id"].(string); projectID != "" {
+		if client == nil {
+			return nil, shared.ErrNoClient
+		}
+		project, err := searchProject(ctx, client, projectID)
+		if err != nil {
+			return nil, err
+		}
+		projectTags = project.TagList.Native()
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal([]byte(yamlContent), &document); err != nil {
+		return nil, shared.InvalidArgument("Invalid YAML: %v", err)
+	}
+	findings := lintImportYaml(yamlContent, projectTags, disabled)
+
+	rules := make([]importLintRule, 0, len(importLintRules))
+	for _, rule := range importLintRules {
+		if !disabled[rule.ID] {
+			rules = append(rules, rule)
+		}
+	}
+	result := lintImportResult{
+		Findings: findings,
+		Count:    len(findings),
+		Rules:    rules,
+	}
+	if len(findings) == 0 {
+		result.Message = "No lint findings."
+	} else {
+		result.Message = fmt.Sprintf("%d lint finding(s). They don't block the import unless import_services runs with strict: true.", len(findings))
+	}
+	return result, nil
+}
+
+// lintImportYaml runs the lint rules on every service of importYaml. projectTags are the
+// tags of the target project, added to project.tags of the YAML. Unparseable YAML has no
+// findings; the preflight checks report it.
+func lintImportYaml(importYaml string, projectTags []string, disabled map[string]bool) []importProblem {
+	findings := []importProblem{}
+	var document yaml.Node
+	if yaml.Unmarshal([]byte(importYaml), &document) != nil || len(document.Content) == 0 {
+		return findings
+	}
+	root := document.Content[0]
+
+	if tags := mappingValue(mappingValue(root, "project"), "tags"); tags != nil && tags.Kind == yaml.SequenceNode {
+		for _, tag := range tags.Content {
+			projectTags = append(projectTags, tag.Value)
+		}
+	}
+	production := false
+	for _, tag := range projectTags {
+		switch strings.ToLower(strings.TrimSpace(tag)) {
+		case "prod", "production":
+			production = true
+		}
+	}
+
+	services := mappingValue(root, "services")
+	if services == nil || services.Kind != yaml.SequenceNode {
+		return findings
+	}
+	for i, node := range services.Content {
+		service := lintService{node: node, path: fmt.Sprintf("services[%d]", i), production: production}
+		if hostname := mappingValue(node, "hostname"); hostname != nil {
+			service.hostname = hostname.Value
+		}
+		if serviceType := mappingValue(node, "type"); serviceType != nil {
+			service.base = serviceTypeBase(serviceType.Value)
+		}
+		for _, rule := range importLintRules {
+			if disabled[rule.ID] {
+				continue
+			}
+			if found, key, message, fix := rule.check(service); found != nil {
+				findings = append(findings, importProblem{
+					Severity: "warning",
+					Rule:     rule.ID,
+					Line:     found.Line,
+					Path:     service.path + key,
+					Service:  service.hostname,
+					Message:  message,
+					Fix:      fix,
+				})
+			}
+		}
+	}
+	sort.SliceStable(findings, func(i, j int) bool { return findings[i].Line < findings[j].Line })
+	return findings
+}
+
+// lintProjectImport lints an import into projectID, counting the project's tags. The tags
+// are skipped when the project can't be read.
+func lintProjectImport(ctx context.Context, client *sdk.Handler, projectID, importYaml string) []importProblem {
+	var projectTags []string
+	if project, err := searchProject(ctx, client, projectID); err == nil {
+		projectTags = project.TagList.Native()
+	}
+	return lintImportYaml(importYaml, projectTags, nil)
+}
+
+// lintDevService reports whether a service is meant for development, judged by its
+// hostname (appdev, devapi) or its zeropsSetup
+func lintDevService(s lintService) bool {
+	if setup := mappingValue(s.node, "zeropsSetup"); setup != nil && strings.Contains(setup.Value, "dev") {
+		return true
+	}
+	return strings.Contains(s.hostname, "dev")
+}
+
+// lintGigabytes reads a RAM value in GB such as 2, 0.5 or 8GB
+func lintGigabytes(node *yaml.Node) (float64, bool) {
+	if node == nil || node.Kind != yaml.ScalarNode {
+		return 0, false
+	}
+	value := strings.TrimSpace(strings.TrimSuffix(strings.ToUpper(node.Value), "GB"))
+	gigabytes, err := strconv.ParseFloat(value, 64)
+	return gigabytes, err == nil
+}
@@ -0,0 +1,366 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/dto/input/body"
+	"github.com/zeropsio/zerops-go/sdk"
+	"gopkg.in/yaml.v3"
+)
+
+// importVerticalSchema is verticalAutoscaling of an imported service
+var importVerticalSchema = yamlObjectOf(nil, map[string]*yamlField{
+	"cpuMode":           yamlEnum("SHARED", "DEDICATED"),
+	"minCpu":            yamlValue(yamlInt),
+	"maxCpu":            yamlValue(yamlInt),
+	"startCpuCoreCount": yamlValue(yamlInt),
+	"minRam":            yamlValue(yamlScalarValue),
+	"maxRam":            yamlValue(yamlScalarValue),
+	"minDisk":           yamlValue(yamlScalarValue),
+	"maxDisk":           yamlValue(yamlScalarValue),
+	"minFreeRamGB":      yamlValue(yamlScalarValue),
+	"minFreeRamPercent": yamlValue(yamlScalarValue),
+	"minFreeCpuCores":   yamlValue(yamlScalarValue),
+	"minFreeCpuPercent": yamlValue(yamlScalarValue),
+})
+
+// importYamlSchema is the structure of an import YAML for import_services and project_apply
+var importYamlSchema = yamlObjectOf([]string{"services"}, map[string]*yamlField{
+	"project": yamlObjectOf(nil, map[string]*yamlField{
+		"name":         yamlValue(yamlString),
+		"description":  yamlValue(yamlString),
+		"tags":         yamlListOf(yamlValue(yamlString)),
+		"corePackage":  yamlEnum("LIGHT", "SERIOUS"),
+		"envVariables": yamlValue(yamlScalarMap),
+		"envIsolation": yamlEnum("none", "service"),
+		"sshIsolation": yamlValue(yamlString),
+	}),
+	"services": yamlListOf(yamlObjectOf([]string{"hostname", "type"}, map[string]*yamlField{
+		"hostname":               yamlValue(yamlString),
+		"type":                   yamlValue(yamlString),
+		"mode":                   yamlEnum("HA", "NON_HA"),
+		"priority":               yamlValue(yamlInt),
+		"startWithoutCode":       yamlValue(yamlBool),
+		"enableSubdomainAccess":  yamlValue(yamlBool),
+		"minContainers":          yamlIntRange(1, 10),
+		"maxContainers":          yamlIntRange(1, 10),
+		"verticalAutoscaling":    importVerticalSchema,
+		"envVariables":           yamlValue(yamlScalarMap),
+		"envSecrets":             yamlValue(yamlScalarMap),
+		"dotEnvSecrets":          yamlValue(yamlString),
+		"buildFromGit":           yamlValue(yamlString),
+		"zeropsSetup":            yamlValue(yamlString),
+		"zeropsYaml":             yamlValue(yamlAnything),
+		"objectStorageSize":      yamlIntRange(1, 100),
+		"objectStoragePolicy":    yamlEnum("private", "public-read", "public-objects-read", "public-write", "public-read-write"),
+		"objectStorageRawPolicy": yamlValue(yamlString),
+		"override":               yamlValue(yamlBool),
+		"mount":                  yamlListOf(yamlValue(yamlString)),
+		"nginx":                  yamlValue(yamlAnything),
+		"envIsolation":           yamlEnum("none", "service"),
+		"sshIsolation":           yamlValue(yamlString),
+	})),
+})
+
+// importProblem is one problem found before an import, with a suggested fix when one is known
+type importProblem struct {
+	Severity string `json:"severity"`
+	Rule     string `json:"rule,omitempty"`
+	Line     int    `json:"line"`
+	Path     string `json:"path"`
+	Service  string `json:"service,omitempty"`
+	Message  string `json:"message"`
+	Fix      string `json:"fix,omitempty"`
+}
+
+// importPreflight holds what the checks of an import YAML need from the API
+type importPreflight struct {
+	// types maps normalized type versions (nodejs_22) to their display form (nodejs@22)
+	types map[string]string
+	// versions lists the available versions per type base, e.g. postgresql -> [postgresql@16, ...]
+	versions map[string][]string
+	// existing are hostnames of the target project; nil skips collision checks
+	existing map[string]bool
+}
+
+// loadImportPreflight reads the live service type list. A failure is returned as a warning
+// so structural checks still run.
+func loadImportPreflight(ctx context.Context, client *sdk.Handler) (*importPreflight, []importProblem) {
+	check := &importPreflight{types: map[string]string{}, versions: map[string][]string{}}
+	available, err := availableServiceTypes(ctx, client)
+	if err != nil {
+		return check, []importProblem{{Severity: "warning", Path: "services", Message: fmt.Sprintf("service types were not checked: %v", err)}}
+	}
+	for _, serviceType := range available {
+		normalized := normalizeTypeVersion(serviceType)
+		if _, ok := check.types[normalized]; ok {
+			continue
+		}
+		check.types[normalized] = serviceType
+		base := serviceTypeBase(serviceType)
+		check.versions[base] = append(check.versions[base], serviceType)
+	}
+	return check, nil
+}
+
+// availableServiceTypes lists type@version for every type version import accepts
+func availableServiceTypes(ctx context.Context, client *sdk.Handler) ([]string, error) {
+	resp, err := client.PostServiceStackTypeSearch(ctx, body.EsFilter{})
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to get service types")
+	}
+	output, err := resp.Output()
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to parse response")
+	}
+
+	var serviceTypes []string
+	for _, item := range output.Items {
+		baseName := item.Name.Native()
+		if !importableServiceType(baseName) {
+			continue
+		}
+		if item.DefaultServiceStackVersion != nil {
+			serviceTypes = append(serviceTypes, fmt.Sprintf("%s@%s", baseName, item.DefaultServiceStackVersion.Name.Native()))
+		}
+		for _, version := range item.ServiceStackTypeVersionList {
+			serviceTypes = append(serviceTypes, fmt.Sprintf("%s@%s", baseName, version.Name.Native()))
+		}
+	}
+	return serviceTypes, nil
+}
+
+// importableServiceType filters out internal build/prepare services and unavailable services
+func importableServiceType(baseName string) bool {
+	if strings.HasPrefix(baseName, "build ") ||
+		strings.HasPrefix(baseName, "prepare ") ||
+		strings.HasPrefix(baseName, "zbuild ") {
+		return false
+	}
+	switch baseName {
+	case "MongoDB", "RabbitMQ", "Core", "L7 HTTP Balancer", "Generic Runtime":
+		return false
+	}
+	return true
+}
+
+// check validates importYaml and returns every problem found, errors first
+func (p *importPreflight) check(importYaml string) []importProblem {
+	var problems []importProblem
+	root, issues := checkYamlDocument([]byte(importYaml), importYamlSchema)
+	for _, issue := range issues {
+		problems = append(problems, importProblem{Severity: issue.Severity, Line: issue.Line, Path: issue.Path, Message: issue.Message})
+	}
+
+	services := mappingValue(root, "services")
+	if services == nil || services.Kind != yaml.SequenceNode {
+		return problems
+	}
+	if len(services.Content) == 0 {
+		problems = append(problems, importProblem{Severity: "error", Line: services.Line, Path: "services", Message: "no services defined"})
+	}
+
+	seen := map[string]int{}
+	for i, service := range services.Content {
+		servicePath := fmt.Sprintf("services[%d]", i)
+		hostname := ""
+		if node := mappingValue(service, "hostname"); node != nil && node.Kind == yaml.ScalarNode {
+			hostname = node.Value
+			problems = append(problems, p.checkHostname(node, servicePath, seen)...)
+		}
+		add := func(severity string, node *yaml.Node, key, message, fix string) {
+			problems = append(problems, importProblem{Severity: severity, Line: node.Line, Path: servicePath + key, Service: hostname, Message: message, Fix: fix})
+		}
+
+		// base stays empty for unknown types so the checks below do not guess
+		base := ""
+		if node := mappingValue(service, "type"); node != nil && node.Kind == yaml.ScalarNode {
+			if message, fix := p.checkType(node.Value); message != "" {
+				add("error", node, ".type", message, fix)
+			}
+			if known := serviceTypeBase(node.Value); len(p.types) == 0 || len(p.versions[known]) > 0 {
+				base = known
+			}
+		}
+
+		mode := mappingValue(service, "mode")
+		switch {
+		case base == "" || base == "object-storage":
+		case managedServiceTypes[base] && mode == nil:
+			add("error", service, ".mode", fmt.Sprintf("%s needs a mode", base), "add mode: NON_HA, or mode: HA for production data")
+		case !managedServiceTypes[base] && mode != nil:
+			add("warning", mode, ".mode", "mode only applies to managed services and is ignored for "+base, "remove mode")
+		}
+		if base == "object-storage" && mappingValue(service, "objectStorageSize") == nil {
+			add("error", service, ".objectStorageSize", "object-storage needs objectStorageSize", "add objectStorageSize: 2 (GB)")
+		}
+		if startWithoutCode := mappingValue(service, "startWithoutCode"); startWithoutCode != nil && managedServiceTypes[base] {
+			add("warning", startWithoutCode, ".startWithoutCode", "startWithoutCode only applies to runtime services", "remove startWithoutCode")
+		}
+
+		minContainers, minOK := yamlIntValue(mappingValue(service, "minContainers"))
+		maxContainers, maxOK := yamlIntValue(mappingValue(service, "maxContainers"))
+		if minOK && maxOK && minContainers > maxContainers {
+			add("error", mappingValue(service, "minContainers"), ".minContainers",
+				fmt.Sprintf("minContainers (%d) is greater than maxContainers (%d)", minContainers, maxContainers),
+				fmt.Sprintf("set maxContainers: %d or lower minContainers", minContainers))
+		}
+		if (minOK || maxOK) && managedServiceTypes[base] {
+			add("warning", service, ".minContainers", "container counts of managed services follow their mode and are ignored", "use mode: HA for more containers")
+		}
+	}
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		if problems[i].Severity != problems[j].Severity {
+			return problems[i].Severity == "error"
+		}
+		return problems[i].Line < problems[j].Line
+	})
+	return problems
+}
+
+// checkHostname reports invalid, duplicate and already used hostnames
+func (p *importPreflight) checkHostname(node *yaml.Node, servicePath string, seen map[string]int) []importProblem {
+	hostname := node.Value
+	problem := func(message, fix string) importProblem {
+		return importProblem{Severity: "error", Line: node.Line, Path: servicePath + ".hostname", Service: hostname, Message: message, Fix: fix}
+	}
+
+	var problems []importProblem
+	switch {
+	case !hostnamePattern.MatchString(hostname):
+		problems = append(problems, problem("hostname must be 1-25 lowercase letters and digits", "use hostname: "+sanitizeHostname(hostname)))
+	case hostname[0] >= '0' && hostname[0] <= '9':
+		problems = append(problems, problem("hostname must start with a letter", "use hostname: "+sanitizeHostname(hostname)))
+	}
+	if first, ok := seen[hostname]; ok {
+		taken := map[string]bool{}
+		for name := range seen {
+			taken[name] = true
+		}
+		for name := range p.existing {
+			taken[name] = true
+		}
+		problems = append(problems, problem(fmt.Sprintf("duplicate hostname, first used on line %d", first), "use hostname: "+uniqueHostname(hostname, taken)))
+	} else {
+		seen[hostname] = node.Line
+	}
+	if p.existing[hostname] {
+		taken := map[string]bool{}
+		for name := range p.existing {
+			taken[name] = true
+		}
+		for name := range seen {
+			taken[name] = true
+		}
+		problems = append(problems, problem("a service with this hostname already exists in the project", "use hostname: "+uniqueHostname(hostname, taken)))
+	}
+	return problems
+}
+
+// checkType describes why serviceType cannot be imported and how to fix it, or returns ""
+func (p *importPreflight) checkType(serviceType string) (string, string) {
+	if len(p.types) == 0 {
+		return "", ""
+	}
+	if _, ok := p.types[normalizeTypeVersion(serviceType)]; ok {
+		return "", ""
+	}
+	// A type without a version, like object-storage, gets the default version
+	if !strings.Contains(serviceType, "@") && len(p.versions[strings.ToLower(serviceType)]) > 0 {
+		return "", ""
+	}
+	base := serviceTypeBase(serviceType)
+	if versions := p.versions[base]; len(versions) > 0 {
+		return fmt.Sprintf("version of '%s' is not available", serviceType), "use one of: " + strings.Join(versions, ", ")
+	}
+	bases := make(map[string]bool, len(p.versions))
+	for name := range p.versions {
+		bases[name] = true
+	}
+	if suggestion := closestKey(base, bases); suggestion != "" {
+		return fmt.Sprintf("unknown service type '%s'", serviceType), "did you mean " + p.versions[suggestion][0] + "?"
+	}
+	return fmt.Sprintf("unknown service type '%s'", serviceType), "list the available types with get_service_types"
+}
+
+// yamlIntValue reads an integer scalar node
+func yamlIntValue(node *yaml.Node) (int, bool) {
+	if node == nil || node.Kind != yaml.ScalarNode {
+		return 0, false
+	}
+	value, err := strconv.Atoi(node.Value)
+	return value, err == nil
+}
+
+// importPreflightSummary counts the problems found by the import checks
+type importPreflightSummary struct {
+	Valid    bool            `json:"valid"`
+	Errors   int             `json:"errors"`
+	Warnings int             `json:"warnings"`
+	Problems []importProblem `json:"problems"`
+}
+
+// importDryRunResult is the result of import_services with dry_run
+type importDryRunResult struct {
+	Status    string `json:"status"`
+	ProjectID string `json:"project_id"`
+	importPreflightSummary
+	Lint    []importProblem `json:"lint"`
+	Message string          `json:"message"`
+}
+
+// importPreflightResult summarizes the problems of a dry run
+func importPreflightResult(problems []importProblem) importPreflightSummary {
+	errors, warnings := 0, 0
+	for _, problem := range problems {
+		if problem.Severity == "error" {
+			errors++
+		} else {
+			warnings++
+		}
+	}
+	if problems == nil {
+		problems = []importProblem{}
+	}
+	return importPreflightSummary{
+		Valid:    errors == 0,
+		Errors:   errors,
+		Warnings: warnings,
+		Problems: problems,
+	}
+}
+
+// dryRunImport checks an import_services YAML against the live type list and the hostnames
+// already used in the project, without importing anything
+func dryRunImport(ctx context.Context, client *sdk.Handler, projectID, importYaml string) importDryRunResult {
+	preflight, problems := loadImportPreflight(ctx, client)
+	if project, err := searchProject(ctx, client, projectID); err != nil {
+		problems = append(problems, importProblem{Severity: "warning", Path: "services", Message: fmt.Sprintf("hostname collisions were not checked: %v", err)})
+	} else if services, err := searchProjectServices(ctx, client, project); err != nil {
+		problems = append(problems, importProblem{Severity: "warning", Path: "services", Message: fmt.Sprintf("hostname collisions were not checked: %v", err)})
+	} else {
+		preflight.existing = make(map[string]bool, len(services))
+		for _, service := range services {
+			preflight.existing[service.Name.Native()] = true
+		}
+	}
+	problems = append(preflight.check(importYaml), problems...)
+
+	result := importDryRunResult{
+		Status:                 "dry_run",
+		ProjectID:              projectID,
+		importPreflightSummary: importPreflightResult(problems),
+	}
+	if result.Valid {
+		result.Message = "No blocking problems found. Run import_services again without dry_run to import."
+	} else {
+		result.Message = fmt.Sprintf("%d problem(s) would make the import fail. Apply the fixes and run the dry run again.", result.Errors)
+	}
+	return result
+}
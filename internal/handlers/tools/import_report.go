@@ -0,0 +1,136 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/zeropsio/zerops-go/dto/output"
+	"gopkg.in/yaml.v3"
+)
+
+// importReport is the per-service outcome of a service import
+type importReport struct {
+	Services []importServiceReport
+	Created  int
+	Failed   []string
+}
+
+// importServiceReport is the outcome of one service of an import
+type importServiceReport struct {
+	ID              string       `json:"id,omitempty"`
+	Hostname        string       `json:"hostname"`
+	Status          string       `json:"status"`
+	Error           *importError `json:"error,omitempty"`
+	ProcessCount    int          `json:"process_count,omitempty"`
+	ImportProcessID string       `json:"import_process_id,omitempty"`
+}
+
+// importError is the reason Zerops gave for a service it did not create
+type importError struct {
+	Code    string          `json:"code"`
+	Message string          `json:"message"`
+	Meta    json.RawMessage `json:"meta,omitempty"`
+}
+
+// buildImportReport matches the import result against the hostnames requested in the YAML.
+// Hostnames missing from the result are reported as not created.
+func buildImportReport(result output.ProjectImport, requested []string) importReport {
+	report := importReport{}
+	seen := make(map[string]bool, len(result.ServiceStacks))
+
+	for _, stack := range result.ServiceStacks {
+		hostname := stack.Name.Native()
+		seen[hostname] = true
+
+		serviceInfo := importServiceReport{
+			ID:       string(stack.Id),
+			Hostname: hostname,
+		}
+
+		if stack.Error != nil {
+			serviceInfo.Status = "failed"
+			importErr := &importError{
+				Code:    stack.Error.Code.Native(),
+				Message: stack.Error.Message.Native(),
+			}
+			if meta := stack.Error.Meta.Native(); len(meta) > 0 && string(meta) != "null" {
+				importErr.Meta = json.RawMessage(meta)
+			}
+			serviceInfo.Error = importErr
+			report.Failed = append(report.Failed, hostname)
+		} else {
+			serviceInfo.Status = "created"
+			report.Created++
+		}
+
+		if len(stack.Processes) > 0 {
+			serviceInfo.ProcessCount = len(stack.Processes)
+			serviceInfo.ImportProcessID = string(stack.Processes[0].Id)
+		}
+
+		report.Services = append(report.Services, serviceInfo)
+	}
+
+	for _, hostname := range requested {
+		if hostname == "" || seen[hostname] {
+			continue
+		}
+		report.Services = append(report.Services, importServiceReport{
+			Hostname: hostname,
+			Status:   "not_created",
+		})
+		report.Failed = append(report.Failed, hostname)
+	}
+
+	return report
+}
+
+// filterImportYaml keeps only the services with the given hostnames, preserving key order and comments.
+// The project section is dropped because the project already exists.
+func filterImportYaml(importYaml string, hostnames []string) (string, error) {
+	keep := make(map[string]bool, len(hostnames))
+	for _, hostname := range hostnames {
+		keep[hostname] = true
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(importYaml), &doc); err != nil {
+		return "", err
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return "", fmt.Errorf("import YAML is not a mapping")
+	}
+
+	root := doc.Content[0]
+	var services *yaml.Node
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == "services" {
+			services = root.Content[i+1]
+		}
+	}
+	if services == nil || services.Kind != yaml.SequenceNode {
+		return "", fmt.Errorf("import YAML has no services list")
+	}
+
+	filtered := &yaml.Node{Kind: yaml.SequenceNode, Tag: services.Tag}
+	for _, service := range services.Content {
+		for i := 0; i+1 < len(service.Content); i += 2 {
+			if service.Content[i].Value == "hostname" && keep[service.Content[i+1].Value] {
+				filtered.Content = append(filtered.Content, service)
+				break
+			}
+		}
+	}
+
+	out, err := yaml.Marshal(&yaml.Node{
+		Kind: yaml.MappingNode,
+		Content: []*yaml.Node{
+			{Kind: yaml.ScalarNode, Value: "services"},
+			filtered,
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
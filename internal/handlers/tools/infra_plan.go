@@ -0,0 +1,500 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/sdk"
+	"gopkg.in/yaml.v3"
+)
+
+// runtimeProfile is how a runtime is built and started in the generated zerops.yml
+type runtimeProfile struct {
+	serviceType   string
+	buildBase     string
+	runBase       string
+	buildCommands []string
+	deployFiles   []string
+	// start may contain {port}, replaced by the planned port
+	start string
+}
+
+// runtimeProfiles are the runtimes plan_infrastructure and generate_zerops_yml can generate configuration for
+var runtimeProfiles = map[string]runtimeProfile{
+	"nodejs": {
+		serviceType: "nodejs@22", buildBase: "nodejs@22", runBase: "nodejs@22",
+		buildCommands: []string{"npm ci", "npm run build"},
+		deployFiles:   []string{"./dist", "./node_modules", "./package.json"},
+		start:         "npm start",
+	},
+	"bun": {
+		serviceType: "bun@1.1", buildBase: "bun@1.1", runBase: "bun@1.1",
+		buildCommands: []string{"bun install", "bun run build"},
+		deployFiles:   []string{"./"},
+		start:         "bun start",
+	},
+	"python": {
+		serviceType: "python@3.12", buildBase: "python@3.12", runBase: "python@3.12",
+		buildCommands: []string{"pip install --target=./vendor -r requirements.txt"},
+		deployFiles:   []string{"./"},
+		start:         "PYTHONPATH=./vendor python -m gunicorn -b 0.0.0.0:{port} app:app",
+	},
+	"go": {
+		serviceType: "go@1", buildBase: "go@1", runBase: "go@1",
+		buildCommands: []string{"go build -o app ./..."},
+		deployFiles:   []string{"./app"},
+		start:         "./app",
+	},
+	"php": {
+		serviceType: "php-nginx@8.3", buildBase: "php@8.3", runBase: "php-nginx@8.3",
+		buildCommands: []string{"composer install --no-dev --optimize-autoloader"},
+		deployFiles:   []string{"./"},
+	},
+}
+
+// planDatabases map the database choices to their service types
+var planDatabases = map[string]string{
+	"postgresql": "postgresql@17",
+	"mariadb":    "mariadb@11",
+	"mongodb":    "mongodb@7",
+}
+
+// trafficProfile sets the scaling of production services for a traffic level
+type trafficProfile struct {
+	minContainers int
+	maxContainers int
+	managedMode   string
+}
+
+var trafficProfiles = map[string]trafficProfile{
+	"low":    {minContainers: 1, maxContainers: 2, managedMode: "NON_HA"},
+	"medium": {minContainers: 1, maxContainers: 4, managedMode: "NON_HA"},
+	"high":   {minContainers: 2, maxContainers: 6, managedMode: "HA"},
+}
+
+var planAppNamePattern = regexp.MustCompile(`^[a-z][a-z0-9]{0,19}$`)
+
+// planService is one service of the generated import YAML
+type planService struct {
+	hostname    string
+	serviceType string
+	environment string
+	managed     bool
+	lines       []string
+}
+
+// RegisterInfraPlan registers the plan_infrastructure tool
+func RegisterInfraPlan() {
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "plan_infrastructure",
+		Description: toolDescription("plan_infrastructure"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"runtime": map[string]interface{}{
+					"type":        "string",
+					"description": "REQUIRED: Runtime of the app",
+					"enum":        []string{"nodejs", "bun", "python", "go", "php"},
+				},
+				"app_name": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Base hostname of the app; each environment adds its suffix, e.g. appdev, appstage, appprod (default: app)",
+				},
+				"port": map[string]interface{}{
+					"type":        "integer",
+					"description": "OPTIONAL: Port the app listens on (default: runtime default)",
+					"minimum":     1,
+					"maximum":     65535,
+				},
+				"database": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Database to add (default: none)",
+					"enum":        []string{"none", "postgresql", "mariadb", "mongodb"},
+				},
+				"needs_cache": map[string]interface{}{
+					"type":        "boolean",
+					"description": "OPTIONAL: Add a Valkey cache (default: false)",
+				},
+				"needs_storage": map[string]interface{}{
+					"type":        "boolean",
+					"description": "OPTIONAL: Add an object storage bucket (default: false)",
+				},
+				"traffic": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Expected production traffic; sets prod containers and HA mode (default: low)",
+					"enum":        []string{"low", "medium", "high"},
+				},
+				"environments": map[string]interface{}{
+					"type":        "array",
+					"description": "OPTIONAL: Environments to plan (default: [dev, stage])",
+					"items": map[string]interface{}{
+						"type": "string",
+						"enum": []string{"dev", "stage", "prod"},
+					},
+					"minItems": 1,
+				},
+				"project_id": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Project to import into; filled into tool_calls instead of a placeholder",
+				},
+			},
+			"required":             []string{"runtime"},
+			"additionalProperties": false,
+		},
+		Annotations: shared.ReadOnly(),
+		Output:      infraPlanResult{},
+		Handler:     handlePlanInfrastructure,
+	})
+}
+
+// infraPlanResult is the result of plan_infrastructure
+type infraPlanResult struct {
+	Spec       infraPlanSpec     `json:"spec"`
+	Services   []plannedService  `json:"services"`
+	ImportYAML string            `json:"import_yaml"`
+	ZeropsYml  string            `json:"zerops_yml"`
+	ToolCalls  []plannedToolCall `json:"tool_calls"`
+	Notes      []string          `json:"notes,omitempty"`
+}
+
+// infraPlanSpec echoes the effective arguments of plan_infrastructure
+type infraPlanSpec struct {
+	Runtime      string   `json:"runtime"`
+	AppName      string   `json:"app_name"`
+	Port         int      `json:"port"`
+	Database     string   `json:"database"`
+	NeedsCache   bool     `json:"needs_cache"`
+	NeedsStorage bool     `json:"needs_storage"`
+	Traffic      string   `json:"traffic"`
+	Environments []string `json:"environments"`
+}
+
+// plannedService is a service of the plan; managed services are shared by the environments
+type plannedService struct {
+	Hostname    string `json:"hostname"`
+	Type        string `json:"type"`
+	Managed     bool   `json:"managed"`
+	Environment string `json:"environment,omitempty"`
+}
+
+// plannedToolCall is one step of applying the plan. DependsOn lists the steps that must
+// finish first.
+type plannedToolCall struct {
+	Step      int                    `json:"step"`
+	Kind      string                 `json:"kind"`
+	Tool      string                 `json:"tool"`
+	Arguments map[string]interface{} `json:"arguments"`
+	Purpose   string                 `json:"purpose"`
+	DependsOn []int                  `json:"depends_on"`
+}
+
+func handlePlanInfrastructure(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	runtime, _ := args["runtime"].(string)
+	runtime = strings.ToLower(strings.SplitN(runtime, "@", 2)[0])
+	profile, ok := runtimeProfiles[runtime]
+	if !ok {
+		return nil, shared.InvalidArgument("Unsupported runtime '%s'. Use one of: nodejs, bun, python, go, php.", runtime)
+	}
+
+	appName, _ := args["app_name"].(string)
+	if appName == "" {
+		appName = "app"
+	}
+	if !planAppNamePattern.MatchString(appName) {
+		return nil, shared.InvalidArgument("app_name must start with a letter and have at most 20 lowercase letters and digits, got '%s'", appName)
+	}
+
+	port := runtimeDefaultPorts[runtime]
+	if value, ok := args["port"].(float64); ok {
+		port = int(value)
+	}
+	if port < 1 || port > 65535 {
+		return nil, shared.InvalidArgument("port must be between 1 and 65535")
+	}
+
+	database, _ := args["database"].(string)
+	if database == "none" {
+		database = ""
+	}
+	if _, ok := planDatabases[database]; database != "" && !ok {
+		return nil, shared.InvalidArgument("database must be none, postgresql, mariadb or mongodb")
+	}
+	needsCache, _ := args["needs_cache"].(bool)
+	needsStorage, _ := args["needs_storage"].(bool)
+
+	trafficName, _ := args["traffic"].(string)
+	if trafficName == "" {
+		trafficName = "low"
+	}
+	traffic, ok := trafficProfiles[trafficName]
+	if !ok {
+		return nil, shared.InvalidArgument("traffic must be low, medium or high")
+	}
+
+	environments := []string{"dev", "stage"}
+	if list, ok := args["environments"].([]interface{}); ok && len(list) > 0 {
+		environments = nil
+		seen := map[string]bool{}
+		for _, item := range list {
+			name, _ := item.(string)
+			if _, ok := environmentNames[name]; !ok {
+				return nil, shared.InvalidArgument("Unknown environment '%v'. Use dev, stage or prod.", item)
+			}
+			if !seen[name] {
+				seen[name] = true
+				environments = append(environments, name)
+			}
+		}
+	}
+	projectID, _ := args["project_id"].(string)
+	if projectID == "" {
+		projectID = "<project_id>"
+	}
+
+	hasProd := false
+	for _, env := range environments {
+		hasProd = hasProd || env == "prod"
+	}
+	managedMode := "NON_HA"
+	if hasProd {
+		managedMode = traffic.managedMode
+	}
+
+	var services []planService
+	if database != "" {
+		services = append(services, planService{
+			hostname: "db", serviceType: planDatabases[database], managed: true,
+			lines: []string{"mode: " + managedMode},
+		})
+	}
+	if needsCache {
+		services = append(services, planService{
+			hostname: "cache", serviceType: "valkey@7.2", managed: true,
+			lines: []string{"mode: " + managedMode},
+		})
+	}
+	if needsStorage {
+		services = append(services, planService{
+			hostname: "storage", serviceType: "object-storage", managed: true,
+			lines: []string{"objectStorageSize: 2", "objectStoragePolicy: private"},
+		})
+	}
+	for _, env := range environments {
+		service := planService{
+			hostname:    appName + environmentNames[env],
+			serviceType: profile.serviceType,
+			environment: env,
+		}
+		switch env {
+		case "dev":
+			service.lines = []string{"startWithoutCode: true", "enableSubdomainAccess: true", "minContainers: 1", "maxContainers: 1"}
+		case "stage":
+			service.lines = []string{"enableSubdomainAccess: true", "minContainers: 1", "maxContainers: 1"}
+		case "prod":
+			service.lines = []string{
+				"enableSubdomainAccess: true",
+				fmt.Sprintf("minContainers: %d", traffic.minContainers),
+				fmt.Sprintf("maxContainers: %d", traffic.maxContainers),
+			}
+		}
+		services = append(services, service)
+	}
+
+	plannedServices := make([]plannedService, 0, len(services))
+	for _, service := range services {
+		plannedServices = append(plannedServices, plannedService{
+			Hostname:    service.hostname,
+			Type:        service.serviceType,
+			Managed:     service.managed,
+			Environment: service.environment,
+		})
+	}
+
+	result := infraPlanResult{
+		Spec: infraPlanSpec{
+			Runtime:      runtime,
+			AppName:      appName,
+			Port:         port,
+			Database:     database,
+			NeedsCache:   needsCache,
+			NeedsStorage: needsStorage,
+			Traffic:      trafficName,
+			Environments: environments,
+		},
+		Services:   plannedServices,
+		ImportYAML: renderPlanImport(services),
+		ZeropsYml:  renderZeropsYml(profile, environments, []int{port}, "/", planEnvVariables(database, needsCache, needsStorage)),
+		ToolCalls:  planToolCalls(projectID, appName, services),
+	}
+
+	var notes []string
+	if hasProd && len(environments) > 1 {
+		notes = append(notes, "prod shares the managed services with the other environments in one project; consider a separate project for production data.")
+	}
+	if runtime == "php" {
+		notes = append(notes, "php-nginx serves the deployed files; set run.documentRoot (e.g. public) for frameworks like Laravel.")
+	}
+	result.Notes = notes
+	return result, nil
+}
+
+// renderPlanImport renders the import YAML with managed services first so they exist before runtimes start
+func renderPlanImport(services []planService) string {
+	var b strings.Builder
+	b.WriteString("services:\n")
+	for _, service := range services {
+		fmt.Fprintf(&b, "  - hostname: %s\n    type: %s\n", service.hostname, service.serviceType)
+		if service.managed {
+			b.WriteString("    priority: 10\n")
+		}
+		for _, line := range service.lines {
+			fmt.Fprintf(&b, "    %s\n", line)
+		}
+	}
+	return b.String()
+}
+
+// planEnvVariables references the managed services from the app's environment
+func planEnvVariables(database string, needsCache, needsStorage bool) [][2]string {
+	var vars [][2]string
+	if database != "" {
+		vars = append(vars,
+			[2]string{"DB_HOST", "${db_hostname}"},
+			[2]string{"DB_PORT", "${db_port}"},
+			[2]string{"DB_USER", "${db_user}"},
+			[2]string{"DB_PASS", "${db_password}"},
+		)
+	}
+	if needsCache {
+		vars = append(vars,
+			[2]string{"CACHE_HOST", "${cache_hostname}"},
+			[2]string{"CACHE_PORT", "${cache_port}"},
+		)
+	}
+	if needsStorage {
+		vars = append(vars,
+			[2]string{"S3_ENDPOINT", "${storage_apiUrl}"},
+			[2]string{"S3_BUCKET", "${storage_bucketName}"},
+			[2]string{"S3_ACCESS_KEY_ID", "${storage_accessKeyId}"},
+			[2]string{"S3_SECRET_ACCESS_KEY", "${storage_secretAccessKey}"},
+		)
+	}
+	return vars
+}
+
+// renderZeropsYml renders one setup per name. A setup named dev deploys the source and idles;
+// the others build, run start and are checked on the first port, which serves HTTP.
+func renderZeropsYml(profile runtimeProfile, setups []string, ports []int, healthPath string, envVars [][2]string) string {
+	port := ports[0]
+	var b strings.Builder
+	b.WriteString("zerops:\n")
+	for _, setup := range setups {
+		fmt.Fprintf(&b, "  - setup: %s\n    build:\n      base: %s\n", yamlScalar(setup), profile.buildBase)
+		if setup == "dev" {
+			b.WriteString("      # deploy the source; the dev server is started by hand\n      deployFiles: ./\n")
+		} else {
+			b.WriteString("      buildCommands:\n")
+			for _, command := range profile.buildCommands {
+				fmt.Fprintf(&b, "        - %s\n", yamlScalar(command))
+			}
+			b.WriteString("      deployFiles:\n")
+			for _, file := range profile.deployFiles {
+				fmt.Fprintf(&b, "        - %s\n", yamlScalar(file))
+			}
+			fmt.Fprintf(&b, "    deploy:\n      readinessCheck:\n        httpGet:\n          port: %d\n          path: %s\n", port, yamlScalar(healthPath))
+		}
+
+		fmt.Fprintf(&b, "    run:\n      base: %s\n", profile.runBase)
+		if !strings.HasPrefix(profile.runBase, "php-") {
+			b.WriteString("      ports:\n")
+			for i, p := range ports {
+				fmt.Fprintf(&b, "        - port: %d\n          httpSupport: %t\n", p, i == 0)
+			}
+		}
+		if len(envVars) > 0 {
+			b.WriteString("      envVariables:\n")
+			for _, variable := range envVars {
+				fmt.Fprintf(&b, "        %s: %s\n", variable[0], yamlScalar(variable[1]))
+			}
+		}
+		switch {
+		case setup == "dev" && profile.start != "":
+			b.WriteString("      start: zsc noop --silent\n")
+		case profile.start != "":
+			fmt.Fprintf(&b, "      start: %s\n", yamlScalar(strings.ReplaceAll(profile.start, "{port}", fmt.Sprint(port))))
+		}
+		if setup != "dev" {
+			fmt.Fprintf(&b, "      healthCheck:\n        httpGet:\n          port: %d\n          path: %s\n", port, yamlScalar(healthPath))
+		}
+	}
+	return b.String()
+}
+
+// yamlScalar renders value as a single-line YAML scalar, quoting it only when needed
+func yamlScalar(value string) string {
+	data, err := yaml.Marshal(value)
+	if err != nil || strings.Count(strings.TrimRight(string(data), "\n"), "\n") > 0 {
+		return strconv.Quote(value)
+	}
+	return strings.TrimRight(string(data), "\n")
+}
+
+// planToolCalls lists the calls that apply the plan, in order, with the steps each depends on
+func planToolCalls(projectID, appName string, services []planService) []plannedToolCall {
+	var calls []plannedToolCall
+	add := func(tool string, arguments map[string]interface{}, purpose string, dependsOn ...int) int {
+		step := len(calls) + 1
+		call := plannedToolCall{
+			Step:      step,
+			Kind:      "tool",
+			Tool:      tool,
+			Arguments: arguments,
+			Purpose:   purpose,
+			DependsOn: dependsOn,
+		}
+		if dependsOn == nil {
+			call.DependsOn = []int{}
+		}
+		calls = append(calls, call)
+		return step
+	}
+
+	verify := add("get_service_types", map[string]interface{}{}, "Verify the planned versions are available")
+	imported := add("import_services", map[string]interface{}{
+		"project_id": projectID,
+		"yaml":       "<import_yaml>",
+	}, "Create all planned services", verify)
+	processes := add("wait_for_process", map[string]interface{}{
+		"process_id": "<process_id of each service from step " + fmt.Sprint(imported) + ">",
+	}, "Wait until every service is created", imported)
+
+	var deploySteps []int
+	for _, service := range services {
+		if service.managed {
+			continue
+		}
+		ready := add("wait_for_service", map[string]interface{}{
+			"service_id": fmt.Sprintf("<service_id of %s>", service.hostname),
+		}, fmt.Sprintf("Wait until %s is running", service.hostname), processes)
+		deploy := add("deploy_push", map[string]interface{}{
+			"service_id":  fmt.Sprintf("<service_id of %s>", service.hostname),
+			"setup":       service.environment,
+			"working_dir": "<app source directory>",
+		}, fmt.Sprintf("Deploy the app to %s", service.hostname), ready)
+		deploySteps = append(deploySteps, deploy)
+		add("get_service_logs", map[string]interface{}{
+			"service_id": fmt.Sprintf("<service_id of %s>", service.hostname),
+			"limit":      50,
+		}, fmt.Sprintf("Check the first run of %s", service.hostname), deploy)
+	}
+	if len(deploySteps) > 0 {
+		add("discovery", map[string]interface{}{
+			"project_id": projectID,
+		}, fmt.Sprintf("Confirm all %s services are ACTIVE and read their subdomain URLs", appName), deploySteps...)
+	}
+	return calls
+}
@@ -0,0 +1,265 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/dto/input/body"
+	"github.com/zeropsio/zerops-go/dto/output"
+	"github.com/zeropsio/zerops-go/sdk"
+	"github.com/zeropsio/zerops-go/types"
+)
+
+// keyScope is what an API key can access
+type keyScope struct {
+	userID string
+	email  string
+	// orgs maps organization ID to its name and the key's role in it
+	orgs map[string]orgAccess
+}
+
+type orgAccess struct {
+	name string
+	role string
+}
+
+// orgIDs returns the organization IDs ordered by organization name
+func (s *keyScope) orgIDs() []string {
+	ids := make([]string, 0, len(s.orgs))
+	for id := range s.orgs {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return s.orgs[ids[i]].name < s.orgs[ids[j]].name })
+	return ids
+}
+
+// RegisterKeyRotation registers the rotate_api_key tool
+func RegisterKeyRotation() {
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "rotate_api_key",
+		Description: toolDescription("rotate_api_key"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"new_api_key": map[string]interface{}{
+					"type":        "string",
+					"description": "REQUIRED: The new Zerops API key",
+					"minLength":   1,
+				},
+				"force": map[string]interface{}{
+					"type":        "boolean",
+					"description": "OPTIONAL: Swap even when the new key does not keep the current access (default: false)",
+				},
+			},
+			"required":             []string{"new_api_key"},
+			"additionalProperties": false,
+		},
+		Annotations: shared.Mutating(true, true),
+		Output:      rotateAPIKeyResult{},
+		Handler:     handleRotateAPIKey,
+	})
+}
+
+// rotateAPIKeyResult is the result of rotate_api_key. Differences is missing when the
+// current key could not be read.
+type rotateAPIKeyResult struct {
+	User                  string           `json:"user"`
+	Organizations         int              `json:"organizations"`
+	Differences           *keyScopeDiff    `json:"differences,omitempty"`
+	Verification          []orgAccessCheck `json:"verification"`
+	Rotated               bool             `json:"rotated"`
+	ScheduledActionsMoved *int             `json:"scheduled_actions_moved,omitempty"`
+	Warnings              []string         `json:"warnings,omitempty"`
+	Message               string           `json:"message"`
+}
+
+// keyScopeDiff is what the new key can access compared to the current one
+type keyScopeDiff struct {
+	SameUser            bool     `json:"same_user"`
+	CurrentUser         string   `json:"current_user,omitempty"`
+	NewUser             string   `json:"new_user,omitempty"`
+	LostOrganizations   []string `json:"lost_organizations"`
+	GainedOrganizations []string `json:"gained_organizations"`
+	RoleChanges         []string `json:"role_changes"`
+}
+
+// orgAccessCheck tells whether the new key can list the projects of an organization
+type orgAccessCheck struct {
+	Organization string `json:"organization"`
+	Role         string `json:"role"`
+	Accessible   bool   `json:"accessible"`
+	Error        string `json:"error,omitempty"`
+}
+
+func handleRotateAPIKey(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+
+	newKey, _ := args["new_api_key"].(string)
+	if newKey == "" {
+		return nil, shared.InvalidArgument("new_api_key is required")
+	}
+	force, _ := args["force"].(bool)
+
+	factory := shared.ClientFactoryFromContext(ctx)
+	if factory == nil {
+		return nil, shared.NewToolError(shared.ErrForbidden, "This transport does not support API key rotation")
+	}
+	newClient := factory(newKey)
+
+	newScope, err := readKeyScope(ctx, newClient)
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "The new API key was rejected")
+	}
+
+	result := rotateAPIKeyResult{
+		User:          newScope.email,
+		Organizations: len(newScope.orgs),
+	}
+
+	// The old key may already be revoked; rotation then continues without a comparison
+	var warnings []string
+	oldScope, err := readKeyScope(ctx, client)
+	lostAccess := false
+	if err != nil {
+		warnings = append(warnings, "current key could not be read, scopes were not compared: "+err.Error())
+	} else {
+		differences, lost := compareKeyScopes(oldScope, newScope)
+		result.Differences = differences
+		lostAccess = lost
+	}
+
+	verification, failed := verifyOrgAccess(ctx, newClient, newScope)
+	result.Verification = verification
+	if failed > 0 {
+		lostAccess = true
+	}
+
+	if lostAccess && !force {
+		result.Warnings = warnings
+		result.Message = "The new key does not keep the access of the current one (user, organizations or roles). Review the differences and call again with force: true to swap anyway."
+		return result, nil
+	}
+
+	store := shared.KeyStoreFromContext(ctx)
+	if store == nil {
+		result.Warnings = warnings
+		result.Message = "The new key is valid. This client sends its key with every request: update the Authorization header in the MCP client configuration, then revoke the old key."
+		return result, nil
+	}
+	if err := store(ctx, newKey, newClient); err != nil {
+		return nil, shared.NewToolError(shared.ErrAPIUnavailable, "Failed to store the new API key: %v", err)
+	}
+	shared.ForgetIdentity(sessionAPIKey(ctx))
+
+	newOwner := ""
+	if actionOwner(ctx) != "" {
+		newOwner = shared.OwnerID(newKey)
+	}
+	moved := scheduler.rekey(client, newClient, actionOwner(ctx), newOwner)
+	result.ScheduledActionsMoved = &moved
+	result.Rotated = true
+	result.Warnings = warnings
+
+	if httpMode, _ := ctx.Value("httpMode").(bool); httpMode {
+		result.Message = "The OAuth key vault now holds the new key; requests use it immediately. Revoke the old key in the Zerops GUI."
+	} else {
+		result.Message = "This session now uses the new key. Update ZEROPS_API_KEY in the MCP client configuration so restarts use it, then revoke the old key in the Zerops GUI."
+	}
+	return result, nil
+}
+
+// readKeyScope reads the user and organizations an API key can access
+func readKeyScope(ctx context.Context, client *sdk.Handler) (*keyScope, error) {
+	resp, err := client.GetUserInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	info, err := resp.Output()
+	if err != nil {
+		return nil, err
+	}
+	return userKeyScope(info), nil
+}
+
+func userKeyScope(info output.UserAuthorize) *keyScope {
+	scope := &keyScope{
+		userID: string(info.Id),
+		email:  info.Email.Native(),
+		orgs:   make(map[string]orgAccess, len(info.ClientUserList)),
+	}
+	for _, clientUser := range info.ClientUserList {
+		scope.orgs[string(clientUser.ClientId)] = orgAccess{
+			name: clientUser.Client.AccountName.Native(),
+			role: string(clientUser.RoleCode),
+		}
+	}
+	return scope
+}
+
+// compareKeyScopes lists what differs between the current and the new key; lost reports
+// whether the new key has less access
+func compareKeyScopes(current, next *keyScope) (*keyScopeDiff, bool) {
+	differences := &keyScopeDiff{
+		SameUser: current.userID == next.userID,
+	}
+	lost := current.userID != next.userID
+	if current.userID != next.userID {
+		differences.CurrentUser = current.email
+		differences.NewUser = next.email
+	}
+
+	var lostOrgs, gainedOrgs, roleChanges []string
+	for _, id := range current.orgIDs() {
+		access := current.orgs[id]
+		nextAccess, ok := next.orgs[id]
+		switch {
+		case !ok:
+			lostOrgs = append(lostOrgs, access.name)
+		case nextAccess.role != access.role:
+			roleChanges = append(roleChanges, fmt.Sprintf("%s: %s -> %s", access.name, access.role, nextAccess.role))
+		}
+	}
+	for _, id := range next.orgIDs() {
+		if _, ok := current.orgs[id]; !ok {
+			gainedOrgs = append(gainedOrgs, next.orgs[id].name)
+		}
+	}
+
+	differences.LostOrganizations = lostOrgs
+	differences.GainedOrganizations = gainedOrgs
+	differences.RoleChanges = roleChanges
+	return differences, lost || len(lostOrgs) > 0 || len(roleChanges) > 0
+}
+
+// verifyOrgAccess checks that the key can list projects in each of its organizations
+func verifyOrgAccess(ctx context.Context, client *sdk.Handler, scope *keyScope) ([]orgAccessCheck, int) {
+	ids := scope.orgIDs()
+	verification := make([]orgAccessCheck, 0, len(ids))
+	failed := 0
+	for _, id := range ids {
+		entry := orgAccessCheck{
+			Organization: scope.orgs[id].name,
+			Role:         scope.orgs[id].role,
+		}
+		resp, err := client.PostProjectSearch(ctx, body.EsFilter{
+			Search: []body.EsSearchItem{
+				{Name: "clientId", Operator: "eq", Value: types.String(id)},
+			},
+			Limit: types.NewIntNull(1),
+		})
+		if err == nil {
+			_, err = resp.Output()
+		}
+		entry.Accessible = err == nil
+		if err != nil {
+			entry.Error = err.Error()
+			failed++
+		}
+		verification = append(verification, entry)
+	}
+	return verification, failed
+}
@@ -9,7 +9,7 @@ import (
 	"sync"
 	"time"
 
-	"github.com/zerops-mcp-basic/internal/handlers/shared"
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
 	"github.com/zeropsio/zerops-go/sdk"
 )
 
@@ -17,20 +17,7 @@ import (
 func RegisterKnowledgeBase() {
 	shared.GlobalRegistry.Register(&shared.ToolDefinition{
 		Name:        "knowledge_base",
-		Description: `Provides comprehensive service import YAML examples and configuration patterns.
-
-QUERY TYPES:
-- "service_import" - Get service import YAML patterns for databases, storage, runtime services
-- "runtime_name" (nodejs, python, go, php) - Get complete zerops.yml examples with dev/prod setups
-- "database_patterns" - Get database and storage service configurations
-- "autoscaling" - Get vertical/horizontal autoscaling configurations
-
-RETURNS:
-- Complete service import YAML with all parameters
-- Runtime-specific zerops.yml with dev/prod setups  
-- Database, cache, storage service patterns
-- Autoscaling and mount configurations
-- Environment variables and secrets patterns`,
+		Description: toolDescription("knowledge_base"),
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
@@ -50,40 +37,15 @@ RETURNS:
 			"required":             []string{"runtime"},
 			"additionalProperties": false,
 		},
-		Handler: handleKnowledgeBase,
+		Annotations: shared.ReadOnly(),
+		Output:      knowledgeDocument{},
+		Handler:     handleKnowledgeBase,
 	})
 
 	// Load platform guide
 	shared.GlobalRegistry.Register(&shared.ToolDefinition{
 		Name:        "load_platform_guide",
-		Description: `Loads comprehensive workflow guides for different development scenarios from GitHub repository.
-
-Fetches the latest guides from https://github.com/zeropsio/zagent-knowledge with 10-minute caching.
-These guides align with the Zerops development methodology and provide detailed step-by-step workflows.
-
-AVAILABLE GUIDES:
-- fresh_project: Complete setup from scratch (databases → services → hello-world → development)
-- existing_service: Most common scenario - start development on existing services
-- add_services: Expand existing projects with new services
-
-EACH GUIDE INCLUDES:
-- The mandatory hello-world pattern for new services
-- Proper dev/stage deployment workflows
-- Environment variable management patterns
-- Service restart and remount procedures
-- Integration testing approaches
-
-WHEN TO USE:
-- After discovery() to determine your development path
-- When starting a completely new project (fresh_project)
-- When working on existing services (existing_service) 
-- When adding new functionality/services (add_services)
-- Need structured workflow guidance
-
-FETCHING:
-- Content fetched from GitHub zagent-knowledge repository
-- 10-minute cache to reduce API calls
-- Falls back to local content if GitHub unavailable`,
+		Description: toolDescription("load_platform_guide"),
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
@@ -98,14 +60,20 @@ FETCHING:
 			"required":             []string{"path_type"},
 			"additionalProperties": false,
 		},
-		Handler: handleLoadPlatformGuide,
+		Annotations: shared.ReadOnly(),
+		Output:      platformGuideResult{},
+		Handler:     handleLoadPlatformGuide,
 	})
 }
 
+// knowledgeDocument is the result of knowledge_base. The keys depend on the topic: runtimes
+// and databases hold examples and notes, the pattern topics hold their own sections.
+type knowledgeDocument map[string]interface{}
+
 func handleKnowledgeBase(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
 	runtime, ok := args["runtime"].(string)
 	if !ok || runtime == "" {
-		return shared.ErrorResponse("Runtime is required"), nil
+		return nil, shared.InvalidArgument("Runtime is required")
 	}
 
 	runtime = strings.ToLower(runtime)
@@ -135,7 +103,7 @@ func handleKnowledgeBase(ctx context.Context, client *sdk.Handler, args map[stri
 	case "redis", "valkey":
 		return getCacheKnowledge(), nil
 	default:
-		return map[string]interface{}{
+		return knowledgeDocument{
 			"runtime": runtime,
 			"message": fmt.Sprintf("Runtime '%s' not directly supported. Use Node.js pattern as reference.", runtime),
 			"pattern": getNodejsKnowledge(),
@@ -143,8 +111,8 @@ func handleKnowledgeBase(ctx context.Context, client *sdk.Handler, args map[stri
 	}
 }
 
-func getNodejsKnowledge() interface{} {
-	return map[string]interface{}{
+func getNodejsKnowledge() knowledgeDocument {
+	return knowledgeDocument{
 		"runtime": "Node.js",
 		"examples": map[string]interface{}{
 			"basic": `services:
@@ -233,8 +201,8 @@ func getNodejsKnowledge() interface{} {
 	}
 }
 
-func getPythonKnowledge() interface{} {
-	return map[string]interface{}{
+func getPythonKnowledge() knowledgeDocument {
+	return knowledgeDocument{
 		"runtime": "Python",
 		"examples": map[string]interface{}{
 			"basic": `services:
@@ -267,8 +235,8 @@ func getPythonKnowledge() interface{} {
 	}
 }
 
-func getGoKnowledge() interface{} {
-	return map[string]interface{}{
+func getGoKnowledge() knowledgeDocument {
+	return knowledgeDocument{
 		"runtime": "Go",
 		"examples": map[string]interface{}{
 			"basic": `services:
@@ -301,8 +269,8 @@ func getGoKnowledge() interface{} {
 	}
 }
 
-func getPHPKnowledge() interface{} {
-	return map[string]interface{}{
+func getPHPKnowledge() knowledgeDocument {
+	return knowledgeDocument{
 		"runtime": "PHP",
 		"examples": map[string]interface{}{
 			"basic": `services:
@@ -338,8 +306,8 @@ func getPHPKnowledge() interface{} {
 	}
 }
 
-func getPostgreSQLKnowledge() interface{} {
-	return map[string]interface{}{
+func getPostgreSQLKnowledge() knowledgeDocument {
+	return knowledgeDocument{
 		"runtime": "PostgreSQL",
 		"examples": map[string]interface{}{
 			"basic": `services:
@@ -365,8 +333,8 @@ func getPostgreSQLKnowledge() interface{} {
 	}
 }
 
-func getMariaDBKnowledge() interface{} {
-	return map[string]interface{}{
+func getMariaDBKnowledge() knowledgeDocument {
+	return knowledgeDocument{
 		"runtime": "MariaDB",
 		"examples": map[string]interface{}{
 			"basic": `services:
@@ -386,8 +354,8 @@ func getMariaDBKnowledge() interface{} {
 	}
 }
 
-func getMongoDBKnowledge() interface{} {
-	return map[string]interface{}{
+func getMongoDBKnowledge() knowledgeDocument {
+	return knowledgeDocument{
 		"runtime": "MongoDB",
 		"examples": map[string]interface{}{
 			"basic": `services:
@@ -407,8 +375,8 @@ func getMongoDBKnowledge() interface{} {
 	}
 }
 
-func getCacheKnowledge() interface{} {
-	return map[string]interface{}{
+func getCacheKnowledge() knowledgeDocument {
+	return knowledgeDocument{
 		"runtime": "Cache (Redis/Valkey)",
 		"examples": map[string]interface{}{
 			"valkey": `services:
@@ -436,7 +404,7 @@ func getCacheKnowledge() interface{} {
 func handleLoadPlatformGuide(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
 	pathType, ok := args["path_type"].(string)
 	if !ok || pathType == "" {
-		return shared.ErrorResponse("Path type is required"), nil
+		return nil, shared.InvalidArgument("Path type is required")
 	}
 
 	switch pathType {
@@ -447,7 +415,7 @@ func handleLoadPlatformGuide(ctx context.Context, client *sdk.Handler, args map[
 	case "add_services":
 		return getAddServicesGuide(), nil
 	default:
-		return shared.ErrorResponse(fmt.Sprintf("Unknown path type '%s'. Available: fresh_project, existing_service, add_services", pathType)), nil
+		return nil, shared.InvalidArgument("Unknown path type '%s'. Available: fresh_project, existing_service, add_services", pathType)
 	}
 }
 
@@ -458,23 +426,44 @@ var (
 )
 
 type cacheEntry struct {
-	content   interface{}
+	content   platformGuideResult
 	timestamp time.Time
 }
 
-func getFreshProjectGuide() interface{} {
+// platformGuideResult is the result of load_platform_guide. Content is the markdown guide
+// from GitHub, or a platformWorkflowGuide when GitHub could not be reached.
+type platformGuideResult struct {
+	Source       string      `json:"source,omitempty"`
+	PathType     string      `json:"path_type,omitempty"`
+	URL          string      `json:"url,omitempty"`
+	Content      interface{} `json:"content,omitempty"`
+	Error        string      `json:"error,omitempty"`
+	Available    []string    `json:"available,omitempty"`
+	CachedAt     string      `json:"cached_at,omitempty"`
+	CacheExpires string      `json:"cache_expires,omitempty"`
+}
+
+// platformWorkflowGuide is the built-in guide used as fallback content
+type platformWorkflowGuide struct {
+	PathType string   `json:"path_type,omitempty"`
+	Title    string   `json:"title,omitempty"`
+	Workflow []string `json:"workflow,omitempty"`
+	Error    string   `json:"error,omitempty"`
+}
+
+func getFreshProjectGuide() platformGuideResult {
 	return fetchGuideFromGitHub("fresh_project")
 }
 
-func getExistingServiceGuide() interface{} {
+func getExistingServiceGuide() platformGuideResult {
 	return fetchGuideFromGitHub("existing_service")
 }
 
-func getAddServicesGuide() interface{} {
+func getAddServicesGuide() platformGuideResult {
 	return fetchGuideFromGitHub("add_services")
 }
 
-func fetchGuideFromGitHub(pathType string) interface{} {
+func fetchGuideFromGitHub(pathType string) platformGuideResult {
 	cacheMutex.RLock()
 	if entry, exists := guideCache[pathType]; exists {
 		if time.Since(entry.timestamp) < 10*time.Minute {
@@ -495,32 +484,32 @@ func fetchGuideFromGitHub(pathType string) interface{} {
 	case "add_services":
 		fileURL = fmt.Sprintf("%s/add_services.md", baseURL)
 	default:
-		return map[string]interface{}{
-			"error": "Unknown path type",
-			"available": []string{"fresh_project", "existing_service", "add_services"},
+		return platformGuideResult{
+			Error:     "Unknown path type",
+			Available: []string{"fresh_project", "existing_service", "add_services"},
 		}
 	}
 
 	// Fetch actual content from GitHub
 	content, err := fetchFromURL(fileURL)
-	var result interface{}
-	
+	var result platformGuideResult
+
 	if err != nil {
 		// Fallback to local content on error
-		result = map[string]interface{}{
-			"source": "fallback",
-			"error":  fmt.Sprintf("Failed to fetch from GitHub: %v", err),
-			"content": getFallbackGuide(pathType),
+		result = platformGuideResult{
+			Source:  "fallback",
+			Error:   fmt.Sprintf("Failed to fetch from GitHub: %v", err),
+			Content: getFallbackGuide(pathType),
 		}
 	} else {
 		// Return the fetched markdown content
-		result = map[string]interface{}{
-			"source":    "github",
-			"path_type": pathType,
-			"url":       fileURL,
-			"content":   content,
-			"cached_at": time.Now().Format("2006-01-02 15:04:05"),
-			"cache_expires": time.Now().Add(10 * time.Minute).Format("2006-01-02 15:04:05"),
+		result = platformGuideResult{
+			Source:       "github",
+			PathType:     pathType,
+			URL:          fileURL,
+			Content:      content,
+			CachedAt:     time.Now().Format("2006-01-02 15:04:05"),
+			CacheExpires: time.Now().Add(10 * time.Minute).Format("2006-01-02 15:04:05"),
 		}
 	}
 
@@ -558,66 +547,88 @@ func fetchFromURL(url string) (string, error) {
 	return string(body), nil
 }
 
-func getFallbackGuide(pathType string) interface{} {
-	switch pathType {
-	case "fresh_project":
-		return map[string]interface{}{
-			"path_type": "fresh_project",
-			"title":     "Complete Guide: Starting a Fresh Project",
-			"workflow": []string{
-				"1. discovery() - Check current project state (should be empty)",
-				"2. get_service_types() - See available service types",
-				"3. knowledge_base('nodejs') - Get complete YAML examples",
-				"4. import_services(yaml: '...') - Create your services",
-				"5. discovery() - Verify services were created",
-				"6. enable_preview_subdomain(service_id: '...') - Enable public access",
-				"7. get_process_status(process_id: '...') - Monitor subdomain setup",
-				"8. discovery() - Get final subdomain URLs",
-				"9. set_project_env() / set_service_env() - Configure environment",
-				"10. get_service_logs() - Monitor application startup",
-			},
-		}
-	case "existing_service":
-		return map[string]interface{}{
-			"path_type": "existing_service",
-			"title":     "Guide: Working with Existing Services",
-			"workflow": []string{
-				"1. discovery() - See all existing services and their status",
-				"2. get_service_logs(service_id: '...') - Check current service health",
-				"3. set_service_env() / set_project_env() - Update configuration",
-				"4. restart_service(service_id: '...') - Apply configuration changes",
-				"5. get_process_status(process_id: '...') - Monitor restart progress",
-				"6. scale_service(service_id: '...') - Adjust resources if needed",
-				"7. remount_service(service_name: '...') - Fix SSHFS issues if needed",
-				"8. discovery() - Verify final state",
-			},
-		}
-	case "add_services":
-		return map[string]interface{}{
-			"path_type": "add_services",
-			"title":     "Guide: Adding Services to Existing Project",
-			"workflow": []string{
-				"1. discovery() - See current project services",
-				"2. get_service_types() - Check available service types",
-				"3. knowledge_base('database_type') - Get examples for new services",
-				"4. import_services(yaml: '...') - Add new services to project",
-				"5. discovery() - Verify new services were created",
-				"6. set_project_env() - Add shared environment variables",
-				"7. restart_service() - Restart existing services to use new config",
-				"8. enable_preview_subdomain() - Enable access for web services",
-				"9. get_running_processes() - Monitor all operations",
-				"10. discovery() - Get final project state",
-			},
-		}
-	default:
-		return map[string]interface{}{
-			"error": "Unknown guide type",
+// platformWorkflow is a step-by-step guide; load_platform_guide falls back to it and the
+// MCP prompts are built from it
+type platformWorkflow struct {
+	title string
+	steps []string
+}
+
+var platformWorkflows = map[string]platformWorkflow{
+	"fresh_project": {
+		title: "Complete Guide: Starting a Fresh Project",
+		steps: []string{
+			"1. discovery() - Check current project state (should be empty)",
+			"2. get_service_types() - See available service types",
+			"3. knowledge_base('nodejs') - Get complete YAML examples",
+			"4. import_services(yaml: '...') - Create your services",
+			"5. discovery() - Verify services were created",
+			"6. enable_preview_subdomain(service_id: '...') - Enable public access",
+			"7. get_process_status(process_id: '...') - Monitor subdomain setup",
+			"8. discovery() - Get final subdomain URLs",
+			"9. set_project_env() / set_service_env() - Configure environment",
+			"10. get_service_logs() - Monitor application startup",
+		},
+	},
+	"existing_service": {
+		title: "Guide: Working with Existing Services",
+		steps: []string{
+			"1. discovery() - See all existing services and their status",
+			"2. get_service_logs(service_id: '...') - Check current service health",
+			"3. set_service_env() / set_project_env() - Update configuration",
+			"4. restart_service(service_id: '...') - Apply configuration changes",
+			"5. get_process_status(process_id: '...') - Monitor restart progress",
+			"6. scale_service(service_id: '...') - Adjust resources if needed",
+			"7. remount_service(service_name: '...') - Fix SSHFS issues if needed",
+			"8. discovery() - Verify final state",
+		},
+	},
+	"add_services": {
+		title: "Guide: Adding Services to Existing Project",
+		steps: []string{
+			"1. discovery() - See current project services",
+			"2. get_service_types() - Check available service types",
+			"3. knowledge_base('database_type') - Get examples for new services",
+			"4. import_services(yaml: '...') - Add new services to project",
+			"5. discovery() - Verify new services were created",
+			"6. set_project_env() - Add shared environment variables",
+			"7. restart_service() - Restart existing services to use new config",
+			"8. enable_preview_subdomain() - Enable access for web services",
+			"9. get_running_processes() - Monitor all operations",
+			"10. discovery() - Get final project state",
+		},
+	},
+	"debug_deploy": {
+		title: "Guide: Debugging a Failed Deploy",
+		steps: []string{
+			"1. discovery(service_id: '...') - Check service status and the latest app version",
+			"2. troubleshoot_service(service_id: '...') - Get ranked likely causes with the next call for each",
+			"3. get_service_logs(service_id: '...', show_build_logs: true) - Read the output of the latest build",
+			"4. get_service_logs(service_id: '...', minimum_severity: 'error') - Check runtime errors after the deploy",
+			"5. deploy_validate(zerops_yml: '...') - Validate zerops.yml before pushing a fix",
+			"6. list_app_versions(service_id: '...') - Compare with the last working version",
+			"7. rollback_deployment(service_id: '...') - Return to the last working version while fixing",
+			"8. wait_for_process(process_id: '...') - Wait for the redeploy or rollback",
+		},
+	},
+}
+
+func getFallbackGuide(pathType string) platformWorkflowGuide {
+	workflow, ok := platformWorkflows[pathType]
+	if !ok {
+		return platformWorkflowGuide{
+			Error: "Unknown guide type",
 		}
 	}
+	return platformWorkflowGuide{
+		PathType: pathType,
+		Title:    workflow.title,
+		Workflow: workflow.steps,
+	}
 }
 
-func getServiceImportPatterns() interface{} {
-	return map[string]interface{}{
+func getServiceImportPatterns() knowledgeDocument {
+	return knowledgeDocument{
 		"title": "Complete Service Import YAML Patterns",
 		"description": "Examples use current versions from get_service_types. Always verify with get_service_types for latest versions.",
 		"patterns": map[string]interface{}{
@@ -742,8 +753,8 @@ services:
 	}
 }
 
-func getDatabasePatterns() interface{} {
-	return map[string]interface{}{
+func getDatabasePatterns() knowledgeDocument {
+	return knowledgeDocument{
 		"title": "Database and Storage Service Patterns",
 		"description": "Complete configuration examples for databases, caches, and storage services",
 		"databases": map[string]interface{}{
@@ -889,8 +900,8 @@ services:
 	}
 }
 
-func getAutoscalingPatterns() interface{} {
-	return map[string]interface{}{
+func getAutoscalingPatterns() knowledgeDocument {
+	return knowledgeDocument{
 		"title": "Autoscaling Configuration Patterns",
 		"description": "Vertical and horizontal autoscaling examples for services",
 		"vertical_autoscaling": map[string]interface{}{
@@ -0,0 +1,204 @@
+package tools
+
+import (
+	"context"
+	"time"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/dto/input/path"
+	"github.com/zeropsio/zerops-go/dto/output"
+	"github.com/zeropsio/zerops-go/sdk"
+	"github.com/zeropsio/zerops-go/types/uuid"
+)
+
+// RegisterLifecycle registers the start_project, stop_project, start_service and stop_service tools
+func RegisterLifecycle() {
+	projectIDProperty := map[string]interface{}{
+		"type":        "string",
+		"description": "OPTIONAL: Project ID from discovery tool (default: $projectId)",
+		"pattern":     "^[A-Za-z0-9_-]+$",
+	}
+	serviceIDProperty := map[string]interface{}{
+		"type":        "string",
+		"description": "REQUIRED: Service ID from discovery tool",
+		"pattern":     "^[A-Za-z0-9_-]+$",
+	}
+
+	for _, action := range []string{"start_project", "stop_project"} {
+		shared.GlobalRegistry.Register(&shared.ToolDefinition{
+			Name:        action,
+			Description: toolDescription(action),
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"project_id": projectIDProperty,
+				},
+				"additionalProperties": false,
+			},
+			Annotations: shared.Mutating(action == "stop_project", true),
+			Output:      projectLifecycleResult{},
+			Handler:     handleProjectLifecycle(action),
+		})
+	}
+
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "start_service",
+		Description: toolDescription("start_service"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"service_id": serviceIDProperty,
+			},
+			"required":             []string{"service_id"},
+			"additionalProperties": false,
+		},
+		Annotations: shared.Mutating(false, true),
+		Output:      serviceLifecycleResult{},
+		Handler:     handleServiceLifecycle("start_service"),
+	})
+
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "stop_service",
+		Description: toolDescription("stop_service"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"service_id":           serviceIDProperty,
+				"expected_last_update": expectedLastUpdateProperty(),
+			},
+			"required":             []string{"service_id"},
+			"additionalProperties": false,
+		},
+		Annotations: shared.Mutating(true, true),
+		Output:      serviceLifecycleResult{},
+		Handler:     handleServiceLifecycle("stop_service"),
+	})
+}
+
+// projectLifecycleResult is the result of start_project and stop_project
+type projectLifecycleResult struct {
+	ProcessID  string    `json:"process_id"`
+	ProjectID  string    `json:"project_id"`
+	Status     string    `json:"status"`
+	ActionName string    `json:"action_name"`
+	Created    time.Time `json:"created"`
+	Message    string    `json:"message"`
+	shared.Suggestions
+}
+
+// serviceLifecycleResult is the result of start_service and stop_service
+type serviceLifecycleResult struct {
+	ProcessID  string    `json:"process_id"`
+	ServiceID  string    `json:"service_id"`
+	Status     string    `json:"status"`
+	ActionName string    `json:"action_name"`
+	Created    time.Time `json:"created"`
+	Message    string    `json:"message"`
+	shared.Suggestions
+}
+
+// handleProjectLifecycle returns the handler of start_project or stop_project
+func handleProjectLifecycle(action string) shared.ToolFunc {
+	return func(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+		if client == nil {
+			return nil, shared.ErrNoClient
+		}
+
+		projectID, err := resolveProjectID(ctx, client, args)
+		if err != nil {
+			return nil, err
+		}
+
+		recordSnapshot(ctx, client, projectID, action, projectID)
+
+		projectPath := path.ProjectId{Id: uuid.ProjectId(projectID)}
+		var process output.Process
+		verb := "start"
+		if action == "stop_project" {
+			verb = "stop"
+			resp, callErr := client.PutProjectStop(ctx, projectPath)
+			if callErr == nil {
+				process, callErr = resp.Output()
+			}
+			err = callErr
+		} else {
+			resp, callErr := client.PutProjectStart(ctx, projectPath)
+			if callErr == nil {
+				process, callErr = resp.Output()
+			}
+			err = callErr
+		}
+		if err != nil {
+			return nil, shared.WrapAPIError(err, "Failed to "+verb+" project")
+		}
+
+		result := projectLifecycleResult{
+			ProcessID:  string(process.Id),
+			ProjectID:  projectID,
+			Status:     string(process.Status),
+			ActionName: process.ActionName.Native(),
+			Created:    process.Created.Native(),
+			Message:    "Project " + verb + " initiated. Use 'wait_for_process' or 'get_process_status' to monitor progress.",
+		}
+		result.SuggestNext(shared.WaitForProcess(string(process.Id), "Wait until the project "+verb+" completes"))
+		return result, nil
+	}
+}
+
+// handleServiceLifecycle returns the handler of start_service or stop_service
+func handleServiceLifecycle(action string) shared.ToolFunc {
+	return func(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+		if client == nil {
+			return nil, shared.ErrNoClient
+		}
+
+		serviceID, ok := args["service_id"].(string)
+		if !ok || serviceID == "" {
+			return nil, shared.InvalidArgument("Service ID is required")
+		}
+
+		verb := "start"
+		if action == "stop_service" {
+			verb = "stop"
+			if err := checkServiceUnchanged(ctx, client, args, serviceID); err != nil {
+				return nil, err
+			}
+		}
+
+		if projectID, err := serviceProjectID(ctx, client, serviceID); err == nil {
+			recordSnapshot(ctx, client, projectID, action, serviceID)
+		}
+
+		servicePath := path.ServiceStackId{Id: uuid.ServiceStackId(serviceID)}
+		var process output.Process
+		var err error
+		if action == "stop_service" {
+			resp, callErr := client.PutServiceStackStop(ctx, servicePath)
+			if callErr == nil {
+				process, callErr = resp.Output()
+			}
+			err = callErr
+		} else {
+			resp, callErr := client.PutServiceStackStart(ctx, servicePath)
+			if callErr == nil {
+				process, callErr = resp.Output()
+			}
+			err = callErr
+		}
+		if err != nil {
+			return nil, shared.WrapAPIError(err, "Failed to "+verb+" service")
+		}
+		forgetServiceStamp(ctx, serviceID)
+
+		result := serviceLifecycleResult{
+			ProcessID:  string(process.Id),
+			ServiceID:  serviceID,
+			Status:     string(process.Status),
+			ActionName: process.ActionName.Native(),
+			Created:    process.Created.Native(),
+			Message:    "Service " + verb + " initiated. Use 'wait_for_process' or 'get_process_status' to monitor progress.",
+		}
+		result.SuggestNext(shared.WaitForProcess(string(process.Id), "Wait until the service "+verb+" completes"))
+		return result, nil
+	}
+}
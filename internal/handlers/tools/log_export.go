@@ -0,0 +1,476 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/dto/input/path"
+	"github.com/zeropsio/zerops-go/dto/output"
+	"github.com/zeropsio/zerops-go/sdk"
+	"github.com/zeropsio/zerops-go/types/uuid"
+)
+
+const (
+	// logExportPageSize is how many entries one log API request returns
+	logExportPageSize = 1000
+	// defaultLogExportEntries and maxLogExportEntries bound the exported window
+	defaultLogExportEntries = 5000
+	maxLogExportEntries     = 50000
+	// objectStorageRegion is the signing region Zerops object storage expects
+	objectStorageRegion = "us-east-1"
+)
+
+// RegisterLogExport registers the export_service_logs tool
+func RegisterLogExport() {
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "export_service_logs",
+		Description: toolDescription("export_service_logs"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"service_id": map[string]interface{}{
+					"type":        "string",
+					"description": "REQUIRED: The service ID to export logs from",
+					"minLength":   1,
+				},
+				"max_entries": map[string]interface{}{
+					"type":        "integer",
+					"description": fmt.Sprintf("OPTIONAL: Most log entries to export (default: %d)", defaultLogExportEntries),
+					"minimum":     1,
+					"maximum":     maxLogExportEntries,
+				},
+				"minimum_severity": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Minimum severity level",
+					"enum":        []string{"EMERGENCY", "ALERT", "CRITICAL", "ERROR", "WARNING", "NOTICE", "INFORMATIONAL", "DEBUG"},
+				},
+				"message_type": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Type of messages (default: APPLICATION)",
+					"enum":        []string{"APPLICATION", "WEBSERVER"},
+				},
+				"format": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: jsonl writes one JSON entry per line, text writes 'timestamp severity hostname message' (default: jsonl)",
+					"enum":        []string{"jsonl", "text"},
+				},
+				"destination": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Where to write the export (default: file in stdio mode, object_storage in HTTP mode)",
+					"enum":        []string{"file", "object_storage"},
+				},
+				"path": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: File path for destination file (default: zerops-logs-<hostname>-<time>.<ext> in the working directory)",
+				},
+				"storage_service_id": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Object storage service to upload to (default: the only object storage service in the project)",
+				},
+				"object_key": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Object key for destination object_storage (default: logs/<hostname>/<time>.<ext>)",
+				},
+			},
+			"required":             []string{"service_id"},
+			"additionalProperties": false,
+		},
+		Annotations: shared.Mutating(false, false),
+		Output:      exportServiceLogsResult{},
+		Handler:     handleExportServiceLogs,
+	})
+}
+
+// exportServiceLogsResult is the result of export_service_logs
+type exportServiceLogsResult struct {
+	ServiceID      string `json:"service_id"`
+	ServiceName    string `json:"service_name"`
+	Entries        int    `json:"entries"`
+	Pages          int    `json:"pages"`
+	Complete       bool   `json:"complete"`
+	Format         string `json:"format"`
+	Bytes          int    `json:"bytes"`
+	Destination    string `json:"destination"`
+	From           string `json:"from,omitempty"`
+	Till           string `json:"till,omitempty"`
+	Note           string `json:"note,omitempty"`
+	Path           string `json:"path,omitempty"`
+	StorageService string `json:"storage_service,omitempty"`
+	Bucket         string `json:"bucket,omitempty"`
+	ObjectKey      string `json:"object_key,omitempty"`
+	URL            string `json:"url,omitempty"`
+}
+
+func handleExportServiceLogs(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+
+	serviceID, _ := args["service_id"].(string)
+	if serviceID == "" {
+		return nil, shared.InvalidArgument("service_id is required")
+	}
+	maxEntries := defaultLogExportEntries
+	if value, ok := args["max_entries"].(float64); ok {
+		maxEntries = int(value)
+	}
+	if maxEntries < 1 || maxEntries > maxLogExportEntries {
+		return nil, shared.InvalidArgument("max_entries must be between 1 and %d", maxLogExportEntries)
+	}
+	minSeverity, _ := args["minimum_severity"].(string)
+	messageType, _ := args["message_type"].(string)
+	if messageType == "" {
+		messageType = "APPLICATION"
+	}
+	format, _ := args["format"].(string)
+	if format == "" {
+		format = "jsonl"
+	}
+	if format != "jsonl" && format != "text" {
+		return nil, shared.InvalidArgument("format must be jsonl or text")
+	}
+
+	httpMode, _ := ctx.Value("httpMode").(bool)
+	destination, _ := args["destination"].(string)
+	if destination == "" {
+		destination = "file"
+		if httpMode {
+			destination = "object_storage"
+		}
+	}
+	switch destination {
+	case "file":
+		if httpMode {
+			return nil, shared.InvalidArgument("destination file is not available in HTTP mode: the server's filesystem is not yours. Use destination object_storage.")
+		}
+	case "object_storage":
+	default:
+		return nil, shared.InvalidArgument("destination must be file or object_storage")
+	}
+
+	serviceResp, err := client.GetServiceStack(ctx, path.ServiceStackId{Id: uuid.ServiceStackId(serviceID)})
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to get service")
+	}
+	service, err := serviceResp.Output()
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to get service")
+	}
+	hostname := service.Name.Native()
+
+	// Resolve the bucket before reading logs so a missing bucket fails fast
+	var target *objectStorageTarget
+	if destination == "object_storage" {
+		storageServiceID, _ := args["storage_service_id"].(string)
+		target, err = findObjectStorage(ctx, client, string(service.ProjectId), storageServiceID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	q := logQuery{
+		ServiceID:   serviceID,
+		Limit:       logExportPageSize,
+		Facility:    getFacilityCode(messageType),
+		MinSeverity: minSeverity,
+	}
+	logs, pages, complete, err := collectLogs(ctx, client, service.ProjectId, q, maxEntries)
+	if err != nil {
+		return nil, err
+	}
+
+	data, contentType := encodeLogExport(logs, format)
+	extension := map[string]string{"jsonl": "jsonl", "text": "log"}[format]
+	stamp := time.Now().UTC().Format("20060102-150405")
+
+	result := exportServiceLogsResult{
+		ServiceID:   serviceID,
+		ServiceName: hostname,
+		Entries:     len(logs),
+		Pages:       pages,
+		Complete:    complete,
+		Format:      format,
+		Bytes:       len(data),
+		Destination: destination,
+	}
+	if len(logs) > 0 {
+		result.From = logs[0].Timestamp
+		result.Till = logs[len(logs)-1].Timestamp
+	}
+	if !complete {
+		result.Note = fmt.Sprintf("Stopped at max_entries (%d); older entries exist. Raise max_entries (up to %d) for a longer window.", maxEntries, maxLogExportEntries)
+	}
+
+	if destination == "file" {
+		filePath, _ := args["path"].(string)
+		if filePath == "" {
+			filePath = fmt.Sprintf("zerops-logs-%s-%s.%s", hostname, stamp, extension)
+		}
+		written, err := writeLocalFile(ctx, filePath, data)
+		if err != nil {
+			return nil, err
+		}
+		result.Path = written
+		return result, nil
+	}
+
+	objectKey, _ := args["object_key"].(string)
+	if objectKey == "" {
+		objectKey = fmt.Sprintf("logs/%s/%s.%s", hostname, stamp, extension)
+	}
+	objectURL, err := target.put(ctx, objectKey, data, contentType)
+	if err != nil {
+		return nil, err
+	}
+	result.StorageService = target.hostname
+	result.Bucket = target.bucket
+	result.ObjectKey = objectKey
+	result.URL = objectURL
+	return result, nil
+}
+
+// collectLogs pages backwards through the log backend until maxEntries are read or no older
+// entries come back. complete is false when maxEntries cut the window short. The result is
+// ordered oldest first.
+func collectLogs(ctx context.Context, client *sdk.Handler, projectID uuid.ProjectId, q logQuery, maxEntries int) ([]LogData, int, bool, error) {
+	seen := make(map[string]bool)
+	var logs []LogData
+	pages := 0
+	complete := true
+
+	for len(logs) < maxEntries {
+		q.Limit = logExportPageSize
+		if remaining := maxEntries - len(logs); remaining < q.Limit {
+			q.Limit = remaining
+		}
+		page, err := fetchLogs(ctx, client, projectID, q)
+		if err != nil {
+			return nil, pages, false, err
+		}
+		pages++
+
+		added := 0
+		oldest := ""
+		oldestTimestamp := ""
+		for _, entry := range page {
+			key := logEntryKey(entry)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			logs = append(logs, entry)
+			added++
+			if oldest == "" || entry.Timestamp < oldestTimestamp {
+				oldest, oldestTimestamp = entry.Id, entry.Timestamp
+			}
+		}
+		shared.ReportProgress(ctx, float64(len(logs)), float64(maxEntries), fmt.Sprintf("%d log entries read", len(logs)))
+
+		// A short page, a page without new entries or entries without IDs end the window
+		if len(page) < q.Limit || added == 0 || oldest == "" {
+			break
+		}
+		if len(logs) >= maxEntries {
+			complete = false
+			break
+		}
+		q.Till = oldest
+	}
+
+	sort.SliceStable(logs, func(i, j int) bool { return logs[i].Timestamp < logs[j].Timestamp })
+	return logs, pages, complete, nil
+}
+
+// encodeLogExport renders logs as JSON lines or plain text and returns the content type
+func encodeLogExport(logs []LogData, format string) ([]byte, string) {
+	var buf bytes.Buffer
+	if format == "text" {
+		for _, entry := range logs {
+			fmt.Fprintf(&buf, "%s %s %s %s\n", entry.Timestamp, entry.SeverityLabel, entry.Hostname, entry.Message)
+		}
+		return buf.Bytes(), "text/plain; charset=utf-8"
+	}
+
+	encoder := json.NewEncoder(&buf)
+	for _, entry := range logs {
+		_ = encoder.Encode(entry)
+	}
+	return buf.Bytes(), "application/x-ndjson"
+}
+
+// writeLocalFile writes data to filePath inside the client's roots and returns the absolute path
+func writeLocalFile(ctx context.Context, filePath string, data []byte) (string, error) {
+	absolute, err := filepath.Abs(filePath)
+	if err != nil {
+		return "", shared.InvalidArgument("Invalid path '%s'", filePath)
+	}
+	if err := shared.CheckPathInRoots(ctx, absolute); err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(absolute), 0o755); err != nil {
+		return "", shared.NewToolError(shared.ErrInvalidArgument, "Failed to create directory for '%s': %v", absolute, err)
+	}
+	if err := os.WriteFile(absolute, data, 0o600); err != nil {
+		return "", shared.NewToolError(shared.ErrInvalidArgument, "Failed to write '%s': %v", absolute, err)
+	}
+	return absolute, nil
+}
+
+// objectStorageTarget is a bucket of a Zerops object storage service with its S3 credentials
+type objectStorageTarget struct {
+	hostname        string
+	apiURL          string
+	bucket          string
+	accessKeyID     string
+	secretAccessKey string
+}
+
+// findObjectStorage picks the object storage service to upload to and reads its credentials
+// from the service env (apiUrl, bucketName, accessKeyId, secretAccessKey)
+func findObjectStorage(ctx context.Context, client *sdk.Handler, projectID, storageServiceID string) (*objectStorageTarget, error) {
+	project, err := searchProject(ctx, client, projectID)
+	if err != nil {
+		return nil, err
+	}
+	services, err := searchProjectServices(ctx, client, project)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []output.EsServiceStack
+	for _, service := range services {
+		if storageServiceID != "" && string(service.Id) != storageServiceID {
+			continue
+		}
+		if isObjectStorage(service) {
+			candidates = append(candidates, service)
+		}
+	}
+	switch {
+	case len(candidates) == 0 && storageServiceID != "":
+		return nil, shared.NotFound("No object storage service '%s' in project %s", storageServiceID, projectID)
+	case len(candidates) == 0:
+		return nil, shared.NotFound("Project %s has no object storage service. Import one (type: object-storage) or use destination file.", projectID)
+	case len(candidates) > 1:
+		names := make([]string, 0, len(candidates))
+		for _, service := range candidates {
+			names = append(names, fmt.Sprintf("%s (%s)", service.Name.Native(), service.Id))
+		}
+		return nil, shared.InvalidArgument("Project has several object storage services, pick one with storage_service_id: %s", strings.Join(names, ", "))
+	}
+
+	storage := candidates[0]
+	envResp, err := client.GetServiceStackEnv(ctx, path.ServiceStackId{Id: storage.Id})
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to read object storage credentials")
+	}
+	envOutput, err := envResp.Output()
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to read object storage credentials")
+	}
+	env := make(map[string]string, len(envOutput.Items))
+	for _, item := range envOutput.Items {
+		env[item.Key.Native()] = item.Content.Native()
+	}
+
+	target := &objectStorageTarget{
+		hostname:        storage.Name.Native(),
+		apiURL:          strings.TrimRight(env["apiUrl"], "/"),
+		bucket:          env["bucketName"],
+		accessKeyID:     env["accessKeyId"],
+		secretAccessKey: env["secretAccessKey"],
+	}
+	if target.apiURL == "" || target.bucket == "" || target.accessKeyID == "" || target.secretAccessKey == "" {
+		return nil, shared.NewToolError(shared.ErrForbidden, "Object storage '%s' credentials are not readable with this API key (apiUrl, bucketName, accessKeyId, secretAccessKey)", target.hostname)
+	}
+	return target, nil
+}
+
+// isObjectStorage reports whether a service is an object storage service
+func isObjectStorage(service output.EsServiceStack) bool {
+	typeName := strings.ToLower(service.ServiceStackTypeInfo.ServiceStackTypeVersionName.Native())
+	return strings.HasPrefix(typeName, "object_storage") || strings.HasPrefix(typeName, "object-storage")
+}
+
+// put uploads data to the bucket with an AWS Signature Version 4 signed request and returns the object URL
+func (t *objectStorageTarget) put(ctx context.Context, key string, data []byte, contentType string) (string, error) {
+	escapedKey := strings.ReplaceAll(url.PathEscape(key), "%2F", "/")
+	objectURL := fmt.Sprintf("%s/%s/%s", t.apiURL, t.bucket, escapedKey)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, objectURL, bytes.NewReader(data))
+	if err != nil {
+		return "", shared.InvalidArgument("Invalid object key '%s'", key)
+	}
+	req.Header.Set("Content-Type", contentType)
+	t.sign(req, data, time.Now().UTC())
+
+	resp, err := (&http.Client{Timeout: 5 * time.Minute}).Do(req)
+	if err != nil {
+		return "", shared.NewToolError(shared.ErrAPIUnavailable, "Upload to object storage '%s' failed: %v", t.hostname, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return "", shared.NewToolError(shared.ErrAPIUnavailable, "Upload to object storage '%s' failed: %s: %s", t.hostname, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return objectURL, nil
+}
+
+// sign adds the AWS Signature Version 4 headers S3 compatible storage requires
+func (t *objectStorageTarget) sign(req *http.Request, payload []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+	payloadHash := sha256Hex(payload)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := "content-type;host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := fmt.Sprintf("content-type:%s\nhost:%s\nx-amz-content-sha256:%s\nx-amz-date:%s\n",
+		req.Header.Get("Content-Type"), req.URL.Host, payloadHash, amzDate)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := fmt.Sprintf("%s/%s/s3/aws4_request", day, objectStorageRegion)
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+t.secretAccessKey), day)
+	signingKey = hmacSHA256(signingKey, objectStorageRegion)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		t.accessKeyID, scope, signedHeaders, signature))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
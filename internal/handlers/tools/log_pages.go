@@ -0,0 +1,136 @@
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/sdk"
+	"github.com/zeropsio/zerops-go/types/uuid"
+)
+
+// logChunkSize is the most log lines get_service_logs returns at once; larger requests
+// are split into chunks linked by continuation tokens
+const logChunkSize = 200
+
+// maxServiceLogs is the most lines one get_service_logs request can page through
+const maxServiceLogs = 10000
+
+// logContinuation is the state behind a continuation token: the query of the first call,
+// the oldest entry delivered so far and how many lines of the requested limit remain
+type logContinuation struct {
+	ServiceID    string `json:"s"`
+	LogServiceID string `json:"l"`
+	Facility     int    `json:"f"`
+	MinSeverity  string `json:"m,omitempty"`
+	Build        bool   `json:"b,omitempty"`
+	Till         string `json:"t"`
+	Delivered    int    `json:"d"`
+	Remaining    int    `json:"r"`
+}
+
+func encodeLogContinuation(c logContinuation) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeLogContinuation reads a token and checks it belongs to serviceID
+func decodeLogContinuation(token, serviceID string) (*logContinuation, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, shared.InvalidArgument("Invalid continuation_token; pass the token of the previous get_service_logs result unchanged")
+	}
+	var c logContinuation
+	if err := json.Unmarshal(data, &c); err != nil || c.Till == "" || c.Remaining <= 0 {
+		return nil, shared.InvalidArgument("Invalid continuation_token; pass the token of the previous get_service_logs result unchanged")
+	}
+	if c.ServiceID != serviceID {
+		return nil, shared.InvalidArgument("continuation_token belongs to service %s, not %s", c.ServiceID, serviceID)
+	}
+	return &c, nil
+}
+
+// logChunk is one page of a chunked log read
+type logChunk struct {
+	logs []LogData
+	// more is true when the log backend has older entries matching the query
+	more bool
+	// next is set when more entries are available and the requested limit is not reached yet
+	next *logContinuation
+}
+
+// fetchLogChunk reads the newest chunk of entries older than state.Till, asking for one
+// entry more than it returns to learn whether older entries exist
+func fetchLogChunk(ctx context.Context, client *sdk.Handler, projectID uuid.ProjectId, state logContinuation) (*logChunk, error) {
+	size := min(state.Remaining, logChunkSize)
+	logs, err := fetchLogs(ctx, client, projectID, logQuery{
+		ServiceID:   state.LogServiceID,
+		Limit:       size + 1,
+		Facility:    state.Facility,
+		MinSeverity: state.MinSeverity,
+		Till:        state.Till,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	chunk := &logChunk{logs: logs}
+	if len(logs) > size {
+		chunk.logs = logs[:size]
+		chunk.more = true
+	}
+	oldest, oldestTimestamp := "", ""
+	for _, entry := range chunk.logs {
+		if oldest == "" || entry.Timestamp < oldestTimestamp {
+			oldest, oldestTimestamp = entry.Id, entry.Timestamp
+		}
+	}
+	// Entries without IDs cannot be paged past
+	if chunk.more && oldest != "" && state.Remaining > len(chunk.logs) {
+		next := state
+		next.Till = oldest
+		next.Delivered += len(chunk.logs)
+		next.Remaining -= len(chunk.logs)
+		chunk.next = &next
+	}
+	return chunk, nil
+}
+
+// logPages is the pagination block of a chunked get_service_logs result
+type logPages struct {
+	ChunkSize         int    `json:"chunk_size"`
+	Delivered         int    `json:"delivered"`
+	Remaining         int    `json:"remaining"`
+	MoreAvailable     bool   `json:"more_available"`
+	AvailableAtLeast  int    `json:"available_at_least"`
+	ContinuationToken string `json:"continuation_token,omitempty"`
+	Message           string `json:"message,omitempty"`
+}
+
+// logPagination describes where a chunk sits in the requested window. The log backend
+// reports no totals, so available_at_least is a lower bound: the lines delivered so far
+// plus one when the backend returned an extra, older entry.
+func logPagination(state logContinuation, chunk *logChunk) *logPages {
+	delivered := state.Delivered + len(chunk.logs)
+	availableAtLeast := delivered
+	if chunk.more {
+		availableAtLeast++
+	}
+	remaining := state.Remaining - len(chunk.logs)
+	if chunk.next == nil {
+		remaining = 0
+	}
+	pagination := &logPages{
+		ChunkSize:        logChunkSize,
+		Delivered:        delivered,
+		Remaining:        remaining,
+		MoreAvailable:    chunk.more,
+		AvailableAtLeast: availableAtLeast,
+	}
+	if chunk.next != nil {
+		pagination.ContinuationToken = encodeLogContinuation(*chunk.next)
+		pagination.Message = "Older entries follow. Call get_service_logs with the same service_id and this continuation_token for the next chunk."
+	}
+	return pagination
+}
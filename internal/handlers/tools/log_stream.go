@@ -0,0 +1,131 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/sdk"
+	"github.com/zeropsio/zerops-go/types/uuid"
+)
+
+const (
+	// defaultFollowDuration is how long follow=true streams when follow_duration is not set
+	defaultFollowDuration = 60 * time.Second
+	// maxFollowDuration caps follow_duration
+	maxFollowDuration = 10 * time.Minute
+	// followPollInterval is how often the log backend is polled for new lines
+	followPollInterval = 2 * time.Second
+	// followPollLimit is how many of the newest lines each poll reads
+	followPollLimit = 200
+	// maxFollowEntries is how many lines the final result keeps
+	maxFollowEntries = 1000
+)
+
+// logFollow is the outcome of a followed log read
+type logFollow struct {
+	logs       []LogData
+	streamed   int
+	truncated  bool
+	stopReason string
+}
+
+// followLogs polls the log backend until ctx is cancelled or duration passes. New lines
+// are sent as MCP log messages (logger "logs") in the format of the call, and each poll
+// reports progress. initial are the lines already read as returned by the backend; they
+// are not sent again and are normalized like the streamed ones.
+func followLogs(ctx context.Context, client *sdk.Handler, projectID uuid.ProjectId, q logQuery, initial []LogData, duration time.Duration, render func([]LogData) (interface{}, error), display *displayFormat) (*logFollow, error) {
+	result := &logFollow{}
+	seen := map[string]bool{}
+	for _, entry := range initial {
+		seen[logEntryKey(entry)] = true
+		entry.Timestamp = normalizeTimestamp(entry.Timestamp, display)
+		result.logs = append(result.logs, entry)
+	}
+
+	deadline := time.NewTimer(duration)
+	defer deadline.Stop()
+	ticker := time.NewTicker(followPollInterval)
+	defer ticker.Stop()
+
+	q.Limit = followPollLimit
+	for {
+		select {
+		case <-ctx.Done():
+			result.stopReason = "cancelled"
+			return result, nil
+		case <-deadline.C:
+			result.stopReason = "max_duration"
+			return result, nil
+		case <-ticker.C:
+		}
+
+		entries, err := fetchLogs(ctx, client, projectID, q)
+		if err != nil {
+			if ctx.Err() != nil {
+				result.stopReason = "cancelled"
+				return result, nil
+			}
+			shared.Log(ctx, "warning", "logs", fmt.Sprintf("log poll failed: %v", err))
+			continue
+		}
+
+		var fresh []LogData
+		var times []time.Time
+		for _, entry := range entries {
+			key := logEntryKey(entry)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			t, _ := parseTimestamp(entry.Timestamp)
+			fresh = append(fresh, entry)
+			times = append(times, t)
+		}
+		if len(fresh) == 0 {
+			continue
+		}
+		// Sorted by the parsed time, display formats like 02/01/2006 don't sort as strings
+		sort.Stable(logsByTime{fresh, times})
+		for i := range fresh {
+			fresh[i].Timestamp = normalizeTimestamp(fresh[i].Timestamp, display)
+		}
+
+		rendered, err := render(fresh)
+		if err != nil {
+			return nil, err
+		}
+		shared.Log(ctx, "info", "logs", rendered)
+		result.streamed += len(fresh)
+		shared.ReportProgress(ctx, float64(result.streamed), 0, fmt.Sprintf("%d new log lines", result.streamed))
+
+		result.logs = append(result.logs, fresh...)
+		if len(result.logs) > maxFollowEntries {
+			result.logs = result.logs[len(result.logs)-maxFollowEntries:]
+			result.truncated = true
+		}
+	}
+}
+
+// logsByTime sorts log lines by their parsed timestamps
+type logsByTime struct {
+	logs  []LogData
+	times []time.Time
+}
+
+func (l logsByTime) Len() int           { return len(l.logs) }
+func (l logsByTime) Less(i, j int) bool { return l.times[i].Before(l.times[j]) }
+func (l logsByTime) Swap(i, j int) {
+	l.logs[i], l.logs[j] = l.logs[j], l.logs[i]
+	l.times[i], l.times[j] = l.times[j], l.times[i]
+}
+
+// logEntryKey identifies a log line across polls; lines without an ID fall back to their content
+func logEntryKey(entry LogData) string {
+	if entry.Id != "" {
+		return entry.Id
+	}
+	return entry.Timestamp + "|" + entry.Hostname + "|" + entry.Content
+}
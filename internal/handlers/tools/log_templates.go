@@ -0,0 +1,85 @@
+package tools
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+)
+
+// logTemplatePresets are named format templates usable as format_template
+var logTemplatePresets = map[string]string{
+	"nginx":    `{{.Hostname}} [{{.Timestamp}}] {{.SeverityLabel}}: {{.Content}}`,
+	"json-app": `{{.Timestamp}} [{{or .JSON.level .JSON.severity .SeverityLabel}}] {{or .JSON.msg .JSON.message .Message}}`,
+	"compact":  `{{.Timestamp}} {{.SeverityLabel}} {{.Message}}`,
+}
+
+// logTemplateData is the value a format template is executed against
+type logTemplateData struct {
+	LogData
+	// JSON holds the message parsed as a JSON object, empty for plain text messages
+	JSON map[string]interface{}
+}
+
+// compileLogTemplate resolves a preset name or parses a custom text/template
+func compileLogTemplate(formatTemplate string) (*template.Template, error) {
+	source := formatTemplate
+	if preset, ok := logTemplatePresets[formatTemplate]; ok {
+		source = preset
+	}
+
+	tmpl, err := template.New("log").Option("missingkey=zero").Parse(source)
+	if err != nil {
+		return nil, shared.InvalidArgument("Invalid format_template: %v", err)
+	}
+	return tmpl, nil
+}
+
+// renderLogTemplate formats every log entry as one line using the template
+func renderLogTemplate(logs []LogData, tmpl *template.Template) ([]string, error) {
+	lines := make([]string, 0, len(logs))
+	var buf bytes.Buffer
+	for _, log := range logs {
+		data := logTemplateData{LogData: log, JSON: map[string]interface{}{}}
+		if strings.HasPrefix(strings.TrimSpace(log.Message), "{") {
+			_ = json.Unmarshal([]byte(log.Message), &data.JSON)
+		}
+
+		buf.Reset()
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return nil, shared.InvalidArgument("format_template failed: %v", err)
+		}
+		lines = append(lines, buf.String())
+	}
+	return lines, nil
+}
+
+// logTemplateFieldsDoc documents the fields available to format templates, generated from LogData
+func logTemplateFieldsDoc() string {
+	logType := reflect.TypeOf(LogData{})
+	fields := make([]string, 0, logType.NumField()+1)
+	for i := 0; i < logType.NumField(); i++ {
+		field := logType.Field(i)
+		fields = append(fields, fmt.Sprintf("{{.%s}} (%s)", field.Name, field.Type.Kind()))
+	}
+	fields = append(fields, "{{.JSON.<key>}} (message parsed as JSON object)")
+
+	presets := make([]string, 0, len(logTemplatePresets))
+	for name := range logTemplatePresets {
+		presets = append(presets, name)
+	}
+	sort.Strings(presets)
+
+	return fmt.Sprintf(`
+
+FORMAT TEMPLATES:
+format_template is a Go text/template rendered once per log line (returns a list of strings).
+Presets: %s
+Fields: %s
+Example: "{{.Timestamp}} {{.Hostname}} {{.Message}}"`, strings.Join(presets, ", "), strings.Join(fields, ", "))
+}
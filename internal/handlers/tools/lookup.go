@@ -0,0 +1,139 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/dto/input/body"
+	"github.com/zeropsio/zerops-go/dto/input/path"
+	"github.com/zeropsio/zerops-go/dto/output"
+	"github.com/zeropsio/zerops-go/sdk"
+	"github.com/zeropsio/zerops-go/types"
+	"github.com/zeropsio/zerops-go/types/uuid"
+)
+
+// Lookup helpers shared by tools that work with a whole project
+
+// resolveProjectID returns the project_id argument or the session's current project,
+// see resolveProject
+func resolveProjectID(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (string, error) {
+	resolved, err := resolveProject(ctx, client, args)
+	return resolved.ID, err
+}
+
+// searchProject loads a project including its envList
+func searchProject(ctx context.Context, client *sdk.Handler, projectID string) (output.EsProject, error) {
+	projectResp, err := client.GetProject(ctx, path.ProjectId{Id: uuid.ProjectId(projectID)})
+	if err != nil {
+		return output.EsProject{}, shared.WrapAPIError(err, "Failed to get project")
+	}
+
+	projectOutput, err := projectResp.Output()
+	if err != nil {
+		return output.EsProject{}, shared.WrapAPIError(err, "Failed to get project")
+	}
+
+	searchResp, err := client.PostProjectSearch(ctx, body.EsFilter{
+		Search: []body.EsSearchItem{
+			{Name: "id", Operator: "eq", Value: types.String(projectID)},
+			{Name: "clientId", Operator: "eq", Value: projectOutput.ClientId.TypedString()},
+		},
+	})
+	if err != nil {
+		return output.EsProject{}, shared.WrapAPIError(err, "Failed to search project")
+	}
+
+	searchOutput, err := searchResp.Output()
+	if err != nil {
+		return output.EsProject{}, shared.WrapAPIError(err, "Failed to search project")
+	}
+
+	if len(searchOutput.Items) == 0 {
+		return output.EsProject{}, shared.NotFound("Project not found")
+	}
+
+	return searchOutput.Items[0], nil
+}
+
+// searchProjectServices lists all services of a project
+func searchProjectServices(ctx context.Context, client *sdk.Handler, project output.EsProject) ([]output.EsServiceStack, error) {
+	serviceResp, err := client.PostServiceStackSearch(ctx, body.EsFilter{
+		Search: []body.EsSearchItem{
+			{Name: "projectId", Operator: "eq", Value: types.String(string(project.Id))},
+			{Name: "clientId", Operator: "eq", Value: project.ClientId.TypedString()},
+		},
+	})
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to search services")
+	}
+
+	serviceOutput, err := serviceResp.Output()
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to search services")
+	}
+
+	return serviceOutput.Items, nil
+}
+
+// serviceEnvKeys returns the env variable keys of a service, nil if they can't be read
+func serviceEnvKeys(ctx context.Context, client *sdk.Handler, serviceID uuid.ServiceStackId) []string {
+	envResp, err := client.GetServiceStackEnv(ctx, path.ServiceStackId{Id: serviceID})
+	if err != nil {
+		return nil
+	}
+	envOutput, err := envResp.Output()
+	if err != nil {
+		return nil
+	}
+
+	keys := make([]string, 0, len(envOutput.Items))
+	for _, envItem := range envOutput.Items {
+		keys = append(keys, envItem.Key.Native())
+	}
+	return keys
+}
+
+// userDataSearchPage is how many env variables one search request returns
+const userDataSearchPage = 500
+
+// projectServiceEnvKeys returns the env variable keys of every service of a project, read with
+// one search instead of a request per service
+func projectServiceEnvKeys(ctx context.Context, client *sdk.Handler, project output.EsProject) (map[uuid.ServiceStackId][]string, error) {
+	keys := make(map[uuid.ServiceStackId][]string)
+	for offset := 0; ; offset += userDataSearchPage {
+		resp, err := client.PostUserDataSearch(ctx, body.EsFilter{
+			Search: []body.EsSearchItem{
+				{Name: "projectId", Operator: "eq", Value: types.String(string(project.Id))},
+				{Name: "clientId", Operator: "eq", Value: project.ClientId.TypedString()},
+			},
+			Offset: types.NewIntNull(offset),
+			Limit:  types.NewIntNull(userDataSearchPage),
+		})
+		if err != nil {
+			return nil, shared.WrapAPIError(err, "Failed to search environment variables")
+		}
+		userData, err := resp.Output()
+		if err != nil {
+			return nil, shared.WrapAPIError(err, "Failed to search environment variables")
+		}
+		for _, item := range userData.Items {
+			keys[item.ServiceStackId] = append(keys[item.ServiceStackId], item.Key.Native())
+		}
+		if len(userData.Items) < userDataSearchPage || offset+len(userData.Items) >= userData.TotalHits.Native() {
+			return keys, nil
+		}
+	}
+}
+
+// serviceProjectID returns the project a service belongs to
+func serviceProjectID(ctx context.Context, client *sdk.Handler, serviceID string) (string, error) {
+	serviceResp, err := client.GetServiceStack(ctx, path.ServiceStackId{Id: uuid.ServiceStackId(serviceID)})
+	if err != nil {
+		return "", shared.WrapAPIError(err, "Failed to get service")
+	}
+	serviceOutput, err := serviceResp.Output()
+	if err != nil {
+		return "", shared.WrapAPIError(err, "Failed to get service")
+	}
+	return string(serviceOutput.ProjectId), nil
+}
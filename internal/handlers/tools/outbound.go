@@ -0,0 +1,77 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// errNonPublicAddress is returned when an outbound request would reach a non-public address
+var errNonPublicAddress = errors.New("destination is a loopback, private or link-local address")
+
+// sharedAddressSpace is the carrier-grade NAT range (RFC 6598), not covered by net.IP.IsPrivate
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// isPublicIP reports whether ip is routable on the internet
+func isPublicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() || sharedAddressSpace.Contains(ip))
+}
+
+// checkPublicURL checks that raw is an absolute URL with one of the schemes and that its host
+// is not a literal non-public address. Hostnames are resolved and checked when dialing, see
+// publicHTTPClient.
+func checkPublicURL(raw string, schemes ...string) error {
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Hostname() == "" {
+		return fmt.Errorf("must be an absolute %s URL", strings.Join(schemes, "/"))
+	}
+	allowed := false
+	for _, scheme := range schemes {
+		allowed = allowed || parsed.Scheme == scheme
+	}
+	if !allowed {
+		return fmt.Errorf("must be an absolute %s URL", strings.Join(schemes, "/"))
+	}
+	host := strings.ToLower(parsed.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return errNonPublicAddress
+	}
+	if ip := net.ParseIP(host); ip != nil && !isPublicIP(ip) {
+		return errNonPublicAddress
+	}
+	return nil
+}
+
+// publicHTTPClient only connects to public addresses. The check runs on the resolved IP of
+// every connection, redirects included, so DNS names pointing inside can't be used either.
+func publicHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 10 * time.Second,
+		Control: func(network, address string, conn syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+				return fmt.Errorf("%s: %w", host, errNonPublicAddress)
+			}
+			return nil
+		},
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network, address string) (net.Conn, error) {
+				return dialer.DialContext(ctx, network, address)
+			},
+			TLSHandshakeTimeout: 10 * time.Second,
+		},
+	}
+}
@@ -0,0 +1,102 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/sdk"
+)
+
+const (
+	dateFormatISO    = "iso"
+	dateFormatLocale = "locale"
+	sizeUnitsDecimal = "decimal"
+	sizeUnitsBinary  = "binary"
+)
+
+// outputFormat is a session's preference for rendering dates and sizes
+type outputFormat struct {
+	Dates string `json:"dates"`
+	Sizes string `json:"sizes"`
+}
+
+// sessionOutputFormat returns the output format of the caller's session
+func sessionOutputFormat(ctx context.Context) outputFormat {
+	preferences := shared.SessionPreferences(ctx)
+	return outputFormat{Dates: preferences.Dates, Sizes: preferences.Sizes}
+}
+
+// RegisterOutputFormat registers the set_output_format tool, an older name for the output
+// settings of set_preferences
+func RegisterOutputFormat() {
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "set_output_format",
+		Description: toolDescription("set_output_format"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"dates": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Date format for this session",
+					"enum":        []string{dateFormatISO, dateFormatLocale},
+				},
+				"sizes": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Size units for this session",
+					"enum":        []string{sizeUnitsDecimal, sizeUnitsBinary},
+				},
+				"compact": map[string]interface{}{
+					"type":        "boolean",
+					"description": "OPTIONAL: Render results as terse key=value lines (default: false, or ZEROPS_MCP_COMPACT)",
+				},
+				"ascii": map[string]interface{}{
+					"type":        "boolean",
+					"description": "OPTIONAL: Make results ASCII-only (default: false, or ZEROPS_MCP_ASCII)",
+				},
+			},
+			"additionalProperties": false,
+		},
+		Annotations: shared.Mutating(false, true),
+		Output:      setOutputFormatResult{},
+		Handler:     handleSetOutputFormat,
+	})
+}
+
+// setOutputFormatResult is the result of set_output_format
+type setOutputFormatResult struct {
+	Format  outputFormat `json:"format"`
+	Compact bool         `json:"compact"`
+	ASCII   bool         `json:"ascii"`
+	Message string       `json:"message,omitempty"`
+}
+
+func handleSetOutputFormat(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	format := make(map[string]interface{})
+	for _, name := range []string{"dates", "sizes", "ascii"} {
+		if value, ok := args[name]; ok {
+			format[name] = value
+		}
+	}
+	preferenceArgs := make(map[string]interface{})
+	if len(format) > 0 {
+		preferenceArgs["output_format"] = format
+	}
+	if compact, ok := args["compact"]; ok {
+		preferenceArgs["compact"] = compact
+	}
+
+	result, err := handleSetPreferences(ctx, client, preferenceArgs)
+	if err != nil {
+		return nil, err
+	}
+	preferences := result.(setPreferencesResult)
+	output := setOutputFormatResult{
+		Format:  preferences.OutputFormat,
+		Compact: preferences.Compact,
+		ASCII:   preferences.ASCII,
+	}
+	if preferences.Message != "" {
+		output.Message = "Output format updated for this session."
+	}
+	return output, nil
+}
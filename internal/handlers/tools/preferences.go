@@ -0,0 +1,138 @@
+package tools
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/sdk"
+)
+
+// languagePattern accepts BCP 47 tags like "en", "cs" or "en-GB"
+var languagePattern = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
+// RegisterPreferences registers the set_preferences tool
+func RegisterPreferences() {
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "set_preferences",
+		Description: toolDescription("set_preferences"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"output_format": map[string]interface{}{
+					"type":        "object",
+					"description": "OPTIONAL: Output format as in set_output_format: {\"dates\": \"iso\"|\"locale\", \"sizes\": \"decimal\"|\"binary\", \"ascii\": bool}",
+					"properties": map[string]interface{}{
+						"dates": map[string]interface{}{"type": "string", "enum": []string{dateFormatISO, dateFormatLocale}},
+						"sizes": map[string]interface{}{"type": "string", "enum": []string{sizeUnitsDecimal, sizeUnitsBinary}},
+						"ascii": map[string]interface{}{"type": "boolean"},
+					},
+					"additionalProperties": false,
+				},
+				"compact": map[string]interface{}{
+					"type":        "boolean",
+					"description": "OPTIONAL: Render results as terse key=value lines (default: false, or ZEROPS_MCP_COMPACT)",
+				},
+				"timezone": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: IANA timezone for returned timestamps, e.g. 'Europe/Prague'; empty string resets to $ZEROPS_MCP_TIMEZONE or UTC",
+				},
+				"language": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Language of the user as a BCP 47 tag, e.g. 'cs' or 'en-GB'; sets the layout of locale dates (default: ZEROPS_MCP_LANGUAGE)",
+				},
+				"confirm_destructive": map[string]interface{}{
+					"type":        "boolean",
+					"description": "OPTIONAL: Require confirm: true on every destructive tool call (default: false, or ZEROPS_MCP_CONFIRM_DESTRUCTIVE)",
+				},
+			},
+			"additionalProperties": false,
+		},
+		Annotations: shared.Mutating(false, true),
+		Output:      setPreferencesResult{},
+		Handler:     handleSetPreferences,
+	})
+}
+
+// setPreferencesResult is the result of set_preferences
+type setPreferencesResult struct {
+	OutputFormat       outputFormat `json:"output_format"`
+	Compact            bool         `json:"compact"`
+	ASCII              bool         `json:"ascii"`
+	Timezone           string       `json:"timezone,omitempty"`
+	Language           string       `json:"language,omitempty"`
+	ConfirmDestructive bool         `json:"confirm_destructive"`
+	Message            string       `json:"message,omitempty"`
+}
+
+// newSetPreferencesResult reports the stored preferences
+func newSetPreferencesResult(preferences shared.Preferences) setPreferencesResult {
+	return setPreferencesResult{
+		OutputFormat:       outputFormat{Dates: preferences.Dates, Sizes: preferences.Sizes},
+		Compact:            preferences.Compact,
+		ASCII:              preferences.ASCII,
+		Timezone:           preferences.Timezone,
+		Language:           preferences.Language,
+		ConfirmDestructive: preferences.ConfirmDestructive,
+	}
+}
+
+// handleSetPreferences also serves set_output_format, so both tools change the same preferences
+func handleSetPreferences(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	formatArgs, hasFormat := args["output_format"].(map[string]interface{})
+	dates, _ := formatArgs["dates"].(string)
+	sizes, _ := formatArgs["sizes"].(string)
+	ascii, hasASCII := formatArgs["ascii"].(bool)
+	if dates != "" && dates != dateFormatISO && dates != dateFormatLocale {
+		return nil, shared.InvalidArgument("output_format.dates must be '%s' or '%s'", dateFormatISO, dateFormatLocale)
+	}
+	if sizes != "" && sizes != sizeUnitsDecimal && sizes != sizeUnitsBinary {
+		return nil, shared.InvalidArgument("output_format.sizes must be '%s' or '%s'", sizeUnitsDecimal, sizeUnitsBinary)
+	}
+	compact, hasCompact := args["compact"].(bool)
+
+	timezone, hasTimezone := args["timezone"].(string)
+	if hasTimezone {
+		if _, err := time.LoadLocation(timezone); err != nil {
+			return nil, shared.InvalidArgument("Unknown timezone '%s'. Use an IANA name like 'Europe/Prague' or 'UTC'.", timezone)
+		}
+	}
+	language, hasLanguage := args["language"].(string)
+	if hasLanguage && language != "" && !languagePattern.MatchString(language) {
+		return nil, shared.InvalidArgument("language must be a BCP 47 tag like 'en', 'cs' or 'en-GB', got '%s'", language)
+	}
+	confirm, hasConfirm := args["confirm_destructive"].(bool)
+
+	if !hasFormat && !hasCompact && !hasTimezone && !hasLanguage && !hasConfirm {
+		return newSetPreferencesResult(shared.SessionPreferences(ctx)), nil
+	}
+
+	preferences := shared.UpdateSessionPreferences(ctx, func(preferences *shared.Preferences) {
+		if dates != "" {
+			preferences.Dates = dates
+		}
+		if sizes != "" {
+			preferences.Sizes = sizes
+		}
+		if hasASCII {
+			preferences.ASCII = ascii
+		}
+		if hasCompact {
+			preferences.Compact = compact
+		}
+		if hasTimezone {
+			preferences.Timezone = timezone
+		}
+		if hasLanguage {
+			preferences.Language = language
+		}
+		if hasConfirm {
+			preferences.ConfirmDestructive = confirm
+		}
+	})
+
+	result := newSetPreferencesResult(preferences)
+	result.Message = "Preferences updated for this session. Tool messages stay in English; reply to the user in their language."
+	return result, nil
+}
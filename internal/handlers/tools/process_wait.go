@@ -0,0 +1,180 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/dto/input/path"
+	"github.com/zeropsio/zerops-go/dto/output"
+	"github.com/zeropsio/zerops-go/sdk"
+	"github.com/zeropsio/zerops-go/types/enum"
+	"github.com/zeropsio/zerops-go/types/uuid"
+)
+
+// Process polling backoff: starts fast for short processes, slows down for long builds
+const (
+	processPollInitial = 2 * time.Second
+	processPollMax     = 15 * time.Second
+)
+
+// finalProcessStatuses map the end states of a process to the result status of wait_for_process
+var finalProcessStatuses = map[enum.ProcessStatusEnum]string{
+	enum.ProcessStatusEnumFinished: "completed",
+	enum.ProcessStatusEnumFailed:   "failed",
+	enum.ProcessStatusEnumCanceled: "canceled",
+}
+
+// RegisterProcessWait registers the wait_for_process tool
+func RegisterProcessWait() {
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "wait_for_process",
+		Description: toolDescription("wait_for_process"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"process_id": map[string]interface{}{
+					"type":        "string",
+					"description": "REQUIRED: Process ID returned from async operations",
+					"pattern":     "^[A-Za-z0-9_-]+$",
+				},
+				"timeout_seconds": map[string]interface{}{
+					"type":        "integer",
+					"description": "OPTIONAL: Maximum time to wait (10-1800, default: 300)",
+					"minimum":     10,
+					"maximum":     1800,
+					"default":     300,
+				},
+				"timezone": timezoneProperty(),
+			},
+			"required":             []string{"process_id"},
+			"additionalProperties": false,
+		},
+		Annotations: shared.ReadOnly(),
+		Output:      waitForProcessResult{},
+		Handler:     handleWaitForProcess,
+	})
+}
+
+// waitForProcessResult is the result of wait_for_process. Status is completed, failed,
+// canceled or timeout; ProcessStatus is the status Zerops reported last.
+type waitForProcessResult struct {
+	ProcessID      string `json:"process_id"`
+	Status         string `json:"status"`
+	ProcessStatus  string `json:"process_status"`
+	ActionName     string `json:"action_name"`
+	ElapsedSeconds int    `json:"elapsed_seconds"`
+	Started        string `json:"started,omitempty"`
+	Finished       string `json:"finished,omitempty"`
+	Message        string `json:"message,omitempty"`
+	shared.Suggestions
+}
+
+func handleWaitForProcess(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+
+	processID, ok := args["process_id"].(string)
+	if !ok || processID == "" {
+		return nil, shared.InvalidArgument("Process ID is required")
+	}
+
+	display, err := resolveDisplayFormat(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := defaultWaitTimeout
+	if t, ok := args["timeout_seconds"].(float64); ok && t > 0 {
+		timeout = time.Duration(t) * time.Second
+		if timeout > maxWaitTimeout {
+			timeout = maxWaitTimeout
+		}
+	}
+
+	result, err := waitForProcess(ctx, client, processID, timeout, display)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// waitForProcess polls a process until it reaches a final status or the timeout expires
+func waitForProcess(ctx context.Context, client *sdk.Handler, processID string, timeout time.Duration, display *displayFormat) (waitForProcessResult, error) {
+	started := time.Now()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	processPath := path.ProcessId{Id: uuid.ProcessId(processID)}
+	interval := processPollInitial
+	var process output.Process
+	var lastStatus enum.ProcessStatusEnum
+
+	for {
+		processResp, err := client.GetProcess(shared.WithBackgroundPolling(ctx), processPath)
+		if err == nil {
+			process, err = processResp.Output()
+		}
+		if err != nil && ctx.Err() == nil {
+			return waitForProcessResult{}, shared.WrapAPIError(err, "Failed to get process")
+		}
+		if err == nil {
+			if process.Status != lastStatus {
+				shared.Log(ctx, "info", "wait_for_process", fmt.Sprintf("Process %s (%s) is %s", processID, process.ActionName.Native(), process.Status))
+			}
+			lastStatus = process.Status
+			if status, ok := finalProcessStatuses[process.Status]; ok {
+				return processWaitResult(process, status, started, display), nil
+			}
+		}
+
+		elapsed := time.Since(started)
+		shared.ReportProgress(ctx, elapsed.Seconds(), timeout.Seconds(), fmt.Sprintf("Process status %s", lastStatus))
+
+		select {
+		case <-ctx.Done():
+			if parentErr := context.Cause(ctx); parentErr != nil && parentErr != context.DeadlineExceeded {
+				return waitForProcessResult{}, parentErr
+			}
+			result := processWaitResult(process, "timeout", started, display)
+			result.ProcessID = processID
+			result.Message = fmt.Sprintf("Process was still %s after %d seconds. Call wait_for_process again to keep waiting.", lastStatus, int(timeout.Seconds()))
+			result.SuggestNext(shared.WaitForProcess(processID, "Keep waiting for the process"))
+			return result, nil
+		case <-time.After(interval):
+		}
+
+		interval = interval * 3 / 2
+		if interval > processPollMax {
+			interval = processPollMax
+		}
+	}
+}
+
+// processWaitResult describes the process as last read
+func processWaitResult(process output.Process, status string, started time.Time, display *displayFormat) waitForProcessResult {
+	result := waitForProcessResult{
+		ProcessID:      string(process.Id),
+		Status:         status,
+		ProcessStatus:  string(process.Status),
+		ActionName:     process.ActionName.Native(),
+		ElapsedSeconds: int(time.Since(started).Seconds()),
+	}
+	if value, ok := process.Started.Get(); ok {
+		result.Started = formatTimestamp(value.Native(), display)
+	}
+	if value, ok := process.Finished.Get(); ok {
+		result.Finished = formatTimestamp(value.Native(), display)
+	}
+	switch status {
+	case "completed":
+		result.Message = "Process finished successfully."
+	case "failed":
+		result.Message = "Process failed. Check get_service_logs (show_build_logs: true for builds) and get_running_processes."
+	case "canceled":
+		result.Message = "Process was canceled."
+	}
+	return result
+}
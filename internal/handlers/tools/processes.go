@@ -4,7 +4,7 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/zerops-mcp-basic/internal/handlers/shared"
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
 	"github.com/zeropsio/zerops-go/dto/input/body"
 	"github.com/zeropsio/zerops-go/dto/input/path"
 	"github.com/zeropsio/zerops-go/sdk"
@@ -16,30 +16,7 @@ import (
 func RegisterProcesses() {
 	shared.GlobalRegistry.Register(&shared.ToolDefinition{
 		Name:        "get_running_processes",
-		Description: `Retrieves information about running processes, optionally filtered by service.
-
-PROCESS INFORMATION:
-- Process IDs and status
-- Creation timestamps
-- Associated service information
-- Process state and metadata
-
-FILTERING OPTIONS:
-- No service_id: Returns all processes across all services (limited to 50)
-- With service_id: Returns processes only for specified service
-- Use limit parameter to control response size
-
-PROCESS STATES:
-- running: Process is actively running
-- completed: Process finished successfully
-- failed: Process encountered an error
-- pending: Process is queued/starting
-
-WHEN TO USE:
-- Monitoring service deployments
-- Checking process status after operations
-- Debugging service issues
-- Tracking long-running operations`,
+		Description: toolDescription("get_running_processes"),
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
@@ -55,16 +32,37 @@ WHEN TO USE:
 					"maximum":     100,
 					"default":     20,
 				},
+				"timezone": timezoneProperty(),
 			},
 			"additionalProperties": false,
 		},
-		Handler: handleGetRunningProcesses,
+		Annotations: shared.ReadOnly(),
+		Output:      runningProcessesResult{},
+		Handler:     handleGetRunningProcesses,
 	})
 }
 
+// runningProcessesResult is the result of get_running_processes
+type runningProcessesResult struct {
+	Service   string           `json:"service,omitempty"`
+	Processes []runningProcess `json:"processes"`
+	Count     int              `json:"count"`
+	Limit     int              `json:"limit,omitempty"`
+	Note      string           `json:"note,omitempty"`
+	Message   string           `json:"message,omitempty"`
+	Warnings  []string         `json:"warnings,omitempty"`
+}
+
+// runningProcess is one process in get_running_processes
+type runningProcess struct {
+	ID      string `json:"id"`
+	Status  string `json:"status"`
+	Created string `json:"created"`
+}
+
 func handleGetRunningProcesses(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
 	if client == nil {
-		return shared.ErrorResponse("No API key provided"), nil
+		return nil, shared.ErrNoClient
 	}
 
 	// Get limit parameter
@@ -73,6 +71,11 @@ func handleGetRunningProcesses(ctx context.Context, client *sdk.Handler, args ma
 		limit = int(l)
 	}
 
+	display, err := resolveDisplayFormat(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
 	// Check if service_id is provided
 	serviceID, hasServiceID := args["service_id"].(string)
 
@@ -83,12 +86,12 @@ func handleGetRunningProcesses(ctx context.Context, client *sdk.Handler, args ma
 		// Get service details
 		serviceResp, err := client.GetServiceStack(ctx, servicePath)
 		if err != nil {
-			return shared.ErrorResponse(fmt.Sprintf("Failed to get service: %v", err)), nil
+			return nil, shared.WrapAPIError(err, "Failed to get service")
 		}
 
 		serviceOutput, err := serviceResp.Output()
 		if err != nil {
-			return shared.ErrorResponse(fmt.Sprintf("Failed to parse service: %v", err)), nil
+			return nil, shared.WrapAPIError(err, "Failed to parse service")
 		}
 
 		// Get RUNNING processes for this service
@@ -109,46 +112,47 @@ func handleGetRunningProcesses(ctx context.Context, client *sdk.Handler, args ma
 
 		processResp, err := client.PostProcessSearch(ctx, processFilter)
 		if err != nil {
-			return shared.ErrorResponse(fmt.Sprintf("Failed to get processes: %v", err)), nil
+			return nil, shared.WrapAPIError(err, "Failed to get processes")
 		}
 
 		processOutput, err := processResp.Output()
 		if err != nil {
-			return shared.ErrorResponse(fmt.Sprintf("Failed to parse processes: %v", err)), nil
+			return nil, shared.WrapAPIError(err, "Failed to parse processes")
 		}
 
-		var processes []map[string]interface{}
+		processes := []runningProcess{}
 		for i, process := range processOutput.Items {
 			if i >= limit {
 				break
 			}
-			processInfo := map[string]interface{}{
-				"id":      string(process.Id),
-				"status":  string(process.Status),
-				"created": process.Created.Format("15:04:05"),
-			}
-			processes = append(processes, processInfo)
+			processes = append(processes, runningProcess{
+				ID:      string(process.Id),
+				Status:  string(process.Status),
+				Created: formatTimestamp(process.Created.Native(), display),
+			})
 		}
 
-		return map[string]interface{}{
-			"service":   serviceOutput.Name.Native(),
-			"processes": processes,
-			"count":     len(processes),
+		return runningProcessesResult{
+			Service:   serviceOutput.Name.Native(),
+			Processes: processes,
+			Count:     len(processes),
 		}, nil
 	}
 
 	// Get all processes across all services
 	userResp, err := client.GetUserInfo(ctx)
 	if err != nil {
-		return shared.ErrorResponse(fmt.Sprintf("Failed to get user info: %v", err)), nil
+		return nil, shared.WrapAPIError(err, "Failed to get user info")
 	}
 
 	userOutput, err := userResp.Output()
 	if err != nil {
-		return shared.ErrorResponse(fmt.Sprintf("Failed to parse user info: %v", err)), nil
+		return nil, shared.WrapAPIError(err, "Failed to parse user info")
 	}
+	shared.StoreIdentity(sessionAPIKey(ctx), userOutput)
 
-	var allProcesses []map[string]interface{}
+	var allProcesses []runningProcess
+	var warnings []string
 
 	// Get RUNNING processes for all clients
 	for _, clientUser := range userOutput.ClientUserList {
@@ -172,11 +176,13 @@ func handleGetRunningProcesses(ctx context.Context, client *sdk.Handler, args ma
 
 		processResp, err := client.PostProcessSearch(ctx, processFilter)
 		if err != nil {
+			warnings = append(warnings, clientUser.Client.AccountName.Native()+": "+err.Error())
 			continue
 		}
 
 		processOutput, err := processResp.Output()
 		if err != nil {
+			warnings = append(warnings, clientUser.Client.AccountName.Native()+": "+err.Error())
 			continue
 		}
 
@@ -184,32 +190,35 @@ func handleGetRunningProcesses(ctx context.Context, client *sdk.Handler, args ma
 			if len(allProcesses) >= limit {
 				break
 			}
-			processInfo := map[string]interface{}{
-				"id":      string(process.Id),
-				"status":  string(process.Status),
-				"created": process.Created.Format("2006-01-02 15:04:05"),
-			}
-			
-			allProcesses = append(allProcesses, processInfo)
+			allProcesses = append(allProcesses, runningProcess{
+				ID:      string(process.Id),
+				Status:  string(process.Status),
+				Created: formatTimestamp(process.Created.Native(), display),
+			})
 		}
 	}
 
 	if len(allProcesses) == 0 {
-		return map[string]interface{}{
-			"processes": []interface{}{},
-			"message":   "No running processes found",
-		}, nil
+		result := runningProcessesResult{
+			Processes: []runningProcess{},
+			Message:   "No running processes found",
+			Warnings:  warnings,
+		}
+		if len(warnings) > 0 {
+			result.Message = "No running processes found in the organizations that could be searched"
+		}
+		return result, nil
 	}
 
-	result := map[string]interface{}{
-		"processes": allProcesses,
-		"count":     len(allProcesses),
-		"limit":     limit,
+	result := runningProcessesResult{
+		Processes: allProcesses,
+		Count:     len(allProcesses),
+		Limit:     limit,
+		Warnings:  warnings,
 	}
-	
 	if len(allProcesses) == limit {
-		result["note"] = fmt.Sprintf("Results limited to %d processes. Use 'limit' parameter to see more or filter by service_id.", limit)
+		result.Note = fmt.Sprintf("Results limited to %d processes. Use 'limit' parameter to see more or filter by service_id.", limit)
 	}
-	
+
 	return result, nil
 }
\ No newline at end of file
@@ -0,0 +1,478 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/dto/input/body"
+	"github.com/zeropsio/zerops-go/dto/input/path"
+	"github.com/zeropsio/zerops-go/dto/output"
+	"github.com/zeropsio/zerops-go/sdk"
+	"github.com/zeropsio/zerops-go/types"
+	"github.com/zeropsio/zerops-go/types/uuid"
+	"gopkg.in/yaml.v3"
+)
+
+// desiredState is the part of an import YAML that project_apply reconciles
+type desiredState struct {
+	Project struct {
+		EnvVariables map[string]string `yaml:"envVariables"`
+	} `yaml:"project"`
+	Services []desiredService `yaml:"services"`
+}
+
+type desiredService struct {
+	Hostname            string            `yaml:"hostname"`
+	Type                string            `yaml:"type"`
+	Mode                string            `yaml:"mode"`
+	MinContainers       *int              `yaml:"minContainers"`
+	MaxContainers       *int              `yaml:"maxContainers"`
+	VerticalAutoscaling *desiredVertical  `yaml:"verticalAutoscaling"`
+	EnvVariables        map[string]string `yaml:"envVariables"`
+	EnvSecrets          map[string]string `yaml:"envSecrets"`
+}
+
+type desiredVertical struct {
+	MinCpu  *int     `yaml:"minCpu"`
+	MaxCpu  *int     `yaml:"maxCpu"`
+	MinRam  *float64 `yaml:"minRam"`
+	MaxRam  *float64 `yaml:"maxRam"`
+	MinDisk *float64 `yaml:"minDisk"`
+	MaxDisk *float64 `yaml:"maxDisk"`
+}
+
+// applyStep is one change project_apply makes to move the project toward the desired state
+type applyStep struct {
+	Action string `json:"action"`
+	Target string `json:"target"`
+	Detail string `json:"detail,omitempty"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	run    func(ctx context.Context) error
+}
+
+// applyDrift is a difference project_apply reports but does not change
+type applyDrift struct {
+	Service string   `json:"service"`
+	Field   string   `json:"field"`
+	Desired string   `json:"desired,omitempty"`
+	Live    string   `json:"live,omitempty"`
+	Detail  string   `json:"detail,omitempty"`
+	Keys    []string `json:"keys,omitempty"`
+}
+
+// projectApplyResult is the result of project_apply
+type projectApplyResult struct {
+	ProjectID   string                  `json:"project_id"`
+	DryRun      bool                    `json:"dry_run"`
+	Steps       []applyStep             `json:"steps"`
+	Unsupported []applyDrift            `json:"unsupported"`
+	InSync      bool                    `json:"in_sync"`
+	Preflight   *importPreflightSummary `json:"preflight,omitempty"`
+	Lint        []importProblem         `json:"lint,omitempty"`
+	Applied     *int                    `json:"applied,omitempty"`
+	Failed      *int                    `json:"failed,omitempty"`
+	Message     string                  `json:"message"`
+}
+
+// typeVersionSeparators normalizes postgresql@16 and postgresql_16 for comparison
+var typeVersionSeparators = regexp.MustCompile(`[^a-z0-9]+`)
+
+// RegisterProjectApply registers the project_apply tool
+func RegisterProjectApply() {
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "project_apply",
+		Description: toolDescription("project_apply"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"yaml": map[string]interface{}{
+					"type":        "string",
+					"description": "REQUIRED: Full desired-state import YAML (project and services sections)",
+				},
+				"project_id": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Project ID. Defaults to $projectId.",
+					"pattern":     "^[A-Za-z0-9_-]+$",
+				},
+				"dry_run": map[string]interface{}{
+					"type":        "boolean",
+					"description": "OPTIONAL: Only return the plan without changing anything (default: false)",
+					"default":     false,
+				},
+			},
+			"required":             []string{"yaml"},
+			"additionalProperties": false,
+		},
+		Annotations: shared.Mutating(true, true),
+		Output:      projectApplyResult{},
+		Handler:     handleProjectApply,
+	})
+}
+
+func handleProjectApply(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+
+	yamlContent, ok := args["yaml"].(string)
+	if !ok || strings.TrimSpace(yamlContent) == "" {
+		return nil, shared.InvalidArgument("YAML content is required")
+	}
+
+	projectID, err := resolveProjectID(ctx, client, args)
+	if err != nil {
+		return nil, err
+	}
+
+	dryRun, _ := args["dry_run"].(bool)
+
+	var desired desiredState
+	if err := yaml.Unmarshal([]byte(yamlContent), &desired); err != nil {
+		return nil, shared.InvalidArgument("Invalid YAML: %v", err)
+	}
+	for _, service := range desired.Services {
+		if service.Hostname == "" {
+			return nil, shared.InvalidArgument("Every service in the YAML needs a hostname")
+		}
+	}
+
+	project, err := searchProject(ctx, client, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	live, err := searchProjectServices(ctx, client, project)
+	if err != nil {
+		return nil, err
+	}
+
+	steps, unsupported, err := planApply(ctx, client, project, live, desired, yamlContent)
+	if err != nil {
+		return nil, err
+	}
+
+	result := projectApplyResult{
+		ProjectID:   projectID,
+		DryRun:      dryRun,
+		Steps:       steps,
+		Unsupported: unsupported,
+		InSync:      len(steps) == 0 && len(unsupported) == 0,
+	}
+
+	if dryRun {
+		preflight, problems := loadImportPreflight(ctx, client)
+		summary := importPreflightResult(append(preflight.check(yamlContent), problems...))
+		result.Preflight = &summary
+		result.Lint = lintImportYaml(yamlContent, project.TagList.Native(), nil)
+	}
+	if dryRun || len(steps) == 0 {
+		result.Message = fmt.Sprintf("%d change(s) planned, %d unsupported drift(s) reported.", len(steps), len(unsupported))
+		return result, nil
+	}
+
+	recordSnapshot(ctx, client, projectID, "project_apply", "")
+
+	applied, failed := 0, 0
+	for i := range steps {
+		if err := steps[i].run(ctx); err != nil {
+			steps[i].Status = "failed"
+			steps[i].Error = err.Error()
+			shared.Log(ctx, "warning", "project_apply", fmt.Sprintf("%s %s failed: %v", steps[i].Action, steps[i].Target, err))
+			failed++
+			continue
+		}
+		steps[i].Status = "applied"
+		applied++
+	}
+
+	result.Applied = &applied
+	result.Failed = &failed
+	result.Message = fmt.Sprintf("%d change(s) applied, %d failed, %d unsupported drift(s) reported. Restart services whose env changed and use get_running_processes to follow imports.", applied, failed, len(unsupported))
+	return result, nil
+}
+
+// planApply compares live state with the desired YAML and returns runnable steps plus unsupported drifts
+func planApply(ctx context.Context, client *sdk.Handler, project output.EsProject, live []output.EsServiceStack, desired desiredState, yamlContent string) ([]applyStep, []applyDrift, error) {
+	var steps []applyStep
+	var unsupported []applyDrift
+
+	// Project env
+	liveProjectEnv := make(map[string]output.ProjectEnv, len(project.EnvList))
+	for _, env := range project.EnvList {
+		liveProjectEnv[env.Key.Native()] = env
+	}
+	for _, key := range sortedMapKeys(desired.Project.EnvVariables) {
+		value := desired.Project.EnvVariables[key]
+		current, exists := liveProjectEnv[key]
+		switch {
+		case !exists:
+			steps = append(steps, applyStep{Action: "set_project_env", Target: key, Detail: "create", Status: "planned",
+				run: func(ctx context.Context) error {
+					resp, err := client.PostProjectEnv(ctx, body.ProjectEnvPost{ProjectId: project.Id, Key: types.NewString(key), Content: types.NewText(value)})
+					return outputErr(resp.Output, err)
+				}})
+		case current.Content.Native() != value:
+			steps = append(steps, applyStep{Action: "set_project_env", Target: key, Detail: "update value", Status: "planned",
+				run: func(ctx context.Context) error {
+					resp, err := client.PutProjectEnv(ctx, path.ProjectEnvId{Id: current.Id}, body.ProjectEnvPut{Key: types.NewString(key), Content: types.NewText(value), Sensitive: current.Sensitive})
+					return outputErr(resp.Output, err)
+				}})
+		}
+	}
+
+	// Services
+	liveByHostname := make(map[string]output.EsServiceStack, len(live))
+	for _, service := range live {
+		if !service.IsSystem.Native() {
+			liveByHostname[service.Name.Native()] = service
+		}
+	}
+
+	desiredHostnames := map[string]bool{}
+	var missing []string
+	for _, want := range desired.Services {
+		desiredHostnames[want.Hostname] = true
+		service, exists := liveByHostname[want.Hostname]
+		if !exists {
+			missing = append(missing, want.Hostname)
+			continue
+		}
+
+		if want.Type != "" && normalizeTypeVersion(want.Type) != normalizeTypeVersion(string(service.ServiceStackTypeVersionId)) {
+			unsupported = append(unsupported, applyDrift{
+				Service: want.Hostname, Field: "type", Desired: want.Type, Live: string(service.ServiceStackTypeVersionId),
+			})
+		}
+		if want.Mode != "" && service.Mode != nil && !strings.EqualFold(want.Mode, string(*service.Mode)) {
+			unsupported = append(unsupported, applyDrift{
+				Service: want.Hostname, Field: "mode", Desired: want.Mode, Live: string(*service.Mode),
+			})
+		}
+
+		envSteps, extraKeys, err := planServiceEnv(ctx, client, service, want)
+		if err != nil {
+			return nil, nil, err
+		}
+		steps = append(steps, envSteps...)
+		if len(extraKeys) > 0 {
+			unsupported = append(unsupported, applyDrift{
+				Service: want.Hostname, Field: "env", Detail: "keys not in YAML are kept", Keys: extraKeys,
+			})
+		}
+
+		if step, ok := planAutoscaling(client, service, want); ok {
+			steps = append(steps, step)
+		}
+	}
+
+	if len(missing) > 0 {
+		steps = append(steps, applyStep{Action: "import_services", Target: strings.Join(missing, ", "), Detail: "create missing services", Status: "planned",
+			run: func(ctx context.Context) error {
+				importYaml, err := filterImportYaml(yamlContent, missing)
+				if err != nil {
+					return err
+				}
+				resp, err := client.PostServiceStackImport(ctx, body.ServiceStackImport{ProjectId: project.Id, Yaml: types.NewText(importYaml)})
+				if err != nil {
+					return err
+				}
+				importOutput, err := resp.Output()
+				if err != nil {
+					return err
+				}
+				if report := buildImportReport(importOutput, missing); len(report.Failed) > 0 {
+					return fmt.Errorf("failed to create: %s", strings.Join(report.Failed, ", "))
+				}
+				return nil
+			}})
+	}
+
+	var extraServices []string
+	for hostname := range liveByHostname {
+		if !desiredHostnames[hostname] {
+			extraServices = append(extraServices, hostname)
+		}
+	}
+	sort.Strings(extraServices)
+	for _, hostname := range extraServices {
+		unsupported = append(unsupported, applyDrift{
+			Service: hostname, Field: "service", Detail: "not in YAML, not deleted",
+		})
+	}
+
+	return steps, unsupported, nil
+}
+
+// planServiceEnv plans env variable changes of one service and returns live keys missing from the YAML
+func planServiceEnv(ctx context.Context, client *sdk.Handler, service output.EsServiceStack, want desiredService) ([]applyStep, []string, error) {
+	if len(want.EnvVariables) == 0 && len(want.EnvSecrets) == 0 {
+		return nil, nil, nil
+	}
+
+	// Without the live env every key would be planned as a create, so a failed read fails the plan
+	liveEnv, err := serviceEnvByKey(ctx, client, service.Id)
+	if err != nil {
+		return nil, nil, shared.WrapAPIError(err, fmt.Sprintf("Failed to get environment variables of service %s", service.Name.Native()))
+	}
+
+	var steps []applyStep
+	target := service.Name.Native()
+	for _, key := range sortedMapKeys(want.EnvVariables) {
+		value := want.EnvVariables[key]
+		current, exists := liveEnv[key]
+		switch {
+		case !exists:
+			steps = append(steps, serviceEnvStep(client, service.Id, liveEnv, target, key, value, "create"))
+		case current.Content.Native() != value:
+			steps = append(steps, serviceEnvStep(client, service.Id, liveEnv, target, key, value, "update value"))
+		}
+	}
+	for _, key := range sortedMapKeys(want.EnvSecrets) {
+		if _, exists := liveEnv[key]; !exists {
+			steps = append(steps, serviceEnvStep(client, service.Id, liveEnv, target, key, want.EnvSecrets[key], "create"))
+		}
+	}
+
+	var extra []string
+	for key := range liveEnv {
+		_, inVariables := want.EnvVariables[key]
+		_, inSecrets := want.EnvSecrets[key]
+		if !inVariables && !inSecrets {
+			extra = append(extra, key)
+		}
+	}
+	sort.Strings(extra)
+	return steps, extra, nil
+}
+
+// serviceEnvStep plans setting one service env variable against the env read by the plan
+func serviceEnvStep(client *sdk.Handler, serviceID uuid.ServiceStackId, liveEnv map[string]output.ServiceStackEnv, target, key, value, detail string) applyStep {
+	return applyStep{Action: "set_service_env", Target: target + "/" + key, Detail: detail, Status: "planned",
+		run: func(ctx context.Context) error {
+			_, _, err := upsertServiceEnv(ctx, client, serviceID, liveEnv, key, value)
+			return err
+		}}
+}
+
+// planAutoscaling returns an autoscaling update when the YAML limits differ from the live ones
+func planAutoscaling(client *sdk.Handler, service output.EsServiceStack, want desiredService) (applyStep, bool) {
+	var liveHorizontal *output.HorizontalAutoscalingNullable
+	var liveVertical *output.VerticalAutoscalingNullable
+	if service.CustomAutoscaling != nil {
+		liveHorizontal = service.CustomAutoscaling.HorizontalAutoscalingNullable
+		liveVertical = service.CustomAutoscaling.VerticalAutoscalingNullable
+	}
+
+	var changes []string
+	custom := &body.CustomAutoscaling{}
+
+	if want.MinContainers != nil || want.MaxContainers != nil {
+		horizontal := &body.HorizontalAutoscalingNullable{}
+		if want.MinContainers != nil {
+			horizontal.MinContainerCount = types.NewIntNull(*want.MinContainers)
+			if liveHorizontal == nil || !intNullEquals(liveHorizontal.MinContainerCount, *want.MinContainers) {
+				changes = append(changes, fmt.Sprintf("minContainers=%d", *want.MinContainers))
+			}
+		}
+		if want.MaxContainers != nil {
+			horizontal.MaxContainerCount = types.NewIntNull(*want.MaxContainers)
+			if liveHorizontal == nil || !intNullEquals(liveHorizontal.MaxContainerCount, *want.MaxContainers) {
+				changes = append(changes, fmt.Sprintf("maxContainers=%d", *want.MaxContainers))
+			}
+		}
+		custom.HorizontalAutoscaling = horizontal
+	}
+
+	if v := want.VerticalAutoscaling; v != nil {
+		var liveMin, liveMax *output.ScalingResourceNullable
+		if liveVertical != nil {
+			liveMin, liveMax = liveVertical.MinResource, liveVertical.MaxResource
+		}
+		minResource, minChanges := desiredResource(liveMin, v.MinCpu, v.MinRam, v.MinDisk, "min")
+		maxResource, maxChanges := desiredResource(liveMax, v.MaxCpu, v.MaxRam, v.MaxDisk, "max")
+		custom.VerticalAutoscaling = &body.VerticalAutoscalingNullable{MinResource: minResource, MaxResource: maxResource}
+		changes = append(changes, minChanges...)
+		changes = append(changes, maxChanges...)
+	}
+
+	if len(changes) == 0 {
+		return applyStep{}, false
+	}
+
+	return applyStep{Action: "scale_service", Target: service.Name.Native(), Detail: strings.Join(changes, ", "), Status: "planned",
+		run: func(ctx context.Context) error {
+			resp, err := client.PutServiceStackAutoscaling(ctx, path.ServiceStackId{Id: service.Id}, body.Autoscaling{
+				Mode:              service.Mode,
+				CustomAutoscaling: custom,
+			})
+			return outputErr(resp.Output, err)
+		}}, true
+}
+
+// desiredResource builds a scaling resource from the YAML and lists the values that differ from live
+func desiredResource(live *output.ScalingResourceNullable, cpu *int, ram, disk *float64, prefix string) (*body.ScalingResourceNullable, []string) {
+	if cpu == nil && ram == nil && disk == nil {
+		return nil, nil
+	}
+
+	resource := &body.ScalingResourceNullable{}
+	var changes []string
+	if cpu != nil {
+		resource.CpuCoreCount = types.NewIntNull(*cpu)
+		if live == nil || !intNullEquals(live.CpuCoreCount, *cpu) {
+			changes = append(changes, fmt.Sprintf("%sCpu=%d", prefix, *cpu))
+		}
+	}
+	if ram != nil {
+		resource.MemoryGBytes = types.NewFloatNull(*ram)
+		if live == nil || !floatNullEquals(live.MemoryGBytes, *ram) {
+			changes = append(changes, fmt.Sprintf("%sRam=%g", prefix, *ram))
+		}
+	}
+	if disk != nil {
+		resource.DiskGBytes = types.NewFloatNull(*disk)
+		if live == nil || !floatNullEquals(live.DiskGBytes, *disk) {
+			changes = append(changes, fmt.Sprintf("%sDisk=%g", prefix, *disk))
+		}
+	}
+	return resource, changes
+}
+
+func intNullEquals(value types.IntNull, want int) bool {
+	v, ok := value.Get()
+	return ok && v.Native() == want
+}
+
+func floatNullEquals(value types.FloatNull, want float64) bool {
+	v, ok := value.Get()
+	return ok && v.Native() == want
+}
+
+// normalizeTypeVersion makes import types (nodejs@22) comparable with type version IDs (nodejs_22)
+func normalizeTypeVersion(typeVersion string) string {
+	return typeVersionSeparators.ReplaceAllString(strings.ToLower(typeVersion), "_")
+}
+
+// outputErr returns the transport error or the API error of an SDK response
+func outputErr[T any](output func() (T, error), err error) error {
+	if err != nil {
+		return err
+	}
+	_, err = output()
+	return err
+}
+
+// sortedMapKeys returns map keys in lexical order
+func sortedMapKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,358 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/dto/input/body"
+	"github.com/zeropsio/zerops-go/dto/output"
+	"github.com/zeropsio/zerops-go/sdk"
+	"github.com/zeropsio/zerops-go/types"
+	"github.com/zeropsio/zerops-go/types/enum"
+)
+
+// costPeriods are the billing periods project_cost reports; the Zerops API aggregates costs
+// for these periods only
+var costPeriods = []string{"today", "yesterday", "last_7_days", "last_30_days", "this_month", "last_month", "this_year"}
+
+// RegisterProjectCost registers the project_cost tool
+func RegisterProjectCost() {
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "project_cost",
+		Description: toolDescription("project_cost"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"project_id": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Project ID from discovery tool (default: $projectId)",
+					"pattern":     "^[A-Za-z0-9_-]+$",
+				},
+				"period": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Billing period (default: this_month)",
+					"enum":        costPeriods,
+				},
+				"timezone": timezoneProperty(),
+			},
+			"additionalProperties": false,
+		},
+		Annotations: shared.ReadOnly(),
+		Output:      projectCostResult{},
+		Handler:     handleProjectCost,
+	})
+}
+
+// serviceUsage is the resource consumption of one service over the period
+type serviceUsage struct {
+	samples  int
+	cpu      float64
+	ram      float64
+	disk     float64
+	builds   int
+	buildDur time.Duration
+}
+
+// projectCostResult is the result of get_project_cost
+type projectCostResult struct {
+	ProjectID                string        `json:"project_id"`
+	ProjectName              string        `json:"project_name"`
+	Period                   string        `json:"period"`
+	From                     string        `json:"from"`
+	Till                     string        `json:"till"`
+	TotalCost                float64       `json:"total_cost"`
+	Services                 []serviceCost `json:"services"`
+	OtherCost                float64       `json:"other_cost,omitempty"`
+	OrganizationDailyAverage float64       `json:"organization_daily_average,omitempty"`
+	Warnings                 []string      `json:"warnings,omitempty"`
+}
+
+// serviceCost is one service in get_project_cost
+type serviceCost struct {
+	ServiceID string        `json:"service_id"`
+	Hostname  string        `json:"hostname"`
+	Type      string        `json:"type"`
+	Cost      float64       `json:"cost"`
+	Usage     *usageSummary `json:"usage,omitempty"`
+}
+
+// usageSummary is the averaged resource usage and build time of a service
+type usageSummary struct {
+	AvgCPUCores  *float64 `json:"avg_cpu_cores,omitempty"`
+	AvgRAM       string   `json:"avg_ram,omitempty"`
+	AvgDisk      string   `json:"avg_disk,omitempty"`
+	Builds       int      `json:"builds,omitempty"`
+	BuildMinutes *float64 `json:"build_minutes,omitempty"`
+}
+
+func handleProjectCost(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+
+	projectID, err := resolveProjectID(ctx, client, args)
+	if err != nil {
+		return nil, err
+	}
+	period := "this_month"
+	if value, ok := args["period"].(string); ok && value != "" {
+		period = value
+	}
+	display, err := resolveDisplayFormat(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	from, till, err := costPeriodRange(period, time.Now().In(display.loc))
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := searchProject(ctx, client, projectID)
+	if err != nil {
+		return nil, err
+	}
+	services, err := searchProjectServices(ctx, client, project)
+	if err != nil {
+		return nil, err
+	}
+
+	costResp, err := client.PostTransactionDebitCostSearch(ctx, body.EsTransactionDebitCost{
+		Search: body.EsTransactionDebitCostSearch{
+			{Name: "clientId", Operator: "eq", Value: project.ClientId.TypedString()},
+			{Name: "projectId", Operator: "eq", Value: types.String(projectID)},
+		},
+	})
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to get project costs")
+	}
+	costs, err := costResp.Output()
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to get project costs")
+	}
+	serviceCosts := make(map[string]output.EsTransactionDebitPeriodCost, len(costs.Stack))
+	for _, item := range costs.Stack {
+		serviceCosts[item.Id.Native()] = item.PeriodCost
+	}
+
+	// Usage and build minutes are best effort: the cost breakdown is still useful without them
+	var warnings []string
+	usage, err := projectUsage(ctx, client, projectID, from, till, display)
+	if err != nil {
+		usage = map[string]*serviceUsage{}
+		warnings = append(warnings, "Resource usage is unavailable: "+err.Error())
+	}
+	if err := addBuildUsage(ctx, client, projectID, from, till, usage); err != nil {
+		warnings = append(warnings, "Build minutes are unavailable: "+err.Error())
+	}
+
+	total := 0.0
+	entries := make([]serviceCost, 0, len(services))
+	for _, service := range services {
+		id := string(service.Id)
+		cost := periodCost(serviceCosts[id], period)
+		total += cost
+		entry := serviceCost{
+			ServiceID: id,
+			Hostname:  service.Name.Native(),
+			Type:      service.ServiceStackTypeInfo.ServiceStackTypeVersionName.Native(),
+			Cost:      roundCost(cost),
+		}
+		if u, ok := usage[id]; ok {
+			entry.Usage = usageEntry(u, display)
+		}
+		entries = append(entries, entry)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Cost > entries[j].Cost
+	})
+
+	projectCost := total
+	for _, item := range costs.Project {
+		if item.Id.Native() == projectID {
+			projectCost = periodCost(item.PeriodCost, period)
+		}
+	}
+
+	result := projectCostResult{
+		ProjectID:   projectID,
+		ProjectName: project.Name.Native(),
+		Period:      period,
+		From:        formatTimestamp(from, display),
+		Till:        formatTimestamp(till, display),
+		TotalCost:   roundCost(projectCost),
+		Services:    entries,
+		Warnings:    warnings,
+	}
+	// The project total also covers costs of deleted services and project-level resources
+	if other := projectCost - total; other > 0.005 {
+		result.OtherCost = roundCost(other)
+	}
+	if avg := costs.Client.PeriodCost.AverageLast30Days.Native(); avg > 0 {
+		result.OrganizationDailyAverage = roundCost(avg)
+	}
+	return result, nil
+}
+
+// costPeriodRange returns the time range of a billing period ending now
+func costPeriodRange(period string, now time.Time) (time.Time, time.Time, error) {
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	switch period {
+	case "today":
+		return day, now, nil
+	case "yesterday":
+		return day.AddDate(0, 0, -1), day, nil
+	case "last_7_days":
+		return now.AddDate(0, 0, -7), now, nil
+	case "last_30_days":
+		return now.AddDate(0, 0, -30), now, nil
+	case "this_month":
+		return month, now, nil
+	case "last_month":
+		return month.AddDate(0, -1, 0), month, nil
+	case "this_year":
+		return time.Date(now.Year(), 1, 1, 0, 0, 0, 0, now.Location()), now, nil
+	}
+	return time.Time{}, time.Time{}, shared.InvalidArgument("Unknown period '%s'", period)
+}
+
+// periodCost picks the cost of a period from the API's aggregates
+func periodCost(cost output.EsTransactionDebitPeriodCost, period string) float64 {
+	switch period {
+	case "today":
+		return cost.Today.Native()
+	case "yesterday":
+		return cost.Yesterday.Native()
+	case "last_7_days":
+		return cost.Last7days.Native()
+	case "last_30_days":
+		return cost.Last30days.Native()
+	case "last_month":
+		return cost.LastMonth.Native()
+	case "this_year":
+		return cost.ThisYear.Native()
+	}
+	return cost.ThisMonth.Native()
+}
+
+func roundCost(cost float64) float64 {
+	return math.Round(cost*100) / 100
+}
+
+// projectUsage averages the CPU, RAM and disk used by each service over the period
+func projectUsage(ctx context.Context, client *sdk.Handler, projectID string, from, till time.Time, display *displayFormat) (map[string]*serviceUsage, error) {
+	history, err := searchStatsHistory(ctx, client, projectID, from, till, display.loc, "day")
+	if err != nil {
+		return nil, err
+	}
+
+	usage := map[string]*serviceUsage{}
+	for _, item := range history {
+		id := item.ServiceStackId.Native()
+		u, ok := usage[id]
+		if !ok {
+			u = &serviceUsage{}
+			usage[id] = u
+		}
+		u.samples++
+		u.cpu += item.CpuUsed.Native()
+		u.ram += item.RamUsed.Native()
+		u.disk += item.DiskUsed.Native()
+	}
+	return usage, nil
+}
+
+// searchStatsHistory loads the resource statistics of a project's services, one item per
+// service and timeGroupBy bucket (hour or day)
+func searchStatsHistory(ctx context.Context, client *sdk.Handler, projectID string, from, till time.Time, loc *time.Location, timeGroupBy string) ([]output.EsStatsHistory, error) {
+	return statsHistory(ctx, client, "projectId", projectID, enum.EsStatsHistoryGroupByEnumServiceStackId, from, till, loc, timeGroupBy)
+}
+
+// statsHistory loads resource statistics matching field == id, one item per groupBy value
+// and timeGroupBy bucket
+func statsHistory(ctx context.Context, client *sdk.Handler, field, id string, groupBy enum.EsStatsHistoryGroupByEnum, from, till time.Time, loc *time.Location, timeGroupBy string) ([]output.EsStatsHistory, error) {
+	value, _ := json.Marshal(id)
+	resp, err := client.PostStatsHistoryGroupBySearch(ctx, body.EsStatsHistoryFilter{
+		Search: body.EsStatsHistoryFilterSearch{
+			{Name: types.String(field), Operator: "eq", Value: types.NewJsonRawMessage(string(value))},
+		},
+		From:        types.NewDateTimeNull(from),
+		Till:        types.NewDateTimeNull(till),
+		TimeZone:    types.String(loc.String()),
+		GroupBy:     groupBy,
+		TimeGroupBy: types.String(timeGroupBy),
+	})
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to get resource usage")
+	}
+	history, err := resp.Output()
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to get resource usage")
+	}
+	return history.Items, nil
+}
+
+// addBuildUsage adds the builds of each service that started in the period and their
+// pipeline time, which is billed as build minutes
+func addBuildUsage(ctx context.Context, client *sdk.Handler, projectID string, from, till time.Time, usage map[string]*serviceUsage) error {
+	resp, err := client.PostAppVersionSearch(ctx, body.EsFilter{
+		Search: []body.EsSearchItem{
+			{Name: "projectId", Operator: "eq", Value: types.String(projectID)},
+			{Name: "created", Operator: "gte", Value: types.String(from.UTC().Format(time.RFC3339))},
+		},
+	})
+	if err != nil {
+		return shared.WrapAPIError(err, "Failed to search app versions")
+	}
+	versions, err := resp.Output()
+	if err != nil {
+		return shared.WrapAPIError(err, "Failed to search app versions")
+	}
+
+	for _, version := range versions.Items {
+		build := version.Build
+		if build == nil {
+			continue
+		}
+		start, ok := build.PipelineStart.Get()
+		if !ok || start.Native().Before(from) || start.Native().After(till) {
+			continue
+		}
+		end, ok := build.PipelineFinish.Get()
+		if !ok {
+			if end, ok = build.PipelineFailed.Get(); !ok {
+				continue
+			}
+		}
+		id := string(version.ServiceStackId)
+		u, ok := usage[id]
+		if !ok {
+			u = &serviceUsage{}
+			usage[id] = u
+		}
+		u.builds++
+		u.buildDur += end.Native().Sub(start.Native())
+	}
+	return nil
+}
+
+func usageEntry(u *serviceUsage, display *displayFormat) *usageSummary {
+	entry := &usageSummary{}
+	if u.samples > 0 {
+		samples := float64(u.samples)
+		cores := math.Round(u.cpu/samples*100) / 100
+		entry.AvgCPUCores = &cores
+		entry.AvgRAM = formatSize(u.ram/samples, display)
+		entry.AvgDisk = formatSize(u.disk/samples, display)
+	}
+	if u.builds > 0 {
+		minutes := math.Round(u.buildDur.Minutes()*10) / 10
+		entry.Builds = u.builds
+		entry.BuildMinutes = &minutes
+	}
+	return entry
+}
@@ -0,0 +1,184 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/sdk"
+)
+
+// RegisterProjectDiff registers the project_diff tool
+func RegisterProjectDiff() {
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "project_diff",
+		Description: toolDescription("project_diff"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"source_project_id": map[string]interface{}{
+					"type":        "string",
+					"description": "REQUIRED: Reference project ID (e.g. production)",
+					"pattern":     "^[A-Za-z0-9_-]+$",
+				},
+				"target_project_id": map[string]interface{}{
+					"type":        "string",
+					"description": "REQUIRED: Project ID compared against the source (e.g. staging)",
+					"pattern":     "^[A-Za-z0-9_-]+$",
+				},
+			},
+			"required":             []string{"source_project_id", "target_project_id"},
+			"additionalProperties": false,
+		},
+		Annotations: shared.ReadOnly(),
+		Output:      projectDiffResult{},
+		Handler:     handleProjectDiff,
+	})
+}
+
+// projectDiffResult is the result of project_diff
+type projectDiffResult struct {
+	SourceProjectID string      `json:"source_project_id"`
+	TargetProjectID string      `json:"target_project_id"`
+	Identical       bool        `json:"identical"`
+	Diff            projectDiff `json:"diff"`
+}
+
+// projectDiff lists what differs between two projects; fields are omitted when there is no difference
+type projectDiff struct {
+	ProjectEnv           *keyDifference         `json:"project_env,omitempty"`
+	ServicesOnlyInSource []string               `json:"services_only_in_source,omitempty"`
+	ServicesOnlyInTarget []string               `json:"services_only_in_target,omitempty"`
+	Services             map[string]serviceDiff `json:"services,omitempty"`
+}
+
+func (d projectDiff) empty() bool {
+	return d.ProjectEnv == nil && len(d.ServicesOnlyInSource) == 0 && len(d.ServicesOnlyInTarget) == 0 && len(d.Services) == 0
+}
+
+// serviceDiff lists what differs between two services with the same hostname
+type serviceDiff struct {
+	Type        *valueChange   `json:"type,omitempty"`
+	Env         *keyDifference `json:"env,omitempty"`
+	Autoscaling *valueChange   `json:"autoscaling,omitempty"`
+}
+
+// keyDifference holds keys present on only one side
+type keyDifference struct {
+	OnlyInSource []string `json:"only_in_source,omitempty"`
+	OnlyInTarget []string `json:"only_in_target,omitempty"`
+}
+
+// valueChange holds a value that differs between source and target
+type valueChange struct {
+	Source interface{} `json:"source"`
+	Target interface{} `json:"target"`
+}
+
+func handleProjectDiff(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+
+	sourceID, ok := args["source_project_id"].(string)
+	if !ok || sourceID == "" {
+		return nil, shared.InvalidArgument("Source project ID is required")
+	}
+
+	targetID, ok := args["target_project_id"].(string)
+	if !ok || targetID == "" {
+		return nil, shared.InvalidArgument("Target project ID is required")
+	}
+
+	source, err := captureState(ctx, client, sourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := captureState(ctx, client, targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := diffProjects(source, target)
+	return projectDiffResult{
+		SourceProjectID: sourceID,
+		TargetProjectID: targetID,
+		Identical:       diff.empty(),
+		Diff:            diff,
+	}, nil
+}
+
+// diffProjects compares two project states; keys are omitted when there is no difference
+func diffProjects(source, target stateSnapshot) projectDiff {
+	var diff projectDiff
+	onlyTarget, onlySource := diffKeys(source.ProjectEnvKeys, target.ProjectEnvKeys)
+	diff.ProjectEnv = keyDiff(onlySource, onlyTarget)
+
+	var servicesOnlySource, servicesOnlyTarget []string
+	services := map[string]serviceDiff{}
+	for hostname, sourceService := range source.Services {
+		targetService, ok := target.Services[hostname]
+		if !ok {
+			servicesOnlySource = append(servicesOnlySource, hostname)
+			continue
+		}
+
+		var changed serviceDiff
+		if sourceService.Type != targetService.Type {
+			changed.Type = &valueChange{
+				Source: sourceService.Type,
+				Target: targetService.Type,
+			}
+		}
+
+		envOnlyTarget, envOnlySource := diffKeys(sourceService.EnvKeys, targetService.EnvKeys)
+		changed.Env = keyDiff(envOnlySource, envOnlyTarget)
+
+		if sourceService.Autoscaling != targetService.Autoscaling {
+			changed.Autoscaling = &valueChange{
+				Source: decodeAutoscaling(sourceService.Autoscaling),
+				Target: decodeAutoscaling(targetService.Autoscaling),
+			}
+		}
+
+		if changed != (serviceDiff{}) {
+			services[hostname] = changed
+		}
+	}
+	for hostname := range target.Services {
+		if _, ok := source.Services[hostname]; !ok {
+			servicesOnlyTarget = append(servicesOnlyTarget, hostname)
+		}
+	}
+	sort.Strings(servicesOnlySource)
+	sort.Strings(servicesOnlyTarget)
+
+	diff.ServicesOnlyInSource = servicesOnlySource
+	diff.ServicesOnlyInTarget = servicesOnlyTarget
+	if len(services) > 0 {
+		diff.Services = services
+	}
+	return diff
+}
+
+// keyDiff builds the only_in_source/only_in_target pair, nil when both are empty
+func keyDiff(onlySource, onlyTarget []string) *keyDifference {
+	if len(onlySource) == 0 && len(onlyTarget) == 0 {
+		return nil
+	}
+	return &keyDifference{OnlyInSource: onlySource, OnlyInTarget: onlyTarget}
+}
+
+// decodeAutoscaling turns the JSON kept in snapshots back into an object, nil when not customized
+func decodeAutoscaling(autoscaling string) interface{} {
+	if autoscaling == "" {
+		return nil
+	}
+	var decoded interface{}
+	if err := json.Unmarshal([]byte(autoscaling), &decoded); err != nil {
+		return autoscaling
+	}
+	return decoded
+}
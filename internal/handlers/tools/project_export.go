@@ -0,0 +1,192 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/dto/input/path"
+	"github.com/zeropsio/zerops-go/sdk"
+	"github.com/zeropsio/zerops-go/types/uuid"
+	"gopkg.in/yaml.v3"
+)
+
+// secretPlaceholder replaces secret values in exports without secrets
+const secretPlaceholder = "REPLACE_ME"
+
+// RegisterProjectExport registers the project_export tool
+func RegisterProjectExport() {
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "project_export",
+		Description: toolDescription("project_export"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"project_id": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Project ID from discovery tool (default: $projectId)",
+					"pattern":     "^[A-Za-z0-9_-]+$",
+				},
+				"include_project": map[string]interface{}{
+					"type":        "boolean",
+					"description": "OPTIONAL: Include the project section; false gives a services-only YAML for import_services into an existing project (default: true)",
+					"default":     true,
+				},
+				"include_secrets": map[string]interface{}{
+					"type":        "boolean",
+					"description": "OPTIONAL: Export secret env values; otherwise secret keys are kept with the value " + secretPlaceholder + " (default: false)",
+					"default":     false,
+				},
+				"path": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Also write the YAML to this file (stdio mode only; must be inside the client's roots)",
+				},
+			},
+			"additionalProperties": false,
+		},
+		Annotations: shared.ReadOnly(),
+		Output:      projectExportResult{},
+		Handler:     handleProjectExport,
+	})
+}
+
+// projectExportResult is the result of project_export
+type projectExportResult struct {
+	ProjectID      string   `json:"project_id"`
+	ProjectName    string   `json:"project_name"`
+	Services       int      `json:"services"`
+	SecretsCopied  bool     `json:"secrets_copied"`
+	IncludeProject bool     `json:"include_project"`
+	YAML           string   `json:"yaml"`
+	SecretsToFill  []string `json:"secrets_to_fill,omitempty"`
+	Warnings       []string `json:"warnings,omitempty"`
+	Path           string   `json:"path,omitempty"`
+	Message        string   `json:"message"`
+}
+
+func handleProjectExport(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+
+	projectID, err := resolveProjectID(ctx, client, args)
+	if err != nil {
+		return nil, err
+	}
+	includeProject := true
+	if value, ok := args["include_project"].(bool); ok {
+		includeProject = value
+	}
+	includeSecrets, _ := args["include_secrets"].(bool)
+	filePath, _ := args["path"].(string)
+	if httpMode, _ := ctx.Value("httpMode").(bool); httpMode && filePath != "" {
+		return nil, shared.InvalidArgument("path is not available in HTTP mode: the server's filesystem is not yours. Save the returned yaml instead.")
+	}
+
+	project, err := searchProject(ctx, client, projectID)
+	if err != nil {
+		return nil, err
+	}
+	services, err := searchProjectServices(ctx, client, project)
+	if err != nil {
+		return nil, err
+	}
+
+	exportResp, err := client.GetProjectExport(ctx, path.ProjectId{Id: uuid.ProjectId(projectID)})
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to export project")
+	}
+	exportOutput, err := exportResp.Output()
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to export project")
+	}
+
+	var export map[string]interface{}
+	if err := yaml.Unmarshal([]byte(exportOutput.Yaml.Native()), &export); err != nil {
+		return nil, shared.NewToolError(shared.ErrAPIUnavailable, "Failed to parse project export: %v", err)
+	}
+	exported, _ := export["services"].([]interface{})
+
+	var secretsToFill []string
+	exportedHostnames := map[string]bool{}
+	for _, item := range exported {
+		service, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		hostname, _ := service["hostname"].(string)
+		exportedHostnames[hostname] = true
+		if !includeSecrets {
+			for _, key := range maskExportSecrets(service) {
+				secretsToFill = append(secretsToFill, hostname+"."+key)
+			}
+		}
+	}
+	if !includeProject {
+		delete(export, "project")
+	}
+
+	// The export skips services Zerops can't re-create from YAML, e.g. ones still being created
+	var warnings []string
+	for _, service := range services {
+		if hostname := service.Name.Native(); !exportedHostnames[hostname] {
+			warnings = append(warnings, fmt.Sprintf("Service '%s' (%s) is not in the export", hostname, string(service.Status)))
+		}
+	}
+
+	out, err := yaml.Marshal(export)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build export YAML: %w", err)
+	}
+
+	sort.Strings(secretsToFill)
+	result := projectExportResult{
+		ProjectID:      projectID,
+		ProjectName:    project.Name.Native(),
+		Services:       len(exported),
+		SecretsCopied:  includeSecrets,
+		IncludeProject: includeProject,
+		YAML:           string(out),
+		SecretsToFill:  secretsToFill,
+		Warnings:       warnings,
+	}
+	if filePath != "" {
+		written, err := writeLocalFile(ctx, filePath, out)
+		if err != nil {
+			return nil, err
+		}
+		result.Path = written
+	}
+	if includeProject {
+		result.Message = "Re-create the project with import_services on a new project, or version the YAML. Fill secrets_to_fill first."
+	} else {
+		result.Message = "Import the services into an existing project with import_services. Fill secrets_to_fill first."
+	}
+	return result, nil
+}
+
+// maskExportSecrets replaces the secret values of an exported service with secretPlaceholder,
+// keeping the keys, and returns the masked keys
+func maskExportSecrets(service map[string]interface{}) []string {
+	var masked []string
+	if secrets, ok := service["envSecrets"].(map[string]interface{}); ok {
+		for key := range secrets {
+			secrets[key] = secretPlaceholder
+			masked = append(masked, key)
+		}
+	}
+	if dotenv, ok := service["dotEnvSecrets"].(string); ok {
+		var lines []string
+		for _, entry := range parseDotenv(dotenv) {
+			if entry.err != "" {
+				continue
+			}
+			lines = append(lines, entry.key+"="+secretPlaceholder)
+			masked = append(masked, entry.key)
+		}
+		service["dotEnvSecrets"] = strings.Join(lines, "\n") + "\n"
+	}
+	return masked
+}
@@ -0,0 +1,257 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/dto/input/body"
+	"github.com/zeropsio/zerops-go/dto/output"
+	"github.com/zeropsio/zerops-go/sdk"
+	"github.com/zeropsio/zerops-go/types"
+)
+
+// RegisterProjectTags registers the list_project_tags, add_project_tags and remove_project_tags tools
+func RegisterProjectTags() {
+	projectIDProperty := map[string]interface{}{
+		"type":        "string",
+		"description": "OPTIONAL: Project ID from discovery tool (default: $projectId)",
+		"pattern":     "^[A-Za-z0-9_-]+$",
+	}
+	tagsProperty := func(description string) map[string]interface{} {
+		return map[string]interface{}{
+			"type":        "array",
+			"description": description,
+			"items":       map[string]interface{}{"type": "string"},
+			"minItems":    1,
+		}
+	}
+
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "list_project_tags",
+		Description: toolDescription("list_project_tags"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"project_id": projectIDProperty,
+			},
+			"additionalProperties": false,
+		},
+		Annotations: shared.ReadOnly(),
+		Output:      listProjectTagsResult{},
+		Handler:     handleListProjectTags,
+	})
+
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "add_project_tags",
+		Description: toolDescription("add_project_tags"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"project_id": projectIDProperty,
+				"tags":       tagsProperty("REQUIRED: Tags to add, e.g. [\"prod\", \"team-web\"]; tags the project already has are skipped"),
+			},
+			"required":             []string{"tags"},
+			"additionalProperties": false,
+		},
+		Annotations: shared.Mutating(false, true),
+		Output:      addProjectTagsResult{},
+		Handler:     handleAddProjectTags,
+	})
+
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "remove_project_tags",
+		Description: toolDescription("remove_project_tags"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"project_id": projectIDProperty,
+				"tags":       tagsProperty("REQUIRED: Tags to remove; tags the project doesn't have are reported in not_found"),
+			},
+			"required":             []string{"tags"},
+			"additionalProperties": false,
+		},
+		Annotations: shared.Mutating(false, true),
+		Output:      removeProjectTagsResult{},
+		Handler:     handleRemoveProjectTags,
+	})
+}
+
+// listProjectTagsResult is the result of list_project_tags
+type listProjectTagsResult struct {
+	ProjectID        string   `json:"project_id"`
+	ProjectName      string   `json:"project_name"`
+	Tags             []string `json:"tags"`
+	OrganizationTags []string `json:"organization_tags,omitempty"`
+	Warnings         []string `json:"warnings,omitempty"`
+}
+
+// projectTagsChange is the part of the add_project_tags and remove_project_tags results they share
+type projectTagsChange struct {
+	ProjectID   string   `json:"project_id"`
+	ProjectName string   `json:"project_name"`
+	Tags        []string `json:"tags"`
+	Status      string   `json:"status"`
+	Message     string   `json:"message,omitempty"`
+}
+
+// addProjectTagsResult is the result of add_project_tags
+type addProjectTagsResult struct {
+	projectTagsChange
+	Added          []string `json:"added"`
+	AlreadyPresent []string `json:"already_present,omitempty"`
+}
+
+// removeProjectTagsResult is the result of remove_project_tags
+type removeProjectTagsResult struct {
+	projectTagsChange
+	Removed  []string `json:"removed"`
+	NotFound []string `json:"not_found,omitempty"`
+}
+
+func handleListProjectTags(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+
+	projectID, err := resolveProjectID(ctx, client, args)
+	if err != nil {
+		return nil, err
+	}
+	project, err := searchProject(ctx, client, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := listProjectTagsResult{
+		ProjectID:   projectID,
+		ProjectName: project.Name.Native(),
+		Tags:        projectTags(project.TagList),
+	}
+
+	// The organization's tags help reuse existing labels; the project's tags are listed without them
+	tagResp, err := client.PostProjectTagList(ctx, body.PostProjectTagList{ClientId: project.ClientId})
+	if err == nil {
+		var tagOutput output.TagList
+		if tagOutput, err = tagResp.Output(); err == nil {
+			organizationTags := projectTags(tagOutput.Items)
+			sort.Strings(organizationTags)
+			result.OrganizationTags = organizationTags
+		}
+	}
+	if err != nil {
+		result.Warnings = []string{"Organization tags unavailable: " + err.Error()}
+	}
+	return result, nil
+}
+
+func handleAddProjectTags(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	return changeProjectTags(ctx, client, args, true)
+}
+
+func handleRemoveProjectTags(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	return changeProjectTags(ctx, client, args, false)
+}
+
+// changeProjectTags adds or removes the tags argument. Tags keep their order; added ones go last.
+func changeProjectTags(ctx context.Context, client *sdk.Handler, args map[string]interface{}, add bool) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+
+	projectID, err := resolveProjectID(ctx, client, args)
+	if err != nil {
+		return nil, err
+	}
+	requested, err := stringListArg(args, "tags")
+	if err != nil {
+		return nil, err
+	}
+	if len(requested) == 0 {
+		return nil, shared.InvalidArgument("tags is required")
+	}
+
+	project, err := searchProject(ctx, client, projectID)
+	if err != nil {
+		return nil, err
+	}
+	current := projectTags(project.TagList)
+	has := make(map[string]bool, len(current))
+	for _, tag := range current {
+		has[tag] = true
+	}
+
+	changed := []string{}
+	var skipped []string
+	tags := current
+	if add {
+		for _, tag := range requested {
+			tag = strings.TrimSpace(tag)
+			if has[tag] {
+				skipped = append(skipped, tag)
+				continue
+			}
+			has[tag] = true
+			tags = append(tags, tag)
+			changed = append(changed, tag)
+		}
+	} else {
+		remove := map[string]bool{}
+		for _, tag := range requested {
+			tag = strings.TrimSpace(tag)
+			if !has[tag] {
+				skipped = append(skipped, tag)
+				continue
+			}
+			if !remove[tag] {
+				remove[tag] = true
+				changed = append(changed, tag)
+			}
+		}
+		tags = make([]string, 0, len(current))
+		for _, tag := range current {
+			if !remove[tag] {
+				tags = append(tags, tag)
+			}
+		}
+	}
+
+	action, verb := "add_project_tags", "added"
+	if !add {
+		action, verb = "remove_project_tags", "removed"
+	}
+	change := projectTagsChange{
+		ProjectID:   projectID,
+		ProjectName: project.Name.Native(),
+		Tags:        tags,
+		Status:      "unchanged",
+	}
+	result := func() interface{} {
+		if add {
+			return addProjectTagsResult{projectTagsChange: change, Added: changed, AlreadyPresent: skipped}
+		}
+		return removeProjectTagsResult{projectTagsChange: change, Removed: changed, NotFound: skipped}
+	}
+	if len(changed) == 0 {
+		return result(), nil
+	}
+
+	recordSnapshot(ctx, client, projectID, action, strings.Join(changed, ","))
+
+	if err := updateProject(ctx, client, project, "Failed to update project tags", func(update *body.PutProject) {
+		update.TagList = types.NewStringArray(tags)
+	}); err != nil {
+		return nil, err
+	}
+
+	change.Status = "updated"
+	change.Message = fmt.Sprintf("Project '%s' %s %d tag(s); filter projects by tag with discover_all.", project.Name.Native(), verb, len(changed))
+	return result(), nil
+}
+
+// projectTags returns the tags of a project, never nil so results always carry a list
+func projectTags(tagList types.StringArray) []string {
+	return append(make([]string, 0, len(tagList)), tagList.Native()...)
+}
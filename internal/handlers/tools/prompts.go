@@ -0,0 +1,95 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+)
+
+// RegisterPrompts registers the guided workflows as MCP prompts. They render the same
+// steps as load_platform_guide, filled in with the given project or service.
+func RegisterPrompts() {
+	shared.GlobalPrompts.Register(&shared.PromptDefinition{
+		Name:        "fresh_project",
+		Description: "Set up services in an empty project, from picking types to public URLs",
+		Arguments: []shared.PromptArgument{
+			{Name: "project_id", Description: "Project to set up", Required: true},
+			{Name: "runtime", Description: "Runtime of the app, e.g. nodejs or go (default: nodejs)"},
+		},
+		Handler: func(args map[string]string) (string, error) {
+			intro := fmt.Sprintf("Set up project %s. Pass project_id: '%s' to every tool that takes one.", args["project_id"], args["project_id"])
+			replacements := map[string]string{"project_id": args["project_id"]}
+			if runtime := args["runtime"]; runtime != "" {
+				replacements["knowledge_base"] = runtime
+			}
+			return workflowPrompt("fresh_project", intro, replacements), nil
+		},
+	})
+
+	shared.GlobalPrompts.Register(&shared.PromptDefinition{
+		Name:        "add_service",
+		Description: "Add a service to an existing project and wire it to the services already there",
+		Arguments: []shared.PromptArgument{
+			{Name: "project_id", Description: "Project to add the service to", Required: true},
+			{Name: "service_type", Description: "Type of the new service, e.g. postgresql or valkey"},
+		},
+		Handler: func(args map[string]string) (string, error) {
+			intro := fmt.Sprintf("Add a service to project %s. Pass project_id: '%s' to every tool that takes one.", args["project_id"], args["project_id"])
+			replacements := map[string]string{"project_id": args["project_id"]}
+			if serviceType := args["service_type"]; serviceType != "" {
+				intro += fmt.Sprintf(" The new service is of type %s.", serviceType)
+				replacements["knowledge_base"] = serviceType
+			}
+			return workflowPrompt("add_services", intro, replacements), nil
+		},
+	})
+
+	shared.GlobalPrompts.Register(&shared.PromptDefinition{
+		Name:        "debug_deploy",
+		Description: "Find out why the latest deploy of a service failed and get it running again",
+		Arguments: []shared.PromptArgument{
+			{Name: "service_id", Description: "Service whose deploy failed", Required: true},
+		},
+		Handler: func(args map[string]string) (string, error) {
+			intro := fmt.Sprintf("The latest deploy of service %s failed or the service does not come up. Find the cause before changing anything.", args["service_id"])
+			return workflowPrompt("debug_deploy", intro, map[string]string{"service_id": args["service_id"]}), nil
+		},
+	})
+}
+
+// workflowPrompt renders a platform workflow as prompt text. The "id: '...'" placeholders of
+// the steps are filled from replacements; a knowledge_base replacement names the query.
+func workflowPrompt(pathType, intro string, replacements map[string]string) string {
+	workflow := platformWorkflows[pathType]
+
+	var text strings.Builder
+	text.WriteString("# " + workflow.title + "\n\n")
+	text.WriteString(intro + "\n\n")
+	text.WriteString("Follow these steps in order and check each result before moving on:\n\n")
+	for _, step := range workflow.steps {
+		for name, value := range replacements {
+			if name == "knowledge_base" {
+				step = replaceKnowledgeQuery(step, value)
+				continue
+			}
+			step = strings.ReplaceAll(step, name+": '...'", name+": '"+value+"'")
+		}
+		text.WriteString(step + "\n")
+	}
+	return text.String()
+}
+
+// replaceKnowledgeQuery swaps the example query of a knowledge_base step
+func replaceKnowledgeQuery(step, query string) string {
+	start := strings.Index(step, "knowledge_base('")
+	if start < 0 {
+		return step
+	}
+	start += len("knowledge_base('")
+	end := strings.Index(step[start:], "'")
+	if end < 0 {
+		return step
+	}
+	return step[:start] + query + step[start+end:]
+}
@@ -0,0 +1,282 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/dto/input/query"
+	"github.com/zeropsio/zerops-go/sdk"
+	"github.com/zeropsio/zerops-go/types"
+	"gopkg.in/yaml.v3"
+)
+
+// recipeRepoPrefix turns a recipe ID into its repository URL
+const recipeRepoPrefix = "https://github.com/zeropsio/recipe-"
+
+// managedServiceTypes are service types a recipe depends on rather than deploys code to
+var managedServiceTypes = map[string]bool{
+	"postgresql": true, "mariadb": true, "mysql": true, "mongodb": true,
+	"valkey": true, "keydb": true, "redis": true, "clickhouse": true,
+	"elasticsearch": true, "meilisearch": true, "typesense": true, "qdrant": true,
+	"nats": true, "kafka": true, "rabbitmq": true,
+	"object-storage": true, "shared-storage": true,
+}
+
+// RegisterRecipeFit registers the check_recipe_fit tool
+func RegisterRecipeFit() {
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "check_recipe_fit",
+		Description: toolDescription("check_recipe_fit"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"recipe": map[string]interface{}{
+					"type":        "string",
+					"description": "REQUIRED: Recipe ID (e.g. 'nodejs-hello-world') or recipe repository URL",
+				},
+				"project_id": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Project ID to check against. Defaults to $projectId.",
+					"pattern":     "^[A-Za-z0-9_-]+$",
+				},
+				"environment": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Recipe environment name (default: first one listed by the recipe)",
+				},
+			},
+			"required":             []string{"recipe"},
+			"additionalProperties": false,
+		},
+		Annotations: shared.ReadOnly(),
+		Output:      recipeFitResult{},
+		Handler:     handleCheckRecipeFit,
+	})
+}
+
+// recipeFitResult is the result of check_recipe_fit
+type recipeFitResult struct {
+	Recipe       string             `json:"recipe"`
+	RecipeURL    string             `json:"recipe_url"`
+	ProjectID    string             `json:"project_id"`
+	Environment  string             `json:"environment"`
+	Environments []string           `json:"environments"`
+	Fits         bool               `json:"fits"`
+	Collisions   []recipeCollision  `json:"collisions"`
+	Satisfied    []recipeDependency `json:"satisfied"`
+	Resources    recipeResources    `json:"resources"`
+	YAML         string             `json:"yaml"`
+	Message      string             `json:"message"`
+}
+
+// recipeCollision is a recipe hostname that already exists in the project
+type recipeCollision struct {
+	Hostname     string `json:"hostname"`
+	RecipeType   string `json:"recipe_type"`
+	ExistingType string `json:"existing_type"`
+}
+
+// recipeDependency is a managed recipe service the project already has a service of the same type for
+type recipeDependency struct {
+	RecipeHostname   string   `json:"recipe_hostname"`
+	Type             string   `json:"type"`
+	ExistingServices []string `json:"existing_services"`
+}
+
+// recipeResources sums up what importing the recipe would create
+type recipeResources struct {
+	NewServices   int             `json:"new_services"`
+	MinContainers int             `json:"min_containers"`
+	HAServices    int             `json:"ha_services"`
+	Services      []recipeService `json:"services"`
+}
+
+// recipeService is one service the recipe would create
+type recipeService struct {
+	Hostname            string      `json:"hostname"`
+	Type                string      `json:"type"`
+	Mode                string      `json:"mode,omitempty"`
+	MinContainers       int         `json:"min_containers"`
+	MaxContainers       *int        `json:"max_containers,omitempty"`
+	VerticalAutoscaling interface{} `json:"vertical_autoscaling,omitempty"`
+}
+
+func handleCheckRecipeFit(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+
+	recipe, ok := args["recipe"].(string)
+	if !ok || strings.TrimSpace(recipe) == "" {
+		return nil, shared.InvalidArgument("Recipe is required")
+	}
+
+	projectID, err := resolveProjectID(ctx, client, args)
+	if err != nil {
+		return nil, err
+	}
+
+	recipeURL := recipe
+	if !strings.Contains(recipe, "://") {
+		recipeURL = recipeRepoPrefix + strings.TrimPrefix(recipe, "recipe-")
+	}
+
+	recipeResp, err := client.GetRecipeInfo(ctx, query.GetRecipeInfo{Url: types.NewString(recipeURL)})
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to get recipe")
+	}
+
+	recipeOutput, err := recipeResp.Output()
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to get recipe")
+	}
+
+	if len(recipeOutput.ZeropsImportYaml) == 0 {
+		return nil, shared.NotFound("Recipe '%s' has no import YAML", recipe)
+	}
+
+	environments := make([]string, 0, len(recipeOutput.ZeropsImportYaml))
+	selected := recipeOutput.ZeropsImportYaml[0]
+	environment, _ := args["environment"].(string)
+	found := environment == ""
+	for _, item := range recipeOutput.ZeropsImportYaml {
+		environments = append(environments, item.Name.Native())
+		if environment != "" && strings.EqualFold(item.Name.Native(), environment) {
+			selected = item
+			found = true
+		}
+	}
+	if !found {
+		return nil, shared.InvalidArgument("Recipe has no environment '%s'. Available: %s", environment, strings.Join(environments, ", "))
+	}
+
+	importYaml := selected.Content.Native()
+	recipeServices, err := importYamlServices(importYaml)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := searchProject(ctx, client, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := searchProjectServices(ctx, client, project)
+	if err != nil {
+		return nil, err
+	}
+
+	existingByHostname := map[string]string{}
+	existingByType := map[string][]string{}
+	for _, service := range existing {
+		if service.IsSystem.Native() {
+			continue
+		}
+		typeVersion := string(service.ServiceStackTypeVersionId)
+		existingByHostname[service.Name.Native()] = typeVersion
+		base := serviceTypeBase(typeVersion)
+		existingByType[base] = append(existingByType[base], service.Name.Native())
+	}
+
+	var collisions []recipeCollision
+	var satisfied []recipeDependency
+	var newServices []recipeService
+	minContainers, haServices := 0, 0
+
+	for _, service := range recipeServices {
+		hostname, _ := service["hostname"].(string)
+		serviceType, _ := service["type"].(string)
+		base := serviceTypeBase(serviceType)
+
+		if existingType, ok := existingByHostname[hostname]; ok {
+			collisions = append(collisions, recipeCollision{
+				Hostname:     hostname,
+				RecipeType:   serviceType,
+				ExistingType: existingType,
+			})
+		}
+
+		if managedServiceTypes[base] && len(existingByType[base]) > 0 {
+			satisfied = append(satisfied, recipeDependency{
+				RecipeHostname:   hostname,
+				Type:             serviceType,
+				ExistingServices: existingByType[base],
+			})
+		}
+
+		info := recipeService{
+			Hostname: hostname,
+			Type:     serviceType,
+		}
+		containers := 1
+		if mode, ok := service["mode"].(string); ok {
+			info.Mode = mode
+			if strings.EqualFold(mode, "HA") {
+				haServices++
+				containers = 3
+			}
+		}
+		if min, ok := service["minContainers"].(int); ok && min > containers {
+			containers = min
+		}
+		if max, ok := service["maxContainers"].(int); ok {
+			info.MaxContainers = &max
+		}
+		if vertical, ok := service["verticalAutoscaling"]; ok {
+			info.VerticalAutoscaling = vertical
+		}
+		info.MinContainers = containers
+		minContainers += containers
+		newServices = append(newServices, info)
+	}
+
+	fits := len(collisions) == 0
+	result := recipeFitResult{
+		Recipe:       recipe,
+		RecipeURL:    recipeURL,
+		ProjectID:    projectID,
+		Environment:  selected.Name.Native(),
+		Environments: environments,
+		Fits:         fits,
+		Collisions:   collisions,
+		Satisfied:    satisfied,
+		Resources: recipeResources{
+			NewServices:   len(newServices),
+			MinContainers: minContainers,
+			HAServices:    haServices,
+			Services:      newServices,
+		},
+		YAML: importYaml,
+	}
+
+	switch {
+	case !fits:
+		result.Message = fmt.Sprintf("%d hostname collision(s). Rename them in the YAML before import_services.", len(collisions))
+	case len(satisfied) > 0:
+		result.Message = "No collisions. Some dependencies already exist; consider removing them from the YAML and reusing the existing services."
+	default:
+		result.Message = "No collisions. The recipe can be imported with import_services."
+	}
+
+	return result, nil
+}
+
+// importYamlServices parses the services list of an import YAML
+func importYamlServices(importYaml string) ([]map[string]interface{}, error) {
+	var doc struct {
+		Services []map[string]interface{} `yaml:"services"`
+	}
+	if err := yaml.Unmarshal([]byte(importYaml), &doc); err != nil {
+		return nil, shared.InvalidArgument("Invalid import YAML: %v", err)
+	}
+	return doc.Services, nil
+}
+
+// serviceTypeBase strips the version from an import type (postgresql@16) or type version ID (postgresql_16)
+func serviceTypeBase(serviceType string) string {
+	if i := strings.IndexAny(serviceType, "@_"); i >= 0 {
+		serviceType = serviceType[:i]
+	}
+	return strings.ToLower(serviceType)
+}
@@ -0,0 +1,209 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/sdk"
+)
+
+const (
+	// regionProbeAttempts is how many connections are timed per region; the fastest counts
+	regionProbeAttempts = 3
+	// regionProbeTimeout bounds a single connection attempt
+	regionProbeTimeout = 3 * time.Second
+)
+
+// regionLocation is where the data center of a region is
+type regionLocation struct {
+	City        string
+	Country     string
+	CountryCode string
+}
+
+// regionLocations maps the location code that region names start with (prg1 -> prg) to the
+// data center's city and country. Regions with an unknown code are listed without location.
+var regionLocations = map[string]regionLocation{
+	"prg": {City: "Prague", Country: "Czechia", CountryCode: "CZ"},
+	"fra": {City: "Frankfurt", Country: "Germany", CountryCode: "DE"},
+	"ams": {City: "Amsterdam", Country: "Netherlands", CountryCode: "NL"},
+	"lon": {City: "London", Country: "United Kingdom", CountryCode: "GB"},
+	"par": {City: "Paris", Country: "France", CountryCode: "FR"},
+	"waw": {City: "Warsaw", Country: "Poland", CountryCode: "PL"},
+	"vie": {City: "Vienna", Country: "Austria", CountryCode: "AT"},
+	"nyc": {City: "New York", Country: "United States", CountryCode: "US"},
+	"sfo": {City: "San Francisco", Country: "United States", CountryCode: "US"},
+	"tor": {City: "Toronto", Country: "Canada", CountryCode: "CA"},
+	"sin": {City: "Singapore", Country: "Singapore", CountryCode: "SG"},
+	"syd": {City: "Sydney", Country: "Australia", CountryCode: "AU"},
+	"blr": {City: "Bangalore", Country: "India", CountryCode: "IN"},
+}
+
+// RegisterRegions registers the list_regions tool
+func RegisterRegions() {
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "list_regions",
+		Description: toolDescription("list_regions"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"probe_latency": map[string]interface{}{
+					"type":        "boolean",
+					"description": "OPTIONAL: Measure the connection time from this server to each region and report the closest (default: false)",
+					"default":     false,
+				},
+			},
+			"additionalProperties": false,
+		},
+		Annotations: shared.ReadOnly(),
+		Output:      listRegionsResult{},
+		Handler:     handleListRegions,
+	})
+}
+
+// listRegionsResult is the result of list_regions
+type listRegionsResult struct {
+	Regions  []regionEntry `json:"regions"`
+	Count    int           `json:"count"`
+	Default  string        `json:"default"`
+	Closest  string        `json:"closest,omitempty"`
+	Message  string        `json:"message,omitempty"`
+	Warnings []string      `json:"warnings,omitempty"`
+}
+
+// regionEntry is one region in list_regions; LatencyMs is set for probed regions
+type regionEntry struct {
+	Name        string `json:"name"`
+	IsDefault   bool   `json:"is_default"`
+	Address     string `json:"address"`
+	City        string `json:"city,omitempty"`
+	Country     string `json:"country,omitempty"`
+	CountryCode string `json:"country_code,omitempty"`
+	LatencyMs   *int64 `json:"latency_ms,omitempty"`
+}
+
+func handleListRegions(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+	probe, _ := args["probe_latency"].(bool)
+
+	resp, err := client.GetRegion(ctx)
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to list regions")
+	}
+	regionOutput, err := resp.Output()
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to list regions")
+	}
+
+	regions := make([]regionEntry, 0, len(regionOutput.Items))
+	addresses := make([]string, 0, len(regionOutput.Items))
+	defaultRegion := ""
+	for _, region := range regionOutput.Items {
+		name := region.Name.Native()
+		entry := regionEntry{
+			Name:      name,
+			IsDefault: region.IsDefault.Native(),
+			Address:   region.Address.Native(),
+		}
+		if location, ok := regionLocations[strings.TrimRight(strings.ToLower(name), "0123456789")]; ok {
+			entry.City = location.City
+			entry.Country = location.Country
+			entry.CountryCode = location.CountryCode
+		}
+		if region.IsDefault.Native() {
+			defaultRegion = name
+		}
+		regions = append(regions, entry)
+		addresses = append(addresses, region.Address.Native())
+	}
+
+	result := listRegionsResult{
+		Regions: regions,
+		Count:   len(regions),
+		Default: defaultRegion,
+	}
+	if !probe || len(regions) == 0 {
+		return result, nil
+	}
+
+	// Regions are probed concurrently so the call takes as long as the slowest region
+	latencies := make([]time.Duration, len(addresses))
+	errs := make([]error, len(addresses))
+	var wg sync.WaitGroup
+	for i, address := range addresses {
+		wg.Add(1)
+		go func(i int, address string) {
+			defer wg.Done()
+			latencies[i], errs[i] = probeRegion(ctx, address)
+		}(i, address)
+	}
+	wg.Wait()
+
+	var warnings []string
+	for i := range regions {
+		if errs[i] != nil {
+			warnings = append(warnings, fmt.Sprintf("%s: %v", regions[i].Name, errs[i]))
+			continue
+		}
+		latency := latencies[i].Milliseconds()
+		regions[i].LatencyMs = &latency
+	}
+	// Probed regions come first, fastest first
+	sort.SliceStable(regions, func(i, j int) bool {
+		a, b := regions[i].LatencyMs, regions[j].LatencyMs
+		if (a != nil) != (b != nil) {
+			return a != nil
+		}
+		return a != nil && *a < *b
+	})
+	if closest := regions[0]; closest.LatencyMs != nil {
+		result.Closest = closest.Name
+		result.Message = fmt.Sprintf("%s is the closest region to this server (%d ms). Latency is measured from the MCP server, not from your users.", closest.Name, *closest.LatencyMs)
+	}
+	result.Warnings = warnings
+	return result, nil
+}
+
+// probeRegion returns the fastest of a few TCP connections to a region's address
+func probeRegion(ctx context.Context, address string) (time.Duration, error) {
+	host := address
+	if parsed, err := url.Parse(address); err == nil && parsed.Host != "" {
+		host = parsed.Host
+	}
+	if host == "" {
+		return 0, fmt.Errorf("region has no address")
+	}
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		host = net.JoinHostPort(host, "443")
+	}
+
+	dialer := net.Dialer{Timeout: regionProbeTimeout}
+	var best time.Duration
+	var lastErr error
+	for attempt := 0; attempt < regionProbeAttempts; attempt++ {
+		start := time.Now()
+		conn, err := dialer.DialContext(ctx, "tcp", host)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		elapsed := time.Since(start)
+		conn.Close()
+		if best == 0 || elapsed < best {
+			best = elapsed
+		}
+	}
+	if best == 0 {
+		return 0, lastErr
+	}
+	return best, nil
+}
@@ -0,0 +1,298 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/dto/input/body"
+	"github.com/zeropsio/zerops-go/dto/input/path"
+	"github.com/zeropsio/zerops-go/dto/output"
+	"github.com/zeropsio/zerops-go/sdk"
+	"github.com/zeropsio/zerops-go/types"
+)
+
+// maxProjectNameLength is the longest project name accepted
+const maxProjectNameLength = 255
+
+// RegisterRename registers the project_rename and service_rename tools
+func RegisterRename() {
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "project_rename",
+		Description: toolDescription("project_rename"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"project_id": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Project ID from discovery tool (default: $projectId)",
+					"pattern":     "^[A-Za-z0-9_-]+$",
+				},
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": "REQUIRED: New project name",
+					"minLength":   1,
+					"maxLength":   maxProjectNameLength,
+				},
+			},
+			"required":             []string{"name"},
+			"additionalProperties": false,
+		},
+		Annotations: shared.Mutating(false, true),
+		Output:      projectRenameResult{},
+		Handler:     handleProjectRename,
+	})
+
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "service_rename",
+		Description: toolDescription("service_rename"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"service_id": map[string]interface{}{
+					"type":        "string",
+					"description": "REQUIRED: ID of the service to rename (from discovery tool)",
+					"pattern":     "^[A-Za-z0-9_-]+$",
+				},
+				"hostname": map[string]interface{}{
+					"type":        "string",
+					"description": "REQUIRED: New hostname (lowercase letters and digits, starting with a letter, max 25 characters)",
+					"pattern":     "^[a-z0-9]{1,25}$",
+				},
+				"copy_secrets": map[string]interface{}{
+					"type":        "boolean",
+					"description": "OPTIONAL: Copy secret env variables to the new service (default: false)",
+					"default":     false,
+				},
+				"confirm": map[string]interface{}{
+					"type":        "boolean",
+					"description": "OPTIONAL: true creates the service under the new hostname; otherwise only the plan is returned (default: false)",
+					"default":     false,
+				},
+			},
+			"required":             []string{"service_id", "hostname"},
+			"additionalProperties": false,
+		},
+		Annotations: shared.Mutating(false, false),
+		Output:      serviceRenameResult{},
+		Handler:     handleServiceRename,
+	})
+}
+
+// projectRenameResult is the result of project_rename
+type projectRenameResult struct {
+	Status    string `json:"status"`
+	ProjectID string `json:"project_id"`
+	OldName   string `json:"old_name,omitempty"`
+	Name      string `json:"name"`
+	Message   string `json:"message,omitempty"`
+}
+
+func handleProjectRename(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+
+	projectID, err := resolveProjectID(ctx, client, args)
+	if err != nil {
+		return nil, err
+	}
+	name, _ := args["name"].(string)
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, shared.InvalidArgument("name is required")
+	}
+	if len(name) > maxProjectNameLength {
+		return nil, shared.InvalidArgument("name can be at most %d characters", maxProjectNameLength)
+	}
+
+	project, err := searchProject(ctx, client, projectID)
+	if err != nil {
+		return nil, err
+	}
+	oldName := project.Name.Native()
+	if oldName == name {
+		return projectRenameResult{
+			Status:    "unchanged",
+			ProjectID: projectID,
+			Name:      name,
+		}, nil
+	}
+
+	recordSnapshot(ctx, client, projectID, "project_rename", name)
+
+	if err := updateProject(ctx, client, project, "Failed to rename project", func(update *body.PutProject) {
+		update.Name = types.String(name)
+	}); err != nil {
+		return nil, err
+	}
+
+	return projectRenameResult{
+		Status:    "renamed",
+		ProjectID: projectID,
+		OldName:   oldName,
+		Name:      name,
+		Message:   fmt.Sprintf("Project '%s' is now '%s'. Project IDs, services and URLs are unchanged.", oldName, name),
+	}, nil
+}
+
+// updateProject applies change to the editable fields of project. The update replaces every
+// field, so the ones change leaves alone are sent as they are.
+func updateProject(ctx context.Context, client *sdk.Handler, project output.EsProject, failure string, change func(*body.PutProject)) error {
+	update := body.PutProject{
+		Name:             project.Name,
+		Description:      project.Description,
+		TagList:          project.TagList,
+		PublicIpV4Shared: project.PublicIpV4Shared,
+	}
+	change(&update)
+	resp, err := client.PutProject(ctx, path.ProjectId{Id: project.Id}, update)
+	if err != nil {
+		return shared.WrapAPIError(err, failure)
+	}
+	if _, err := resp.Output(); err != nil {
+		return shared.WrapAPIError(err, failure)
+	}
+	return nil
+}
+
+// serviceRenameResult is the result of service_rename. Without confirm it is only the plan;
+// with confirm the copy fields describe the new service.
+type serviceRenameResult struct {
+	Status        string              `json:"status"`
+	ServiceID     string              `json:"service_id"`
+	OldHostname   string              `json:"old_hostname"`
+	Hostname      string              `json:"hostname"`
+	References    []string            `json:"references"`
+	NextSteps     []string            `json:"next_steps"`
+	NewServiceID  string              `json:"new_service_id,omitempty"`
+	ProcessID     string              `json:"process_id,omitempty"`
+	SecretsCopied *bool               `json:"secrets_copied,omitempty"`
+	Error         *output.ErrorObject `json:"error,omitempty"`
+	Warnings      []string            `json:"warnings,omitempty"`
+	Message       string              `json:"message"`
+	shared.Suggestions
+}
+
+func handleServiceRename(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+
+	serviceID, _ := args["service_id"].(string)
+	if serviceID == "" {
+		return nil, shared.InvalidArgument("Service ID is required")
+	}
+	hostname, _ := args["hostname"].(string)
+	if !hostnamePattern.MatchString(hostname) || hostname[0] >= '0' && hostname[0] <= '9' {
+		return nil, shared.InvalidArgument("hostname must be lowercase letters and digits, start with a letter and have at most %d characters; suggest_hostname turns a name into a valid one", maxHostnameLength)
+	}
+	confirm, _ := args["confirm"].(bool)
+
+	service, err := getServiceStack(ctx, client, serviceID)
+	if err != nil {
+		return nil, err
+	}
+	oldHostname := service.Name.Native()
+	if oldHostname == hostname {
+		return nil, shared.InvalidArgument("The service is already called '%s'", hostname)
+	}
+
+	project, err := searchProject(ctx, client, string(service.ProjectId))
+	if err != nil {
+		return nil, err
+	}
+	services, err := searchProjectServices(ctx, client, project)
+	if err != nil {
+		return nil, err
+	}
+	for _, other := range services {
+		if other.Name.Native() == hostname {
+			return nil, shared.Conflict("Hostname '%s' is already used in the project", hostname)
+		}
+	}
+
+	references, warnings := hostnameReferences(ctx, client, project, services, oldHostname)
+	steps := []string{
+		fmt.Sprintf("Deploy the code of '%s' to '%s' (deploy_push, or the same pipeline)", oldHostname, hostname),
+		fmt.Sprintf("Update the env values in references to point at '%s'", hostname),
+		fmt.Sprintf("Move public access: HTTP routing and subdomains of '%s'", oldHostname),
+		fmt.Sprintf("Delete '%s' once nothing uses it; its data is not copied", oldHostname),
+	}
+
+	result := serviceRenameResult{
+		ServiceID:   serviceID,
+		OldHostname: oldHostname,
+		Hostname:    hostname,
+		References:  references,
+		NextSteps:   steps,
+		Warnings:    warnings,
+	}
+	if !confirm {
+		result.Status = "plan"
+		result.Message = fmt.Sprintf("Zerops hostnames can't be changed, so '%s' is renamed by creating a copy called '%s' and retiring the original. Call again with confirm: true to create the copy.", oldHostname, hostname)
+		return result, nil
+	}
+
+	cloned, err := handleServiceClone(ctx, client, map[string]interface{}{
+		"service_id":   serviceID,
+		"hostname":     hostname,
+		"copy_secrets": args["copy_secrets"],
+	})
+	if err != nil {
+		return nil, err
+	}
+	clone := cloned.(serviceCloneResult)
+	result.Status = "copy_started"
+	result.NewServiceID = clone.ServiceID
+	result.ProcessID = clone.ProcessID
+	result.SecretsCopied = &clone.SecretsCopied
+	result.Error = clone.Error
+	result.Message = fmt.Sprintf("Creating '%s' as a copy of '%s'. Wait for process_id with wait_for_process, then follow next_steps; '%s' keeps running until you delete it.", hostname, oldHostname, oldHostname)
+	if clone.ProcessID != "" {
+		result.SuggestNext(shared.WaitForProcess(clone.ProcessID, fmt.Sprintf("Wait until '%s' is created", hostname)))
+	}
+	return result, nil
+}
+
+// hostnameReferences lists the project and service env variables whose values name hostname,
+// as "<hostname>.<key>" ("project.<key>" for project env). Services whose env can't be read
+// are reported as warnings.
+func hostnameReferences(ctx context.Context, client *sdk.Handler, project output.EsProject, services []output.EsServiceStack, hostname string) ([]string, []string) {
+	renames := map[string]string{hostname: "\x00"}
+	mentions := func(value string) bool {
+		return renameHostnames(value, renames) != value
+	}
+
+	references := []string{}
+	var warnings []string
+	for _, env := range project.EnvList {
+		if mentions(env.Content.Native()) {
+			references = append(references, "project."+env.Key.Native())
+		}
+	}
+	for _, service := range services {
+		if service.Name.Native() == hostname {
+			continue
+		}
+		envResp, err := client.GetServiceStackEnv(ctx, path.ServiceStackId{Id: service.Id})
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("Could not read env of '%s': %v", service.Name.Native(), err))
+			continue
+		}
+		envOutput, err := envResp.Output()
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("Could not read env of '%s': %v", service.Name.Native(), err))
+			continue
+		}
+		for _, env := range envOutput.Items {
+			if mentions(env.Content.Native()) {
+				references = append(references, service.Name.Native()+"."+env.Key.Native())
+			}
+		}
+	}
+	sort.Strings(references)
+	return references, warnings
+}
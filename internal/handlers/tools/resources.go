@@ -0,0 +1,92 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/sdk"
+)
+
+// serviceResourceState is what subscribers of zerops://service/{id} are notified about
+type serviceResourceState struct {
+	Status          string `json:"status"`
+	AppVersionID    string `json:"app_version_id"`
+	SubdomainAccess bool   `json:"subdomain_access"`
+}
+
+// RegisterResources registers the read-only MCP resources. They return what the matching
+// read tools return, so env values are masked the same way.
+func RegisterResources() {
+	shared.GlobalResources.Register(&shared.ResourceTemplate{
+		URITemplate: "zerops://project/{id}",
+		Name:        "project",
+		Description: "A project with its env keys and services, as returned by discovery",
+		Handler: func(ctx context.Context, client *sdk.Handler, params map[string]string) (interface{}, error) {
+			return handleDiscovery(ctx, client, map[string]interface{}{"project_id": params["id"]})
+		},
+	})
+
+	shared.GlobalResources.Register(&shared.ResourceTemplate{
+		URITemplate: "zerops://project/{id}/env",
+		Name:        "project-env",
+		Description: "Project-level environment variables with sensitive values masked",
+		Handler: func(ctx context.Context, client *sdk.Handler, params map[string]string) (interface{}, error) {
+			return handleGetProjectEnv(ctx, client, map[string]interface{}{"project_id": params["id"]})
+		},
+	})
+
+	shared.GlobalResources.Register(&shared.ResourceTemplate{
+		URITemplate: "zerops://service/{id}",
+		Name:        "service",
+		Description: "A service with its status, scaling, ports and env keys, as returned by discovery",
+		Handler: func(ctx context.Context, client *sdk.Handler, params map[string]string) (interface{}, error) {
+			if client == nil {
+				return nil, shared.ErrNoClient
+			}
+			// discovery is scoped by project, which the service tells
+			service, err := getServiceStack(ctx, client, params["id"])
+			if err != nil {
+				return nil, err
+			}
+			return handleDiscovery(ctx, client, map[string]interface{}{
+				"project_id": string(service.ProjectId),
+				"service_id": params["id"],
+			})
+		},
+		Watch: func(ctx context.Context, client *sdk.Handler, params map[string]string) (interface{}, error) {
+			if client == nil {
+				return nil, shared.ErrNoClient
+			}
+			service, err := getServiceStack(ctx, client, params["id"])
+			if err != nil {
+				return nil, err
+			}
+			state := serviceResourceState{
+				Status:          string(service.Status),
+				SubdomainAccess: service.SubdomainAccess.Native(),
+			}
+			if service.ActiveAppVersion != nil {
+				state.AppVersionID = string(service.ActiveAppVersion.Id)
+			}
+			return state, nil
+		},
+	})
+
+	shared.GlobalResources.Register(&shared.ResourceTemplate{
+		URITemplate: "zerops://service/{id}/env",
+		Name:        "service-env",
+		Description: "Service environment variables with sensitive values masked",
+		Handler: func(ctx context.Context, client *sdk.Handler, params map[string]string) (interface{}, error) {
+			return handleGetServiceEnv(ctx, client, map[string]interface{}{"service_id": params["id"]})
+		},
+	})
+
+	shared.GlobalResources.Register(&shared.ResourceTemplate{
+		URITemplate: "zerops://app-version/{id}/deploy-config",
+		Name:        "deploy-config",
+		Description: "The zerops.yml an app version was built and deployed with, as returned by get_deployment_config",
+		Handler: func(ctx context.Context, client *sdk.Handler, params map[string]string) (interface{}, error) {
+			return handleGetDeploymentConfig(ctx, client, map[string]interface{}{"app_version_id": params["id"]})
+		},
+	})
+}
@@ -0,0 +1,180 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/dto/input/body"
+	"github.com/zeropsio/zerops-go/dto/input/path"
+	"github.com/zeropsio/zerops-go/dto/output"
+	"github.com/zeropsio/zerops-go/sdk"
+	"github.com/zeropsio/zerops-go/types"
+	"github.com/zeropsio/zerops-go/types/enum"
+	"github.com/zeropsio/zerops-go/types/uuid"
+)
+
+// rollbackSearchLimit is how many recent app versions are searched for the previous one
+const rollbackSearchLimit = 50
+
+// RegisterRollback registers the rollback_deployment tool
+func RegisterRollback() {
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "rollback_deployment",
+		Description: toolDescription("rollback_deployment"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"service_id": map[string]interface{}{
+					"type":        "string",
+					"description": "REQUIRED: Service ID from discovery tool",
+					"pattern":     "^[A-Za-z0-9_-]+$",
+				},
+				"app_version_id": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: App version to activate, or 'previous' (default: previous)",
+				},
+				"expected_last_update": expectedLastUpdateProperty(),
+			},
+			"required":             []string{"service_id"},
+			"additionalProperties": false,
+		},
+		Annotations: shared.Mutating(true, true),
+		Output:      rollbackResult{},
+		Handler:     handleRollbackDeployment,
+	})
+}
+
+// rollbackResult is the result of rollback_deployment
+type rollbackResult struct {
+	Status       string `json:"status"`
+	ServiceID    string `json:"service_id"`
+	ServiceName  string `json:"service_name,omitempty"`
+	AppVersionID string `json:"app_version_id,omitempty"`
+	ProcessID    string `json:"process_id,omitempty"`
+	FromVersion  string `json:"from_version,omitempty"`
+	ToVersion    string `json:"to_version,omitempty"`
+	ToSequence   int    `json:"to_sequence,omitempty"`
+	Message      string `json:"message"`
+	shared.Suggestions
+}
+
+func handleRollbackDeployment(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+
+	serviceID, ok := args["service_id"].(string)
+	if !ok || serviceID == "" {
+		return nil, shared.InvalidArgument("Service ID is required")
+	}
+	target, _ := args["app_version_id"].(string)
+	if target == "" {
+		target = "previous"
+	}
+
+	if err := checkServiceUnchanged(ctx, client, args, serviceID); err != nil {
+		return nil, err
+	}
+
+	serviceResp, err := client.GetServiceStack(ctx, path.ServiceStackId{Id: uuid.ServiceStackId(serviceID)})
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to get service")
+	}
+	service, err := serviceResp.Output()
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to get service")
+	}
+	activeID := ""
+	if service.ActiveAppVersion != nil {
+		activeID = string(service.ActiveAppVersion.Id)
+	}
+
+	resp, err := client.PostAppVersionSearch(ctx, body.EsFilter{
+		Search: []body.EsSearchItem{
+			{
+				Name:     "serviceStackId",
+				Operator: "eq",
+				Value:    types.String(serviceID),
+			},
+		},
+		Sort: []body.EsSortItem{
+			{Name: "sequence", Ascending: types.NewBoolNull(false)},
+		},
+		Limit: types.NewIntNull(rollbackSearchLimit),
+	})
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to search app versions")
+	}
+	versions, err := resp.Output()
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to parse app versions")
+	}
+
+	version, err := rollbackTarget(versions.Items, activeID, target)
+	if err != nil {
+		return nil, err
+	}
+	if string(version.Id) == activeID {
+		return rollbackResult{
+			Status:       "already_active",
+			ServiceID:    serviceID,
+			AppVersionID: activeID,
+			Message:      fmt.Sprintf("App version %s is already active on %s.", activeID, service.Name.Native()),
+		}, nil
+	}
+
+	recordSnapshot(ctx, client, string(service.ProjectId), "rollback_deployment", serviceID)
+
+	deployResp, err := client.PutAppVersionDeploy(ctx, path.AppVersionId{Id: version.Id}, body.PutAppVersionDeploy{})
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Rollback failed")
+	}
+	process, err := deployResp.Output()
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Rollback failed")
+	}
+	forgetServiceStamp(ctx, serviceID)
+
+	result := rollbackResult{
+		Status:      "rollback_started",
+		ServiceID:   serviceID,
+		ServiceName: service.Name.Native(),
+		ProcessID:   string(process.Id),
+		FromVersion: activeID,
+		ToVersion:   string(version.Id),
+		ToSequence:  version.Sequence.Native(),
+		Message:     fmt.Sprintf("Activating app version #%d on %s. Use wait_for_process to monitor progress.", version.Sequence.Native(), service.Name.Native()),
+	}
+	result.SuggestNext(shared.WaitForProcess(string(process.Id), fmt.Sprintf("Wait until version #%d is active", version.Sequence.Native())))
+	return result, nil
+}
+
+// rollbackTarget picks the app version to activate from versions ordered newest first
+func rollbackTarget(versions []output.EsAppVersion, activeID, target string) (*output.EsAppVersion, error) {
+	if target != "previous" {
+		for i := range versions {
+			if string(versions[i].Id) != target {
+				continue
+			}
+			if !versions[i].Status.Is(enum.AppVersionStatusEnumActive, enum.AppVersionStatusEnumBackup) {
+				return nil, shared.Conflict("App version %s has status %s; only ACTIVE or BACKUP versions can be activated", target, versions[i].Status)
+			}
+			return &versions[i], nil
+		}
+		return nil, shared.NotFound("App version '%s' is not among the %d newest versions of the service; check list_app_versions", target, rollbackSearchLimit)
+	}
+
+	// Without an active version any backup is older than what runs now
+	pastActive := activeID == ""
+	for i := range versions {
+		if string(versions[i].Id) == activeID {
+			pastActive = true
+			continue
+		}
+		if pastActive && versions[i].Status.Is(enum.AppVersionStatusEnumBackup) {
+			return &versions[i], nil
+		}
+	}
+	return nil, shared.NotFound("No previous app version to roll back to; the service has no BACKUP version older than the active one")
+}
@@ -0,0 +1,49 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/sdk"
+)
+
+// RegisterRoots registers the list_effective_roots tool
+func RegisterRoots() {
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "list_effective_roots",
+		Description: toolDescription("list_effective_roots"),
+		InputSchema: map[string]interface{}{
+			"type":                 "object",
+			"properties":           map[string]interface{}{},
+			"additionalProperties": false,
+		},
+		Annotations: shared.ReadOnly(),
+		Output:      rootsResult{},
+		Handler:     handleListEffectiveRoots,
+	})
+}
+
+// rootsResult is the result of list_effective_roots
+type rootsResult struct {
+	Enforced bool          `json:"enforced"`
+	Roots    []shared.Root `json:"roots"`
+	Message  string        `json:"message,omitempty"`
+}
+
+func handleListEffectiveRoots(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	roots, enforced, err := shared.EffectiveRoots(ctx)
+	result := rootsResult{
+		Enforced: enforced,
+		Roots:    roots,
+	}
+
+	switch {
+	case err != nil:
+		result.Message = "The client does not provide roots (" + err.Error() + "); filesystem paths are not restricted."
+	case !enforced:
+		result.Message = "This transport has no access to client roots; filesystem paths are not restricted."
+	case len(roots) == 0:
+		result.Message = "The client provides no roots; all local filesystem operations are refused."
+	}
+	return result, nil
+}
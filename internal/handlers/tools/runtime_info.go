@@ -0,0 +1,240 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/dto/input/path"
+	"github.com/zeropsio/zerops-go/dto/output"
+	"github.com/zeropsio/zerops-go/sdk"
+	"github.com/zeropsio/zerops-go/types/uuid"
+	"gopkg.in/yaml.v3"
+)
+
+// packageInstallPattern matches package manager installs in prepareCommands
+var packageInstallPattern = regexp.MustCompile(`(?:apk add|apt-get install|apt install|zsc install)((?:\s+[^\s;&|]+)+)`)
+
+// zeropsRuntimeSetup is the part of a zerops.yml setup that defines the build and run images
+type zeropsRuntimeSetup struct {
+	Setup string `yaml:"setup"`
+	Build struct {
+		Base            interface{} `yaml:"base"`
+		OS              string      `yaml:"os"`
+		PrepareCommands []string    `yaml:"prepareCommands"`
+	} `yaml:"build"`
+	Run struct {
+		Base            interface{} `yaml:"base"`
+		OS              string      `yaml:"os"`
+		PrepareCommands []string    `yaml:"prepareCommands"`
+	} `yaml:"run"`
+}
+
+// RegisterRuntimeInfo registers the get_runtime_info tool
+func RegisterRuntimeInfo() {
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "get_runtime_info",
+		Description: toolDescription("get_runtime_info"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"service_id": map[string]interface{}{
+					"type":        "string",
+					"description": "REQUIRED: Service ID from discovery tool",
+					"pattern":     "^[A-Za-z0-9_-]+$",
+				},
+			},
+			"required":             []string{"service_id"},
+			"additionalProperties": false,
+		},
+		Annotations: shared.ReadOnly(),
+		Output:      runtimeInfoResult{},
+		Handler:     handleGetRuntimeInfo,
+	})
+}
+
+// runtimeInfoResult is the result of get_runtime_info
+type runtimeInfoResult struct {
+	ServiceID     string         `json:"service_id"`
+	ServiceName   string         `json:"service_name"`
+	Runtime       runtimeType    `json:"runtime"`
+	ActiveVersion *activeRuntime `json:"active_version,omitempty"`
+	Build         *runtimeImage  `json:"build,omitempty"`
+	Run           *runtimeImage  `json:"run,omitempty"`
+	Hints         []string       `json:"hints,omitempty"`
+	Message       string         `json:"message,omitempty"`
+	Warnings      []string       `json:"warnings,omitempty"`
+}
+
+// runtimeType is the service type of a runtime service
+type runtimeType struct {
+	Type          string `json:"type"`
+	Version       string `json:"version"`
+	TypeVersionID string `json:"type_version_id"`
+	Category      string `json:"category"`
+	Mode          string `json:"mode"`
+}
+
+// activeRuntime describes the image of the active app version
+type activeRuntime struct {
+	ID            string `json:"id"`
+	CustomRuntime bool   `json:"custom_runtime"`
+	OS            string `json:"os,omitempty"`
+	Base          string `json:"base,omitempty"`
+	BuildImage    string `json:"build_image,omitempty"`
+	BuildCache    *bool  `json:"build_cache,omitempty"`
+}
+
+// runtimeImage is the build or run image declared in zerops.yml
+type runtimeImage struct {
+	Base            []string `json:"base"`
+	Packages        []string `json:"packages"`
+	OS              string   `json:"os,omitempty"`
+	PrepareCommands []string `json:"prepare_commands,omitempty"`
+}
+
+func handleGetRuntimeInfo(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+
+	serviceID, ok := args["service_id"].(string)
+	if !ok || serviceID == "" {
+		return nil, shared.InvalidArgument("Service ID is required")
+	}
+
+	serviceResp, err := client.GetServiceStack(ctx, path.ServiceStackId{Id: uuid.ServiceStackId(serviceID)})
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to get service")
+	}
+	service, err := serviceResp.Output()
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to get service")
+	}
+
+	result := runtimeInfoResult{
+		ServiceID:   serviceID,
+		ServiceName: service.Name.Native(),
+		Runtime: runtimeType{
+			Type:          service.ServiceStackTypeInfo.ServiceStackTypeName.Native(),
+			Version:       service.ServiceStackTypeInfo.ServiceStackTypeVersionName.Native(),
+			TypeVersionID: string(service.ServiceStackTypeVersionId),
+			Category:      string(service.ServiceStackTypeInfo.ServiceStackTypeCategory),
+			Mode:          string(service.Mode),
+		},
+	}
+
+	if service.ActiveAppVersion == nil {
+		result.Message = "Service has no active deployment; image details are known after the first deploy."
+		return result, nil
+	}
+
+	versions, err := deployedAppVersions(ctx, client, serviceID)
+	if err != nil {
+		result.Warnings = []string{"app version details unavailable: " + err.Error()}
+		return result, nil
+	}
+	var active *output.AppVersionJsonObject
+	for i := range versions {
+		if versions[i].Id == service.ActiveAppVersion.Id {
+			active = &versions[i]
+			break
+		}
+	}
+	if active == nil {
+		result.Warnings = []string{fmt.Sprintf("active app version %s not found in recent processes", service.ActiveAppVersion.Id)}
+		return result, nil
+	}
+
+	activeInfo := &activeRuntime{
+		ID:            string(active.Id),
+		CustomRuntime: active.PrepareCustomRuntime != nil,
+	}
+	osName := ""
+	if value, ok := active.Os.Get(); ok {
+		osName = value.Native()
+		activeInfo.OS = osName
+	}
+	if value, ok := active.Base.Get(); ok {
+		activeInfo.Base = value.Native()
+	}
+	if active.Build != nil {
+		if buildType, ok := active.Build.ServiceStackTypeVersionId.Get(); ok {
+			activeInfo.BuildImage = string(buildType)
+		}
+		_, cached := active.Build.CacheSnapshotId.Get()
+		activeInfo.BuildCache = &cached
+	}
+	result.ActiveVersion = activeInfo
+
+	if config, ok := active.ConfigContent.Get(); ok && config.Native() != "" {
+		var parsed struct {
+			Zerops []zeropsRuntimeSetup `yaml:"zerops"`
+		}
+		if err := yaml.Unmarshal([]byte(config.Native()), &parsed); err == nil {
+			for _, setup := range parsed.Zerops {
+				if setup.Setup != service.Name.Native() && len(parsed.Zerops) > 1 {
+					continue
+				}
+				result.Build = runtimeImageInfo(setup.Build.Base, setup.Build.OS, setup.Build.PrepareCommands)
+				result.Run = runtimeImageInfo(setup.Run.Base, setup.Run.OS, setup.Run.PrepareCommands)
+				if osName == "" {
+					osName = setup.Run.OS
+				}
+				break
+			}
+		}
+	}
+
+	result.Hints = nativeDependencyHints(osName)
+	return result, nil
+}
+
+// runtimeImageInfo describes the build or run image declared in zerops.yml
+func runtimeImageInfo(base interface{}, osName string, prepareCommands []string) *runtimeImage {
+	var bases []string
+	switch value := base.(type) {
+	case string:
+		bases = append(bases, value)
+	case []interface{}:
+		for _, item := range value {
+			bases = append(bases, fmt.Sprint(item))
+		}
+	}
+
+	var packages []string
+	for _, command := range prepareCommands {
+		for _, match := range packageInstallPattern.FindAllStringSubmatch(command, -1) {
+			for _, pkg := range strings.Fields(match[1]) {
+				if !strings.HasPrefix(pkg, "-") {
+					packages = append(packages, pkg)
+				}
+			}
+		}
+	}
+
+	return &runtimeImage{
+		Base:            bases,
+		Packages:        packages,
+		OS:              osName,
+		PrepareCommands: prepareCommands,
+	}
+}
+
+// nativeDependencyHints lists common native dependency problems for the image OS
+func nativeDependencyHints(osName string) []string {
+	switch strings.ToLower(osName) {
+	case "alpine":
+		return []string{
+			"Alpine uses musl libc: prebuilt binaries for glibc fail to load (e.g. sharp, prisma engines, some Python wheels).",
+			"Install build tools and headers in prepareCommands (e.g. 'sudo apk add --no-cache vips-dev build-base python3') or set os: ubuntu.",
+		}
+	case "ubuntu":
+		return []string{
+			"Ubuntu uses glibc; install missing libraries in prepareCommands (e.g. 'sudo apt-get install -y libvips-dev').",
+		}
+	}
+	return nil
+}
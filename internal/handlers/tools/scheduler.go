@@ -0,0 +1,520 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/sdk"
+)
+
+// schedulerTick is how often the scheduler checks for due actions
+const schedulerTick = 30 * time.Second
+
+// finishedActionRetention is how long done, failed and cancelled actions stay listed
+const finishedActionRetention = 24 * time.Hour
+
+// scheduledActionTypes lists actions the scheduler can execute and the ID they target
+var scheduledActionTypes = map[string]string{
+	"start_project":   "project",
+	"stop_project":    "project",
+	"start_service":   "service",
+	"stop_service":    "service",
+	"restart_service": "service",
+	"scale_service":   "service",
+}
+
+// reservedScheduleParameters are set by the scheduler itself and can't be passed in parameters,
+// so the action runs against the target that was scheduled and confirmed
+var reservedScheduleParameters = []string{"service_id", "project_id", "confirm"}
+
+// destructiveScheduledAction reports whether an action needs confirmation when the session set
+// confirm_destructive, i.e. whether the tool it calls is destructive
+func destructiveScheduledAction(action string) bool {
+	tool, ok := shared.GlobalRegistry.Get(action)
+	return ok && tool.Annotations != nil && tool.Annotations.Destructive
+}
+
+// scheduledAction is a deferred action executed by the scheduler when due
+type scheduledAction struct {
+	ID         string                 `json:"id"`
+	Action     string                 `json:"action"`
+	TargetID   string                 `json:"target_id"`
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+	RunAt      time.Time              `json:"run_at"`
+	Created    time.Time              `json:"created"`
+	Status     string                 `json:"status"`
+	Result     string                 `json:"result,omitempty"`
+	Finished   *time.Time             `json:"finished,omitempty"`
+	Owner      string                 `json:"owner,omitempty"`
+
+	// client executes the action; persisted actions use the scheduler default client
+	client *sdk.Handler
+}
+
+// actionScheduler keeps scheduled actions and runs them when due
+type actionScheduler struct {
+	mu            sync.Mutex
+	actions       map[string]*scheduledAction
+	nextID        int
+	defaultClient *sdk.Handler
+	persistPath   string
+}
+
+var scheduler = &actionScheduler{
+	actions: make(map[string]*scheduledAction),
+}
+
+// StartScheduler starts executing scheduled actions until ctx is cancelled.
+// With a non-empty persistPath (stdio mode) actions survive restarts and run with defaultClient.
+func StartScheduler(ctx context.Context, defaultClient *sdk.Handler, persistPath string) {
+	scheduler.mu.Lock()
+	scheduler.defaultClient = defaultClient
+	scheduler.persistPath = persistPath
+	scheduler.mu.Unlock()
+
+	if persistPath != "" {
+		shared.RegisterCredentialStore("scheduled_actions", persistPath)
+		if err := scheduler.load(); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to load scheduled actions: %v\n", err)
+		}
+	}
+
+	go func() {
+		ticker := time.NewTicker(schedulerTick)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				scheduler.runDue(ctx)
+			}
+		}
+	}()
+}
+
+// DefaultSchedulePath returns the file used to persist scheduled actions in stdio mode
+func DefaultSchedulePath() string {
+	if dir := os.Getenv("ZEROPS_MCP_STATE_DIR"); dir != "" {
+		return filepath.Join(dir, "schedule.json")
+	}
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(configDir, "zerops-mcp", "schedule.json")
+}
+
+// add schedules an action; the error reports that it could not be persisted, the action is
+// scheduled in memory anyway
+func (s *actionScheduler) add(action *scheduledAction) (*scheduledAction, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextID++
+	action.ID = fmt.Sprintf("sched-%d-%d", time.Now().Unix(), s.nextID)
+	action.Status = "pending"
+	s.actions[action.ID] = action
+	return action, s.saveLocked()
+}
+
+func (s *actionScheduler) cancel(id, owner string) (*scheduledAction, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	action, ok := s.actions[id]
+	if !ok || action.Owner != owner {
+		return nil, false
+	}
+	if action.Status != "pending" {
+		return action, false
+	}
+	now := time.Now()
+	action.Status = "cancelled"
+	action.Finished = &now
+	s.saveLocked()
+	return action, true
+}
+
+func (s *actionScheduler) list(owner string) []scheduledAction {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := make([]scheduledAction, 0, len(s.actions))
+	for _, action := range s.actions {
+		if action.Owner == owner {
+			list = append(list, *action)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].RunAt.Before(list[j].RunAt)
+	})
+	return list
+}
+
+// rekey moves pending actions of oldOwner created with oldClient to a rotated API key.
+// The default client is replaced too when it was oldClient. It returns the number of moved actions.
+func (s *actionScheduler) rekey(oldClient, newClient *sdk.Handler, oldOwner, newOwner string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.defaultClient == oldClient {
+		s.defaultClient = newClient
+	}
+	moved := 0
+	for _, action := range s.actions {
+		if action.Status != "pending" || action.Owner != oldOwner {
+			continue
+		}
+		if action.client != nil && action.client != oldClient {
+			continue
+		}
+		if action.client != nil {
+			action.client = newClient
+		}
+		action.Owner = newOwner
+		moved++
+	}
+	if moved > 0 {
+		s.saveLocked()
+	}
+	return moved
+}
+
+// pruneLocked drops actions finished more than finishedActionRetention ago; the caller must
+// hold s.mu. Only pending actions are persisted, so the file doesn't change.
+func (s *actionScheduler) pruneLocked(now time.Time) {
+	for id, action := range s.actions {
+		if action.Finished != nil && now.Sub(*action.Finished) > finishedActionRetention {
+			delete(s.actions, id)
+		}
+	}
+}
+
+// runDue executes every pending action whose time has come
+func (s *actionScheduler) runDue(ctx context.Context) {
+	s.mu.Lock()
+	var due []*scheduledAction
+	now := time.Now()
+	s.pruneLocked(now)
+	for _, action := range s.actions {
+		if action.Status == "pending" && !action.RunAt.After(now) {
+			action.Status = "running"
+			due = append(due, action)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, action := range due {
+		client := action.client
+		if client == nil {
+			client = s.defaultClient
+		}
+
+		status, result := "done", ""
+		if client == nil {
+			status, result = "failed", "No API client available to execute the action"
+		} else if out, err := executeScheduledAction(ctx, client, action); err != nil {
+			status, result = "failed", err.Error()
+		} else {
+			result = out
+		}
+
+		finished := time.Now()
+		s.mu.Lock()
+		action.Status = status
+		action.Result = result
+		action.Finished = &finished
+		s.saveLocked()
+		s.mu.Unlock()
+
+		fmt.Fprintf(os.Stderr, "Scheduled action %s (%s %s): %s %s\n", action.ID, action.Action, action.TargetID, status, result)
+	}
+}
+
+// executeScheduledAction calls the tool of the action against its target and returns the result
+func executeScheduledAction(ctx context.Context, client *sdk.Handler, action *scheduledAction) (string, error) {
+	targetKind, ok := scheduledActionTypes[action.Action]
+	if !ok {
+		return "", shared.InvalidArgument("Unknown scheduled action '%s'", action.Action)
+	}
+
+	args := make(map[string]interface{}, len(action.Parameters)+1)
+	for key, value := range action.Parameters {
+		args[key] = value
+	}
+	for _, name := range reservedScheduleParameters {
+		delete(args, name)
+	}
+	args[targetKind+"_id"] = action.TargetID
+
+	// Scheduled actions run long after the agent's last read, so optimistic locking doesn't apply,
+	// and destructive ones were confirmed by schedule_action
+	callCtx := context.WithValue(context.WithValue(ctx, "zeropsClient", client), "skipServiceLock", true)
+	callCtx = context.WithValue(callCtx, "skipConfirmation", true)
+	result, err := shared.GlobalRegistry.CallTool(callCtx, action.Action, args)
+	if err != nil {
+		return "", err
+	}
+	out, _ := json.Marshal(result)
+	return string(out), nil
+}
+
+func actionOwner(ctx context.Context) string {
+	return shared.OwnerID(sessionAPIKey(ctx))
+}
+
+// parseRunAt accepts future RFC3339 timestamps, "HH:MM" (next occurrence in the location of now)
+// or delays like "2h30m"
+func parseRunAt(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		if !t.After(now) {
+			return time.Time{}, shared.InvalidArgument("Time '%s' is in the past", value)
+		}
+		return t, nil
+	}
+	if clock, err := time.ParseInLocation("15:04", value, now.Location()); err == nil {
+		runAt := time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, now.Location())
+		if !runAt.After(now) {
+			runAt = runAt.AddDate(0, 0, 1)
+		}
+		return runAt, nil
+	}
+	if delay, err := time.ParseDuration(value); err == nil && delay > 0 {
+		return now.Add(delay), nil
+	}
+	return time.Time{}, shared.InvalidArgument("Invalid time '%s'. Use RFC3339 (2025-01-02T19:00:00Z), HH:MM or a delay like 2h30m", value)
+}
+
+// RegisterScheduler registers the scheduled action tools
+func RegisterScheduler() {
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "schedule_action",
+		Description: toolDescription("schedule_action"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"action": map[string]interface{}{
+					"type":        "string",
+					"description": "REQUIRED: Action to execute",
+					"enum":        []string{"start_project", "stop_project", "start_service", "stop_service", "restart_service", "scale_service"},
+				},
+				"target_id": map[string]interface{}{
+					"type":        "string",
+					"description": "REQUIRED: Project ID for project actions, service ID for service actions",
+					"pattern":     "^[A-Za-z0-9_-]+$",
+				},
+				"run_at": map[string]interface{}{
+					"type":        "string",
+					"description": "REQUIRED: When to run - RFC3339 timestamp, HH:MM or delay like 2h",
+				},
+				"parameters": map[string]interface{}{
+					"type":        "object",
+					"description": "OPTIONAL: Extra arguments for scale_service",
+				},
+				"confirm": map[string]interface{}{
+					"type":        "boolean",
+					"description": "OPTIONAL: Set to true to confirm the action when the session set confirm_destructive with set_preferences",
+				},
+			},
+			"required":             []string{"action", "target_id", "run_at"},
+			"additionalProperties": false,
+		},
+		// Destructive actions are confirmed by the handler, so scheduling a start needs no confirmation
+		Annotations: shared.Mutating(false, false),
+		Output:      scheduleActionResult{},
+		Handler:     handleScheduleAction,
+	})
+
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "list_scheduled_actions",
+		Description: toolDescription("list_scheduled_actions"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"include_finished": map[string]interface{}{
+					"type":        "boolean",
+					"description": "OPTIONAL: Also list done, failed and cancelled actions (default: false)",
+					"default":     false,
+				},
+			},
+			"additionalProperties": false,
+		},
+		Annotations: shared.ReadOnly(),
+		Output:      listScheduledActionsResult{},
+		Handler:     handleListScheduledActions,
+	})
+
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "cancel_scheduled_action",
+		Description: toolDescription("cancel_scheduled_action"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"schedule_id": map[string]interface{}{
+					"type":        "string",
+					"description": "REQUIRED: Scheduled action ID",
+				},
+			},
+			"required":             []string{"schedule_id"},
+			"additionalProperties": false,
+		},
+		Annotations: shared.Mutating(true, true),
+		Output:      cancelScheduledActionResult{},
+		Handler:     handleCancelScheduledAction,
+	})
+}
+
+// scheduleActionResult is the result of schedule_action
+type scheduleActionResult struct {
+	ScheduleID string `json:"schedule_id"`
+	Action     string `json:"action"`
+	Target     string `json:"target"`
+	RunAt      string `json:"run_at"`
+	RunsIn     string `json:"runs_in"`
+	Status     string `json:"status"`
+	Message    string `json:"message"`
+	Warning    string `json:"warning,omitempty"`
+	shared.Suggestions
+}
+
+// listScheduledActionsResult is the result of list_scheduled_actions
+type listScheduledActionsResult struct {
+	Actions []scheduledAction `json:"actions"`
+	Count   int               `json:"count,omitempty"`
+	Message string            `json:"message,omitempty"`
+}
+
+// cancelScheduledActionResult is the result of cancel_scheduled_action
+type cancelScheduledActionResult struct {
+	ScheduleID string `json:"schedule_id"`
+	Status     string `json:"status"`
+	Message    string `json:"message"`
+}
+
+func handleScheduleAction(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+
+	action, _ := args["action"].(string)
+	targetKind, ok := scheduledActionTypes[action]
+	if !ok {
+		return nil, shared.InvalidArgument("Unknown action '%s'", action)
+	}
+
+	targetID, ok := args["target_id"].(string)
+	if !ok || targetID == "" {
+		return nil, shared.InvalidArgument("Target ID is required")
+	}
+
+	runAtValue, _ := args["run_at"].(string)
+	// Clock times are read in the session timezone, like the timestamps tools return
+	display, err := resolveDisplayFormat(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	runAt, err := parseRunAt(runAtValue, time.Now().In(display.loc))
+	if err != nil {
+		return nil, err
+	}
+
+	parameters, _ := args["parameters"].(map[string]interface{})
+	if len(parameters) > 0 && action != "scale_service" {
+		return nil, shared.InvalidArgument("Parameters are only supported for scale_service")
+	}
+	for _, name := range reservedScheduleParameters {
+		if _, ok := parameters[name]; ok {
+			return nil, shared.InvalidArgument("Parameter '%s' is not allowed; the action runs against target_id", name)
+		}
+	}
+	// The scheduled call skips the confirmation check, so destructive actions are confirmed now
+	if destructiveScheduledAction(action) {
+		tool := &shared.ToolDefinition{Name: action, Annotations: shared.Mutating(true, true)}
+		if err := shared.CheckConfirmation(ctx, tool, args); err != nil {
+			return nil, shared.InvalidArgument("%s is destructive and this session confirms destructive changes (set_preferences confirm_destructive); review the action and schedule it again with confirm: true", action)
+		}
+	}
+
+	scheduled, saveErr := scheduler.add(&scheduledAction{
+		Action:     action,
+		TargetID:   targetID,
+		Parameters: parameters,
+		RunAt:      runAt,
+		Created:    time.Now(),
+		Owner:      actionOwner(ctx),
+		client:     client,
+	})
+
+	result := scheduleActionResult{
+		ScheduleID: scheduled.ID,
+		Action:     action,
+		Target:     fmt.Sprintf("%s %s", targetKind, targetID),
+		RunAt:      runAt.Format(time.RFC3339),
+		RunsIn:     time.Until(runAt).Round(time.Second).String(),
+		Status:     scheduled.Status,
+		Message:    "Action scheduled. Use 'list_scheduled_actions' to check its status.",
+	}
+	if saveErr != nil {
+		result.Warning = fmt.Sprintf("The action could not be saved and is lost if the server restarts before it runs: %v", saveErr)
+	}
+	result.SuggestNext(shared.NextCall{
+		Tool:      "list_scheduled_actions",
+		Arguments: map[string]interface{}{},
+		Purpose:   "Check the status of the action after it ran",
+	})
+	return result, nil
+}
+
+func handleListScheduledActions(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	includeFinished, _ := args["include_finished"].(bool)
+
+	var actions []scheduledAction
+	for _, action := range scheduler.list(actionOwner(ctx)) {
+		if includeFinished || action.Status == "pending" || action.Status == "running" {
+			actions = append(actions, action)
+		}
+	}
+
+	if len(actions) == 0 {
+		return listScheduledActionsResult{
+			Actions: []scheduledAction{},
+			Message: "No scheduled actions",
+		}, nil
+	}
+
+	return listScheduledActionsResult{
+		Actions: actions,
+		Count:   len(actions),
+	}, nil
+}
+
+func handleCancelScheduledAction(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	scheduleID, ok := args["schedule_id"].(string)
+	if !ok || scheduleID == "" {
+		return nil, shared.InvalidArgument("Schedule ID is required")
+	}
+
+	action, cancelled := scheduler.cancel(scheduleID, actionOwner(ctx))
+	if action == nil {
+		return nil, shared.NotFound("Scheduled action '%s' not found", scheduleID)
+	}
+	if !cancelled {
+		return nil, shared.InvalidArgument("Scheduled action '%s' is already %s", scheduleID, action.Status)
+	}
+
+	return cancelScheduledActionResult{
+		ScheduleID: scheduleID,
+		Status:     action.Status,
+		Message:    fmt.Sprintf("Scheduled %s for %s cancelled", action.Action, action.TargetID),
+	}, nil
+}
@@ -0,0 +1,182 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/sdk"
+)
+
+func TestParseRunAt(t *testing.T) {
+	now := time.Date(2025, 1, 2, 18, 0, 0, 0, time.UTC)
+	session := time.FixedZone("UTC+2", 2*60*60)
+	tests := []struct {
+		name    string
+		value   string
+		loc     *time.Location
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "future timestamp", value: "2025-01-02T19:00:00Z", want: now.Add(time.Hour)},
+		{name: "past timestamp", value: "2025-01-02T17:00:00Z", wantErr: true},
+		{name: "current timestamp", value: "2025-01-02T18:00:00Z", wantErr: true},
+		{name: "clock later today", value: "19:30", want: now.Add(90 * time.Minute)},
+		{name: "clock passed rolls to tomorrow", value: "17:00", want: now.Add(23 * time.Hour)},
+		{name: "clock in session timezone", value: "21:30", loc: session, want: now.Add(90 * time.Minute)},
+		{name: "clock passed in session timezone", value: "19:30", loc: session, want: now.Add(23*time.Hour + 30*time.Minute)},
+		{name: "delay", value: "2h", want: now.Add(2 * time.Hour)},
+		{name: "negative delay", value: "-2h", wantErr: true},
+		{name: "garbage", value: "tomorrow", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loc := time.UTC
+			if tt.loc != nil {
+				loc = tt.loc
+			}
+			got, err := parseRunAt(tt.value, now.In(loc))
+			if tt.wantErr {
+				if !errors.Is(err, shared.ErrInvalidArgument) {
+					t.Fatalf("err = %v, want invalid argument", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Fatalf("run at = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduleActionParameters(t *testing.T) {
+	tests := []struct {
+		name       string
+		action     string
+		parameters map[string]interface{}
+		wantErr    bool
+	}{
+		{name: "scale arguments", action: "scale_service", parameters: map[string]interface{}{"max_containers": 1.0}},
+		{name: "service_id", action: "scale_service", parameters: map[string]interface{}{"service_id": "other"}, wantErr: true},
+		{name: "project_id", action: "scale_service", parameters: map[string]interface{}{"project_id": "other"}, wantErr: true},
+		{name: "confirm", action: "scale_service", parameters: map[string]interface{}{"confirm": true}, wantErr: true},
+		{name: "parameters for other actions", action: "start_service", parameters: map[string]interface{}{"max_containers": 1.0}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := handleScheduleAction(context.Background(), &sdk.Handler{}, map[string]interface{}{
+				"action":     tt.action,
+				"target_id":  "service1",
+				"run_at":     "1h",
+				"parameters": tt.parameters,
+			})
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("err = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestScheduleActionConfirmation(t *testing.T) {
+	// Called through the registry, so the tool annotation is checked as well
+	RegisterScheduler()
+	RegisterLifecycle()
+	ctx := context.WithValue(context.Background(), "apiKey", "scheduler-test-key")
+	shared.UpdateSessionPreferences(ctx, func(preferences *shared.Preferences) {
+		preferences.ConfirmDestructive = true
+	})
+
+	tests := []struct {
+		name    string
+		action  string
+		confirm bool
+		wantErr bool
+	}{
+		{name: "start needs no confirmation", action: "start_project"},
+		{name: "stop needs confirmation", action: "stop_project", wantErr: true},
+		{name: "confirmed stop", action: "stop_project", confirm: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := map[string]interface{}{
+				"action":    tt.action,
+				"target_id": "project1",
+				"run_at":    "1h",
+			}
+			if tt.confirm {
+				args["confirm"] = true
+			}
+			_, err := shared.GlobalRegistry.CallTool(context.WithValue(ctx, "zeropsClient", &sdk.Handler{}), "schedule_action", args)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("err = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExecuteScheduledActionKeepsTarget(t *testing.T) {
+	// The actions run against a local registry, so the fake tools don't leak into other tests
+	previous := shared.GlobalRegistry
+	shared.GlobalRegistry = shared.NewToolRegistry()
+	t.Cleanup(func() { shared.GlobalRegistry = previous })
+
+	var got map[string]interface{}
+	for _, name := range []string{"scale_service", "stop_project"} {
+		shared.GlobalRegistry.Register(&shared.ToolDefinition{
+			Name:        name,
+			Annotations: shared.Mutating(true, true),
+			Handler: func(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+				got = args
+				return map[string]interface{}{}, nil
+			},
+		})
+	}
+
+	tests := []struct {
+		name   string
+		action *scheduledAction
+		want   map[string]interface{}
+	}{
+		{
+			// A persisted action whose parameters predate the schedule-time check
+			name: "scale with reserved parameters",
+			action: &scheduledAction{
+				Action:   "scale_service",
+				TargetID: "service1",
+				Parameters: map[string]interface{}{
+					"service_id":     "other",
+					"project_id":     "other",
+					"confirm":        true,
+					"max_containers": 2.0,
+				},
+			},
+			want: map[string]interface{}{"service_id": "service1", "max_containers": 2.0},
+		},
+		{
+			name:   "project action",
+			action: &scheduledAction{Action: "stop_project", TargetID: "project1"},
+			want:   map[string]interface{}{"project_id": "project1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = nil
+			if _, err := executeScheduledAction(context.Background(), &sdk.Handler{}, tt.action); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("arguments = %v, want %v", got, tt.want)
+			}
+			for name, value := range tt.want {
+				if got[name] != value {
+					t.Fatalf("%s = %v, want %v", name, got[name], value)
+				}
+			}
+		})
+	}
+}
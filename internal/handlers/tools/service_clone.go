@@ -0,0 +1,209 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/dto/input/body"
+	"github.com/zeropsio/zerops-go/dto/input/path"
+	"github.com/zeropsio/zerops-go/dto/output"
+	"github.com/zeropsio/zerops-go/sdk"
+	"github.com/zeropsio/zerops-go/types"
+	"github.com/zeropsio/zerops-go/types/uuid"
+	"gopkg.in/yaml.v3"
+)
+
+// hostnamePattern matches hostnames accepted by Zerops import
+var hostnamePattern = regexp.MustCompile(`^[a-z0-9]{1,25}$`)
+
+// secretKeys are service import keys holding secret values
+var secretKeys = []string{"envSecrets", "dotEnvSecrets"}
+
+// RegisterServiceClone registers the service_clone tool
+func RegisterServiceClone() {
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "service_clone",
+		Description: toolDescription("service_clone"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"service_id": map[string]interface{}{
+					"type":        "string",
+					"description": "REQUIRED: ID of the service to clone (from discovery tool)",
+					"pattern":     "^[A-Za-z0-9_-]+$",
+				},
+				"hostname": map[string]interface{}{
+					"type":        "string",
+					"description": "REQUIRED: Hostname of the new service (lowercase alphanumeric, max 25 characters)",
+					"pattern":     "^[a-z0-9]{1,25}$",
+				},
+				"project_id": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Target project ID. Defaults to the source service's project.",
+					"pattern":     "^[A-Za-z0-9_-]+$",
+				},
+				"copy_secrets": map[string]interface{}{
+					"type":        "boolean",
+					"description": "OPTIONAL: Copy secret env variables to the clone (default: false)",
+					"default":     false,
+				},
+			},
+			"required":             []string{"service_id", "hostname"},
+			"additionalProperties": false,
+		},
+		Annotations: shared.Mutating(false, false),
+		Output:      serviceCloneResult{},
+		Handler:     handleServiceClone,
+	})
+}
+
+// serviceCloneResult is the result of service_clone
+type serviceCloneResult struct {
+	Status        string              `json:"status"`
+	SourceService string              `json:"source_service"`
+	Hostname      string              `json:"hostname"`
+	ProjectID     string              `json:"project_id"`
+	ServiceID     string              `json:"service_id,omitempty"`
+	ProcessID     string              `json:"process_id,omitempty"`
+	SecretsCopied bool                `json:"secrets_copied"`
+	Error         *output.ErrorObject `json:"error,omitempty"`
+	YAML          string              `json:"yaml"`
+	Message       string              `json:"message"`
+	shared.Suggestions
+}
+
+func handleServiceClone(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+
+	serviceID, ok := args["service_id"].(string)
+	if !ok || serviceID == "" {
+		return nil, shared.InvalidArgument("Service ID is required")
+	}
+
+	hostname, ok := args["hostname"].(string)
+	if !ok || !hostnamePattern.MatchString(hostname) {
+		return nil, shared.InvalidArgument("Hostname is required and must be lowercase alphanumeric, max 25 characters")
+	}
+
+	copySecrets, _ := args["copy_secrets"].(bool)
+
+	servicePath := path.ServiceStackId{Id: uuid.ServiceStackId(serviceID)}
+	serviceResp, err := client.GetServiceStack(ctx, servicePath)
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to get service")
+	}
+
+	serviceOutput, err := serviceResp.Output()
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to get service")
+	}
+
+	targetProjectID := string(serviceOutput.ProjectId)
+	if projectID, ok := args["project_id"].(string); ok && projectID != "" {
+		targetProjectID = projectID
+	}
+
+	exportResp, err := client.GetServiceStackExport(ctx, servicePath)
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to export service")
+	}
+
+	exportOutput, err := exportResp.Output()
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to export service")
+	}
+
+	cloneYaml, err := buildCloneYaml(exportOutput.Yaml.Native(), serviceOutput.Name.Native(), hostname, copySecrets)
+	if err != nil {
+		return nil, err
+	}
+
+	recordSnapshot(ctx, client, targetProjectID, "service_clone", hostname)
+
+	importResp, err := client.PostServiceStackImport(ctx, body.ServiceStackImport{
+		ProjectId: uuid.ProjectId(targetProjectID),
+		Yaml:      types.NewText(cloneYaml),
+	})
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Clone failed")
+	}
+
+	importOutput, err := importResp.Output()
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Clone failed")
+	}
+
+	result := serviceCloneResult{
+		Status:        "clone_started",
+		SourceService: serviceOutput.Name.Native(),
+		Hostname:      hostname,
+		ProjectID:     string(importOutput.ProjectId),
+		SecretsCopied: copySecrets,
+		YAML:          cloneYaml,
+	}
+
+	for _, stack := range importOutput.ServiceStacks {
+		result.ServiceID = string(stack.Id)
+		if stack.Error != nil {
+			result.Error = stack.Error
+		}
+		if len(stack.Processes) > 0 {
+			result.ProcessID = string(stack.Processes[0].Id)
+		}
+	}
+
+	result.Message = fmt.Sprintf("Cloning '%s' as '%s'. Use 'get_process_status' to monitor progress.", serviceOutput.Name.Native(), hostname)
+	if result.ProcessID != "" {
+		result.SuggestNext(shared.WaitForProcess(result.ProcessID, fmt.Sprintf("Wait until '%s' is created", hostname)))
+	}
+	return result, nil
+}
+
+// buildCloneYaml rewrites a service export into an import YAML for a single new service
+func buildCloneYaml(exportYaml, sourceHostname, hostname string, copySecrets bool) (string, error) {
+	source, err := cloneServiceDefinition(exportYaml, sourceHostname, hostname, copySecrets)
+	if err != nil {
+		return "", err
+	}
+
+	out, err := yaml.Marshal(map[string]interface{}{
+		"services": []interface{}{source},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to build clone YAML: %w", err)
+	}
+	return string(out), nil
+}
+
+// cloneServiceDefinition returns the import definition of the exported service renamed to hostname
+func cloneServiceDefinition(exportYaml, sourceHostname, hostname string, copySecrets bool) (map[string]interface{}, error) {
+	var export map[string]interface{}
+	if err := yaml.Unmarshal([]byte(exportYaml), &export); err != nil {
+		return nil, shared.NewToolError(shared.ErrAPIUnavailable, "Failed to parse service export: %v", err)
+	}
+
+	services, _ := export["services"].([]interface{})
+	var source map[string]interface{}
+	for _, item := range services {
+		if service, ok := item.(map[string]interface{}); ok {
+			if source == nil || service["hostname"] == sourceHostname {
+				source = service
+			}
+		}
+	}
+	if source == nil {
+		return nil, shared.NewToolError(shared.ErrAPIUnavailable, "Service export does not contain a service definition")
+	}
+
+	source["hostname"] = hostname
+	if !copySecrets {
+		for _, key := range secretKeys {
+			delete(source, key)
+		}
+	}
+	return source, nil
+}
@@ -0,0 +1,132 @@
+package tools
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/dto/input/path"
+	"github.com/zeropsio/zerops-go/sdk"
+	"github.com/zeropsio/zerops-go/types/uuid"
+)
+
+// readStampTTL is how long a read stays the base of the optimistic lock; after that the
+// service counts as unread and is not checked
+const readStampTTL = 24 * time.Hour
+
+// serviceStamp is the lastUpdate of a service and when the agent read it
+type serviceStamp struct {
+	LastUpdate time.Time `json:"last_update"`
+	Read       time.Time `json:"read"`
+}
+
+// serviceStamps remembers the lastUpdate of each service as the agent last read it,
+// so mutations can detect changes made in the meantime (e.g. by a teammate in the GUI)
+type serviceStamps struct {
+	mu        sync.Mutex
+	stamps    map[string]serviceStamp
+	lastSweep time.Time
+}
+
+var readStamps = &serviceStamps{stamps: make(map[string]serviceStamp)}
+
+func stampKey(ctx context.Context, serviceID string) string {
+	return actionOwner(ctx) + "/" + serviceID
+}
+
+// rememberServiceStamp records the lastUpdate the agent has just seen for a service
+func rememberServiceStamp(ctx context.Context, serviceID string, lastUpdate time.Time) {
+	now := time.Now()
+	readStamps.mu.Lock()
+	readStamps.sweepLocked(now)
+	readStamps.stamps[stampKey(ctx, serviceID)] = serviceStamp{LastUpdate: lastUpdate, Read: now}
+	readStamps.mu.Unlock()
+	shared.SessionStateChanged()
+}
+
+// sweepLocked drops stamps read more than readStampTTL ago, at most once a minute;
+// the caller must hold s.mu
+func (s *serviceStamps) sweepLocked(now time.Time) {
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+	s.lastSweep = now
+	for key, stamp := range s.stamps {
+		if now.Sub(stamp.Read) > readStampTTL {
+			delete(s.stamps, key)
+		}
+	}
+}
+
+// forgetServiceStamp drops the stamp after our own mutation changed the service
+func forgetServiceStamp(ctx context.Context, serviceID string) {
+	readStamps.mu.Lock()
+	delete(readStamps.stamps, stampKey(ctx, serviceID))
+	readStamps.mu.Unlock()
+	shared.SessionStateChanged()
+}
+
+func lookupServiceStamp(ctx context.Context, serviceID string) (time.Time, bool) {
+	readStamps.mu.Lock()
+	defer readStamps.mu.Unlock()
+	stamp, ok := readStamps.stamps[stampKey(ctx, serviceID)]
+	if !ok || time.Since(stamp.Read) > readStampTTL {
+		return time.Time{}, false
+	}
+	return stamp.LastUpdate, true
+}
+
+// expectedLastUpdateProperty is the input schema for the explicit optimistic lock
+func expectedLastUpdateProperty() map[string]interface{} {
+	return map[string]interface{}{
+		"type":        "string",
+		"description": "OPTIONAL: Service last_update from discovery (RFC3339). The call fails with CONFLICT if the service changed since. Defaults to the value seen by the last discovery call.",
+	}
+}
+
+// formatServiceStamp renders a service's last_update so it can be passed back as
+// expected_last_update: RFC3339 in the display timezone, whatever date format the session chose
+func formatServiceStamp(t time.Time, display *displayFormat) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.In(display.loc).Format(time.RFC3339)
+}
+
+// checkServiceUnchanged aborts a mutation with a conflict error when the service was modified
+// after the agent last read it. Services the agent has not read are not checked.
+func checkServiceUnchanged(ctx context.Context, client *sdk.Handler, args map[string]interface{}, serviceID string) error {
+	if skip, _ := ctx.Value("skipServiceLock").(bool); skip {
+		return nil
+	}
+
+	expected, ok := lookupServiceStamp(ctx, serviceID)
+	if value, _ := args["expected_last_update"].(string); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return shared.InvalidArgument("expected_last_update must be an RFC3339 timestamp, got '%s'", value)
+		}
+		expected, ok = parsed, true
+	}
+	if !ok {
+		return nil
+	}
+
+	serviceResp, err := client.GetServiceStack(ctx, path.ServiceStackId{Id: uuid.ServiceStackId(serviceID)})
+	if err != nil {
+		return shared.WrapAPIError(err, "Failed to get service")
+	}
+	service, err := serviceResp.Output()
+	if err != nil {
+		return shared.WrapAPIError(err, "Failed to get service")
+	}
+
+	// RFC3339 output has second precision
+	current := service.LastUpdate.Native()
+	if !current.Truncate(time.Second).Equal(expected.Truncate(time.Second)) {
+		return shared.Conflict("Service '%s' was modified at %s, after you last read it (%s). Run discovery again and re-check before retrying.",
+			service.Name.Native(), current.UTC().Format(time.RFC3339), expected.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
@@ -0,0 +1,305 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/dto/output"
+	"github.com/zeropsio/zerops-go/sdk"
+	"github.com/zeropsio/zerops-go/types/enum"
+)
+
+const (
+	// defaultMetricsHours is the range of get_service_metrics when hours is omitted
+	defaultMetricsHours = 24
+	// maxMetricsHours bounds the range to 30 days
+	maxMetricsHours = 720
+	// hourlyMetricsHours is the longest range returned hourly by default; longer ones are daily
+	hourlyMetricsHours = 72
+	// highUtilization and lowUtilization are the peak and average percentages of a limit
+	// at which get_service_metrics suggests scaling up or down
+	highUtilization = 85.0
+	lowUtilization  = 20.0
+)
+
+// RegisterServiceMetrics registers the get_service_metrics tool
+func RegisterServiceMetrics() {
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "get_service_metrics",
+		Description: toolDescription("get_service_metrics"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"service_id": map[string]interface{}{
+					"type":        "string",
+					"description": "REQUIRED: Service ID from discovery tool",
+					"pattern":     "^[A-Za-z0-9_-]+$",
+				},
+				"hours": map[string]interface{}{
+					"type":        "integer",
+					"description": fmt.Sprintf("OPTIONAL: How many hours back to return (default: %d, max: %d)", defaultMetricsHours, maxMetricsHours),
+					"minimum":     1,
+					"maximum":     maxMetricsHours,
+				},
+				"resolution": map[string]interface{}{
+					"type":        "string",
+					"description": fmt.Sprintf("OPTIONAL: Bucket size of the series (default: hour up to %d hours, day for longer ranges)", hourlyMetricsHours),
+					"enum":        []string{"hour", "day"},
+				},
+				"per_container": map[string]interface{}{
+					"type":        "boolean",
+					"description": "OPTIONAL: Return a series per container instead of one for the service (default: false)",
+					"default":     false,
+				},
+				"timezone": timezoneProperty(),
+			},
+			"required":             []string{"service_id"},
+			"additionalProperties": false,
+		},
+		Annotations: shared.ReadOnly(),
+		Output:      serviceMetricsResult{},
+		Handler:     handleGetServiceMetrics,
+	})
+}
+
+// serviceMetricsResult is the result of get_service_metrics. Series and Summary are set for
+// the whole service, Containers when per_container is requested.
+type serviceMetricsResult struct {
+	ServiceID  string             `json:"service_id"`
+	Hostname   string             `json:"hostname"`
+	From       string             `json:"from"`
+	Till       string             `json:"till"`
+	Resolution string             `json:"resolution"`
+	Units      string             `json:"units"`
+	Series     []metricsPoint     `json:"series,omitempty"`
+	Summary    *metricsSummary    `json:"summary,omitempty"`
+	Hints      []string           `json:"hints,omitempty"`
+	Containers []containerMetrics `json:"containers,omitempty"`
+	Message    string             `json:"message,omitempty"`
+}
+
+// containerMetrics is the statistics of one container
+type containerMetrics struct {
+	ContainerID string         `json:"container_id"`
+	Series      []metricsPoint `json:"series"`
+	Summary     metricsSummary `json:"summary"`
+	Hints       []string       `json:"hints,omitempty"`
+}
+
+// metricsPoint is one statistics interval; the *_pct fields are null when the limit is unknown
+type metricsPoint struct {
+	From        string   `json:"from"`
+	CPUUsed     float64  `json:"cpu_used"`
+	CPULimit    float64  `json:"cpu_limit"`
+	CPUPct      *float64 `json:"cpu_pct"`
+	RAMUsedGB   float64  `json:"ram_used_gb"`
+	RAMLimitGB  float64  `json:"ram_limit_gb"`
+	RAMPct      *float64 `json:"ram_pct"`
+	DiskUsedGB  float64  `json:"disk_used_gb"`
+	DiskLimitGB float64  `json:"disk_limit_gb"`
+	DiskPct     *float64 `json:"disk_pct"`
+	Containers  *int     `json:"containers,omitempty"`
+}
+
+// metricsSummary is the average and peak utilization of each resource over a series
+type metricsSummary struct {
+	Samples int                  `json:"samples"`
+	CPU     *resourceUtilization `json:"cpu,omitempty"`
+	RAM     *resourceUtilization `json:"ram,omitempty"`
+	Disk    *resourceUtilization `json:"disk,omitempty"`
+}
+
+// resourceUtilization is a resource's use in percent of its limit
+type resourceUtilization struct {
+	AvgPct  float64 `json:"avg_pct"`
+	PeakPct float64 `json:"peak_pct"`
+}
+
+func handleGetServiceMetrics(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+
+	serviceID, _ := args["service_id"].(string)
+	if serviceID == "" {
+		return nil, shared.InvalidArgument("Service ID is required")
+	}
+	hours := defaultMetricsHours
+	if value, ok := args["hours"].(float64); ok {
+		hours = int(value)
+	}
+	if hours < 1 || hours > maxMetricsHours {
+		return nil, shared.InvalidArgument("hours must be between 1 and %d", maxMetricsHours)
+	}
+	resolution, _ := args["resolution"].(string)
+	if resolution == "" {
+		resolution = "hour"
+		if hours > hourlyMetricsHours {
+			resolution = "day"
+		}
+	}
+	if resolution != "hour" && resolution != "day" {
+		return nil, shared.InvalidArgument("resolution must be hour or day")
+	}
+	perContainer, _ := args["per_container"].(bool)
+	display, err := resolveDisplayFormat(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	service, err := getServiceStack(ctx, client, serviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	groupBy := enum.EsStatsHistoryGroupByEnumServiceStackId
+	if perContainer {
+		groupBy = enum.EsStatsHistoryGroupByEnumContainerId
+	}
+	till := time.Now().UTC()
+	from := till.Add(-time.Duration(hours) * time.Hour)
+	history, err := statsHistory(ctx, client, "serviceStackId", serviceID, groupBy, from, till, display.loc, resolution)
+	if err != nil {
+		return nil, err
+	}
+
+	result := serviceMetricsResult{
+		ServiceID:  serviceID,
+		Hostname:   service.Name.Native(),
+		From:       formatTimestamp(from, display),
+		Till:       formatTimestamp(till, display),
+		Resolution: resolution,
+		Units:      "cpu in cores, ram and disk in GB, *_pct as percent of the limit",
+	}
+
+	if !perContainer {
+		series, summary := metricsSeries(history, display)
+		result.Series = series
+		result.Summary = &summary
+		result.Hints = scalingHints(summary)
+		if len(series) == 0 {
+			result.Message = "No statistics in the range; the service may not have been running."
+		}
+		return result, nil
+	}
+
+	byContainer := map[string][]output.EsStatsHistory{}
+	for _, item := range history {
+		id, _ := item.ContainerId.Get()
+		byContainer[id.Native()] = append(byContainer[id.Native()], item)
+	}
+	ids := make([]string, 0, len(byContainer))
+	for id := range byContainer {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	containers := make([]containerMetrics, 0, len(ids))
+	for _, id := range ids {
+		series, summary := metricsSeries(byContainer[id], display)
+		containers = append(containers, containerMetrics{
+			ContainerID: id,
+			Series:      series,
+			Summary:     summary,
+			Hints:       scalingHints(summary),
+		})
+	}
+	result.Containers = containers
+	if len(containers) == 0 {
+		result.Message = "No statistics in the range; the service may not have been running."
+	}
+	return result, nil
+}
+
+// metricsSeries turns statistics of one service or container into a time-ordered series and
+// a summary with the average and peak utilization of each resource
+func metricsSeries(history []output.EsStatsHistory, display *displayFormat) ([]metricsPoint, metricsSummary) {
+	sort.Slice(history, func(i, j int) bool {
+		return history[i].From.Native().Before(history[j].From.Native())
+	})
+
+	type utilization struct {
+		sum, peak float64
+		samples   int
+	}
+	resources := map[string]*utilization{"cpu": {}, "ram": {}, "disk": {}}
+	percent := func(resource string, used, limit float64) *float64 {
+		if limit <= 0 {
+			return nil
+		}
+		value := used / limit * 100
+		u := resources[resource]
+		u.sum += value
+		u.samples++
+		u.peak = math.Max(u.peak, value)
+		rounded := round2(value)
+		return &rounded
+	}
+
+	series := make([]metricsPoint, 0, len(history))
+	for _, item := range history {
+		point := metricsPoint{
+			From:        formatTimestamp(item.From.Native(), display),
+			CPUUsed:     round2(item.CpuUsed.Native()),
+			CPULimit:    round2(item.CpuLimit.Native()),
+			CPUPct:      percent("cpu", item.CpuUsed.Native(), item.CpuLimit.Native()),
+			RAMUsedGB:   round2(item.RamUsed.Native() / 1e9),
+			RAMLimitGB:  round2(item.RamLimit.Native() / 1e9),
+			RAMPct:      percent("ram", item.RamUsed.Native(), item.RamLimit.Native()),
+			DiskUsedGB:  round2(item.DiskUsed.Native() / 1e9),
+			DiskLimitGB: round2(item.DiskLimit.Native() / 1e9),
+			DiskPct:     percent("disk", item.DiskUsed.Native(), item.DiskLimit.Native()),
+		}
+		if containers, ok := item.ContainerCount.Get(); ok {
+			count := containers.Native()
+			point.Containers = &count
+		}
+		series = append(series, point)
+	}
+
+	stats := func(u *utilization) *resourceUtilization {
+		if u.samples == 0 {
+			return nil
+		}
+		return &resourceUtilization{
+			AvgPct:  round2(u.sum / float64(u.samples)),
+			PeakPct: round2(u.peak),
+		}
+	}
+	summary := metricsSummary{
+		Samples: len(series),
+		CPU:     stats(resources["cpu"]),
+		RAM:     stats(resources["ram"]),
+		Disk:    stats(resources["disk"]),
+	}
+	return series, summary
+}
+
+// scalingHints suggests scale_service changes for resources that peak near their limit
+// or stay mostly idle
+func scalingHints(summary metricsSummary) []string {
+	var hints []string
+	for i, stats := range []*resourceUtilization{summary.CPU, summary.RAM, summary.Disk} {
+		if stats == nil {
+			continue
+		}
+		resource := []string{"cpu", "ram", "disk"}[i]
+		avg, peak := stats.AvgPct, stats.PeakPct
+		switch {
+		case peak >= highUtilization && resource == "disk":
+			hints = append(hints, fmt.Sprintf("disk peaked at %.0f%% of its limit; free space or raise the disk limit in the Zerops GUI", peak))
+		case peak >= highUtilization:
+			hints = append(hints, fmt.Sprintf("%s peaked at %.0f%% of its limit; consider raising max_%s with scale_service", resource, peak, resource))
+		case resource != "disk" && avg < lowUtilization && peak < 2*lowUtilization:
+			hints = append(hints, fmt.Sprintf("%s averaged %.0f%% of its limit (peak %.0f%%); lowering min_%s with scale_service may save costs", resource, avg, peak, resource))
+		}
+	}
+	return hints
+}
+
+func round2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -6,16 +6,20 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"os"
+	"net/url"
 	"strings"
 	"time"
 
-	"github.com/zerops-mcp-basic/internal/handlers/shared"
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/apiError"
 	"github.com/zeropsio/zerops-go/dto/input/body"
 	"github.com/zeropsio/zerops-go/dto/input/path"
 	"github.com/zeropsio/zerops-go/dto/input/query"
+	"github.com/zeropsio/zerops-go/dto/output"
+	"github.com/zeropsio/zerops-go/errorCode"
 	"github.com/zeropsio/zerops-go/sdk"
 	"github.com/zeropsio/zerops-go/types"
+	"github.com/zeropsio/zerops-go/types/enum"
 	"github.com/zeropsio/zerops-go/types/uuid"
 	"gopkg.in/yaml.v3"
 )
@@ -62,53 +66,22 @@ var severityLevels = map[string]int{
 func RegisterServiceTools() {
 	// Get service types
 	shared.GlobalRegistry.Register(&shared.ToolDefinition{
-		Name: "get_service_types",
-		Description: `Returns comprehensive list of available Zerops service types and versions.
-
-WHEN TO USE:
-- Before importing services to verify correct type names
-- To explore available runtime options
-- When service import fails with "serviceStackTypeNotFound"
-
-IMPORTANT: Service types use specific naming format:
-- Format: "runtime@version" (e.g., "nodejs@22", "postgresql@16")
-- NOT "node@22" or "postgres@16"
-- NOT "php-apache@8.3" (use "php@8.3")
-
-Returns current available types including:
-- Runtime services: nodejs, python, go, php, rust, etc.
-- Databases: postgresql, mariadb, mongodb, etc.
-- Cache: redis, valkey, keydb
-- Storage: objectstorage, elasticsearch
-- Web servers: nginx, static
-
-Use knowledge_base tool for detailed configuration examples.`,
+		Name:        "get_service_types",
+		Description: toolDescription("get_service_types"),
 		InputSchema: map[string]interface{}{
 			"type":                 "object",
 			"properties":           map[string]interface{}{},
 			"additionalProperties": false,
 		},
-		Handler: handleGetServiceTypes,
+		Annotations: shared.ReadOnly(),
+		Output:      serviceTypesResult{},
+		Handler:     handleGetServiceTypes,
 	})
 
 	// Import services
 	shared.GlobalRegistry.Register(&shared.ToolDefinition{
-		Name: "import_services",
-		Description: `Imports services into a Zerops project using YAML configuration.
-
-CRITICAL WORKFLOW:
-1. Import databases FIRST (postgresql, redis, objectstorage)
-2. Then import runtime services with startWithoutCode: true for dev
-3. MANDATORY: Deploy hello-world pattern before real development
-4. Monitor all imports with get_process_status
-
-YAML STRUCTURE:
-services:
-  - hostname: servicename    # alphanumeric only
-    type: runtime@version    # from get_service_types
-    startWithoutCode: true   # REQUIRED for dev services
-
-Use knowledge_base or load_platform_guide for complete workflow patterns and examples.`,
+		Name:        "import_services",
+		Description: toolDescription("import_services"),
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
@@ -122,33 +95,29 @@ Use knowledge_base or load_platform_guide for complete workflow patterns and exa
 					"description": "REQUIRED: YAML configuration for services. Must include 'services' array with hostname, type, and optional configuration. Use knowledge_base or load_platform_guide for examples.",
 					"minLength":   10,
 				},
+				"dry_run": map[string]interface{}{
+					"type":        "boolean",
+					"description": "OPTIONAL: Only check the YAML and report problems, without importing (default: false)",
+					"default":     false,
+				},
+				"strict": map[string]interface{}{
+					"type":        "boolean",
+					"description": "OPTIONAL: Refuse to import when lint_import_yaml rules report findings (default: false)",
+					"default":     false,
+				},
 			},
 			"required":             []string{"yaml"},
 			"additionalProperties": false,
 		},
-		Handler: handleImportServices,
+		Annotations: shared.Mutating(false, false),
+		Output:      importServicesResult{},
+		Handler:     handleImportServices,
 	})
 
 	// Enable preview subdomain
 	shared.GlobalRegistry.Register(&shared.ToolDefinition{
-		Name: "enable_preview_subdomain",
-		Description: `Enables public subdomain access for a web service, making it accessible via HTTPS URL.
-
-BEHAVIOR:
-- If subdomain is already enabled: Returns existing URL immediately
-- If not enabled: Starts enablement process asynchronously
-
-REQUIREMENTS:
-- service_id: Get from discovery tool
-- Service must be a web service (not databases)
-- Service must have appropriate port configuration
-
-RESULT:
-- Generates public URL: https://servicename-projectname.prg1.zerops.app
-- Enables HTTPS access with automatic SSL certificate
-- For new enablement: Use 'get_running_processes' to monitor progress
-
-NOTE: Only works for web services. Databases and internal services don't need subdomains.`,
+		Name:        "enable_preview_subdomain",
+		Description: toolDescription("enable_preview_subdomain"),
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
@@ -157,37 +126,25 @@ NOTE: Only works for web services. Databases and internal services don't need su
 					"description": "REQUIRED: Service ID from discovery tool. Must be a web service (not database).",
 					"pattern":     "^[A-Za-z0-9_-]+$",
 				},
+				"wait": map[string]interface{}{
+					"type":        "boolean",
+					"description": "OPTIONAL: Wait until the subdomain is enabled and return its URLs (default: false)",
+					"default":     false,
+				},
+				"expected_last_update": expectedLastUpdateProperty(),
 			},
 			"required":             []string{"service_id"},
 			"additionalProperties": false,
 		},
-		Handler: handleEnablePreviewSubdomain,
+		Annotations: shared.Mutating(false, true),
+		Output:      previewSubdomainResult{},
+		Handler:     handleEnablePreviewSubdomain,
 	})
 
 	// Scale service
 	shared.GlobalRegistry.Register(&shared.ToolDefinition{
-		Name: "scale_service",
-		Description: `Configures scaling parameters for a service including CPU, RAM, and container count.
-
-SCALING OPTIONS:
-- CPU: 0.25 to 20 cores (decimal values allowed)
-- RAM: 0.5 to 32 GB (decimal values allowed)
-- Containers: 1 to 6 containers per service
-
-AUTO-SCALING:
-- Set min/max values for automatic scaling based on load
-- Single values set fixed allocation
-- Leave parameters empty to keep current settings
-
-EXAMPLES:
-- Basic: min_cpu: 1, max_cpu: 2, min_ram: 1, max_ram: 2
-- Fixed: min_cpu: 2, max_cpu: 2 (no auto-scaling)
-- High-performance: min_cpu: 4, max_cpu: 8, min_containers: 2
-
-WHEN TO USE:
-- After service creation for performance optimization
-- When experiencing resource constraints
-- For production scaling configuration`,
+		Name:        "scale_service",
+		Description: toolDescription("scale_service"),
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
@@ -197,15 +154,15 @@ WHEN TO USE:
 					"pattern":     "^[A-Za-z0-9_-]+$",
 				},
 				"min_cpu": map[string]interface{}{
-					"type":        "number",
-					"description": "Minimum CPU cores (0.25 to 20). Decimal values allowed.",
-					"minimum":     0.25,
+					"type":        "integer",
+					"description": "Minimum CPU cores (1 to 20, whole cores)",
+					"minimum":     1,
 					"maximum":     20,
 				},
 				"max_cpu": map[string]interface{}{
-					"type":        "number",
-					"description": "Maximum CPU cores (0.25 to 20). Must be >= min_cpu.",
-					"minimum":     0.25,
+					"type":        "integer",
+					"description": "Maximum CPU cores (1 to 20, whole cores). Must be >= min_cpu.",
+					"minimum":     1,
 					"maximum":     20,
 				},
 				"min_ram": map[string]interface{}{
@@ -222,58 +179,30 @@ WHEN TO USE:
 				},
 				"min_containers": map[string]interface{}{
 					"type":        "integer",
-					"description": "Minimum container count (1 to 6)",
+					"description": "Minimum container count (1 to 10, limited by the service plan)",
 					"minimum":     1,
-					"maximum":     6,
+					"maximum":     10,
 				},
 				"max_containers": map[string]interface{}{
 					"type":        "integer",
-					"description": "Maximum container count (1 to 6). Must be >= min_containers.",
+					"description": "Maximum container count (1 to 10, limited by the service plan). Must be >= min_containers.",
 					"minimum":     1,
-					"maximum":     6,
+					"maximum":     10,
 				},
+				"expected_last_update": expectedLastUpdateProperty(),
 			},
 			"required":             []string{"service_id"},
 			"additionalProperties": false,
 		},
-		Handler: handleScaleService,
+		Annotations: shared.Mutating(true, true),
+		Output:      scaleServiceResult{},
+		Handler:     handleScaleService,
 	})
 
 	// Get service logs
 	shared.GlobalRegistry.Register(&shared.ToolDefinition{
-		Name: "get_service_logs",
-		Description: `Retrieves logs from a specific service with comprehensive filtering options.
-
-LOG OPTIONS:
-- limit: Number of recent log lines (default: 100, max: 1000)
-- minimum_severity: Filter by minimum log severity level
-- message_type: Type of messages to retrieve (APPLICATION, SYSTEM, BUILD)
-- format: Log format (FULL, SHORT, JSON)
-- format_template: Custom format template for log output
-- follow: Stream logs in real-time (boolean)
-- show_build_logs: Show build logs instead of runtime logs (boolean)
-
-SEVERITY LEVELS:
-- debug, info, warning, error, critical
-
-MESSAGE TYPES:
-- APPLICATION: Application stdout/stderr logs
-- SYSTEM: System and runtime logs
-- BUILD: Build and deployment logs
-
-FORMATS:
-- FULL: Complete log information with timestamps
-- SHORT: Condensed log format
-- JSON: Machine-readable JSON format
-
-WHEN TO USE:
-- Debugging service issues
-- Monitoring application behavior
-- Checking deployment status
-- Investigating errors
-- Real-time log monitoring with follow=true
-
-NOTE: Large log requests may take time. Start with smaller line counts.`,
+		Name:        "get_service_logs",
+		Description: toolDescription("get_service_logs") + logTemplateFieldsDoc(),
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
@@ -284,11 +213,15 @@ NOTE: Large log requests may take time. Start with smaller line counts.`,
 				},
 				"limit": map[string]interface{}{
 					"type":        "integer",
-					"description": "Number of log lines to retrieve (1-1000, default: 100)",
+					"description": "Number of log lines to retrieve (1-10000, default: 100); over 200 are returned in chunks",
 					"minimum":     1,
-					"maximum":     1000,
+					"maximum":     maxServiceLogs,
 					"default":     100,
 				},
+				"continuation_token": map[string]interface{}{
+					"type":        "string",
+					"description": "Token from pagination.continuation_token of the previous call; returns the next, older chunk",
+				},
 				"minimum_severity": map[string]interface{}{
 					"type":        "string",
 					"description": "Minimum severity level (debug, info, warning, error, critical)",
@@ -308,37 +241,43 @@ NOTE: Large log requests may take time. Start with smaller line counts.`,
 				},
 				"format_template": map[string]interface{}{
 					"type":        "string",
-					"description": "Custom format template for log output (optional)",
+					"description": "Go text/template applied to each log line, or a preset name: nginx, json-app, compact (optional)",
 				},
 				"follow": map[string]interface{}{
 					"type":        "boolean",
-					"description": "Stream logs in real-time (default: false)",
+					"description": "Stream new log lines until cancelled or follow_duration passes (default: false)",
 					"default":     false,
 				},
+				"follow_duration": map[string]interface{}{
+					"type":        "integer",
+					"description": "Seconds to follow logs with follow=true (1-600, default: 60)",
+					"minimum":     1,
+					"maximum":     600,
+				},
 				"show_build_logs": map[string]interface{}{
 					"type":        "boolean",
 					"description": "Show build logs instead of runtime logs (default: false)",
 					"default":     false,
 				},
+				"app_version_id": map[string]interface{}{
+					"type":        "string",
+					"description": "App version whose build logs to show with show_build_logs (default: latest build)",
+					"pattern":     "^[A-Za-z0-9_-]+$",
+				},
+				"timezone": timezoneProperty(),
 			},
 			"required":             []string{"service_id"},
 			"additionalProperties": false,
 		},
-		Handler: handleGetServiceLogs,
+		Annotations: shared.ReadOnly(),
+		Output:      serviceLogsResult{},
+		Handler:     handleGetServiceLogs,
 	})
 
 	// Restart service
 	shared.GlobalRegistry.Register(&shared.ToolDefinition{
-		Name: "restart_service",
-		Description: `Restarts a service (async operation returning process_id).
-
-CRITICAL REQUIREMENTS:
-- MANDATORY after setting environment variables
-- Must restart dependent services that read changed variables
-- Monitor completion with get_process_status
-- Environment variables NOT available until restart completes
-
-Use knowledge_base or load_platform_guide for complete restart workflow and dependency patterns.`,
+		Name:        "restart_service",
+		Description: toolDescription("restart_service"),
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
@@ -347,32 +286,20 @@ Use knowledge_base or load_platform_guide for complete restart workflow and depe
 					"description": "REQUIRED: Service ID from discovery tool",
 					"pattern":     "^[A-Za-z0-9_-]+$",
 				},
+				"expected_last_update": expectedLastUpdateProperty(),
 			},
 			"required":             []string{"service_id"},
 			"additionalProperties": false,
 		},
-		Handler: handleRestartService,
+		Annotations: shared.Mutating(true, false),
+		Output:      restartServiceResult{},
+		Handler:     handleRestartService,
 	})
 
 	// Remount service
 	shared.GlobalRegistry.Register(&shared.ToolDefinition{
-		Name: "remount_service",
-		Description: `Reconnects SSHFS mounts for a service (fixes file system connection issues).
-
-WHEN TO USE:
-- When file system access is broken
-- After network connectivity issues
-- When getting file permission errors
-- To refresh SSHFS connections
-- After deploying a new version of any service and need to work on it
-- After restarting any service
-
-RETURNS:
-- mkdir command to create mount directory (required first)
-- sshfs command to reconnect the mount
-- Step-by-step instructions
-
-NOTE: Always run mkdir first, then sshfs command.`,
+		Name:        "remount_service",
+		Description: toolDescription("remount_service"),
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
@@ -385,24 +312,15 @@ NOTE: Always run mkdir first, then sshfs command.`,
 			"required":             []string{"service_name"},
 			"additionalProperties": false,
 		},
-		Handler: handleRemountService,
+		Annotations: shared.Mutating(false, true),
+		Output:      remountServiceResult{},
+		Handler:     handleRemountService,
 	})
 
 	// Get process status
 	shared.GlobalRegistry.Register(&shared.ToolDefinition{
-		Name: "get_process_status",
-		Description: `Gets the status of a specific process by its ID.
-
-WHEN TO USE:
-- Monitor async operations (restart_service, enable_preview_subdomain)
-- Check if a process completed successfully
-- Get detailed process information
-
-PROCESS STATES:
-- running: Process is actively running
-- completed: Process finished successfully
-- failed: Process encountered an error
-- pending: Process is queued/starting`,
+		Name:        "get_process_status",
+		Description: toolDescription("get_process_status"),
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
@@ -411,101 +329,94 @@ PROCESS STATES:
 					"description": "REQUIRED: Process ID returned from async operations",
 					"pattern":     "^[A-Za-z0-9_-]+$",
 				},
+				"timezone": timezoneProperty(),
 			},
 			"required":             []string{"process_id"},
 			"additionalProperties": false,
 		},
-		Handler: handleGetProcessStatus,
+		Annotations: shared.ReadOnly(),
+		Output:      processStatusResult{},
+		Handler:     handleGetProcessStatus,
 	})
 }
 
+// serviceTypesResult is the result of get_service_types
+type serviceTypesResult struct {
+	ServiceTypes []string `json:"service_types"`
+	Count        int      `json:"count"`
+	Note         string   `json:"note"`
+}
+
 func handleGetServiceTypes(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
 	if client == nil {
-		return shared.ErrorResponse("No API key provided"), nil
+		return nil, shared.ErrNoClient
 	}
 
-	// Search for all available service types
-	filter := body.EsFilter{
-		// Get available service types
-	}
-
-	resp, err := client.PostServiceStackTypeSearch(ctx, filter)
+	serviceTypes, err := availableServiceTypes(ctx, client)
 	if err != nil {
-		return shared.ErrorResponse(fmt.Sprintf("Failed to get service types: %v", err)), nil
+		return nil, err
 	}
 
-	output, err := resp.Output()
-	if err != nil {
-		return shared.ErrorResponse(fmt.Sprintf("Failed to parse response: %v", err)), nil
-	}
-
-	var serviceTypes []string
-	for _, item := range output.Items {
-		// Extract service type name
-		baseName := item.Name.Native()
-
-		// Filter out internal build/prepare services and unavailable services
-		if strings.HasPrefix(baseName, "build ") ||
-			strings.HasPrefix(baseName, "prepare ") ||
-			strings.HasPrefix(baseName, "zbuild ") ||
-			baseName == "MongoDB" ||
-			baseName == "RabbitMQ" ||
-			baseName == "Core" ||
-			baseName == "L7 HTTP Balancer" ||
-			baseName == "Generic Runtime" {
-			continue
-		}
-
-		// If there's a default version, add it
-		if item.DefaultServiceStackVersion != nil {
-			typeName := fmt.Sprintf("%s@%s",
-				baseName,
-				item.DefaultServiceStackVersion.Name.Native())
-			serviceTypes = append(serviceTypes, typeName)
-		}
-
-		// Also add all available versions
-		for _, version := range item.ServiceStackTypeVersionList {
-			versionedType := fmt.Sprintf("%s@%s",
-				baseName,
-				version.Name.Native())
-			serviceTypes = append(serviceTypes, versionedType)
-		}
-	}
-
-	return map[string]interface{}{
-		"service_types": serviceTypes,
-		"count":         len(serviceTypes),
-		"note":          "Use knowledge_base tool for detailed configuration examples",
+	return serviceTypesResult{
+		ServiceTypes: serviceTypes,
+		Count:        len(serviceTypes),
+		Note:         "Use knowledge_base tool for detailed configuration examples",
 	}, nil
 }
 
+// importServicesResult is the result of import_services; dry runs return an
+// importDryRunResult instead
+type importServicesResult struct {
+	Status      string                `json:"status"`
+	ProjectID   string                `json:"project_id"`
+	ProjectName string                `json:"project_name"`
+	Services    []importServiceReport `json:"services"`
+	Count       int                   `json:"count"`
+	Created     int                   `json:"created"`
+	Failed      []string              `json:"failed,omitempty"`
+	RetryYAML   string                `json:"retry_yaml,omitempty"`
+	Lint        []importProblem       `json:"lint,omitempty"`
+	Message     string                `json:"message"`
+	shared.Suggestions
+}
+
 func handleImportServices(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
 	if client == nil {
-		return shared.ErrorResponse("No API key provided"), nil
+		return nil, shared.ErrNoClient
 	}
 
-	projectID, ok := args["project_id"].(string)
-	if !ok || projectID == "" {
-		// Check environment variable
-		if envProjectID := os.Getenv("projectId"); envProjectID != "" {
-			projectID = envProjectID
-		} else {
-			return shared.ErrorResponse("Project ID is required. Provide project_id parameter or set $projectId environment variable."), nil
-		}
+	projectID, err := resolveProjectID(ctx, client, args)
+	if err != nil {
+		return nil, err
 	}
 
 	yamlContent, ok := args["yaml"].(string)
 	if !ok || yamlContent == "" {
-		return shared.ErrorResponse("YAML content is required"), nil
+		return nil, shared.InvalidArgument("YAML content is required")
 	}
 
 	// Validate YAML
 	var yamlData interface{}
 	if err := yaml.Unmarshal([]byte(yamlContent), &yamlData); err != nil {
-		return shared.ErrorResponse(fmt.Sprintf("Invalid YAML: %v", err)), nil
+		return nil, shared.InvalidArgument("Invalid YAML: %v", err)
 	}
 
+	lint := lintProjectImport(ctx, client, projectID, yamlContent)
+	if dryRun, _ := args["dry_run"].(bool); dryRun {
+		result := dryRunImport(ctx, client, projectID, yamlContent)
+		result.Lint = lint
+		return result, nil
+	}
+	if strict, _ := args["strict"].(bool); strict && len(lint) > 0 {
+		lines := make([]string, 0, len(lint))
+		for _, finding := range lint {
+			lines = append(lines, fmt.Sprintf("- line %d %s [%s]: %s (fix: %s)", finding.Line, finding.Path, finding.Rule, finding.Message, finding.Fix))
+		}
+		return nil, shared.InvalidArgument("strict: %d lint finding(s) block the import:\n%s", len(lint), strings.Join(lines, "\n"))
+	}
+
+	recordSnapshot(ctx, client, projectID, "import_services", "")
+
 	importBody := body.ServiceStackImport{
 		ProjectId: uuid.ProjectId(projectID),
 		Yaml:      types.NewText(yamlContent),
@@ -513,86 +424,117 @@ func handleImportServices(ctx context.Context, client *sdk.Handler, args map[str
 
 	resp, err := client.PostServiceStackImport(ctx, importBody)
 	if err != nil {
-		errMsg := err.Error()
-		if strings.Contains(errMsg, "serviceStackTypeNotFound") {
-			return shared.ErrorResponse("Service type not found. Check available types with 'get_service_types' or 'knowledge_base'"), nil
-		}
-		return shared.ErrorResponse(fmt.Sprintf("Import failed: %v", err)), nil
+		return nil, shared.WrapAPIError(err, "Import failed")
 	}
 
 	output, err := resp.Output()
 	if err != nil {
-		return shared.ErrorResponse(fmt.Sprintf("Import failed: %v", err)), nil
+		if apiError.HasErrorCode(err, errorCode.ServiceStackTypeNotFound) {
+			return nil, shared.NotFound("Service type not found. Check available types with 'get_service_types' or 'knowledge_base'")
+		}
+		return nil, shared.WrapAPIError(err, "Import failed")
 	}
 
-	// Extract just the essential information from imported services
-	var importedServices []map[string]interface{}
-	for _, stack := range output.ServiceStacks {
-		serviceInfo := map[string]interface{}{
-			"id":       string(stack.Id),
-			"hostname": stack.Name.Native(),
+	// Report per-service outcome so partially failed imports are visible
+	var requested []string
+	if services, err := importYamlServices(yamlContent); err == nil {
+		for _, service := range services {
+			hostname, _ := service["hostname"].(string)
+			requested = append(requested, hostname)
 		}
-		
-		// Add error if present
-		if stack.Error != nil {
-			serviceInfo["error"] = stack.Error
+	}
+	report := buildImportReport(output, requested)
+
+	result := importServicesResult{
+		Status:      "import_completed",
+		ProjectID:   string(output.ProjectId),
+		ProjectName: output.ProjectName.Native(),
+		Services:    report.Services,
+		Count:       len(report.Services),
+		Created:     report.Created,
+		Lint:        lint,
+		Message:     "Services imported successfully. Use 'discovery' tool to get full details.",
+	}
+
+	if len(report.Failed) > 0 {
+		result.Status = "partial_failure"
+		if report.Created == 0 {
+			result.Status = "import_failed"
 		}
-		
-		// Add process count if there are processes
-		if len(stack.Processes) > 0 {
-			serviceInfo["process_count"] = len(stack.Processes)
-			
-			// Get the first process ID as the main import process
-			if len(stack.Processes) > 0 {
-				serviceInfo["import_process_id"] = string(stack.Processes[0].Id)
-			}
+		result.Failed = report.Failed
+		shared.Log(ctx, "warning", "import_services", fmt.Sprintf("%d of %d services failed to import", len(report.Failed), len(report.Services)))
+		result.Message = fmt.Sprintf("%d of %d services failed to import. Fix the errors and import 'retry_yaml' to create only the remaining services.", len(report.Failed), len(report.Services))
+		if retryYaml, err := filterImportYaml(yamlContent, report.Failed); err == nil {
+			result.RetryYAML = retryYaml
 		}
-		
-		importedServices = append(importedServices, serviceInfo)
 	}
 
-	return map[string]interface{}{
-		"status":       "import_completed",
-		"project_id":   string(output.ProjectId),
-		"project_name": output.ProjectName.Native(),
-		"services":     importedServices,
-		"count":        len(importedServices),
-		"message":      "Services imported successfully. Use 'discovery' tool to get full details.",
-	}, nil
+	if report.Created > 0 {
+		result.SuggestNext(shared.NextCall{
+			Tool:      "discovery",
+			Arguments: map[string]interface{}{"project_id": string(output.ProjectId)},
+			Purpose:   "Get the IDs and status of the imported services",
+		})
+	}
+	return result, nil
+}
+
+// previewSubdomainResult is the result of enable_preview_subdomain. URL is the first of
+// URLs; RoutingID and Subdomain are set when a custom domain routing already exists.
+type previewSubdomainResult struct {
+	Status    string         `json:"status"`
+	ProcessID string         `json:"process_id,omitempty"`
+	Subdomain string         `json:"subdomain,omitempty"`
+	URL       string         `json:"url,omitempty"`
+	URLs      []subdomainURL `json:"urls,omitempty"`
+	RoutingID string         `json:"routing_id,omitempty"`
+	Warnings  []string       `json:"warnings,omitempty"`
+	Message   string         `json:"message"`
+	shared.Suggestions
+}
+
+// subdomainURL is the zerops.app URL of one port of a service
+type subdomainURL struct {
+	Port int    `json:"port"`
+	URL  string `json:"url"`
 }
 
 func handleEnablePreviewSubdomain(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
 	if client == nil {
-		return shared.ErrorResponse("No API key provided"), nil
+		return nil, shared.ErrNoClient
 	}
 
 	serviceID, ok := args["service_id"].(string)
 	if !ok || serviceID == "" {
-		return shared.ErrorResponse("Service ID is required"), nil
+		return nil, shared.InvalidArgument("Service ID is required")
+	}
+
+	if err := checkServiceUnchanged(ctx, client, args, serviceID); err != nil {
+		return nil, err
 	}
 
 	// First, get the service details to obtain projectId
 	servicePath := path.ServiceStackId{Id: uuid.ServiceStackId(serviceID)}
 	serviceResp, err := client.GetServiceStack(ctx, servicePath)
 	if err != nil {
-		return shared.ErrorResponse(fmt.Sprintf("Failed to get service details: %v", err)), nil
+		return nil, shared.WrapAPIError(err, "Failed to get service details")
 	}
 
 	serviceOutput, err := serviceResp.Output()
 	if err != nil {
-		return shared.ErrorResponse(fmt.Sprintf("Failed to parse service details: %v", err)), nil
+		return nil, shared.WrapAPIError(err, "Failed to parse service details")
 	}
 
 	// Get project details to obtain clientId (following discovery tool pattern)
 	projectPath := path.ProjectId{Id: serviceOutput.ProjectId}
 	projectResp, err := client.GetProject(ctx, projectPath)
 	if err != nil {
-		return shared.ErrorResponse(fmt.Sprintf("Failed to get project details: %v", err)), nil
+		return nil, shared.WrapAPIError(err, "Failed to get project details")
 	}
 
 	projectOutput, err := projectResp.Output()
 	if err != nil {
-		return shared.ErrorResponse(fmt.Sprintf("Failed to parse project details: %v", err)), nil
+		return nil, shared.WrapAPIError(err, "Failed to parse project details")
 	}
 
 	// Now check if subdomain access is already enabled by searching for existing HTTP routing
@@ -618,12 +560,22 @@ func handleEnablePreviewSubdomain(ctx context.Context, client *sdk.Handler, args
 
 	routingResp, err := client.PostPublicHttpRoutingSearch(ctx, routingFilter)
 	if err != nil {
-		return shared.ErrorResponse(fmt.Sprintf("Failed to check existing routing: %v", err)), nil
+		return nil, shared.WrapAPIError(err, "Failed to check existing routing")
 	}
 
 	routingOutput, err := routingResp.Output()
 	if err != nil {
-		return shared.ErrorResponse(fmt.Sprintf("Failed to parse routing response: %v", err)), nil
+		return nil, shared.WrapAPIError(err, "Failed to parse routing response")
+	}
+
+	// Zerops subdomains are flagged on the service; custom domain routing is reported as before
+	if serviceOutput.SubdomainAccess.Native() {
+		result := previewSubdomainResult{
+			Status:  "already_enabled",
+			Message: "Subdomain access is already enabled for this service.",
+		}
+		addSubdomainURLs(&result, serviceOutput, projectOutput)
+		return result, nil
 	}
 
 	// If routing already exists, return the existing subdomain URL
@@ -634,85 +586,365 @@ func handleEnablePreviewSubdomain(ctx context.Context, client *sdk.Handler, args
 			domainName = existingRouting.Domains[0].DomainName.Native()
 		}
 		
-		return map[string]interface{}{
-			"status":     "already_enabled",
-			"subdomain":  domainName,
-			"url":        fmt.Sprintf("https://%s", domainName),
-			"routing_id": string(existingRouting.Id),
-			"message":    "Subdomain access is already enabled for this service.",
+		return previewSubdomainResult{
+			Status:    "already_enabled",
+			Subdomain: domainName,
+			URL:       fmt.Sprintf("https://%s", domainName),
+			RoutingID: string(existingRouting.Id),
+			Message:   "Subdomain access is already enabled for this service.",
 		}, nil
 	}
 
 	// If no existing routing found, proceed to enable subdomain access
 	resp, err := client.PutServiceStackEnableSubdomainAccess(ctx, servicePath)
 	if err != nil {
-		return shared.ErrorResponse(fmt.Sprintf("Failed to enable subdomain: %v", err)), nil
+		return nil, shared.WrapAPIError(err, "Failed to enable subdomain")
 	}
 
 	output, err := resp.Output()
 	if err != nil {
-		return shared.ErrorResponse(fmt.Sprintf("Failed to parse response: %v", err)), nil
+		return nil, shared.WrapAPIError(err, "Failed to parse response")
 	}
+	forgetServiceStamp(ctx, serviceID)
 
-	return map[string]interface{}{
-		"process_id": string(output.Id),
-		"status":     "process_started",
-		"message":    "Subdomain enablement started. Use 'get_running_processes' with this service_id to check progress. Once completed, use 'discovery' to see the actual subdomain URL.",
-	}, nil
+	if wait, _ := args["wait"].(bool); wait {
+		display, err := resolveDisplayFormat(ctx, args)
+		if err != nil {
+			return nil, err
+		}
+		waited, err := waitForProcess(ctx, client, string(output.Id), defaultWaitTimeout, display)
+		if err != nil {
+			return nil, err
+		}
+		result := previewSubdomainResult{
+			ProcessID: string(output.Id),
+			Status:    waited.Status,
+		}
+		if waited.Status != "completed" {
+			result.Message = fmt.Sprintf("Subdomain enablement ended with %s; no URL is available yet.", waited.Status)
+			if waited.Status == "timeout" {
+				result.SuggestNext(shared.WaitForProcess(string(output.Id), "Keep waiting for the subdomain"))
+			}
+			return result, nil
+		}
+		result.Status = "enabled"
+		result.Message = "Subdomain access is enabled."
+		addSubdomainURLs(&result, serviceOutput, projectOutput)
+		return result, nil
+	}
+
+	result := previewSubdomainResult{
+		ProcessID: string(output.Id),
+		Status:    "process_started",
+		Message:   "Subdomain enablement started. Use 'wait_for_process' with this process_id, or call again with wait: true to get the URL directly.",
+	}
+	result.SuggestNext(shared.WaitForProcess(string(output.Id), "Wait until the subdomain is enabled"))
+	// The URLs are known up front; they answer once the process completed
+	addSubdomainURLs(&result, serviceOutput, projectOutput)
+	return result, nil
+}
+
+// addSubdomainURLs adds the zerops.app URL of each HTTP port of a service to result, with
+// url set to the first one. Subdomains are "<hostname>-<prefix>-<port>.<region host>", where the
+// project's subdomain host is "<prefix>.<region host>"; port 80 has no port suffix.
+func addSubdomainURLs(result *previewSubdomainResult, service output.ServiceStack, project output.Project) {
+	subdomainHost, _ := project.ZeropsSubdomainHost.Get()
+	prefix, regionHost, ok := strings.Cut(subdomainHost.Native(), ".")
+	if !ok {
+		result.Warnings = []string{"The project has no subdomain host yet; read the URL with discovery later."}
+		return
+	}
+
+	var ports []output.ServicePort
+	for _, port := range service.Ports {
+		if httpRouting, set := port.HttpRouting.Get(); set && httpRouting.Native() {
+			ports = append(ports, port)
+		}
+	}
+	// Services that don't flag HTTP ports get a URL per TCP port
+	if len(ports) == 0 {
+		for _, port := range service.Ports {
+			if port.Protocol == enum.ServicePortProtocolEnumTcp {
+				ports = append(ports, port)
+			}
+		}
+	}
+
+	urls := make([]subdomainURL, 0, len(ports))
+	for _, port := range ports {
+		label := fmt.Sprintf("%s-%s-%d", service.Name.Native(), prefix, port.Port.Native())
+		if port.Port.Native() == 80 {
+			label = fmt.Sprintf("%s-%s", service.Name.Native(), prefix)
+		}
+		urls = append(urls, subdomainURL{
+			Port: port.Port.Native(),
+			URL:  fmt.Sprintf("https://%s.%s", label, regionHost),
+		})
+	}
+	result.URLs = urls
+	if len(urls) > 0 {
+		result.URL = urls[0].URL
+	}
+}
+
+// scaleServiceResult is the result of scale_service
+type scaleServiceResult struct {
+	Status          string           `json:"status"`
+	ServiceID       string           `json:"service_id"`
+	ProcessID       string           `json:"process_id,omitempty"`
+	Parameters      scalingParams    `json:"parameters"`
+	ContainerLimits *containerLimits `json:"container_limits,omitempty"`
+	Warnings        []string         `json:"warnings,omitempty"`
+	Message         string           `json:"message"`
+	shared.Suggestions
+}
+
+// scalingParams are the limits scale_service sent; the others keep their values
+type scalingParams struct {
+	ServiceID     string   `json:"service_id"`
+	MinCPU        *int     `json:"min_cpu,omitempty"`
+	MaxCPU        *int     `json:"max_cpu,omitempty"`
+	MinRAM        *float64 `json:"min_ram,omitempty"`
+	MaxRAM        *float64 `json:"max_ram,omitempty"`
+	MinContainers *int     `json:"min_containers,omitempty"`
+	MaxContainers *int     `json:"max_containers,omitempty"`
 }
 
 func handleScaleService(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
 	if client == nil {
-		return shared.ErrorResponse("No API key provided"), nil
+		return nil, shared.ErrNoClient
 	}
 
 	serviceID, ok := args["service_id"].(string)
 	if !ok || serviceID == "" {
-		return shared.ErrorResponse("Service ID is required"), nil
+		return nil, shared.InvalidArgument("Service ID is required")
+	}
+
+	var warnings []string
+	minContainers, hasMin := args["min_containers"].(float64)
+	maxContainers, hasMax := args["max_containers"].(float64)
+	var limits *containerLimits
+	if hasMin || hasMax {
+		var err error
+		limits, err = serviceContainerLimits(ctx, client, serviceID)
+		if err != nil {
+			warnings = append(warnings, "container limits unavailable, checked against 1 to 10: "+err.Error())
+			limits = &containerLimits{Min: 1, Max: maxContainerCount}
+		}
+		for _, value := range []struct {
+			name  string
+			value float64
+			set   bool
+		}{{"min_containers", minContainers, hasMin}, {"max_containers", maxContainers, hasMax}} {
+			if value.set && value.value != float64(int(value.value)) {
+				return nil, shared.InvalidArgument("%s must be a whole number of containers, got %g", value.name, value.value)
+			}
+			if value.set && (int(value.value) < limits.Min || int(value.value) > limits.Max) {
+				return nil, shared.InvalidArgument("%s must be between %d and %d for this service, got %d", value.name, limits.Min, limits.Max, int(value.value))
+			}
+		}
+		if hasMin && hasMax && minContainers > maxContainers {
+			return nil, shared.InvalidArgument("min_containers (%d) must not exceed max_containers (%d)", int(minContainers), int(maxContainers))
+		}
 	}
 
-	// Collect scaling parameters
-	scalingParams := map[string]interface{}{
-		"service_id": serviceID,
+	if err := checkServiceUnchanged(ctx, client, args, serviceID); err != nil {
+		return nil, err
 	}
 
-	if minCPU, ok := args["min_cpu"].(float64); ok {
-		scalingParams["min_cpu"] = minCPU
+	if projectID, err := serviceProjectID(ctx, client, serviceID); err == nil {
+		recordSnapshot(ctx, client, projectID, "scale_service", serviceID)
 	}
-	if maxCPU, ok := args["max_cpu"].(float64); ok {
-		scalingParams["max_cpu"] = maxCPU
+
+	service, err := getServiceStack(ctx, client, serviceID)
+	if err != nil {
+		return nil, err
 	}
-	if minRAM, ok := args["min_ram"].(float64); ok {
-		scalingParams["min_ram"] = minRAM
+
+	// Collect scaling parameters; only the given limits are sent, the rest keep their values
+	params := scalingParams{ServiceID: serviceID}
+	custom := &body.CustomAutoscaling{}
+
+	var minResource, maxResource *body.ScalingResourceNullable
+	for _, cpu := range []struct {
+		name     string
+		resource **body.ScalingResourceNullable
+		param    **int
+	}{{"min_cpu", &minResource, &params.MinCPU}, {"max_cpu", &maxResource, &params.MaxCPU}} {
+		value, ok := args[cpu.name].(float64)
+		if !ok {
+			continue
+		}
+		if value != float64(int(value)) {
+			return nil, shared.InvalidArgument("%s must be a whole number of cores, got %g", cpu.name, value)
+		}
+		if *cpu.resource == nil {
+			*cpu.resource = &body.ScalingResourceNullable{}
+		}
+		cores := int(value)
+		(*cpu.resource).CpuCoreCount = types.NewIntNull(cores)
+		*cpu.param = &cores
+	}
+	for _, ram := range []struct {
+		name     string
+		resource **body.ScalingResourceNullable
+		param    **float64
+	}{{"min_ram", &minResource, &params.MinRAM}, {"max_ram", &maxResource, &params.MaxRAM}} {
+		value, ok := args[ram.name].(float64)
+		if !ok {
+			continue
+		}
+		if *ram.resource == nil {
+			*ram.resource = &body.ScalingResourceNullable{}
+		}
+		(*ram.resource).MemoryGBytes = types.NewFloatNull(value)
+		*ram.param = &value
 	}
-	if maxRAM, ok := args["max_ram"].(float64); ok {
-		scalingParams["max_ram"] = maxRAM
+	if minResource != nil || maxResource != nil {
+		custom.VerticalAutoscaling = &body.VerticalAutoscalingNullable{MinResource: minResource, MaxResource: maxResource}
 	}
-	if minContainers, ok := args["min_containers"].(float64); ok {
-		scalingParams["min_containers"] = int(minContainers)
+
+	if hasMin || hasMax {
+		horizontal := &body.HorizontalAutoscalingNullable{}
+		if hasMin {
+			count := int(minContainers)
+			horizontal.MinContainerCount = types.NewIntNull(count)
+			params.MinContainers = &count
+		}
+		if hasMax {
+			count := int(maxContainers)
+			horizontal.MaxContainerCount = types.NewIntNull(count)
+			params.MaxContainers = &count
+		}
+		custom.HorizontalAutoscaling = horizontal
 	}
-	if maxContainers, ok := args["max_containers"].(float64); ok {
-		scalingParams["max_containers"] = int(maxContainers)
+
+	if custom.VerticalAutoscaling == nil && custom.HorizontalAutoscaling == nil {
+		return nil, shared.InvalidArgument("At least one scaling parameter is required")
 	}
 
-	// Note: Actual scaling would require proper SDK methods
-	// This is a simplified response
-	return map[string]interface{}{
-		"status":     "scaling_configured",
-		"service_id": serviceID,
-		"parameters": scalingParams,
-		"message":    "Service scaling parameters have been configured",
-	}, nil
+	resp, err := client.PutServiceStackAutoscaling(ctx, path.ServiceStackId{Id: service.Id}, body.Autoscaling{
+		Mode:              &service.Mode,
+		CustomAutoscaling: custom,
+	})
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to scale service")
+	}
+	processNil, err := resp.Output()
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to parse response")
+	}
+	forgetServiceStamp(ctx, serviceID)
+
+	result := scaleServiceResult{
+		Status:          "unchanged",
+		ServiceID:       serviceID,
+		Parameters:      params,
+		ContainerLimits: limits,
+		Warnings:        warnings,
+		Message:         "The service already runs with these scaling parameters",
+	}
+	// The API starts no process when the limits are already in place
+	if process := processNil.Process; process != nil {
+		result.ProcessID = string(process.Id)
+		result.Status = "scaling"
+		result.Message = "Service scaling is being applied. Use 'get_process_status' to monitor progress."
+		result.SuggestNext(shared.WaitForProcess(string(process.Id), "Wait until the new limits apply"))
+	}
+	return result, nil
+}
+
+// maxContainerCount is the highest container count any plan allows
+const maxContainerCount = 10
+
+// containerLimits is the container count range a service's type and plan allow
+type containerLimits struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+}
+
+// serviceContainerLimits reads the horizontal scaling range of the service's type version
+func serviceContainerLimits(ctx context.Context, client *sdk.Handler, serviceID string) (*containerLimits, error) {
+	serviceResp, err := client.GetServiceStack(ctx, path.ServiceStackId{Id: uuid.ServiceStackId(serviceID)})
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to get service")
+	}
+	service, err := serviceResp.Output()
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to get service")
+	}
+
+	// Settings carry the full type versions including their scaling config
+	settingsResp, err := client.GetSettings(ctx)
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to get service types")
+	}
+	settings, err := settingsResp.Output()
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to parse service types")
+	}
+	for _, item := range settings.ServiceStackList {
+		for _, version := range item.ServiceStackTypeVersionList {
+			if string(version.Id) != string(service.ServiceStackTypeVersionId) {
+				continue
+			}
+			scaling := version.Config.HorizontalAutoscaling
+			if scaling == nil {
+				return nil, fmt.Errorf("%s has no horizontal scaling limits", version.Name.Native())
+			}
+			return &containerLimits{
+				Min: scaling.MinContainerCount.Native(),
+				Max: scaling.MaxContainerCount.Native(),
+			}, nil
+		}
+	}
+	return nil, fmt.Errorf("service type version %s not found", service.ServiceStackTypeVersionId)
+}
+
+// serviceLogsResult is the result of get_service_logs. Logs is a list of entries for FULL,
+// or text for the other formats.
+type serviceLogsResult struct {
+	ServiceID    string            `json:"service_id"`
+	ServiceName  string            `json:"service_name"`
+	ProjectID    string            `json:"project_id"`
+	Logs         interface{}       `json:"logs"`
+	TotalEntries int               `json:"total_entries"`
+	Parameters   serviceLogsParams `json:"parameters"`
+	Status       string            `json:"status"`
+	Build        *buildInfo        `json:"build,omitempty"`
+	Note         string            `json:"note,omitempty"`
+	Pagination   *logPages         `json:"pagination,omitempty"`
+	Follow       *logFollowSummary `json:"follow,omitempty"`
+}
+
+// serviceLogsParams echoes the effective parameters of get_service_logs
+type serviceLogsParams struct {
+	Limit           int    `json:"limit"`
+	MinimumSeverity string `json:"minimum_severity"`
+	MessageType     string `json:"message_type"`
+	Format          string `json:"format"`
+	FormatTemplate  string `json:"format_template"`
+	Follow          bool   `json:"follow"`
+	ShowBuildLogs   bool   `json:"show_build_logs"`
+	Timezone        string `json:"timezone"`
+}
+
+// logFollowSummary tells how following the logs ended
+type logFollowSummary struct {
+	StreamedEntries int    `json:"streamed_entries"`
+	StopReason      string `json:"stop_reason"`
+	Truncated       bool   `json:"truncated"`
+	DurationSeconds int    `json:"duration_seconds"`
 }
 
 func handleGetServiceLogs(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
 	if client == nil {
-		return shared.ErrorResponse("No API key provided"), nil
+		return nil, shared.ErrNoClient
 	}
 
 	serviceID, ok := args["service_id"].(string)
 	if !ok || serviceID == "" {
-		return shared.ErrorResponse("Service ID is required"), nil
+		return nil, shared.InvalidArgument("Service ID is required")
 	}
 
 	// Parse parameters with defaults
@@ -746,65 +978,237 @@ func handleGetServiceLogs(ctx context.Context, client *sdk.Handler, args map[str
 		follow = f
 	}
 
+	followDuration := defaultFollowDuration
+	if fd, ok := args["follow_duration"].(float64); ok && fd > 0 {
+		followDuration = time.Duration(fd) * time.Second
+		if followDuration > maxFollowDuration {
+			return nil, shared.InvalidArgument("follow_duration must be at most %d seconds", int(maxFollowDuration.Seconds()))
+		}
+	}
+
 	showBuildLogs := false
 	if sbl, ok := args["show_build_logs"].(bool); ok {
 		showBuildLogs = sbl
 	}
 
+	display, err := resolveDisplayFormat(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	var continuation *logContinuation
+	if token, _ := args["continuation_token"].(string); token != "" {
+		if follow {
+			return nil, shared.InvalidArgument("continuation_token cannot be combined with follow")
+		}
+		continuation, err = decodeLogContinuation(token, serviceID)
+		if err != nil {
+			return nil, err
+		}
+		minSeverity = continuation.MinSeverity
+		showBuildLogs = continuation.Build
+	} else if limit > maxServiceLogs {
+		return nil, shared.InvalidArgument("limit must be at most %d", maxServiceLogs)
+	} else if follow && limit > 1000 {
+		return nil, shared.InvalidArgument("limit must be at most 1000 with follow")
+	}
+
 	servicePath := path.ServiceStackId{Id: uuid.ServiceStackId(serviceID)}
 
 	// Get service info first to validate it exists and get project ID
 	serviceResp, err := client.GetServiceStack(ctx, servicePath)
 	if err != nil {
-		return shared.ErrorResponse(fmt.Sprintf("Failed to get service: %v", err)), nil
+		return nil, shared.WrapAPIError(err, "Failed to get service")
 	}
 
 	serviceOutput, err := serviceResp.Output()
 	if err != nil {
-		return shared.ErrorResponse(fmt.Sprintf("Failed to parse service: %v", err)), nil
+		return nil, shared.WrapAPIError(err, "Failed to parse service")
 	}
 
 	projectID := serviceOutput.ProjectId
 
-	// Handle build logs if requested
-	if showBuildLogs {
-		return map[string]interface{}{
-			"service_id":   serviceID,
-			"service_name": serviceOutput.Name.Native(),
-			"error":        "Build logs support requires app version lookup - not yet implemented",
-			"note":         "Use show_build_logs: false for runtime logs",
-		}, nil
+	// Build logs live on the build container of the app version, not on the service
+	logServiceID := serviceID
+	var build *buildInfo
+	if continuation != nil {
+		logServiceID = continuation.LogServiceID
+	} else if showBuildLogs {
+		appVersionID, _ := args["app_version_id"].(string)
+		version, err := findBuildAppVersion(ctx, client, serviceID, appVersionID)
+		if err != nil {
+			return nil, err
+		}
+		build = buildSummary(version, display)
+		logServiceID = build.BuildServiceID
+	}
+
+	q := logQuery{
+		ServiceID:   logServiceID,
+		Limit:       limit,
+		Facility:    getFacilityCode(messageType),
+		MinSeverity: minSeverity,
+	}
+	var logs []LogData
+	var pagination *logPages
+	if continuation != nil || (limit > logChunkSize && !follow) {
+		state := logContinuation{
+			ServiceID:    serviceID,
+			LogServiceID: logServiceID,
+			Facility:     q.Facility,
+			MinSeverity:  minSeverity,
+			Build:        showBuildLogs,
+			Remaining:    limit,
+		}
+		if continuation != nil {
+			state = *continuation
+		}
+		chunk, err := fetchLogChunk(ctx, client, projectID, state)
+		if err != nil {
+			return nil, err
+		}
+		logs = chunk.logs
+		pagination = logPagination(state, chunk)
+	} else {
+		logs, err = fetchLogs(ctx, client, projectID, q)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	render := func(entries []LogData) (interface{}, error) {
+		return formatLogs(entries, format, formatTemplate)
+	}
+	var followed *logFollow
+	if follow {
+		followed, err = followLogs(ctx, client, projectID, q, logs, followDuration, render, display)
+		if err != nil {
+			return nil, err
+		}
+		logs = followed.logs
+	} else {
+		// Normalize timestamps before formatting so templates see RFC3339 too
+		for i := range logs {
+			logs[i].Timestamp = normalizeTimestamp(logs[i].Timestamp, display)
+		}
 	}
 
+	// Format logs based on requested format
+	formattedLogs, err := render(logs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := serviceLogsResult{
+		ServiceID:    serviceID,
+		ServiceName:  serviceOutput.Name.Native(),
+		ProjectID:    string(projectID),
+		Logs:         formattedLogs,
+		TotalEntries: len(logs),
+		Parameters: serviceLogsParams{
+			Limit:           limit,
+			MinimumSeverity: minSeverity,
+			MessageType:     messageType,
+			Format:          format,
+			FormatTemplate:  formatTemplate,
+			Follow:          follow,
+			ShowBuildLogs:   showBuildLogs,
+			Timezone:        display.loc.String(),
+		},
+		Status:     "success",
+		Build:      build,
+		Pagination: pagination,
+	}
+	if shared.GlobalExporter != nil {
+		shared.ExportEvent("service_logs", logSummaryEvent(serviceID, serviceOutput.Name.Native(), string(projectID), messageType, showBuildLogs, logs))
+	}
+	if build != nil {
+		result.Note = "Build and prepare output of the build container. Deploy output (initCommands, start) is in the runtime logs (show_build_logs: false)."
+	}
+	if followed != nil {
+		result.Follow = &logFollowSummary{
+			StreamedEntries: followed.streamed,
+			StopReason:      followed.stopReason,
+			Truncated:       followed.truncated,
+			DurationSeconds: int(followDuration.Seconds()),
+		}
+	}
+	return result, nil
+}
+
+// logSummaryEvent summarizes fetched logs for the event exporter: counts per severity and
+// the last error line, never the full log content
+func logSummaryEvent(serviceID, serviceName, projectID, messageType string, build bool, logs []LogData) map[string]interface{} {
+	severities := map[string]int{}
+	lastError := ""
+	for _, entry := range logs {
+		severities[strings.ToLower(entry.SeverityLabel)]++
+		if entry.Severity <= severityLevels["error"] {
+			lastError = entry.Message
+		}
+	}
+	event := map[string]interface{}{
+		"service_id":   serviceID,
+		"service_name": serviceName,
+		"project_id":   projectID,
+		"message_type": messageType,
+		"build_logs":   build,
+		"entries":      len(logs),
+		"severities":   severities,
+	}
+	if lastError != "" {
+		if len(lastError) > 500 {
+			lastError = lastError[:500]
+		}
+		event["last_error"] = lastError
+	}
+	return event
+}
+
+// logQuery holds the filters sent to the log backend
+type logQuery struct {
+	ServiceID   string
+	Limit       int
+	Facility    int
+	MinSeverity string
+	// Till asks for entries older than this log ID, used to page backwards
+	Till string
+}
+
+// fetchLogs reads service logs from the project log backend (following zcli pattern)
+func fetchLogs(ctx context.Context, client *sdk.Handler, projectID uuid.ProjectId, q logQuery) ([]LogData, error) {
 	// Get log URL from project log endpoint (following zcli pattern)
 	projectPath := path.ProjectId{Id: projectID}
 	logResp, err := client.GetProjectLog(ctx, projectPath, query.GetProjectLog{})
 	if err != nil {
-		return shared.ErrorResponse(fmt.Sprintf("Failed to get project log access: %v", err)), nil
+		return nil, shared.WrapAPIError(err, "Failed to get project log access")
 	}
 
 	logOutput, err := logResp.Output()
 	if err != nil {
-		return shared.ErrorResponse(fmt.Sprintf("Failed to parse log access: %v", err)), nil
+		return nil, shared.WrapAPIError(err, "Failed to parse log access")
 	}
 
 	// Parse method and URL from response (format: "METHOD URL")
 	urlData := strings.Split(string(logOutput.Url), " ")
 	if len(urlData) != 2 {
-		return shared.ErrorResponse("Invalid log URL format received"), nil
+		return nil, shared.NewToolError(shared.ErrAPIUnavailable, "Invalid log URL format received")
 	}
 	method, baseURL := urlData[0], urlData[1]
 
 	// Build query parameters (following zcli pattern)
 	queryParams := fmt.Sprintf("&limit=%d&desc=1&facility=%d&serviceStackId=%s",
-		limit, getFacilityCode(messageType), serviceID)
+		q.Limit, q.Facility, q.ServiceID)
 
 	// Add severity filter if specified
-	if minSeverity != "" {
-		if severityCode, ok := severityLevels[strings.ToLower(minSeverity)]; ok {
+	if q.MinSeverity != "" {
+		if severityCode, ok := severityLevels[strings.ToLower(q.MinSeverity)]; ok {
 			queryParams += fmt.Sprintf("&minimumSeverity=%d", severityCode)
 		}
 	}
+	if q.Till != "" {
+		queryParams += "&till=" + url.QueryEscape(q.Till)
+	}
 
 	// Make HTTP request to get logs
 	fullURL := "https://" + baseURL + queryParams
@@ -812,47 +1216,28 @@ func handleGetServiceLogs(ctx context.Context, client *sdk.Handler, args map[str
 
 	req, err := http.NewRequestWithContext(ctx, method, fullURL, nil)
 	if err != nil {
-		return shared.ErrorResponse(fmt.Sprintf("Failed to create request: %v", err)), nil
+		return nil, shared.WrapAPIError(err, "Failed to create request")
 	}
 	req.Header.Add("Content-Type", "application/json")
 
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return shared.ErrorResponse(fmt.Sprintf("Failed to fetch logs: %v", err)), nil
+		return nil, shared.WrapAPIError(err, "Failed to fetch logs")
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return shared.ErrorResponse(fmt.Sprintf("Failed to read response: %v", err)), nil
+		return nil, shared.WrapAPIError(err, "Failed to read response")
 	}
 
 	// Parse JSON response
 	var logResponse LogResponse
 	if err := json.Unmarshal(body, &logResponse); err != nil {
-		return shared.ErrorResponse(fmt.Sprintf("Failed to parse log response: %v", err)), nil
+		return nil, shared.WrapAPIError(err, "Failed to parse log response")
 	}
 
-	// Format logs based on requested format
-	formattedLogs := formatLogs(logResponse.Items, format, formatTemplate)
-
-	return map[string]interface{}{
-		"service_id":    serviceID,
-		"service_name":  serviceOutput.Name.Native(),
-		"project_id":    string(projectID),
-		"logs":          formattedLogs,
-		"total_entries": len(logResponse.Items),
-		"parameters": map[string]interface{}{
-			"limit":            limit,
-			"minimum_severity": minSeverity,
-			"message_type":     messageType,
-			"format":           format,
-			"format_template":  formatTemplate,
-			"follow":           follow,
-			"show_build_logs":  showBuildLogs,
-		},
-		"status": "success",
-	}, nil
+	return logResponse.Items, nil
 }
 
 // getFacilityCode returns facility code based on message type (from zcli)
@@ -868,10 +1253,19 @@ func getFacilityCode(messageType string) int {
 }
 
 // formatLogs formats log entries based on the requested format
-func formatLogs(logs []LogData, format, formatTemplate string) interface{} {
+func formatLogs(logs []LogData, format, formatTemplate string) (interface{}, error) {
+	// A custom template takes precedence over the predefined formats
+	if formatTemplate != "" {
+		tmpl, err := compileLogTemplate(formatTemplate)
+		if err != nil {
+			return nil, err
+		}
+		return renderLogTemplate(logs, tmpl)
+	}
+
 	switch strings.ToUpper(format) {
 	case "JSON":
-		return logs
+		return logs, nil
 	case "SHORT":
 		var shortLogs []map[string]interface{}
 		for _, log := range logs {
@@ -881,7 +1275,7 @@ func formatLogs(logs []LogData, format, formatTemplate string) interface{} {
 				"message":   log.Message,
 			})
 		}
-		return shortLogs
+		return shortLogs, nil
 	case "FULL":
 		fallthrough
 	default:
@@ -902,18 +1296,34 @@ func formatLogs(logs []LogData, format, formatTemplate string) interface{} {
 			}
 			fullLogs = append(fullLogs, entry)
 		}
-		return fullLogs
+		return fullLogs, nil
 	}
 }
 
+// restartServiceResult is the result of restart_service
+type restartServiceResult struct {
+	ProcessID   string    `json:"process_id"`
+	ServiceID   string    `json:"service_id"`
+	ServiceName string    `json:"service_name"`
+	Status      string    `json:"status"`
+	ActionName  string    `json:"action_name"`
+	Created     time.Time `json:"created"`
+	Message     string    `json:"message"`
+	shared.Suggestions
+}
+
 func handleRestartService(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
 	if client == nil {
-		return shared.ErrorResponse("No API key provided"), nil
+		return nil, shared.ErrNoClient
 	}
 
 	serviceID, ok := args["service_id"].(string)
 	if !ok || serviceID == "" {
-		return shared.ErrorResponse("Service ID is required"), nil
+		return nil, shared.InvalidArgument("Service ID is required")
+	}
+
+	if err := checkServiceUnchanged(ctx, client, args, serviceID); err != nil {
+		return nil, err
 	}
 
 	servicePath := path.ServiceStackId{Id: uuid.ServiceStackId(serviceID)}
@@ -921,65 +1331,86 @@ func handleRestartService(ctx context.Context, client *sdk.Handler, args map[str
 	// Get service info to validate it exists and get service name
 	serviceResp, err := client.GetServiceStack(ctx, servicePath)
 	if err != nil {
-		return shared.ErrorResponse(fmt.Sprintf("Failed to get service: %v", err)), nil
+		return nil, shared.WrapAPIError(err, "Failed to get service")
 	}
 
 	serviceOutput, err := serviceResp.Output()
 	if err != nil {
-		return shared.ErrorResponse(fmt.Sprintf("Failed to parse service: %v", err)), nil
+		return nil, shared.WrapAPIError(err, "Failed to parse service")
 	}
 
-	// Perform actual restart: Stop then Start
-	// First, stop the service
-	stopResp, err := client.PutServiceStackStop(ctx, servicePath)
+	process, err := restartService(ctx, client, serviceID)
 	if err != nil {
-		return shared.ErrorResponse(fmt.Sprintf("Failed to stop service: %v", err)), nil
+		return nil, err
 	}
 
-	stopProcess, err := stopResp.Output()
-	if err != nil {
-		return shared.ErrorResponse(fmt.Sprintf("Failed to parse stop process: %v", err)), nil
+	result := restartServiceResult{
+		ProcessID:   string(process.Id),
+		ServiceID:   serviceID,
+		ServiceName: serviceOutput.Name.Native(),
+		Status:      string(process.Status),
+		ActionName:  process.ActionName.Native(),
+		Created:     process.Created.Native(),
+		Message:     "Service restart initiated. Use 'wait_for_process' or 'get_process_status' to monitor progress.",
 	}
+	result.SuggestNext(shared.WaitForProcess(string(process.Id), "Wait until the restart completes"))
+	return result, nil
+}
+
+// restartService restarts a service with the restart endpoint, so the service is not left
+// stopped between two processes and there is a single process to watch
+func restartService(ctx context.Context, client *sdk.Handler, serviceID string) (output.Process, error) {
+	servicePath := path.ServiceStackId{Id: uuid.ServiceStackId(serviceID)}
 
-	// Then, start the service
-	startResp, err := client.PutServiceStackStart(ctx, servicePath)
+	resp, err := client.PutServiceStackRestart(ctx, servicePath)
 	if err != nil {
-		return shared.ErrorResponse(fmt.Sprintf("Failed to start service: %v", err)), nil
+		return output.Process{}, shared.WrapAPIError(err, "Failed to restart service")
 	}
 
-	startProcess, err := startResp.Output()
+	process, err := resp.Output()
 	if err != nil {
-		return shared.ErrorResponse(fmt.Sprintf("Failed to parse start process: %v", err)), nil
-	}
-
-	// Return the start process information (most relevant for monitoring)
-	return map[string]interface{}{
-		"process_id":       string(startProcess.Id),
-		"service_id":       serviceID,
-		"service_name":     serviceOutput.Name.Native(),
-		"status":           string(startProcess.Status),
-		"action_name":      startProcess.ActionName.Native(),
-		"created":          startProcess.Created.Native(),
-		"stop_process_id":  string(stopProcess.Id),
-		"start_process_id": string(startProcess.Id),
-		"message":          "Service restart initiated (stop + start). Use 'get_process_status' to monitor progress.",
-	}, nil
+		return output.Process{}, shared.WrapAPIError(err, "Failed to parse restart process")
+	}
+	forgetServiceStamp(ctx, serviceID)
+	return process, nil
+}
+
+// remountServiceResult is the result of remount_service
+type remountServiceResult struct {
+	Status       string          `json:"status"`
+	ServiceName  string          `json:"service_name"`
+	MountPath    string          `json:"mount_path"`
+	Commands     remountCommands `json:"commands"`
+	Message      string          `json:"message"`
+	Instructions []string        `json:"instructions"`
+}
+
+// remountCommands are the shell commands that remount a service over SSHFS
+type remountCommands struct {
+	CheckMount string `json:"check_mount"`
+	Unmount    string `json:"unmount"`
+	Mkdir      string `json:"mkdir"`
+	SSHFS      string `json:"sshfs"`
+	Combined   string `json:"combined"`
 }
 
 func handleRemountService(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
 	if client == nil {
-		return shared.ErrorResponse("No API key provided"), nil
+		return nil, shared.ErrNoClient
 	}
 
 	serviceName, ok := args["service_name"].(string)
 	if !ok || serviceName == "" {
-		return shared.ErrorResponse("Service name is required"), nil
+		return nil, shared.InvalidArgument("Service name is required")
 	}
 
 	// Note: This is a placeholder implementation
 	// The actual remount would need proper SDK methods or system commands
 
 	mountPath := fmt.Sprintf("/var/www/%s", serviceName)
+	if err := shared.CheckPathInRoots(ctx, mountPath); err != nil {
+		return nil, err
+	}
 
 	// Commands to check and handle existing mounts
 	checkMountCommand := fmt.Sprintf(`mount | grep "%s"`, mountPath)
@@ -1002,19 +1433,19 @@ mkdir -p "%s"
 sshfs -o StrictHostKeyChecking=no,reconnect,ServerAliveInterval=15,ServerAliveCountMax=3,auto_cache,kernel_cache "%s:/var/www" "%s"
 `, mountPath, mountPath, mountPath, mountPath, mountPath, serviceName, mountPath)
 
-	return map[string]interface{}{
-		"status":       "success",
-		"service_name": serviceName,
-		"mount_path":   mountPath,
-		"commands": map[string]interface{}{
-			"check_mount": checkMountCommand,
-			"unmount":     unmountCommand,
-			"mkdir":       mkdirCommand,
-			"sshfs":       sshfsCommand,
-			"combined":    combinedCommand,
+	return remountServiceResult{
+		Status:      "success",
+		ServiceName: serviceName,
+		MountPath:   mountPath,
+		Commands: remountCommands{
+			CheckMount: checkMountCommand,
+			Unmount:    unmountCommand,
+			Mkdir:      mkdirCommand,
+			SSHFS:      sshfsCommand,
+			Combined:   combinedCommand,
 		},
-		"message": fmt.Sprintf("Commands to remount SSHFS for service '%s':", serviceName),
-		"instructions": []string{
+		Message: fmt.Sprintf("Commands to remount SSHFS for service '%s':", serviceName),
+		Instructions: []string{
 			"Option 1: Run the combined command that handles everything:",
 			combinedCommand,
 			"",
@@ -1027,31 +1458,43 @@ sshfs -o StrictHostKeyChecking=no,reconnect,ServerAliveInterval=15,ServerAliveCo
 	}, nil
 }
 
+// processStatusResult is the result of get_process_status
+type processStatusResult struct {
+	ProcessID string `json:"process_id"`
+	Status    string `json:"status"`
+	Created   string `json:"created"`
+}
+
 func handleGetProcessStatus(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
 	if client == nil {
-		return shared.ErrorResponse("No API key provided"), nil
+		return nil, shared.ErrNoClient
 	}
 
 	processID, ok := args["process_id"].(string)
 	if !ok || processID == "" {
-		return shared.ErrorResponse("Process ID is required"), nil
+		return nil, shared.InvalidArgument("Process ID is required")
+	}
+
+	display, err := resolveDisplayFormat(ctx, args)
+	if err != nil {
+		return nil, err
 	}
 
 	// Get process details
 	processPath := path.ProcessId{Id: uuid.ProcessId(processID)}
 	processResp, err := client.GetProcess(ctx, processPath)
 	if err != nil {
-		return shared.ErrorResponse(fmt.Sprintf("Failed to get process: %v", err)), nil
+		return nil, shared.WrapAPIError(err, "Failed to get process")
 	}
 
 	processOutput, err := processResp.Output()
 	if err != nil {
-		return shared.ErrorResponse(fmt.Sprintf("Failed to parse process: %v", err)), nil
+		return nil, shared.WrapAPIError(err, "Failed to parse process")
 	}
 
-	return map[string]interface{}{
-		"process_id": string(processOutput.Id),
-		"status":     string(processOutput.Status),
-		"created":    processOutput.Created.Format("2006-01-02 15:04:05"),
+	return processStatusResult{
+		ProcessID: string(processOutput.Id),
+		Status:    string(processOutput.Status),
+		Created:   formatTimestamp(processOutput.Created.Native(), display),
 	}, nil
 }
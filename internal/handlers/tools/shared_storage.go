@@ -0,0 +1,514 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/dto/input/body"
+	"github.com/zeropsio/zerops-go/dto/input/path"
+	"github.com/zeropsio/zerops-go/dto/output"
+	"github.com/zeropsio/zerops-go/sdk"
+	"github.com/zeropsio/zerops-go/types"
+	"github.com/zeropsio/zerops-go/types/uuid"
+	"gopkg.in/yaml.v3"
+)
+
+// sharedStorageType is the service type base of shared storages
+const sharedStorageType = "shared-storage"
+
+// sharedStorageWaitTimeout bounds how long create_shared_storage waits for the storage
+// before connecting it
+const sharedStorageWaitTimeout = 10 * time.Minute
+
+// RegisterSharedStorage registers the list_shared_storages, create_shared_storage,
+// connect_shared_storage and disconnect_shared_storage tools
+func RegisterSharedStorage() {
+	pairProperties := map[string]interface{}{
+		"service_id": map[string]interface{}{
+			"type":        "string",
+			"description": "REQUIRED: ID of the runtime service",
+			"pattern":     "^[A-Za-z0-9_-]+$",
+		},
+		"storage_id": map[string]interface{}{
+			"type":        "string",
+			"description": "REQUIRED: ID of the shared storage service (from list_shared_storages)",
+			"pattern":     "^[A-Za-z0-9_-]+$",
+		},
+	}
+
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "list_shared_storages",
+		Description: toolDescription("list_shared_storages"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"project_id": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Project ID (defaults to $projectId)",
+					"pattern":     "^[A-Za-z0-9_-]+$",
+				},
+			},
+			"additionalProperties": false,
+		},
+		Annotations: shared.ReadOnly(),
+		Output:      listSharedStoragesResult{},
+		Handler:     handleListSharedStorages,
+	})
+
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "create_shared_storage",
+		Description: toolDescription("create_shared_storage"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"project_id": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Project ID (defaults to $projectId)",
+					"pattern":     "^[A-Za-z0-9_-]+$",
+				},
+				"hostname": map[string]interface{}{
+					"type":        "string",
+					"description": "REQUIRED: Hostname of the storage (lowercase alphanumeric, max 25 characters); runtimes mount it at /mnt/<hostname>",
+					"pattern":     "^[a-z0-9]{1,25}$",
+				},
+				"mode": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: HA or NON_HA (default: NON_HA). Cannot be changed later.",
+					"enum":        []string{"HA", "NON_HA"},
+				},
+				"connect": map[string]interface{}{
+					"type":        "array",
+					"description": "OPTIONAL: IDs of runtime services to connect once the storage is created",
+					"items":       map[string]interface{}{"type": "string"},
+				},
+			},
+			"required":             []string{"hostname"},
+			"additionalProperties": false,
+		},
+		Annotations: shared.Mutating(false, false),
+		Output:      createSharedStorageResult{},
+		Handler:     handleCreateSharedStorage,
+	})
+
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "connect_shared_storage",
+		Description: toolDescription("connect_shared_storage"),
+		InputSchema: map[string]interface{}{
+			"type":                 "object",
+			"properties":           pairProperties,
+			"required":             []string{"service_id", "storage_id"},
+			"additionalProperties": false,
+		},
+		Annotations: shared.Mutating(false, true),
+		Output:      sharedStorageConnectionResult{},
+		Handler:     handleConnectSharedStorage,
+	})
+
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "disconnect_shared_storage",
+		Description: toolDescription("disconnect_shared_storage"),
+		InputSchema: map[string]interface{}{
+			"type":                 "object",
+			"properties":           pairProperties,
+			"required":             []string{"service_id", "storage_id"},
+			"additionalProperties": false,
+		},
+		Annotations: shared.Mutating(true, true),
+		Output:      sharedStorageConnectionResult{},
+		Handler:     handleDisconnectSharedStorage,
+	})
+}
+
+// listSharedStoragesResult is the result of list_shared_storages
+type listSharedStoragesResult struct {
+	ProjectID string          `json:"project_id"`
+	Storages  []sharedStorage `json:"storages"`
+	Count     int             `json:"count"`
+	Message   string          `json:"message,omitempty"`
+}
+
+// sharedStorage is a shared storage service and the runtimes it is connected to
+type sharedStorage struct {
+	StorageID      string              `json:"storage_id"`
+	Hostname       string              `json:"hostname"`
+	Status         string              `json:"status"`
+	Mode           string              `json:"mode,omitempty"`
+	MountPath      string              `json:"mount_path"`
+	Connected      []storageConnection `json:"connected"`
+	ConnectedCount int                 `json:"connected_count"`
+}
+
+// storageConnection is a runtime connected to a shared storage
+type storageConnection struct {
+	ServiceID string `json:"service_id"`
+	Hostname  string `json:"hostname"`
+	Status    string `json:"status"`
+	ProcessID string `json:"process_id,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+func handleListSharedStorages(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+	projectID, err := resolveProjectID(ctx, client, args)
+	if err != nil {
+		return nil, err
+	}
+	project, err := searchProject(ctx, client, projectID)
+	if err != nil {
+		return nil, err
+	}
+	services, err := searchProjectServices(ctx, client, project)
+	if err != nil {
+		return nil, err
+	}
+
+	// A connection may be listed on the storage, on the runtime or on both
+	connected := map[uuid.ServiceStackId]map[uuid.ServiceStackId]string{}
+	byID := map[uuid.ServiceStackId]output.EsServiceStack{}
+	for _, service := range services {
+		byID[service.Id] = service
+	}
+	for _, service := range services {
+		storage := isSharedStorage(string(service.ServiceStackTypeVersionId))
+		for _, stack := range service.ConnectedStacks {
+			other, ok := byID[stack.ServiceStack.Id]
+			if !ok {
+				continue
+			}
+			storageID, runtimeID := service.Id, other.Id
+			if !storage {
+				if !isSharedStorage(string(other.ServiceStackTypeVersionId)) {
+					continue
+				}
+				storageID, runtimeID = other.Id, service.Id
+			}
+			if connected[storageID] == nil {
+				connected[storageID] = map[uuid.ServiceStackId]string{}
+			}
+			connected[storageID][runtimeID] = string(stack.Status)
+		}
+	}
+
+	storages := []sharedStorage{}
+	for _, service := range services {
+		if !isSharedStorage(string(service.ServiceStackTypeVersionId)) {
+			continue
+		}
+		runtimes := []storageConnection{}
+		for runtimeID, status := range connected[service.Id] {
+			runtimes = append(runtimes, storageConnection{
+				ServiceID: string(runtimeID),
+				Hostname:  byID[runtimeID].Name.Native(),
+				Status:    status,
+			})
+		}
+		sort.Slice(runtimes, func(i, j int) bool { return runtimes[i].Hostname < runtimes[j].Hostname })
+		entry := sharedStorage{
+			StorageID:      string(service.Id),
+			Hostname:       service.Name.Native(),
+			Status:         string(service.Status),
+			MountPath:      "/mnt/" + service.Name.Native(),
+			Connected:      runtimes,
+			ConnectedCount: len(runtimes),
+		}
+		if service.Mode != nil {
+			entry.Mode = string(*service.Mode)
+		}
+		storages = append(storages, entry)
+	}
+
+	result := listSharedStoragesResult{
+		ProjectID: projectID,
+		Storages:  storages,
+		Count:     len(storages),
+	}
+	if len(storages) == 0 {
+		result.Message = "The project has no shared storage. Create one with create_shared_storage."
+	}
+	return result, nil
+}
+
+// createSharedStorageResult is the result of create_shared_storage
+type createSharedStorageResult struct {
+	Status      string              `json:"status"`
+	ProjectID   string              `json:"project_id"`
+	StorageID   string              `json:"storage_id"`
+	Hostname    string              `json:"hostname"`
+	Mode        string              `json:"mode"`
+	MountPath   string              `json:"mount_path"`
+	ProcessID   string              `json:"process_id,omitempty"`
+	Connections []storageConnection `json:"connections,omitempty"`
+	Message     string              `json:"message"`
+	shared.Suggestions
+}
+
+func handleCreateSharedStorage(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+	projectID, err := resolveProjectID(ctx, client, args)
+	if err != nil {
+		return nil, err
+	}
+	hostname, _ := args["hostname"].(string)
+	if !hostnamePattern.MatchString(hostname) {
+		return nil, shared.InvalidArgument("Hostname is required and must be lowercase alphanumeric, max 25 characters")
+	}
+	mode := "NON_HA"
+	if value, _ := args["mode"].(string); value != "" {
+		if value != "HA" && value != "NON_HA" {
+			return nil, shared.InvalidArgument("mode must be HA or NON_HA")
+		}
+		mode = value
+	}
+	connectIDs, err := stringListArg(args, "connect")
+	if err != nil {
+		return nil, err
+	}
+	display, err := resolveDisplayFormat(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the runtimes before anything is created
+	runtimes := make([]output.ServiceStack, 0, len(connectIDs))
+	for _, serviceID := range connectIDs {
+		service, err := getServiceStack(ctx, client, serviceID)
+		if err != nil {
+			return nil, err
+		}
+		if string(service.ProjectId) != projectID {
+			return nil, shared.InvalidArgument("Service '%s' is not in project %s", service.Name.Native(), projectID)
+		}
+		if base := serviceTypeBase(string(service.ServiceStackTypeVersionId)); managedServiceTypes[base] {
+			return nil, shared.InvalidArgument("%s is a %s service; shared storage connects to runtime services only", service.Name.Native(), base)
+		}
+		runtimes = append(runtimes, service)
+	}
+
+	importYaml, err := yaml.Marshal(map[string]interface{}{
+		"services": []map[string]interface{}{
+			{"hostname": hostname, "type": sharedStorageType, "mode": mode},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build shared storage YAML: %w", err)
+	}
+
+	recordSnapshot(ctx, client, projectID, "create_shared_storage", hostname)
+
+	importResp, err := client.PostServiceStackImport(ctx, body.ServiceStackImport{
+		ProjectId: uuid.ProjectId(projectID),
+		Yaml:      types.NewText(string(importYaml)),
+	})
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Shared storage import failed")
+	}
+	importOutput, err := importResp.Output()
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Shared storage import failed")
+	}
+	if len(importOutput.ServiceStacks) == 0 {
+		return nil, fmt.Errorf("import of shared storage %s returned no service", hostname)
+	}
+	stack := importOutput.ServiceStacks[0]
+	if stack.Error != nil {
+		return nil, fmt.Errorf("shared storage %s was not created: %s", hostname, stack.Error.Message.Native())
+	}
+
+	result := createSharedStorageResult{
+		Status:    "storage_started",
+		ProjectID: projectID,
+		StorageID: string(stack.Id),
+		Hostname:  hostname,
+		Mode:      mode,
+		MountPath: "/mnt/" + hostname,
+	}
+	processID := ""
+	if len(stack.Processes) > 0 {
+		processID = string(stack.Processes[0].Id)
+		result.ProcessID = processID
+	}
+	if len(runtimes) == 0 {
+		result.Message = fmt.Sprintf("Creating shared storage %s. Use wait_for_process on process_id, then connect runtimes with connect_shared_storage.", hostname)
+		if processID != "" {
+			result.SuggestNext(shared.WaitForProcess(processID, fmt.Sprintf("Wait until %s is created", hostname)))
+		}
+		return result, nil
+	}
+
+	// Connecting needs the storage to exist
+	if processID != "" {
+		waited, err := waitForProcess(ctx, client, processID, sharedStorageWaitTimeout, display)
+		if err != nil {
+			return nil, err
+		}
+		if waited.Status != "completed" {
+			result.Status = waited.Status
+			result.Message = fmt.Sprintf("Shared storage %s did not finish (%s); no runtime was connected. Connect them with connect_shared_storage once it is ready.", hostname, waited.Status)
+			return result, nil
+		}
+	}
+	result.Status = "storage_created"
+
+	connections := make([]storageConnection, 0, len(runtimes))
+	for _, runtime := range runtimes {
+		entry := storageConnection{
+			ServiceID: string(runtime.Id),
+			Hostname:  runtime.Name.Native(),
+		}
+		process, err := connectSharedStorage(ctx, client, runtime.Id, stack.Id, true)
+		if err != nil {
+			entry.Status = "failed"
+			entry.Error = err.Error()
+		} else {
+			entry.Status = "connecting"
+			entry.ProcessID = string(process.Id)
+		}
+		connections = append(connections, entry)
+	}
+	result.Connections = connections
+	result.Message = fmt.Sprintf("Shared storage %s created. Use wait_for_process on the connection process_id values; the runtimes then see it at /mnt/%s.", hostname, hostname)
+	for _, entry := range connections {
+		if entry.ProcessID != "" {
+			result.SuggestNext(shared.WaitForProcess(entry.ProcessID, fmt.Sprintf("Wait until %s is connected", entry.Hostname)))
+		}
+	}
+	return result, nil
+}
+
+func handleConnectSharedStorage(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	return changeSharedStorageConnection(ctx, client, args, true)
+}
+
+func handleDisconnectSharedStorage(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	return changeSharedStorageConnection(ctx, client, args, false)
+}
+
+// sharedStorageConnectionResult is the result of connect_shared_storage and
+// disconnect_shared_storage
+type sharedStorageConnectionResult struct {
+	Status      string `json:"status"`
+	ServiceID   string `json:"service_id"`
+	ServiceName string `json:"service_name"`
+	StorageID   string `json:"storage_id"`
+	StorageName string `json:"storage_name"`
+	MountPath   string `json:"mount_path"`
+	ProcessID   string `json:"process_id"`
+	Message     string `json:"message"`
+	shared.Suggestions
+}
+
+// changeSharedStorageConnection connects or disconnects the storage_id storage and the
+// service_id runtime
+func changeSharedStorageConnection(ctx context.Context, client *sdk.Handler, args map[string]interface{}, connect bool) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+	serviceID, _ := args["service_id"].(string)
+	if serviceID == "" {
+		return nil, shared.InvalidArgument("Service ID is required")
+	}
+	storageID, _ := args["storage_id"].(string)
+	if storageID == "" {
+		return nil, shared.InvalidArgument("Storage ID is required")
+	}
+
+	service, err := getServiceStack(ctx, client, serviceID)
+	if err != nil {
+		return nil, err
+	}
+	if base := serviceTypeBase(string(service.ServiceStackTypeVersionId)); managedServiceTypes[base] {
+		return nil, shared.InvalidArgument("%s is a %s service; shared storage connects to runtime services only", service.Name.Native(), base)
+	}
+	storage, err := getServiceStack(ctx, client, storageID)
+	if err != nil {
+		return nil, err
+	}
+	if !isSharedStorage(string(storage.ServiceStackTypeVersionId)) {
+		return nil, shared.InvalidArgument("%s is not a shared storage; find storages with list_shared_storages", storage.Name.Native())
+	}
+	if service.ProjectId != storage.ProjectId {
+		return nil, shared.InvalidArgument("%s and %s are in different projects", service.Name.Native(), storage.Name.Native())
+	}
+
+	action := "connect_shared_storage"
+	if !connect {
+		action = "disconnect_shared_storage"
+	}
+	recordSnapshot(ctx, client, string(service.ProjectId), action, service.Name.Native())
+
+	process, err := connectSharedStorage(ctx, client, service.Id, storage.Id, connect)
+	if err != nil {
+		return nil, err
+	}
+	forgetServiceStamp(ctx, string(service.Id))
+
+	mountPath := "/mnt/" + storage.Name.Native()
+	result := sharedStorageConnectionResult{
+		ServiceID:   string(service.Id),
+		ServiceName: service.Name.Native(),
+		StorageID:   string(storage.Id),
+		StorageName: storage.Name.Native(),
+		MountPath:   mountPath,
+		ProcessID:   string(process.Id),
+	}
+	if connect {
+		result.Status = "connecting"
+		result.Message = fmt.Sprintf("Connecting %s to %s. Once wait_for_process reports completed, the storage is mounted at %s.", storage.Name.Native(), service.Name.Native(), mountPath)
+		result.SuggestNext(shared.WaitForProcess(string(process.Id), fmt.Sprintf("Wait until %s is mounted", mountPath)))
+	} else {
+		result.Status = "disconnecting"
+		result.Message = fmt.Sprintf("Disconnecting %s from %s. Files stay on the storage; only %s disappears from the runtime.", storage.Name.Native(), service.Name.Native(), mountPath)
+	}
+	return result, nil
+}
+
+// connectSharedStorage starts connecting or disconnecting a storage and a runtime
+func connectSharedStorage(ctx context.Context, client *sdk.Handler, serviceID, storageID uuid.ServiceStackId, connect bool) (output.Process, error) {
+	servicePath := path.ServiceStackId{Id: serviceID}
+	request := body.PutSharedStorageAction{SharedStorageId: storageID}
+	if !connect {
+		resp, err := client.PutServiceStackDisconnectSharedStorage(ctx, servicePath, request)
+		if err != nil {
+			return output.Process{}, shared.WrapAPIError(err, "Failed to disconnect shared storage")
+		}
+		process, err := resp.Output()
+		if err != nil {
+			return output.Process{}, shared.WrapAPIError(err, "Failed to disconnect shared storage")
+		}
+		return process, nil
+	}
+	resp, err := client.PutServiceStackConnectSharedStorage(ctx, servicePath, request)
+	if err != nil {
+		return output.Process{}, shared.WrapAPIError(err, "Failed to connect shared storage")
+	}
+	process, err := resp.Output()
+	if err != nil {
+		return output.Process{}, shared.WrapAPIError(err, "Failed to connect shared storage")
+	}
+	return process, nil
+}
+
+// getServiceStack loads a service by ID
+func getServiceStack(ctx context.Context, client *sdk.Handler, serviceID string) (output.ServiceStack, error) {
+	resp, err := client.GetServiceStack(ctx, path.ServiceStackId{Id: uuid.ServiceStackId(serviceID)})
+	if err != nil {
+		return output.ServiceStack{}, shared.WrapAPIError(err, "Failed to get service")
+	}
+	service, err := resp.Output()
+	if err != nil {
+		return output.ServiceStack{}, shared.WrapAPIError(err, "Failed to get service")
+	}
+	return service, nil
+}
+
+// isSharedStorage reports whether a service type is a shared storage
+func isSharedStorage(serviceType string) bool {
+	return serviceTypeBase(serviceType) == sharedStorageType
+}
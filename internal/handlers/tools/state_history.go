@@ -0,0 +1,371 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/sdk"
+)
+
+// maxSnapshotsPerProject bounds the in-memory history kept for each owner and project
+const maxSnapshotsPerProject = 50
+
+// stateSnapshot is a lightweight copy of project state taken before a mutation
+type stateSnapshot struct {
+	ID             int                        `json:"id"`
+	ProjectID      string                     `json:"project_id"`
+	Action         string                     `json:"action"`
+	Target         string                     `json:"target,omitempty"`
+	Taken          time.Time                  `json:"taken"`
+	Actor          string                     `json:"actor,omitempty"`
+	ProjectEnvKeys []string                   `json:"project_env_keys"`
+	Services       map[string]serviceSnapshot `json:"services"`
+}
+
+// serviceSnapshot is the per-service part of a stateSnapshot, keyed by hostname
+type serviceSnapshot struct {
+	ID          string   `json:"id"`
+	Type        string   `json:"type"`
+	Status      string   `json:"status"`
+	EnvKeys     []string `json:"env_keys"`
+	Autoscaling string   `json:"autoscaling,omitempty"`
+}
+
+// snapshotDiff describes what changed between two snapshots; fields are omitted when unchanged
+type snapshotDiff struct {
+	ProjectEnvAdded   []string                 `json:"project_env_added,omitempty"`
+	ProjectEnvRemoved []string                 `json:"project_env_removed,omitempty"`
+	ServicesAdded     []string                 `json:"services_added,omitempty"`
+	ServicesRemoved   []string                 `json:"services_removed,omitempty"`
+	ServicesChanged   map[string]serviceChange `json:"services_changed,omitempty"`
+}
+
+// serviceChange describes what changed in a service present in both snapshots
+type serviceChange struct {
+	Status      string             `json:"status,omitempty"`
+	Autoscaling *autoscalingChange `json:"autoscaling,omitempty"`
+	EnvAdded    []string           `json:"env_added,omitempty"`
+	EnvRemoved  []string           `json:"env_removed,omitempty"`
+}
+
+// autoscalingChange holds the autoscaling JSON before and after a change
+type autoscalingChange struct {
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// snapshotStore keeps snapshots per owner and project in memory; HTTP tenants only see
+// the history of their own API key
+type snapshotStore struct {
+	mu        sync.Mutex
+	nextID    int
+	snapshots map[string][]stateSnapshot
+}
+
+var stateHistory = &snapshotStore{
+	snapshots: make(map[string][]stateSnapshot),
+}
+
+// historyKey keys the history of a project by owner. The single stdio owner uses the bare
+// project ID, so histories saved before they were scoped still load.
+func historyKey(owner, projectID string) string {
+	if owner == "" {
+		return projectID
+	}
+	return owner + "/" + projectID
+}
+
+func (s *snapshotStore) add(owner string, snapshot stateSnapshot) stateSnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextID++
+	snapshot.ID = s.nextID
+	key := historyKey(owner, snapshot.ProjectID)
+	list := append(s.snapshots[key], snapshot)
+	if len(list) > maxSnapshotsPerProject {
+		list = list[len(list)-maxSnapshotsPerProject:]
+	}
+	s.snapshots[key] = list
+	shared.SessionStateChanged()
+	return snapshot
+}
+
+// sessionState persists the history with the session store
+func (s *snapshotStore) sessionState() shared.SessionState {
+	type saved struct {
+		NextID    int                        `json:"next_id"`
+		Snapshots map[string][]stateSnapshot `json:"snapshots"`
+	}
+	return shared.SessionState{
+		Save: func() interface{} {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			snapshots := make(map[string][]stateSnapshot, len(s.snapshots))
+			for key, list := range s.snapshots {
+				snapshots[key] = append([]stateSnapshot(nil), list...)
+			}
+			return saved{NextID: s.nextID, Snapshots: snapshots}
+		},
+		Load: func(data json.RawMessage) error {
+			var restored saved
+			if err := json.Unmarshal(data, &restored); err != nil {
+				return err
+			}
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.nextID = max(s.nextID, restored.NextID)
+			for key, list := range restored.Snapshots {
+				s.snapshots[key] = list
+			}
+			return nil
+		},
+	}
+}
+
+func (s *snapshotStore) list(owner, projectID string) []stateSnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]stateSnapshot(nil), s.snapshots[historyKey(owner, projectID)]...)
+}
+
+// captureState reads the current service list, env keys and autoscaling of a project
+func captureState(ctx context.Context, client *sdk.Handler, projectID string) (stateSnapshot, error) {
+	project, err := searchProject(ctx, client, projectID)
+	if err != nil {
+		return stateSnapshot{}, err
+	}
+
+	services, err := searchProjectServices(ctx, client, project)
+	if err != nil {
+		return stateSnapshot{}, err
+	}
+
+	snapshot := stateSnapshot{
+		ProjectID: projectID,
+		Taken:     time.Now().UTC(),
+		Services:  make(map[string]serviceSnapshot, len(services)),
+	}
+	for _, envItem := range project.EnvList {
+		snapshot.ProjectEnvKeys = append(snapshot.ProjectEnvKeys, envItem.Key.Native())
+	}
+	sort.Strings(snapshot.ProjectEnvKeys)
+
+	envKeys, err := projectServiceEnvKeys(ctx, client, project)
+	if err != nil {
+		return stateSnapshot{}, err
+	}
+
+	for _, service := range services {
+		keys := envKeys[service.Id]
+		sort.Strings(keys)
+
+		serviceState := serviceSnapshot{
+			ID:      string(service.Id),
+			Type:    string(service.ServiceStackTypeVersionId),
+			Status:  string(service.Status),
+			EnvKeys: keys,
+		}
+		if service.CustomAutoscaling != nil {
+			if autoscaling, err := json.Marshal(service.CustomAutoscaling); err == nil {
+				serviceState.Autoscaling = string(autoscaling)
+			}
+		}
+		snapshot.Services[service.Name.Native()] = serviceState
+	}
+
+	return snapshot, nil
+}
+
+// recordSnapshot stores the state of a project before a mutation.
+// Snapshots are best-effort and never block the mutation itself.
+func recordSnapshot(ctx context.Context, client *sdk.Handler, projectID, action, target string) {
+	snapshot, err := captureState(ctx, client, projectID)
+	if err != nil {
+		return
+	}
+	snapshot.Action = action
+	snapshot.Target = target
+	if identity, err := shared.ResolveIdentity(ctx, client, sessionAPIKey(ctx)); err == nil {
+		snapshot.Actor = identity.Email
+	}
+	stateHistory.add(actionOwner(ctx), snapshot)
+}
+
+// diffSnapshots describes what changed between two snapshots
+func diffSnapshots(before, after stateSnapshot) snapshotDiff {
+	var changes snapshotDiff
+	changes.ProjectEnvAdded, changes.ProjectEnvRemoved = diffKeys(before.ProjectEnvKeys, after.ProjectEnvKeys)
+
+	var servicesAdded, servicesRemoved []string
+	serviceChanges := map[string]serviceChange{}
+	for hostname, afterService := range after.Services {
+		beforeService, ok := before.Services[hostname]
+		if !ok {
+			servicesAdded = append(servicesAdded, hostname)
+			continue
+		}
+
+		var change serviceChange
+		if beforeService.Status != afterService.Status {
+			change.Status = fmt.Sprintf("%s -> %s", beforeService.Status, afterService.Status)
+		}
+		if beforeService.Autoscaling != afterService.Autoscaling {
+			change.Autoscaling = &autoscalingChange{
+				Before: beforeService.Autoscaling,
+				After:  afterService.Autoscaling,
+			}
+		}
+		change.EnvAdded, change.EnvRemoved = diffKeys(beforeService.EnvKeys, afterService.EnvKeys)
+		if change.Status != "" || change.Autoscaling != nil || change.EnvAdded != nil || change.EnvRemoved != nil {
+			serviceChanges[hostname] = change
+		}
+	}
+	for hostname := range before.Services {
+		if _, ok := after.Services[hostname]; !ok {
+			servicesRemoved = append(servicesRemoved, hostname)
+		}
+	}
+	sort.Strings(servicesAdded)
+	sort.Strings(servicesRemoved)
+
+	changes.ServicesAdded = servicesAdded
+	changes.ServicesRemoved = servicesRemoved
+	if len(serviceChanges) > 0 {
+		changes.ServicesChanged = serviceChanges
+	}
+	return changes
+}
+
+// diffKeys returns keys present only in after (added) and only in before (removed)
+func diffKeys(before, after []string) (added, removed []string) {
+	beforeSet := make(map[string]bool, len(before))
+	for _, key := range before {
+		beforeSet[key] = true
+	}
+	afterSet := make(map[string]bool, len(after))
+	for _, key := range after {
+		afterSet[key] = true
+		if !beforeSet[key] {
+			added = append(added, key)
+		}
+	}
+	for _, key := range before {
+		if !afterSet[key] {
+			removed = append(removed, key)
+		}
+	}
+	return added, removed
+}
+
+// RegisterStateHistory registers the state_history tool
+func RegisterStateHistory() {
+	shared.RegisterSessionState("state_history", stateHistory.sessionState())
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "state_history",
+		Description: toolDescription("state_history"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"project_id": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Project ID. If not provided, will check $projectId environment variable.",
+					"pattern":     "^[A-Za-z0-9_-]+$",
+				},
+				"limit": map[string]interface{}{
+					"type":        "integer",
+					"description": "OPTIONAL: Number of most recent changes to return (1-50, default: 10)",
+					"minimum":     1,
+					"maximum":     maxSnapshotsPerProject,
+					"default":     10,
+				},
+			},
+			"additionalProperties": false,
+		},
+		Annotations: shared.ReadOnly(),
+		Output:      stateHistoryResult{},
+		Handler:     handleStateHistory,
+	})
+}
+
+// stateHistoryResult is the result of state_history
+type stateHistoryResult struct {
+	ProjectID string         `json:"project_id"`
+	Changes   []stateChange  `json:"changes"`
+	Count     int            `json:"count"`
+	Current   *stateSnapshot `json:"current,omitempty"`
+	Message   string         `json:"message,omitempty"`
+}
+
+// stateChange is one recorded mutation and what changed from its snapshot to the next
+type stateChange struct {
+	SnapshotID int          `json:"snapshot_id"`
+	Action     string       `json:"action"`
+	Target     string       `json:"target"`
+	Actor      string       `json:"actor"`
+	At         string       `json:"at"`
+	Diff       snapshotDiff `json:"diff"`
+}
+
+func handleStateHistory(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+
+	projectID, err := resolveProjectID(ctx, client, args)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := 10
+	if l, ok := args["limit"].(float64); ok && l > 0 && l <= maxSnapshotsPerProject {
+		limit = int(l)
+	}
+
+	snapshots := stateHistory.list(actionOwner(ctx), projectID)
+	if len(snapshots) == 0 {
+		return stateHistoryResult{
+			ProjectID: projectID,
+			Changes:   []stateChange{},
+			Message:   "No mutations of this project recorded for your API key",
+		}, nil
+	}
+
+	current, err := captureState(ctx, client, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	start := 0
+	if len(snapshots) > limit {
+		start = len(snapshots) - limit
+	}
+
+	changes := make([]stateChange, 0, len(snapshots)-start)
+	for i := start; i < len(snapshots); i++ {
+		after := current
+		if i+1 < len(snapshots) {
+			after = snapshots[i+1]
+		}
+		changes = append(changes, stateChange{
+			SnapshotID: snapshots[i].ID,
+			Action:     snapshots[i].Action,
+			Target:     snapshots[i].Target,
+			Actor:      snapshots[i].Actor,
+			At:         snapshots[i].Taken.Format(time.RFC3339),
+			Diff:       diffSnapshots(snapshots[i], after),
+		})
+	}
+
+	return stateHistoryResult{
+		ProjectID: projectID,
+		Changes:   changes,
+		Count:     len(changes),
+		Current:   &current,
+	}, nil
+}
@@ -0,0 +1,139 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+)
+
+// timestampLayouts are the input formats seen in API and log backend responses
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999 -0700 MST",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+}
+
+// timezoneProperty is the input schema for the per-tool timezone option
+func timezoneProperty() map[string]interface{} {
+	return map[string]interface{}{
+		"type":        "string",
+		"description": "OPTIONAL: IANA timezone for returned timestamps, e.g. 'Europe/Prague' (default: the session timezone from set_preferences, $ZEROPS_MCP_TIMEZONE or UTC). Timestamps are RFC3339 unless the session chose locale dates.",
+	}
+}
+
+// displayFormat is how timestamps and sizes are rendered in the results of one call
+type displayFormat struct {
+	loc      *time.Location
+	dates    string
+	sizes    string
+	language string
+}
+
+// localeDateLayouts are the locale date layouts by language tag or primary subtag;
+// other languages get RFC1123
+var localeDateLayouts = map[string]string{
+	"en-us": "01/02/2006 03:04:05 PM MST",
+	"en-gb": "02/01/2006 15:04:05 MST",
+	"de":    "02.01.2006 15:04:05 MST",
+	"cs":    "02.01.2006 15:04:05 MST",
+	"sk":    "02.01.2006 15:04:05 MST",
+	"pl":    "02.01.2006 15:04:05 MST",
+	"ru":    "02.01.2006 15:04:05 MST",
+	"fr":    "02/01/2006 15:04:05 MST",
+	"es":    "02/01/2006 15:04:05 MST",
+	"it":    "02/01/2006 15:04:05 MST",
+	"pt":    "02/01/2006 15:04:05 MST",
+	"nl":    "02-01-2006 15:04:05 MST",
+	"ja":    "2006/01/02 15:04:05 MST",
+	"zh":    "2006/01/02 15:04:05 MST",
+	"ko":    "2006/01/02 15:04:05 MST",
+}
+
+// localeDateLayout picks the layout for a language such as "cs" or "en-US"
+func localeDateLayout(language string) string {
+	tag := strings.ToLower(language)
+	if layout, ok := localeDateLayouts[tag]; ok {
+		return layout
+	}
+	primary, _, _ := strings.Cut(tag, "-")
+	if layout, ok := localeDateLayouts[primary]; ok {
+		return layout
+	}
+	return time.RFC1123
+}
+
+// resolveDisplayFormat combines the timezone requested by the tool call (or the session
+// timezone, which defaults to $ZEROPS_MCP_TIMEZONE, or UTC) with the session's output format
+// and language
+func resolveDisplayFormat(ctx context.Context, args map[string]interface{}) (*displayFormat, error) {
+	preferences := shared.SessionPreferences(ctx)
+	name, _ := args["timezone"].(string)
+	if name == "" {
+		name = preferences.Timezone
+	}
+
+	loc := time.UTC
+	if name != "" {
+		var err error
+		loc, err = time.LoadLocation(name)
+		if err != nil {
+			return nil, shared.InvalidArgument("Unknown timezone '%s'. Use an IANA name like 'Europe/Prague' or 'UTC'.", name)
+		}
+	}
+
+	prefs := sessionOutputFormat(ctx)
+	return &displayFormat{loc: loc, dates: prefs.Dates, sizes: prefs.Sizes, language: preferences.Language}, nil
+}
+
+// formatTimestamp renders a time in the display timezone, as RFC3339 unless the session chose
+// locale dates, which follow the session language
+func formatTimestamp(t time.Time, display *displayFormat) string {
+	if t.IsZero() {
+		return ""
+	}
+	if display.dates == dateFormatLocale {
+		return t.In(display.loc).Format(localeDateLayout(display.language))
+	}
+	return t.In(display.loc).Format(time.RFC3339)
+}
+
+// formatSize renders a byte count in decimal (GB, 10^9) or binary (GiB, 2^30) units
+func formatSize(bytes float64, display *displayFormat) string {
+	base, units := 1000.0, []string{"B", "kB", "MB", "GB", "TB"}
+	if display.sizes == sizeUnitsBinary {
+		base, units = 1024.0, []string{"B", "KiB", "MiB", "GiB", "TiB"}
+	}
+
+	unit := 0
+	for bytes >= base && unit < len(units)-1 {
+		bytes /= base
+		unit++
+	}
+	if unit == 0 {
+		return fmt.Sprintf("%.0f %s", bytes, units[unit])
+	}
+	return fmt.Sprintf("%.2f %s", bytes, units[unit])
+}
+
+// normalizeTimestamp converts a timestamp string to the display format.
+// Unrecognized values are returned unchanged.
+func normalizeTimestamp(value string, display *displayFormat) string {
+	if t, ok := parseTimestamp(value); ok {
+		return formatTimestamp(t, display)
+	}
+	return value
+}
+
+// parseTimestamp parses a timestamp in any of the timestampLayouts
+func parseTimestamp(value string) (time.Time, bool) {
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,381 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/dto/input/body"
+	"github.com/zeropsio/zerops-go/dto/input/path"
+	"github.com/zeropsio/zerops-go/dto/output"
+	"github.com/zeropsio/zerops-go/sdk"
+	"github.com/zeropsio/zerops-go/types"
+	"github.com/zeropsio/zerops-go/types/enum"
+	"github.com/zeropsio/zerops-go/types/uuid"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// troubleshootProcessLimit is how many recent processes are inspected
+	troubleshootProcessLimit = 20
+	// troubleshootLogLimit is how many recent error log entries are inspected
+	troubleshootLogLimit = 50
+	// troubleshootLogWindow is how far back error logs count as recent
+	troubleshootLogWindow = 15 * time.Minute
+)
+
+// probableCause is one ranked finding of troubleshoot_service
+type probableCause struct {
+	Cause    string                 `json:"cause"`
+	Score    int                    `json:"score"`
+	Evidence string                 `json:"evidence"`
+	NextTool string                 `json:"next_tool"`
+	NextArgs map[string]interface{} `json:"next_args,omitempty"`
+}
+
+// RegisterTroubleshoot registers the troubleshoot_service tool
+func RegisterTroubleshoot() {
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "troubleshoot_service",
+		Description: toolDescription("troubleshoot_service"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"service_id": map[string]interface{}{
+					"type":        "string",
+					"description": "REQUIRED: Service ID from discovery tool",
+					"pattern":     "^[A-Za-z0-9_-]+$",
+				},
+			},
+			"required":             []string{"service_id"},
+			"additionalProperties": false,
+		},
+		Annotations: shared.ReadOnly(),
+		Output:      troubleshootResult{},
+		Handler:     handleTroubleshootService,
+	})
+}
+
+// troubleshootResult is the result of troubleshoot_service; Causes are ranked by score
+type troubleshootResult struct {
+	ServiceID   string          `json:"service_id"`
+	ServiceName string          `json:"service_name"`
+	Status      string          `json:"status"`
+	Causes      []probableCause `json:"causes"`
+	Message     string          `json:"message,omitempty"`
+	Warnings    []string        `json:"warnings,omitempty"`
+	shared.Suggestions
+}
+
+func handleTroubleshootService(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+
+	serviceID, ok := args["service_id"].(string)
+	if !ok || serviceID == "" {
+		return nil, shared.InvalidArgument("Service ID is required")
+	}
+
+	serviceResp, err := client.GetServiceStack(ctx, path.ServiceStackId{Id: uuid.ServiceStackId(serviceID)})
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to get service")
+	}
+	service, err := serviceResp.Output()
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to get service")
+	}
+	hostname := service.Name.Native()
+
+	var causes []probableCause
+	var warnings []string
+
+	// 1. Status
+	causes = append(causes, statusCauses(service)...)
+
+	// 2. Processes
+	processes, err := recentServiceProcesses(ctx, client, serviceID)
+	if err != nil {
+		warnings = append(warnings, "processes unavailable: "+err.Error())
+	}
+	causes = append(causes, processCauses(processes, serviceID)...)
+
+	// 3. Error logs
+	logs, err := fetchLogs(ctx, client, service.ProjectId, logQuery{
+		ServiceID:   serviceID,
+		Limit:       troubleshootLogLimit,
+		Facility:    getFacilityCode("APPLICATION"),
+		MinSeverity: "error",
+	})
+	if err != nil {
+		warnings = append(warnings, "error logs unavailable: "+err.Error())
+	} else if cause, ok := logCause(logs, serviceID); ok {
+		causes = append(causes, cause)
+	}
+
+	// 4. Config of the newest deployed version
+	if config := latestConfig(processes); config != "" {
+		found, configWarnings := configCauses(ctx, client, string(service.ProjectId), hostname, config)
+		causes = append(causes, found...)
+		warnings = append(warnings, configWarnings...)
+	}
+
+	sort.SliceStable(causes, func(i, j int) bool {
+		return causes[i].Score > causes[j].Score
+	})
+
+	result := troubleshootResult{
+		ServiceID:   serviceID,
+		ServiceName: hostname,
+		Status:      string(service.Status),
+		Causes:      causes,
+		Warnings:    warnings,
+	}
+	if len(causes) == 0 {
+		result.Message = "No probable cause found. Check get_service_logs without a severity filter and get_access_stats."
+		result.SuggestNext(shared.NextCall{
+			Tool:      "get_service_logs",
+			Arguments: map[string]interface{}{"service_id": serviceID},
+			Purpose:   "Read recent logs of every severity",
+		})
+	}
+	// Causes are ranked, so their follow-ups are suggested in the same order
+	for _, cause := range causes {
+		if cause.NextArgs != nil {
+			result.SuggestNext(shared.NextCall{Tool: cause.NextTool, Arguments: cause.NextArgs, Purpose: "Investigate " + cause.Cause})
+		}
+	}
+	return result, nil
+}
+
+func statusCauses(service output.ServiceStack) []probableCause {
+	serviceID := string(service.Id)
+	switch service.Status {
+	case enum.ServiceStackStatusEnumReadyToDeploy, enum.ServiceStackStatusEnumNew:
+		return []probableCause{{
+			Cause:    "never_deployed",
+			Score:    95,
+			Evidence: fmt.Sprintf("Service status is %s; no code has been deployed yet", service.Status),
+			NextTool: "knowledge_base",
+			NextArgs: map[string]interface{}{"runtime": serviceTypeBase(string(service.ServiceStackTypeVersionId))},
+		}}
+	case enum.ServiceStackStatusEnumStopped:
+		return []probableCause{{
+			Cause:    "stopped",
+			Score:    95,
+			Evidence: "Service is stopped",
+			NextTool: "restart_service",
+			NextArgs: map[string]interface{}{"service_id": serviceID},
+		}}
+	}
+	if failedServiceStatuses[service.Status] || strings.HasSuffix(string(service.Status), "FAILED") {
+		return []probableCause{{
+			Cause:    "service_failed",
+			Score:    80,
+			Evidence: fmt.Sprintf("Service status is %s", service.Status),
+			NextTool: "get_running_processes",
+			NextArgs: map[string]interface{}{"service_id": serviceID},
+		}}
+	}
+	return nil
+}
+
+// recentServiceProcesses returns the service's latest processes, newest first
+func recentServiceProcesses(ctx context.Context, client *sdk.Handler, serviceID string) (output.EsProcessResponseItems, error) {
+	processResp, err := client.PostProcessSearch(ctx, body.EsFilter{
+		Search: []body.EsSearchItem{
+			{Name: "serviceStackId", Operator: "eq", Value: types.String(serviceID)},
+		},
+		Sort: []body.EsSortItem{
+			{Name: "created", Ascending: types.NewBoolNull(false)},
+		},
+		Limit: types.NewIntNull(troubleshootProcessLimit),
+	})
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to search processes")
+	}
+	processOutput, err := processResp.Output()
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to parse processes")
+	}
+	return processOutput.Items, nil
+}
+
+// processCauses looks at the latest completed process; a failure there is what the user is seeing
+func processCauses(processes []output.EsProcess, serviceID string) []probableCause {
+	for _, process := range processes {
+		switch process.Status {
+		case enum.ProcessStatusEnumFinished:
+			return nil
+		case enum.ProcessStatusEnumFailed, enum.ProcessStatusEnumCanceled:
+		default:
+			continue
+		}
+
+		if process.AppVersion != nil && process.AppVersion.Status != nil {
+			switch *process.AppVersion.Status {
+			case enum.AppVersionStatusEnumBuildFailed:
+				return []probableCause{{
+					Cause:    "build_failed",
+					Score:    90,
+					Evidence: fmt.Sprintf("Build of app version %s failed (%s)", process.AppVersion.Id, process.ActionName.Native()),
+					NextTool: "get_service_logs",
+					NextArgs: map[string]interface{}{"service_id": serviceID, "show_build_logs": true},
+				}}
+			case enum.AppVersionStatusEnumDeployFailed:
+				return []probableCause{{
+					Cause:    "deploy_failed",
+					Score:    90,
+					Evidence: fmt.Sprintf("Deploy of app version %s failed; the previous version keeps running if there was one", process.AppVersion.Id),
+					NextTool: "get_service_logs",
+					NextArgs: map[string]interface{}{"service_id": serviceID, "minimum_severity": "error"},
+				}}
+			}
+		}
+
+		return []probableCause{{
+			Cause:    "last_process_failed",
+			Score:    85,
+			Evidence: fmt.Sprintf("Process %s (%s) ended %s", process.Id, process.ActionName.Native(), process.Status),
+			NextTool: "get_process_status",
+			NextArgs: map[string]interface{}{"process_id": string(process.Id)},
+		}}
+	}
+	return nil
+}
+
+// logCause reports recent application errors with a sample of messages
+func logCause(logs []LogData, serviceID string) (probableCause, bool) {
+	since := time.Now().Add(-troubleshootLogWindow)
+	count := 0
+	var samples []string
+	for _, entry := range logs {
+		ts, err := time.Parse(time.RFC3339Nano, entry.Timestamp)
+		if err != nil || ts.Before(since) {
+			continue
+		}
+		count++
+		if len(samples) < 3 {
+			samples = append(samples, strings.TrimSpace(entry.Message))
+		}
+	}
+	if count == 0 {
+		return probableCause{}, false
+	}
+
+	score := 60 + count
+	if score > 88 {
+		score = 88
+	}
+	return probableCause{
+		Cause:    "runtime_errors",
+		Score:    score,
+		Evidence: fmt.Sprintf("%d error log entries in the last %d minutes, e.g.: %s", count, int(troubleshootLogWindow.Minutes()), strings.Join(samples, " | ")),
+		NextTool: "get_service_logs",
+		NextArgs: map[string]interface{}{"service_id": serviceID, "minimum_severity": "error"},
+	}, true
+}
+
+// latestConfig returns the zerops.yml of the newest app version found in the processes
+func latestConfig(processes []output.EsProcess) string {
+	for _, process := range processes {
+		if process.AppVersion == nil {
+			continue
+		}
+		if config, ok := process.AppVersion.ConfigContent.Get(); ok && config.Native() != "" {
+			return config.Native()
+		}
+	}
+	return ""
+}
+
+// configCauses checks the setup of the service in zerops.yml: env references, health checks and ports
+func configCauses(ctx context.Context, client *sdk.Handler, projectID, hostname, config string) ([]probableCause, []string) {
+	var envConfig struct {
+		Zerops []zeropsEnvSetup `yaml:"zerops"`
+	}
+	if err := yaml.Unmarshal([]byte(config), &envConfig); err != nil {
+		return nil, []string{"deployed zerops.yml could not be parsed: " + err.Error()}
+	}
+
+	// The setup deployed to the service is usually named after its hostname
+	setupName := ""
+	for _, setup := range envConfig.Zerops {
+		if setup.Setup == hostname {
+			setupName = hostname
+		}
+	}
+	if setupName == "" && len(envConfig.Zerops) == 1 {
+		setupName = envConfig.Zerops[0].Setup
+	}
+	if setupName == "" {
+		return nil, []string{fmt.Sprintf("no setup named '%s' in the deployed zerops.yml; config checks skipped", hostname)}
+	}
+
+	var causes []probableCause
+	var warnings []string
+
+	for _, setup := range envConfig.Zerops {
+		if setup.Setup != setupName {
+			continue
+		}
+		references, _, err := checkEnvReferences(ctx, client, projectID, []zeropsEnvSetup{setup})
+		if err != nil {
+			warnings = append(warnings, "env references not checked: "+err.Error())
+			break
+		}
+		var broken []string
+		for _, ref := range references {
+			if ref.Status == "unknown_service" || ref.Status == "unknown_variable" {
+				broken = append(broken, fmt.Sprintf("%s in %s", ref.Reference, ref.Variable))
+			}
+		}
+		if len(broken) > 0 {
+			causes = append(causes, probableCause{
+				Cause:    "missing_env_reference",
+				Score:    75,
+				Evidence: "Env references that resolve to nothing: " + strings.Join(broken, ", "),
+				NextTool: "validate_env_references",
+				NextArgs: map[string]interface{}{"project_id": projectID},
+			})
+		}
+	}
+
+	setups, err := verifyHealthChecks(config)
+	if err != nil {
+		return causes, append(warnings, "health checks not checked: "+err.Error())
+	}
+	for _, setup := range setups {
+		if setup.Setup != setupName {
+			continue
+		}
+		var portIssues, missing []string
+		for _, issue := range setup.Issues {
+			if strings.Contains(issue, "is missing;") {
+				missing = append(missing, issue)
+			} else {
+				portIssues = append(portIssues, issue)
+			}
+		}
+		if len(portIssues) > 0 {
+			causes = append(causes, probableCause{
+				Cause:    "health_check_mismatch",
+				Score:    70,
+				Evidence: strings.Join(portIssues, "; "),
+				NextTool: "generate_healthcheck",
+			})
+		}
+		if len(missing) > 0 {
+			causes = append(causes, probableCause{
+				Cause:    "no_health_check",
+				Score:    30,
+				Evidence: strings.Join(missing, "; "),
+				NextTool: "generate_healthcheck",
+			})
+		}
+	}
+	return causes, warnings
+}
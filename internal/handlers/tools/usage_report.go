@@ -0,0 +1,343 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/dto/output"
+	"github.com/zeropsio/zerops-go/sdk"
+)
+
+const (
+	// defaultUsageReportDays is the report range when from is omitted
+	defaultUsageReportDays = 30
+	// maxUsageReportDays bounds the range; statistics are hourly
+	maxUsageReportDays = 366
+)
+
+// usageReportColumns are the CSV columns, in order; JSON reports use the same keys
+var usageReportColumns = []string{
+	"service_id", "hostname", "type", "status", "uptime_hours", "avg_containers",
+	"avg_cpu_limit", "avg_cpu_used", "avg_ram_limit_gb", "avg_ram_used_gb", "avg_disk_limit_gb", "avg_disk_used_gb",
+}
+
+// RegisterUsageReport registers the export_usage_report tool
+func RegisterUsageReport() {
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "export_usage_report",
+		Description: toolDescription("export_usage_report"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"project_id": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Project ID from discovery tool (default: $projectId)",
+					"pattern":     "^[A-Za-z0-9_-]+$",
+				},
+				"from": map[string]interface{}{
+					"type":        "string",
+					"description": fmt.Sprintf("OPTIONAL: Start of the range, RFC3339 (default: %d days before till)", defaultUsageReportDays),
+				},
+				"till": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: End of the range, RFC3339 (default: now)",
+				},
+				"format": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: csv writes one row per service, json an object with the range and a services array (default: csv)",
+					"enum":        []string{"csv", "json"},
+				},
+				"destination": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Where to write the report; inline returns it in the result (default: file in stdio mode, inline in HTTP mode)",
+					"enum":        []string{"file", "object_storage", "inline"},
+				},
+				"path": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: File path for destination file (default: zerops-usage-<project>-<from>-<till>.<ext> in the working directory)",
+				},
+				"storage_service_id": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Object storage service to upload to (default: the only object storage service in the project)",
+				},
+				"object_key": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Object key for destination object_storage (default: reports/usage-<from>-<till>.<ext>)",
+				},
+			},
+			"additionalProperties": false,
+		},
+		Annotations: shared.Mutating(false, false),
+		Output:      usageReportResult{},
+		Handler:     handleExportUsageReport,
+	})
+}
+
+// usageReportResult is the result of export_usage_report
+type usageReportResult struct {
+	ProjectID      string `json:"project_id"`
+	ProjectName    string `json:"project_name"`
+	From           string `json:"from"`
+	Till           string `json:"till"`
+	Services       int    `json:"services"`
+	Format         string `json:"format"`
+	Bytes          int    `json:"bytes"`
+	Destination    string `json:"destination"`
+	ContentType    string `json:"content_type,omitempty"`
+	Content        string `json:"content,omitempty"`
+	Path           string `json:"path,omitempty"`
+	StorageService string `json:"storage_service,omitempty"`
+	Bucket         string `json:"bucket,omitempty"`
+	ObjectKey      string `json:"object_key,omitempty"`
+	URL            string `json:"url,omitempty"`
+}
+
+func handleExportUsageReport(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+
+	projectID, err := resolveProjectID(ctx, client, args)
+	if err != nil {
+		return nil, err
+	}
+	till := time.Now().UTC()
+	if value, _ := args["till"].(string); value != "" {
+		if till, err = time.Parse(time.RFC3339, value); err != nil {
+			return nil, shared.InvalidArgument("till must be an RFC3339 timestamp, got '%s'", value)
+		}
+	}
+	from := till.AddDate(0, 0, -defaultUsageReportDays)
+	if value, _ := args["from"].(string); value != "" {
+		if from, err = time.Parse(time.RFC3339, value); err != nil {
+			return nil, shared.InvalidArgument("from must be an RFC3339 timestamp, got '%s'", value)
+		}
+	}
+	if !from.Before(till) {
+		return nil, shared.InvalidArgument("from must be before till")
+	}
+	if till.Sub(from) > maxUsageReportDays*24*time.Hour {
+		return nil, shared.InvalidArgument("The range can be at most %d days", maxUsageReportDays)
+	}
+
+	format, _ := args["format"].(string)
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "json" {
+		return nil, shared.InvalidArgument("format must be csv or json")
+	}
+	httpMode, _ := ctx.Value("httpMode").(bool)
+	destination, _ := args["destination"].(string)
+	if destination == "" {
+		destination = "file"
+		if httpMode {
+			destination = "inline"
+		}
+	}
+	switch destination {
+	case "file":
+		if httpMode {
+			return nil, shared.InvalidArgument("destination file is not available in HTTP mode: the server's filesystem is not yours. Use destination inline or object_storage.")
+		}
+	case "object_storage", "inline":
+	default:
+		return nil, shared.InvalidArgument("destination must be file, object_storage or inline")
+	}
+
+	project, err := searchProject(ctx, client, projectID)
+	if err != nil {
+		return nil, err
+	}
+	services, err := searchProjectServices(ctx, client, project)
+	if err != nil {
+		return nil, err
+	}
+
+	var target *objectStorageTarget
+	if destination == "object_storage" {
+		storageServiceID, _ := args["storage_service_id"].(string)
+		target, err = findObjectStorage(ctx, client, projectID, storageServiceID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	history, err := searchStatsHistory(ctx, client, projectID, from, till, time.UTC, "hour")
+	if err != nil {
+		return nil, err
+	}
+
+	rows := usageReportRows(services, history)
+	data, contentType, err := encodeUsageReport(rows, format, projectID, project.Name.Native(), from, till)
+	if err != nil {
+		return nil, err
+	}
+	extension := format
+	span := from.Format("20060102") + "-" + till.Format("20060102")
+
+	result := usageReportResult{
+		ProjectID:   projectID,
+		ProjectName: project.Name.Native(),
+		From:        from.Format(time.RFC3339),
+		Till:        till.Format(time.RFC3339),
+		Services:    len(rows),
+		Format:      format,
+		Bytes:       len(data),
+		Destination: destination,
+	}
+
+	switch destination {
+	case "inline":
+		result.ContentType = contentType
+		result.Content = string(data)
+	case "file":
+		filePath, _ := args["path"].(string)
+		if filePath == "" {
+			filePath = fmt.Sprintf("zerops-usage-%s-%s.%s", project.Name.Native(), span, extension)
+		}
+		written, err := writeLocalFile(ctx, filePath, data)
+		if err != nil {
+			return nil, err
+		}
+		result.Path = written
+	case "object_storage":
+		objectKey, _ := args["object_key"].(string)
+		if objectKey == "" {
+			objectKey = fmt.Sprintf("reports/usage-%s.%s", span, extension)
+		}
+		objectURL, err := target.put(ctx, objectKey, data, contentType)
+		if err != nil {
+			return nil, err
+		}
+		result.StorageService = target.hostname
+		result.Bucket = target.bucket
+		result.ObjectKey = objectKey
+		result.URL = objectURL
+	}
+	return result, nil
+}
+
+// usageReportRows summarizes hourly statistics into one row per service, keyed by
+// usageReportColumns. Services deleted during the range still get a row, without hostname.
+// An hour counts as uptime when the service ran at least one container.
+func usageReportRows(services []output.EsServiceStack, history []output.EsStatsHistory) []map[string]interface{} {
+	type totals struct {
+		hours, uptime                          int
+		containers, cpuLimit, cpuUsed          float64
+		ramLimit, ramUsed, diskLimit, diskUsed float64
+	}
+	byService := map[string]*totals{}
+	for _, item := range history {
+		id := item.ServiceStackId.Native()
+		t, ok := byService[id]
+		if !ok {
+			t = &totals{}
+			byService[id] = t
+		}
+		t.hours++
+		containers, _ := item.ContainerCount.Get()
+		if containers.Native() > 0 {
+			t.uptime++
+		}
+		t.containers += float64(containers.Native())
+		t.cpuLimit += item.CpuLimit.Native()
+		t.cpuUsed += item.CpuUsed.Native()
+		t.ramLimit += item.RamLimit.Native()
+		t.ramUsed += item.RamUsed.Native()
+		t.diskLimit += item.DiskLimit.Native()
+		t.diskUsed += item.DiskUsed.Native()
+	}
+
+	rows := make([]map[string]interface{}, 0, len(byService))
+	known := map[string]bool{}
+	addRow := func(id, hostname, serviceType, status string) {
+		row := map[string]interface{}{
+			"service_id": id,
+			"hostname":   hostname,
+			"type":       serviceType,
+			"status":     status,
+		}
+		t := byService[id]
+		if t == nil {
+			t = &totals{}
+		}
+		row["uptime_hours"] = t.uptime
+		average := func(sum, scale float64) float64 {
+			if t.hours == 0 {
+				return 0
+			}
+			return math.Round(sum/float64(t.hours)/scale*100) / 100
+		}
+		row["avg_containers"] = average(t.containers, 1)
+		row["avg_cpu_limit"] = average(t.cpuLimit, 1)
+		row["avg_cpu_used"] = average(t.cpuUsed, 1)
+		row["avg_ram_limit_gb"] = average(t.ramLimit, 1e9)
+		row["avg_ram_used_gb"] = average(t.ramUsed, 1e9)
+		row["avg_disk_limit_gb"] = average(t.diskLimit, 1e9)
+		row["avg_disk_used_gb"] = average(t.diskUsed, 1e9)
+		rows = append(rows, row)
+	}
+
+	for _, service := range services {
+		id := string(service.Id)
+		known[id] = true
+		addRow(id, service.Name.Native(), service.ServiceStackTypeInfo.ServiceStackTypeVersionName.Native(), string(service.Status))
+	}
+	var deleted []string
+	for id := range byService {
+		if !known[id] {
+			deleted = append(deleted, id)
+		}
+	}
+	sort.Strings(deleted)
+	for _, id := range deleted {
+		addRow(id, "", "", "DELETED")
+	}
+	return rows
+}
+
+// encodeUsageReport renders the rows as CSV or as a JSON document with the range
+func encodeUsageReport(rows []map[string]interface{}, format, projectID, projectName string, from, till time.Time) ([]byte, string, error) {
+	if format == "json" {
+		data, err := json.MarshalIndent(map[string]interface{}{
+			"project_id":   projectID,
+			"project_name": projectName,
+			"from":         from.Format(time.RFC3339),
+			"till":         till.Format(time.RFC3339),
+			"services":     rows,
+		}, "", "  ")
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to encode usage report: %w", err)
+		}
+		return data, "application/json", nil
+	}
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	writer.Write(usageReportColumns)
+	for _, row := range rows {
+		record := make([]string, len(usageReportColumns))
+		for i, column := range usageReportColumns {
+			switch value := row[column].(type) {
+			case string:
+				record[i] = value
+			case int:
+				record[i] = strconv.Itoa(value)
+			case float64:
+				record[i] = strconv.FormatFloat(value, 'f', -1, 64)
+			}
+		}
+		writer.Write(record)
+	}
+	writer.Flush()
+	return buf.Bytes(), "text/csv", writer.Error()
+}
@@ -0,0 +1,211 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/dto/input/body"
+	"github.com/zeropsio/zerops-go/dto/input/path"
+	"github.com/zeropsio/zerops-go/dto/output"
+	"github.com/zeropsio/zerops-go/sdk"
+	"github.com/zeropsio/zerops-go/types"
+	"github.com/zeropsio/zerops-go/types/uuid"
+)
+
+// vpnPrivateKeyPlaceholder stands in for the private key when the client brings its own key pair
+const vpnPrivateKeyPlaceholder = "<your WireGuard private key>"
+
+// vpnConfigTemplate is the wg-quick config zcli vpn up writes for a project
+var vpnConfigTemplate = template.Must(template.New("wireguard").Parse(`[Interface]
+PrivateKey = {{.PrivateKey}}
+Address = {{.Addresses}}
+DNS = {{.DNS}}, zerops
+
+[Peer]
+PublicKey = {{.ProjectPublicKey}}
+AllowedIPs = {{.AllowedIPs}}
+Endpoint = {{.Endpoint}}
+PersistentKeepalive = 25
+`))
+
+// RegisterVpn registers the vpn_connect tool
+func RegisterVpn() {
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "vpn_connect",
+		Description: toolDescription("vpn_connect"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"project_id": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Project ID (defaults to $projectId)",
+					"pattern":     "^[A-Za-z0-9_-]+$",
+				},
+				"public_key": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Your WireGuard public key (base64, from wg genkey | wg pubkey). Without it a key pair is generated.",
+				},
+				"path": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Write the config to this file (stdio mode only, e.g. zerops.conf); the private key is then left out of the result",
+				},
+			},
+			"additionalProperties": false,
+		},
+		Annotations: shared.Mutating(false, false),
+		Output:      vpnConnectResult{},
+		Handler:     handleVpnConnect,
+	})
+}
+
+// vpnConnectResult is the result of vpn_connect
+type vpnConnectResult struct {
+	ProjectID      string          `json:"project_id"`
+	PublicKey      string          `json:"public_key"`
+	AssignedIPv4   string          `json:"assigned_ipv4"`
+	AssignedIPv6   string          `json:"assigned_ipv6,omitempty"`
+	ZcliEquivalent string          `json:"zcli_equivalent"`
+	Path           string          `json:"path,omitempty"`
+	Config         string          `json:"config"`
+	Instructions   vpnInstructions `json:"instructions"`
+	Message        string          `json:"message"`
+}
+
+// vpnInstructions tells how to bring the tunnel up on each platform
+type vpnInstructions struct {
+	Linux   string `json:"linux"`
+	MacOS   string `json:"macos"`
+	Windows string `json:"windows"`
+	Access  string `json:"access"`
+}
+
+func handleVpnConnect(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+	projectID, err := resolveProjectID(ctx, client, args)
+	if err != nil {
+		return nil, err
+	}
+	filePath, _ := args["path"].(string)
+	if httpMode, _ := ctx.Value("httpMode").(bool); httpMode && filePath != "" {
+		return nil, shared.InvalidArgument("path is not available in HTTP mode: the server's filesystem is not yours. Save the returned config yourself.")
+	}
+
+	privateKey := vpnPrivateKeyPlaceholder
+	publicKey, _ := args["public_key"].(string)
+	publicKey = strings.TrimSpace(publicKey)
+	if publicKey != "" {
+		if decoded, err := base64.StdEncoding.DecodeString(publicKey); err != nil || len(decoded) != 32 {
+			return nil, shared.InvalidArgument("public_key must be a base64 WireGuard public key (32 bytes)")
+		}
+	} else {
+		privateKey, publicKey, err = generateWireguardKeys()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate WireGuard keys: %w", err)
+		}
+	}
+
+	resp, err := client.PostProjectVpn(ctx, path.ProjectId{Id: uuid.ProjectId(projectID)}, body.PostProjectVpn{
+		PublicKey: types.String(publicKey),
+	})
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to register VPN key")
+	}
+	vpn, err := resp.Output()
+	if err != nil {
+		return nil, shared.WrapAPIError(err, "Failed to register VPN key")
+	}
+
+	config, err := wireguardConfig(vpn, privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	result := vpnConnectResult{
+		ProjectID:      projectID,
+		PublicKey:      publicKey,
+		AssignedIPv4:   vpn.Peer.Ipv4.AssignedIpAddress.Native(),
+		AssignedIPv6:   vpn.Peer.Ipv6.AssignedIpAddress.Native(),
+		ZcliEquivalent: fmt.Sprintf("zcli vpn up --projectId %s", projectID),
+	}
+
+	configName := "zerops.conf"
+	if filePath != "" {
+		written, err := writeLocalFile(ctx, filePath, []byte(config))
+		if err != nil {
+			return nil, err
+		}
+		configName = written
+		result.Path = written
+		result.Config = strings.Replace(config, privateKey, "<written to "+written+">", 1)
+	} else {
+		result.Config = config
+	}
+	result.Instructions = vpnInstructions{
+		Linux:   fmt.Sprintf("Save the config and run: sudo wg-quick up %s (disconnect: sudo wg-quick down %s)", configName, configName),
+		MacOS:   fmt.Sprintf("Import %s in the WireGuard app, or with wireguard-tools: sudo wg-quick up %s", configName, configName),
+		Windows: fmt.Sprintf("In the WireGuard app choose Import tunnel(s) from file and select %s", configName),
+		Access:  "Once connected, services are reachable by hostname on their private ports, e.g. db:5432 or db.zerops:5432",
+	}
+	if privateKey == vpnPrivateKeyPlaceholder {
+		result.Message = "Your public key is registered. Replace the PrivateKey placeholder with your private key before connecting."
+	} else if filePath == "" {
+		result.Message = "A new key pair was registered. The config contains its private key; store it only on the machine that connects."
+	} else {
+		result.Message = "A new key pair was registered and the config was written with its private key."
+	}
+	return result, nil
+}
+
+// generateWireguardKeys returns a new base64 WireGuard private and public key, like wg genkey
+// and wg pubkey
+func generateWireguardKeys() (string, string, error) {
+	seed := make([]byte, 32)
+	if _, err := rand.Read(seed); err != nil {
+		return "", "", err
+	}
+	// Clamp as wg genkey does
+	seed[0] &= 248
+	seed[31] = (seed[31] & 127) | 64
+	key, err := ecdh.X25519().NewPrivateKey(seed)
+	if err != nil {
+		return "", "", err
+	}
+	return base64.StdEncoding.EncodeToString(key.Bytes()), base64.StdEncoding.EncodeToString(key.PublicKey().Bytes()), nil
+}
+
+// wireguardConfig renders the wg-quick config for a registered peer
+func wireguardConfig(vpn output.ProjectVpnItem, privateKey string) (string, error) {
+	addresses := []string{vpn.Peer.Ipv4.AssignedIpAddress.Native() + "/32"}
+	allowedIPs := []string{vpn.Project.Ipv4.Network.Network.Native()}
+	if ipv6 := vpn.Peer.Ipv6.AssignedIpAddress.Native(); ipv6 != "" {
+		addresses = append(addresses, ipv6+"/128")
+		allowedIPs = append(allowedIPs, vpn.Project.Ipv6.Network.Network.Native())
+	}
+	endpoint := vpn.Project.Ipv4.Endpoint.Native()
+	if endpoint == "" {
+		endpoint = vpn.Project.Ipv4.SharedEndpoint.Native()
+	}
+
+	var buf bytes.Buffer
+	err := vpnConfigTemplate.Execute(&buf, map[string]string{
+		"PrivateKey":       privateKey,
+		"Addresses":        strings.Join(addresses, ", "),
+		"DNS":              vpn.Peer.Ipv4.Network.Gateway.Native(),
+		"ProjectPublicKey": vpn.Project.PublicKey.Native(),
+		"AllowedIPs":       strings.Join(allowedIPs, ", "),
+		"Endpoint":         endpoint,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to render WireGuard config: %w", err)
+	}
+	return buf.String(), nil
+}
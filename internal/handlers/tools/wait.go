@@ -0,0 +1,222 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/dto/input/path"
+	"github.com/zeropsio/zerops-go/sdk"
+	"github.com/zeropsio/zerops-go/types/enum"
+	"github.com/zeropsio/zerops-go/types/uuid"
+)
+
+// Wait limits
+const (
+	defaultWaitTimeout = 5 * time.Minute
+	maxWaitTimeout     = 30 * time.Minute
+	waitPollInterval   = 5 * time.Second
+	healthCheckTimeout = 10 * time.Second
+)
+
+// failedServiceStatuses end a wait early because the service won't become ready on its own
+var failedServiceStatuses = map[enum.ServiceStackStatusEnum]bool{
+	enum.ServiceStackStatusEnumFailed:       true,
+	enum.ServiceStackStatusEnumActionFailed: true,
+	enum.ServiceStackStatusEnumDeleted:      true,
+}
+
+// RegisterWait registers the wait_for_service tool
+func RegisterWait() {
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "wait_for_service",
+		Description: toolDescription("wait_for_service"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"service_id": map[string]interface{}{
+					"type":        "string",
+					"description": "REQUIRED: Service ID from discovery tool",
+					"pattern":     "^[A-Za-z0-9_-]+$",
+				},
+				"health_url": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: HTTP(S) URL that must return 200 once the service is ACTIVE",
+				},
+				"timeout_seconds": map[string]interface{}{
+					"type":        "integer",
+					"description": "OPTIONAL: Maximum time to wait (10-1800, default: 300)",
+					"minimum":     10,
+					"maximum":     1800,
+					"default":     300,
+				},
+			},
+			"required":             []string{"service_id"},
+			"additionalProperties": false,
+		},
+		Annotations: shared.ReadOnly(),
+		Output:      waitForServiceResult{},
+		Handler:     handleWaitForService,
+	})
+}
+
+// waitForServiceResult is the result of wait_for_service
+type waitForServiceResult struct {
+	ServiceID       string `json:"service_id"`
+	ServiceName     string `json:"service_name,omitempty"`
+	HealthURL       string `json:"health_url,omitempty"`
+	Status          string `json:"status"`
+	ServiceStatus   string `json:"service_status"`
+	LastHealthCheck string `json:"last_health_check,omitempty"`
+	WaitedSeconds   int    `json:"waited_seconds"`
+	Message         string `json:"message"`
+}
+
+func handleWaitForService(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+
+	serviceID, ok := args["service_id"].(string)
+	if !ok || serviceID == "" {
+		return nil, shared.InvalidArgument("Service ID is required")
+	}
+
+	healthURL, _ := args["health_url"].(string)
+	if healthURL != "" && !strings.HasPrefix(healthURL, "http://") && !strings.HasPrefix(healthURL, "https://") {
+		return nil, shared.InvalidArgument("health_url must start with http:// or https://")
+	}
+	// A hosted server must not probe its own network on behalf of remote callers
+	httpMode, _ := ctx.Value("httpMode").(bool)
+	if httpMode && healthURL != "" {
+		if err := checkPublicURL(healthURL, "http", "https"); err != nil {
+			return nil, shared.InvalidArgument("health_url %v", err)
+		}
+	}
+
+	timeout := defaultWaitTimeout
+	if t, ok := args["timeout_seconds"].(float64); ok && t > 0 {
+		timeout = time.Duration(t) * time.Second
+		if timeout > maxWaitTimeout {
+			timeout = maxWaitTimeout
+		}
+	}
+
+	result, err := waitForService(ctx, client, serviceID, healthURL, timeout)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// waitForService polls a service until it is ACTIVE and healthy, failed or the timeout expires
+func waitForService(ctx context.Context, client *sdk.Handler, serviceID, healthURL string, timeout time.Duration) (waitForServiceResult, error) {
+	started := time.Now()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	result := waitForServiceResult{
+		ServiceID: serviceID,
+		HealthURL: healthURL,
+	}
+
+	ticker := time.NewTicker(waitPollInterval)
+	defer ticker.Stop()
+
+	httpClient := &http.Client{Timeout: healthCheckTimeout}
+	if httpMode, _ := ctx.Value("httpMode").(bool); httpMode {
+		httpClient = publicHTTPClient(healthCheckTimeout)
+	}
+	servicePath := path.ServiceStackId{Id: uuid.ServiceStackId(serviceID)}
+	var lastStatus string
+	var lastHealth string
+
+	for {
+		serviceResp, err := client.GetServiceStack(shared.WithBackgroundPolling(ctx), servicePath)
+		// Other errors are retried until the timeout, a spent polling budget won't recover in time
+		if errors.Is(err, shared.ErrBudgetExceeded) {
+			return result, err
+		}
+		if err == nil {
+			service, err := serviceResp.Output()
+			if err != nil {
+				if ctx.Err() == nil {
+					return result, shared.WrapAPIError(err, "Failed to get service")
+				}
+			} else {
+				result.ServiceName = service.Name.Native()
+				if string(service.Status) != lastStatus {
+					shared.Log(ctx, "info", "wait_for_service", fmt.Sprintf("Service %s is %s", service.Name.Native(), service.Status))
+				}
+				lastStatus = string(service.Status)
+
+				if failedServiceStatuses[service.Status] {
+					result.Status = "failed"
+					result.ServiceStatus = lastStatus
+					result.WaitedSeconds = int(time.Since(started).Seconds())
+					result.Message = fmt.Sprintf("Service is in state %s. Check get_service_logs and get_running_processes.", lastStatus)
+					return result, nil
+				}
+
+				if service.Status == enum.ServiceStackStatusEnumActive {
+					if healthURL == "" {
+						break
+					}
+					lastHealth = checkHealth(ctx, httpClient, healthURL)
+					if lastHealth == "200" {
+						break
+					}
+				}
+			}
+		}
+
+		elapsed := time.Since(started)
+		message := fmt.Sprintf("Service status %s", lastStatus)
+		if lastHealth != "" {
+			message += fmt.Sprintf(", health check: %s", lastHealth)
+		}
+		shared.ReportProgress(ctx, elapsed.Seconds(), timeout.Seconds(), message)
+
+		select {
+		case <-ctx.Done():
+			if parentErr := context.Cause(ctx); parentErr != nil && parentErr != context.DeadlineExceeded {
+				return result, parentErr
+			}
+			result.Status = "timeout"
+			result.ServiceStatus = lastStatus
+			result.LastHealthCheck = lastHealth
+			result.WaitedSeconds = int(time.Since(started).Seconds())
+			result.Message = fmt.Sprintf("Service was not ready within %d seconds.", int(timeout.Seconds()))
+			return result, nil
+		case <-ticker.C:
+		}
+	}
+
+	result.Status = "ready"
+	result.ServiceStatus = lastStatus
+	result.WaitedSeconds = int(time.Since(started).Seconds())
+	if healthURL != "" {
+		result.Message = "Service is ACTIVE and its health endpoint returned 200."
+	} else {
+		result.Message = "Service is ACTIVE."
+	}
+	return result, nil
+}
+
+// checkHealth requests the health URL and returns the status code or the error as text
+func checkHealth(ctx context.Context, httpClient *http.Client, healthURL string) string {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, healthURL, nil)
+	if err != nil {
+		return err.Error()
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err.Error()
+	}
+	resp.Body.Close()
+	return fmt.Sprintf("%d", resp.StatusCode)
+}
@@ -0,0 +1,395 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/dto/input/path"
+	"github.com/zeropsio/zerops-go/sdk"
+	"github.com/zeropsio/zerops-go/types/uuid"
+)
+
+// Watch limits
+const (
+	defaultWatchInterval = 30 * time.Second
+	minWatchInterval     = 10 * time.Second
+	defaultWatchDuration = time.Hour
+	maxWatchDuration     = 24 * time.Hour
+	maxWatchesPerOwner   = 10
+)
+
+// serviceWatch is a server-side poller for one service
+type serviceWatch struct {
+	ID         string    `json:"watch_id"`
+	ServiceID  string    `json:"service_id"`
+	Hostname   string    `json:"hostname"`
+	Interval   string    `json:"interval"`
+	Expires    time.Time `json:"expires"`
+	WebhookURL string    `json:"webhook_url,omitempty"`
+	Status     string    `json:"last_status"`
+	AppVersion string    `json:"last_app_version,omitempty"`
+	Changes    int       `json:"changes_detected"`
+	Owner      string    `json:"-"`
+
+	cancel context.CancelFunc
+}
+
+// watchState is what a watch compares between polls
+type watchState struct {
+	Status           string
+	AppVersionID     string
+	AppVersionStatus string
+}
+
+type watchRegistry struct {
+	mu      sync.Mutex
+	nextID  int
+	watches map[string]*serviceWatch
+}
+
+var watches = &watchRegistry{
+	watches: make(map[string]*serviceWatch),
+}
+
+func (r *watchRegistry) add(watch *serviceWatch) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for _, existing := range r.watches {
+		if existing.Owner == watch.Owner {
+			count++
+		}
+	}
+	if count >= maxWatchesPerOwner {
+		return shared.InvalidArgument("Too many active watches (max %d). Remove one with unwatch_service first.", maxWatchesPerOwner)
+	}
+
+	r.nextID++
+	watch.ID = fmt.Sprintf("watch-%d", r.nextID)
+	r.watches[watch.ID] = watch
+	return nil
+}
+
+func (r *watchRegistry) remove(id, owner string) (*serviceWatch, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	watch, ok := r.watches[id]
+	if !ok || watch.Owner != owner {
+		return nil, false
+	}
+	delete(r.watches, id)
+	watch.cancel()
+	return watch, true
+}
+
+func (r *watchRegistry) update(watch *serviceWatch, state watchState, changed bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	watch.Status = state.Status
+	watch.AppVersion = state.AppVersionID
+	if changed {
+		watch.Changes++
+	}
+}
+
+func (r *watchRegistry) list(owner string) []serviceWatch {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	list := make([]serviceWatch, 0, len(r.watches))
+	for _, watch := range r.watches {
+		if watch.Owner == owner {
+			list = append(list, *watch)
+		}
+	}
+	return list
+}
+
+// readWatchState fetches the fields a watch tracks
+func readWatchState(ctx context.Context, client *sdk.Handler, serviceID string) (watchState, string, error) {
+	serviceResp, err := client.GetServiceStack(ctx, path.ServiceStackId{Id: uuid.ServiceStackId(serviceID)})
+	if err != nil {
+		return watchState{}, "", shared.WrapAPIError(err, "Failed to get service")
+	}
+	service, err := serviceResp.Output()
+	if err != nil {
+		return watchState{}, "", shared.WrapAPIError(err, "Failed to get service")
+	}
+
+	state := watchState{Status: string(service.Status)}
+	if service.ActiveAppVersion != nil {
+		state.AppVersionID = string(service.ActiveAppVersion.Id)
+		state.AppVersionStatus = string(service.ActiveAppVersion.Status)
+	}
+	return state, service.Name.Native(), nil
+}
+
+// runWatch polls the service until the watch expires or is removed
+func runWatch(ctx context.Context, client *sdk.Handler, watch *serviceWatch, interval time.Duration, initial watchState, notifier shared.Notifier) {
+	defer func() {
+		watches.mu.Lock()
+		delete(watches.watches, watch.ID)
+		watches.mu.Unlock()
+	}()
+
+	last := initial
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	pollCtx := shared.WithBackgroundPolling(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			event := "watch_expired"
+			if ctx.Err() == context.Canceled {
+				event = "watch_stopped"
+			}
+			emitWatchEvent(context.Background(), watch, notifier, event, last, last)
+			return
+		case <-ticker.C:
+			state, _, err := readWatchState(pollCtx, client, watch.ServiceID)
+			if err != nil {
+				continue
+			}
+			changed := state != last
+			watches.update(watch, state, changed)
+			if changed {
+				emitWatchEvent(ctx, watch, notifier, "service_changed", last, state)
+				last = state
+			}
+		}
+	}
+}
+
+// emitWatchEvent delivers a watch event via MCP notification and/or webhook
+func emitWatchEvent(ctx context.Context, watch *serviceWatch, notifier shared.Notifier, event string, before, after watchState) {
+	payload := map[string]interface{}{
+		"event":      event,
+		"watch_id":   watch.ID,
+		"service_id": watch.ServiceID,
+		"hostname":   watch.Hostname,
+		"at":         time.Now().UTC().Format(time.RFC3339),
+		"before": map[string]interface{}{
+			"status":             before.Status,
+			"app_version_id":     before.AppVersionID,
+			"app_version_status": before.AppVersionStatus,
+		},
+		"after": map[string]interface{}{
+			"status":             after.Status,
+			"app_version_id":     after.AppVersionID,
+			"app_version_status": after.AppVersionStatus,
+		},
+	}
+
+	if notifier != nil {
+		level := "info"
+		if event == "service_changed" {
+			level = "notice"
+		}
+		if err := notifier(ctx, level, "watch_service", payload); err != nil {
+			fmt.Fprintf(os.Stderr, "Watch %s: failed to send notification: %v\n", watch.ID, err)
+		}
+	}
+
+	if watch.WebhookURL != "" {
+		data, _ := json.Marshal(payload)
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, watch.WebhookURL, bytes.NewReader(data))
+		if err != nil {
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := publicHTTPClient(10 * time.Second).Do(req)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Watch %s: webhook failed: %v\n", watch.ID, err)
+			return
+		}
+		resp.Body.Close()
+	}
+}
+
+// RegisterWatch registers the service watch tools
+func RegisterWatch() {
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "watch_service",
+		Description: toolDescription("watch_service"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"service_id": map[string]interface{}{
+					"type":        "string",
+					"description": "Service ID to watch (from discovery). Omit to list active watches.",
+					"pattern":     "^[A-Za-z0-9_-]+$",
+				},
+				"interval_seconds": map[string]interface{}{
+					"type":        "integer",
+					"description": "OPTIONAL: Poll interval in seconds (10-600, default: 30)",
+					"minimum":     10,
+					"maximum":     600,
+					"default":     30,
+				},
+				"expires_in_minutes": map[string]interface{}{
+					"type":        "integer",
+					"description": "OPTIONAL: Stop watching after this many minutes (1-1440, default: 60)",
+					"minimum":     1,
+					"maximum":     1440,
+					"default":     60,
+				},
+				"webhook_url": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Public HTTPS URL that receives change events as JSON POST requests",
+				},
+			},
+			"additionalProperties": false,
+		},
+		Annotations: shared.Mutating(false, false),
+		Output:      watchServiceResult{},
+		Handler:     handleWatchService,
+	})
+
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "unwatch_service",
+		Description: toolDescription("unwatch_service"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"watch_id": map[string]interface{}{
+					"type":        "string",
+					"description": "REQUIRED: Watch ID returned by watch_service",
+				},
+			},
+			"required":             []string{"watch_id"},
+			"additionalProperties": false,
+		},
+		Annotations: shared.Mutating(false, true),
+		Output:      unwatchServiceResult{},
+		Handler:     handleUnwatchService,
+	})
+}
+
+// watchServiceResult is the result of watch_service. Without service_id it lists the active
+// watches; otherwise it describes the watch just started.
+type watchServiceResult struct {
+	Watches   []serviceWatch `json:"watches,omitempty"`
+	Count     *int           `json:"count,omitempty"`
+	WatchID   string         `json:"watch_id,omitempty"`
+	ServiceID string         `json:"service_id,omitempty"`
+	Hostname  string         `json:"hostname,omitempty"`
+	Status    string         `json:"status,omitempty"`
+	Interval  string         `json:"interval,omitempty"`
+	Expires   string         `json:"expires,omitempty"`
+	Message   string         `json:"message,omitempty"`
+}
+
+// unwatchServiceResult is the result of unwatch_service
+type unwatchServiceResult struct {
+	WatchID         string `json:"watch_id"`
+	ChangesDetected int    `json:"changes_detected"`
+	Message         string `json:"message"`
+}
+
+func handleWatchService(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+
+	owner := actionOwner(ctx)
+	serviceID, _ := args["service_id"].(string)
+	if serviceID == "" {
+		active := watches.list(owner)
+		count := len(active)
+		return watchServiceResult{
+			Watches: active,
+			Count:   &count,
+		}, nil
+	}
+
+	interval := defaultWatchInterval
+	if i, ok := args["interval_seconds"].(float64); ok {
+		interval = time.Duration(i) * time.Second
+		if interval < minWatchInterval {
+			interval = minWatchInterval
+		}
+	}
+
+	duration := defaultWatchDuration
+	if m, ok := args["expires_in_minutes"].(float64); ok && m > 0 {
+		duration = time.Duration(m) * time.Minute
+		if duration > maxWatchDuration {
+			duration = maxWatchDuration
+		}
+	}
+
+	webhookURL, _ := args["webhook_url"].(string)
+	if webhookURL != "" {
+		if err := checkPublicURL(webhookURL, "https"); err != nil {
+			return nil, shared.InvalidArgument("webhook_url %v", err)
+		}
+	}
+
+	notifier := shared.NotifierFromContext(ctx)
+	if notifier == nil && webhookURL == "" {
+		return nil, shared.InvalidArgument("This transport cannot push notifications. Provide webhook_url to receive change events.")
+	}
+
+	initial, hostname, err := readWatchState(ctx, client, serviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The watch outlives the tool call, so it must not inherit the request context
+	watchCtx, cancel := context.WithTimeout(context.Background(), duration)
+	watch := &serviceWatch{
+		ServiceID:  serviceID,
+		Hostname:   hostname,
+		Interval:   interval.String(),
+		Expires:    time.Now().Add(duration).UTC(),
+		WebhookURL: webhookURL,
+		Status:     initial.Status,
+		AppVersion: initial.AppVersionID,
+		Owner:      owner,
+		cancel:     cancel,
+	}
+	if err := watches.add(watch); err != nil {
+		cancel()
+		return nil, err
+	}
+
+	go runWatch(watchCtx, client, watch, interval, initial, notifier)
+
+	return watchServiceResult{
+		WatchID:   watch.ID,
+		ServiceID: serviceID,
+		Hostname:  hostname,
+		Status:    initial.Status,
+		Interval:  watch.Interval,
+		Expires:   watch.Expires.Format(time.RFC3339),
+		Message:   fmt.Sprintf("Watching '%s'. Changes will be reported until %s.", hostname, watch.Expires.Format(time.RFC3339)),
+	}, nil
+}
+
+func handleUnwatchService(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	watchID, ok := args["watch_id"].(string)
+	if !ok || watchID == "" {
+		return nil, shared.InvalidArgument("Watch ID is required")
+	}
+
+	watch, ok := watches.remove(watchID, actionOwner(ctx))
+	if !ok {
+		return nil, shared.NotFound("Watch '%s' not found", watchID)
+	}
+
+	return unwatchServiceResult{
+		WatchID:         watch.ID,
+		ChangesDetected: watch.Changes,
+		Message:         fmt.Sprintf("Stopped watching '%s'", watch.Hostname),
+	}, nil
+}
@@ -0,0 +1,78 @@
+package tools
+
+import (
+	"context"
+	"time"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/sdk"
+)
+
+// RegisterWhoami registers the whoami tool
+func RegisterWhoami() {
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "whoami",
+		Description: toolDescription("whoami"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"refresh": map[string]interface{}{
+					"type":        "boolean",
+					"description": "OPTIONAL: Read the identity from the API even when it is cached (default: false)",
+				},
+			},
+			"additionalProperties": false,
+		},
+		Annotations: shared.ReadOnly(),
+		Output:      whoamiResult{},
+		Handler:     handleWhoami,
+	})
+}
+
+// whoamiResult is the result of whoami
+type whoamiResult struct {
+	User           whoamiUser           `json:"user"`
+	Organizations  []shared.IdentityOrg `json:"organizations"`
+	Cached         bool                 `json:"cached"`
+	ResolvedAt     string               `json:"resolved_at"`
+	KeyFingerprint string               `json:"key_fingerprint,omitempty"`
+}
+
+// whoamiUser is the user an API key belongs to
+type whoamiUser struct {
+	ID       string `json:"id"`
+	Email    string `json:"email"`
+	FullName string `json:"full_name"`
+}
+
+func handleWhoami(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	if client == nil {
+		return nil, shared.ErrNoClient
+	}
+
+	apiKey := sessionAPIKey(ctx)
+	if refresh, _ := args["refresh"].(bool); refresh {
+		shared.ForgetIdentity(apiKey)
+	}
+	cached := shared.CachedIdentity(apiKey) != nil
+
+	identity, err := shared.ResolveIdentity(ctx, client, apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	result := whoamiResult{
+		User: whoamiUser{
+			ID:       identity.UserID,
+			Email:    identity.Email,
+			FullName: identity.FullName,
+		},
+		Organizations: identity.Organizations,
+		Cached:        cached,
+		ResolvedAt:    identity.ResolvedAt.UTC().Format(time.RFC3339),
+	}
+	if apiKey != "" {
+		result.KeyFingerprint = shared.OwnerID(apiKey)
+	}
+	return result, nil
+}
@@ -0,0 +1,325 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/sdk"
+)
+
+// generatorServices map the service hints of generate_zerops_yml to the planEnvVariables inputs
+var generatorServices = map[string]string{
+	"postgresql":     "database",
+	"mariadb":        "database",
+	"mongodb":        "database",
+	"valkey":         "cache",
+	"object-storage": "storage",
+}
+
+var (
+	setupNamePattern   = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,39}$`)
+	envVarNamePattern  = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	typeVersionPattern = regexp.MustCompile(`^[0-9][0-9a-z.]*$`)
+)
+
+// RegisterZeropsYml registers the generate_zerops_yml tool
+func RegisterZeropsYml() {
+	shared.GlobalRegistry.Register(&shared.ToolDefinition{
+		Name:        "generate_zerops_yml",
+		Description: toolDescription("generate_zerops_yml"),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"runtime": map[string]interface{}{
+					"type":        "string",
+					"description": "REQUIRED: Runtime of the app",
+					"enum":        []string{"nodejs", "bun", "python", "go", "php"},
+				},
+				"version": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Runtime version, e.g. 20 for nodejs@20 (default: latest recipe version)",
+				},
+				"build_commands": map[string]interface{}{
+					"type":        "array",
+					"description": "OPTIONAL: Build commands (default: runtime recipe, e.g. npm ci, npm run build)",
+					"items":       map[string]interface{}{"type": "string"},
+				},
+				"deploy_files": map[string]interface{}{
+					"type":        "array",
+					"description": "OPTIONAL: Files and directories to deploy after the build (default: runtime recipe)",
+					"items":       map[string]interface{}{"type": "string"},
+				},
+				"start_command": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: Command that starts the app; {port} is replaced by the first port (default: runtime recipe; not used by php)",
+				},
+				"ports": map[string]interface{}{
+					"type":        "array",
+					"description": "OPTIONAL: Ports the app listens on; the first serves HTTP and is health checked (default: runtime default)",
+					"items": map[string]interface{}{
+						"type":    "integer",
+						"minimum": 10,
+						"maximum": 65435,
+					},
+					"minItems": 1,
+				},
+				"health_check_path": map[string]interface{}{
+					"type":        "string",
+					"description": "OPTIONAL: HTTP path of the readiness and health checks (default: /)",
+				},
+				"env_variables": map[string]interface{}{
+					"type":        "object",
+					"description": "OPTIONAL: Extra run.envVariables, e.g. {\"NODE_ENV\": \"production\"}",
+					"additionalProperties": map[string]interface{}{
+						"type": "string",
+					},
+				},
+				"uses": map[string]interface{}{
+					"type":        "array",
+					"description": "OPTIONAL: Managed services the app connects to; adds env variables referencing them",
+					"items": map[string]interface{}{
+						"type": "string",
+						"enum": []string{"postgresql", "mariadb", "mongodb", "valkey", "object-storage"},
+					},
+				},
+				"setups": map[string]interface{}{
+					"type":        "array",
+					"description": "OPTIONAL: Setup names, usually matching the service hostnames deploy_push uses (default: [dev, prod])",
+					"items":       map[string]interface{}{"type": "string"},
+					"minItems":    1,
+				},
+			},
+			"required":             []string{"runtime"},
+			"additionalProperties": false,
+		},
+		Annotations: shared.ReadOnly(),
+		Output:      generateZeropsYmlResult{},
+		Handler:     handleGenerateZeropsYml,
+	})
+}
+
+// generateZeropsYmlResult is the result of generate_zerops_yml
+type generateZeropsYmlResult struct {
+	ZeropsYml string        `json:"zerops_yml"`
+	Spec      zeropsYmlSpec `json:"spec"`
+	Notes     []string      `json:"notes,omitempty"`
+}
+
+// zeropsYmlSpec lists the values the generated zerops.yml was built from
+type zeropsYmlSpec struct {
+	Runtime         string   `json:"runtime"`
+	ServiceType     string   `json:"service_type"`
+	BuildBase       string   `json:"build_base"`
+	RunBase         string   `json:"run_base"`
+	BuildCommands   []string `json:"build_commands"`
+	DeployFiles     []string `json:"deploy_files"`
+	StartCommand    string   `json:"start_command"`
+	Ports           []int    `json:"ports"`
+	HealthCheckPath string   `json:"health_check_path"`
+	Setups          []string `json:"setups"`
+}
+
+func handleGenerateZeropsYml(ctx context.Context, client *sdk.Handler, args map[string]interface{}) (interface{}, error) {
+	runtime, _ := args["runtime"].(string)
+	runtime = strings.ToLower(strings.SplitN(runtime, "@", 2)[0])
+	profile, ok := runtimeProfiles[runtime]
+	if !ok {
+		return nil, shared.InvalidArgument("Unsupported runtime '%s'. Use one of: nodejs, bun, python, go, php.", runtime)
+	}
+
+	if version, _ := args["version"].(string); version != "" {
+		if !typeVersionPattern.MatchString(version) {
+			return nil, shared.InvalidArgument("version must look like 22 or 3.12, got '%s'", version)
+		}
+		profile.serviceType = withTypeVersion(profile.serviceType, version)
+		profile.buildBase = withTypeVersion(profile.buildBase, version)
+		profile.runBase = withTypeVersion(profile.runBase, version)
+	}
+
+	if commands, err := stringListArg(args, "build_commands"); err != nil {
+		return nil, err
+	} else if commands != nil {
+		profile.buildCommands = commands
+	}
+	if files, err := stringListArg(args, "deploy_files"); err != nil {
+		return nil, err
+	} else if files != nil {
+		profile.deployFiles = files
+	}
+	if len(profile.buildCommands) == 0 || len(profile.deployFiles) == 0 {
+		return nil, shared.InvalidArgument("build_commands and deploy_files must not be empty")
+	}
+	if start, _ := args["start_command"].(string); start != "" {
+		if strings.HasPrefix(profile.runBase, "php-") {
+			return nil, shared.InvalidArgument("php services are started by their web server; omit start_command")
+		}
+		profile.start = start
+	}
+
+	ports := []int{runtimeDefaultPorts[runtime]}
+	if list, ok := args["ports"].([]interface{}); ok && len(list) > 0 {
+		ports = nil
+		seen := map[int]bool{}
+		for _, item := range list {
+			value, ok := item.(float64)
+			port := int(value)
+			if !ok || float64(port) != value || port < 10 || port > 65435 {
+				return nil, shared.InvalidArgument("ports must be integers between 10 and 65435, got %v", item)
+			}
+			if !seen[port] {
+				seen[port] = true
+				ports = append(ports, port)
+			}
+		}
+	}
+
+	healthPath, _ := args["health_check_path"].(string)
+	if healthPath == "" {
+		healthPath = "/"
+	}
+	if !strings.HasPrefix(healthPath, "/") {
+		return nil, shared.InvalidArgument("health_check_path must start with /, got '%s'", healthPath)
+	}
+
+	var database string
+	var needsCache, needsStorage bool
+	uses, err := stringListArg(args, "uses")
+	if err != nil {
+		return nil, err
+	}
+	for _, service := range uses {
+		switch generatorServices[service] {
+		case "database":
+			if database != "" && database != service {
+				return nil, shared.InvalidArgument("uses can name one database, got %s and %s", database, service)
+			}
+			database = service
+		case "cache":
+			needsCache = true
+		case "storage":
+			needsStorage = true
+		default:
+			return nil, shared.InvalidArgument("Unknown service '%s' in uses. Use postgresql, mariadb, mongodb, valkey or object-storage.", service)
+		}
+	}
+	envVars := planEnvVariables(database, needsCache, needsStorage)
+
+	if extra, ok := args["env_variables"].(map[string]interface{}); ok {
+		keys := make([]string, 0, len(extra))
+		for key := range extra {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if !envVarNamePattern.MatchString(key) {
+				return nil, shared.InvalidArgument("Invalid env variable name '%s'", key)
+			}
+			value, ok := extra[key].(string)
+			if !ok {
+				return nil, shared.InvalidArgument("env variable %s must be a string", key)
+			}
+			replaced := false
+			for i := range envVars {
+				if envVars[i][0] == key {
+					envVars[i][1] = value
+					replaced = true
+				}
+			}
+			if !replaced {
+				envVars = append(envVars, [2]string{key, value})
+			}
+		}
+	}
+
+	setups := []string{"dev", "prod"}
+	if names, err := stringListArg(args, "setups"); err != nil {
+		return nil, err
+	} else if len(names) > 0 {
+		setups = nil
+		seen := map[string]bool{}
+		for _, name := range names {
+			if !setupNamePattern.MatchString(name) {
+				return nil, shared.InvalidArgument("Invalid setup name '%s'; use lowercase letters, digits, - and _", name)
+			}
+			if !seen[name] {
+				seen[name] = true
+				setups = append(setups, name)
+			}
+		}
+	}
+
+	rendered := renderZeropsYml(profile, setups, ports, healthPath, envVars)
+	if _, issues := validateZeropsYml([]byte(rendered)); len(issues) > 0 && issues[0].Severity == "error" {
+		return nil, fmt.Errorf("generated zerops.yml is invalid: %s", issues[0])
+	}
+
+	result := generateZeropsYmlResult{
+		ZeropsYml: rendered,
+		Spec: zeropsYmlSpec{
+			Runtime:         runtime,
+			ServiceType:     profile.serviceType,
+			BuildBase:       profile.buildBase,
+			RunBase:         profile.runBase,
+			BuildCommands:   profile.buildCommands,
+			DeployFiles:     profile.deployFiles,
+			StartCommand:    profile.start,
+			Ports:           ports,
+			HealthCheckPath: healthPath,
+			Setups:          setups,
+		},
+	}
+
+	var notes []string
+	for _, setup := range setups {
+		if setup == "dev" {
+			notes = append(notes, "dev idles after deploy; start the dev server over SSH, e.g. "+devServerHint(runtime)+".")
+			break
+		}
+	}
+	if strings.HasPrefix(profile.runBase, "php-") {
+		notes = append(notes, "php-nginx serves the deployed files on port 80; set run.documentRoot (e.g. public) for frameworks like Laravel.")
+	}
+	result.Notes = notes
+	return result, nil
+}
+
+// withTypeVersion replaces the version of a service type such as nodejs@22
+func withTypeVersion(serviceType, version string) string {
+	return strings.SplitN(serviceType, "@", 2)[0] + "@" + version
+}
+
+// stringListArg reads an optional array of strings; nil means the argument is absent
+func stringListArg(args map[string]interface{}, name string) ([]string, error) {
+	list, ok := args[name].([]interface{})
+	if !ok {
+		return nil, nil
+	}
+	values := make([]string, 0, len(list))
+	for _, item := range list {
+		value, ok := item.(string)
+		if !ok || strings.TrimSpace(value) == "" {
+			return nil, shared.InvalidArgument("%s must contain non-empty strings, got %v", name, item)
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// devServerHint is the usual command that starts a runtime's dev server
+func devServerHint(runtime string) string {
+	switch runtime {
+	case "nodejs":
+		return "npm run dev"
+	case "bun":
+		return "bun run dev"
+	case "python":
+		return "python app.py"
+	case "go":
+		return "go run ."
+	}
+	return "the framework's dev command"
+}
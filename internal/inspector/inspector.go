@@ -0,0 +1,251 @@
+// Package inspector serves a local web UI for listing and calling the registered tools
+// without an MCP client, for developing and debugging handlers.
+package inspector
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/sdk"
+)
+
+//go:embed index.html
+var indexHTML []byte
+
+// maxRecentCalls is how many calls the UI keeps
+const maxRecentCalls = 50
+
+// errCallFinished is returned to tools that notify after their inspector call returned
+var errCallFinished = errors.New("inspector call finished, notifications are no longer collected")
+
+// Config configures the inspector server
+type Config struct {
+	// Addr is the listen address; keep it on loopback, the UI calls tools with your API key
+	Addr          string
+	Client        *sdk.Handler
+	ClientFactory shared.ClientFactory
+}
+
+// callRecord is one tool call made from the UI
+type callRecord struct {
+	ID         int                    `json:"id"`
+	Tool       string                 `json:"tool"`
+	Arguments  map[string]interface{} `json:"arguments"`
+	Result     interface{}            `json:"result,omitempty"`
+	Error      string                 `json:"error,omitempty"`
+	ErrorCode  string                 `json:"error_code,omitempty"`
+	Messages   []callMessage          `json:"messages,omitempty"`
+	Started    time.Time              `json:"started"`
+	DurationMs int64                  `json:"duration_ms"`
+}
+
+// callMessage is a log or progress notification sent by a tool during a call
+type callMessage struct {
+	Level  string      `json:"level"`
+	Logger string      `json:"logger,omitempty"`
+	Data   interface{} `json:"data"`
+}
+
+type inspector struct {
+	token string
+
+	mu     sync.Mutex
+	client *sdk.Handler
+	calls  []*callRecord
+	nextID int
+
+	factory shared.ClientFactory
+}
+
+// Start serves the inspector until ctx is cancelled. The URL with its access token is
+// printed to stderr; API requests without the token are rejected.
+func Start(ctx context.Context, config Config) error {
+	token, err := newToken()
+	if err != nil {
+		return err
+	}
+	in := &inspector{token: token, client: config.Client, factory: config.ClientFactory}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", in.handleIndex)
+	mux.HandleFunc("/api/tools", in.authorized(in.handleTools))
+	mux.HandleFunc("/api/calls", in.authorized(in.handleCalls))
+	mux.HandleFunc("/api/call", in.authorized(in.handleCall))
+
+	listener, err := net.Listen("tcp", config.Addr)
+	if err != nil {
+		return err
+	}
+	if host, _, err := net.SplitHostPort(listener.Addr().String()); err == nil {
+		if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+			fmt.Fprintf(os.Stderr, "WARNING: the inspector listens on %s, not loopback; anyone with the URL can call tools with your API key\n", host)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Inspector: http://%s/?token=%s\n", listener.Addr(), token)
+
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+	if err := server.Serve(listener); err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+func newToken() (string, error) {
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("failed to generate inspector token: %w", err)
+	}
+	return hex.EncodeToString(raw), nil
+}
+
+// authorized requires the access token in the X-Inspector-Token header
+func (in *inspector) authorized(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := r.Header.Get("X-Inspector-Token")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(in.token)) != 1 {
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "missing or wrong inspector token; open the URL printed on startup"})
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (in *inspector) handleIndex(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Security-Policy", "default-src 'self'; script-src 'unsafe-inline'; style-src 'unsafe-inline'")
+	w.Write(indexHTML)
+}
+
+func (in *inspector) handleTools(w http.ResponseWriter, r *http.Request) {
+	tools := make([]map[string]interface{}, 0)
+	for _, tool := range shared.GlobalRegistry.List() {
+		entry := map[string]interface{}{
+			"name":        tool.Name,
+			"description": tool.Description,
+			"inputSchema": tool.InputSchema,
+		}
+		if tool.Annotations != nil {
+			entry["annotations"] = tool.Annotations.Map()
+		}
+		tools = append(tools, entry)
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"tools": tools})
+}
+
+// handleCalls lists recent calls; records are complete and no longer change once listed
+func (in *inspector) handleCalls(w http.ResponseWriter, r *http.Request) {
+	in.mu.Lock()
+	calls := make([]*callRecord, len(in.calls))
+	copy(calls, in.calls)
+	in.mu.Unlock()
+	writeJSON(w, http.StatusOK, map[string]interface{}{"calls": calls})
+}
+
+func (in *inspector) handleCall(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use POST"})
+		return
+	}
+	var request struct {
+		Name      string                 `json:"name"`
+		Arguments map[string]interface{} `json:"arguments"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&request); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON: " + err.Error()})
+		return
+	}
+	if request.Arguments == nil {
+		request.Arguments = map[string]interface{}{}
+	}
+
+	record := &callRecord{Tool: request.Name, Arguments: request.Arguments, Started: time.Now()}
+	// Tools like watch_service keep notifying after the call returned; the record is shared
+	// with /api/calls by then, so later messages are dropped instead of appended
+	var messagesMu sync.Mutex
+	finished := false
+	collect := func(ctx context.Context, level, logger string, data interface{}) error {
+		messagesMu.Lock()
+		defer messagesMu.Unlock()
+		if finished {
+			return errCallFinished
+		}
+		record.Messages = append(record.Messages, callMessage{Level: level, Logger: logger, Data: data})
+		return nil
+	}
+
+	ctx := r.Context()
+	in.mu.Lock()
+	client := in.client
+	in.mu.Unlock()
+	if client != nil {
+		ctx = context.WithValue(ctx, "zeropsClient", client)
+	}
+	if in.factory != nil {
+		ctx = shared.WithClientFactory(ctx, in.factory)
+		ctx = shared.WithKeyStore(ctx, func(ctx context.Context, apiKey string, client *sdk.Handler) error {
+			in.mu.Lock()
+			in.client = client
+			in.mu.Unlock()
+			return nil
+		})
+	}
+	ctx = shared.WithNotifier(ctx, collect)
+	ctx = shared.WithLogger(ctx, collect)
+	ctx = shared.WithProgress(ctx, func(ctx context.Context, progress, total float64, message string) error {
+		return collect(ctx, "progress", "", map[string]interface{}{"progress": progress, "total": total, "message": message})
+	})
+	ctx = context.WithValue(ctx, "clientName", "inspector")
+
+	result, err := shared.GlobalRegistry.CallTool(ctx, request.Name, request.Arguments)
+	messagesMu.Lock()
+	finished = true
+	messagesMu.Unlock()
+	record.DurationMs = time.Since(record.Started).Milliseconds()
+	if err != nil {
+		record.Error = err.Error()
+		record.ErrorCode = shared.ErrorCode(err)
+	} else {
+		record.Result = result
+	}
+
+	in.mu.Lock()
+	in.nextID++
+	record.ID = in.nextID
+	in.calls = append([]*callRecord{record}, in.calls...)
+	if len(in.calls) > maxRecentCalls {
+		in.calls = in.calls[:maxRecentCalls]
+	}
+	in.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, record)
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(value)
+}
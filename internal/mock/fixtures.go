@@ -0,0 +1,747 @@
+package mock
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/zeropsio/zerops-go/dto/output"
+	"github.com/zeropsio/zerops-go/types"
+	"github.com/zeropsio/zerops-go/types/enum"
+	"github.com/zeropsio/zerops-go/types/stringId"
+	"github.com/zeropsio/zerops-go/types/uuid"
+)
+
+// Fixed identities of the demo organization
+const (
+	clientID    = "mockOrgAcme01"
+	userID      = "mockUserDemo1"
+	userEmail   = "demo@example.com"
+	userName    = "Demo User"
+	accountName = "Acme Demo"
+)
+
+// created is when the fixtures were "created"; fixed so IDs and dates never change between runs
+var created = time.Date(2026, 1, 12, 9, 30, 0, 0, time.UTC)
+
+type fixtureProject struct {
+	id, name, description, status string
+	subdomainHost                 string
+	env                           map[string]string
+}
+
+type fixtureService struct {
+	id, projectID, hostname string
+	typeName, version       string
+	category                string
+	status                  string
+	mode                    string
+	port                    int
+	subdomain               bool
+	env                     []fixtureEnv
+	logs                    []string
+}
+
+type fixtureEnv struct {
+	key, value string
+	sensitive  bool
+}
+
+type fixtureAppVersion struct {
+	id, serviceID string
+	sequence      int
+	status        string
+	age           time.Duration
+}
+
+var projects = []fixtureProject{
+	{
+		id: "mockProjShop1", name: "shop", description: "Online shop with API, frontend, database and cache",
+		status: "ACTIVE", subdomainHost: "1a2b.prg1.zerops.app",
+		env: map[string]string{"APP_NAME": "Acme Shop", "LOG_LEVEL": "info"},
+	},
+	{
+		id: "mockProjBlog1", name: "blog", description: "Company blog",
+		status: "STOPPED", subdomainHost: "3c4d.prg1.zerops.app",
+		env: map[string]string{},
+	},
+}
+
+var services = []fixtureService{
+	{
+		id: "mockSvcApi001", projectID: "mockProjShop1", hostname: "api",
+		typeName: "nodejs", version: "22", category: "USER", status: "ACTIVE", mode: "NON_HA", port: 3000, subdomain: true,
+		env: []fixtureEnv{
+			{key: "NODE_ENV", value: "production"},
+			{key: "DATABASE_URL", value: "${db_connectionString}"},
+			{key: "REDIS_URL", value: "redis://cache:6379"},
+			{key: "JWT_SECRET", value: "mock-jwt-secret-change-me", sensitive: true},
+		},
+		logs: []string{
+			"info|GET /api/products 200 12ms",
+			"info|GET /api/products/42 200 8ms",
+			"info|POST /api/cart 201 23ms",
+			"warning|Slow query on orders (412ms)",
+			"info|GET /api/health 200 1ms",
+			"error|Error: connect ECONNREFUSED 10.0.0.12:6379 (cache reconnecting)",
+			"info|POST /api/checkout 200 187ms",
+			"info|GET /api/categories 200 5ms",
+		},
+	},
+	{
+		id: "mockSvcWeb001", projectID: "mockProjShop1", hostname: "web",
+		typeName: "static", version: "", category: "USER", status: "ACTIVE", mode: "NON_HA", port: 80, subdomain: true,
+		logs: []string{
+			"info|GET / 200",
+			"info|GET /assets/app.js 200",
+			"info|GET /favicon.ico 404",
+		},
+	},
+	{
+		id: "mockSvcDb0001", projectID: "mockProjShop1", hostname: "db",
+		typeName: "postgresql", version: "16", category: "STANDARD", status: "ACTIVE", mode: "HA", port: 5432,
+		env: []fixtureEnv{
+			{key: "hostname", value: "db"},
+			{key: "port", value: "5432"},
+			{key: "user", value: "db"},
+			{key: "password", value: "mock-db-password", sensitive: true},
+			{key: "connectionString", value: "postgresql://db:mock-db-password@db:5432/db"},
+		},
+		logs: []string{
+			"info|checkpoint starting: time",
+			"info|checkpoint complete: wrote 118 buffers",
+			"warning|duration: 412.337 ms statement: SELECT * FROM orders WHERE status = 'open'",
+		},
+	},
+	{
+		id: "mockSvcCache1", projectID: "mockProjShop1", hostname: "cache",
+		typeName: "valkey", version: "7.2", category: "STANDARD", status: "ACTIVE", mode: "NON_HA", port: 6379,
+		env: []fixtureEnv{
+			{key: "hostname", value: "cache"},
+			{key: "port", value: "6379"},
+			{key: "connectionString", value: "redis://cache:6379"},
+		},
+		logs: []string{
+			"info|DB saved on disk",
+			"info|Background saving started",
+		},
+	},
+	{
+		id: "mockSvcStore1", projectID: "mockProjShop1", hostname: "storage",
+		typeName: "object-storage", version: "", category: "STANDARD", status: "ACTIVE", mode: "NON_HA",
+		env: []fixtureEnv{
+			{key: "apiUrl", value: "https://storage.mock.zerops.invalid"},
+			{key: "bucketName", value: "mock-shop-storage"},
+			{key: "accessKeyId", value: "MOCKACCESSKEY"},
+			{key: "secretAccessKey", value: "mock-secret-access-key", sensitive: true},
+		},
+	},
+	{
+		id: "mockSvcBlog01", projectID: "mockProjBlog1", hostname: "app",
+		typeName: "php-apache", version: "8.3", category: "USER", status: "STOPPED", mode: "NON_HA", port: 80,
+		env: []fixtureEnv{
+			{key: "DB_HOST", value: "${db_hostname}"},
+		},
+	},
+	{
+		id: "mockSvcBlogDb", projectID: "mockProjBlog1", hostname: "db",
+		typeName: "mariadb", version: "10.6", category: "STANDARD", status: "STOPPED", mode: "NON_HA", port: 3306,
+		env: []fixtureEnv{
+			{key: "hostname", value: "db"},
+			{key: "password", value: "mock-blog-password", sensitive: true},
+		},
+	},
+}
+
+// appVersions of api: the active #3, a failed build #2 and the previous good #1
+var appVersions = []fixtureAppVersion{
+	{id: "mockAppVer003", serviceID: "mockSvcApi001", sequence: 3, status: "ACTIVE", age: 2 * time.Hour},
+	{id: "mockAppVer002", serviceID: "mockSvcApi001", sequence: 2, status: "BUILD_FAILED", age: 26 * time.Hour},
+	{id: "mockAppVer001", serviceID: "mockSvcApi001", sequence: 1, status: "BACKUP", age: 72 * time.Hour},
+	{id: "mockAppVerW01", serviceID: "mockSvcWeb001", sequence: 1, status: "ACTIVE", age: 72 * time.Hour},
+	{id: "mockAppVerB01", serviceID: "mockSvcBlog01", sequence: 1, status: "ACTIVE", age: 30 * 24 * time.Hour},
+}
+
+// fixtureProcess is a finished process in the project history
+type fixtureProcess struct {
+	id, projectID, serviceID, action, status string
+	appVersionID                             string
+	age                                      time.Duration
+}
+
+var processes = []fixtureProcess{
+	{id: "mockProcess01", projectID: "mockProjShop1", serviceID: "mockSvcApi001", action: "stack.build", status: "FINISHED", appVersionID: "mockAppVer003", age: 2 * time.Hour},
+	{id: "mockProcess02", projectID: "mockProjShop1", serviceID: "mockSvcApi001", action: "stack.build", status: "FAILED", appVersionID: "mockAppVer002", age: 26 * time.Hour},
+	{id: "mockProcess03", projectID: "mockProjShop1", serviceID: "mockSvcDb0001", action: "stack.create", status: "FINISHED", age: 72 * time.Hour},
+	{id: "mockProcess05", projectID: "mockProjShop1", serviceID: "mockSvcApi001", action: "stack.build", status: "FINISHED", appVersionID: "mockAppVer001", age: 72 * time.Hour},
+	{id: "mockProcess04", projectID: "mockProjBlog1", serviceID: "mockSvcBlog01", action: "stack.stop", status: "FINISHED", age: 5 * 24 * time.Hour},
+}
+
+// serviceTypes are the types offered by get_service_types, name to versions
+var serviceTypes = []struct {
+	id, name, category string
+	versions           []string
+}{
+	{id: "nodejs", name: "Node.js", category: "USER", versions: []string{"22", "20"}},
+	{id: "php-apache", name: "PHP + Apache", category: "USER", versions: []string{"8.3", "8.1"}},
+	{id: "python", name: "Python", category: "USER", versions: []string{"3.12"}},
+	{id: "go", name: "Go", category: "USER", versions: []string{"1"}},
+	{id: "static", name: "Static", category: "USER", versions: []string{""}},
+	{id: "postgresql", name: "PostgreSQL", category: "STANDARD", versions: []string{"16", "14"}},
+	{id: "mariadb", name: "MariaDB", category: "STANDARD", versions: []string{"10.6"}},
+	{id: "valkey", name: "Valkey", category: "STANDARD", versions: []string{"7.2"}},
+	{id: "object-storage", name: "Object Storage", category: "STANDARD", versions: []string{""}},
+}
+
+func findProject(id string) *fixtureProject {
+	for i := range projects {
+		if projects[i].id == id {
+			return &projects[i]
+		}
+	}
+	return nil
+}
+
+func findService(id string) *fixtureService {
+	for i := range services {
+		if services[i].id == id {
+			return &services[i]
+		}
+	}
+	return nil
+}
+
+func (s *fixtureService) typeVersion() string {
+	if s.version == "" {
+		return s.typeName
+	}
+	return s.typeName + "@" + s.version
+}
+
+func (s *fixtureService) typeVersionID() stringId.ServiceStackTypeVersionId {
+	return stringId.ServiceStackTypeVersionId(s.typeVersion())
+}
+
+func (s *fixtureService) typeInfo() output.ServiceStackInfoJsonObject {
+	return output.ServiceStackInfoJsonObject{
+		ServiceStackTypeName:        types.NewString(s.typeName),
+		ServiceStackTypeCategory:    enum.ServiceStackTypeCategoryEnum(s.category),
+		ServiceStackTypeVersionName: types.NewString(s.typeVersion()),
+	}
+}
+
+func (s *fixtureService) ports() []output.ServicePort {
+	if s.port == 0 {
+		return nil
+	}
+	return []output.ServicePort{{
+		Protocol:    enum.ServicePortProtocolEnum("tcp"),
+		Port:        types.NewInt(s.port),
+		HttpRouting: types.NewBoolNull(s.category == "USER"),
+		Scheme:      enum.ServicePortSchemeEnum("http"),
+	}}
+}
+
+func (s *fixtureService) activeVersion() *fixtureAppVersion {
+	for i := range appVersions {
+		if appVersions[i].serviceID == s.id && appVersions[i].status == "ACTIVE" {
+			return &appVersions[i]
+		}
+	}
+	return nil
+}
+
+func (p *fixtureProject) light() output.ProjectLight {
+	return output.ProjectLight{
+		Id:          uuid.ProjectId(p.id),
+		ClientId:    uuid.ClientId(clientID),
+		Name:        types.NewString(p.name),
+		Mode:        enum.ProjectModeEnum("LIGHT"),
+		Description: types.NewTextNull(p.description),
+		TagList:     types.StringArray{},
+		Status:      enum.ProjectStatusEnum(p.status),
+		Created:     types.NewDateTime(created),
+		LastUpdate:  types.NewDateTime(created),
+	}
+}
+
+func (p *fixtureProject) output() output.Project {
+	return output.Project{
+		Id:                  uuid.ProjectId(p.id),
+		ClientId:            uuid.ClientId(clientID),
+		Name:                types.NewString(p.name),
+		Mode:                enum.ProjectModeEnum("LIGHT"),
+		Description:         types.NewTextNull(p.description),
+		TagList:             types.StringArray{},
+		Status:              enum.ProjectStatusEnum(p.status),
+		Created:             types.NewDateTime(created),
+		LastUpdate:          types.NewDateTime(created),
+		PublicZone:          types.NewString("prg1"),
+		ZeropsSubdomainHost: types.NewStringNull(p.subdomainHost),
+		AutoStartup:         types.NewBool(true),
+	}
+}
+
+func (p *fixtureProject) es() output.EsProject {
+	keys := make([]string, 0, len(p.env))
+	for key := range p.env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	envs := make([]output.ProjectEnv, 0, len(p.env))
+	for _, key := range keys {
+		value := p.env[key]
+		envs = append(envs, output.ProjectEnv{
+			Id:         uuid.EnvId("mockEnv" + p.name + key),
+			Created:    types.NewDateTime(created),
+			LastUpdate: types.NewDateTime(created),
+			ClientId:   uuid.ClientId(clientID),
+			ProjectId:  uuid.ProjectId(p.id),
+			Key:        types.NewString(key),
+			Content:    types.NewText(value),
+			Type:       enum.EnvTypeEnum("PROJECT"),
+			Editable:   types.NewBool(true),
+		})
+	}
+	return output.EsProject{
+		Id:                  uuid.ProjectId(p.id),
+		ClientId:            uuid.ClientId(clientID),
+		Name:                types.NewString(p.name),
+		Mode:                enum.ProjectModeEnum("LIGHT"),
+		Description:         types.NewTextNull(p.description),
+		TagList:             types.StringArray{},
+		Status:              enum.ProjectStatusEnum(p.status),
+		Created:             types.NewDateTime(created),
+		LastUpdate:          types.NewDateTime(created),
+		PublicZone:          types.NewString("prg1"),
+		ZeropsSubdomainHost: types.NewStringNull(p.subdomainHost),
+		AutoStartup:         types.NewBool(true),
+		EnvList:             envs,
+	}
+}
+
+func (s *fixtureService) userDataLight() []output.UserDataLight {
+	items := make([]output.UserDataLight, 0, len(s.env))
+	for _, env := range s.env {
+		items = append(items, output.UserDataLight{
+			Id:             uuid.UserDataId("mockEnv" + s.id + env.key),
+			ClientId:       uuid.ClientId(clientID),
+			ProjectId:      uuid.ProjectId(s.projectID),
+			ServiceStackId: uuid.ServiceStackId(s.id),
+			Key:            types.NewString(env.key),
+			Content:        types.NewText(env.value),
+			Type:           enum.UserDataTypeEnum("BASIC"),
+			Created:        types.NewDateTime(created),
+			LastUpdate:     types.NewDateTime(created),
+			Sensitive:      types.NewBool(env.sensitive),
+		})
+	}
+	return items
+}
+
+func (s *fixtureService) output(now time.Time) output.ServiceStack {
+	project := findProject(s.projectID)
+	out := output.ServiceStack{
+		Id:                        uuid.ServiceStackId(s.id),
+		Status:                    enum.ServiceStackStatusEnum(s.status),
+		Name:                      types.NewString(s.hostname),
+		ServiceStackTypeInfo:      s.typeInfo(),
+		ServiceStackTypeId:        stringId.ServiceStackTypeId(s.typeName),
+		ServiceStackTypeVersionId: s.typeVersionID(),
+		StartOnProjectStart:       types.NewBool(true),
+		Ports:                     s.ports(),
+		Created:                   types.NewDateTime(created),
+		LastUpdate:                types.NewDateTime(created),
+		Mode:                      enum.ServiceStackModeEnum(s.mode),
+		SubdomainAccess:           types.NewBool(s.subdomain),
+		ProjectId:                 uuid.ProjectId(s.projectID),
+		Project:                   project.light(),
+	}
+	for _, env := range s.env {
+		out.UserData = append(out.UserData, output.UserData{
+			Id:               uuid.UserDataId("mockEnv" + s.id + env.key),
+			Created:          types.NewDateTime(created),
+			LastUpdate:       types.NewDateTime(created),
+			ClientId:         uuid.ClientId(clientID),
+			ProjectId:        uuid.ProjectId(s.projectID),
+			ServiceStackId:   uuid.ServiceStackId(s.id),
+			Key:              types.NewString(env.key),
+			Content:          types.NewText(env.value),
+			Type:             enum.UserDataTypeEnum("BASIC"),
+			ServiceStackName: types.NewString(s.hostname),
+		})
+	}
+	if version := s.activeVersion(); version != nil {
+		active := version.getOutput(now)
+		out.ActiveAppVersion = &active
+	}
+	return out
+}
+
+func (s *fixtureService) es() output.EsServiceStack {
+	mode := enum.ServiceStackModeEnum(s.mode)
+	out := output.EsServiceStack{
+		Id:                        uuid.ServiceStackId(s.id),
+		ClientId:                  uuid.ClientId(clientID),
+		ProjectId:                 uuid.ProjectId(s.projectID),
+		InstanceId:                uuid.InstanceId("mockInstance1"),
+		ServiceStackTypeId:        stringId.ServiceStackTypeId(s.typeName),
+		ServiceStackTypeVersionId: s.typeVersionID(),
+		ServiceStackTypeInfo:      s.typeInfo(),
+		Status:                    enum.ServiceStackStatusEnum(s.status),
+		Name:                      types.NewString(s.hostname),
+		Created:                   types.NewDateTime(created),
+		LastUpdate:                types.NewDateTime(created),
+		Ports:                     s.ports(),
+		Mode:                      &mode,
+		SubdomainAccess:           types.NewBool(s.subdomain),
+		Project:                   findProject(s.projectID).light(),
+		UserData:                  s.userDataLight(),
+		StartOnProjectStart:       types.NewBool(true),
+	}
+	if version := s.activeVersion(); version != nil {
+		out.ActiveAppVersion = &output.AppVersionLight{
+			Id:         uuid.AppVersionId(version.id),
+			Status:     enum.AppVersionStatusEnum(version.status),
+			Created:    types.NewDateTime(created),
+			LastUpdate: types.NewDateTime(created),
+		}
+	}
+	return out
+}
+
+// exportYaml is the service export as the API returns it: an import YAML with this one service
+func (s *fixtureService) exportYaml() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "services:\n  - hostname: %s\n    type: %s\n", s.hostname, s.typeVersion())
+	if s.mode == "HA" || s.category == "STANDARD" {
+		fmt.Fprintf(&b, "    mode: %s\n", s.mode)
+	}
+	if s.subdomain {
+		b.WriteString("    enableSubdomainAccess: true\n")
+	}
+	if s.category == "USER" && len(s.env) > 0 {
+		b.WriteString("    envSecrets:\n")
+		for _, env := range s.env {
+			fmt.Fprintf(&b, "      %s: %q\n", env.key, env.value)
+		}
+	}
+	return b.String()
+}
+
+func (v *fixtureAppVersion) build(now time.Time) *output.AppVersionBuild {
+	start := now.Add(-v.age)
+	build := &output.AppVersionBuild{
+		ServiceStackId:   uuid.NewServiceStackIdNull(uuid.ServiceStackId("mockBuild" + v.id[len(v.id)-3:])),
+		ServiceStackName: types.NewStringNull("build-" + findService(v.serviceID).hostname),
+		PipelineStart:    types.NewDateTimeNull(start),
+	}
+	if v.status == "BUILD_FAILED" {
+		build.PipelineFailed = types.NewDateTimeNull(start.Add(48 * time.Second))
+	} else {
+		build.PipelineFinish = types.NewDateTimeNull(start.Add(94 * time.Second))
+	}
+	return build
+}
+
+func (v *fixtureAppVersion) es(now time.Time) output.EsAppVersion {
+	return output.EsAppVersion{
+		Id:             uuid.AppVersionId(v.id),
+		ClientId:       uuid.ClientId(clientID),
+		ProjectId:      uuid.ProjectId(findService(v.serviceID).projectID),
+		ServiceStackId: uuid.ServiceStackId(v.serviceID),
+		Build:          v.build(now),
+		Source:         enum.AppVersionSourceEnum("CLI"),
+		Sequence:       types.NewInt(v.sequence),
+		Status:         enum.AppVersionStatusEnum(v.status),
+		Created:        types.NewDateTime(now.Add(-v.age)),
+		LastUpdate:     types.NewDateTime(now.Add(-v.age)),
+	}
+}
+
+func (v *fixtureAppVersion) getOutput(now time.Time) output.GetAppVersion {
+	return output.GetAppVersion{
+		Id:             uuid.AppVersionId(v.id),
+		ClientId:       uuid.ClientId(clientID),
+		ProjectId:      uuid.ProjectId(findService(v.serviceID).projectID),
+		ServiceStackId: uuid.ServiceStackId(v.serviceID),
+		Build:          v.build(now),
+		Source:         enum.AppVersionSourceEnum("CLI"),
+		Sequence:       types.NewInt(v.sequence),
+		Status:         enum.AppVersionStatusEnum(v.status),
+		Created:        types.NewDateTime(now.Add(-v.age)),
+		LastUpdate:     types.NewDateTime(now.Add(-v.age)),
+	}
+}
+
+// jsonObject is the app version as embedded in its build process, with the zerops.yml it was built with
+func (v *fixtureAppVersion) jsonObject(now time.Time) *output.AppVersionJsonObject {
+	service := findService(v.serviceID)
+	status := enum.AppVersionStatusEnum(v.status)
+	return &output.AppVersionJsonObject{
+		Id:             uuid.AppVersionId(v.id),
+		ServiceStackId: uuid.NewServiceStackIdNull(uuid.ServiceStackId(v.serviceID)),
+		ProjectId:      uuid.NewProjectIdNull(uuid.ProjectId(service.projectID)),
+		Status:         &status,
+		Source:         enum.AppVersionSourceEnum("CLI"),
+		Sequence:       types.NewInt(v.sequence),
+		Created:        types.NewDateTimeNull(now.Add(-v.age)),
+		Build:          v.build(now),
+		ConfigContent:  types.NewTextNull(zeropsYml(service)),
+	}
+}
+
+// zeropsYml is the zerops.yml runtime services are deployed with
+func zeropsYml(s *fixtureService) string {
+	return fmt.Sprintf(`zerops:
+  - setup: %[1]s
+    build:
+      base: %[2]s
+      buildCommands:
+        - npm ci
+        - npm run build
+      deployFiles:
+        - dist
+        - node_modules
+        - package.json
+    deploy:
+      readinessCheck:
+        httpGet:
+          port: %[3]d
+          path: /api/health
+    run:
+      base: %[2]s
+      ports:
+        - port: %[3]d
+          httpSupport: true
+      envVariables:
+        DATABASE_URL: ${db_connectionString}
+      start: node dist/main.js
+`, s.hostname, s.typeVersion(), s.port)
+}
+
+// process builds a process; finished processes report their duration
+func process(id, projectID, serviceID, action, status string, started time.Time) output.Process {
+	project := findProject(projectID)
+	out := output.Process{
+		Id:            uuid.ProcessId(id),
+		ClientId:      uuid.ClientId(clientID),
+		ProjectId:     uuid.ProjectId(projectID),
+		Status:        enum.ProcessStatusEnum(status),
+		Sequence:      types.NewInt(1),
+		CreatedByUser: user(),
+		ActionName:    types.NewString(action),
+		Created:       types.NewDateTime(started),
+		LastUpdate:    types.NewDateTime(started),
+		Started:       types.NewDateTimeNull(started),
+	}
+	if project != nil {
+		out.Project = output.ProjectLightJsonObject{
+			Id:          uuid.ProjectId(project.id),
+			ClientId:    uuid.ClientId(clientID),
+			Name:        types.NewString(project.name),
+			Description: types.NewTextNull(project.description),
+			TagList:     types.StringArray{},
+			Status:      enum.ProjectStatusEnum(project.status),
+			Created:     types.NewDateTime(created),
+			LastUpdate:  types.NewDateTime(created),
+		}
+	}
+	if service := findService(serviceID); service != nil {
+		out.ServiceStackId = uuid.NewServiceStackIdNull(uuid.ServiceStackId(service.id))
+		out.ServiceStacks = []output.ServiceStackLightJsonObject{{
+			Id:                        uuid.ServiceStackId(service.id),
+			Created:                   types.NewDateTime(created),
+			LastUpdate:                types.NewDateTime(created),
+			ProjectId:                 uuid.ProjectId(service.projectID),
+			ServiceStackTypeId:        stringId.ServiceStackTypeId(service.typeName),
+			ServiceStackTypeVersionId: service.typeVersionID(),
+			Name:                      types.NewString(service.hostname),
+			ServiceStackTypeInfo:      service.typeInfo(),
+			Ports:                     service.ports(),
+		}}
+	}
+	if status == "FINISHED" || status == "FAILED" {
+		out.Finished = types.NewDateTimeNull(started.Add(40 * time.Second))
+		out.LastUpdate = types.NewDateTime(started.Add(40 * time.Second))
+	}
+	return out
+}
+
+func (p *fixtureProcess) es(now time.Time) output.EsProcess {
+	out := process(p.id, p.projectID, p.serviceID, p.action, p.status, now.Add(-p.age))
+	for i := range appVersions {
+		if appVersions[i].id == p.appVersionID {
+			out.AppVersion = appVersions[i].jsonObject(now)
+		}
+	}
+	return output.EsProcess{
+		Id:             out.Id,
+		ClientId:       out.ClientId,
+		ProjectId:      out.ProjectId,
+		ServiceStackId: out.ServiceStackId,
+		Project:        out.Project,
+		ServiceStacks:  output.EsProcessServiceStacks(out.ServiceStacks),
+		Status:         out.Status,
+		Sequence:       out.Sequence,
+		CreatedByUser:  out.CreatedByUser,
+		ActionName:     out.ActionName,
+		Created:        out.Created,
+		LastUpdate:     out.LastUpdate,
+		Started:        out.Started,
+		Finished:       out.Finished,
+		AppVersion:     out.AppVersion,
+	}
+}
+
+func user() output.UserJsonObject {
+	return output.UserJsonObject{
+		Type:      enum.UserJsonObjectTypeEnum("USER"),
+		Id:        uuid.NewUserIdNull(uuid.UserId(userID)),
+		Email:     types.NewEmailNull(userEmail),
+		FirstName: types.NewStringNull("Demo"),
+		FullName:  types.NewStringNull(userName),
+	}
+}
+
+func userInfo() output.UserAuthorize {
+	return output.UserAuthorize{
+		Id:         uuid.UserId(userID),
+		Email:      types.NewEmail(userEmail),
+		FullName:   types.NewString(userName),
+		FirstName:  types.NewString("Demo"),
+		LastName:   types.NewEmptyString("User"),
+		Language:   output.Language{Id: stringId.LanguageId("en"), Name: types.NewString("English")},
+		Created:    types.NewDateTime(created),
+		LastUpdate: types.NewDateTime(created),
+		Status:     enum.UserStatusEnum("ACTIVE"),
+		ClientUserList: []output.ClientUserExtra{{
+			Id:       uuid.ClientUserId("mockClientUsr"),
+			ClientId: uuid.ClientId(clientID),
+			UserId:   uuid.UserId(userID),
+			Status:   enum.ClientUserStatusEnum("ACTIVE"),
+			RoleCode: enum.ClientUserRoleCodeEnum("OWNER"),
+			Client: output.Client{
+				Id:          uuid.ClientId(clientID),
+				AccountName: types.NewString(accountName),
+			},
+			User: output.UserLight{
+				Id:        uuid.UserId(userID),
+				Email:     types.NewEmail(userEmail),
+				FullName:  types.NewString(userName),
+				FirstName: types.NewString("Demo"),
+				LastName:  types.NewEmptyString("User"),
+			},
+		}},
+		PasswordIsSet: types.NewBool(true),
+	}
+}
+
+func serviceTypeList() []output.EsServiceStackType {
+	items := make([]output.EsServiceStackType, 0, len(serviceTypes))
+	for _, st := range serviceTypes {
+		item := output.EsServiceStackType{
+			Id:          stringId.ServiceStackTypeId(st.id),
+			Name:        types.NewString(st.id),
+			Description: types.NewText(st.name),
+			Category:    enum.ServiceStackTypeCategoryEnum(st.category),
+			Created:     types.NewDateTime(created),
+			LastUpdate:  types.NewDateTime(created),
+		}
+		for i, version := range st.versions {
+			name := st.id
+			if version != "" {
+				name += "@" + version
+			}
+			versionName := version
+			if versionName == "" {
+				versionName = "latest"
+			}
+			light := output.ServiceStackTypeVersionLight{
+				Id:                 stringId.ServiceStackTypeVersionId(name),
+				ServiceStackTypeId: stringId.ServiceStackTypeId(st.id),
+				Name:               types.NewString(versionName),
+				UpdateUrl:          types.NewText(""),
+				Status:             enum.ServiceStackTypeVersionStatusEnum("ACTIVE"),
+				ReleaseDate:        types.NewDateTime(created),
+			}
+			if i == 0 {
+				item.DefaultServiceStackVersionId = stringId.NewServiceStackTypeVersionIdNull(light.Id)
+				def := light
+				item.DefaultServiceStackVersion = &def
+			}
+			item.ServiceStackTypeVersionList = append(item.ServiceStackTypeVersionList, light)
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+func routingList() []output.EsPublicHttpRouting {
+	var items []output.EsPublicHttpRouting
+	for i := range services {
+		service := &services[i]
+		if !service.subdomain {
+			continue
+		}
+		project := findProject(service.projectID)
+		domain := fmt.Sprintf("%s-%s-%d.%s", service.hostname, project.subdomainHost[:4], service.port, strings.SplitN(project.subdomainHost, ".", 2)[1])
+		items = append(items, output.EsPublicHttpRouting{
+			Id:         uuid.PublicHttpRoutingId("mockRoute" + service.hostname),
+			ClientId:   uuid.ClientId(clientID),
+			ProjectId:  uuid.ProjectId(service.projectID),
+			SslEnabled: types.NewBool(true),
+			Domains: []output.PublicHttpRoutingDomain{{
+				DomainName:     types.NewString(domain),
+				DnsCheckStatus: enum.PublicHttpRoutingDomainDnsCheckStatusEnum("ACTIVE"),
+				SslStatus:      enum.PublicHttpRoutingDomainSslStatusEnum("ACTIVE"),
+				CdnStatus:      enum.PublicHttpRoutingDomainCdnStatusEnum("INACTIVE"),
+			}},
+			Locations: []output.PublicHttpRoutingLocation{{
+				Path:           types.NewString("/"),
+				Port:           types.NewInt(service.port),
+				ServiceStackId: uuid.ServiceStackId(service.id),
+			}},
+			Created:    types.NewDateTime(created),
+			LastUpdate: types.NewDateTime(created),
+			IsSynced:   types.NewBool(true),
+		})
+	}
+	return items
+}
+
+// serviceLogs returns the log entries of a service, newest first. Messages repeat the
+// fixture lines in order; timestamps step back from now.
+func serviceLogs(service *fixtureService, now time.Time, count int) []map[string]interface{} {
+	entries := make([]map[string]interface{}, 0, count)
+	if len(service.logs) == 0 {
+		return entries
+	}
+	for i := 0; i < count; i++ {
+		seq := count - i
+		line := service.logs[seq%len(service.logs)]
+		severityLabel, message, _ := strings.Cut(line, "|")
+		severity := map[string]int{"error": 3, "warning": 4, "info": 6}[severityLabel]
+		timestamp := now.Add(-time.Duration(i) * 45 * time.Second).UTC()
+		entries = append(entries, map[string]interface{}{
+			"timestamp":     timestamp.Format(time.RFC3339Nano),
+			"version":       1,
+			"hostname":      service.hostname + "1",
+			"content":       message,
+			"client":        clientID,
+			"facility":      16,
+			"facilityLabel": "local0",
+			"id":            fmt.Sprintf("%s-%06d", service.id, seq),
+			"msgId":         "",
+			"priority":      16*8 + severity,
+			"procId":        "-",
+			"severity":      severity,
+			"severityLabel": severityLabel,
+			"tag":           service.hostname,
+			"appName":       service.hostname,
+			"message":       message,
+		})
+	}
+	return entries
+}
@@ -0,0 +1,491 @@
+// Package mock answers Zerops API requests with canned, deterministic data so every tool can
+// be exercised offline without an API key, for demos and client development.
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/zeropsio/zerops-go/dto/output"
+	"github.com/zeropsio/zerops-go/types"
+	"github.com/zeropsio/zerops-go/types/enum"
+	"github.com/zeropsio/zerops-go/types/uuid"
+)
+
+// APIKey is the API key clients use in mock mode; any key is accepted
+const APIKey = "mock"
+
+// logHost serves the project log backend in mock mode
+const logHost = "logs.mock.zerops.invalid"
+
+// Enable routes all HTTP traffic of http.DefaultTransport to the mock API. Call it before
+// any API client is created. Service URLs (*.zerops.app) answer 200; other hosts are unreachable.
+func Enable() {
+	http.DefaultTransport = &transport{}
+}
+
+type transport struct {
+	processes atomic.Int64
+}
+
+// route is one mocked API endpoint; path groups capture IDs
+type route struct {
+	method  string
+	pattern *regexp.Regexp
+	handle  func(t *transport, req *http.Request, ids []string) (int, interface{})
+}
+
+var routes []route
+
+func handle(method, pattern string, fn func(t *transport, req *http.Request, ids []string) (int, interface{})) {
+	routes = append(routes, route{method: method, pattern: regexp.MustCompile("^/api/rest/public/" + pattern + "$"), handle: fn})
+}
+
+func init() {
+	handle("GET", "user/info", func(t *transport, req *http.Request, ids []string) (int, interface{}) {
+		return http.StatusOK, userInfo()
+	})
+
+	handle("POST", "project/search", func(t *transport, req *http.Request, ids []string) (int, interface{}) {
+		items := make([]interface{}, 0, len(projects))
+		for i := range projects {
+			items = append(items, projects[i].es())
+		}
+		return search(req, items)
+	})
+	handle("GET", "project/([^/]+)", func(t *transport, req *http.Request, ids []string) (int, interface{}) {
+		project := findProject(ids[0])
+		if project == nil {
+			return notFound("projectNotFound", "Project not found")
+		}
+		return http.StatusOK, project.output()
+	})
+	handle("GET", "project/([^/]+)/log", func(t *transport, req *http.Request, ids []string) (int, interface{}) {
+		if findProject(ids[0]) == nil {
+			return notFound("projectNotFound", "Project not found")
+		}
+		return http.StatusOK, output.ProjectLog{
+			AccessToken: uuid.ProjectLogAccessToken("mockLogToken"),
+			Expiration:  types.NewDateTime(time.Now().Add(time.Hour)),
+			Url:         types.NewString("GET " + logHost + "/api/logs?projectId=" + ids[0]),
+			UrlInfo:     types.NewString(""),
+		}
+	})
+	handle("PUT", "project/([^/]+)/(start|stop)", func(t *transport, req *http.Request, ids []string) (int, interface{}) {
+		if findProject(ids[0]) == nil {
+			return notFound("projectNotFound", "Project not found")
+		}
+		return http.StatusOK, t.newProcess(ids[0], "", "project."+ids[1])
+	})
+
+	handle("POST", "service-stack/search", func(t *transport, req *http.Request, ids []string) (int, interface{}) {
+		items := make([]interface{}, 0, len(services))
+		for i := range services {
+			items = append(items, services[i].es())
+		}
+		return search(req, items)
+	})
+	handle("GET", "service-stack/([^/]+)", func(t *transport, req *http.Request, ids []string) (int, interface{}) {
+		service := findService(ids[0])
+		if service == nil {
+			return notFound("serviceStackNotFound", "Service stack not found")
+		}
+		return http.StatusOK, service.output(time.Now())
+	})
+	handle("GET", "service-stack/([^/]+)/env", func(t *transport, req *http.Request, ids []string) (int, interface{}) {
+		service := findService(ids[0])
+		if service == nil {
+			return notFound("serviceStackNotFound", "Service stack not found")
+		}
+		items := make([]output.ServiceStackEnv, 0, len(service.env))
+		for _, env := range service.userDataLight() {
+			items = append(items, output.ServiceStackEnv{
+				Id:             env.Id,
+				ClientId:       env.ClientId,
+				ProjectId:      env.ProjectId,
+				ServiceStackId: env.ServiceStackId,
+				Key:            env.Key,
+				Content:        env.Content,
+				Type:           env.Type,
+				Sensitive:      env.Sensitive,
+				Created:        env.Created,
+				LastUpdate:     env.LastUpdate,
+			})
+		}
+		return http.StatusOK, output.ServiceStackEnvList{Items: items}
+	})
+	handle("POST", "user-data/search", func(t *transport, req *http.Request, ids []string) (int, interface{}) {
+		var items []interface{}
+		for i := range services {
+			for _, env := range services[i].userDataLight() {
+				items = append(items, output.EsUserData{
+					Id:               env.Id,
+					ClientId:         env.ClientId,
+					ProjectId:        env.ProjectId,
+					ServiceStackId:   env.ServiceStackId,
+					Key:              env.Key,
+					Content:          env.Content,
+					Type:             env.Type,
+					Created:          env.Created,
+					LastUpdate:       env.LastUpdate,
+					ServiceStackName: types.NewString(services[i].hostname),
+				})
+			}
+		}
+		return search(req, items)
+	})
+	handle("GET", "service-stack/([^/]+)/export", func(t *transport, req *http.Request, ids []string) (int, interface{}) {
+		service := findService(ids[0])
+		if service == nil {
+			return notFound("serviceStackNotFound", "Service stack not found")
+		}
+		return http.StatusOK, output.ProjectExport{Yaml: types.NewText(service.exportYaml())}
+	})
+	handle("PUT", "service-stack/([^/]+)/autoscaling", func(t *transport, req *http.Request, ids []string) (int, interface{}) {
+		service := findService(ids[0])
+		if service == nil {
+			return notFound("serviceStackNotFound", "Service stack not found")
+		}
+		process := t.newProcess(service.projectID, service.id, "stack.autoscaling")
+		return http.StatusOK, output.ProcessNil{Process: &process}
+	})
+	handle("PUT", "service-stack/([^/]+)/([a-z-]+)", func(t *transport, req *http.Request, ids []string) (int, interface{}) {
+		service := findService(ids[0])
+		if service == nil {
+			return notFound("serviceStackNotFound", "Service stack not found")
+		}
+		return http.StatusOK, t.newProcess(service.projectID, service.id, "stack."+ids[1])
+	})
+	handle("POST", "service-stack/import", func(t *transport, req *http.Request, ids []string) (int, interface{}) {
+		var request struct {
+			ProjectId string `json:"projectId"`
+			Yaml      string `json:"yaml"`
+		}
+		if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+			return badRequest("invalidJson", err.Error())
+		}
+		project := findProject(request.ProjectId)
+		if project == nil {
+			return notFound("projectNotFound", "Project not found")
+		}
+		result := output.ProjectImport{ProjectId: uuid.ProjectId(project.id), ProjectName: types.NewString(project.name)}
+		for i, hostname := range importHostnames(request.Yaml) {
+			id := fmt.Sprintf("mockNewSvc%03d", i+1)
+			result.ServiceStacks = append(result.ServiceStacks, output.ProjectImportServiceStack{
+				Id:        uuid.ServiceStackId(id),
+				Name:      types.NewString(hostname),
+				Processes: []output.Process{t.newProcess(project.id, "", "stack.create")},
+			})
+		}
+		if len(result.ServiceStacks) == 0 {
+			return badRequest("yamlValidationError", "the import YAML defines no services")
+		}
+		return http.StatusOK, result
+	})
+
+	handle("POST", "process/search", func(t *transport, req *http.Request, ids []string) (int, interface{}) {
+		now := time.Now()
+		items := make([]interface{}, 0, len(processes))
+		for i := range processes {
+			items = append(items, processes[i].es(now))
+		}
+		return search(req, items)
+	})
+	handle("GET", "process/([^/]+)", func(t *transport, req *http.Request, ids []string) (int, interface{}) {
+		now := time.Now()
+		for i := range processes {
+			if processes[i].id == ids[0] {
+				p := processes[i]
+				return http.StatusOK, process(p.id, p.projectID, p.serviceID, p.action, p.status, now.Add(-p.age))
+			}
+		}
+		if !strings.HasPrefix(ids[0], "mockNewProc") {
+			return notFound("processNotFound", "Process not found")
+		}
+		// Processes started in this session finish immediately
+		return http.StatusOK, process(ids[0], projects[0].id, "", "stack.action", "FINISHED", now.Add(-time.Minute))
+	})
+
+	handle("POST", "app-version/search", func(t *transport, req *http.Request, ids []string) (int, interface{}) {
+		now := time.Now()
+		items := make([]interface{}, 0, len(appVersions))
+		for i := range appVersions {
+			items = append(items, appVersions[i].es(now))
+		}
+		return search(req, items)
+	})
+	handle("GET", "app-version/([^/]+)", func(t *transport, req *http.Request, ids []string) (int, interface{}) {
+		for i := range appVersions {
+			if appVersions[i].id == ids[0] {
+				return http.StatusOK, appVersions[i].getOutput(time.Now())
+			}
+		}
+		return notFound("appVersionNotFound", "App version not found")
+	})
+	handle("POST", "app-version", func(t *transport, req *http.Request, ids []string) (int, interface{}) {
+		var request struct {
+			ServiceStackId string `json:"serviceStackId"`
+		}
+		json.NewDecoder(req.Body).Decode(&request)
+		service := findService(request.ServiceStackId)
+		if service == nil {
+			return notFound("serviceStackNotFound", "Service stack not found")
+		}
+		now := time.Now()
+		return http.StatusOK, output.PostAppVersion{
+			Id:             uuid.AppVersionId("mockAppVerNew"),
+			ClientId:       uuid.ClientId(clientID),
+			ProjectId:      uuid.ProjectId(service.projectID),
+			ServiceStackId: uuid.ServiceStackId(service.id),
+			Sequence:       types.NewInt(4),
+			Status:         enum.AppVersionStatusEnum("UPLOADING"),
+			Created:        types.NewDateTime(now),
+			LastUpdate:     types.NewDateTime(now),
+			UploadUrl:      types.NewString("https://upload.mock.zerops.invalid/mockAppVerNew"),
+		}
+	})
+	handle("PUT", "app-version/([^/]+)/upload", func(t *transport, req *http.Request, ids []string) (int, interface{}) {
+		io.Copy(io.Discard, req.Body)
+		return http.StatusOK, output.Success{Success: types.NewBool(true)}
+	})
+	handle("PUT", "app-version/([^/]+)/(deploy|build-and-deploy)", func(t *transport, req *http.Request, ids []string) (int, interface{}) {
+		serviceID := "mockSvcApi001"
+		for _, version := range appVersions {
+			if version.id == ids[0] {
+				serviceID = version.serviceID
+			}
+		}
+		service := findService(serviceID)
+		return http.StatusOK, t.newProcess(service.projectID, service.id, "stack."+strings.ReplaceAll(ids[1], "-and-", "_"))
+	})
+
+	handle("POST", "service-stack-type/search", func(t *transport, req *http.Request, ids []string) (int, interface{}) {
+		items := make([]interface{}, 0, len(serviceTypes))
+		for _, item := range serviceTypeList() {
+			items = append(items, item)
+		}
+		return search(req, items)
+	})
+	handle("POST", "public-http-routing/search", func(t *transport, req *http.Request, ids []string) (int, interface{}) {
+		list := routingList()
+		items := make([]interface{}, 0, len(list))
+		for _, item := range list {
+			items = append(items, item)
+		}
+		return search(req, items)
+	})
+
+	envChange := func(t *transport, req *http.Request, ids []string) (int, interface{}) {
+		io.Copy(io.Discard, req.Body)
+		return http.StatusOK, t.newProcess(projects[0].id, "", "stack.userDataChange")
+	}
+	handle("POST", "project-env", envChange)
+	handle("PUT", "project-env/([^/]+)", envChange)
+	handle("DELETE", "project-env/([^/]+)", envChange)
+	handle("POST", "user-data", envChange)
+	handle("PUT", "user-data/([^/]+)", envChange)
+	handle("DELETE", "user-data/([^/]+)", envChange)
+}
+
+// newProcess starts a process that finishes immediately when polled
+func (t *transport) newProcess(projectID, serviceID, action string) output.Process {
+	id := fmt.Sprintf("mockNewProc%03d", t.processes.Add(1))
+	return process(id, projectID, serviceID, action, "PENDING", time.Now())
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch {
+	case strings.HasPrefix(req.URL.Path, "/api/rest/public/"):
+		status, body := t.serveAPI(req)
+		return jsonResponse(req, status, body)
+	case req.URL.Host == logHost:
+		return t.serveLogs(req)
+	case strings.HasSuffix(req.URL.Hostname(), ".zerops.app"):
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Status:     "200 OK",
+			Proto:      "HTTP/1.1", ProtoMajor: 1, ProtoMinor: 1,
+			Header:  http.Header{"Content-Type": []string{"text/plain"}},
+			Body:    io.NopCloser(strings.NewReader("OK")),
+			Request: req,
+		}, nil
+	}
+	return nil, fmt.Errorf("mock mode: no network access to %s", req.URL.Host)
+}
+
+func (t *transport) serveAPI(req *http.Request) (int, interface{}) {
+	path := req.URL.Path
+	for _, r := range routes {
+		if r.method != req.Method {
+			continue
+		}
+		if match := r.pattern.FindStringSubmatch(path); match != nil {
+			return r.handle(t, req, match[1:])
+		}
+	}
+	return notFound("notImplementedInMock", fmt.Sprintf("%s %s is not available in mock mode", req.Method, strings.TrimPrefix(path, "/api/rest/public/")))
+}
+
+// serveLogs answers the log backend with the fixture logs of the requested service
+func (t *transport) serveLogs(req *http.Request) (*http.Response, error) {
+	query := req.URL.Query()
+	service := findService(query.Get("serviceStackId"))
+	entries := []map[string]interface{}{}
+	if service != nil {
+		limit, _ := strconv.Atoi(query.Get("limit"))
+		if limit <= 0 {
+			limit = 100
+		}
+		minimum, err := strconv.Atoi(query.Get("minimumSeverity"))
+		if err != nil {
+			minimum = 7
+		}
+		till := query.Get("till")
+		for _, entry := range serviceLogs(service, time.Now(), 500) {
+			if till != "" && entry["id"].(string) >= till {
+				continue
+			}
+			if entry["severity"].(int) > minimum {
+				continue
+			}
+			entries = append(entries, entry)
+			if len(entries) == limit {
+				break
+			}
+		}
+	}
+	return jsonResponse(req, http.StatusOK, map[string]interface{}{"items": entries})
+}
+
+// search applies the filter, sort and paging of an EsFilter body to items
+func search(req *http.Request, items []interface{}) (int, interface{}) {
+	var filter struct {
+		Search []struct {
+			Name     string      `json:"name"`
+			Operator string      `json:"operator"`
+			Value    interface{} `json:"value"`
+		} `json:"search"`
+		Sort []struct {
+			Name      string `json:"name"`
+			Ascending *bool  `json:"ascending"`
+		} `json:"sort"`
+		Limit  *int `json:"limit"`
+		Offset *int `json:"offset"`
+	}
+	if err := json.NewDecoder(req.Body).Decode(&filter); err != nil && err != io.EOF {
+		return badRequest("invalidJson", err.Error())
+	}
+
+	// Compare on the JSON form, so filters see the same field names the API does
+	var matched []map[string]interface{}
+	for _, item := range items {
+		data, _ := json.Marshal(item)
+		var fields map[string]interface{}
+		json.Unmarshal(data, &fields)
+		ok := true
+		for _, condition := range filter.Search {
+			if fmt.Sprint(fields[condition.Name]) != fmt.Sprint(condition.Value) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			matched = append(matched, fields)
+		}
+	}
+	for i := len(filter.Sort) - 1; i >= 0; i-- {
+		field := filter.Sort[i].Name
+		ascending := filter.Sort[i].Ascending == nil || *filter.Sort[i].Ascending
+		sort.SliceStable(matched, func(a, b int) bool {
+			less := compareFields(matched[a][field], matched[b][field])
+			if ascending {
+				return less < 0
+			}
+			return less > 0
+		})
+	}
+
+	total := len(matched)
+	offset, limit := 0, total
+	if filter.Offset != nil {
+		offset = min(*filter.Offset, total)
+	}
+	if filter.Limit != nil {
+		limit = *filter.Limit
+	}
+	matched = matched[offset:min(offset+limit, total)]
+	if matched == nil {
+		matched = []map[string]interface{}{}
+	}
+	return http.StatusOK, map[string]interface{}{
+		"items":     matched,
+		"totalHits": total,
+		"offset":    offset,
+		"limit":     limit,
+	}
+}
+
+func compareFields(a, b interface{}) int {
+	if x, ok := a.(float64); ok {
+		if y, ok := b.(float64); ok {
+			switch {
+			case x < y:
+				return -1
+			case x > y:
+				return 1
+			}
+			return 0
+		}
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+// importHostnames lists the hostnames of an import YAML without validating it
+func importHostnames(yaml string) []string {
+	var hostnames []string
+	for _, line := range strings.Split(yaml, "\n") {
+		line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "- "))
+		if value, ok := strings.CutPrefix(line, "hostname:"); ok {
+			hostnames = append(hostnames, strings.Trim(strings.TrimSpace(value), `"'`))
+		}
+	}
+	return hostnames
+}
+
+func notFound(code, message string) (int, interface{}) {
+	return http.StatusNotFound, apiError(code, message)
+}
+
+func badRequest(code, message string) (int, interface{}) {
+	return http.StatusBadRequest, apiError(code, message)
+}
+
+func apiError(code, message string) map[string]interface{} {
+	return map[string]interface{}{"error": map[string]interface{}{"code": code, "message": message}}
+}
+
+func jsonResponse(req *http.Request, status int, body interface{}) (*http.Response, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("mock mode: %w", err)
+	}
+	return &http.Response{
+		StatusCode:    status,
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(strings.NewReader(string(data))),
+		ContentLength: int64(len(data)),
+		Request:       req,
+	}, nil
+}
@@ -0,0 +1,391 @@
+// Package replay records the tool calls of a session together with the Zerops API traffic they
+// caused, and replays a recording against the captured responses instead of the live API.
+// A recording reproduces a bug report offline and doubles as a regression test.
+package replay
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"reflect"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/sdk"
+)
+
+// Entry kinds of a recording
+const (
+	kindCall   = "call"
+	kindResult = "result"
+	kindHTTP   = "http"
+)
+
+// entry is one line of a recording file
+type entry struct {
+	Seq  int       `json:"seq"`
+	Kind string    `json:"kind"`
+	Time time.Time `json:"time"`
+
+	// call and result
+	Call      int                    `json:"call,omitempty"`
+	Tool      string                 `json:"tool,omitempty"`
+	Arguments map[string]interface{} `json:"arguments,omitempty"`
+	Result    interface{}            `json:"result,omitempty"`
+	Error     string                 `json:"error,omitempty"`
+	ErrorCode string                 `json:"error_code,omitempty"`
+
+	// http
+	Method      string `json:"method,omitempty"`
+	URL         string `json:"url,omitempty"`
+	Status      int    `json:"status,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        string `json:"body,omitempty"`
+	BodyBase64  bool   `json:"body_base64,omitempty"`
+}
+
+// Recorder appends tool calls and API exchanges to a recording file
+type Recorder struct {
+	mu      sync.Mutex
+	file    *os.File
+	encoder *json.Encoder
+	seq     int
+}
+
+// StartRecording captures every tool call and all HTTP traffic of http.DefaultTransport to path.
+// Call it before any API client is created. The file holds API responses, including env
+// variable values; review it before sharing.
+func StartRecording(path string) (*Recorder, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	r := &Recorder{file: file, encoder: json.NewEncoder(file)}
+	http.DefaultTransport = &recordingTransport{base: http.DefaultTransport, recorder: r}
+	shared.GlobalRecorder = r
+	return r, nil
+}
+
+// Close stops recording and closes the file
+func (r *Recorder) Close() error {
+	shared.GlobalRecorder = nil
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
+
+// write appends e with the next sequence number, unbuffered so a crash keeps everything before it
+func (r *Recorder) write(e *entry) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.seq++
+	e.Seq = r.seq
+	e.Time = time.Now().UTC()
+	if err := r.encoder.Encode(e); err != nil {
+		fmt.Fprintf(os.Stderr, "Recording failed: %v\n", err)
+	}
+	return e.Seq
+}
+
+// ToolCalled implements shared.CallRecorder
+func (r *Recorder) ToolCalled(ctx context.Context, name string, args map[string]interface{}) func(result interface{}, err error) {
+	call := r.write(&entry{Kind: kindCall, Tool: name, Arguments: args})
+	return func(result interface{}, err error) {
+		e := &entry{Kind: kindResult, Call: call, Tool: name, Result: result}
+		if err != nil {
+			e.Result = nil
+			e.Error = err.Error()
+			e.ErrorCode = shared.ErrorCode(err)
+		}
+		r.write(e)
+	}
+}
+
+// recordingTransport records each response before handing it to the caller
+type recordingTransport struct {
+	base     http.RoundTripper
+	recorder *Recorder
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(strings.NewReader(string(data)))
+
+	e := &entry{
+		Kind:        kindHTTP,
+		Method:      req.Method,
+		URL:         redactURL(req.URL),
+		Status:      resp.StatusCode,
+		ContentType: resp.Header.Get("Content-Type"),
+		Body:        string(data),
+	}
+	if !utf8.Valid(data) {
+		e.Body = base64.StdEncoding.EncodeToString(data)
+		e.BodyBase64 = true
+	}
+	t.recorder.write(e)
+	return resp, nil
+}
+
+// redactURL drops query values that look like credentials, e.g. the log backend access token
+func redactURL(u *url.URL) string {
+	query := u.Query()
+	for key := range query {
+		lower := strings.ToLower(key)
+		if strings.Contains(lower, "token") || strings.Contains(lower, "signature") || strings.Contains(lower, "key") {
+			query.Set(key, "REDACTED")
+		}
+	}
+	redacted := *u
+	redacted.User = nil
+	redacted.RawQuery = query.Encode()
+	return redacted.String()
+}
+
+// recordedCall is a call of a recording with its recorded outcome
+type recordedCall struct {
+	call   *entry
+	result *entry
+}
+
+// Run replays the calls recorded in path in order. API requests are answered from the
+// recording; newClient builds the client the tools use, wired to http.DefaultTransport.
+// Each call is reported to out; ok is false when any result differs from the recording.
+func Run(ctx context.Context, path string, newClient func(apiKey string) *sdk.Handler, out io.Writer) (ok bool, err error) {
+	calls, exchanges, err := load(path)
+	if err != nil {
+		return false, err
+	}
+	http.DefaultTransport = newPlayer(exchanges)
+	client := newClient("replay")
+
+	ok = true
+	for _, rc := range calls {
+		problem := replayCall(ctx, client, rc)
+		if problem == "" {
+			fmt.Fprintf(out, "ok    #%d %s\n", rc.call.Seq, rc.call.Tool)
+			continue
+		}
+		ok = false
+		fmt.Fprintf(out, "FAIL  #%d %s: %s\n", rc.call.Seq, rc.call.Tool, problem)
+	}
+	fmt.Fprintf(out, "%d calls replayed\n", len(calls))
+	return ok, nil
+}
+
+// load reads a recording and pairs each call with its result
+func load(path string) ([]recordedCall, []*entry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	var calls []recordedCall
+	var exchanges []*entry
+	index := make(map[int]int)
+	decoder := json.NewDecoder(file)
+	for {
+		e := &entry{}
+		if err := decoder.Decode(e); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, nil, fmt.Errorf("invalid recording %s: %w", path, err)
+		}
+		switch e.Kind {
+		case kindCall:
+			index[e.Seq] = len(calls)
+			calls = append(calls, recordedCall{call: e})
+		case kindResult:
+			if i, ok := index[e.Call]; ok {
+				calls[i].result = e
+			}
+		case kindHTTP:
+			exchanges = append(exchanges, e)
+		}
+	}
+	return calls, exchanges, nil
+}
+
+// replayCall runs one recorded call and describes how its outcome differs, or returns ""
+func replayCall(ctx context.Context, client *sdk.Handler, rc recordedCall) (problem string) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			problem = fmt.Sprintf("panic: %v\n%s", recovered, debug.Stack())
+			if rc.result == nil {
+				problem += "(the recorded call has no result either; the original session crashed here)"
+			}
+		}
+	}()
+
+	ctx = context.WithValue(ctx, "zeropsClient", client)
+	ctx = context.WithValue(ctx, "clientName", "replay")
+	args := rc.call.Arguments
+	if args == nil {
+		args = map[string]interface{}{}
+	}
+	result, err := shared.GlobalRegistry.CallTool(ctx, rc.call.Tool, args)
+
+	if rc.result == nil {
+		return "the recording has no result for this call"
+	}
+	if err != nil || rc.result.Error != "" {
+		got := ""
+		if err != nil {
+			got = err.Error()
+		}
+		if got != rc.result.Error {
+			return fmt.Sprintf("error %q, recorded %q", got, rc.result.Error)
+		}
+		return ""
+	}
+
+	normalized, err := normalize(result)
+	if err != nil {
+		return fmt.Sprintf("result is not JSON: %v", err)
+	}
+	if diff := firstDifference(rc.result.Result, normalized, "result"); diff != "" {
+		return diff
+	}
+	return ""
+}
+
+// normalize turns a handler result into the generic JSON values a recording decodes to
+func normalize(value interface{}) (interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var generic interface{}
+	err = json.Unmarshal(data, &generic)
+	return generic, err
+}
+
+// firstDifference names the first path where got differs from want
+func firstDifference(want, got interface{}, path string) string {
+	switch w := want.(type) {
+	case map[string]interface{}:
+		g, ok := got.(map[string]interface{})
+		if !ok {
+			return fmt.Sprintf("%s: got %T, recorded an object", path, got)
+		}
+		keys := make([]string, 0, len(w)+len(g))
+		for key := range w {
+			keys = append(keys, key)
+		}
+		for key := range g {
+			if _, ok := w[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if diff := firstDifference(w[key], g[key], path+"."+key); diff != "" {
+				return diff
+			}
+		}
+		return ""
+	case []interface{}:
+		g, ok := got.([]interface{})
+		if !ok {
+			return fmt.Sprintf("%s: got %T, recorded an array", path, got)
+		}
+		if len(g) != len(w) {
+			return fmt.Sprintf("%s: got %d items, recorded %d", path, len(g), len(w))
+		}
+		for i := range w {
+			if diff := firstDifference(w[i], g[i], fmt.Sprintf("%s[%d]", path, i)); diff != "" {
+				return diff
+			}
+		}
+		return ""
+	}
+	if !reflect.DeepEqual(want, got) {
+		return fmt.Sprintf("%s: got %v, recorded %v", path, got, want)
+	}
+	return ""
+}
+
+// player answers requests from recorded exchanges, matched by method and path in recorded
+// order. The last exchange of a path repeats, since polling tools may ask more often than
+// they did while recording.
+type player struct {
+	mu       sync.Mutex
+	queues   map[string][]*entry
+	lastSeen map[string]*entry
+}
+
+func newPlayer(exchanges []*entry) *player {
+	p := &player{queues: make(map[string][]*entry), lastSeen: make(map[string]*entry)}
+	for _, e := range exchanges {
+		u, err := url.Parse(e.URL)
+		if err != nil {
+			continue
+		}
+		key := e.Method + " " + u.Path
+		p.queues[key] = append(p.queues[key], e)
+	}
+	return p
+}
+
+func (p *player) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		io.Copy(io.Discard, req.Body)
+		req.Body.Close()
+	}
+	key := req.Method + " " + req.URL.Path
+
+	p.mu.Lock()
+	e := p.lastSeen[key]
+	if queue := p.queues[key]; len(queue) > 0 {
+		e = queue[0]
+		p.queues[key] = queue[1:]
+		p.lastSeen[key] = e
+	}
+	p.mu.Unlock()
+	if e == nil {
+		return nil, fmt.Errorf("replay: no recorded response for %s", key)
+	}
+
+	data := []byte(e.Body)
+	if e.BodyBase64 {
+		decoded, err := base64.StdEncoding.DecodeString(e.Body)
+		if err != nil {
+			return nil, fmt.Errorf("replay: corrupt body of exchange #%d: %w", e.Seq, err)
+		}
+		data = decoded
+	}
+	header := make(http.Header)
+	if e.ContentType != "" {
+		header.Set("Content-Type", e.ContentType)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.Status, http.StatusText(e.Status)),
+		StatusCode:    e.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(string(data))),
+		ContentLength: int64(len(data)),
+		Request:       req,
+	}, nil
+}
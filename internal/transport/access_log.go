@@ -0,0 +1,194 @@
+package transport
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+)
+
+// redacted replaces values that must not reach the logs
+const redacted = "[REDACTED]"
+
+// accessLogConfig is read from MCP_ACCESS_LOG ("off" disables logging) and
+// MCP_ACCESS_LOG_BODY ("true" adds the redacted JSON-RPC request)
+type accessLogConfig struct {
+	enabled bool
+	body    bool
+}
+
+func loadAccessLogConfig() accessLogConfig {
+	return accessLogConfig{
+		enabled: os.Getenv("MCP_ACCESS_LOG") != "off",
+		body:    os.Getenv("MCP_ACCESS_LOG_BODY") == "true",
+	}
+}
+
+// accessEntry collects what a request did; ServeHTTP fills it as the request is processed
+type accessEntry struct {
+	start   time.Time
+	method  string
+	path    string
+	ip      string
+	auth    string
+	session string
+	client  string
+	rpc     string
+	tool    string
+	user    string
+	orgs    string
+	body    string
+}
+
+func newAccessEntry(r *http.Request, ip string) *accessEntry {
+	auth := "none"
+	if header := r.Header.Get("Authorization"); header != "" {
+		// Only the scheme is logged, never the credential
+		auth = strings.ToLower(strings.SplitN(header, " ", 2)[0])
+	}
+
+	client := r.Header.Get("User-Agent")
+	session := r.Header.Get("Mcp-Session-Id")
+
+	return &accessEntry{
+		start:   time.Now(),
+		method:  r.Method,
+		path:    r.URL.Path,
+		ip:      ip,
+		auth:    auth,
+		session: session,
+		client:  client,
+	}
+}
+
+// setRequest records the JSON-RPC method, tool and client name of a parsed request
+func (e *accessEntry) setRequest(request map[string]interface{}, logBody bool) {
+	e.rpc, _ = request["method"].(string)
+	params, _ := request["params"].(map[string]interface{})
+	if e.rpc == "tools/call" && params != nil {
+		e.tool, _ = params["name"].(string)
+	}
+	if clientInfo, ok := params["clientInfo"].(map[string]interface{}); ok {
+		if name, _ := clientInfo["name"].(string); name != "" {
+			e.client = name
+		}
+	}
+	if logBody {
+		if data, err := json.Marshal(redactRequest(request)); err == nil {
+			e.body = string(data)
+		}
+	}
+}
+
+// setIdentity records who made the request
+func (e *accessEntry) setIdentity(identity *shared.Identity) {
+	e.user = identity.Email
+	e.orgs = identity.OrgNames()
+}
+
+// write prints the entry as one logfmt line to stderr
+func (e *accessEntry) write(status int) {
+	fields := []string{
+		"method=" + e.method,
+		"path=" + strconv.Quote(e.path),
+		"status=" + strconv.Itoa(status),
+		"duration=" + time.Since(e.start).Round(time.Millisecond).String(),
+		"ip=" + e.ip,
+		"auth=" + e.auth,
+		"session=" + orDash(e.session),
+		"client=" + strconv.Quote(e.client),
+	}
+	if e.user != "" {
+		fields = append(fields, "user="+strconv.Quote(e.user), "orgs="+strconv.Quote(e.orgs))
+	}
+	if e.rpc != "" {
+		fields = append(fields, "rpc="+e.rpc)
+	}
+	if e.tool != "" {
+		fields = append(fields, "tool="+e.tool)
+	}
+	if e.body != "" {
+		fields = append(fields, "body="+strconv.Quote(e.body))
+	}
+	fmt.Fprintf(os.Stderr, "access %s\n", strings.Join(fields, " "))
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+// statusRecorder captures the status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Flush lets streamed responses through the recorder
+func (r *statusRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// redactRequest copies a JSON-RPC request, keeping the envelope and identifiers
+// but replacing tool arguments and initialize metadata that may hold secrets
+func redactRequest(request map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(request))
+	for key, value := range request {
+		copied[key] = value
+	}
+
+	params, ok := request["params"].(map[string]interface{})
+	if !ok {
+		return copied
+	}
+	redactedParams := make(map[string]interface{}, len(params))
+	for key, value := range params {
+		switch key {
+		case "arguments":
+			if args, ok := value.(map[string]interface{}); ok {
+				redactedParams[key] = redactArguments(args)
+			} else {
+				redactedParams[key] = redacted
+			}
+		case "name", "protocolVersion", "clientInfo", "capabilities":
+			redactedParams[key] = value
+		default:
+			redactedParams[key] = redacted
+		}
+	}
+	copied["params"] = redactedParams
+	return copied
+}
+
+// redactArguments keeps IDs, hostnames, numbers and booleans; other strings (YAML, env values) are redacted
+func redactArguments(args map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(args))
+	for key, value := range args {
+		switch value.(type) {
+		case float64, bool, nil:
+			result[key] = value
+			continue
+		case string:
+			if strings.HasSuffix(key, "_id") || key == "hostname" || key == "service_name" || key == "action" {
+				result[key] = value
+				continue
+			}
+		}
+		result[key] = redacted
+	}
+	return result
+}
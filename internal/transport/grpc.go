@@ -0,0 +1,295 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+)
+
+// The gRPC facade serves the tool registry to automation that doesn't speak MCP, with the
+// service defined in api/zeropsmcp/v1/tools.proto. Requests go through the same
+// authentication, lockout, access log and audit events as the MCP endpoint.
+
+// grpcServicePath is the path prefix of the ToolService methods
+const grpcServicePath = "/zeropsmcp.v1.ToolService/"
+
+// grpcMaxMessage caps request messages, like the MCP endpoint caps request bodies
+const grpcMaxMessage = 4 << 20
+
+// gRPC status codes
+const (
+	grpcOK                = 0
+	grpcInvalidArgument   = 3
+	grpcDeadlineExceeded  = 4
+	grpcNotFound          = 5
+	grpcPermissionDenied  = 7
+	grpcResourceExhausted = 8
+	grpcAborted           = 10
+	grpcUnimplemented     = 12
+	grpcInternal          = 13
+	grpcUnavailable       = 14
+	grpcUnauthenticated   = 16
+)
+
+// grpcCodes maps tool error codes to gRPC status codes
+var grpcCodes = map[string]int{
+	shared.CodeNotFound:        grpcNotFound,
+	shared.CodeInvalidArgument: grpcInvalidArgument,
+	shared.CodeAPIUnavailable:  grpcUnavailable,
+	shared.CodeForbidden:       grpcPermissionDenied,
+	shared.CodeConflict:        grpcAborted,
+	shared.CodeBudgetExceeded:  grpcResourceExhausted,
+	shared.CodeInternal:        grpcInternal,
+}
+
+// ServeGRPC handles the unary calls of the gRPC ToolService: ListTools and CallTool
+func (h *HTTPHandler) ServeGRPC(w http.ResponseWriter, r *http.Request) {
+	entry := newAccessEntry(r, h.guard.clientIP(r))
+	if h.accessLog.enabled {
+		defer func() { entry.write(http.StatusOK) }()
+	}
+
+	if r.Method != http.MethodPost || !strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+		http.Error(w, "gRPC requests only", http.StatusUnsupportedMediaType)
+		return
+	}
+	w.Header().Set("Content-Type", "application/grpc")
+
+	method := strings.TrimPrefix(r.URL.Path, grpcServicePath)
+	if method != "ListTools" && method != "CallTool" {
+		writeGRPCStatus(w, grpcUnimplemented, "unknown method "+r.URL.Path)
+		return
+	}
+
+	capture := &grpcAuthWriter{header: http.Header{}}
+	apiKey, subject, ok := h.authenticate(capture, r)
+	if !ok {
+		writeGRPCStatus(w, capture.grpcCode(), capture.message())
+		return
+	}
+
+	message, err := readGRPCMessage(r.Body)
+	if err != nil {
+		writeGRPCStatus(w, grpcInvalidArgument, err.Error())
+		return
+	}
+
+	ctx := h.requestContext(r, apiKey, subject, entry)
+	ctx = context.WithValue(ctx, "grpcMode", true)
+	if timeout, ok := grpcTimeout(r.Header.Get("Grpc-Timeout")); ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	switch method {
+	case "ListTools":
+		entry.setRequest(map[string]interface{}{"method": "tools/list"}, false)
+		writeGRPCMessage(w, encodeToolList(shared.GlobalRegistry.List()))
+	case "CallTool":
+		name, arguments, err := decodeCallToolRequest(message)
+		if err != nil {
+			writeGRPCStatus(w, grpcInvalidArgument, err.Error())
+			return
+		}
+		entry.setRequest(map[string]interface{}{
+			"method": "tools/call",
+			"params": map[string]interface{}{"name": name, "arguments": arguments},
+		}, h.accessLog.body)
+
+		result, err := shared.GlobalRegistry.CallTool(ctx, name, arguments)
+		if err != nil {
+			code := grpcCodes[shared.ErrorCode(err)]
+			switch {
+			case errors.Is(err, shared.ErrToolNotFound):
+				code = grpcNotFound
+			case errors.Is(err, context.DeadlineExceeded):
+				code = grpcDeadlineExceeded
+			case code == grpcOK:
+				code = grpcInternal
+			}
+			w.Header().Set("Zerops-Error-Code", shared.ErrorCode(err))
+			writeGRPCStatus(w, code, err.Error())
+			return
+		}
+		value, err := jsonValue(result)
+		if err != nil {
+			writeGRPCStatus(w, grpcInternal, "failed to encode result: "+err.Error())
+			return
+		}
+		writeGRPCMessage(w, appendBytesField(nil, 1, encodeValue(value)))
+	}
+}
+
+// encodeToolList encodes a ListToolsResponse
+func encodeToolList(tools []*shared.ToolDefinition) []byte {
+	var b []byte
+	for _, tool := range tools {
+		var message []byte
+		message = appendStringField(message, 1, tool.Name)
+		message = appendStringField(message, 2, tool.Description)
+		if schema, err := jsonValue(tool.InputSchema); err == nil {
+			if object, ok := schema.(map[string]interface{}); ok {
+				message = appendBytesField(message, 3, encodeStruct(object))
+			}
+		}
+		if tool.Annotations != nil {
+			message = appendBoolField(message, 4, tool.Annotations.ReadOnly)
+			message = appendBoolField(message, 5, tool.Annotations.Destructive)
+			message = appendBoolField(message, 6, tool.Annotations.Idempotent)
+		}
+		b = appendBytesField(b, 1, message)
+	}
+	return b
+}
+
+// decodeCallToolRequest decodes a CallToolRequest into the tool name and arguments
+func decodeCallToolRequest(message []byte) (string, map[string]interface{}, error) {
+	fields, err := decodeFields(message)
+	if err != nil {
+		return "", nil, err
+	}
+	name := ""
+	arguments := map[string]interface{}{}
+	for _, field := range fields {
+		switch {
+		case field.number == 1 && field.wire == wireBytes:
+			name = string(field.data)
+		case field.number == 2 && field.wire == wireBytes:
+			if arguments, err = decodeStruct(field.data); err != nil {
+				return "", nil, err
+			}
+		}
+	}
+	if name == "" {
+		return "", nil, errors.New("name is required")
+	}
+	return name, arguments, nil
+}
+
+// jsonValue converts a result to plain JSON values (maps, slices, float64) by its JSON encoding
+func jsonValue(result interface{}) (interface{}, error) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	err = json.Unmarshal(data, &value)
+	return value, err
+}
+
+// readGRPCMessage reads the single length-prefixed message of a unary call
+func readGRPCMessage(body io.Reader) ([]byte, error) {
+	var prefix [5]byte
+	if _, err := io.ReadFull(body, prefix[:]); err != nil {
+		return nil, errors.New("missing gRPC message")
+	}
+	if prefix[0] != 0 {
+		return nil, errors.New("compressed messages are not supported")
+	}
+	length := binary.BigEndian.Uint32(prefix[1:])
+	if length > grpcMaxMessage {
+		return nil, fmt.Errorf("message of %d bytes exceeds the %d byte limit", length, grpcMaxMessage)
+	}
+	message := make([]byte, length)
+	if _, err := io.ReadFull(body, message); err != nil {
+		return nil, errors.New("truncated gRPC message")
+	}
+	return message, nil
+}
+
+// writeGRPCMessage writes a response message followed by an OK status in the trailers
+func writeGRPCMessage(w http.ResponseWriter, message []byte) {
+	w.Header().Set("Trailer", "Grpc-Status, Grpc-Message")
+	w.WriteHeader(http.StatusOK)
+	var prefix [5]byte
+	binary.BigEndian.PutUint32(prefix[1:], uint32(len(message)))
+	w.Write(prefix[:])
+	w.Write(message)
+	w.Header().Set("Grpc-Status", strconv.Itoa(grpcOK))
+	w.Header().Set("Grpc-Message", "")
+}
+
+// writeGRPCStatus writes a trailers-only response with an error status
+func writeGRPCStatus(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Grpc-Status", strconv.Itoa(code))
+	w.Header().Set("Grpc-Message", grpcPercentEncode(message))
+	w.WriteHeader(http.StatusOK)
+}
+
+// grpcPercentEncode encodes a status message as the grpc-message header requires
+func grpcPercentEncode(message string) string {
+	var b strings.Builder
+	for i := 0; i < len(message); i++ {
+		c := message[i]
+		if c < 0x20 || c > 0x7e || c == '%' {
+			fmt.Fprintf(&b, "%%%02X", c)
+		} else {
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// grpcTimeout parses a grpc-timeout header such as 30S or 500m
+func grpcTimeout(header string) (time.Duration, bool) {
+	if len(header) < 2 {
+		return 0, false
+	}
+	value, err := strconv.ParseInt(header[:len(header)-1], 10, 64)
+	if err != nil || value <= 0 {
+		return 0, false
+	}
+	units := map[byte]time.Duration{
+		'H': time.Hour, 'M': time.Minute, 'S': time.Second,
+		'm': time.Millisecond, 'u': time.Microsecond, 'n': time.Nanosecond,
+	}
+	unit, ok := units[header[len(header)-1]]
+	if !ok {
+		return 0, false
+	}
+	return time.Duration(value) * unit, true
+}
+
+// grpcAuthWriter captures the error response written by authenticate, so it can be sent as
+// a gRPC status
+type grpcAuthWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (w *grpcAuthWriter) Header() http.Header         { return w.header }
+func (w *grpcAuthWriter) Write(b []byte) (int, error) { return w.body.Write(b) }
+func (w *grpcAuthWriter) WriteHeader(status int)      { w.status = status }
+
+func (w *grpcAuthWriter) grpcCode() int {
+	switch w.status {
+	case http.StatusUnauthorized:
+		return grpcUnauthenticated
+	case http.StatusForbidden:
+		return grpcPermissionDenied
+	}
+	return grpcUnavailable
+}
+
+func (w *grpcAuthWriter) message() string {
+	var body struct {
+		Error   string `json:"error"`
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(w.body.Bytes(), &body) != nil || body.Message == "" {
+		return http.StatusText(w.status)
+	}
+	return body.Error + ": " + body.Message
+}
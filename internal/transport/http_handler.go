@@ -3,14 +3,18 @@ package transport
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"strings"
+	"time"
 
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
-	"github.com/zerops-mcp-basic/internal/handlers/shared"
 	"github.com/zeropsio/zerops-go/sdk"
 	"github.com/zeropsio/zerops-go/sdkBase"
 )
@@ -20,42 +24,75 @@ type HTTPServerConfig struct {
 	Host   string
 	Port   string
 	Server *mcp.Server
+	// APIKey is an optional server-side Zerops API key used when a request has no Bearer token
+	APIKey string
+	// OAuth, when set, requires OAuth access tokens instead of raw Zerops API keys
+	OAuth *OAuthConfig
+	// GRPCPort, when set, also serves the gRPC ToolService on this port (plaintext HTTP/2)
+	GRPCPort string
+	// SessionStore, when set, is the file session state is persisted to across restarts
+	SessionStore string
+	// MetricsPort, when set, serves the authentication counters at /metrics on this port of
+	// the loopback interface only, since they are served without authentication
+	MetricsPort string
 }
 
 // HTTPHandler handles HTTP requests using the global tool registry
 type HTTPHandler struct {
-	mcpServer *mcp.Server
+	mcpServer    *mcp.Server
+	staticAPIKey string
+	oauth        *oauthAuthenticator
+	guard        *authGuard
+	accessLog    accessLogConfig
+	// logLevels keeps the level set by logging/setLevel for each session
+	logLevels *sessionValues
+	// clientNames keeps the client name from initialize for each session
+	clientNames *sessionValues
+	// streams keeps the GET event stream of each session
+	streams *sessionStreams
 }
 
-// NewHTTPHandler creates a new HTTP handler
-func NewHTTPHandler(mcpServer *mcp.Server) *HTTPHandler {
-	return &HTTPHandler{
-		mcpServer: mcpServer,
+// NewHTTPHandler creates a new HTTP handler. When staticAPIKey is set, requests
+// without a Bearer token use it instead of being rejected. When oauth is set,
+// Bearer tokens are OAuth access tokens mapped to Zerops API keys and the static key is ignored.
+func NewHTTPHandler(mcpServer *mcp.Server, staticAPIKey string, oauth *OAuthConfig) *HTTPHandler {
+	handler := &HTTPHandler{
+		mcpServer:    mcpServer,
+		staticAPIKey: staticAPIKey,
+		guard:        newAuthGuard(),
+		accessLog:    loadAccessLogConfig(),
+		logLevels:    &sessionValues{values: make(map[string]sessionEntry)},
+		clientNames:  &sessionValues{values: make(map[string]sessionEntry)},
+		streams:      &sessionStreams{streams: make(map[string]*eventStream)},
 	}
+	if oauth != nil {
+		handler.oauth = newOAuthAuthenticator(*oauth)
+	}
+	return handler
 }
 
 // ServeHTTP handles incoming HTTP requests using shared registry
 func (h *HTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	entry := newAccessEntry(r, h.guard.clientIP(r))
+	if h.accessLog.enabled {
+		defer func() { entry.write(recorder.status) }()
+	}
+	h.serve(recorder, r, entry)
+}
+
+// serve routes a request; entry collects access log details
+func (h *HTTPHandler) serve(w http.ResponseWriter, r *http.Request, entry *accessEntry) {
 	// Handle CORS
-	w.Header().Set("Access-Control-Allow-Origin", "*")
-	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, Accept")
+	if !h.setCORSHeaders(w, r) {
+		http.Error(w, "Origin not allowed", http.StatusForbidden)
+		return
+	}
 
 	if r.Method == http.MethodOptions {
 		w.WriteHeader(http.StatusOK)
 		return
 	}
-	
-	// Log User-Agent and other headers that might contain model info
-	if userAgent := r.Header.Get("User-Agent"); userAgent != "" {
-		fmt.Fprintf(os.Stderr, "User-Agent: %s\n", userAgent)
-	}
-	if xModel := r.Header.Get("X-Model"); xModel != "" {
-		fmt.Fprintf(os.Stderr, "X-Model: %s\n", xModel)
-	}
-	if xClaudeModel := r.Header.Get("X-Claude-Model"); xClaudeModel != "" {
-		fmt.Fprintf(os.Stderr, "X-Claude-Model: %s\n", xClaudeModel)
-	}
 
 	// Health check endpoint
 	if r.URL.Path == "/health" {
@@ -68,16 +105,31 @@ func (h *HTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// OAuth protected resource metadata for client discovery
+	if h.oauth != nil && r.URL.Path == protectedResourcePath {
+		h.oauth.serveMetadata(w, r)
+		return
+	}
+
+	// REST facade of the tools for clients that don't speak MCP
+	if h.serveREST(w, r, entry) {
+		return
+	}
+
+	// A GET opens the session's stream for messages that don't answer a request
+	if r.Method == http.MethodGet && acceptsEventStream(r) {
+		h.serveSessionStream(w, r)
+		return
+	}
+
 	// Only accept POST for JSON-RPC
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	// Extract API key
-	apiKey := extractBearerToken(r.Header.Get("Authorization"))
-	if apiKey == "" {
-		http.Error(w, "Authorization header with Bearer token required", http.StatusUnauthorized)
+	apiKey, subject, ok := h.authenticate(w, r)
+	if !ok {
 		return
 	}
 
@@ -89,20 +141,100 @@ func (h *HTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 	defer r.Body.Close()
 
-	// Log the raw request for debugging
-	fmt.Fprintf(os.Stderr, "\n=== RAW REQUEST ===\n")
-	fmt.Fprintf(os.Stderr, "Body: %s\n", string(body))
-	fmt.Fprintf(os.Stderr, "==================\n\n")
-
 	// Parse JSON-RPC request
 	var request map[string]interface{}
 	if err := json.Unmarshal(body, &request); err != nil {
-		fmt.Fprintf(os.Stderr, "JSON Parse Error: %v\n", err)
 		http.Error(w, "Invalid JSON", http.StatusBadRequest)
 		return
 	}
+	entry.setRequest(request, h.accessLog.body)
+
+	ctx := h.requestContext(r, apiKey, subject, entry)
+
+	// The server issues session IDs, so clients can't pick one another client uses
+	if method, _ := request["method"].(string); method == "initialize" {
+		sessionID, err := newSessionID()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Mcp-Session-Id", sessionID)
+		ctx = shared.WithSession(ctx, requestSession(apiKey, sessionID))
+	}
+	session := shared.SessionID(ctx)
+
+	// Tool calls stream log and progress notifications as SSE when the client accepts them
+	if method, _ := request["method"].(string); method == "tools/call" && acceptsEventStream(r) {
+		params, _ := request["params"].(map[string]interface{})
+		meta, _ := params["_meta"].(map[string]interface{})
+		level := h.logLevels.get(session)
+		progressToken := meta["progressToken"]
+		if level == "" && followsLogs(params) {
+			level = "info"
+		}
+		if level != "" || progressToken != nil {
+			stream := newEventStream(w)
+			ctx = withStreamNotifications(ctx, stream, level, progressToken)
+			stream.close(h.processRequest(ctx, request))
+			return
+		}
+	}
 
-	// Create context with API key and HTTP mode flag
+	// Process the request
+	response := h.processRequest(ctx, request)
+
+	// Send response
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// streamKeepAlive is how often an idle session stream is pinged
+const streamKeepAlive = 25 * time.Second
+
+// serveSessionStream holds the event stream of a session open until the client disconnects.
+// Resource subscriptions are delivered on it, so they end with it.
+func (h *HTTPHandler) serveSessionStream(w http.ResponseWriter, r *http.Request) {
+	apiKey, _, ok := h.authenticate(w, r)
+	if !ok {
+		return
+	}
+	sessionID := r.Header.Get("Mcp-Session-Id")
+	if sessionID == "" {
+		http.Error(w, "Mcp-Session-Id header required", http.StatusBadRequest)
+		return
+	}
+	session := requestSession(apiKey, sessionID)
+
+	flusher, _ := w.(http.Flusher)
+	stream := &eventStream{w: w, flusher: flusher}
+	if !h.streams.open(session, stream) {
+		http.Error(w, "The session already has an open stream", http.StatusConflict)
+		return
+	}
+	defer func() {
+		stream.stop()
+		h.streams.remove(session)
+		shared.GlobalSubscriptions.UnsubscribeAll(session)
+	}()
+	stream.start()
+
+	ticker := time.NewTicker(streamKeepAlive)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			if stream.ping() != nil {
+				return
+			}
+		}
+	}
+}
+
+// requestContext builds the context tool handlers run with for an authenticated request:
+// the API key and its client, the key rotation hooks and the MCP session
+func (h *HTTPHandler) requestContext(r *http.Request, apiKey, subject string, entry *accessEntry) context.Context {
 	ctx := r.Context()
 	ctx = context.WithValue(ctx, "httpMode", true) // Flag for HTTP mode
 
@@ -110,21 +242,84 @@ func (h *HTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		ctx = context.WithValue(ctx, "apiKey", apiKey)
 		client := createZeropsClient(apiKey)
 		ctx = context.WithValue(ctx, "zeropsClient", client)
+
+		// Access log lines name the user; the identity is cached per key
+		if identity, err := shared.ResolveIdentity(ctx, client, apiKey); err == nil {
+			entry.setIdentity(identity)
+		}
 	}
 
-	// Process the request
-	response := h.processRequest(ctx, request)
+	// rotate_api_key validates new keys with the server's client configuration; only
+	// OAuth sessions keep their key on the server, so only they can have it replaced
+	ctx = shared.WithClientFactory(ctx, createZeropsClient)
+	if h.oauth != nil && subject != "" {
+		ctx = shared.WithKeyStore(ctx, func(ctx context.Context, apiKey string, client *sdk.Handler) error {
+			return h.oauth.storeAPIKey(subject, apiKey)
+		})
+	}
+
+	return shared.WithSession(ctx, requestSession(apiKey, r.Header.Get("Mcp-Session-Id")))
+}
 
-	// Log the response for debugging
-	if responseBytes, err := json.MarshalIndent(response, "", "  "); err == nil {
-		fmt.Fprintf(os.Stderr, "\n=== RESPONSE ===\n")
-		fmt.Fprintf(os.Stderr, "%s\n", string(responseBytes))
-		fmt.Fprintf(os.Stderr, "================\n\n")
+// authenticate resolves the Zerops API key for the request, writing the error response when it fails.
+// Repeated failures from one IP or with one key lock them out temporarily.
+// subject is the OAuth token subject, empty without OAuth.
+func (h *HTTPHandler) authenticate(w http.ResponseWriter, r *http.Request) (apiKey, subject string, ok bool) {
+	token := extractBearerToken(r.Header.Get("Authorization"))
+	// OAuth access tokens are JWTs that all start alike, so their failures lock out the IP only
+	keyToken := token
+	if h.oauth != nil {
+		keyToken = ""
 	}
+	subjects := h.guard.subjects(r, keyToken)
 
-	// Send response
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	if retryAfter := h.guard.lockedFor(subjects); retryAfter > 0 {
+		writeAuthError(w, http.StatusUnauthorized, "locked_out", "Too many failed authentication attempts, try again later", retryAfter)
+		return "", "", false
+	}
+
+	if h.oauth != nil {
+		if token == "" {
+			h.oauth.challenge(w, r, false)
+			writeAuthError(w, http.StatusUnauthorized, "missing_token", "Authorization header with OAuth access token required", 0)
+			return "", "", false
+		}
+		apiKey, subject, err := h.oauth.Authenticate(r.Context(), token)
+		switch {
+		case errors.Is(err, errInvalidToken):
+			h.guard.recordFailure(subjects)
+			h.oauth.challenge(w, r, true)
+			writeAuthError(w, http.StatusUnauthorized, "invalid_token", err.Error(), 0)
+			return "", "", false
+		case errors.Is(err, errNoAPIKey):
+			fmt.Fprintf(os.Stderr, "OAuth: no Zerops API key for subject %s\n", subject)
+			writeAuthError(w, http.StatusForbidden, "no_api_key", err.Error(), 0)
+			return "", "", false
+		case err != nil:
+			fmt.Fprintf(os.Stderr, "OAuth: %v\n", err)
+			writeAuthError(w, http.StatusBadGateway, "introspection_failed", "Failed to validate access token", 0)
+			return "", "", false
+		}
+		h.guard.recordSuccess(subjects)
+		return apiKey, subject, true
+	}
+
+	// Fall back to the server-side key for single-tenant deployments
+	if token == "" {
+		if h.staticAPIKey == "" {
+			writeAuthError(w, http.StatusUnauthorized, "missing_token", "Authorization header with Bearer token required", 0)
+			return "", "", false
+		}
+		return h.staticAPIKey, "", true
+	}
+
+	if !h.guard.validateAPIKey(r.Context(), token) {
+		h.guard.recordFailure(subjects)
+		writeAuthError(w, http.StatusUnauthorized, "invalid_token", "Zerops API key was rejected", 0)
+		return "", "", false
+	}
+	h.guard.recordSuccess(subjects)
+	return token, "", true
 }
 
 // processRequest handles JSON-RPC requests using shared registry
@@ -133,70 +328,79 @@ func (h *HTTPHandler) processRequest(ctx context.Context, request map[string]int
 	id := request["id"]
 	params, _ := request["params"].(map[string]interface{})
 
-	// Extract client information if available
-	var clientName, clientVersion string
+	// Store client info in context for use in tools; it is logged by the access log
 	if method == "initialize" && params != nil {
 		if clientInfo, ok := params["clientInfo"].(map[string]interface{}); ok {
-			clientName, _ = clientInfo["name"].(string)
-			clientVersion, _ = clientInfo["version"].(string)
-			clientTitle, _ := clientInfo["title"].(string)
-			
-			fmt.Fprintf(os.Stderr, "\n=== CLIENT IDENTIFICATION (HTTP) ===\n")
-			fmt.Fprintf(os.Stderr, "Client: %s\n", clientName)
-			fmt.Fprintf(os.Stderr, "Version: %s\n", clientVersion)
-			if clientTitle != "" {
-				fmt.Fprintf(os.Stderr, "Title: %s\n", clientTitle)
-			}
-			if protocol, ok := params["protocolVersion"].(string); ok {
-				fmt.Fprintf(os.Stderr, "Protocol: %s\n", protocol)
-			}
-			
-			// Check for _meta field which might contain model info
-			if meta, ok := params["_meta"].(map[string]interface{}); ok {
-				fmt.Fprintf(os.Stderr, "Meta fields:\n")
-				for key, value := range meta {
-					fmt.Fprintf(os.Stderr, "  %s: %v\n", key, value)
-				}
-			}
-			
-			// Check capabilities for any model hints
-			if caps, ok := params["capabilities"].(map[string]interface{}); ok {
-				// Check if there's any model-specific info in capabilities
-				if len(caps) > 0 {
-					fmt.Fprintf(os.Stderr, "Capabilities: %v\n", caps)
-				}
-			}
-			
-			fmt.Fprintf(os.Stderr, "===========================\n\n")
-			
-			// Store client info in context for use in tools
+			clientName, _ := clientInfo["name"].(string)
+			clientVersion, _ := clientInfo["version"].(string)
 			ctx = context.WithValue(ctx, "clientName", clientName)
 			ctx = context.WithValue(ctx, "clientVersion", clientVersion)
+
+			// tools/list arrives in a later request and picks its descriptions by client
+			session := shared.SessionID(ctx)
+			h.clientNames.set(session, clientName)
 		}
 	}
 
 	switch method {
 	case "initialize":
+		requested, _ := params["protocolVersion"].(string)
+		version, ok := negotiateProtocolVersion(requested)
+		if !ok {
+			return map[string]interface{}{
+				"jsonrpc": "2.0",
+				"id":      id,
+				"error": map[string]interface{}{
+					"code":    -32602,
+					"message": "Unsupported protocol version",
+					"data": map[string]interface{}{
+						"supported": supportedProtocolVersions,
+						"requested": requested,
+					},
+				},
+			}
+		}
+		result := map[string]interface{}{
+			"protocolVersion": version,
+			"capabilities":    serverCapabilities(),
+			"serverInfo": map[string]interface{}{
+				"name":    "zerops-mcp",
+				"version": shared.ServerVersion,
+			},
+		}
+		// Operator-provided organization guidelines
+		if instructions := shared.ServerInstructions(); instructions != "" {
+			result["instructions"] = instructions
+		}
 		return map[string]interface{}{
 			"jsonrpc": "2.0",
 			"id":      id,
-			"result": map[string]interface{}{
-				"protocolVersion": "2024-11-05",
-				"capabilities": map[string]interface{}{
-					"tools":     map[string]interface{}{},
-					"resources": map[string]interface{}{},
-					"prompts":   map[string]interface{}{},
-				},
-				"serverInfo": map[string]interface{}{
-					"name":    "zerops-mcp",
-					"version": "1.0.0",
+			"result":  result,
+		}
+
+	case "logging/setLevel":
+		level, _ := params["level"].(string)
+		if _, ok := logLevels[level]; !ok {
+			return map[string]interface{}{
+				"jsonrpc": "2.0",
+				"id":      id,
+				"error": map[string]interface{}{
+					"code":    -32602,
+					"message": "Invalid log level: " + level,
 				},
-				// No special instructions for simplified MCP
-			},
+			}
+		}
+		session := shared.SessionID(ctx)
+		h.logLevels.set(session, level)
+		return map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      id,
+			"result":  map[string]interface{}{},
 		}
 
 	case "tools/list":
-		tools := h.getRegisteredTools()
+		session := shared.SessionID(ctx)
+		tools := h.getRegisteredTools(shared.ShortDescriptions(h.clientNames.get(session)))
 		return map[string]interface{}{
 			"jsonrpc": "2.0",
 			"id":      id,
@@ -209,10 +413,16 @@ func (h *HTTPHandler) processRequest(ctx context.Context, request map[string]int
 		toolName, _ := params["name"].(string)
 		toolArgs, _ := params["arguments"].(map[string]interface{})
 
-		// Note: Client info was stored in context during initialize
-		// but context is per-request in HTTP mode, so it's lost
-		// Call tool using shared registry
+		// Context is per-request in HTTP mode, so the client name comes from the session
+		session := shared.SessionID(ctx)
+		if clientName := h.clientNames.get(session); clientName != "" {
+			ctx = context.WithValue(ctx, "clientName", clientName)
+		}
 		result, err := shared.GlobalRegistry.CallTool(ctx, toolName, toolArgs)
+		if err != nil && !errors.Is(err, shared.ErrToolNotFound) {
+			// Tool failures are reported in the result so the model can see them
+			result, err = shared.ErrorResult(ctx, err), nil
+		}
 		if err != nil {
 			return map[string]interface{}{
 				"jsonrpc": "2.0",
@@ -230,6 +440,137 @@ func (h *HTTPHandler) processRequest(ctx context.Context, request map[string]int
 			"result":  result,
 		}
 
+	case "resources/list":
+		// Every resource is addressed by a template; there are no fixed URIs to list
+		return map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      id,
+			"result": map[string]interface{}{
+				"resources": []interface{}{},
+			},
+		}
+
+	case "resources/templates/list":
+		templates := shared.GlobalResources.Templates()
+		list := make([]map[string]interface{}, 0, len(templates))
+		for _, template := range templates {
+			list = append(list, map[string]interface{}{
+				"uriTemplate": template.URITemplate,
+				"name":        template.Name,
+				"description": template.Description,
+				"mimeType":    shared.ResourceMIMEType,
+			})
+		}
+		return map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      id,
+			"result": map[string]interface{}{
+				"resourceTemplates": list,
+			},
+		}
+
+	case "resources/read":
+		uri, _ := params["uri"].(string)
+		text, err := shared.GlobalResources.ReadResource(ctx, uri)
+		if err != nil {
+			return resourceError(id, uri, err)
+		}
+		return map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      id,
+			"result": map[string]interface{}{
+				"contents": []interface{}{
+					map[string]interface{}{
+						"uri":      uri,
+						"mimeType": shared.ResourceMIMEType,
+						"text":     text,
+					},
+				},
+			},
+		}
+
+	case "prompts/list":
+		prompts := shared.GlobalPrompts.List()
+		list := make([]map[string]interface{}, 0, len(prompts))
+		for _, prompt := range prompts {
+			arguments := make([]map[string]interface{}, 0, len(prompt.Arguments))
+			for _, arg := range prompt.Arguments {
+				arguments = append(arguments, map[string]interface{}{
+					"name":        arg.Name,
+					"description": arg.Description,
+					"required":    arg.Required,
+				})
+			}
+			list = append(list, map[string]interface{}{
+				"name":        prompt.Name,
+				"description": prompt.Description,
+				"arguments":   arguments,
+			})
+		}
+		return map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      id,
+			"result": map[string]interface{}{
+				"prompts": list,
+			},
+		}
+
+	case "prompts/get":
+		name, _ := params["name"].(string)
+		arguments := map[string]string{}
+		if raw, ok := params["arguments"].(map[string]interface{}); ok {
+			for key, value := range raw {
+				if text, ok := value.(string); ok {
+					arguments[key] = text
+				}
+			}
+		}
+		prompt, text, err := shared.GlobalPrompts.GetPrompt(name, arguments)
+		if err != nil {
+			return map[string]interface{}{
+				"jsonrpc": "2.0",
+				"id":      id,
+				"error": map[string]interface{}{
+					"code":    -32602,
+					"message": err.Error(),
+					"data":    map[string]interface{}{"name": name, "error_code": shared.ErrorCode(err)},
+				},
+			}
+		}
+		return map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      id,
+			"result": map[string]interface{}{
+				"description": prompt.Description,
+				"messages": []interface{}{
+					map[string]interface{}{
+						"role":    "user",
+						"content": map[string]interface{}{"type": "text", "text": text},
+					},
+				},
+			},
+		}
+
+	case "resources/subscribe":
+		uri, _ := params["uri"].(string)
+		if err := h.subscribe(ctx, uri); err != nil {
+			return resourceError(id, uri, err)
+		}
+		return map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      id,
+			"result":  map[string]interface{}{},
+		}
+
+	case "resources/unsubscribe":
+		uri, _ := params["uri"].(string)
+		shared.GlobalSubscriptions.Unsubscribe(shared.SessionID(ctx), uri)
+		return map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      id,
+			"result":  map[string]interface{}{},
+		}
+
 	default:
 		return map[string]interface{}{
 			"jsonrpc": "2.0",
@@ -242,22 +583,94 @@ func (h *HTTPHandler) processRequest(ctx context.Context, request map[string]int
 	}
 }
 
-// getRegisteredTools returns all tools from shared registry
-func (h *HTTPHandler) getRegisteredTools() []map[string]interface{} {
+// subscribe watches uri for the session of ctx. Updates are pushed on the session's GET
+// stream, so it must be open.
+func (h *HTTPHandler) subscribe(ctx context.Context, uri string) error {
+	session := shared.SessionID(ctx)
+	stream := h.streams.get(session)
+	if stream == nil {
+		return shared.InvalidArgument("Open the session stream with a GET carrying Mcp-Session-Id before subscribing")
+	}
+	err := shared.GlobalSubscriptions.Subscribe(ctx, session, uri, func(uri string) error {
+		return stream.send(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"method":  "notifications/resources/updated",
+			"params":  map[string]interface{}{"uri": uri},
+		})
+	})
+	// The stream may have closed while the resource was read, taking the session's subscriptions with it
+	if err == nil && h.streams.get(session) != stream {
+		shared.GlobalSubscriptions.Unsubscribe(session, uri)
+		return shared.InvalidArgument("The session stream closed; open it again and subscribe")
+	}
+	return err
+}
+
+// resourceError is the JSON-RPC error response of a failed resource request.
+// -32002 is the MCP code for a resource that doesn't exist.
+func resourceError(id interface{}, uri string, err error) map[string]interface{} {
+	code := -32603
+	switch {
+	case errors.Is(err, shared.ErrNotFound):
+		code = -32002
+	case errors.Is(err, shared.ErrInvalidArgument):
+		code = -32602
+	}
+	return map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      id,
+		"error": map[string]interface{}{
+			"code":    code,
+			"message": err.Error(),
+			"data":    map[string]interface{}{"uri": uri, "error_code": shared.ErrorCode(err)},
+		},
+	}
+}
+
+// supportedProtocolVersions lists the MCP protocol versions served over HTTP, newest first
+var supportedProtocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+// negotiateProtocolVersion echoes the client's version when supported. Clients that
+// send no version get the newest one.
+func negotiateProtocolVersion(requested string) (string, bool) {
+	if requested == "" {
+		return supportedProtocolVersions[0], true
+	}
+	for _, version := range supportedProtocolVersions {
+		if version == requested {
+			return version, true
+		}
+	}
+	return "", false
+}
+
+// serverCapabilities lists what this transport actually supports. Log messages are
+// delivered on tools/call responses streamed as SSE, resource updates on the session's
+// GET stream.
+func serverCapabilities() map[string]interface{} {
+	return map[string]interface{}{
+		"tools":     map[string]interface{}{},
+		"logging":   map[string]interface{}{},
+		"resources": map[string]interface{}{"subscribe": true},
+		"prompts":   map[string]interface{}{},
+	}
+}
+
+// getRegisteredTools returns all tools from shared registry, with short descriptions when short is set
+func (h *HTTPHandler) getRegisteredTools(short bool) []map[string]interface{} {
 	tools := shared.GlobalRegistry.List()
 	result := make([]map[string]interface{}, 0, len(tools))
 
 	for _, tool := range tools {
-		// Debug: Log the actual InputSchema for discovery
-		if tool.Name == "discovery" {
-			fmt.Printf("DEBUG: Discovery InputSchema: %+v\n", tool.InputSchema)
-		}
-		
-		result = append(result, map[string]interface{}{
+		entry := map[string]interface{}{
 			"name":        tool.Name,
-			"description": tool.Description,
+			"description": tool.DescriptionFor(short),
 			"inputSchema": tool.InputSchema,
-		})
+		}
+		if tool.Annotations != nil {
+			entry["annotations"] = tool.Annotations.Map()
+		}
+		result = append(result, entry)
 	}
 
 	return result
@@ -275,19 +688,73 @@ func extractBearerToken(authHeader string) string {
 	return strings.TrimSpace(parts[1])
 }
 
-// createZeropsClient creates a Zerops SDK client with the given API key
+// createZeropsClient creates a Zerops SDK client with the given API key; its calls count against the key's budget
 func createZeropsClient(apiKey string) *sdk.Handler {
+	return newZeropsClient(apiKey, shared.BudgetedHTTPClient(shared.OwnerID(apiKey)))
+}
+
+// newZeropsClient creates a Zerops SDK client using httpClient
+func newZeropsClient(apiKey string, httpClient *http.Client) *sdk.Handler {
 	config := sdkBase.Config{
 		Endpoint: "https://api.app-prg1.zerops.io",
 	}
-	baseSDK := sdk.New(config, http.DefaultClient)
+	baseSDK := sdk.New(config, httpClient)
 	authorizedSDK := sdk.AuthorizeSdk(baseSDK, apiKey)
 	return &authorizedSDK
 }
 
+// setCORSHeaders allows any origin, except with the server-side key fallback: then any web
+// page could call tools with that key, so only loopback origins are allowed. It returns false
+// for a refused origin.
+func (h *HTTPHandler) setCORSHeaders(w http.ResponseWriter, r *http.Request) bool {
+	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, Accept, Mcp-Session-Id")
+	w.Header().Set("Access-Control-Expose-Headers", "Mcp-Session-Id")
+	if h.staticAPIKey == "" || h.oauth != nil {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		return true
+	}
+
+	w.Header().Add("Vary", "Origin")
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	parsed, err := url.Parse(origin)
+	if err != nil || !isLoopbackHost(parsed.Hostname()) {
+		return false
+	}
+	w.Header().Set("Access-Control-Allow-Origin", origin)
+	return true
+}
+
+// isLoopbackHost reports whether host is localhost or a loopback IP
+func isLoopbackHost(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
 // StartHTTPServer starts the HTTP server using the global registry
 func StartHTTPServer(ctx context.Context, config HTTPServerConfig) error {
-	handler := NewHTTPHandler(config.Server)
+	// Anyone who reaches the listener acts with the server-side key, so it is only used on loopback
+	apiKey := config.APIKey
+	if apiKey != "" && config.OAuth == nil && !isLoopbackHost(config.Host) {
+		fmt.Fprintf(os.Stderr, "Ignoring ZEROPS_API_KEY for requests without a Bearer token: the server-side key is only used when listening on a loopback address (--host 127.0.0.1)\n")
+		apiKey = ""
+	}
+	handler := NewHTTPHandler(config.Server, apiKey, config.OAuth)
+
+	if config.SessionStore != "" {
+		shared.RegisterSessionState("log_levels", shared.MapSessionState(&handler.logLevels.mu, handler.logLevels.values))
+		shared.RegisterSessionState("client_names", shared.MapSessionState(&handler.clientNames.mu, handler.clientNames.values))
+		if err := shared.OpenSessionStore(ctx, config.SessionStore); err != nil {
+			return err
+		}
+		defer shared.FlushSessionStore()
+	}
 
 	server := &http.Server{
 		Addr:    fmt.Sprintf("%s:%s", config.Host, config.Port),
@@ -300,6 +767,47 @@ func StartHTTPServer(ctx context.Context, config HTTPServerConfig) error {
 		server.Close()
 	}()
 
+	if config.MetricsPort != "" {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+			handler.guard.serveMetrics(w)
+		})
+		metricsServer := &http.Server{
+			Addr:    net.JoinHostPort("127.0.0.1", config.MetricsPort),
+			Handler: mux,
+		}
+		go func() {
+			<-ctx.Done()
+			metricsServer.Close()
+		}()
+		go func() {
+			if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				fmt.Fprintf(os.Stderr, "Metrics server error: %v\n", err)
+			}
+		}()
+	}
+
+	if config.GRPCPort != "" {
+		// gRPC clients speak HTTP/2 without TLS unless configured otherwise; TLS is
+		// expected to terminate in front of the server, as for the MCP endpoint
+		protocols := new(http.Protocols)
+		protocols.SetUnencryptedHTTP2(true)
+		grpcServer := &http.Server{
+			Addr:      fmt.Sprintf("%s:%s", config.Host, config.GRPCPort),
+			Handler:   http.HandlerFunc(handler.ServeGRPC),
+			Protocols: protocols,
+		}
+		go func() {
+			<-ctx.Done()
+			grpcServer.Close()
+		}()
+		go func() {
+			if err := grpcServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				fmt.Fprintf(os.Stderr, "gRPC server error: %v\n", err)
+			}
+		}()
+	}
+
 	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 		return err
 	}
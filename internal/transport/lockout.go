@@ -0,0 +1,255 @@
+package transport
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+)
+
+const (
+	// defaultMaxAuthFailures is how many failures within authFailureWindow trigger a lockout
+	defaultMaxAuthFailures = 5
+	// defaultLockoutDuration is how long a locked IP or key is rejected
+	defaultLockoutDuration = 15 * time.Minute
+	authFailureWindow      = 15 * time.Minute
+	// validKeyTTL is how long a Zerops API key stays trusted after a successful check
+	validKeyTTL = 10 * time.Minute
+	// apiKeyCheckTimeout bounds the API call that checks a raw Zerops API key
+	apiKeyCheckTimeout = 10 * time.Second
+)
+
+// authFailures tracks recent failures of one IP or key
+type authFailures struct {
+	count       int
+	first       time.Time
+	lockedUntil time.Time
+}
+
+// authGuard applies temporary lockouts after repeated authentication failures
+type authGuard struct {
+	maxFailures int
+	lockout     time.Duration
+	trustProxy  bool
+	// httpClient checks API keys; it is not charged to the key's budget, so unknown keys
+	// don't create budget entries
+	httpClient *http.Client
+
+	mu        sync.Mutex
+	failures  map[string]*authFailures
+	validKeys map[string]time.Time
+	lastSweep time.Time
+
+	failureCount  atomic.Int64
+	lockoutCount  atomic.Int64
+	rejectedCount atomic.Int64
+}
+
+// newAuthGuard creates a guard configured by MCP_AUTH_MAX_FAILURES, MCP_AUTH_LOCKOUT and MCP_TRUST_PROXY
+func newAuthGuard() *authGuard {
+	guard := &authGuard{
+		maxFailures: defaultMaxAuthFailures,
+		lockout:     defaultLockoutDuration,
+		trustProxy:  os.Getenv("MCP_TRUST_PROXY") == "true",
+		httpClient:  &http.Client{Timeout: apiKeyCheckTimeout},
+		failures:    make(map[string]*authFailures),
+		validKeys:   make(map[string]time.Time),
+	}
+	if value, err := strconv.Atoi(os.Getenv("MCP_AUTH_MAX_FAILURES")); err == nil && value > 0 {
+		guard.maxFailures = value
+	}
+	if value, err := time.ParseDuration(os.Getenv("MCP_AUTH_LOCKOUT")); err == nil && value > 0 {
+		guard.lockout = value
+	}
+	return guard
+}
+
+// clientIP returns the caller address; behind a trusted proxy the last X-Forwarded-For hop is used
+func (g *authGuard) clientIP(r *http.Request) string {
+	if g.trustProxy {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			hops := strings.Split(forwarded, ",")
+			return strings.TrimSpace(hops[len(hops)-1])
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// subjects returns the lockout keys of a request: its IP and, with a raw API key, the key hash.
+// The full hash is used so bad keys can't lock out a valid key that shares their prefix.
+func (g *authGuard) subjects(r *http.Request, token string) []string {
+	subjects := []string{"ip:" + g.clientIP(r)}
+	if token != "" {
+		subjects = append(subjects, "key:"+hashKey(token))
+	}
+	return subjects
+}
+
+// hashKey identifies an API key without keeping it in memory
+func hashKey(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:])
+}
+
+// lockedFor returns the remaining lockout of any of the subjects, zero when none is locked
+func (g *authGuard) lockedFor(subjects []string) time.Duration {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := time.Now()
+	var remaining time.Duration
+	for _, subject := range subjects {
+		if entry, ok := g.failures[subject]; ok && now.Before(entry.lockedUntil) {
+			if left := entry.lockedUntil.Sub(now); left > remaining {
+				remaining = left
+			}
+		}
+	}
+	if remaining > 0 {
+		g.rejectedCount.Add(1)
+	}
+	return remaining
+}
+
+// recordFailure counts a failed authentication and locks subjects that reached the limit
+func (g *authGuard) recordFailure(subjects []string) {
+	g.failureCount.Add(1)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := time.Now()
+	g.sweepLocked(now)
+	for _, subject := range subjects {
+		entry, ok := g.failures[subject]
+		if !ok || now.Sub(entry.first) > authFailureWindow {
+			entry = &authFailures{first: now}
+			g.failures[subject] = entry
+		}
+		entry.count++
+		if entry.count >= g.maxFailures && now.After(entry.lockedUntil) {
+			entry.lockedUntil = now.Add(g.lockout)
+			g.lockoutCount.Add(1)
+			fmt.Fprintf(os.Stderr, "Auth lockout: %s locked for %s after %d failures\n", subject, g.lockout, entry.count)
+		}
+	}
+}
+
+// recordSuccess clears the failure history of the key that succeeded.
+// IP failures are left to expire, otherwise one valid key would reset the IP between guesses.
+func (g *authGuard) recordSuccess(subjects []string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, subject := range subjects {
+		if strings.HasPrefix(subject, "key:") {
+			delete(g.failures, subject)
+		}
+	}
+}
+
+// sweepLocked drops expired entries at most once a minute
+func (g *authGuard) sweepLocked(now time.Time) {
+	if now.Sub(g.lastSweep) < time.Minute {
+		return
+	}
+	g.lastSweep = now
+	for subject, entry := range g.failures {
+		if now.Sub(entry.first) > authFailureWindow && now.After(entry.lockedUntil) {
+			delete(g.failures, subject)
+		}
+	}
+	for key, expires := range g.validKeys {
+		if now.After(expires) {
+			delete(g.validKeys, key)
+		}
+	}
+}
+
+// validateAPIKey checks a raw Zerops API key against the API, caching keys that worked.
+// It fails open: only a 401 or 403 from the API counts as invalid, while outages, timeouts and
+// other errors let the request through uncached. That is safe because every tool call uses the
+// key itself, so a bad key gets no further than the API; it only isn't counted as a failure.
+func (g *authGuard) validateAPIKey(ctx context.Context, apiKey string) bool {
+	cacheKey := hashKey(apiKey)
+
+	g.mu.Lock()
+	expires, ok := g.validKeys[cacheKey]
+	g.mu.Unlock()
+	if ok && time.Now().Before(expires) {
+		return true
+	}
+
+	resp, err := newZeropsClient(apiKey, g.httpClient).GetUserInfo(ctx)
+	if err != nil {
+		return !errors.Is(shared.WrapAPIError(err, "Failed to verify API key"), shared.ErrForbidden)
+	}
+	info, err := resp.Output()
+	if err != nil {
+		return !errors.Is(shared.WrapAPIError(err, "Failed to verify API key"), shared.ErrForbidden)
+	}
+	shared.StoreIdentity(apiKey, info)
+
+	g.mu.Lock()
+	g.validKeys[cacheKey] = time.Now().Add(validKeyTTL)
+	g.mu.Unlock()
+	return true
+}
+
+// serveMetrics writes authentication counters in the Prometheus text format
+func (g *authGuard) serveMetrics(w http.ResponseWriter) {
+	g.mu.Lock()
+	locked := 0
+	now := time.Now()
+	for _, entry := range g.failures {
+		if now.Before(entry.lockedUntil) {
+			locked++
+		}
+	}
+	g.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	fmt.Fprintf(w, "# HELP zerops_mcp_auth_failures_total Failed authentication attempts.\n")
+	fmt.Fprintf(w, "# TYPE zerops_mcp_auth_failures_total counter\n")
+	fmt.Fprintf(w, "zerops_mcp_auth_failures_total %d\n", g.failureCount.Load())
+	fmt.Fprintf(w, "# HELP zerops_mcp_auth_lockouts_total Lockouts applied to an IP or key.\n")
+	fmt.Fprintf(w, "# TYPE zerops_mcp_auth_lockouts_total counter\n")
+	fmt.Fprintf(w, "zerops_mcp_auth_lockouts_total %d\n", g.lockoutCount.Load())
+	fmt.Fprintf(w, "# HELP zerops_mcp_auth_rejected_total Requests rejected because of an active lockout.\n")
+	fmt.Fprintf(w, "# TYPE zerops_mcp_auth_rejected_total counter\n")
+	fmt.Fprintf(w, "zerops_mcp_auth_rejected_total %d\n", g.rejectedCount.Load())
+	fmt.Fprintf(w, "# HELP zerops_mcp_auth_locked Currently locked IPs and keys.\n")
+	fmt.Fprintf(w, "# TYPE zerops_mcp_auth_locked gauge\n")
+	fmt.Fprintf(w, "zerops_mcp_auth_locked %d\n", locked)
+}
+
+// writeAuthError writes a structured authentication error body
+func writeAuthError(w http.ResponseWriter, status int, code, message string, retryAfter time.Duration) {
+	body := map[string]interface{}{
+		"error":   code,
+		"message": message,
+	}
+	if retryAfter > 0 {
+		seconds := int(retryAfter.Round(time.Second).Seconds())
+		w.Header().Set("Retry-After", strconv.Itoa(seconds))
+		body["retry_after_seconds"] = seconds
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
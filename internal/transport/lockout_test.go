@@ -0,0 +1,195 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testAuthGuard(maxFailures int, lockout time.Duration) *authGuard {
+	return &authGuard{
+		maxFailures: maxFailures,
+		lockout:     lockout,
+		failures:    make(map[string]*authFailures),
+		validKeys:   make(map[string]time.Time),
+	}
+}
+
+func TestAuthGuardThresholds(t *testing.T) {
+	tests := []struct {
+		name         string
+		maxFailures  int
+		failures     int
+		success      bool
+		wantLocked   bool
+		wantIPLocked bool
+	}{
+		{name: "below threshold", maxFailures: 5, failures: 4},
+		{name: "at threshold", maxFailures: 5, failures: 5, wantLocked: true, wantIPLocked: true},
+		{name: "above threshold", maxFailures: 5, failures: 8, wantLocked: true, wantIPLocked: true},
+		{name: "single failure allowed", maxFailures: 1, failures: 1, wantLocked: true, wantIPLocked: true},
+		{name: "success clears key but not ip", maxFailures: 3, failures: 2, success: true, wantIPLocked: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			guard := testAuthGuard(tt.maxFailures, time.Minute)
+			ip, key := "ip:192.0.2.1", "key:"+hashKey("zrp_0123456789")
+			subjects := []string{ip, key}
+			for i := 0; i < tt.failures; i++ {
+				guard.recordFailure(subjects)
+			}
+			if tt.success {
+				guard.recordSuccess(subjects)
+				guard.recordFailure(subjects)
+			}
+			remaining := guard.lockedFor([]string{key})
+			if locked := remaining > 0; locked != tt.wantLocked {
+				t.Fatalf("key locked = %v (remaining %s), want %v", locked, remaining, tt.wantLocked)
+			}
+			if tt.wantLocked && remaining > time.Minute {
+				t.Fatalf("remaining %s exceeds the lockout duration", remaining)
+			}
+			if locked := guard.lockedFor([]string{ip}) > 0; locked != tt.wantIPLocked {
+				t.Fatalf("ip locked = %v, want %v", locked, tt.wantIPLocked)
+			}
+		})
+	}
+}
+
+func TestAuthGuardExpiry(t *testing.T) {
+	// Each case starts one failure short of the threshold; locked is the lockout left, if any
+	tests := []struct {
+		name       string
+		age        time.Duration
+		locked     time.Duration
+		failures   int
+		wantLocked bool
+	}{
+		{name: "lockout in effect", age: time.Minute, locked: time.Minute, wantLocked: true},
+		{name: "lockout expired", age: time.Minute, locked: -time.Second},
+		{name: "failures within window add up", age: time.Minute, failures: 1, wantLocked: true},
+		{name: "failures outside window are forgotten", age: authFailureWindow + time.Minute, failures: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			guard := testAuthGuard(5, time.Minute)
+			subject := "key:" + hashKey("zrp_abcd")
+			now := time.Now()
+			entry := &authFailures{count: 4, first: now.Add(-tt.age)}
+			if tt.locked != 0 {
+				entry.lockedUntil = now.Add(tt.locked)
+			}
+			guard.failures[subject] = entry
+			for i := 0; i < tt.failures; i++ {
+				guard.recordFailure([]string{subject})
+			}
+			if locked := guard.lockedFor([]string{subject}) > 0; locked != tt.wantLocked {
+				t.Fatalf("locked = %v, want %v", locked, tt.wantLocked)
+			}
+		})
+	}
+}
+
+func TestAuthGuardSubjects(t *testing.T) {
+	tests := []struct {
+		name       string
+		trustProxy bool
+		forwarded  string
+		token      string
+		want       []string
+	}{
+		{name: "ip only", want: []string{"ip:192.0.2.1"}},
+		{name: "key hash", token: "zrp_0123456789", want: []string{"ip:192.0.2.1", "key:" + hashKey("zrp_0123456789")}},
+		{name: "short key", token: "abc", want: []string{"ip:192.0.2.1", "key:" + hashKey("abc")}},
+		{name: "untrusted proxy header", forwarded: "198.51.100.7", want: []string{"ip:192.0.2.1"}},
+		{name: "trusted proxy uses last hop", trustProxy: true, forwarded: "203.0.113.9, 198.51.100.7", want: []string{"ip:198.51.100.7"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			guard := testAuthGuard(5, time.Minute)
+			guard.trustProxy = tt.trustProxy
+			r := httptest.NewRequest("POST", "/mcp", nil)
+			r.RemoteAddr = "192.0.2.1:51234"
+			if tt.forwarded != "" {
+				r.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+			got := guard.subjects(r, tt.token)
+			if len(got) != len(tt.want) {
+				t.Fatalf("subjects = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("subjects = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestAuthGuardSharedPrefix(t *testing.T) {
+	guard := testAuthGuard(2, time.Minute)
+	r := httptest.NewRequest("POST", "/mcp", nil)
+	r.RemoteAddr = "192.0.2.1:51234"
+	for i := 0; i < 2; i++ {
+		guard.recordFailure(guard.subjects(r, "zrp_0123bad"))
+	}
+
+	// A valid key with the same prefix, used from another IP, stays unlocked
+	r.RemoteAddr = "198.51.100.7:40000"
+	if remaining := guard.lockedFor(guard.subjects(r, "zrp_0123good")); remaining > 0 {
+		t.Fatalf("key sharing a prefix with bad keys is locked for %s", remaining)
+	}
+}
+
+// roundTripFunc answers the API calls of a test client without a network
+type roundTripFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestValidateAPIKeyFailsOpen(t *testing.T) {
+	const apiError = `{"error":{"code":"error","message":"failed"}}`
+	tests := []struct {
+		name      string
+		status    int
+		body      string
+		failure   bool
+		wantValid bool
+		wantCache bool
+	}{
+		{name: "accepted key", status: http.StatusOK, body: `{}`, wantValid: true, wantCache: true},
+		{name: "unauthorized key", status: http.StatusUnauthorized, body: apiError},
+		{name: "forbidden key", status: http.StatusForbidden, body: apiError},
+		{name: "api outage passes uncached", status: http.StatusServiceUnavailable, body: apiError, wantValid: true},
+		{name: "unreachable api passes uncached", failure: true, wantValid: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			guard := testAuthGuard(5, time.Minute)
+			guard.httpClient = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+				if tt.failure {
+					return nil, errors.New("connection refused")
+				}
+				return &http.Response{
+					StatusCode: tt.status,
+					Header:     http.Header{"Content-Type": {"application/json"}},
+					Body:       io.NopCloser(strings.NewReader(tt.body)),
+					Request:    r,
+				}, nil
+			})}
+
+			if valid := guard.validateAPIKey(context.Background(), "zrp_0123456789"); valid != tt.wantValid {
+				t.Fatalf("valid = %v, want %v", valid, tt.wantValid)
+			}
+			if _, cached := guard.validKeys[hashKey("zrp_0123456789")]; cached != tt.wantCache {
+				t.Fatalf("cached = %v, want %v", cached, tt.wantCache)
+			}
+		})
+	}
+}
@@ -0,0 +1,348 @@
+package transport
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+)
+
+// protectedResourcePath serves the OAuth protected resource metadata (RFC 9728)
+const protectedResourcePath = "/.well-known/oauth-protected-resource"
+
+// introspectionCacheTTL bounds how long an introspection result is reused
+const introspectionCacheTTL = time.Minute
+
+// maxCachedTokens caps the introspection cache, since every client can present new tokens.
+// Expired entries are only evicted once the cap is reached.
+const maxCachedTokens = 10000
+
+var (
+	errInvalidToken = errors.New("invalid or expired access token")
+	errNoAPIKey     = errors.New("no Zerops API key is registered for this identity")
+)
+
+// OAuthConfig configures OAuth 2.1 bearer token validation for the HTTP transport.
+// The server acts as a resource server: tokens are validated by introspection
+// at the identity provider and the token subject is mapped to a Zerops API key.
+type OAuthConfig struct {
+	// Issuer is the authorization server advertised to clients
+	Issuer string
+	// IntrospectionURL is the RFC 7662 token introspection endpoint
+	IntrospectionURL string
+	ClientID         string
+	ClientSecret     string
+	// Audience must be present in the token's aud claim; defaults to Resource
+	Audience string
+	// Resource is this server's canonical URL; derived from the request when empty
+	Resource string
+	// KeyVaultPath is an encrypted JSON file mapping token subjects to Zerops API keys
+	KeyVaultPath string
+}
+
+// LoadOAuthConfigFromEnv returns the OAuth configuration, nil when MCP_OAUTH_ISSUER is not set
+func LoadOAuthConfigFromEnv() (*OAuthConfig, error) {
+	issuer := os.Getenv("MCP_OAUTH_ISSUER")
+	if issuer == "" {
+		return nil, nil
+	}
+
+	config := &OAuthConfig{
+		Issuer:           strings.TrimSuffix(issuer, "/"),
+		IntrospectionURL: os.Getenv("MCP_OAUTH_INTROSPECTION_URL"),
+		ClientID:         os.Getenv("MCP_OAUTH_CLIENT_ID"),
+		ClientSecret:     os.Getenv("MCP_OAUTH_CLIENT_SECRET"),
+		Audience:         os.Getenv("MCP_OAUTH_AUDIENCE"),
+		Resource:         os.Getenv("MCP_OAUTH_RESOURCE"),
+		KeyVaultPath:     os.Getenv("MCP_OAUTH_KEY_VAULT"),
+	}
+	if config.IntrospectionURL == "" {
+		return nil, fmt.Errorf("MCP_OAUTH_INTROSPECTION_URL is required when MCP_OAUTH_ISSUER is set")
+	}
+	if config.KeyVaultPath == "" {
+		return nil, fmt.Errorf("MCP_OAUTH_KEY_VAULT is required when MCP_OAUTH_ISSUER is set")
+	}
+	// Tokens must be issued for this server, not just by the same authorization server
+	if config.Audience == "" {
+		config.Audience = strings.TrimSuffix(config.Resource, "/")
+	}
+	if config.Audience == "" {
+		return nil, fmt.Errorf("MCP_OAUTH_RESOURCE or MCP_OAUTH_AUDIENCE is required when MCP_OAUTH_ISSUER is set")
+	}
+	return config, nil
+}
+
+// introspection is the subset of an RFC 7662 response used for authorization
+type introspection struct {
+	Active   bool            `json:"active"`
+	Subject  string          `json:"sub"`
+	Audience json.RawMessage `json:"aud"`
+	Expiry   int64           `json:"exp"`
+}
+
+// cachedIdentity is a recent introspection result and the subject's API key, so neither the
+// identity provider nor the key vault is asked again until it expires
+type cachedIdentity struct {
+	subject string
+	// apiKey is empty until it was read from the vault
+	apiKey  string
+	expires time.Time
+}
+
+// oauthAuthenticator validates access tokens and resolves the caller's Zerops API key
+type oauthAuthenticator struct {
+	config     OAuthConfig
+	httpClient *http.Client
+
+	mu    sync.Mutex
+	cache map[string]cachedIdentity
+
+	// vaultMu serializes key vault rewrites
+	vaultMu sync.Mutex
+}
+
+func newOAuthAuthenticator(config OAuthConfig) *oauthAuthenticator {
+	shared.RegisterCredentialStore("oauth_key_vault", config.KeyVaultPath)
+	return &oauthAuthenticator{
+		config:     config,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		cache:      make(map[string]cachedIdentity),
+	}
+}
+
+// Authenticate validates the access token and returns the Zerops API key of its subject
+func (a *oauthAuthenticator) Authenticate(ctx context.Context, token string) (apiKey, subject string, err error) {
+	cacheKey := tokenCacheKey(token)
+	a.mu.Lock()
+	cached, ok := a.cache[cacheKey]
+	a.mu.Unlock()
+	if ok && cached.apiKey != "" && time.Now().Before(cached.expires) {
+		return cached.apiKey, cached.subject, nil
+	}
+
+	subject, err = a.subject(ctx, token)
+	if err != nil {
+		return "", "", err
+	}
+
+	apiKey, err = a.lookupAPIKey(subject)
+	if err != nil {
+		return "", subject, err
+	}
+	a.mu.Lock()
+	if cached, ok := a.cache[cacheKey]; ok && cached.subject == subject {
+		cached.apiKey = apiKey
+		a.cache[cacheKey] = cached
+	}
+	a.mu.Unlock()
+	return apiKey, subject, nil
+}
+
+// tokenCacheKey keys the cache by a hash, so tokens aren't kept in memory
+func tokenCacheKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// subject introspects the token, reusing recent results
+func (a *oauthAuthenticator) subject(ctx context.Context, token string) (string, error) {
+	cacheKey := tokenCacheKey(token)
+
+	a.mu.Lock()
+	if cached, ok := a.cache[cacheKey]; ok && time.Now().Before(cached.expires) {
+		a.mu.Unlock()
+		return cached.subject, nil
+	}
+	a.mu.Unlock()
+
+	form := url.Values{"token": {token}, "token_type_hint": {"access_token"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.config.IntrospectionURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if a.config.ClientID != "" {
+		req.SetBasicAuth(a.config.ClientID, a.config.ClientSecret)
+	}
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("token introspection failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token introspection failed: %s", resp.Status)
+	}
+
+	var result introspection
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("token introspection failed: %w", err)
+	}
+	if !result.Active || result.Subject == "" {
+		return "", errInvalidToken
+	}
+	if !audienceContains(result.Audience, a.config.Audience) {
+		return "", errInvalidToken
+	}
+
+	expires := time.Now().Add(introspectionCacheTTL)
+	if result.Expiry > 0 {
+		if tokenExpiry := time.Unix(result.Expiry, 0); tokenExpiry.Before(expires) {
+			expires = tokenExpiry
+		}
+	}
+
+	a.mu.Lock()
+	a.cacheLocked(cacheKey, cachedIdentity{subject: result.Subject, expires: expires})
+	a.mu.Unlock()
+
+	return result.Subject, nil
+}
+
+// cacheLocked stores a successful introspection. A full cache drops its expired entries and,
+// when still full, arbitrary others; the caller must hold a.mu
+func (a *oauthAuthenticator) cacheLocked(cacheKey string, identity cachedIdentity) {
+	now := time.Now()
+	if !now.Before(identity.expires) {
+		return
+	}
+	if _, ok := a.cache[cacheKey]; !ok && len(a.cache) >= maxCachedTokens {
+		for key, cached := range a.cache {
+			if !now.Before(cached.expires) {
+				delete(a.cache, key)
+			}
+		}
+		for key := range a.cache {
+			if len(a.cache) < maxCachedTokens {
+				break
+			}
+			delete(a.cache, key)
+		}
+	}
+	a.cache[cacheKey] = identity
+}
+
+// lookupAPIKey reads the subject's Zerops API key from the encrypted key vault. Keys are
+// cached with the token's introspection result, so the vault is read when a token is first
+// seen or its cache entry expired, and keys added to the vault apply without a restart.
+func (a *oauthAuthenticator) lookupAPIKey(subject string) (string, error) {
+	data, sealed, err := shared.ReadSealedFile(a.config.KeyVaultPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read key vault: %w", err)
+	}
+	if !sealed {
+		return "", fmt.Errorf("key vault %s is not encrypted; run zerops-mcp --seal-file %s", a.config.KeyVaultPath, a.config.KeyVaultPath)
+	}
+
+	var vault map[string]string
+	if err := json.Unmarshal(data, &vault); err != nil {
+		return "", fmt.Errorf("failed to parse key vault: %w", err)
+	}
+
+	apiKey := vault[subject]
+	if apiKey == "" {
+		return "", errNoAPIKey
+	}
+	return apiKey, nil
+}
+
+// storeAPIKey replaces the subject's Zerops API key in the key vault
+func (a *oauthAuthenticator) storeAPIKey(subject, apiKey string) error {
+	a.vaultMu.Lock()
+	defer a.vaultMu.Unlock()
+
+	data, sealed, err := shared.ReadSealedFile(a.config.KeyVaultPath)
+	if err != nil {
+		return fmt.Errorf("failed to read key vault: %w", err)
+	}
+	if !sealed {
+		return fmt.Errorf("key vault %s is not encrypted; run zerops-mcp --seal-file %s", a.config.KeyVaultPath, a.config.KeyVaultPath)
+	}
+
+	var vault map[string]string
+	if err := json.Unmarshal(data, &vault); err != nil {
+		return fmt.Errorf("failed to parse key vault: %w", err)
+	}
+	if vault == nil {
+		vault = map[string]string{}
+	}
+	vault[subject] = apiKey
+
+	data, err = json.Marshal(vault)
+	if err != nil {
+		return err
+	}
+	if err := shared.WriteSealedFile(a.config.KeyVaultPath, data); err != nil {
+		return err
+	}
+
+	// Tokens of the subject switch to the new key right away
+	a.mu.Lock()
+	for key, cached := range a.cache {
+		if cached.subject == subject {
+			cached.apiKey = apiKey
+			a.cache[key] = cached
+		}
+	}
+	a.mu.Unlock()
+	return nil
+}
+
+// resourceURL returns the canonical URL of this server
+func (a *oauthAuthenticator) resourceURL(r *http.Request) string {
+	if a.config.Resource != "" {
+		return a.config.Resource
+	}
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
+
+// serveMetadata writes the protected resource metadata document
+func (a *oauthAuthenticator) serveMetadata(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"resource":                 a.resourceURL(r),
+		"authorization_servers":    []string{a.config.Issuer},
+		"bearer_methods_supported": []string{"header"},
+	})
+}
+
+// challenge sets the header pointing the client at the metadata document
+func (a *oauthAuthenticator) challenge(w http.ResponseWriter, r *http.Request, invalidToken bool) {
+	header := fmt.Sprintf(`Bearer resource_metadata="%s%s"`, a.resourceURL(r), protectedResourcePath)
+	if invalidToken {
+		header += `, error="invalid_token"`
+	}
+	w.Header().Set("WWW-Authenticate", header)
+}
+
+// audienceContains checks a string or string-array aud claim
+func audienceContains(raw json.RawMessage, audience string) bool {
+	var single string
+	if err := json.Unmarshal(raw, &single); err == nil {
+		return single == audience
+	}
+	var list []string
+	if err := json.Unmarshal(raw, &list); err == nil {
+		for _, item := range list {
+			if item == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
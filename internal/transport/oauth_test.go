@@ -0,0 +1,255 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestOAuthSubject(t *testing.T) {
+	const audience = "https://mcp.example.com"
+	tests := []struct {
+		name     string
+		response map[string]interface{}
+		status   int
+		wantSub  string
+		wantErr  error
+	}{
+		{
+			name:     "active with matching audience",
+			response: map[string]interface{}{"active": true, "sub": "user-1", "aud": audience},
+			wantSub:  "user-1",
+		},
+		{
+			name:     "audience in list",
+			response: map[string]interface{}{"active": true, "sub": "user-1", "aud": []string{"https://other.example.com", audience}},
+			wantSub:  "user-1",
+		},
+		{
+			name:     "inactive token",
+			response: map[string]interface{}{"active": false, "sub": "user-1", "aud": audience},
+			wantErr:  errInvalidToken,
+		},
+		{
+			name:     "missing subject",
+			response: map[string]interface{}{"active": true, "aud": audience},
+			wantErr:  errInvalidToken,
+		},
+		{
+			name:     "other audience",
+			response: map[string]interface{}{"active": true, "sub": "user-1", "aud": "https://other.example.com"},
+			wantErr:  errInvalidToken,
+		},
+		{
+			name:     "audience not in list",
+			response: map[string]interface{}{"active": true, "sub": "user-1", "aud": []string{"https://other.example.com"}},
+			wantErr:  errInvalidToken,
+		},
+		{
+			name:     "missing audience",
+			response: map[string]interface{}{"active": true, "sub": "user-1"},
+			wantErr:  errInvalidToken,
+		},
+		{
+			name:     "introspection endpoint fails",
+			response: map[string]interface{}{},
+			status:   http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if err := r.ParseForm(); err != nil || r.PostForm.Get("token") != "token-1" {
+					t.Errorf("introspection request without the token")
+				}
+				if tt.status != 0 {
+					w.WriteHeader(tt.status)
+					return
+				}
+				json.NewEncoder(w).Encode(tt.response)
+			}))
+			defer server.Close()
+
+			authenticator := &oauthAuthenticator{
+				config:     OAuthConfig{IntrospectionURL: server.URL, Audience: audience},
+				httpClient: server.Client(),
+				cache:      make(map[string]cachedIdentity),
+			}
+			subject, err := authenticator.subject(context.Background(), "token-1")
+			switch {
+			case tt.status != 0:
+				if err == nil {
+					t.Fatalf("subject = %q, want an error", subject)
+				}
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+			default:
+				if err != nil || subject != tt.wantSub {
+					t.Fatalf("subject = %q, %v, want %q", subject, err, tt.wantSub)
+				}
+			}
+		})
+	}
+}
+
+func TestOAuthSubjectCachesUntilExpiry(t *testing.T) {
+	const audience = "https://mcp.example.com"
+	tests := []struct {
+		name      string
+		expiry    time.Duration
+		wantCalls int
+	}{
+		{name: "reused while valid", expiry: time.Hour, wantCalls: 1},
+		{name: "expired token is introspected again", expiry: -time.Second, wantCalls: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"active": true, "sub": "user-1", "aud": audience, "exp": time.Now().Add(tt.expiry).Unix(),
+				})
+			}))
+			defer server.Close()
+
+			authenticator := &oauthAuthenticator{
+				config:     OAuthConfig{IntrospectionURL: server.URL, Audience: audience},
+				httpClient: server.Client(),
+				cache:      make(map[string]cachedIdentity),
+			}
+			for i := 0; i < 2; i++ {
+				if _, err := authenticator.subject(context.Background(), "token-1"); err != nil {
+					t.Fatalf("subject: %v", err)
+				}
+			}
+			if calls != tt.wantCalls {
+				t.Fatalf("introspection calls = %d, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestOAuthSubjectCacheEviction(t *testing.T) {
+	const audience = "https://mcp.example.com"
+	tests := []struct {
+		name         string
+		expired      int
+		valid        int
+		wantLen      int
+		keepsExpired bool
+	}{
+		{name: "cache below the cap is left alone", expired: 10, valid: 5, wantLen: 16, keepsExpired: true},
+		{name: "full cache stays capped", valid: maxCachedTokens, wantLen: maxCachedTokens},
+		{name: "expired entries go first in a full cache", expired: 10, valid: maxCachedTokens - 10, wantLen: maxCachedTokens - 9},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				json.NewEncoder(w).Encode(map[string]interface{}{"active": true, "sub": "user-1", "aud": audience})
+			}))
+			defer server.Close()
+
+			authenticator := &oauthAuthenticator{
+				config:     OAuthConfig{IntrospectionURL: server.URL, Audience: audience},
+				httpClient: server.Client(),
+				cache:      make(map[string]cachedIdentity),
+			}
+			now := time.Now()
+			for i := 0; i < tt.expired; i++ {
+				authenticator.cache[fmt.Sprintf("expired-%d", i)] = cachedIdentity{subject: "old", expires: now.Add(-time.Second)}
+			}
+			for i := 0; i < tt.valid; i++ {
+				authenticator.cache[fmt.Sprintf("valid-%d", i)] = cachedIdentity{subject: "user", expires: now.Add(time.Minute)}
+			}
+
+			if _, err := authenticator.subject(context.Background(), "token-1"); err != nil {
+				t.Fatalf("subject: %v", err)
+			}
+			if len(authenticator.cache) != tt.wantLen {
+				t.Fatalf("cache holds %d entries, want %d", len(authenticator.cache), tt.wantLen)
+			}
+			if tt.keepsExpired {
+				return
+			}
+			for key, cached := range authenticator.cache {
+				if !now.Before(cached.expires) {
+					t.Fatalf("expired entry %s was kept", key)
+				}
+			}
+		})
+	}
+}
+
+func TestOAuthAuthenticateCachesAPIKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		cached  cachedIdentity
+		wantKey string
+		wantErr bool
+	}{
+		{name: "cached key skips the vault", cached: cachedIdentity{subject: "user-1", apiKey: "key-1", expires: time.Now().Add(time.Minute)}, wantKey: "key-1"},
+		{name: "entry without a key reads the vault", cached: cachedIdentity{subject: "user-1", expires: time.Now().Add(time.Minute)}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The vault doesn't exist, so only a cached key can authenticate
+			authenticator := &oauthAuthenticator{
+				config: OAuthConfig{KeyVaultPath: filepath.Join(t.TempDir(), "vault.json")},
+				cache:  map[string]cachedIdentity{tokenCacheKey("token-1"): tt.cached},
+			}
+			apiKey, subject, err := authenticator.Authenticate(context.Background(), "token-1")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("api key = %q, want an error", apiKey)
+				}
+				return
+			}
+			if err != nil || apiKey != tt.wantKey || subject != tt.cached.subject {
+				t.Fatalf("Authenticate = %q, %q, %v, want %q, %q", apiKey, subject, err, tt.wantKey, tt.cached.subject)
+			}
+		})
+	}
+}
+
+func TestLoadOAuthConfigAudience(t *testing.T) {
+	tests := []struct {
+		name         string
+		audience     string
+		resource     string
+		wantAudience string
+		wantErr      bool
+	}{
+		{name: "explicit audience", audience: "api://zerops-mcp", resource: "https://mcp.example.com", wantAudience: "api://zerops-mcp"},
+		{name: "defaults to resource", resource: "https://mcp.example.com/", wantAudience: "https://mcp.example.com"},
+		{name: "neither set", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("MCP_OAUTH_ISSUER", "https://auth.example.com")
+			t.Setenv("MCP_OAUTH_INTROSPECTION_URL", "https://auth.example.com/introspect")
+			t.Setenv("MCP_OAUTH_KEY_VAULT", "/tmp/vault.json")
+			t.Setenv("MCP_OAUTH_AUDIENCE", tt.audience)
+			t.Setenv("MCP_OAUTH_RESOURCE", tt.resource)
+
+			config, err := LoadOAuthConfigFromEnv()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("config = %+v, want an error", config)
+				}
+				return
+			}
+			if err != nil || config.Audience != tt.wantAudience {
+				t.Fatalf("audience = %v, %v, want %q", config, err, tt.wantAudience)
+			}
+		})
+	}
+}
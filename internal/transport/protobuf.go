@@ -0,0 +1,223 @@
+package transport
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"sort"
+)
+
+// A minimal protobuf codec for the messages of the gRPC facade (api/zeropsmcp/v1/tools.proto).
+// Tool arguments and results are google.protobuf.Struct and Value, which map one to one to
+// the JSON values tool handlers work with.
+
+// Protobuf wire types
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+var errMalformedProtobuf = errors.New("malformed protobuf message")
+
+// protoField is one field of a decoded message; data holds the payload of length-delimited
+// fields, value the number of the others
+type protoField struct {
+	number int
+	wire   int
+	value  uint64
+	data   []byte
+}
+
+func appendTag(b []byte, number, wire int) []byte {
+	return binary.AppendUvarint(b, uint64(number)<<3|uint64(wire))
+}
+
+func appendBytesField(b []byte, number int, data []byte) []byte {
+	b = appendTag(b, number, wireBytes)
+	b = binary.AppendUvarint(b, uint64(len(data)))
+	return append(b, data...)
+}
+
+func appendStringField(b []byte, number int, value string) []byte {
+	if value == "" {
+		return b
+	}
+	return appendBytesField(b, number, []byte(value))
+}
+
+func appendBoolField(b []byte, number int, value bool) []byte {
+	if !value {
+		return b
+	}
+	b = appendTag(b, number, wireVarint)
+	return append(b, 1)
+}
+
+// decodeFields splits a message into its fields
+func decodeFields(b []byte) ([]protoField, error) {
+	var fields []protoField
+	for len(b) > 0 {
+		tag, n := binary.Uvarint(b)
+		if n <= 0 {
+			return nil, errMalformedProtobuf
+		}
+		b = b[n:]
+		field := protoField{number: int(tag >> 3), wire: int(tag & 7)}
+		switch field.wire {
+		case wireVarint:
+			field.value, n = binary.Uvarint(b)
+			if n <= 0 {
+				return nil, errMalformedProtobuf
+			}
+			b = b[n:]
+		case wireFixed64:
+			if len(b) < 8 {
+				return nil, errMalformedProtobuf
+			}
+			field.value = binary.LittleEndian.Uint64(b)
+			b = b[8:]
+		case wireFixed32:
+			if len(b) < 4 {
+				return nil, errMalformedProtobuf
+			}
+			field.value = uint64(binary.LittleEndian.Uint32(b))
+			b = b[4:]
+		case wireBytes:
+			length, n := binary.Uvarint(b)
+			if n <= 0 || uint64(len(b)-n) < length {
+				return nil, errMalformedProtobuf
+			}
+			field.data = b[n : n+int(length)]
+			b = b[n+int(length):]
+		default:
+			return nil, errMalformedProtobuf
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// encodeValue encodes a JSON value as google.protobuf.Value
+func encodeValue(value interface{}) []byte {
+	var b []byte
+	switch v := value.(type) {
+	case nil:
+		b = appendTag(b, 1, wireVarint)
+		b = append(b, 0)
+	case float64:
+		b = appendTag(b, 2, wireFixed64)
+		b = binary.LittleEndian.AppendUint64(b, math.Float64bits(v))
+	case string:
+		b = appendBytesField(b, 3, []byte(v))
+	case bool:
+		b = appendTag(b, 4, wireVarint)
+		if v {
+			b = append(b, 1)
+		} else {
+			b = append(b, 0)
+		}
+	case map[string]interface{}:
+		b = appendBytesField(b, 5, encodeStruct(v))
+	case []interface{}:
+		var list []byte
+		for _, item := range v {
+			list = appendBytesField(list, 1, encodeValue(item))
+		}
+		b = appendBytesField(b, 6, list)
+	}
+	return b
+}
+
+// encodeStruct encodes a JSON object as google.protobuf.Struct, keys sorted
+func encodeStruct(object map[string]interface{}) []byte {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b []byte
+	for _, key := range keys {
+		entry := appendBytesField(nil, 1, []byte(key))
+		entry = appendBytesField(entry, 2, encodeValue(object[key]))
+		b = appendBytesField(b, 1, entry)
+	}
+	return b
+}
+
+// decodeValue decodes a google.protobuf.Value to a JSON value
+func decodeValue(b []byte) (interface{}, error) {
+	fields, err := decodeFields(b)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	for _, field := range fields {
+		switch field.number {
+		case 1:
+			value = nil
+		case 2:
+			value = math.Float64frombits(field.value)
+		case 3:
+			value = string(field.data)
+		case 4:
+			value = field.value != 0
+		case 5:
+			if value, err = decodeStruct(field.data); err != nil {
+				return nil, err
+			}
+		case 6:
+			items, err := decodeFields(field.data)
+			if err != nil {
+				return nil, err
+			}
+			list := make([]interface{}, 0, len(items))
+			for _, item := range items {
+				if item.number != 1 || item.wire != wireBytes {
+					continue
+				}
+				decoded, err := decodeValue(item.data)
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, decoded)
+			}
+			value = list
+		}
+	}
+	return value, nil
+}
+
+// decodeStruct decodes a google.protobuf.Struct to a JSON object
+func decodeStruct(b []byte) (map[string]interface{}, error) {
+	fields, err := decodeFields(b)
+	if err != nil {
+		return nil, err
+	}
+	object := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		if field.number != 1 || field.wire != wireBytes {
+			continue
+		}
+		entry, err := decodeFields(field.data)
+		if err != nil {
+			return nil, err
+		}
+		var key string
+		var value interface{}
+		for _, part := range entry {
+			switch {
+			case part.number == 1 && part.wire == wireBytes:
+				key = string(part.data)
+			case part.number == 2 && part.wire == wireBytes:
+				if value, err = decodeValue(part.data); err != nil {
+					return nil, err
+				}
+			}
+		}
+		object[key] = value
+	}
+	return object, nil
+}
@@ -0,0 +1,206 @@
+package transport
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+)
+
+// The REST facade lets curl users and low-code platforms call tools without MCP:
+// POST /tools/{name} with the arguments as a JSON object. GET /openapi.json describes
+// every tool, generated from the tool schemas.
+
+const (
+	restToolsPath   = "/tools"
+	restOpenAPIPath = "/openapi.json"
+	// restAPIVersion is the version of the REST facade's shape, not of the server
+	restAPIVersion = "1"
+	// restMaxBody caps argument bodies
+	restMaxBody = 4 << 20
+)
+
+// restStatuses maps tool error codes to HTTP statuses
+var restStatuses = map[string]int{
+	shared.CodeNotFound:        http.StatusNotFound,
+	shared.CodeInvalidArgument: http.StatusBadRequest,
+	shared.CodeAPIUnavailable:  http.StatusServiceUnavailable,
+	shared.CodeForbidden:       http.StatusForbidden,
+	shared.CodeConflict:        http.StatusConflict,
+	shared.CodeBudgetExceeded:  http.StatusTooManyRequests,
+	shared.CodeInternal:        http.StatusInternalServerError,
+}
+
+// serveREST handles the REST facade and reports whether r was one of its requests
+func (h *HTTPHandler) serveREST(w http.ResponseWriter, r *http.Request, entry *accessEntry) bool {
+	switch {
+	case r.URL.Path == restOpenAPIPath:
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return true
+		}
+		writeRESTJSON(w, http.StatusOK, openAPIDocument(shared.GlobalRegistry.List()))
+		return true
+
+	case r.URL.Path == restToolsPath:
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return true
+		}
+		if _, _, ok := h.authenticate(w, r); !ok {
+			return true
+		}
+		entry.setRequest(map[string]interface{}{"method": "tools/list"}, false)
+		writeRESTJSON(w, http.StatusOK, map[string]interface{}{"tools": h.getRegisteredTools(false)})
+		return true
+
+	case strings.HasPrefix(r.URL.Path, restToolsPath+"/"):
+		name := strings.TrimPrefix(r.URL.Path, restToolsPath+"/")
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return true
+		}
+		// Browsers send form and text/plain bodies cross-site without a preflight
+		if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mediaType != "application/json" {
+			writeRESTError(w, http.StatusUnsupportedMediaType, shared.CodeInvalidArgument, "Content-Type must be application/json", nil)
+			return true
+		}
+		apiKey, subject, ok := h.authenticate(w, r)
+		if !ok {
+			return true
+		}
+
+		arguments := map[string]interface{}{}
+		data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, restMaxBody))
+		if err != nil {
+			writeRESTError(w, http.StatusBadRequest, shared.CodeInvalidArgument, "Failed to read request body", nil)
+			return true
+		}
+		if strings.TrimSpace(string(data)) != "" {
+			if err := json.Unmarshal(data, &arguments); err != nil || arguments == nil {
+				writeRESTError(w, http.StatusBadRequest, shared.CodeInvalidArgument, "The body must be a JSON object with the tool arguments", nil)
+				return true
+			}
+		}
+		entry.setRequest(map[string]interface{}{
+			"method": "tools/call",
+			"params": map[string]interface{}{"name": name, "arguments": arguments},
+		}, h.accessLog.body)
+
+		ctx := h.requestContext(r, apiKey, subject, entry)
+		result, err := shared.GlobalRegistry.CallTool(ctx, name, arguments)
+		if errors.Is(err, shared.ErrToolNotFound) {
+			writeRESTError(w, http.StatusNotFound, shared.CodeNotFound, "Unknown tool '"+name+"'; GET /tools lists them", nil)
+			return true
+		}
+		if err != nil {
+			code := shared.ErrorCode(err)
+			writeRESTError(w, restStatuses[code], code, err.Error(), shared.APIErrorDetails(err))
+			return true
+		}
+		writeRESTJSON(w, http.StatusOK, result)
+		return true
+	}
+	return false
+}
+
+// openAPIDocument describes the REST facade of tools as an OpenAPI 3.1 document; tool input
+// schemas are JSON Schema, so they are used as request bodies as they are
+func openAPIDocument(tools []*shared.ToolDefinition) map[string]interface{} {
+	errorResponse := map[string]interface{}{
+		"description": "Tool error",
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{
+				"schema": map[string]interface{}{"$ref": "#/components/schemas/Error"},
+			},
+		},
+	}
+
+	paths := map[string]interface{}{}
+	for _, tool := range tools {
+		operation := map[string]interface{}{
+			"operationId": tool.Name,
+			"summary":     tool.DescriptionFor(true),
+			"description": tool.Description,
+			"requestBody": map[string]interface{}{
+				"required": false,
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{"schema": tool.InputSchema},
+				},
+			},
+			"responses": map[string]interface{}{
+				"200": map[string]interface{}{
+					"description": "Tool result",
+					"content": map[string]interface{}{
+						"application/json": map[string]interface{}{"schema": map[string]interface{}{}},
+					},
+				},
+				"400": errorResponse,
+				"401": map[string]interface{}{"description": "Missing or invalid Bearer token"},
+				"404": errorResponse,
+				"409": errorResponse,
+				"429": errorResponse,
+				"503": errorResponse,
+			},
+		}
+		if tool.Annotations != nil {
+			operation["x-read-only"] = tool.Annotations.ReadOnly
+			operation["x-destructive"] = tool.Annotations.Destructive
+			operation["x-idempotent"] = tool.Annotations.Idempotent
+		}
+		paths[restToolsPath+"/"+tool.Name] = map[string]interface{}{"post": operation}
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.1.0",
+		"info": map[string]interface{}{
+			"title":       "Zerops MCP tools",
+			"version":     restAPIVersion,
+			"description": "The tools of the Zerops MCP server as plain HTTP endpoints. Each takes the tool arguments as a JSON object.",
+		},
+		"servers":  []map[string]interface{}{{"url": "/"}},
+		"security": []map[string]interface{}{{"bearerAuth": []string{}}},
+		"paths":    paths,
+		"components": map[string]interface{}{
+			"securitySchemes": map[string]interface{}{
+				"bearerAuth": map[string]interface{}{
+					"type":        "http",
+					"scheme":      "bearer",
+					"description": "Zerops API key, or an OAuth access token when the server uses OAuth",
+				},
+			},
+			"schemas": map[string]interface{}{
+				"Error": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"error":   map[string]interface{}{"type": "string", "description": "Error code, e.g. NOT_FOUND"},
+						"message": map[string]interface{}{"type": "string"},
+						"details": map[string]interface{}{"type": "object", "description": "The Zerops API error, when the API rejected the request"},
+					},
+					"required": []string{"error", "message"},
+				},
+			},
+		},
+	}
+}
+
+func writeRESTJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
+
+func writeRESTError(w http.ResponseWriter, status int, code, message string, details map[string]interface{}) {
+	body := map[string]interface{}{
+		"error":   code,
+		"message": message,
+	}
+	if details != nil {
+		body["details"] = details
+	}
+	writeRESTJSON(w, status, body)
+}
@@ -0,0 +1,278 @@
+package transport
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+)
+
+// logLevels orders MCP logging levels by severity
+var logLevels = map[string]int{
+	"debug":     0,
+	"info":      1,
+	"notice":    2,
+	"warning":   3,
+	"error":     4,
+	"critical":  5,
+	"alert":     6,
+	"emergency": 7,
+}
+
+// Every initialize starts a new session, so per-session values expire after sessionEntryTTL
+// without use and at most maxSessionEntries are kept, the least recently used going first
+const (
+	sessionEntryTTL   = 24 * time.Hour
+	maxSessionEntries = 10000
+)
+
+// sessionEntry is a per-session value and when it was last used
+type sessionEntry struct {
+	Value    string    `json:"value"`
+	LastUsed time.Time `json:"last_used"`
+}
+
+// sessionValues keeps a string value, such as the logging/setLevel level, for each session.
+// HTTP has no connection state, so a session is identified by requestSession.
+type sessionValues struct {
+	mu     sync.Mutex
+	values map[string]sessionEntry
+}
+
+func (s *sessionValues) get(session string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.values[session]
+	if !ok {
+		return ""
+	}
+	now := time.Now()
+	if now.Sub(entry.LastUsed) > sessionEntryTTL {
+		delete(s.values, session)
+		return ""
+	}
+	entry.LastUsed = now
+	s.values[session] = entry
+	return entry.Value
+}
+
+func (s *sessionValues) set(session, value string) {
+	now := time.Now()
+	s.mu.Lock()
+	s.values[session] = sessionEntry{Value: value, LastUsed: now}
+	s.pruneLocked(now)
+	s.mu.Unlock()
+	shared.SessionStateChanged()
+}
+
+// pruneLocked drops expired entries and the least recently used ones over the cap
+func (s *sessionValues) pruneLocked(now time.Time) {
+	for session, entry := range s.values {
+		if now.Sub(entry.LastUsed) > sessionEntryTTL {
+			delete(s.values, session)
+		}
+	}
+	for len(s.values) > maxSessionEntries {
+		oldest := ""
+		for session, entry := range s.values {
+			if oldest == "" || entry.LastUsed.Before(s.values[oldest].LastUsed) {
+				oldest = session
+			}
+		}
+		delete(s.values, oldest)
+	}
+}
+
+// requestSession identifies the MCP session of a request; its log level, client name and
+// preferences are kept per session. sessionID is the Mcp-Session-Id issued on initialize.
+// Sessions are scoped to the API key owner, so a client can't reach another user's state by
+// sending their session ID; requests without one share the owner's session.
+func requestSession(apiKey, sessionID string) string {
+	owner := "owner:" + shared.OwnerID(apiKey)
+	if sessionID == "" {
+		return owner
+	}
+	return owner + "/session:" + sessionID
+}
+
+// newSessionID returns a random Mcp-Session-Id for an initialize response
+func newSessionID() (string, error) {
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("failed to generate session ID: %w", err)
+	}
+	return hex.EncodeToString(raw), nil
+}
+
+// acceptsEventStream reports whether the client can receive an SSE response
+func acceptsEventStream(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+}
+
+// followsLogs reports whether a tools/call asks to follow logs; the streamed lines are
+// info log messages, so such calls stream even before the client set a level
+func followsLogs(params map[string]interface{}) bool {
+	arguments, _ := params["arguments"].(map[string]interface{})
+	follow, _ := arguments["follow"].(bool)
+	return follow
+}
+
+// eventStream writes JSON-RPC messages as server-sent events on a POST response.
+// Writes after close are dropped, since tools may hold on to the context.
+type eventStream struct {
+	mu      sync.Mutex
+	w       http.ResponseWriter
+	flusher http.Flusher
+	closed  bool
+}
+
+func newEventStream(w http.ResponseWriter) *eventStream {
+	flusher, _ := w.(http.Flusher)
+	stream := &eventStream{w: w, flusher: flusher}
+	stream.start()
+	return stream
+}
+
+// start writes the SSE response headers
+func (s *eventStream) start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.w.Header().Set("Content-Type", "text/event-stream")
+	s.w.Header().Set("Cache-Control", "no-cache")
+	s.w.WriteHeader(http.StatusOK)
+	if s.flusher != nil {
+		s.flusher.Flush()
+	}
+}
+
+// send writes one JSON-RPC message as an SSE event
+func (s *eventStream) send(message map[string]interface{}) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return fmt.Errorf("stream closed")
+	}
+	if _, err := fmt.Fprintf(s.w, "event: message\ndata: %s\n\n", data); err != nil {
+		return err
+	}
+	if s.flusher != nil {
+		s.flusher.Flush()
+	}
+	return nil
+}
+
+// close sends the final response and stops further writes
+func (s *eventStream) close(response map[string]interface{}) {
+	s.send(response)
+	s.stop()
+}
+
+// stop drops further writes
+func (s *eventStream) stop() {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+}
+
+// ping writes an SSE comment, so proxies don't drop an idle stream
+func (s *eventStream) ping() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return fmt.Errorf("stream closed")
+	}
+	if _, err := fmt.Fprint(s.w, ": ping\n\n"); err != nil {
+		return err
+	}
+	if s.flusher != nil {
+		s.flusher.Flush()
+	}
+	return nil
+}
+
+// sessionStreams keeps the event stream a session opened with GET, the only way to push
+// messages that don't answer a request, such as notifications/resources/updated
+type sessionStreams struct {
+	mu      sync.Mutex
+	streams map[string]*eventStream
+}
+
+// open registers the stream of session; a session has at most one
+func (s *sessionStreams) open(session string, stream *eventStream) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.streams[session]; ok {
+		return false
+	}
+	s.streams[session] = stream
+	return true
+}
+
+func (s *sessionStreams) get(session string) *eventStream {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.streams[session]
+}
+
+func (s *sessionStreams) remove(session string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.streams, session)
+}
+
+// withStreamNotifications routes log messages at or above minLevel and progress
+// notifications of the call to the stream
+func withStreamNotifications(ctx context.Context, stream *eventStream, minLevel string, progressToken interface{}) context.Context {
+	if minLevel != "" {
+		ctx = shared.WithLogger(ctx, func(ctx context.Context, level, logger string, data interface{}) error {
+			if logLevels[level] < logLevels[minLevel] {
+				return nil
+			}
+			params := map[string]interface{}{
+				"level": level,
+				"data":  data,
+			}
+			if logger != "" {
+				params["logger"] = logger
+			}
+			return stream.send(map[string]interface{}{
+				"jsonrpc": "2.0",
+				"method":  "notifications/message",
+				"params":  params,
+			})
+		})
+	}
+
+	if progressToken != nil {
+		ctx = shared.WithProgress(ctx, func(ctx context.Context, progress, total float64, message string) error {
+			params := map[string]interface{}{
+				"progressToken": progressToken,
+				"progress":      progress,
+			}
+			if total > 0 {
+				params["total"] = total
+			}
+			if message != "" {
+				params["message"] = message
+			}
+			return stream.send(map[string]interface{}{
+				"jsonrpc": "2.0",
+				"method":  "notifications/progress",
+				"params":  params,
+			})
+		})
+	}
+	return ctx
+}
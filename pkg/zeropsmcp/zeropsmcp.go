@@ -0,0 +1,136 @@
+// Package zeropsmcp embeds the Zerops MCP tools in other Go programs, such as internal
+// portals and CLIs, which call tools directly instead of running the server over stdio.
+//
+//	server, err := zeropsmcp.New(zeropsmcp.Options{APIKey: os.Getenv("ZEROPS_API_KEY")})
+//	if err != nil {
+//		return err
+//	}
+//	result, err := server.CallTool(ctx, "discovery", map[string]interface{}{"project_id": projectID})
+//
+// Results are the same values the MCP transports serialize to JSON. Scheduled actions
+// (schedule_action) only run in the MCP server binary.
+package zeropsmcp
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers"
+	"github.com/fxck/zerops-mcp-go-sdk/internal/handlers/shared"
+	"github.com/zeropsio/zerops-go/sdk"
+	"github.com/zeropsio/zerops-go/sdkBase"
+)
+
+// DefaultEndpoint is the Zerops API used when Options.Endpoint is empty
+const DefaultEndpoint = "https://api.app-prg1.zerops.io"
+
+// Error kinds of CallTool; check them with errors.Is
+var (
+	ErrToolNotFound    = shared.ErrToolNotFound
+	ErrNotFound        = shared.ErrNotFound
+	ErrInvalidArgument = shared.ErrInvalidArgument
+	ErrAPIUnavailable  = shared.ErrAPIUnavailable
+	ErrForbidden       = shared.ErrForbidden
+	ErrConflict        = shared.ErrConflict
+	ErrBudgetExceeded  = shared.ErrBudgetExceeded
+)
+
+// Options configures an embedded server
+type Options struct {
+	// APIKey is the Zerops API key the tools act with
+	APIKey string
+	// Endpoint is the Zerops API endpoint; defaults to DefaultEndpoint
+	Endpoint string
+	// ClientName identifies the embedding program to tools, e.g. in state history
+	ClientName string
+	// Notify receives the log and progress messages tools send during a call; nil drops them
+	Notify func(level, logger string, data interface{})
+}
+
+// Tool describes a tool that can be called
+type Tool struct {
+	Name        string
+	Description string
+	// InputSchema is the JSON schema of the arguments
+	InputSchema map[string]interface{}
+	ReadOnly    bool
+	Destructive bool
+	Idempotent  bool
+}
+
+// Server calls tools with one API key. It is safe for concurrent use.
+type Server struct {
+	client  *sdk.Handler
+	options Options
+}
+
+var registerOnce sync.Once
+
+// New creates a server for the API key of options. The key is not checked until the first
+// tool call; call the whoami tool to validate it.
+func New(options Options) (*Server, error) {
+	if options.APIKey == "" {
+		return nil, errors.New("zeropsmcp: APIKey is required")
+	}
+	if options.Endpoint == "" {
+		options.Endpoint = DefaultEndpoint
+	}
+	registerOnce.Do(handlers.InitializeRegistry)
+
+	base := sdk.New(sdkBase.Config{Endpoint: options.Endpoint}, shared.BudgetedHTTPClient(shared.OwnerID(options.APIKey)))
+	client := sdk.AuthorizeSdk(base, options.APIKey)
+	return &Server{client: &client, options: options}, nil
+}
+
+// Tools returns all tools sorted by name
+func (s *Server) Tools() []Tool {
+	definitions := shared.GlobalRegistry.List()
+	tools := make([]Tool, 0, len(definitions))
+	for _, definition := range definitions {
+		tool := Tool{
+			Name:        definition.Name,
+			Description: definition.Description,
+			InputSchema: definition.InputSchema,
+		}
+		if definition.Annotations != nil {
+			tool.ReadOnly = definition.Annotations.ReadOnly
+			tool.Destructive = definition.Annotations.Destructive
+			tool.Idempotent = definition.Annotations.Idempotent
+		}
+		tools = append(tools, tool)
+	}
+	return tools
+}
+
+// CallTool calls the named tool with arguments as they would arrive in JSON: numbers are
+// float64 and lists are []interface{}
+func (s *Server) CallTool(ctx context.Context, name string, arguments map[string]interface{}) (interface{}, error) {
+	if arguments == nil {
+		arguments = map[string]interface{}{}
+	}
+	ctx = context.WithValue(ctx, "zeropsClient", s.client)
+	// Session preferences and state history are kept per API key, as in HTTP mode
+	ctx = context.WithValue(ctx, "apiKey", s.options.APIKey)
+	if s.options.ClientName != "" {
+		ctx = context.WithValue(ctx, "clientName", s.options.ClientName)
+	}
+	if notify := s.options.Notify; notify != nil {
+		forward := func(ctx context.Context, level, logger string, data interface{}) error {
+			notify(level, logger, data)
+			return nil
+		}
+		ctx = shared.WithNotifier(ctx, forward)
+		ctx = shared.WithLogger(ctx, forward)
+		ctx = shared.WithProgress(ctx, func(ctx context.Context, progress, total float64, message string) error {
+			notify("progress", "", map[string]interface{}{"progress": progress, "total": total, "message": message})
+			return nil
+		})
+	}
+	return shared.GlobalRegistry.CallTool(ctx, name, arguments)
+}
+
+// ErrorCode returns the machine-readable code of an error returned by CallTool, e.g. NOT_FOUND
+func ErrorCode(err error) string {
+	return shared.ErrorCode(err)
+}
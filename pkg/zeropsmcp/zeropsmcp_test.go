@@ -0,0 +1,130 @@
+package zeropsmcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/fxck/zerops-mcp-go-sdk/internal/mock"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name         string
+		options      Options
+		wantEndpoint string
+		wantErr      bool
+	}{
+		{name: "missing api key", wantErr: true},
+		{name: "default endpoint", options: Options{APIKey: "key"}, wantEndpoint: DefaultEndpoint},
+		{name: "custom endpoint", options: Options{APIKey: "key", Endpoint: "https://api.example.com"}, wantEndpoint: "https://api.example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, err := New(tt.options)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if server.options.Endpoint != tt.wantEndpoint {
+				t.Fatalf("endpoint = %q, want %q", server.options.Endpoint, tt.wantEndpoint)
+			}
+		})
+	}
+}
+
+func TestTools(t *testing.T) {
+	server, err := New(Options{APIKey: "key"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tools := server.Tools()
+	if len(tools) == 0 {
+		t.Fatal("no tools")
+	}
+	if !sort.SliceIsSorted(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name }) {
+		t.Fatal("tools are not sorted by name")
+	}
+
+	byName := make(map[string]Tool, len(tools))
+	for _, tool := range tools {
+		byName[tool.Name] = tool
+	}
+	tests := []struct {
+		name            string
+		wantReadOnly    bool
+		wantDestructive bool
+	}{
+		{name: "whoami", wantReadOnly: true},
+		{name: "discovery", wantReadOnly: true},
+		{name: "stop_service", wantDestructive: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool, ok := byName[tt.name]
+			if !ok {
+				t.Fatalf("tool %s is missing", tt.name)
+			}
+			if tool.Description == "" || tool.InputSchema == nil {
+				t.Fatalf("tool %s has no description or input schema", tt.name)
+			}
+			if tool.ReadOnly != tt.wantReadOnly || tool.Destructive != tt.wantDestructive {
+				t.Fatalf("read only = %v, destructive = %v, want %v, %v", tool.ReadOnly, tool.Destructive, tt.wantReadOnly, tt.wantDestructive)
+			}
+		})
+	}
+}
+
+func TestCallTool(t *testing.T) {
+	// Tools call the canned API of mock mode, which takes over http.DefaultTransport
+	previous := http.DefaultTransport
+	mock.Enable()
+	t.Cleanup(func() { http.DefaultTransport = previous })
+
+	server, err := New(Options{APIKey: mock.APIKey, ClientName: "zeropsmcp-test"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		tool      string
+		arguments map[string]interface{}
+		want      string
+		wantErr   error
+	}{
+		{name: "whoami", tool: "whoami", arguments: map[string]interface{}{}, want: "demo@example.com"},
+		{name: "nil arguments", tool: "whoami", arguments: nil, want: "Acme Demo"},
+		{name: "unknown tool", tool: "no_such_tool", wantErr: ErrToolNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := server.CallTool(context.Background(), tt.tool, tt.arguments)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			data, err := json.Marshal(result)
+			if err != nil {
+				t.Fatalf("result doesn't marshal: %v", err)
+			}
+			if !strings.Contains(string(data), tt.want) {
+				t.Fatalf("result %s doesn't contain %q", data, tt.want)
+			}
+		})
+	}
+}
@@ -12,19 +12,19 @@ mkdir -p releases
 
 # Build for all platforms
 echo "Building for Windows AMD64..."
-GOOS=windows GOARCH=amd64 go build -o releases/zerops-mcp-win-x64.exe -ldflags="-X main.serverVersion=${VERSION}" cmd/mcp-server/main.go
+GOOS=windows GOARCH=amd64 go build -o releases/zerops-mcp-win-x64.exe -ldflags="-X main.serverVersion=${VERSION}" ./cmd/mcp-server
 
 echo "Building for Linux AMD64..."
-GOOS=linux GOARCH=amd64 go build -o releases/zerops-mcp-linux-amd64 -ldflags="-X main.serverVersion=${VERSION}" cmd/mcp-server/main.go
+GOOS=linux GOARCH=amd64 go build -o releases/zerops-mcp-linux-amd64 -ldflags="-X main.serverVersion=${VERSION}" ./cmd/mcp-server
 
 echo "Building for Linux 386..."
-GOOS=linux GOARCH=386 go build -o releases/zerops-mcp-linux-i386 -ldflags="-X main.serverVersion=${VERSION}" cmd/mcp-server/main.go
+GOOS=linux GOARCH=386 go build -o releases/zerops-mcp-linux-i386 -ldflags="-X main.serverVersion=${VERSION}" ./cmd/mcp-server
 
 echo "Building for macOS Intel..."
-GOOS=darwin GOARCH=amd64 go build -o releases/zerops-mcp-darwin-amd64 -ldflags="-X main.serverVersion=${VERSION}" cmd/mcp-server/main.go
+GOOS=darwin GOARCH=amd64 go build -o releases/zerops-mcp-darwin-amd64 -ldflags="-X main.serverVersion=${VERSION}" ./cmd/mcp-server
 
 echo "Building for macOS Apple Silicon..."
-GOOS=darwin GOARCH=arm64 go build -o releases/zerops-mcp-darwin-arm64 -ldflags="-X main.serverVersion=${VERSION}" cmd/mcp-server/main.go
+GOOS=darwin GOARCH=arm64 go build -o releases/zerops-mcp-darwin-arm64 -ldflags="-X main.serverVersion=${VERSION}" ./cmd/mcp-server
 
 # Create release archives
 echo "Creating release archives..."
@@ -5,4 +5,4 @@ go build -tags devel \
          -o bin/$1 \
          -gcflags="all=-l -N" \
          -ldflags="all=\"-X=main.version=${VERSION}\"" \
-         cmd/mcp-server/main.go
\ No newline at end of file
+         ./cmd/mcp-server
\ No newline at end of file